@@ -20,11 +20,14 @@ import (
 	"os"
 
 	"github.com/akamai/cli-terraform/cli"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
 )
 
 func main() {
 	if err := cli.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "%s\n", err)
-		os.Exit(1)
+		if msg := err.Error(); msg != "" {
+			fmt.Fprintf(os.Stderr, "%s\n", msg)
+		}
+		os.Exit(exitcode.Of(err))
 	}
 }
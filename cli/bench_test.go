@@ -0,0 +1,340 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/hapi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/archive"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/fakedata"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
+	"github.com/akamai/cli-terraform/pkg/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+var perf = flag.Bool("perf", false, "compare allocations of benchmarks with their budgets, or overwrite the budgets with -update")
+
+const (
+	// budgetsFile holds allocations per export allowed for each benchmark
+	budgetsFile = "./testdata/bench/budgets.json"
+	// budgetTolerance is the percentage by which allocations may exceed their budget, as requests run in parallel
+	budgetTolerance = 10
+)
+
+// benchmarkFlags are global flags of benchmarked commands; rate limits of APIs are removed, so that the benchmarks
+// measure the exporters instead of waiting for the limits
+var benchmarkFlags = []string{
+	"--api-budget", "cloudlets=8", "--api-budget", "dns=8", "--api-budget", "papi=8",
+	"--page-size", "cloudlets=100", "--page-size", "dns=1000",
+}
+
+// benchmarks are checked against their budgets by TestPerformanceBudgets
+var benchmarks = map[string]func(*testing.B){
+	"BenchmarkExportZone":              BenchmarkExportZone,
+	"BenchmarkExportCloudletsPolicies": BenchmarkExportCloudletsPolicies,
+	"BenchmarkExportProperty":          BenchmarkExportProperty,
+}
+
+// budget is the number of allocations and allocated bytes allowed per export
+type budget struct {
+	AllocsPerOp int64 `json:"allocsPerOp"`
+	BytesPerOp  int64 `json:"bytesPerOp"`
+}
+
+// fixture collects responses of the fake API server
+type fixture []archive.Response
+
+// BenchmarkExportZone exports a zone with 10k record sets
+func BenchmarkExportZone(b *testing.B) {
+	zone, recordsets := fakedata.New(1).Zone("example.com", 10000)
+	benchmarkExport(b, zoneFixture(b, zone, recordsets, 1000), "export-zone", "--resources", "--createconfig", "--importscript", zone.Zone)
+}
+
+// BenchmarkExportCloudletsPolicies exports 1k policies of the account, each into its own directory
+func BenchmarkExportCloudletsPolicies(b *testing.B) {
+	g := fakedata.New(1)
+	benchmarkExport(b, policiesFixture(b, g, g.Policies(1000), 100), "export-cloudlets-policy", "--all")
+}
+
+// BenchmarkExportProperty exports a property with a rule tree of about 5MB
+func BenchmarkExportProperty(b *testing.B) {
+	g := fakedata.New(1)
+	property := g.Property("example")
+	benchmarkExport(b, propertyFixture(b, property, g.RuleTree(6, 6, 4)), "export-property", property.PropertyName)
+}
+
+// TestPerformanceBudgets runs the benchmarks and fails when any of them allocates more than its budget allows; the
+// budgets are overwritten with the results when run with -update
+func TestPerformanceBudgets(t *testing.T) {
+	if !*perf {
+		t.Skip("run with -perf to compare benchmarks with their budgets")
+	}
+	names := make([]string, 0, len(benchmarks))
+	for name := range benchmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make(map[string]budget, len(benchmarks))
+	for _, name := range names {
+		result := testing.Benchmark(benchmarks[name])
+		require.NotZero(t, result.N, "%s failed", name)
+		t.Logf("%s: %s %s", name, result, result.MemString())
+		results[name] = budget{AllocsPerOp: result.AllocsPerOp(), BytesPerOp: result.AllocedBytesPerOp()}
+	}
+
+	if testutils.Update() {
+		content, err := json.MarshalIndent(results, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(budgetsFile, append(content, '\n'), 0644))
+		return
+	}
+	content, err := os.ReadFile(budgetsFile)
+	require.NoError(t, err)
+	var budgets map[string]budget
+	require.NoError(t, json.Unmarshal(content, &budgets))
+	for _, name := range names {
+		allowed, ok := budgets[name]
+		if !ok {
+			t.Errorf("%s has no budget, run with -perf -update to add it", name)
+			continue
+		}
+		result := results[name]
+		if overBudget(result.AllocsPerOp, allowed.AllocsPerOp) {
+			t.Errorf("%s: %d allocs/op exceed the budget of %d by more than %d%%", name, result.AllocsPerOp, allowed.AllocsPerOp, budgetTolerance)
+		}
+		if overBudget(result.BytesPerOp, allowed.BytesPerOp) {
+			t.Errorf("%s: %d B/op exceed the budget of %d by more than %d%%", name, result.BytesPerOp, allowed.BytesPerOp, budgetTolerance)
+		}
+	}
+}
+
+func overBudget(value, allowed int64) bool {
+	return value*100 > allowed*(100+budgetTolerance)
+}
+
+// benchmarkExport runs the command b.N times against the fake API server seeded with the responses, each time into
+// an empty directory
+func benchmarkExport(b *testing.B, responses fixture, args ...string) {
+	path := filepath.Join(b.TempDir(), "api-responses.json")
+	require.NoError(b, fakedata.WriteFixture(path, responses))
+	srv := testutils.NewAPIServer(b, path)
+	b.Setenv(edgegrid.APIURLEnv, srv.URL)
+	root := b.TempDir()
+
+	// output of the commands would drown results of the benchmarks
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	require.NoError(b, err)
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = devNull, devNull
+	defer func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		_ = devNull.Close()
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		dir := filepath.Join(root, strconv.Itoa(i))
+		require.NoError(b, os.Mkdir(dir, 0755))
+		cmd := append([]string{"akamai-terraform", "--edgerc", "./testdata/.edgerc", "--section", "test_section"}, benchmarkFlags...)
+		cmd = append(cmd, args[0], "--tfworkpath", dir)
+		b.StartTimer()
+
+		if err := run(append(cmd, args[1:]...)); err != nil {
+			b.Fatalf("%s, requests without responses: %v", err, srv.Unmatched())
+		}
+
+		b.StopTimer()
+		require.NoError(b, os.RemoveAll(dir))
+		b.StartTimer()
+	}
+	b.StopTimer()
+	require.Empty(b, srv.Unmatched())
+}
+
+func (f *fixture) add(t testing.TB, method, path string, body interface{}) {
+	resp, err := fakedata.Response(method, "https://host"+path, http.StatusOK, body)
+	require.NoError(t, err)
+	*f = append(*f, resp)
+}
+
+// zoneFixture returns responses to inventory and export of the zone with the record sets, listed in pages
+func zoneFixture(t testing.TB, zone *dns.ZoneResponse, recordsets []dns.Recordset, pageSize int) fixture {
+	var f fixture
+	prefix := "/config-dns/v2/zones/" + zone.Zone
+	f.add(t, http.MethodGet, prefix, zone)
+
+	var names []string
+	types := make(map[string][]string)
+	for _, recordset := range recordsets {
+		if _, ok := types[recordset.Name]; !ok {
+			names = append(names, recordset.Name)
+		}
+		types[recordset.Name] = append(types[recordset.Name], recordset.Type)
+	}
+	f.add(t, http.MethodGet, prefix+"/names", dns.ZoneNamesResponse{Names: names})
+	for _, name := range names {
+		f.add(t, http.MethodGet, prefix+"/names/"+name+"/types", dns.ZoneNameTypesResponse{Types: types[name]})
+	}
+
+	lastPage := (len(recordsets) + pageSize - 1) / pageSize
+	for page := 1; page <= lastPage; page++ {
+		end := page * pageSize
+		if end > len(recordsets) {
+			end = len(recordsets)
+		}
+		f.add(t, http.MethodGet, fmt.Sprintf("%s/recordsets?page=%d&pageSize=%d&showAll=false&sortBy=name%%2C+type", prefix, page, pageSize), dns.RecordSetResponse{
+			Metadata:   dns.MetadataH{Page: page, PageSize: pageSize, LastPage: lastPage, TotalElements: len(recordsets)},
+			Recordsets: recordsets[(page-1)*pageSize : end],
+		})
+	}
+	return f
+}
+
+// policiesFixture returns responses to listing of the policies in pages and to exports of each of them with their
+// load balancers; the account has no shared policies
+func policiesFixture(t testing.TB, g *fakedata.Generator, policies []cloudlets.Policy, pageSize int) fixture {
+	var f fixture
+	for offset := 0; offset <= len(policies); offset += pageSize {
+		end := offset + pageSize
+		if end > len(policies) {
+			end = len(policies)
+		}
+		f.add(t, http.MethodGet, fmt.Sprintf("/cloudlets/api/v2/policies?includeDeleted=false&offset=%d&pageSize=%d", offset, pageSize), policies[offset:end])
+	}
+	f.add(t, http.MethodGet, fmt.Sprintf("/cloudlets/v3/policies?page=0&size=%d", pageSize), shared.ListPoliciesResponse{
+		Content: []shared.Policy{},
+		Page:    shared.Page{Size: pageSize},
+	})
+
+	for _, policy := range policies {
+		prefix := fmt.Sprintf("/cloudlets/api/v2/policies/%d", policy.PolicyID)
+		f.add(t, http.MethodGet, prefix, policy)
+		f.add(t, http.MethodGet, fmt.Sprintf("%s/versions?includeActivations=false&includeDeleted=false&includeRules=false&offset=0&pageSize=%d", prefix, pageSize), []cloudlets.PolicyVersion{
+			{PolicyID: policy.PolicyID, Version: 1},
+		})
+		f.add(t, http.MethodGet, prefix+"/versions/1?omitRules=false", g.PolicyVersion(policy, 1, 20))
+	}
+	// match rules of load balancers refer to origins named 'origin_<n>' with n below 10
+	for i := 0; i < 10; i++ {
+		originID := fmt.Sprintf("origin_%d", i)
+		f.add(t, http.MethodGet, "/cloudlets/api/v2/origins/"+originID+"/versions?includeModel=true", []cloudlets.LoadBalancerVersion{
+			{OriginID: originID, Version: 1, BalancingType: cloudlets.BalancingTypeWeighted},
+		})
+		f.add(t, http.MethodGet, "/cloudlets/api/v2/origins/"+originID+"/activations", []cloudlets.LoadBalancerActivation{})
+	}
+	return f
+}
+
+// propertyFixture returns responses to export of the property with the rules, the property has a single hostname
+func propertyFixture(t testing.TB, property *papi.Property, rules papi.Rules) fixture {
+	var f fixture
+	query := "?contractId=" + property.ContractID + "&groupId=" + property.GroupID
+	prefix := "/papi/v1/properties/" + property.PropertyID
+	versionPrefix := fmt.Sprintf("%s/versions/%d", prefix, property.LatestVersion)
+
+	f.add(t, http.MethodPost, "/papi/v1/search/find-by-value", papi.SearchResponse{Versions: papi.SearchItems{Items: []papi.SearchItem{{
+		AccountID:       property.AccountID,
+		AssetID:         property.AssetID,
+		ContractID:      property.ContractID,
+		GroupID:         property.GroupID,
+		PropertyID:      property.PropertyID,
+		PropertyName:    property.PropertyName,
+		PropertyVersion: property.LatestVersion,
+		StagingStatus:   "ACTIVE",
+	}}}})
+	f.add(t, http.MethodGet, prefix+query, papi.GetPropertyResponse{Properties: papi.PropertiesItems{Items: []*papi.Property{property}}})
+	f.add(t, http.MethodGet, "/papi/v1/groups", papi.GetGroupsResponse{
+		AccountID: property.AccountID,
+		Groups: papi.GroupItems{Items: []*papi.Group{
+			{GroupID: property.GroupID, GroupName: "example", ContractIDs: []string{property.ContractID}},
+		}},
+	})
+
+	versions := papi.GetPropertyVersionsResponse{
+		PropertyID:   property.PropertyID,
+		PropertyName: property.PropertyName,
+		AccountID:    property.AccountID,
+		ContractID:   property.ContractID,
+		GroupID:      property.GroupID,
+		AssetID:      property.AssetID,
+		Versions: papi.PropertyVersionItems{Items: []papi.PropertyVersionGetItem{{
+			Etag:             "etag",
+			ProductID:        property.ProductID,
+			ProductionStatus: papi.VersionStatusInactive,
+			PropertyVersion:  property.LatestVersion,
+			RuleFormat:       property.RuleFormat,
+			StagingStatus:    papi.VersionStatusActive,
+		}}},
+	}
+	f.add(t, http.MethodGet, prefix+"/versions"+query, versions)
+	f.add(t, http.MethodGet, prefix+"/versions/latest"+query, versions)
+	f.add(t, http.MethodGet, versionPrefix+"/rules"+query+"&validateRules=false", papi.GetRuleTreeResponse{
+		PropertyID:      property.PropertyID,
+		PropertyVersion: property.LatestVersion,
+		Etag:            "etag",
+		RuleFormat:      property.RuleFormat,
+		Rules:           rules,
+	})
+	f.add(t, http.MethodGet, "/papi/v1/products?contractId="+property.ContractID, papi.GetProductsResponse{
+		AccountID:  property.AccountID,
+		ContractID: property.ContractID,
+		Products:   papi.ProductsItems{Items: []papi.ProductItem{{ProductID: property.ProductID, ProductName: "Fresca"}}},
+	})
+
+	f.add(t, http.MethodGet, versionPrefix+"/hostnames"+query+"&includeCertStatus=false&validateHostnames=false", papi.GetPropertyVersionHostnamesResponse{
+		AccountID:       property.AccountID,
+		ContractID:      property.ContractID,
+		GroupID:         property.GroupID,
+		PropertyID:      property.PropertyID,
+		PropertyVersion: property.LatestVersion,
+		Etag:            "etag",
+		Hostnames: papi.HostnameResponseItems{Items: []papi.Hostname{{
+			CnameType:            papi.HostnameCnameTypeEdgeHostname,
+			EdgeHostnameID:       "ehn_1",
+			CnameFrom:            "www.example.com",
+			CnameTo:              "www.example.com.edgesuite.net",
+			CertProvisioningType: "CPS_MANAGED",
+		}}},
+	})
+	f.add(t, http.MethodGet, "/papi/v1/edgehostnames"+query, papi.GetEdgeHostnamesResponse{
+		AccountID:  property.AccountID,
+		ContractID: property.ContractID,
+		GroupID:    property.GroupID,
+		EdgeHostnames: papi.EdgeHostnameItems{Items: []papi.EdgeHostnameGetItem{{
+			ID:                "ehn_1",
+			Domain:            "www.example.com.edgesuite.net",
+			ProductID:         property.ProductID,
+			DomainPrefix:      "www.example.com",
+			DomainSuffix:      "edgesuite.net",
+			IPVersionBehavior: "IPV4",
+		}}},
+	})
+	f.add(t, http.MethodGet, "/hapi/v1/edge-hostnames/1", hapi.GetEdgeHostnameResponse{
+		EdgeHostnameID:    1,
+		RecordName:        "www.example.com",
+		DNSZone:           "edgesuite.net",
+		SecurityType:      "STANDARD-TLS",
+		UseDefaultTTL:     true,
+		IPVersionBehavior: "IPV4",
+		ProductID:         "Fresca",
+		TTL:               21600,
+	})
+	f.add(t, http.MethodGet, prefix+"/activations"+query, papi.GetActivationsResponse{
+		Activations: papi.ActivationsItems{Items: []*papi.Activation{}},
+	})
+	return f
+}
@@ -17,13 +17,31 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
+	"github.com/akamai/cli-terraform/pkg/apistats"
+	"github.com/akamai/cli-terraform/pkg/archive"
 	"github.com/akamai/cli-terraform/pkg/commands"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/secrets"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/throttle"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/wizard"
+	"github.com/akamai/cli-terraform/pkg/workspace"
 	akacli "github.com/akamai/cli/pkg/app"
 	"github.com/akamai/cli/pkg/log"
 	"github.com/akamai/cli/pkg/terminal"
@@ -38,8 +56,20 @@ var (
 
 // Run initializes the cli and runs it
 func Run() error {
+	return run(os.Args)
+}
+
+// run initializes the cli and runs it with given command line arguments
+func run(args []string) error {
 	term := terminal.Color()
-	ctx := context.Background()
+	// interrupting the command cancels its context, so that it stops at the next API call and its partial output is removed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		// the second interrupt terminates the process immediately
+		stop()
+	}()
 	ctx = terminal.Context(ctx, term)
 
 	app := akacli.CreateAppTemplate(ctx, "terraform",
@@ -55,8 +85,195 @@ func Run() error {
 		app.Commands = append(cmds, app.Commands...)
 	}
 
-	app.Before = ensureBefore(putSessionInContext, putLoggerInContext, deprecationInfoForCreateCommands)
-	return app.RunContext(ctx, os.Args)
+	app.Flags = append(app.Flags, &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Maximum time the command is allowed to run, e.g. 30s or 10m (default: no limit)",
+	}, &cli.IntFlag{
+		Name:        "concurrency",
+		Usage:       "Maximum number of API requests and rendered templates run in parallel",
+		Value:       tools.Concurrency,
+		Destination: &tools.Concurrency,
+	}, &cli.StringSliceFlag{
+		Name:  "page-size",
+		Usage: "Size of pages of paginated list calls, either for all APIs or as 'api=size', e.g. dns=500, overriding it for one of: cloudlets, dns, can be repeated",
+	}, &cli.IntFlag{
+		Name:        "retries",
+		Usage:       "Number of times a paginated list call failed with 429 or 5xx status code is retried before the command fails",
+		Value:       tools.Retries,
+		Destination: &tools.Retries,
+	}, &cli.DurationFlag{
+		Name:        "retry-backoff",
+		Usage:       "Delay before the first retry of a failed list call, doubled with every next retry",
+		Value:       tools.RetryBackoff,
+		Destination: &tools.RetryBackoff,
+	}, &cli.StringSliceFlag{
+		Name:  "api-budget",
+		Usage: "Maximum number of concurrent requests and requests per second of a single API as 'api=concurrency[:rate]', e.g. papi=8:20, overriding the default budget of one of: " + strings.Join(throttle.APIs(), ", ") + ", can be repeated",
+	}, &cli.BoolFlag{
+		Name:        "json",
+		Usage:       "Print summary of the command run in JSON format as the last line of the output",
+		Destination: &tools.JSON,
+	}, &cli.BoolFlag{
+		Name:        "api-stats",
+		Usage:       "Report counts, durations and retries of API calls per endpoint when the command finishes, included in the summary with --json",
+		Destination: &tools.APIStats,
+	}, &cli.BoolFlag{
+		Name:        "stats",
+		Usage:       "Report numbers of exported objects, API calls, retries, written files and bytes, warnings and duration of the run when the command finishes, included in the summary with --json",
+		Destination: &tools.Stats,
+	}, &cli.StringFlag{
+		Name:        "check-provider-compat",
+		Usage:       "Version of Akamai Terraform provider, e.g. 2.0.0, against which generated configuration is checked for unavailable resources and attributes",
+		Destination: &tools.ProviderVersion,
+	}, &cli.BoolFlag{
+		Name:        "validate-import-ids",
+		Usage:       "Check IDs of imported resources against import ID formats documented for the provider version given with --check-provider-compat, or the latest one, before import scripts are written",
+		Destination: &tools.ValidateImportIDs,
+	}, &cli.BoolFlag{
+		Name:        "validate-schema",
+		Usage:       "Check resources and data sources of generated configuration for arguments unknown to the provider and missing required arguments before files are written",
+		Destination: &tools.ValidateSchema,
+	}, &cli.BoolFlag{
+		Name:        "terragrunt",
+		Usage:       "Generate terragrunt.hcl with remote state and inputs wiring next to exported configuration",
+		Destination: &tools.Terragrunt,
+	}, &cli.BoolFlag{
+		Name:        "readme",
+		Usage:       "Generate README.md describing exported resources, required variables, import procedure and warnings next to exported configuration",
+		Destination: &tools.Readme,
+	}, &cli.StringFlag{
+		Name:        "readme-template",
+		Usage:       "Path of Go template used to generate README.md instead of the default one, implies --readme",
+		Destination: &tools.ReadmeTemplate,
+	}, &cli.StringFlag{
+		Name:        "archive",
+		Usage:       "Path of gzip compressed tarball, e.g. out.tar.gz, into which exported configuration is packed",
+		Destination: &tools.Archive,
+	}, &cli.StringFlag{
+		Name:        "archive-manifest",
+		Usage:       "Path of manifest file added to the archive",
+		Destination: &tools.ArchiveManifest,
+	}, &cli.BoolFlag{
+		Name:        "archive-api-responses",
+		Usage:       "Record API responses received during the export and add them to the archive",
+		Destination: &tools.ArchiveAPIResponses,
+	}, &cli.BoolFlag{
+		Name:        "origins-inventory",
+		Usage:       "Write origins.tf listing origin hostnames and IDs referenced by exported properties and cloudlets policies, with objects using each of them",
+		Destination: &tools.OriginsInventory,
+	}, &cli.BoolFlag{
+		Name:        "checksums",
+		Usage:       "Write SHA256SUMS with checksums of all files in the work path after the export",
+		Destination: &tools.Checksums,
+	}, &cli.StringFlag{
+		Name:        "sign-key",
+		Usage:       "Path of unencrypted minisign secret key with which SHA256SUMS is signed into SHA256SUMS.minisig, implies --checksums",
+		Destination: &tools.SignKey,
+	}, &cli.StringFlag{
+		Name:        "scan-secrets",
+		Usage:       "Scan generated content for secrets before it is written and either 'fail' the export or 'redact' them into sensitive variables",
+		Destination: &tools.ScanSecrets,
+	}, &cli.BoolFlag{
+		Name:        "merge",
+		Usage:       "Merge re-exported configuration with local edits of previously exported files instead of overwriting them",
+		Destination: &tools.Merge,
+	}, &cli.StringSliceFlag{
+		Name:  "only",
+		Usage: "Address of a block, e.g. akamai_dns_record.www, or path of a file relative to the work path, to which changes of the re-export are limited, leaving the rest of the work path untouched, can be repeated",
+	}, &cli.BoolFlag{
+		Name:        "status",
+		Usage:       "Instead of exporting, show which files exported into the work path are stale or modified locally",
+		Destination: &tools.Status,
+	}, &cli.BoolFlag{
+		Name:        "strict",
+		Usage:       "Fail the export if any warning about skipped, unsupported or guessed parts of exported objects is reported",
+		Destination: &tools.Strict,
+	}, &cli.StringSliceFlag{
+		Name:  "add-comment",
+		Usage: "Comment in 'key=value' format, e.g. owner=team-a, added to every generated resource block, can be repeated",
+	}, &cli.StringFlag{
+		Name:        "output-sink",
+		Usage:       "Where generated files are written, either 'dir' for the work path, 'stdout' or 'zip:<file>'",
+		Value:       "dir",
+		Destination: &tools.OutputSink,
+	}, &cli.StringFlag{
+		Name:        "line-endings",
+		Usage:       "Line endings of generated files, either 'lf' or 'crlf'",
+		Value:       tools.LineEndingsLF,
+		Destination: &tools.LineEndings,
+	}, &cli.StringFlag{
+		Name:        "var-naming",
+		Usage:       "Naming convention of generated variables, either 'snake', 'camel' or 'prefix=<prefix>', e.g. prefix=akamai_",
+		Destination: &tools.VarNaming,
+	}, &cli.StringFlag{
+		Name:        "output-template",
+		Usage:       "Pattern of paths of generated files relative to the work path, e.g. '{{.Product}}/{{.Name}}/{{.File}}'",
+		Destination: &tools.OutputTemplate,
+	}, &cli.BoolFlag{
+		Name:  "interactive",
+		Usage: "Guide through the export by prompting for product, object, target directory and options",
+	})
+	app.Action = func(c *cli.Context) error {
+		if c.Bool("interactive") {
+			return wizard.CmdInteractive(c)
+		}
+		return cli.ShowAppHelp(c)
+	}
+	bindEnvVars(app)
+
+	limit := &timeout{}
+	defer limit.release()
+	summary := &runSummary{}
+	collector := warnings.NewCollector()
+	stats := apistats.NewRecorder()
+	failures := apierrors.NewRecorder()
+	journal := templates.NewJournal()
+	sources := &statsSources{objects: workspace.NewRecorder(), calls: stats, files: templates.NewWriteStats(), warnings: collector, start: time.Now()}
+	var reporter progress.Reporter
+	app.Before = ensureBefore(requireValidSecretsMode, requireValidLineEndings, requireValidVarNaming, requireValidComments, requireValidPageSizes, putSchedulerInContext, storeSelection, putAPIStatsInContext(stats), putStatsInContext(sources), putAPIErrorsInContext(failures), putSessionInContext, putLoggerInContext, deprecationInfoForCreateCommands, putTimeoutInContext(limit),
+		putWarningsCollectorInContext(collector), putProgressReporterInContext(&reporter), putJournalInContext(journal), recordCommand(summary))
+	app.After = func(c *cli.Context) error {
+		if err := printWarningsSummary(c); err != nil {
+			return err
+		}
+		return printAPIStats(c)
+	}
+	// errors are returned to the caller instead of exiting the process, so that the summary can always be printed
+	app.ExitErrHandler = func(*cli.Context, error) {}
+
+	err = app.RunContext(ctx, args)
+	if ctx.Err() != nil {
+		err = interrupted(reporter, journal)
+	} else if err != nil && limit.expired() {
+		err = timedOut(err)
+	} else {
+		err = failures.Enrich(err)
+	}
+	var metrics *runStats
+	if tools.Stats {
+		collected := sources.collect(time.Now())
+		metrics = &collected
+	}
+	if tools.JSON {
+		summary.complete(err, collector)
+		if tools.APIStats {
+			report := stats.Report()
+			summary.APICalls = &report
+		}
+		summary.Stats = metrics
+		if printErr := summary.print(term); printErr != nil {
+			return printErr
+		}
+	} else if metrics != nil {
+		var w io.Writer = term
+		if tools.OutputSink == templates.SinkStdout {
+			w = os.Stderr
+		}
+		if printErr := metrics.write(w); printErr != nil {
+			return printErr
+		}
+	}
+	return err
 }
 
 func ensureBefore(bfs ...cli.BeforeFunc) cli.BeforeFunc {
@@ -73,8 +290,11 @@ func ensureBefore(bfs ...cli.BeforeFunc) cli.BeforeFunc {
 
 func sessionRequired(c *cli.Context) bool {
 	command := c.Args().First()
+	if command == "" && c.Bool("interactive") {
+		return true
+	}
 
-	for _, cmd := range []string{"help", "list", ""} {
+	for _, cmd := range []string{"help", "list", "completion", "resolve-references", "validate-property-rules", "lint", "upgrade-workspace", ""} {
 		if cmd == command {
 			return false
 		}
@@ -85,6 +305,11 @@ func sessionRequired(c *cli.Context) bool {
 		return false
 	}
 
+	// validate-rules calls the API only to find the cloudlet type of the given policy
+	if command == "validate-rules" && !hasFlag(tail, "policy") {
+		return false
+	}
+
 	for _, cmd := range c.App.Commands {
 		if cmd.Name == command || sliceContains(cmd.Aliases, command) {
 			return true
@@ -94,6 +319,17 @@ func sessionRequired(c *cli.Context) bool {
 	return false
 }
 
+// hasFlag returns true if the flag is among the arguments, either followed by its value or in '--flag=value' form
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		flag := strings.TrimLeft(arg, "-")
+		if arg != flag && (flag == name || strings.HasPrefix(flag, name+"=")) {
+			return true
+		}
+	}
+	return false
+}
+
 func sliceContains(slc []string, c string) bool {
 	for _, s := range slc {
 		if s == c {
@@ -107,15 +343,103 @@ func putSessionInContext(c *cli.Context) error {
 	if !sessionRequired(c) {
 		return nil
 	}
-	s, err := edgegrid.InitializeSession(c)
+	var opts []session.Option
+	transport := http.DefaultTransport
+	if apiURL := os.Getenv(edgegrid.APIURLEnv); apiURL != "" {
+		redirect, err := edgegrid.RedirectTransport(apiURL, transport)
+		if err != nil {
+			return cli.Exit(err.Error(), exitcode.General)
+		}
+		transport = redirect
+	}
+	if stats := apistats.GetRecorder(c.Context); stats != nil {
+		transport = stats.RoundTripper(transport)
+	}
+	if failures := apierrors.GetRecorder(c.Context); failures != nil {
+		transport = failures.RoundTripper(transport)
+	}
+	if tools.Archive != "" && tools.ArchiveAPIResponses {
+		recorder := archive.NewRecorder()
+		transport = recorder.RoundTripper(transport)
+		c.Context = archive.WithRecorder(c.Context, recorder)
+	}
+	// requests wait for their budget before they are recorded, so that waiting does not count into durations of calls
+	if scheduler := throttle.GetScheduler(c.Context); scheduler != nil {
+		transport = scheduler.RoundTripper(transport)
+	}
+	if transport != http.DefaultTransport {
+		opts = append(opts, session.WithClient(&http.Client{Transport: transport}))
+	}
+	switch c.Args().First() {
+	case "sections", "doctor":
+		// sections creates a session for each section of the edgerc file, doctor creates the session once it reported
+		// invalid credentials itself
+		c.Context = edgegrid.WithSessionOptions(c.Context, opts)
+		return nil
+	}
+	if err := edgegrid.ValidateSection(c); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	s, err := edgegrid.InitializeSession(c, opts...)
 	if err != nil {
-		return err
+		return cli.Exit(err.Error(), exitcode.Auth)
 	}
 	c.Context = edgegrid.WithSession(c.Context, s)
 
 	return nil
 }
 
+func requireValidSecretsMode(*cli.Context) error {
+	if err := secrets.ValidateMode(tools.ScanSecrets); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	return nil
+}
+
+// requireValidLineEndings checks value of line-endings flag
+func requireValidLineEndings(*cli.Context) error {
+	if err := tools.ValidateLineEndings(tools.LineEndings); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Invalid value of line-endings flag: %s", err)), exitcode.General)
+	}
+	return nil
+}
+
+// requireValidVarNaming checks value of var-naming flag
+func requireValidVarNaming(*cli.Context) error {
+	if err := tools.ValidateVarNaming(tools.VarNaming); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Invalid value of var-naming flag: %s", err)), exitcode.General)
+	}
+	return nil
+}
+
+// requireValidComments checks that values of add-comment flag are 'key=value' pairs and stores them for templates
+func requireValidComments(c *cli.Context) error {
+	comments := c.StringSlice("add-comment")
+	for _, comment := range comments {
+		if _, _, err := tools.ParseComment(comment); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Invalid value of add-comment flag: %s", err)), exitcode.General)
+		}
+	}
+	tools.Comments = comments
+	return nil
+}
+
+// storeSelection stores blocks and files given with only flag for export commands
+func storeSelection(c *cli.Context) error {
+	tools.Only = tools.SplitList(c.StringSlice("only"))
+	return nil
+}
+
+// requireValidPageSizes checks values of page-size flag against bounds of APIs and stores them for providers
+func requireValidPageSizes(c *cli.Context) error {
+	sizes, err := tools.ParsePageSizes(c.StringSlice("page-size"))
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Invalid value of page-size flag: %s", err)), exitcode.General)
+	}
+	tools.PageSizes = sizes
+	return nil
+}
+
 func putLoggerInContext(c *cli.Context) error {
 	c.Context = log.SetupContext(c.Context, c.App.Writer)
 	c.Context = session.ContextWithOptions(c.Context, session.WithContextLog(log.FromContext(c.Context)))
@@ -123,6 +447,134 @@ func putLoggerInContext(c *cli.Context) error {
 	return nil
 }
 
+// timeout is the command context bounded with the value of timeout flag
+type timeout struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// release releases resources of the context once the command exits
+func (t *timeout) release() {
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
+// expired reports whether the command ran out of the time given with timeout flag
+func (t *timeout) expired() bool {
+	return t.ctx != nil && errors.Is(t.ctx.Err(), context.DeadlineExceeded)
+}
+
+// putTimeoutInContext bounds the command context with the value of timeout flag;
+// the created context is stored in the given timeout, so that it can be released once the command exits
+func putTimeoutInContext(limit *timeout) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		d := c.Duration("timeout")
+		if d <= 0 {
+			return nil
+		}
+		c.Context, limit.cancel = context.WithTimeout(c.Context, d)
+		limit.ctx = c.Context
+		return nil
+	}
+}
+
+// timedOut returns the error of the command which ran out of the time given with timeout flag with the timeout exit
+// code, as the command may fail with an error of any category once its context is done
+func timedOut(err error) error {
+	return cli.Exit(err.Error(), exitcode.Timeout)
+}
+
+func putWarningsCollectorInContext(collector *warnings.Collector) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		c.Context = warnings.WithCollector(c.Context, collector)
+		return nil
+	}
+}
+
+// putProgressReporterInContext puts reporter suitable for the terminal in context, the reporter is also stored in the given
+// pointer, so that its running operation can be cancelled once the command is interrupted
+func putProgressReporterInContext(reporter *progress.Reporter) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		*reporter = progress.New(terminal.Get(c.Context), tools.JSON)
+		c.Context = progress.WithReporter(c.Context, *reporter)
+		return nil
+	}
+}
+
+// putJournalInContext makes files written into the filesystem recorded in the journal
+func putJournalInContext(journal *templates.Journal) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		c.Context = templates.WithJournal(c.Context, journal)
+		return nil
+	}
+}
+
+// interrupted stops progress of the interrupted command and removes files it created, so that no half-generated
+// configuration is left in the work path; files which existed before are kept
+func interrupted(reporter progress.Reporter, journal *templates.Journal) error {
+	if reporter != nil {
+		reporter.Cancel()
+	}
+	removed, err := journal.Rollback()
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Interrupted, removing partially written files failed: %s", err)), exitcode.Interrupted)
+	}
+	if len(removed) == 0 {
+		return cli.Exit(color.RedString("Interrupted"), exitcode.Interrupted)
+	}
+	return cli.Exit(color.RedString(fmt.Sprintf("Interrupted, removed %d partially written file(s)", len(removed))), exitcode.Interrupted)
+}
+
+func printWarningsSummary(c *cli.Context) error {
+	warnings.PrintSummary(c.Context)
+	return nil
+}
+
+// putSchedulerInContext checks values of api-budget flag and puts the scheduler keeping API requests within the
+// budgets in context
+func putSchedulerInContext(c *cli.Context) error {
+	budgets, err := throttle.ParseBudgets(c.StringSlice("api-budget"))
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Invalid value of api-budget flag: %s", err)), exitcode.General)
+	}
+	c.Context = throttle.WithScheduler(c.Context, throttle.New(budgets))
+	return nil
+}
+
+// putAPIStatsInContext makes the session record API calls into the recorder if api-stats or stats flag is set
+func putAPIStatsInContext(stats *apistats.Recorder) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		if tools.APIStats || tools.Stats {
+			c.Context = apistats.WithRecorder(c.Context, stats)
+		}
+		return nil
+	}
+}
+
+// putAPIErrorsInContext makes the session record failed API calls into the recorder, so that errors of failed commands
+// can be enriched with their details
+func putAPIErrorsInContext(failures *apierrors.Recorder) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		c.Context = apierrors.WithRecorder(c.Context, failures)
+		return nil
+	}
+}
+
+// printAPIStats writes report of recorded API calls, unless it is included in the JSON summary instead; the report is
+// written to stderr when generated files are streamed to stdout
+func printAPIStats(c *cli.Context) error {
+	stats := apistats.GetRecorder(c.Context)
+	if stats == nil || !tools.APIStats || tools.JSON {
+		return nil
+	}
+	var w io.Writer = terminal.Get(c.Context)
+	if tools.OutputSink == templates.SinkStdout {
+		w = os.Stderr
+	}
+	return apistats.WriteReport(w, stats.Report(), tools.Concurrency)
+}
+
 func deprecationInfoForCreateCommands(c *cli.Context) error {
 	if !c.Args().Present() {
 		return nil
@@ -4,12 +4,27 @@ import (
 	"bytes"
 	"context"
 	"flag"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+	"github.com/akamai/cli-terraform/pkg/apistats"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/throttle"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
 	"github.com/akamai/cli/pkg/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
 )
 
@@ -72,6 +87,14 @@ func Test_sessionRequired(t *testing.T) {
 			},
 			expected: false,
 		},
+		"completion": {
+			c: func() *cli.Context {
+				app := newTemplateApp()
+				app.Commands = append(app.Commands, &cli.Command{Name: "completion"})
+				return newContextFromStringSlice([]string{"completion", "bash"}, app)
+			},
+			expected: false,
+		},
 		"unknown command": {
 			c: func() *cli.Context {
 				return newContextFromStringSlice([]string{"unknown"}, newTemplateApp())
@@ -90,6 +113,55 @@ func Test_sessionRequired(t *testing.T) {
 			},
 			expected: true,
 		},
+		"validate rules without policy": {
+			c: func() *cli.Context {
+				app := newTemplateApp()
+				app.Commands = append(app.Commands, &cli.Command{Name: "validate-rules"})
+				return newContextFromStringSlice([]string{"validate-rules", "--cloudlet-type", "ER", "rules.json"}, app)
+			},
+			expected: false,
+		},
+		"validate rules against policy": {
+			c: func() *cli.Context {
+				app := newTemplateApp()
+				app.Commands = append(app.Commands, &cli.Command{Name: "validate-rules"})
+				return newContextFromStringSlice([]string{"validate-rules", "--policy=redirects", "rules.json"}, app)
+			},
+			expected: true,
+		},
+		"validate property rules": {
+			c: func() *cli.Context {
+				app := newTemplateApp()
+				app.Commands = append(app.Commands, &cli.Command{Name: "validate-property-rules"})
+				return newContextFromStringSlice([]string{"validate-property-rules", "rules.json"}, app)
+			},
+			expected: false,
+		},
+		"lint": {
+			c: func() *cli.Context {
+				app := newTemplateApp()
+				app.Commands = append(app.Commands, &cli.Command{Name: "lint"})
+				return newContextFromStringSlice([]string{"lint", "./export"}, app)
+			},
+			expected: false,
+		},
+		"upgrade workspace": {
+			c: func() *cli.Context {
+				app := newTemplateApp()
+				app.Commands = append(app.Commands, &cli.Command{Name: "upgrade-workspace"})
+				return newContextFromStringSlice([]string{"upgrade-workspace", "./export"}, app)
+			},
+			expected: false,
+		},
+		"interactive mode": {
+			c: func() *cli.Context {
+				set := flag.NewFlagSet("test", 0)
+				set.Bool("interactive", false, "")
+				_ = set.Parse([]string{"--interactive"})
+				return cli.NewContext(newTemplateApp(), set, nil)
+			},
+			expected: true,
+		},
 	}
 
 	for name, test := range tests {
@@ -99,6 +171,40 @@ func Test_sessionRequired(t *testing.T) {
 	}
 }
 
+func TestPutSessionInContextWithoutCredentials(t *testing.T) {
+	tests := map[string]struct {
+		command   string
+		withError bool
+	}{
+		"upgrade workspace": {
+			command: "upgrade-workspace",
+		},
+		"some command which requires auth": {
+			command:   "some-command",
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := newTemplateApp()
+			app.Commands = append(app.Commands, &cli.Command{Name: "upgrade-workspace"})
+			set := flag.NewFlagSet("test", 0)
+			set.String("edgerc", "", "")
+			require.NoError(t, set.Parse([]string{"--edgerc", filepath.Join(t.TempDir(), ".edgerc"), test.command, "./export"}))
+			c := cli.NewContext(app, set, nil)
+			c.Context = context.Background()
+
+			err := putSessionInContext(c)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestPutLoggerInContext(t *testing.T) {
 	t.Setenv("AKAMAI_LOG", "debug")
 	app := cli.NewApp()
@@ -172,3 +278,456 @@ func TestDeprecationInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestPutTimeoutInContext(t *testing.T) {
+	tests := map[string]struct {
+		args         []string
+		withDeadline bool
+		expired      bool
+	}{
+		"no timeout": {
+			args:         []string{"cmd", "some-command"},
+			withDeadline: false,
+		},
+		"timeout set": {
+			args:         []string{"cmd", "--timeout", "1m", "some-command"},
+			withDeadline: true,
+		},
+		"timeout expired": {
+			args:         []string{"cmd", "--timeout", "1ms", "some-command"},
+			withDeadline: true,
+			expired:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var hasDeadline bool
+			limit := &timeout{}
+			defer limit.release()
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.ExitErrHandler = func(*cli.Context, error) {}
+			app.Flags = []cli.Flag{&cli.DurationFlag{Name: "timeout"}}
+			app.Commands = []*cli.Command{{
+				Name: "some-command",
+				Action: func(c *cli.Context) error {
+					deadline, ok := c.Context.Deadline()
+					hasDeadline = ok
+					if test.expired {
+						<-c.Context.Done()
+						// commands report failed calls within their own categories
+						return cli.Exit(fmt.Sprintf("Error fetching policies: %s", c.Context.Err()), exitcode.API)
+					}
+					if ok {
+						assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+					}
+					return nil
+				},
+			}}
+			app.Before = ensureBefore(putTimeoutInContext(limit))
+
+			err := app.Run(test.args)
+			assert.Equal(t, test.withDeadline, hasDeadline)
+			assert.Equal(t, test.expired, limit.expired())
+			if !test.expired {
+				assert.NoError(t, err)
+				return
+			}
+			err = timedOut(err)
+			assert.Equal(t, exitcode.Timeout, exitcode.Of(err))
+			assert.Equal(t, "Error fetching policies: context deadline exceeded", err.Error())
+		})
+	}
+}
+
+func TestRequireValidComments(t *testing.T) {
+	tests := map[string]struct {
+		args      []string
+		expected  []string
+		withError bool
+	}{
+		"no comments": {
+			args: []string{"cmd", "some-command"},
+		},
+		"comments set": {
+			args:     []string{"cmd", "--add-comment", "owner=team-a", "--add-comment", "ticket=ABC-123", "some-command"},
+			expected: []string{"owner=team-a", "ticket=ABC-123"},
+		},
+		"invalid comment": {
+			args:      []string{"cmd", "--add-comment", "owner", "some-command"},
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() { tools.Comments = nil }()
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.ExitErrHandler = func(*cli.Context, error) {}
+			app.Flags = []cli.Flag{&cli.StringSliceFlag{Name: "add-comment"}}
+			app.Commands = []*cli.Command{{Name: "some-command", Action: func(*cli.Context) error { return nil }}}
+			app.Before = ensureBefore(requireValidComments)
+
+			err := app.Run(test.args)
+			if test.withError {
+				assert.Error(t, err)
+				assert.Equal(t, exitcode.General, exitcode.Of(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, tools.Comments)
+		})
+	}
+}
+
+func TestRequireValidLineEndings(t *testing.T) {
+	tests := map[string]struct {
+		args      []string
+		expected  string
+		withError bool
+	}{
+		"default line endings": {
+			args:     []string{"cmd", "some-command"},
+			expected: tools.LineEndingsLF,
+		},
+		"crlf line endings": {
+			args:     []string{"cmd", "--line-endings", "crlf", "some-command"},
+			expected: tools.LineEndingsCRLF,
+		},
+		"unsupported line endings": {
+			args:      []string{"cmd", "--line-endings", "cr", "some-command"},
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() { tools.LineEndings = "" }()
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.ExitErrHandler = func(*cli.Context, error) {}
+			app.Flags = []cli.Flag{&cli.StringFlag{Name: "line-endings", Value: tools.LineEndingsLF, Destination: &tools.LineEndings}}
+			app.Commands = []*cli.Command{{Name: "some-command", Action: func(*cli.Context) error { return nil }}}
+			app.Before = ensureBefore(requireValidLineEndings)
+
+			err := app.Run(test.args)
+			if test.withError {
+				assert.Error(t, err)
+				assert.Equal(t, exitcode.General, exitcode.Of(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, tools.LineEndings)
+		})
+	}
+}
+
+func TestRequireValidVarNaming(t *testing.T) {
+	tests := map[string]struct {
+		args      []string
+		expected  string
+		withError bool
+	}{
+		"no naming": {
+			args: []string{"cmd", "some-command"},
+		},
+		"prefix naming": {
+			args:     []string{"cmd", "--var-naming", "prefix=akamai_", "some-command"},
+			expected: "prefix=akamai_",
+		},
+		"unsupported naming": {
+			args:      []string{"cmd", "--var-naming", "kebab", "some-command"},
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() { tools.VarNaming = "" }()
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.ExitErrHandler = func(*cli.Context, error) {}
+			app.Flags = []cli.Flag{&cli.StringFlag{Name: "var-naming", Destination: &tools.VarNaming}}
+			app.Commands = []*cli.Command{{Name: "some-command", Action: func(*cli.Context) error { return nil }}}
+			app.Before = ensureBefore(requireValidVarNaming)
+
+			err := app.Run(test.args)
+			if test.withError {
+				assert.Error(t, err)
+				assert.Equal(t, exitcode.General, exitcode.Of(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, tools.VarNaming)
+		})
+	}
+}
+
+func TestRequireValidPageSizes(t *testing.T) {
+	tests := map[string]struct {
+		args      []string
+		expected  map[string]int
+		withError bool
+	}{
+		"no page sizes": {
+			args:     []string{"cmd", "some-command"},
+			expected: map[string]int{},
+		},
+		"page sizes set": {
+			args:     []string{"cmd", "--page-size", "500", "--page-size", "dns=100", "some-command"},
+			expected: map[string]int{"": 500, tools.PageSizeDNS: 100},
+		},
+		"page size out of bounds": {
+			args:      []string{"cmd", "--page-size", "cloudlets=2000", "some-command"},
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() { tools.PageSizes = nil }()
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.ExitErrHandler = func(*cli.Context, error) {}
+			app.Flags = []cli.Flag{&cli.StringSliceFlag{Name: "page-size"}}
+			app.Commands = []*cli.Command{{Name: "some-command", Action: func(*cli.Context) error { return nil }}}
+			app.Before = ensureBefore(requireValidPageSizes)
+
+			err := app.Run(test.args)
+			if test.withError {
+				assert.Error(t, err)
+				assert.Equal(t, exitcode.General, exitcode.Of(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, tools.PageSizes)
+		})
+	}
+}
+
+func TestPutSchedulerInContext(t *testing.T) {
+	tests := map[string]struct {
+		args      []string
+		withError bool
+	}{
+		"default budgets": {
+			args: []string{"cmd", "some-command"},
+		},
+		"budget set": {
+			args: []string{"cmd", "--api-budget", "papi=8:20", "--api-budget", "dns=16", "some-command"},
+		},
+		"budget of unknown API": {
+			args:      []string{"cmd", "--api-budget", "hapi=2", "some-command"},
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var scheduler *throttle.Scheduler
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.ExitErrHandler = func(*cli.Context, error) {}
+			app.Flags = []cli.Flag{&cli.StringSliceFlag{Name: "api-budget"}}
+			app.Commands = []*cli.Command{{
+				Name: "some-command",
+				Action: func(c *cli.Context) error {
+					scheduler = throttle.GetScheduler(c.Context)
+					return nil
+				},
+			}}
+			app.Before = ensureBefore(putSchedulerInContext)
+
+			err := app.Run(test.args)
+			if test.withError {
+				assert.Error(t, err)
+				assert.Equal(t, exitcode.General, exitcode.Of(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, scheduler)
+		})
+	}
+}
+
+func TestPutAPIStatsInContext(t *testing.T) {
+	tests := map[string]struct {
+		args         []string
+		withRecorder bool
+	}{
+		"api stats not requested": {
+			args: []string{"cmd", "some-command"},
+		},
+		"api stats requested": {
+			args:         []string{"cmd", "--api-stats", "some-command"},
+			withRecorder: true,
+		},
+		"stats requested": {
+			args:         []string{"cmd", "--stats", "some-command"},
+			withRecorder: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() { tools.APIStats, tools.Stats = false, false }()
+			stats := apistats.NewRecorder()
+			var recorder *apistats.Recorder
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.Flags = []cli.Flag{
+				&cli.BoolFlag{Name: "api-stats", Destination: &tools.APIStats},
+				&cli.BoolFlag{Name: "stats", Destination: &tools.Stats},
+			}
+			app.Commands = []*cli.Command{{
+				Name: "some-command",
+				Action: func(c *cli.Context) error {
+					recorder = apistats.GetRecorder(c.Context)
+					return nil
+				},
+			}}
+			app.Before = ensureBefore(putAPIStatsInContext(stats))
+
+			require.NoError(t, app.Run(test.args))
+			if test.withRecorder {
+				assert.Equal(t, stats, recorder)
+			} else {
+				assert.Nil(t, recorder)
+			}
+		})
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "variables.tf")
+	require.NoError(t, os.WriteFile(existing, []byte("variable"), 0644))
+
+	journal := templates.NewJournal()
+	sink := templates.DirSink{Journal: journal}
+	require.NoError(t, sink.WriteFile(existing, []byte("variable")))
+	require.NoError(t, sink.WriteFile(filepath.Join(dir, "property.tf"), []byte("resource")))
+
+	buf := &bytes.Buffer{}
+	reporter := progress.NewLog(buf)
+	reporter.Start("Saving TF configurations")
+
+	err := interrupted(reporter, journal)
+	assert.Equal(t, exitcode.Interrupted, exitcode.Of(err))
+	assert.Contains(t, err.Error(), "removed 1 partially written file(s)")
+	assert.Contains(t, buf.String(), "CANCELLED")
+	assert.FileExists(t, existing)
+	assert.NoFileExists(t, filepath.Join(dir, "property.tf"))
+
+	err = interrupted(nil, journal)
+	assert.Equal(t, exitcode.Interrupted, exitcode.Of(err))
+	assert.NotContains(t, err.Error(), "removed")
+}
+
+func TestRunSummary(t *testing.T) {
+	errNotFound := exitcode.New(exitcode.NotFound, "property not found")
+	tests := map[string]struct {
+		err      error
+		warnings []warnings.Warning
+		expected runSummary
+	}{
+		"success with warnings": {
+			warnings: []warnings.Warning{{Product: "property", Object: "test", Reason: "oops"}},
+			expected: runSummary{
+				Command:  "export-property",
+				Status:   "success",
+				ExitCode: exitcode.OK,
+				Category: "ok",
+				Warnings: []string{"property 'test': oops"},
+			},
+		},
+		"categorized failure": {
+			err: cli.Exit(fmt.Errorf("Error exporting property: %w", errNotFound).Error(), exitcode.Of(errNotFound)),
+			expected: runSummary{
+				Command:  "export-property",
+				Status:   "failure",
+				ExitCode: exitcode.NotFound,
+				Category: "not_found",
+				Error:    "Error exporting property: property not found",
+				Warnings: []string{},
+			},
+		},
+		"colored message": {
+			err: cli.Exit("\x1b[31moops\x1b[0m", exitcode.IO),
+			expected: runSummary{
+				Command:  "export-property",
+				Status:   "failure",
+				ExitCode: exitcode.IO,
+				Category: "io",
+				Error:    "oops",
+				Warnings: []string{},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			collector := warnings.NewCollector()
+			for _, w := range test.warnings {
+				collector.Add(w)
+			}
+			summary := runSummary{Command: "export-property"}
+			summary.complete(test.err, collector)
+			assert.Equal(t, test.expected, summary)
+		})
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	defer func() { tools.Stats = false }()
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	sources := &statsSources{
+		objects:  workspace.NewRecorder(),
+		calls:    apistats.NewRecorder(),
+		files:    templates.NewWriteStats(),
+		warnings: warnings.NewCollector(),
+		start:    start,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	app := cli.NewApp()
+	app.Writer = io.Discard
+	app.Flags = []cli.Flag{&cli.BoolFlag{Name: "stats", Destination: &tools.Stats}}
+	app.Commands = []*cli.Command{{
+		Name: "some-command",
+		Action: func(c *cli.Context) error {
+			workspace.RecordObject(c.Context, workspace.Object{Product: "property", ID: "prp_1", Name: "test"})
+			sink := templates.GetSink(c.Context)
+			if err := sink.WriteFile(filepath.Join(t.TempDir(), "property.tf"), []byte("resource")); err != nil {
+				return err
+			}
+			client := &http.Client{Transport: sources.calls.RoundTripper(http.DefaultTransport)}
+			resp, err := client.Get(srv.URL + "/papi/v1/groups")
+			if err != nil {
+				return err
+			}
+			sources.warnings.Add(warnings.Warning{Product: "property", Object: "test", Reason: "oops"})
+			return resp.Body.Close()
+		},
+	}}
+	app.Before = ensureBefore(putStatsInContext(sources))
+	require.NoError(t, app.Run([]string{"cmd", "--stats", "some-command"}))
+
+	stats := sources.collect(start.Add(1500 * time.Millisecond))
+	assert.Equal(t, runStats{Objects: 1, APICalls: 1, DurationMs: 1500, Files: 1, Bytes: 8, Warnings: 1}, stats)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, stats.write(buf))
+	assert.Equal(t, `Run statistics:
+  Objects exported:  1
+  API calls:         1
+  Retries:           0
+  Files written:     1
+  Bytes written:     8
+  Warnings:          1
+  Duration:          1.5s
+`, buf.String())
+}
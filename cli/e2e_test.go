@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommands runs commands end to end, from parsing flags to writing files, against a fake API server
+func TestCommands(t *testing.T) {
+	tests := map[string]struct {
+		fixture      string
+		section      string
+		args         []string
+		expectedDir  string
+		filesToCheck []string
+		expectedCode int
+	}{
+		"export edgekv": {
+			fixture:      "./testdata/e2e/edgekv/api-responses.json",
+			args:         []string{"export-edgekv", "test_namespace", "staging"},
+			expectedDir:  "./testdata/e2e/edgekv/expected",
+			filesToCheck: []string{"edgekv.tf", "variables.tf", "import.sh"},
+		},
+		"export edgekv not found": {
+			fixture:      "./testdata/e2e/edgekv/api-responses.json",
+			args:         []string{"export-edgekv", "missing_namespace", "staging"},
+			expectedCode: exitcode.API,
+		},
+		"unknown section": {
+			fixture:      "./testdata/e2e/edgekv/api-responses.json",
+			section:      "missing_section",
+			args:         []string{"export-edgekv", "test_namespace", "staging"},
+			expectedCode: exitcode.Auth,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := testutils.NewAPIServer(t, test.fixture)
+			t.Setenv(edgegrid.APIURLEnv, srv.URL)
+			dir := t.TempDir()
+			section := test.section
+			if section == "" {
+				section = "test_section"
+			}
+
+			args := []string{"akamai-terraform", "--edgerc", "./testdata/.edgerc", "--section", section, test.args[0], "--tfworkpath", dir}
+			err := run(append(args, test.args[1:]...))
+			assert.Equal(t, test.expectedCode, exitcode.Of(err), "error: %s", err)
+			if test.expectedCode != exitcode.OK {
+				return
+			}
+			require.Empty(t, srv.Unmatched())
+			testutils.AssertFiles(t, test.expectedDir, dir, test.filesToCheck...)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// EnvPrefix is the prefix of environment variables setting values of flags, e.g. AKAMAI_TF_CONCURRENCY sets --concurrency
+const EnvPrefix = "AKAMAI_TF_"
+
+// sharedCommandFlags lists command flags which have the same meaning in all commands, so that they can be set with
+// environment variables as well; other command flags are not bound, as their meaning differs between commands
+var sharedCommandFlags = map[string]bool{"tfworkpath": true}
+
+// bindEnvVars lets global flags and shared command flags be set with AKAMAI_TF_* environment variables named after
+// the flag. Values given on the command line take precedence over AKAMAI_TF_* variables, which take precedence over
+// environment variables already bound to the flag, such as AKAMAI_EDGERC, which take precedence over default values.
+func bindEnvVars(app *cli.App) {
+	for _, f := range app.Flags {
+		if f != cli.HelpFlag && f != cli.VersionFlag {
+			bindEnvVar(f)
+		}
+	}
+	for _, cmd := range app.Commands {
+		for _, f := range cmd.Flags {
+			if sharedCommandFlags[f.Names()[0]] {
+				bindEnvVar(f)
+			}
+		}
+	}
+}
+
+// envVar returns name of the environment variable for the flag, e.g. AKAMAI_TF_CHECK_PROVIDER_COMPAT for --check-provider-compat
+func envVar(f cli.Flag) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.Names()[0], "-", "_"))
+}
+
+func bindEnvVar(f cli.Flag) {
+	vars := []string{envVar(f)}
+	switch flag := f.(type) {
+	case *cli.StringFlag:
+		flag.EnvVars = append(vars, flag.EnvVars...)
+	case *cli.BoolFlag:
+		flag.EnvVars = append(vars, flag.EnvVars...)
+	case *cli.IntFlag:
+		flag.EnvVars = append(vars, flag.EnvVars...)
+	case *cli.DurationFlag:
+		flag.EnvVars = append(vars, flag.EnvVars...)
+	case *cli.StringSliceFlag:
+		flag.EnvVars = append(vars, flag.EnvVars...)
+	}
+}
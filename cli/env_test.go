@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestBindEnvVars(t *testing.T) {
+	tests := map[string]struct {
+		args             []string
+		env              map[string]string
+		expectedSection  string
+		expectedWorkpath string
+		expectedCount    int
+		expectedJSON     bool
+		expectedAccount  string
+	}{
+		"defaults": {
+			args:            []string{"cmd", "some-command"},
+			expectedSection: "default",
+			expectedCount:   4,
+		},
+		"environment variables": {
+			args: []string{"cmd", "some-command"},
+			env: map[string]string{
+				"AKAMAI_TF_SECTION":     "ci",
+				"AKAMAI_TF_CONCURRENCY": "8",
+				"AKAMAI_TF_JSON":        "true",
+				"AKAMAI_TF_ACCOUNTKEY":  "key",
+				"AKAMAI_TF_TFWORKPATH":  "out",
+				"AKAMAI_TF_TTL":         "300",
+			},
+			expectedSection:  "ci",
+			expectedWorkpath: "out",
+			expectedCount:    8,
+			expectedJSON:     true,
+			expectedAccount:  "key",
+		},
+		"AKAMAI_TF_ variables take precedence over other variables": {
+			args:            []string{"cmd", "some-command"},
+			env:             map[string]string{"AKAMAI_TF_SECTION": "ci", "AKAMAI_EDGERC_SECTION": "other"},
+			expectedSection: "ci",
+			expectedCount:   4,
+		},
+		"flags take precedence over environment variables": {
+			args:             []string{"cmd", "--section", "flag", "--concurrency", "2", "some-command", "--tfworkpath", "flag"},
+			env:              map[string]string{"AKAMAI_TF_SECTION": "ci", "AKAMAI_TF_CONCURRENCY": "8", "AKAMAI_TF_TFWORKPATH": "out"},
+			expectedSection:  "flag",
+			expectedWorkpath: "flag",
+			expectedCount:    2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+			var count int
+			var isJSON bool
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			app.Flags = []cli.Flag{
+				&cli.StringFlag{Name: "section", Aliases: []string{"s"}, Value: "default", EnvVars: []string{"AKAMAI_EDGERC_SECTION"}},
+				&cli.StringFlag{Name: "accountkey", Aliases: []string{"account-key"}},
+				&cli.IntFlag{Name: "concurrency", Value: 4, Destination: &count},
+				&cli.BoolFlag{Name: "json", Destination: &isJSON},
+			}
+			app.Commands = []*cli.Command{{
+				Name: "some-command",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "tfworkpath"},
+					&cli.StringFlag{Name: "ttl"},
+				},
+				Action: func(c *cli.Context) error {
+					assert.Equal(t, test.expectedSection, c.String("section"))
+					assert.Equal(t, test.expectedAccount, c.String("accountkey"))
+					assert.Equal(t, test.expectedWorkpath, c.String("tfworkpath"))
+					// command specific flags are not bound to environment variables
+					assert.Empty(t, c.String("ttl"))
+					return nil
+				},
+			}}
+			bindEnvVars(app)
+
+			require.NoError(t, app.Run(test.args))
+			assert.Equal(t, test.expectedCount, count)
+			assert.Equal(t, test.expectedJSON, isJSON)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/apistats"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// runStats are metrics of the command run comparable across runs, reported when stats flag is set
+	runStats struct {
+		Objects    int   `json:"objects"`
+		APICalls   int   `json:"apiCalls"`
+		Retries    int   `json:"retries"`
+		DurationMs int64 `json:"durationMs"`
+		Files      int   `json:"files"`
+		Bytes      int64 `json:"bytes"`
+		Warnings   int   `json:"warnings"`
+	}
+
+	// statsSources record what the run stats are computed from
+	statsSources struct {
+		objects  *workspace.Recorder
+		calls    *apistats.Recorder
+		files    *templates.WriteStats
+		warnings *warnings.Collector
+		start    time.Time
+	}
+)
+
+// putStatsInContext makes exports record exported objects and written files into sources if stats flag is set, API
+// calls are recorded by the session as with api-stats flag
+func putStatsInContext(sources *statsSources) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		if tools.Stats {
+			c.Context = workspace.WithRecorder(c.Context, sources.objects)
+			c.Context = templates.WithWriteStats(c.Context, sources.files)
+		}
+		return nil
+	}
+}
+
+// collect returns stats of the run up to now
+func (s *statsSources) collect(now time.Time) runStats {
+	report := s.calls.Report()
+	return runStats{
+		Objects:    len(s.objects.Objects()),
+		APICalls:   report.Calls,
+		Retries:    report.Retries,
+		DurationMs: now.Sub(s.start).Milliseconds(),
+		Files:      s.files.Files(),
+		Bytes:      s.files.Bytes(),
+		Warnings:   len(s.warnings.Warnings()),
+	}
+}
+
+// write writes the stats as a summary block
+func (s runStats) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	lines := []string{
+		"Run statistics:",
+		fmt.Sprintf("  Objects exported:\t%d", s.Objects),
+		fmt.Sprintf("  API calls:\t%d", s.APICalls),
+		fmt.Sprintf("  Retries:\t%d", s.Retries),
+		fmt.Sprintf("  Files written:\t%d", s.Files),
+		fmt.Sprintf("  Bytes written:\t%d", s.Bytes),
+		fmt.Sprintf("  Warnings:\t%d", s.Warnings),
+		fmt.Sprintf("  Duration:\t%s", (time.Duration(s.DurationMs) * time.Millisecond).String()),
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(tw, line); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
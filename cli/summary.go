@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/akamai/cli-terraform/pkg/apistats"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/urfave/cli/v2"
+)
+
+// runSummary is a machine-readable summary of the command run, printed when json flag is set
+type runSummary struct {
+	Command  string   `json:"command"`
+	Status   string   `json:"status"`
+	ExitCode int      `json:"exitCode"`
+	Category string   `json:"category"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings"`
+	// APICalls is the report of API calls, set if api-stats flag is set
+	APICalls *apistats.Report `json:"apiCalls,omitempty"`
+	// Stats are metrics of the run, set if stats flag is set
+	Stats *runStats `json:"stats,omitempty"`
+}
+
+var colorCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func recordCommand(summary *runSummary) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		summary.Command = c.Args().First()
+		if summary.Command == "" && c.Bool("interactive") {
+			summary.Command = "interactive"
+		}
+		return nil
+	}
+}
+
+// complete fills in the summary with the result of the command run
+func (s *runSummary) complete(err error, collector *warnings.Collector) {
+	s.ExitCode = exitcode.Of(err)
+	s.Category = exitcode.Category(s.ExitCode)
+	s.Status = "success"
+	if err != nil {
+		s.Status = "failure"
+		s.Error = colorCodes.ReplaceAllString(err.Error(), "")
+	}
+	s.Warnings = make([]string, 0)
+	for _, w := range collector.Warnings() {
+		s.Warnings = append(s.Warnings, w.String())
+	}
+}
+
+func (s *runSummary) print(term terminal.Terminal) error {
+	content, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = term.Writeln(string(content))
+	return err
+}
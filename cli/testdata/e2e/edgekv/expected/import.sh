@@ -0,0 +1,2 @@
+terraform init
+terraform import akamai_edgekv.edgekv test_namespace:staging
\ No newline at end of file
@@ -0,0 +1,184 @@
+// Package apierrors contains code for enriching errors of failed commands with details of failed API calls
+package apierrors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// Recorder keeps the last API call which failed, calls are recorded by its round tripper
+	Recorder struct {
+		mu   sync.Mutex
+		last *Failure
+	}
+
+	// Failure describes an API call which returned a status code of 400 or above
+	Failure struct {
+		Method     string
+		Path       string
+		StatusCode int
+		// RequestID identifies the failed request for Akamai support, it is empty when the response does not carry one
+		RequestID string
+	}
+
+	roundTripperFunc func(*http.Request) (*http.Response, error)
+
+	ctxType string
+)
+
+var (
+	recorderCtx ctxType = "apiErrors"
+
+	// hints are documented remediation steps of failures with given status code
+	hints = map[int]string{
+		http.StatusUnauthorized:    "check credentials in the section of the .edgerc file used by the command and that the clock of this machine is in sync",
+		http.StatusForbidden:       "the API client is missing the scope of this API or access to the group of the object, grant it in Identity and Access Management",
+		http.StatusNotFound:        "check the name or ID of the object and that the credentials, or --accountkey, belong to the account which owns it",
+		http.StatusTooManyRequests: "the API rate limit was exceeded, lower --concurrency and run the command again",
+	}
+
+	// requestIDHeader carries ID of the request in responses of Akamai APIs which do not include it in the body
+	requestIDHeader = "X-Trace-Id"
+
+	// maxBodySize limits the size of error bodies read to find the request ID
+	maxBodySize int64 = 64 * 1024
+)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// NewRecorder returns a new Recorder without any failure
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// RoundTripper returns http.RoundTripper which records failed calls made using the next round tripper; the failure is
+// forgotten when the same request succeeds afterwards, e.g. when it was retried
+func (r *Recorder) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		if resp.StatusCode < http.StatusBadRequest {
+			r.clear(req)
+			return resp, nil
+		}
+		failure := Failure{Method: req.Method, Path: req.URL.Path, StatusCode: resp.StatusCode}
+		failure.RequestID, resp.Body = requestID(resp)
+		r.mu.Lock()
+		r.last = &failure
+		r.mu.Unlock()
+		return resp, nil
+	})
+}
+
+func (r *Recorder) clear(req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last != nil && r.last.Method == req.Method && r.last.Path == req.URL.Path {
+		r.last = nil
+	}
+}
+
+// Last returns the last failed API call, nil is returned when no call failed
+func (r *Recorder) Last() *Failure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// Enrich returns err with details and remediation hint of the last failed API call appended to its message. err is
+// returned as it is when no call failed or its exit code shows it is not caused by the API, e.g. for template errors.
+// The exit code of err is kept, unless it is the general one, in which case the exit code matching the status code of
+// the failure is used instead.
+func (r *Recorder) Enrich(err error) error {
+	failure := r.Last()
+	if err == nil || failure == nil {
+		return err
+	}
+	code := exitcode.Of(err)
+	switch code {
+	case exitcode.General:
+		code = failure.ExitCode()
+	case exitcode.Auth, exitcode.NotFound, exitcode.API:
+	default:
+		return err
+	}
+	return cli.Exit(err.Error()+"\n"+failure.String(), code)
+}
+
+// String returns the failed call with its status, request ID and hint, each on a separate line
+func (f Failure) String() string {
+	s := fmt.Sprintf("API call %s %s failed with status %d %s", f.Method, f.Path, f.StatusCode, http.StatusText(f.StatusCode))
+	if f.RequestID != "" {
+		s += fmt.Sprintf("\nRequest ID: %s", f.RequestID)
+	}
+	if hint := f.Hint(); hint != "" {
+		s += fmt.Sprintf("\nHint: %s", hint)
+	}
+	return s
+}
+
+// Hint returns documented remediation of the failure, empty string is returned when there is none
+func (f Failure) Hint() string {
+	if hint, ok := hints[f.StatusCode]; ok {
+		return hint
+	}
+	if f.StatusCode >= http.StatusInternalServerError {
+		return "the API failed to process the request, run the command again later and contact Akamai support with the request ID if it keeps failing"
+	}
+	return ""
+}
+
+// ExitCode returns the exit code matching the status code of the failure
+func (f Failure) ExitCode() int {
+	switch f.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return exitcode.Auth
+	case http.StatusNotFound:
+		return exitcode.NotFound
+	}
+	return exitcode.API
+}
+
+// requestID returns ID of the failed request, found either in the problem details body or in the response headers,
+// and the body to be read by the caller instead of the consumed one
+func requestID(resp *http.Response) (string, io.ReadCloser) {
+	if resp.Body == nil {
+		return resp.Header.Get(requestIDHeader), nil
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	body := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(content), resp.Body), resp.Body}
+	var problem struct {
+		RequestID string `json:"requestId"`
+	}
+	if err == nil && json.Unmarshal(content, &problem) == nil && problem.RequestID != "" {
+		return problem.RequestID, body
+	}
+	return resp.Header.Get(requestIDHeader), body
+}
+
+// WithRecorder puts a Recorder in context
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderCtx, r)
+}
+
+// GetRecorder retrieves a Recorder from context, nil is returned if failed API calls are not recorded
+func GetRecorder(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderCtx).(*Recorder)
+	return r
+}
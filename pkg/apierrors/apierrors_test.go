@@ -0,0 +1,154 @@
+package apierrors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestRecorder(t *testing.T) {
+	unavailable := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/papi/v1/groups":
+			w.Header().Set("X-Trace-Id", "trace-1")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"title": "Forbidden"}`))
+		case "/edgeworkers/v1/ids/1":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"title": "Not Found", "requestId": "req-1"}`))
+		case "/papi/v1/properties":
+			if unavailable {
+				unavailable = false
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		paths        []string
+		expected     *Failure
+		expectedBody string
+	}{
+		"no failure": {
+			paths: []string{"/papi/v1/contracts"},
+		},
+		"request ID from header": {
+			paths:        []string{"/papi/v1/groups"},
+			expected:     &Failure{Method: http.MethodGet, Path: "/papi/v1/groups", StatusCode: http.StatusForbidden, RequestID: "trace-1"},
+			expectedBody: `{"title": "Forbidden"}`,
+		},
+		"request ID from body": {
+			paths:        []string{"/papi/v1/groups", "/edgeworkers/v1/ids/1"},
+			expected:     &Failure{Method: http.MethodGet, Path: "/edgeworkers/v1/ids/1", StatusCode: http.StatusNotFound, RequestID: "req-1"},
+			expectedBody: `{"title": "Not Found", "requestId": "req-1"}`,
+		},
+		"retried request": {
+			paths: []string{"/papi/v1/properties", "/papi/v1/properties"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := NewRecorder()
+			client := &http.Client{Transport: recorder.RoundTripper(http.DefaultTransport)}
+			var body []byte
+			for _, path := range test.paths {
+				resp, err := client.Get(srv.URL + path)
+				require.NoError(t, err)
+				body, err = io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, resp.Body.Close())
+			}
+			assert.Equal(t, test.expected, recorder.Last())
+			if test.expected != nil {
+				assert.Equal(t, test.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	tests := map[string]struct {
+		failure      *Failure
+		err          error
+		expected     string
+		expectedCode int
+	}{
+		"no error": {
+			failure: &Failure{Method: http.MethodGet, Path: "/papi/v1/groups", StatusCode: http.StatusForbidden},
+		},
+		"no failure": {
+			err:          cli.Exit("Error exporting property: oops", exitcode.API),
+			expected:     "Error exporting property: oops",
+			expectedCode: exitcode.API,
+		},
+		"unauthorized": {
+			failure: &Failure{Method: http.MethodGet, Path: "/papi/v1/groups", StatusCode: http.StatusUnauthorized, RequestID: "req-1"},
+			err:     cli.Exit("Error exporting property: oops", exitcode.General),
+			expected: "Error exporting property: oops\n" +
+				"API call GET /papi/v1/groups failed with status 401 Unauthorized\n" +
+				"Request ID: req-1\n" +
+				"Hint: check credentials in the section of the .edgerc file used by the command and that the clock of this machine is in sync",
+			expectedCode: exitcode.Auth,
+		},
+		"forbidden": {
+			failure: &Failure{Method: http.MethodGet, Path: "/cloudlets/api/v2/policies", StatusCode: http.StatusForbidden},
+			err:     fmt.Errorf("oops"),
+			expected: "oops\n" +
+				"API call GET /cloudlets/api/v2/policies failed with status 403 Forbidden\n" +
+				"Hint: the API client is missing the scope of this API or access to the group of the object, grant it in Identity and Access Management",
+			expectedCode: exitcode.Auth,
+		},
+		"not found keeps exit code": {
+			failure: &Failure{Method: http.MethodGet, Path: "/config-dns/v2/zones/example.com", StatusCode: http.StatusNotFound},
+			err:     cli.Exit("Error exporting zone: oops", exitcode.API),
+			expected: "Error exporting zone: oops\n" +
+				"API call GET /config-dns/v2/zones/example.com failed with status 404 Not Found\n" +
+				"Hint: check the name or ID of the object and that the credentials, or --accountkey, belong to the account which owns it",
+			expectedCode: exitcode.API,
+		},
+		"status without hint": {
+			failure: &Failure{Method: http.MethodPost, Path: "/papi/v1/search/find-by-value", StatusCode: http.StatusBadRequest},
+			err:     cli.Exit("Error exporting property: oops", exitcode.General),
+			expected: "Error exporting property: oops\n" +
+				"API call POST /papi/v1/search/find-by-value failed with status 400 Bad Request",
+			expectedCode: exitcode.API,
+		},
+		"error not caused by API": {
+			failure:      &Failure{Method: http.MethodGet, Path: "/papi/v1/groups", StatusCode: http.StatusInternalServerError},
+			err:          cli.Exit("Error saving files: oops", exitcode.IO),
+			expected:     "Error saving files: oops",
+			expectedCode: exitcode.IO,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := NewRecorder()
+			recorder.last = test.failure
+			err := recorder.Enrich(test.err)
+			if test.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, test.expected, err.Error())
+			assert.Equal(t, test.expectedCode, exitcode.Of(err))
+		})
+	}
+}
+
+func TestFailureHint(t *testing.T) {
+	assert.Contains(t, Failure{StatusCode: http.StatusBadGateway}.Hint(), "contact Akamai support")
+	assert.Contains(t, Failure{StatusCode: http.StatusTooManyRequests}.Hint(), "--concurrency")
+	assert.Empty(t, Failure{StatusCode: http.StatusConflict}.Hint())
+}
@@ -0,0 +1,36 @@
+package apierrors
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// Transient returns true if the API call failed with 429 or 5xx status code, which may succeed when it is repeated
+func Transient(err error) bool {
+	code := StatusCode(err)
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// Retry calls fn until it succeeds or fails with an error which is not transient, retrying it at most tools.Retries
+// times; the first retry waits tools.RetryBackoff, which doubles with every next retry. The last error is returned
+// once retries are exhausted, or the error of the context when it is done while waiting
+func Retry(ctx context.Context, fn func() error) error {
+	backoff := tools.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= tools.Retries || !Transient(err) {
+			return err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,77 @@
+package apierrors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+	defer func(retries int, backoff time.Duration) {
+		tools.Retries, tools.RetryBackoff = retries, backoff
+	}(tools.Retries, tools.RetryBackoff)
+	tools.Retries, tools.RetryBackoff = 2, time.Millisecond
+
+	tests := map[string]struct {
+		errs          []error
+		expectedCalls int
+		expectedErr   error
+	}{
+		"success": {
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		"success after transient errors": {
+			errs:          []error{&cloudlets.Error{StatusCode: 502}, &cloudlets.Error{StatusCode: 429}, nil},
+			expectedCalls: 3,
+		},
+		"retries exhausted": {
+			errs:          []error{&cloudlets.Error{StatusCode: 502}, &cloudlets.Error{StatusCode: 503}, &cloudlets.Error{StatusCode: 500}},
+			expectedCalls: 3,
+			expectedErr:   &cloudlets.Error{StatusCode: 500},
+		},
+		"error which is not transient": {
+			errs:          []error{&cloudlets.Error{StatusCode: 404}},
+			expectedCalls: 1,
+			expectedErr:   &cloudlets.Error{StatusCode: 404},
+		},
+		"error without status code": {
+			errs:          []error{fmt.Errorf("oops")},
+			expectedCalls: 1,
+			expectedErr:   fmt.Errorf("oops"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			err := Retry(context.Background(), func() error {
+				calls++
+				return test.errs[calls-1]
+			})
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedCalls, calls)
+		})
+	}
+}
+
+func TestRetryCanceledContext(t *testing.T) {
+	defer func(retries int, backoff time.Duration) {
+		tools.Retries, tools.RetryBackoff = retries, backoff
+	}(tools.Retries, tools.RetryBackoff)
+	tools.Retries, tools.RetryBackoff = 2, time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Retry(ctx, func() error {
+		calls++
+		cancel()
+		return &cloudlets.Error{StatusCode: 502}
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, calls)
+}
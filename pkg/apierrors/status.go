@@ -0,0 +1,68 @@
+package apierrors
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/appsec"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/iam"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/imaging"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
+	"github.com/akamai/cli-terraform/pkg/providers/mtls"
+)
+
+// StatusCode returns HTTP status code of the API error of any of the exported products, 0 is returned if the error
+// does not carry one
+func StatusCode(err error) int {
+	var (
+		papiErr        *papi.Error
+		dnsErr         *dns.Error
+		gtmErr         *gtm.Error
+		cloudletsErr   *cloudlets.Error
+		sharedErr      *shared.Error
+		appsecErr      *appsec.Error
+		edgeworkersErr *edgeworkers.Error
+		iamErr         *iam.Error
+		imagingErr     *imaging.Error
+		cpsErr         *cps.Error
+		mtlsErr        *mtls.Error
+	)
+	switch {
+	case errors.As(err, &papiErr):
+		return papiErr.StatusCode
+	case errors.As(err, &dnsErr):
+		return dnsErr.StatusCode
+	case errors.As(err, &gtmErr):
+		return gtmErr.StatusCode
+	case errors.As(err, &cloudletsErr):
+		return cloudletsErr.StatusCode
+	case errors.As(err, &sharedErr):
+		return sharedErr.StatusCode
+	case errors.As(err, &appsecErr):
+		return appsecErr.StatusCode
+	case errors.As(err, &edgeworkersErr):
+		return edgeworkersErr.Status
+	case errors.As(err, &iamErr):
+		return iamErr.StatusCode
+	case errors.As(err, &imagingErr):
+		return imagingErr.Status
+	case errors.As(err, &cpsErr):
+		return cpsErr.StatusCode
+	case errors.As(err, &mtlsErr):
+		return mtlsErr.StatusCode
+	}
+	return 0
+}
+
+// NotEntitled returns true if the API call failed with 403, which for optional parts of exported objects, such as
+// subsystems of security configurations or certificates of properties, means that the API client or the contract is
+// not entitled to them, so they can be skipped instead of failing the whole export
+func NotEntitled(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
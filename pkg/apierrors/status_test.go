@@ -0,0 +1,55 @@
+package apierrors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/appsec"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
+	"github.com/akamai/cli-terraform/pkg/providers/mtls"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := map[string]struct {
+		err                 error
+		expected            int
+		expectedNotEntitled bool
+	}{
+		"wrapped appsec error": {
+			err:                 fmt.Errorf("get WAF mode: %w", &appsec.Error{StatusCode: 403}),
+			expected:            403,
+			expectedNotEntitled: true,
+		},
+		"cps error": {
+			err:      &cps.Error{StatusCode: 404},
+			expected: 404,
+		},
+		"edgeworkers error": {
+			err:                 &edgeworkers.Error{Status: 403},
+			expected:            403,
+			expectedNotEntitled: true,
+		},
+		"mtls error": {
+			err:      &mtls.Error{StatusCode: 401},
+			expected: 401,
+		},
+		"shared cloudlets error": {
+			err:                 fmt.Errorf("list shared policies: %w", &shared.Error{StatusCode: 403}),
+			expected:            403,
+			expectedNotEntitled: true,
+		},
+		"error without status code": {
+			err: fmt.Errorf("oops"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, StatusCode(test.err))
+			assert.Equal(t, test.expectedNotEntitled, NotEntitled(test.err))
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package apistats contains code for recording counts and durations of API calls made by a command
+package apistats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+	"unicode"
+)
+
+type (
+	// Recorder aggregates API calls per endpoint, calls are recorded by its round tripper
+	Recorder struct {
+		mu        sync.Mutex
+		endpoints map[string]*endpointStats
+		// failed holds requests, as method and URL, whose last attempt failed, sending them again counts as a retry
+		failed   map[string]bool
+		inFlight int
+		peak     int
+		now      func() time.Time
+	}
+
+	// Report is a summary of API calls made by the command
+	Report struct {
+		Calls           int        `json:"calls"`
+		Errors          int        `json:"errors"`
+		Retries         int        `json:"retries"`
+		TotalMs         int64      `json:"totalMs"`
+		PeakConcurrency int        `json:"peakConcurrency"`
+		Endpoints       []Endpoint `json:"endpoints"`
+	}
+
+	// Endpoint is a summary of calls of a single endpoint, IDs in its path are replaced with '{id}'
+	Endpoint struct {
+		Method  string `json:"method"`
+		Path    string `json:"path"`
+		Calls   int    `json:"calls"`
+		Errors  int    `json:"errors"`
+		Retries int    `json:"retries"`
+		TotalMs int64  `json:"totalMs"`
+		AvgMs   int64  `json:"avgMs"`
+		MaxMs   int64  `json:"maxMs"`
+	}
+
+	endpointStats struct {
+		method, path           string
+		calls, errors, retries int
+		total, max             time.Duration
+	}
+
+	roundTripperFunc func(*http.Request) (*http.Response, error)
+
+	ctxType string
+)
+
+var (
+	recorderCtx ctxType = "apiStats"
+
+	apiVersion = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// NewRecorder returns a new, empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		endpoints: make(map[string]*endpointStats),
+		failed:    make(map[string]bool),
+		now:       time.Now,
+	}
+}
+
+// RoundTripper returns http.RoundTripper which records calls made using the next round tripper; a call fails when it
+// returns an error or a status code of 400 or above, repeating a call which failed with 429, 5xx or an error is a retry
+func (r *Recorder) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		r.start()
+		begin := r.now()
+		resp, err := next.RoundTrip(req)
+		r.finish(req, resp, err, r.now().Sub(begin))
+		return resp, err
+	})
+}
+
+func (r *Recorder) start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inFlight++
+	if r.inFlight > r.peak {
+		r.peak = r.inFlight
+	}
+}
+
+func (r *Recorder) finish(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	path := NormalizePath(req.URL.Path)
+	key := req.Method + " " + path
+	request := req.Method + " " + req.URL.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inFlight--
+	stats, ok := r.endpoints[key]
+	if !ok {
+		stats = &endpointStats{method: req.Method, path: path}
+		r.endpoints[key] = stats
+	}
+	stats.calls++
+	stats.total += elapsed
+	if elapsed > stats.max {
+		stats.max = elapsed
+	}
+	if r.failed[request] {
+		stats.retries++
+	}
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		stats.errors++
+	}
+	retryable := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	if retryable {
+		r.failed[request] = true
+	} else {
+		delete(r.failed, request)
+	}
+}
+
+// Report returns summary of calls recorded so far, endpoints are sorted by the total time spent calling them
+func (r *Recorder) Report() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := Report{PeakConcurrency: r.peak, Endpoints: make([]Endpoint, 0, len(r.endpoints))}
+	var total time.Duration
+	for _, stats := range r.endpoints {
+		report.Calls += stats.calls
+		report.Errors += stats.errors
+		report.Retries += stats.retries
+		total += stats.total
+		report.Endpoints = append(report.Endpoints, Endpoint{
+			Method:  stats.method,
+			Path:    stats.path,
+			Calls:   stats.calls,
+			Errors:  stats.errors,
+			Retries: stats.retries,
+			TotalMs: stats.total.Milliseconds(),
+			AvgMs:   (stats.total / time.Duration(stats.calls)).Milliseconds(),
+			MaxMs:   stats.max.Milliseconds(),
+		})
+	}
+	report.TotalMs = total.Milliseconds()
+	sort.Slice(report.Endpoints, func(i, j int) bool {
+		a, b := report.Endpoints[i], report.Endpoints[j]
+		if a.TotalMs != b.TotalMs {
+			return a.TotalMs > b.TotalMs
+		}
+		return a.Method+" "+a.Path < b.Method+" "+b.Path
+	})
+	return report
+}
+
+// WriteReport writes the report as a table of endpoints preceded by totals, concurrency is the configured limit of
+// API requests run in parallel
+func WriteReport(w io.Writer, report Report, concurrency int) error {
+	_, err := fmt.Fprintf(w, "API calls: %d (%d errors, %d retries), %s spent in calls, peak concurrency %d of %d\n",
+		report.Calls, report.Errors, report.Retries, formatMs(report.TotalMs), report.PeakConcurrency, concurrency)
+	if err != nil || len(report.Endpoints) == 0 {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  CALLS\tERRORS\tRETRIES\tTOTAL\tAVG\tMAX\tENDPOINT")
+	for _, e := range report.Endpoints {
+		fmt.Fprintf(tw, "  %d\t%d\t%d\t%s\t%s\t%s\t%s %s\n", e.Calls, e.Errors, e.Retries,
+			formatMs(e.TotalMs), formatMs(e.AvgMs), formatMs(e.MaxMs), e.Method, e.Path)
+	}
+	return tw.Flush()
+}
+
+// NormalizePath replaces path segments which identify objects, i.e. contain a digit, a dot or an '@' and are not API
+// versions such as 'v1', with '{id}', so that calls fetching different objects are reported as calls of the same endpoint
+func NormalizePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if apiVersion.MatchString(segment) {
+			continue
+		}
+		if strings.IndexFunc(segment, func(r rune) bool { return unicode.IsDigit(r) || r == '.' || r == '@' }) >= 0 {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// WithRecorder puts a Recorder in context
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderCtx, r)
+}
+
+// GetRecorder retrieves a Recorder from context, nil is returned if API calls are not recorded
+func GetRecorder(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderCtx).(*Recorder)
+	return r
+}
+
+func formatMs(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
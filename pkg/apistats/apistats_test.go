@@ -0,0 +1,114 @@
+package apistats
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	unavailable := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/papi/v1/properties/prp_1/versions/2/rules" && unavailable:
+			unavailable = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/papi/v1/groups":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	recorder := NewRecorder()
+	clock := time.Unix(0, 0)
+	recorder.now = func() time.Time {
+		clock = clock.Add(100 * time.Millisecond)
+		return clock
+	}
+	client := &http.Client{Transport: recorder.RoundTripper(http.DefaultTransport)}
+	for _, path := range []string{
+		"/papi/v1/properties/prp_1/versions/2/rules",
+		"/papi/v1/properties/prp_1/versions/2/rules",
+		"/papi/v1/properties/prp_2/versions/1/rules?contractId=ctr_1",
+		"/papi/v1/groups",
+	} {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	assert.Equal(t, Report{
+		Calls:           4,
+		Errors:          2,
+		Retries:         1,
+		TotalMs:         400,
+		PeakConcurrency: 1,
+		Endpoints: []Endpoint{
+			{Method: http.MethodGet, Path: "/papi/v1/properties/{id}/versions/{id}/rules", Calls: 3, Errors: 1, Retries: 1, TotalMs: 300, AvgMs: 100, MaxMs: 100},
+			{Method: http.MethodGet, Path: "/papi/v1/groups", Calls: 1, Errors: 1, TotalMs: 100, AvgMs: 100, MaxMs: 100},
+		},
+	}, recorder.Report())
+}
+
+func TestWriteReport(t *testing.T) {
+	tests := map[string]struct {
+		report   Report
+		expected string
+	}{
+		"no calls": {
+			report:   Report{Endpoints: []Endpoint{}},
+			expected: "API calls: 0 (0 errors, 0 retries), 0s spent in calls, peak concurrency 0 of 4\n",
+		},
+		"endpoints": {
+			report: Report{
+				Calls: 3, Errors: 1, Retries: 1, TotalMs: 1500, PeakConcurrency: 2,
+				Endpoints: []Endpoint{
+					{Method: "GET", Path: "/papi/v1/properties/{id}", Calls: 2, Errors: 1, Retries: 1, TotalMs: 1200, AvgMs: 600, MaxMs: 1000},
+					{Method: "GET", Path: "/papi/v1/groups", Calls: 1, TotalMs: 300, AvgMs: 300, MaxMs: 300},
+				},
+			},
+			expected: "API calls: 3 (1 errors, 1 retries), 1.5s spent in calls, peak concurrency 2 of 4\n" +
+				"  CALLS  ERRORS  RETRIES  TOTAL  AVG    MAX    ENDPOINT\n" +
+				"  2      1       1        1.2s   600ms  1s     GET /papi/v1/properties/{id}\n" +
+				"  1      0       0        300ms  300ms  300ms  GET /papi/v1/groups\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteReport(&buf, test.report, 4))
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		expected string
+	}{
+		"no IDs":          {path: "/papi/v1/groups", expected: "/papi/v1/groups"},
+		"prefixed IDs":    {path: "/papi/v1/properties/prp_1/versions/3", expected: "/papi/v1/properties/{id}/versions/{id}"},
+		"zone name":       {path: "/config-dns/v2/zones/example.com/recordsets", expected: "/config-dns/v2/zones/{id}/recordsets"},
+		"user email":      {path: "/identity-management/v3/user-admin/ui-identities/a@example.com", expected: "/identity-management/v3/user-admin/ui-identities/{id}"},
+		"name without ID": {path: "/edgekv/v1/networks/staging/namespaces/test", expected: "/edgekv/v1/networks/staging/namespaces/test"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, NormalizePath(test.path))
+		})
+	}
+}
+
+func TestGetRecorder(t *testing.T) {
+	assert.Nil(t, GetRecorder(context.Background()))
+
+	recorder := NewRecorder()
+	assert.Equal(t, recorder, GetRecorder(WithRecorder(context.Background(), recorder)))
+}
@@ -0,0 +1,111 @@
+// Package archive contains code for packaging exported configuration into a tarball
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+)
+
+// File is an additional file stored in the archive next to the exported configuration
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// ErrCreatingArchive is returned when the archive cannot be written
+var ErrCreatingArchive = exitcode.New(exitcode.IO, "creating archive")
+
+// Write packs given files from dir, with paths relative to dir, together with given additional files into gzip
+// compressed tarball at target path; the target itself is skipped when it is placed within dir
+func Write(target, dir string, names []string, files ...File) (err error) {
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCreatingArchive, err)
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCreatingArchive, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("%w: %s", ErrCreatingArchive, closeErr)
+		}
+		if err != nil {
+			_ = os.Remove(target)
+		}
+	}()
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrCreatingArchive, err)
+		}
+		if absPath == absTarget {
+			continue
+		}
+		if err := addFile(tw, path, filepath.ToSlash(name)); err != nil {
+			return fmt.Errorf("%w: %s", ErrCreatingArchive, err)
+		}
+	}
+	for _, f := range files {
+		if err := addContent(tw, f.Name, f.Content); err != nil {
+			return fmt.Errorf("%w: %s", ErrCreatingArchive, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("%w: %s", ErrCreatingArchive, err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("%w: %s", ErrCreatingArchive, err)
+	}
+	return nil
+}
+
+func addFile(tw *tar.Writer, path, name string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func addContent(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: int64(len(content)),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
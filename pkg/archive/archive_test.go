@@ -0,0 +1,110 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	tests := map[string]struct {
+		files     map[string]string
+		extra     []File
+		inside    bool
+		expected  map[string]string
+		withError error
+	}{
+		"workspace with modules": {
+			files: map[string]string{
+				"policy.tf":                "resource {}",
+				"import.sh":                "terraform import",
+				"modules/match_rules/a.tf": "data {}",
+			},
+			expected: map[string]string{
+				"policy.tf":                "resource {}",
+				"import.sh":                "terraform import",
+				"modules/match_rules/a.tf": "data {}",
+			},
+		},
+		"additional files": {
+			files: map[string]string{"zone.tf": "resource {}"},
+			extra: []File{
+				{Name: "meta/manifest.json", Content: []byte(`{"objects":[]}`)},
+				{Name: "meta/api-responses.json", Content: []byte(`[]`)},
+			},
+			expected: map[string]string{
+				"zone.tf":                 "resource {}",
+				"meta/manifest.json":      `{"objects":[]}`,
+				"meta/api-responses.json": `[]`,
+			},
+		},
+		"archive placed in the workspace": {
+			files:    map[string]string{"domain.tf": "resource {}"},
+			inside:   true,
+			expected: map[string]string{"domain.tf": "resource {}"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for file, content := range test.files {
+				path := filepath.Join(dir, filepath.FromSlash(file))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+			target := filepath.Join(t.TempDir(), "out.tar.gz")
+			if test.inside {
+				target = filepath.Join(dir, "out.tar.gz")
+			}
+
+			var names []string
+			for file := range test.files {
+				names = append(names, filepath.FromSlash(file))
+			}
+			if test.inside {
+				names = append(names, "out.tar.gz")
+			}
+			require.NoError(t, Write(target, dir, names, test.extra...))
+			assert.Equal(t, test.expected, readArchive(t, target))
+		})
+	}
+
+	t.Run("missing workspace", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "out.tar.gz")
+		err := Write(target, filepath.Join(t.TempDir(), "missing"), []string{"policy.tf"})
+		assert.True(t, errors.Is(err, ErrCreatingArchive), "expected: %s; got: %s", ErrCreatingArchive, err)
+		assert.NoFileExists(t, target)
+	})
+}
+
+func readArchive(t *testing.T, path string) map[string]string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	return files
+}
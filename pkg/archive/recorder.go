@@ -0,0 +1,89 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type (
+	// Recorder stores API responses received during the export, so that they can be attached to the archive
+	Recorder struct {
+		mu        sync.Mutex
+		responses []Response
+	}
+
+	// Response is a single recorded API response
+	Response struct {
+		Method     string `json:"method"`
+		URL        string `json:"url"`
+		StatusCode int    `json:"statusCode"`
+		Body       string `json:"body"`
+	}
+
+	roundTripperFunc func(*http.Request) (*http.Response, error)
+
+	ctxType string
+)
+
+var recorderCtx ctxType = "recorder"
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// NewRecorder returns a new, empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// RoundTripper returns http.RoundTripper which records responses returned by the next round tripper;
+// request headers are not recorded, so that credentials do not leak into the archive
+func (r *Recorder) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		if closeErr := resp.Body.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.responses = append(r.responses, Response{
+			Method:     req.Method,
+			URL:        req.URL.String(),
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+		})
+		return resp, nil
+	})
+}
+
+// Responses returns all responses recorded so far, in the order they were received
+func (r *Recorder) Responses() []Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	responses := make([]Response, len(r.responses))
+	copy(responses, r.responses)
+	return responses
+}
+
+// WithRecorder puts a Recorder in context
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderCtx, r)
+}
+
+// GetRecorder retrieves a Recorder from context, nil is returned if API responses are not recorded
+func GetRecorder(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderCtx).(*Recorder)
+	return r
+}
@@ -0,0 +1,47 @@
+package archive
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, err := w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	recorder := NewRecorder()
+	client := &http.Client{Transport: recorder.RoundTripper(http.DefaultTransport)}
+	for _, path := range []string{"/policies", "/missing"} {
+		resp, err := client.Get(srv.URL + path)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		// body is still available to the caller after it was recorded
+		assert.Equal(t, `{"path":"`+path+`"}`, string(body))
+	}
+
+	assert.Equal(t, []Response{
+		{Method: http.MethodGet, URL: srv.URL + "/policies", StatusCode: http.StatusOK, Body: `{"path":"/policies"}`},
+		{Method: http.MethodGet, URL: srv.URL + "/missing", StatusCode: http.StatusNotFound, Body: `{"path":"/missing"}`},
+	}, recorder.Responses())
+}
+
+func TestGetRecorder(t *testing.T) {
+	assert.Nil(t, GetRecorder(context.Background()))
+
+	recorder := NewRecorder()
+	assert.Equal(t, recorder, GetRecorder(WithRecorder(context.Background(), recorder)))
+}
@@ -0,0 +1,260 @@
+// Package batch contains code for exporting all objects selected in an export manifest in a single run
+package batch
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+type ctxType string
+
+var nestedCtx ctxType = "nested"
+
+var (
+	// ErrUnknownCommand is returned when a manifest object refers to a command which does not exist
+	ErrUnknownCommand = exitcode.New(exitcode.General, "unknown export command")
+	// ErrExportFailed is returned when export of at least one object failed
+	ErrExportFailed = exitcode.New(exitcode.General, "export failed")
+	// ErrCreatingDirectory is returned when the directory for an exported object cannot be created
+	ErrCreatingDirectory = exitcode.New(exitcode.IO, "unable to create export directory")
+	// ErrNothingExported is returned when exports of all objects are skipped with continue-on-unsupported flag
+	ErrNothingExported = exitcode.New(exitcode.Unsupported, "nothing exported")
+
+	// sequential lists commands which keep the export state in package variables, so they must not run in parallel with themselves
+	sequential = map[string]*sync.Mutex{
+		"export-zone":   {},
+		"export-appsec": {},
+	}
+
+	// FilesystemCommands write generated files directly into the filesystem, they do not support other output sinks
+	FilesystemCommands = map[string]bool{"export-zone": true, "create-zone": true}
+
+	nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// CmdExportManifest is an entrypoint to export-manifest command
+func CmdExportManifest(c *cli.Context) error {
+	m, err := manifest.Load(c.Args().First())
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	// tfWorkPath is a root directory under which each object is exported into its own subdirectory
+	var tfWorkPath = "./"
+	if c.IsSet("tfworkpath") {
+		tfWorkPath = c.String("tfworkpath")
+	}
+	return Run(c, m.Selected(), tfWorkPath)
+}
+
+// Run exports objects concurrently into isolated subdirectories of root, see Dir. All exports share the session,
+// the API request limit of the command context and results of calls stored with Memo, their progress is reported in
+// aggregate. Objects of different products are exported in turns, see schedule. Failed exports do not stop the
+// remaining ones, they are reported as warnings and an error is returned once all objects are processed.
+// With continue-on-unsupported flag of the command, exports failing as the object is not supported are skipped and
+// listed in a report instead, an error is then returned only if no object was exported. Objects of FilesystemCommands
+// are rejected before any export starts, unless generated files are written into the filesystem.
+func Run(c *cli.Context, objects []manifest.Object, root string) error {
+	ctx := c.Context
+	term := terminal.Get(ctx)
+	if len(objects) == 0 {
+		term.Writeln("No objects selected for export")
+		return nil
+	}
+	if tools.OutputTemplate != "" {
+		if err := templates.ValidateOutputTemplate(tools.OutputTemplate); err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+	}
+	if !templates.IsDirSink(templates.GetSink(ctx)) {
+		for _, object := range objects {
+			if cmd := c.App.Command(object.Command); cmd != nil && FilesystemCommands[cmd.Name] {
+				err := fmt.Errorf("%w: '%s' of %s %s writes only into the filesystem", templates.ErrInvalidOutputSink, cmd.Name, object.Product, object.Name)
+				return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+			}
+		}
+	}
+
+	// output of individual exports would interleave, only errors and warnings are kept
+	exportCtx := context.WithValue(ctx, nestedCtx, true)
+	exportCtx = withMemo(exportCtx, c)
+	exportCtx = progress.WithReporter(exportCtx, progress.NewDiscard())
+	exportCtx = terminal.Context(exportCtx, terminal.New(terminal.DiscardWriter(), nil, term.Error()))
+
+	errs := make([]error, len(objects))
+	order := schedule(objects)
+	progress.Get(ctx).Start("Exporting %d objects", len(objects))
+	progress.Get(ctx).Total(len(objects))
+	err := tools.RunConcurrently(exportCtx, len(objects), func(runCtx context.Context, n int) error {
+		i := order[n]
+		errs[i] = export(runCtx, c, objects[i], Dir(root, objects[i]))
+		progress.Get(ctx).Step()
+		return runCtx.Err()
+	})
+	if err != nil {
+		progress.Get(ctx).Fail()
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting objects: %s", err)), exitcode.Of(err))
+	}
+
+	continueOnUnsupported := c.Bool("continue-on-unsupported")
+	var failed int
+	var firstErr error
+	var skipped []int
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if continueOnUnsupported && exitcode.Of(err) == exitcode.Unsupported {
+			skipped = append(skipped, i)
+			warnings.Report(ctx, warnings.Warning{
+				Product: objects[i].Product,
+				Object:  objects[i].Name,
+				Reason:  fmt.Sprintf("export skipped: %s", err),
+			})
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		failed++
+		warnings.Report(ctx, warnings.Warning{
+			Product: objects[i].Product,
+			Object:  objects[i].Name,
+			Reason:  fmt.Sprintf("export failed: %s", err),
+		})
+	}
+	exported := len(objects) - failed - len(skipped)
+	printSkipped(ctx, objects, errs, skipped)
+	if failed > 0 {
+		progress.Get(ctx).Fail()
+		err := fmt.Errorf("%w: %d of %d objects could not be exported", ErrExportFailed, failed, len(objects))
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(firstErr))
+	}
+	if exported == 0 {
+		progress.Get(ctx).Fail()
+		err := fmt.Errorf("%w: all %d objects are not supported", ErrNothingExported, len(objects))
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	progress.Get(ctx).OK()
+	term.Printf("Exported %d objects into %s\n", exported, root)
+	return nil
+}
+
+// printSkipped prints the report of objects whose exports are skipped as they are not supported, with the errors
+// returned by their exports
+func printSkipped(ctx context.Context, objects []manifest.Object, errs []error, skipped []int) {
+	if len(skipped) == 0 {
+		return
+	}
+	term := terminal.Get(ctx)
+	term.Printf("Skipped %d unsupported objects:\n", len(skipped))
+	for _, i := range skipped {
+		term.Printf("  %s %s: %s\n", objects[i].Product, objects[i].Name, errs[i])
+	}
+}
+
+// schedule returns indexes of objects in the order in which their exports start: products take turns in the order of
+// their first object, so that exports running in parallel call different APIs and use request budgets of all of them
+// instead of waiting for the budget of a single API, see throttle.Budget
+func schedule(objects []manifest.Object) []int {
+	var products []string
+	queues := make(map[string][]int)
+	for i, object := range objects {
+		if _, ok := queues[object.Product]; !ok {
+			products = append(products, object.Product)
+		}
+		queues[object.Product] = append(queues[object.Product], i)
+	}
+	order := make([]int, 0, len(objects))
+	for len(order) < len(objects) {
+		for _, product := range products {
+			if queue := queues[product]; len(queue) > 0 {
+				order = append(order, queue[0])
+				queues[product] = queue[1:]
+			}
+		}
+	}
+	return order
+}
+
+// Dir returns the directory into which the object is exported, e.g. root/property/example.com, or root/<dir> if the
+// object has its directory set, or the directory given by the output template, if it is set
+func Dir(root string, object manifest.Object) string {
+	if tools.OutputTemplate != "" {
+		dir, err := templates.OutputDir(tools.OutputTemplate, templates.NewOutputTarget(object.Command, object.Name))
+		if err == nil {
+			return filepath.Join(root, filepath.FromSlash(dir))
+		}
+	}
+	if object.Dir != "" {
+		segments := strings.Split(object.Dir, "/")
+		for i, segment := range segments {
+			segments[i] = nonAlphanumeric.ReplaceAllString(segment, "_")
+		}
+		return filepath.Join(append([]string{root}, segments...)...)
+	}
+	return filepath.Join(root, nonAlphanumeric.ReplaceAllString(object.Product, "_"), nonAlphanumeric.ReplaceAllString(object.Name, "_"))
+}
+
+// IsNested returns true if the command runs as a part of a batch export, e.g. to skip steps which apply to the whole run
+func IsNested(ctx context.Context) bool {
+	nested, _ := ctx.Value(nestedCtx).(bool)
+	return nested
+}
+
+// export runs the command of a single object with its output redirected into dir, tfworkpath given in object arguments takes precedence
+func export(ctx context.Context, c *cli.Context, object manifest.Object, dir string) error {
+	cmd := c.App.Command(object.Command)
+	if cmd == nil {
+		return fmt.Errorf("%w: '%s'", ErrUnknownCommand, object.Command)
+	}
+	if mu, ok := sequential[cmd.Name]; ok {
+		mu.Lock()
+		defer mu.Unlock()
+	}
+	if templates.IsDirSink(templates.GetSink(ctx)) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("%w: %s", ErrCreatingDirectory, err)
+		}
+	}
+
+	set := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	for _, f := range cmd.Flags {
+		// slice flags parse into the value held by the flag definition, which must not be shared by exports running in parallel
+		if slice, ok := f.(*cli.StringSliceFlag); ok {
+			copied := *slice
+			copied.Value = cli.NewStringSlice()
+			if slice.Value != nil {
+				copied.Value = cli.NewStringSlice(slice.Value.Value()...)
+			}
+			f = &copied
+		}
+		if err := f.Apply(set); err != nil {
+			return err
+		}
+	}
+	if err := set.Parse(append([]string{"--tfworkpath", dir}, object.Args...)); err != nil {
+		return err
+	}
+
+	cmdCtx := cli.NewContext(c.App, set, c)
+	cmdCtx.Context = ctx
+	cmdCtx.Command = cmd
+	return cmd.Action(cmdCtx)
+}
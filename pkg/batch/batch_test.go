@@ -0,0 +1,272 @@
+package batch
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func newContext(action cli.ActionFunc) (*cli.Context, *warnings.Collector) {
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{
+		{
+			Name:   "export-test",
+			Action: action,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "tfworkpath"},
+				&cli.StringSliceFlag{Name: "tag"},
+			},
+		},
+		{
+			Name: "export-fail",
+			Action: func(*cli.Context) error {
+				return cli.Exit("oops", exitcode.API)
+			},
+			Flags: []cli.Flag{&cli.StringFlag{Name: "tfworkpath"}},
+		},
+		{
+			Name: "export-unsupported",
+			Action: func(*cli.Context) error {
+				return cli.Exit("cloudlet type not supported", exitcode.Unsupported)
+			},
+			Flags: []cli.Flag{&cli.StringFlag{Name: "tfworkpath"}},
+		},
+	}
+	collector := warnings.NewCollector()
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.Bool("continue-on-unsupported", false, "")
+	c := cli.NewContext(app, set, nil)
+	c.Context = terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+	c.Context = warnings.WithCollector(c.Context, collector)
+	return c, collector
+}
+
+// writeTags writes tags given to the command into tags.txt in its work path
+func writeTags(c *cli.Context) error {
+	content := strings.Join(c.StringSlice("tag"), ",")
+	if IsNested(c.Context) {
+		content += " nested"
+	}
+	return os.WriteFile(filepath.Join(c.String("tfworkpath"), "tags.txt"), []byte(content), 0644)
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		objects               []manifest.Object
+		continueOnUnsupported bool
+		expected              map[string]string
+		expectedWarnings      int
+		withExitCode          int
+	}{
+		"objects exported into own directories": {
+			objects: []manifest.Object{
+				{Product: "property", Name: "example.com", Command: "export-test", Args: []string{"--tag", "a", "--tag", "b", "example.com"}},
+				{Product: "property", Name: "other site", Command: "export-test", Args: []string{"--tag", "c", "other"}},
+				{Product: "dns", Name: "example.com", Command: "export-test", Args: []string{"example.com"}},
+			},
+			expected: map[string]string{
+				"property/example.com/tags.txt": "a,b nested",
+				"property/other_site/tags.txt":  "c nested",
+				"dns/example.com/tags.txt":      " nested",
+			},
+		},
+		"failed exports do not stop remaining ones": {
+			objects: []manifest.Object{
+				{Product: "property", Name: "failing", Command: "export-fail"},
+				{Product: "property", Name: "example.com", Command: "export-test", Args: []string{"example.com"}},
+				{Product: "property", Name: "unknown", Command: "export-unknown"},
+			},
+			expected: map[string]string{
+				"property/example.com/tags.txt": " nested",
+			},
+			expectedWarnings: 2,
+			withExitCode:     exitcode.API,
+		},
+		"unsupported objects fail the export": {
+			objects: []manifest.Object{
+				{Product: "cloudlets", Name: "mobile", Command: "export-unsupported"},
+				{Product: "cloudlets", Name: "redirects", Command: "export-test", Args: []string{"redirects"}},
+			},
+			expected: map[string]string{
+				"cloudlets/redirects/tags.txt": " nested",
+			},
+			expectedWarnings: 1,
+			withExitCode:     exitcode.Unsupported,
+		},
+		"unsupported objects skipped": {
+			objects: []manifest.Object{
+				{Product: "cloudlets", Name: "mobile", Command: "export-unsupported"},
+				{Product: "cloudlets", Name: "redirects", Command: "export-test", Args: []string{"redirects"}},
+			},
+			continueOnUnsupported: true,
+			expected: map[string]string{
+				"cloudlets/redirects/tags.txt": " nested",
+			},
+			expectedWarnings: 1,
+		},
+		"other failures with unsupported objects skipped": {
+			objects: []manifest.Object{
+				{Product: "cloudlets", Name: "mobile", Command: "export-unsupported"},
+				{Product: "property", Name: "failing", Command: "export-fail"},
+				{Product: "cloudlets", Name: "redirects", Command: "export-test", Args: []string{"redirects"}},
+			},
+			continueOnUnsupported: true,
+			expectedWarnings:      2,
+			withExitCode:          exitcode.API,
+		},
+		"nothing exported with unsupported objects skipped": {
+			objects: []manifest.Object{
+				{Product: "cloudlets", Name: "mobile", Command: "export-unsupported"},
+			},
+			continueOnUnsupported: true,
+			expectedWarnings:      1,
+			withExitCode:          exitcode.Unsupported,
+		},
+		"no objects": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, collector := newContext(writeTags)
+			if test.continueOnUnsupported {
+				require.NoError(t, c.Set("continue-on-unsupported", "true"))
+			}
+			dir := t.TempDir()
+
+			err := Run(c, test.objects, dir)
+			assert.Equal(t, test.withExitCode, exitcode.Of(err))
+			assert.Len(t, collector.Warnings(), test.expectedWarnings)
+			for file, expected := range test.expected {
+				content, err := os.ReadFile(filepath.Join(dir, file))
+				require.NoError(t, err)
+				assert.Equal(t, expected, string(content))
+			}
+		})
+	}
+}
+
+func TestPrintSkipped(t *testing.T) {
+	objects := []manifest.Object{
+		{Product: "cloudlets", Name: "mobile"},
+		{Product: "cloudlets", Name: "redirects"},
+	}
+	unsupported := cli.Exit("cloudlet type not supported", exitcode.Unsupported)
+	tests := map[string]struct {
+		skipped []int
+		init    func(*terminal.Mock)
+	}{
+		"nothing skipped": {
+			init: func(*terminal.Mock) {},
+		},
+		"skipped objects": {
+			skipped: []int{0},
+			init: func(term *terminal.Mock) {
+				term.On("Printf", "Skipped %d unsupported objects:\n", []interface{}{1}).Once()
+				term.On("Printf", "  %s %s: %s\n", []interface{}{"cloudlets", "mobile", unsupported}).Once()
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			term := &terminal.Mock{}
+			test.init(term)
+
+			printSkipped(terminal.Context(context.Background(), term), objects, []error{unsupported, nil}, test.skipped)
+			term.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRunSequentialCommands(t *testing.T) {
+	var running, maxRunning int32
+	c, _ := newContext(func(*cli.Context) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		return nil
+	})
+	sequential["export-test"] = new(sync.Mutex)
+	defer delete(sequential, "export-test")
+
+	var objects []manifest.Object
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		objects = append(objects, manifest.Object{Product: "dns", Name: name, Command: "export-test"})
+	}
+	require.NoError(t, Run(c, objects, t.TempDir()))
+	assert.Equal(t, int32(1), maxRunning)
+}
+
+func TestDir(t *testing.T) {
+	assert.Equal(t, filepath.Join("out", "cloudlets", "my_policy_v2"), Dir("out", manifest.Object{Product: "cloudlets", Name: "my policy/v2"}))
+	assert.Equal(t, filepath.Join("out", "dns", "example.com"), Dir("out", manifest.Object{Product: "dns", Name: "example.com"}))
+	assert.Equal(t, filepath.Join("out", "my_policy"), Dir("out", manifest.Object{Product: "cloudlets", Name: "my policy/v2", Dir: "my policy"}))
+	assert.Equal(t, filepath.Join("out", "1001", "my_policy"), Dir("out", manifest.Object{Product: "cloudlets", Name: "my policy", Dir: "1001/my policy"}))
+}
+
+func TestSchedule(t *testing.T) {
+	tests := map[string]struct {
+		products []string
+		expected []int
+	}{
+		"no objects": {
+			expected: []int{},
+		},
+		"single product": {
+			products: []string{"property", "property", "property"},
+			expected: []int{0, 1, 2},
+		},
+		"products take turns": {
+			products: []string{"property", "property", "property", "dns", "cloudlets", "dns"},
+			expected: []int{0, 3, 4, 1, 5, 2},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			objects := make([]manifest.Object, 0, len(test.products))
+			for _, product := range test.products {
+				objects = append(objects, manifest.Object{Product: product})
+			}
+			assert.Equal(t, test.expected, schedule(objects))
+		})
+	}
+}
+
+func TestRunFilesystemCommandsWithOtherSink(t *testing.T) {
+	var exported int32
+	c, _ := newContext(func(*cli.Context) error {
+		atomic.AddInt32(&exported, 1)
+		return nil
+	})
+	c.Context = templates.WithSink(c.Context, templates.NewMemorySink())
+	FilesystemCommands["export-test"] = true
+	defer delete(FilesystemCommands, "export-test")
+
+	objects := []manifest.Object{
+		{Product: "dns", Name: "example.com", Command: "export-test"},
+		{Product: "property", Name: "example.com", Command: "export-fail"},
+	}
+	err := Run(c, objects, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "'export-test' of dns example.com writes only into the filesystem")
+	assert.Equal(t, exitcode.General, exitcode.Of(err))
+	assert.Zero(t, atomic.LoadInt32(&exported))
+}
@@ -0,0 +1,71 @@
+package batch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// memo keeps results of calls shared by exports of a batch run, e.g. listings of all objects of the account, which
+	// every export would otherwise repeat; results are keyed by the section of the credentials and the account used
+	// by the run
+	memo struct {
+		scope   string
+		mu      sync.Mutex
+		entries map[string]*memoEntry
+	}
+
+	// memoEntry is a result of a single call, the lock is held while the call runs, so that concurrent exports wait
+	// for its result instead of repeating it
+	memoEntry struct {
+		mu    sync.Mutex
+		done  bool
+		value interface{}
+	}
+)
+
+var memoCtx ctxType = "memo"
+
+// withMemo returns the context with a memo of exports run by the command, a memo of the batch run in which the command
+// is nested is kept
+func withMemo(ctx context.Context, c *cli.Context) context.Context {
+	if _, ok := ctx.Value(memoCtx).(*memo); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, memoCtx, &memo{
+		scope:   edgegrid.GetEdgercSection(c) + "/" + c.String("accountkey"),
+		entries: make(map[string]*memoEntry),
+	})
+}
+
+// Memo returns the result of fn stored under the key by an export of the same batch run, fn is called and its result
+// stored when there is none. Failed calls are not stored, so that the next export calls fn again. Outside of batch
+// runs fn is called every time
+func Memo(ctx context.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	m, ok := ctx.Value(memoCtx).(*memo)
+	if !ok {
+		return fn()
+	}
+	m.mu.Lock()
+	entry, ok := m.entries[m.scope+"/"+key]
+	if !ok {
+		entry = &memoEntry{}
+		m.entries[m.scope+"/"+key] = entry
+	}
+	m.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.done {
+		return entry.value, nil
+	}
+	value, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	entry.value, entry.done = value, true
+	return value, nil
+}
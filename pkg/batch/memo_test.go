@@ -0,0 +1,78 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"sync/atomic"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestMemo(t *testing.T) {
+	ctx := withMemo(context.Background(), cli.NewContext(cli.NewApp(), flag.NewFlagSet("test", flag.ContinueOnError), nil))
+	calls := 0
+	list := func() (interface{}, error) {
+		calls++
+		return []string{"a", "b"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		value, err := Memo(ctx, "policies", list)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, value)
+	}
+	assert.Equal(t, 1, calls)
+
+	_, err := Memo(ctx, "versions", list)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestMemoFailedCall(t *testing.T) {
+	ctx := withMemo(context.Background(), cli.NewContext(cli.NewApp(), flag.NewFlagSet("test", flag.ContinueOnError), nil))
+	_, err := Memo(ctx, "policies", func() (interface{}, error) {
+		return nil, errors.New("oops")
+	})
+	assert.EqualError(t, err, "oops")
+
+	value, err := Memo(ctx, "policies", func() (interface{}, error) {
+		return "listed", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "listed", value)
+}
+
+func TestMemoOutsideOfBatch(t *testing.T) {
+	calls := 0
+	for i := 0; i < 2; i++ {
+		_, err := Memo(context.Background(), "policies", func() (interface{}, error) {
+			calls++
+			return nil, nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestRunSharesMemo(t *testing.T) {
+	var calls int32
+	c, _ := newContext(func(c *cli.Context) error {
+		_, err := Memo(c.Context, "policies", func() (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, nil
+		})
+		return err
+	})
+
+	var objects []manifest.Object
+	for _, name := range []string{"a", "b", "c", "d"} {
+		objects = append(objects, manifest.Object{Product: "cloudlets", Name: name, Command: "export-test"})
+	}
+	require.NoError(t, Run(c, objects, t.TempDir()))
+	assert.Equal(t, int32(1), calls)
+}
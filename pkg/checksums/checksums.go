@@ -0,0 +1,163 @@
+// Package checksums contains code for writing checksums of exported configuration and signing them, so that exports
+// can be verified not to be modified between their review and apply
+package checksums
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+)
+
+const (
+	// File is the name of the file with SHA-256 checksums of exported files, in the format of sha256sum
+	File = "SHA256SUMS"
+	// SignatureFile is the name of the minisign signature of File
+	SignatureFile = File + ".minisig"
+
+	// minisign secret key: signature algorithm, KDF algorithm, checksum algorithm, KDF salt, ops and mem limits, key ID,
+	// secret key and its checksum
+	secretKeySize = 2 + 2 + 2 + 32 + 8 + 8 + 8 + ed25519.PrivateKeySize + 32
+	keyIDOffset   = 2 + 2 + 2 + 32 + 8 + 8
+)
+
+var (
+	// ErrWritingChecksums is returned when checksums or their signature cannot be written
+	ErrWritingChecksums = exitcode.New(exitcode.IO, "writing checksums")
+	// ErrInvalidKey is returned when the signing key cannot be read or is not an unencrypted minisign secret key
+	ErrInvalidKey = exitcode.New(exitcode.General, "invalid signing key")
+)
+
+// Write writes SHA-256 checksums of all files in dir into File in dir, sorted by their paths relative to dir. Files in
+// the workspace metadata directory, previously written checksums and their signature, and files at skipped paths,
+// e.g. an archive written into dir, are not covered.
+func Write(dir string, skip ...string) error {
+	skipped := make(map[string]bool)
+	for _, path := range append([]string{filepath.Join(dir, File), filepath.Join(dir, SignatureFile)}, skip...) {
+		if abs, err := filepath.Abs(path); err == nil {
+			skipped[abs] = true
+		}
+	}
+
+	sums := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == workspace.MetadataDir {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if abs, err := filepath.Abs(path); err != nil || skipped[abs] {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum, err := fileSum(path)
+		if err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(name)] = sum
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWritingChecksums, err)
+	}
+
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var content bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&content, "%s  %s\n", sums[name], name)
+	}
+	if err := os.WriteFile(filepath.Join(dir, File), content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("%w: %s", ErrWritingChecksums, err)
+	}
+	return nil
+}
+
+// Sign signs File in dir with the unencrypted minisign secret key at keyPath and writes the signature into
+// SignatureFile, it can be verified with 'minisign -Vm SHA256SUMS -p <public key>'
+func Sign(dir, keyPath string, now time.Time) error {
+	keyID, key, err := loadKey(keyPath)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(filepath.Join(dir, File))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWritingChecksums, err)
+	}
+
+	sig := ed25519.Sign(key, content)
+	signature := append(append([]byte("Ed"), keyID...), sig...)
+	trusted := fmt.Sprintf("timestamp:%d\tfile:%s", now.Unix(), File)
+	// the global signature covers the signature together with the trusted comment
+	global := ed25519.Sign(key, append(sig, trusted...))
+	out := fmt.Sprintf("untrusted comment: signature from akamai terraform\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(signature), trusted, base64.StdEncoding.EncodeToString(global))
+	if err := os.WriteFile(filepath.Join(dir, SignatureFile), []byte(out), 0644); err != nil {
+		return fmt.Errorf("%w: %s", ErrWritingChecksums, err)
+	}
+	return nil
+}
+
+// ValidateKey checks that the file at path is an unencrypted minisign secret key
+func ValidateKey(path string) error {
+	_, _, err := loadKey(path)
+	return err
+}
+
+// loadKey returns the key ID and the private key of the minisign secret key file
+func loadKey(path string) ([]byte, ed25519.PrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return nil, nil, fmt.Errorf("%w: %s is not a minisign secret key", ErrInvalidKey, path)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(key) != secretKeySize || string(key[:2]) != "Ed" {
+		return nil, nil, fmt.Errorf("%w: %s is not a minisign secret key", ErrInvalidKey, path)
+	}
+	if key[2] != 0 || key[3] != 0 {
+		return nil, nil, fmt.Errorf("%w: %s is encrypted, generate the key without a password with 'minisign -G -W'", ErrInvalidKey, path)
+	}
+	keyID := key[keyIDOffset : keyIDOffset+8]
+	return keyID, ed25519.PrivateKey(key[keyIDOffset+8 : keyIDOffset+8+ed25519.PrivateKeySize]), nil
+}
+
+func fileSum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/akamai/cli-terraform/pkg/archive"
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// archiveOutput runs the export action and packs generated configuration into a tarball, if it was requested
+func archiveOutput(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if tools.Archive == "" || batch.IsNested(ctx.Context) {
+			return action(ctx)
+		}
+		// only files written by this run are packed, so that credentials, version control data or earlier exports
+		// placed in tfworkpath do not end up in the archive
+		written := recordWrites(ctx)
+		// objects exported by export-manifest are packed together once all of them are exported
+		if err := action(ctx); err != nil {
+			return err
+		}
+		names, err := written(workPath(ctx))
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error archiving exported configuration: %s", err)), exitcode.Of(err))
+		}
+		var files []archive.File
+		if tools.ArchiveManifest != "" {
+			content, err := os.ReadFile(tools.ArchiveManifest)
+			if err != nil {
+				err = fmt.Errorf("%w: %s", archive.ErrCreatingArchive, err)
+				return cli.Exit(color.RedString(fmt.Sprintf("Error archiving exported configuration: %s", err)), exitcode.Of(err))
+			}
+			files = append(files, archive.File{Name: "meta/manifest.json", Content: content})
+		}
+		if recorder := archive.GetRecorder(ctx.Context); recorder != nil {
+			content, err := json.MarshalIndent(recorder.Responses(), "", "  ")
+			if err != nil {
+				return cli.Exit(color.RedString(fmt.Sprintf("Error archiving exported configuration: %s", err)), exitcode.General)
+			}
+			files = append(files, archive.File{Name: "meta/api-responses.json", Content: content})
+		}
+		if err := archive.Write(tools.Archive, workPath(ctx), names, files...); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error archiving exported configuration: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
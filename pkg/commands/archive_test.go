@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestArchiveOutput(t *testing.T) {
+	defer func(archive string) { tools.Archive = archive }(tools.Archive)
+	dir := t.TempDir()
+	tools.Archive = filepath.Join(dir, "out.tar.gz")
+
+	old := time.Now().Add(-time.Hour)
+	for _, file := range []string{".edgerc", "previous.tf"} {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0600))
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	set := flag.NewFlagSet("export-cloudlets-policy", flag.ContinueOnError)
+	set.String("tfworkpath", "", "")
+	require.NoError(t, set.Parse([]string{"--tfworkpath", dir}))
+	c := cli.NewContext(cli.NewApp(), set, nil)
+	c.Context = context.Background()
+
+	action := func(ctx *cli.Context) error {
+		path := filepath.Join(dir, "policy.tf")
+		if err := templates.GetSink(ctx.Context).WriteFile(path, []byte("resource {}")); err != nil {
+			return err
+		}
+		// files recorded by the sink are packed regardless of their modification time
+		return os.Chtimes(path, old, old)
+	}
+	require.NoError(t, archiveOutput(action)(c))
+
+	f, err := os.Open(tools.Archive)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"policy.tf"}, names)
+}
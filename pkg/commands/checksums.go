@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/checksums"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// writeChecksums runs the export action and writes SHA256SUMS covering all files in the work path, signed if a signing
+// key was given; the archive is packed afterwards, so that it contains the checksums as well
+func writeChecksums(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		// objects exported by export-manifest are covered together once all of them are exported
+		if (!tools.Checksums && tools.SignKey == "") || batch.IsNested(ctx.Context) {
+			return action(ctx)
+		}
+		// invalid key is reported before the export is run
+		if tools.SignKey != "" {
+			if err := checksums.ValidateKey(tools.SignKey); err != nil {
+				return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+			}
+		}
+		if err := action(ctx); err != nil {
+			return err
+		}
+		var skip []string
+		if tools.Archive != "" {
+			skip = append(skip, tools.Archive)
+		}
+		if err := checksums.Write(workPath(ctx), skip...); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error writing checksums: %s", err)), exitcode.Of(err))
+		}
+		if tools.SignKey == "" {
+			return nil
+		}
+		if err := checksums.Sign(workPath(ctx), tools.SignKey, time.Now()); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error signing checksums: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
@@ -16,16 +16,27 @@
 package commands
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/completion"
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/akamai/cli-terraform/pkg/doctor"
+	"github.com/akamai/cli-terraform/pkg/inventory"
+	"github.com/akamai/cli-terraform/pkg/lint"
 	"github.com/akamai/cli-terraform/pkg/providers/appsec"
 	"github.com/akamai/cli-terraform/pkg/providers/cloudlets"
 	"github.com/akamai/cli-terraform/pkg/providers/cps"
-	"github.com/akamai/cli-terraform/pkg/providers/dns"
 	"github.com/akamai/cli-terraform/pkg/providers/edgeworkers"
 	"github.com/akamai/cli-terraform/pkg/providers/gtm"
 	"github.com/akamai/cli-terraform/pkg/providers/iam"
 	"github.com/akamai/cli-terraform/pkg/providers/imaging"
+	"github.com/akamai/cli-terraform/pkg/providers/mtls"
 	"github.com/akamai/cli-terraform/pkg/providers/papi"
+	"github.com/akamai/cli-terraform/pkg/references"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/upgrade"
 	"github.com/akamai/cli/pkg/apphelp"
 	"github.com/akamai/cli/pkg/autocomplete"
 	"github.com/urfave/cli/v2"
@@ -41,7 +52,7 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for Domain resources",
 		Usage:       "export-domain",
 		ArgsUsage:   "<domain>",
-		Action:      validatedAction(gtm.CmdCreateDomain, requireValidWorkpath, requireNArguments(1)),
+		Action:      validatedAction(exportAction(gtm.CmdCreateDomain), requireValidWorkpath, requireNArguments(1)),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "tfworkpath",
@@ -49,7 +60,7 @@ func CommandLocator() ([]*cli.Command, error) {
 				DefaultText: "current directory",
 			},
 		},
-		BashComplete: autocomplete.Default,
+		BashComplete: completion.Objects(discovery.ProductGTM),
 	})
 
 	commands = append(commands, &cli.Command{
@@ -58,7 +69,7 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for Zone resources",
 		Usage:       "export-zone",
 		ArgsUsage:   "<zone>",
-		Action:      validatedAction(dns.CmdCreateZone, requireValidWorkpath, requireNArguments(1)),
+		Action:      validatedAction(exportZones, requireValidWorkpath, requireNArgumentsOrFlag(1, "zones-file")),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "tfworkpath",
@@ -81,6 +92,10 @@ func CommandLocator() ([]*cli.Command, error) {
 				Name:  "segmentconfig",
 				Usage: "Directive for createconfig. Group and segment records by name into separate config files.",
 			},
+			&cli.BoolFlag{
+				Name:  "explicit-records",
+				Usage: "Directive for createconfig. Write a separate resource for every recordset instead of consolidating structurally identical recordsets into resources with for_each.",
+			},
 			&cli.BoolFlag{
 				Name:  "configonly",
 				Usage: "Directive for createconfig. Create entire Terraform zone and recordsets configuration (<zone>.tf), dnsvars.tf. Saves zone config for importscript. Ignores any existing resource json file.",
@@ -93,8 +108,32 @@ func CommandLocator() ([]*cli.Command, error) {
 				Name:  "recordname",
 				Usage: "Used in resources gathering or with configonly to filter recordsets. Multiple recordname flags may be specified.",
 			},
+			&cli.StringFlag{
+				Name:  "gtm-domain",
+				Usage: "Directive for createconfig. GTM domain delegated from the zone, the zone is checked for NS and glue records of the delegation.",
+			},
+			&cli.StringFlag{
+				Name:  "gtm-subdomain",
+				Usage: "Directive for gtm-domain. Name in the zone delegated to the GTM domain. (default: name of the GTM domain)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "gtm-nameserver",
+				Usage: "Directive for gtm-domain. Name server the delegation points to, missing NS records are generated from them. Multiple gtm-nameserver flags may be specified.",
+			},
+			&cli.StringSliceFlag{
+				Name:  "record-types",
+				Usage: "Comma separated list of types of recordsets to export, e.g. A,AAAA,CNAME, other types are left out of the configuration and import script.",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude-record-types",
+				Usage: "Comma separated list of types of recordsets left out of the configuration and import script, e.g. TXT for dynamic ACME challenges.",
+			},
+			&cli.StringFlag{
+				Name:  "zones-file",
+				Usage: "File listing zones to export instead of <zone>, one per line, each into its own subdirectory of tfworkpath; '-' reads the list from standard input.",
+			},
 		},
-		BashComplete: autocomplete.Default,
+		BashComplete: completion.Objects(discovery.ProductDNS),
 	})
 
 	commands = append(commands, &cli.Command{
@@ -103,13 +142,17 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for Application Security resources",
 		Usage:       "export-appsec",
 		ArgsUsage:   "<security configuration name>",
-		Action:      validatedAction(appsec.CmdCreateAppsec, requireValidWorkpath, requireNArguments(1)),
+		Action:      validatedAction(exportAction(appsec.CmdCreateAppsec), requireValidWorkpath, requireNArguments(1)),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "tfworkpath",
 				Usage:       "Directory used to store files created when running commands.",
 				DefaultText: "current directory",
 			},
+			&cli.StringFlag{
+				Name:  "clone-as",
+				Usage: "Additionally export a parameterized copy of the configuration, without import script, for creating a new configuration with the given name into a sibling directory named after it.",
+			},
 		},
 		BashComplete: autocomplete.Default,
 	})
@@ -120,20 +163,85 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for Property resources",
 		Usage:       "export-property",
 		ArgsUsage:   "<property name>",
-		Action:      validatedAction(papi.CmdCreateProperty, requireValidWorkpath, requireNArguments(1)),
+		Action:      validatedAction(exportAction(papi.CmdCreateProperty), requireValidWorkpath, requireNArguments(1)),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "tfworkpath",
 				Usage:       "Directory used to store files created when running commands.",
 				DefaultText: "current directory",
 			},
+			&cli.BoolFlag{
+				Name:  "read-only",
+				Usage: "Export the property as a data source and locals for referencing it without managing it, no import script is generated.",
+			},
+			&cli.BoolFlag{
+				Name:  "bootstrap",
+				Usage: "Export the property as akamai_property_bootstrap resource referenced by akamai_property resource managing its versions and rules.",
+			},
 			&cli.StringFlag{
 				Name:        "version",
 				Usage:       "Property version to import",
 				DefaultText: "LATEST",
 			},
+			&cli.BoolFlag{
+				Name:  "cert-status",
+				Usage: "Annotate hostnames using CPS managed certificates with enrollment IDs and certificate status.",
+			},
+			&cli.BoolFlag{
+				Name:  "export-certificates",
+				Usage: "Annotate hostnames with certificate status and export their CPS enrollments into 'cps-<enrollment id>' directories within the work path.",
+			},
+			&cli.BoolFlag{
+				Name:  "reference-existing",
+				Usage: "Reference existing edge hostnames and CP codes used by the property instead of managing them, CP codes are looked up with akamai_cp_code data sources.",
+			},
+			&cli.StringFlag{
+				Name:  "compare-version",
+				Usage: "Property version, or LATEST, to which rules of the exported version are compared. Changed rules, behaviors and criteria are listed in rules-diff.md.",
+			},
+			&cli.StringFlag{
+				Name:  "environments",
+				Usage: "Comma separated environments, e.g. staging,prod, for which '<environment>.tfvars' files with the version active on their network and its hostnames are generated.",
+			},
+		},
+		BashComplete: completion.Objects(discovery.ProductProperty),
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "validate-property-rules",
+		Description: "Validates behaviors and criteria of property rules JSON against the bundled schema of the rule format without calling the API",
+		Usage:       "validate-property-rules",
+		ArgsUsage:   "<rules.json>",
+		Action:      validatedAction(papi.CmdValidatePropertyRules, requireNArguments(1)),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "rule-format",
+				Usage:       "Rule format, e.g. v2023-01-05, against which the rules are validated.",
+				DefaultText: "rule format of the file",
+			},
+		},
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "export-edgehostnames",
+		Description: "Generates Terraform configuration for all edge hostnames of the contract and group, independently of properties using them",
+		Usage:       "export-edgehostnames",
+		Action:      validatedAction(exportAction(papi.CmdCreateEdgeHostnames), requireValidWorkpath, requireNArguments(0), requireFlags("contract", "group")),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "tfworkpath",
+				Usage:       "Directory used to store files created when running commands.",
+				DefaultText: "current directory",
+			},
+			&cli.StringFlag{
+				Name:  "contract",
+				Usage: "ID or contract type name of the contract of exported edge hostnames, e.g. ctr_1-AB123.",
+			},
+			&cli.StringFlag{
+				Name:  "group",
+				Usage: "ID or name of the group of exported edge hostnames, e.g. grp_12345.",
+			},
 		},
-		BashComplete: autocomplete.Default,
 	})
 
 	commands = append(commands, &cli.Command{
@@ -142,15 +250,143 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for Cloudlets Policy resources",
 		Usage:       "export-cloudlets-policy",
 		ArgsUsage:   "<policy_name>",
-		Action:      validatedAction(cloudlets.CmdCreatePolicy, requireValidWorkpath, requireNArguments(1)),
+		Action:      validatedAction(exportCloudletsPolicies, requireValidWorkpath, requireNArgumentsOrFlag(1, "policy-id", "all")),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "tfworkpath",
 				Usage:       "Directory used to store files created when running commands.",
 				DefaultText: "current directory",
 			},
+			&cli.BoolFlag{
+				Name:  "read-only",
+				Usage: "Export the policy as a data source and locals for referencing it without managing it, no import script is generated.",
+			},
+			&cli.BoolFlag{
+				Name:  "as-data",
+				Usage: "Export the policy like read-only with outputs.tf exposing its IDs, versions and match rules to other modules.",
+			},
+			&cli.BoolFlag{
+				Name:  "match-rules-module",
+				Usage: "Export match rules as locals consumed by a generic module with dynamic blocks instead of fully expanded HCL.",
+			},
+			&cli.BoolFlag{
+				Name:  "match-rules-json",
+				Usage: "Export match rules into match-rules.json loaded with jsondecode instead of fully expanded HCL.",
+			},
+			&cli.BoolFlag{
+				Name:  "split-match-rules",
+				Usage: "Export every match rule into its own JSON file in rules directory, loaded with jsondecode in the order of the policy.",
+			},
+			&cli.BoolFlag{
+				Name:  "as-module",
+				Usage: "Export the policy as a module parameterized with its group, activation network and properties, instantiated with values of terraform.tfvars.",
+			},
+			&cli.BoolFlag{
+				Name:  "migrate-to-shared",
+				Usage: "Add the shape of the shared policy resource with a migration note and commands importing it to legacy ER, AS and FR policies.",
+			},
+			&cli.BoolFlag{
+				Name:  "tfvars",
+				Usage: "Write terraform.tfvars with values of variables used by the export, such as the section of the credentials file, and terraform.tfvars.example.",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-activations",
+				Usage: "Export the policy without activation resources of the policy and its load balancers, which are managed outside of Terraform.",
+			},
+			&cli.BoolFlag{
+				Name:  "import-blocks",
+				Usage: "Write import blocks of Terraform 1.5 into imports.tf instead of import.sh.",
+			},
+			&cli.IntFlag{
+				Name:  "version-history",
+				Usage: "Annotate the policy with descriptions and dates of the given number of its latest versions as comments.",
+			},
+			&cli.BoolFlag{
+				Name:  "full-history",
+				Usage: "Write match rules of every version of the policy into versions/<version>.json and annotate all versions as comments.",
+			},
+			&cli.StringFlag{
+				Name:  "resource-prefix",
+				Usage: "Prefix of names of exported resources, data sources, local values and modules, e.g. 'edge_'.",
+			},
+			&cli.StringFlag{
+				Name:  "resource-naming",
+				Usage: "Strategy naming resources of the policy: 'static' (default), 'snake_case' of the policy name or 'hash' appending a hash of the name.",
+			},
+			&cli.Int64Flag{
+				Name:  "policy-id",
+				Usage: "ID of the exported policy, given instead of its name, which skips listing all policies to find it.",
+			},
+			&cli.Int64Flag{
+				Name:        "version",
+				Usage:       "Version of the policy to export, e.g. the version active on production network.",
+				DefaultText: "latest version",
+			},
+			&cli.StringFlag{
+				Name:  "active-version",
+				Usage: "Export the version of the policy active on the given network, 'staging' or 'production', instead of its latest version.",
+			},
+			&cli.Int64Flag{
+				Name:  "group-id",
+				Usage: "ID of the group whose policies are exported with --all, instead of all policies of the account.",
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Export every policy of the account, or of the group given with --group-id, into its own subdirectory of tfworkpath.",
+			},
+			&cli.BoolFlag{
+				Name:  "continue-on-unsupported",
+				Usage: "Skip policies which are not supported with --all, listing them in a report, and fail only if no policy was exported.",
+			},
+		},
+		BashComplete: completion.Objects(discovery.ProductCloudlets),
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "export-load-balancer",
+		Aliases:     []string{"create-load-balancer"},
+		Description: "Generates Terraform configuration for Cloudlets Application Load Balancer resources",
+		Usage:       "export-load-balancer",
+		ArgsUsage:   "<origin_id>",
+		Action:      validatedAction(exportAction(cloudlets.CmdCreateLoadBalancer), requireValidWorkpath, requireNArguments(1)),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "tfworkpath",
+				Usage:       "Directory used to store files created when running commands.",
+				DefaultText: "current directory",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-activations",
+				Usage: "Export the load balancer without its activation resources, which are managed outside of Terraform.",
+			},
+			&cli.BoolFlag{
+				Name:  "import-blocks",
+				Usage: "Write import blocks of Terraform 1.5 into imports.tf instead of import.sh.",
+			},
+			&cli.StringFlag{
+				Name:  "resource-prefix",
+				Usage: "Prefix of names of exported resources, e.g. 'edge_'.",
+			},
+		},
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "validate-rules",
+		Description: "Validates cloudlets policy match rules JSON against the schema of the cloudlet type before it is applied",
+		Usage:       "validate-rules",
+		ArgsUsage:   "<rules.json>",
+		Action:      validatedAction(cloudlets.CmdValidateRules, requireNArguments(1)),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "cloudlet-type",
+				Usage:       "Cloudlet type of the policy, e.g. ER, against which the rules are validated.",
+				DefaultText: "type of the first rule",
+			},
+			&cli.StringFlag{
+				Name:  "policy",
+				Usage: "Name of the policy whose cloudlet type the rules are validated against, requires credentials.",
+			},
 		},
-		BashComplete: autocomplete.Default,
 	})
 
 	commands = append(commands, &cli.Command{
@@ -159,7 +395,7 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for EdgeKV resources",
 		Usage:       "export-edgekv",
 		ArgsUsage:   "<namespace_name> <network>",
-		Action:      validatedAction(edgeworkers.CmdCreateEdgeKV, requireValidWorkpath, requireNArguments(2)),
+		Action:      validatedAction(exportAction(edgeworkers.CmdCreateEdgeKV), requireValidWorkpath, requireNArguments(2)),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "tfworkpath",
@@ -176,7 +412,7 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for EdgeWorker resources",
 		Usage:       "export-edgeworker",
 		ArgsUsage:   "<edgeworker_id>",
-		Action:      validatedAction(edgeworkers.CmdCreateEdgeWorker, requireValidWorkpath, requireNArguments(1)),
+		Action:      validatedAction(exportAction(edgeworkers.CmdCreateEdgeWorker), requireValidWorkpath, requireNArguments(1)),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "bundlepath",
@@ -187,6 +423,10 @@ func CommandLocator() ([]*cli.Command, error) {
 				Usage:       "Directory used to store files created when running commands.",
 				DefaultText: "current directory",
 			},
+			&cli.BoolFlag{
+				Name:  "read-only",
+				Usage: "Export the EdgeWorker as a data source and locals for referencing it without managing it, no import script is generated.",
+			},
 		},
 		BashComplete: autocomplete.Default,
 	})
@@ -202,25 +442,25 @@ func CommandLocator() ([]*cli.Command, error) {
 			{
 				Name:        "all",
 				Description: "Exports all available Terraform Users, Groups and Roles",
-				Action:      validatedAction(iam.CmdCreateIAMAll, requireValidWorkpath),
+				Action:      validatedAction(exportAction(iam.CmdCreateIAMAll), requireValidWorkpath),
 			},
 			{
 				Name:        "group",
 				Description: "Exports Terraform Group resource with relevant users and roles resources",
 				ArgsUsage:   "<group_id>",
-				Action:      validatedAction(iam.CmdCreateIAMGroup, requireValidWorkpath, requireNArguments(1)),
+				Action:      validatedAction(exportAction(iam.CmdCreateIAMGroup), requireValidWorkpath, requireNArguments(1)),
 			},
 			{
 				Name:        "role",
 				Description: "Exports Terraform Role resource with relevant users and groups resources",
 				ArgsUsage:   "<role_id>",
-				Action:      validatedAction(iam.CmdCreateIAMRole, requireValidWorkpath, requireNArguments(1)),
+				Action:      validatedAction(exportAction(iam.CmdCreateIAMRole), requireValidWorkpath, requireNArguments(1)),
 			},
 			{
 				Name:        "user",
 				Description: "Exports Terraform User resource with relevant groups and roles resources",
 				ArgsUsage:   "<user_email>",
-				Action:      validatedAction(iam.CmdCreateIAMUser, requireValidWorkpath, requireNArguments(1)),
+				Action:      validatedAction(exportAction(iam.CmdCreateIAMUser), requireValidWorkpath, requireNArguments(1)),
 			},
 		},
 		Flags: []cli.Flag{
@@ -239,7 +479,7 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for Image and Video Manager resources",
 		Usage:       "export-imaging",
 		ArgsUsage:   "<contract_id> <policy_set_id>",
-		Action:      validatedAction(imaging.CmdCreateImaging, requireValidWorkpath, requireNArguments(2)),
+		Action:      validatedAction(exportAction(imaging.CmdCreateImaging), requireValidWorkpath, requireNArguments(2)),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "policy-json-dir",
@@ -255,6 +495,10 @@ func CommandLocator() ([]*cli.Command, error) {
 				Usage:       "Generate content of the policy using HCL instead of JSON file",
 				Destination: &tools.Schema,
 			},
+			&cli.BoolFlag{
+				Name:  "previous-version",
+				Usage: "Export previous version of each policy into JSON file with variable rolling policies back to it",
+			},
 		},
 		BashComplete: autocomplete.Default,
 	})
@@ -265,17 +509,194 @@ func CommandLocator() ([]*cli.Command, error) {
 		Description: "Generates Terraform configuration for CPS (Certificate Provisioning System) resources",
 		Usage:       "export-cps",
 		ArgsUsage:   "<enrollment_id> <contract_id>",
-		Action:      validatedAction(cps.CmdCreateCPS, requireValidWorkpath, requireNArguments(2)),
+		Action:      validatedAction(exportAction(cps.CmdCreateCPS), requireValidWorkpath, requireNArguments(2)),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "tfworkpath",
+				Usage:       "Directory used to store files created when running commands.",
+				DefaultText: "current directory",
+			},
+			&cli.BoolFlag{
+				Name:  "read-only",
+				Usage: "Export the enrollment as a data source and locals for referencing it without managing it, no import script is generated.",
+			},
+		},
+		BashComplete: autocomplete.Default,
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "export-mtls-truststore",
+		Aliases:     []string{"create-mtls-truststore"},
+		Description: "Generates Terraform configuration for mTLS Edge TrustStore CA set resources",
+		Usage:       "export-mtls-truststore",
+		ArgsUsage:   "<ca_set_id>",
+		Action:      validatedAction(exportAction(mtls.CmdCreateCASet), requireValidWorkpath, requireNArguments(1)),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "tfworkpath",
+				Usage:       "Directory used to store files created when running commands.",
+				DefaultText: "current directory",
+			},
+		},
+		BashComplete: autocomplete.Default,
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "discover",
+		Description: "Discovers exportable objects on the account and writes them to an export manifest",
+		Usage:       "discover",
+		Action:      validatedAction(enforceStrict(discovery.CmdDiscover), requireValidWorkpath),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "tfworkpath",
+				Usage:       "Directory used to store files created when running commands.",
+				DefaultText: "current directory",
+			},
+			&cli.StringSliceFlag{
+				Name:        "products",
+				Usage:       "Comma separated list of products to discover. Supported products: " + strings.Join(discovery.Products(), ", "),
+				DefaultText: "all products",
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Usage:       "Path of the generated manifest file.",
+				DefaultText: "manifest.json in tfworkpath",
+			},
+		},
+		BashComplete: autocomplete.Default,
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "export-manifest",
+		Description: "Exports all objects selected in the export manifest in parallel, each into its own subdirectory",
+		Usage:       "export-manifest",
+		ArgsUsage:   "<manifest.json>",
+		Action:      validatedAction(selectSink(archiveOutput(writeChecksums(enforceStrict(inventoryOrigins(batch.CmdExportManifest))))), requireValidWorkpath, requireNArguments(1)),
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "tfworkpath",
 				Usage:       "Directory used to store files created when running commands.",
 				DefaultText: "current directory",
 			},
+			&cli.BoolFlag{
+				Name:  "continue-on-unsupported",
+				Usage: "Skip objects which are not supported, listing them in a report, and fail only if no object was exported.",
+			},
 		},
 		BashComplete: autocomplete.Default,
 	})
 
+	commands = append(commands, &cli.Command{
+		Name:        "inventory",
+		Description: "Lists exportable objects on the account with their sizes, without generating Terraform configuration",
+		Usage:       "inventory",
+		Action:      validatedAction(inventory.CmdInventory),
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "products",
+				Usage:       "Comma separated list of products to list. Supported products: " + strings.Join(discovery.Products(), ", "),
+				DefaultText: "all products",
+			},
+			&cli.StringFlag{
+				Name:        "format",
+				Usage:       "Format of the report, either 'table', 'json' or 'csv'.",
+				DefaultText: "table",
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Usage:       "Path of the report file.",
+				DefaultText: "standard output",
+			},
+		},
+		BashComplete: autocomplete.Default,
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "doctor",
+		Description: "Checks credentials and permissions to call APIs of products used by the export command, or of all products, before running the export",
+		Usage:       "doctor",
+		ArgsUsage:   "[export command]",
+		Action:      validatedAction(doctor.CmdDoctor),
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "products",
+				Usage:       "Comma separated list of products to check. Supported products: " + strings.Join(doctor.Products(), ", "),
+				DefaultText: "all products",
+			},
+		},
+		BashComplete: autocomplete.Default,
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "sections",
+		Description: "Lists sections of the .edgerc file with product APIs which their credentials can call",
+		Usage:       "sections",
+		Action:      validatedAction(doctor.CmdSections, requireNArguments(0)),
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "products",
+				Usage:       "Comma separated list of products to check. Supported products: " + strings.Join(doctor.Products(), ", "),
+				DefaultText: "all products",
+			},
+		},
+		BashComplete: autocomplete.Default,
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "resolve-references",
+		Description: "Replaces literal identifiers of objects exported into the directory or its subdirectories with Terraform references",
+		Usage:       "resolve-references",
+		ArgsUsage:   "<directory>",
+		Action:      validatedAction(references.CmdResolveReferences, requireNArguments(1)),
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "upgrade-workspace",
+		Description: "Rewrites configuration generated by older versions of the CLI into the directory or its subdirectories to the current file structure and resource schemas",
+		Usage:       "upgrade-workspace",
+		ArgsUsage:   "<directory>",
+		Action:      validatedAction(upgrade.CmdUpgradeWorkspace, requireNArguments(1)),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "List changes of the upgrade without writing them.",
+			},
+		},
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "lint",
+		Description: "Checks configuration exported into the directory or its subdirectories for drift-prone patterns and suggests fixes",
+		Usage:       "lint",
+		ArgsUsage:   "<directory>",
+		Action:      validatedAction(lint.CmdLint, requireNArguments(1)),
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "ignore",
+				Usage: "Comma separated list of rules whose findings are not reported. Supported rules: " + strings.Join(lint.Rules(), ", "),
+			},
+		},
+	})
+
+	commands = append(commands, &cli.Command{
+		Name:        "completion",
+		Description: "Generates shell completion script, which also completes names of properties, zones, domains and cloudlets policies",
+		Usage:       "completion",
+		ArgsUsage:   "<" + strings.Join(completion.Shells(), "|") + ">",
+		Action:      validatedAction(completion.CmdCompletion, requireNArguments(1)),
+		BashComplete: func(c *cli.Context) {
+			for _, shell := range completion.Shells() {
+				fmt.Fprintln(c.App.Writer, shell)
+			}
+		},
+	})
+
+	exporters, err := exporterCommands(commands)
+	if err != nil {
+		return nil, err
+	}
+	commands = append(commands, exporters...)
+
 	commands = append(commands, &cli.Command{
 		Name:               "list",
 		Description:        "List commands",
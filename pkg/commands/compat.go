@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/akamai/cli-terraform/pkg/compat"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// checkProviderCompat runs the export action and checks generated configuration against the target provider version, if it was given
+func checkProviderCompat(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if tools.ProviderVersion == "" {
+			return action(ctx)
+		}
+		// invalid version is reported before the export is run
+		if _, err := compat.ParseVersion(tools.ProviderVersion); err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		// only rendered outputs are checked, not configuration left in tfworkpath by earlier exports
+		written := recordWrites(ctx)
+		if err := action(ctx); err != nil {
+			return err
+		}
+		names, err := written(workPath(ctx))
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error checking provider compatibility: %s", err)), exitcode.Of(err))
+		}
+		files := make([]string, 0, len(names))
+		for _, name := range names {
+			files = append(files, filepath.Join(workPath(ctx), name))
+		}
+		if err := compat.Check(ctx.Context, files, tools.ProviderVersion); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error checking provider compatibility: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/providers/dns"
+	"github.com/urfave/cli/v2"
+)
+
+// exportAction wraps the export action with optional steps run on the generated configuration
+func exportAction(action cli.ActionFunc) cli.ActionFunc {
+	return selectSink(applyOutputTemplate(reportStatus(action, archiveOutput(writeChecksums(enforceStrict(inventoryOrigins(reconcileOutput(selectOnly(checkProviderCompat(scaffoldTerragrunt(generateReadme(action))))))))))))
+}
+
+// exportCloudletsPolicies exports all policies of the group like export-manifest exports its objects when all flag is
+// set, otherwise the single policy is exported
+func exportCloudletsPolicies(ctx *cli.Context) error {
+	if ctx.Bool("all") {
+		return selectSink(archiveOutput(writeChecksums(enforceStrict(inventoryOrigins(cloudlets.CmdCreateGroupPolicies)))))(ctx)
+	}
+	return exportAction(cloudlets.CmdCreatePolicy)(ctx)
+}
+
+// exportZones exports zones listed in the zones file like export-manifest exports its objects when zones-file flag is
+// set, otherwise the single zone is exported
+func exportZones(ctx *cli.Context) error {
+	if ctx.IsSet("zones-file") {
+		return selectSink(archiveOutput(writeChecksums(enforceStrict(inventoryOrigins(dns.CmdCreateZones)))))(ctx)
+	}
+	return exportAction(dns.CmdCreateZone)(ctx)
+}
+
+// workPath returns the directory in which the export command writes generated configuration
+func workPath(ctx *cli.Context) string {
+	if ctx.IsSet("tfworkpath") {
+		return ctx.String("tfworkpath")
+	}
+	return "./"
+}
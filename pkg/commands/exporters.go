@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/providers"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// exporterCommands returns commands of exporters registered in providers package, an error is returned if a command
+// has the name or alias of another command
+func exporterCommands(commands []*cli.Command) ([]*cli.Command, error) {
+	names := make(map[string]bool)
+	for _, command := range commands {
+		for _, name := range command.Names() {
+			names[name] = true
+		}
+	}
+
+	var result []*cli.Command
+	for _, exporter := range providers.Exporters() {
+		command := exporter.Command()
+		for _, name := range command.Names() {
+			if names[name] {
+				return nil, fmt.Errorf("command '%s' of exporter '%s' is already defined", name, exporter.Name())
+			}
+			names[name] = true
+		}
+		if !hasFlag(command, "tfworkpath") {
+			command.Flags = append(command.Flags, &cli.StringFlag{
+				Name:        "tfworkpath",
+				Usage:       "Directory used to store files created when running commands.",
+				DefaultText: "current directory",
+			})
+		}
+		command.Action = validatedAction(exportAction(exporterAction(exporter)), requireValidWorkpath)
+		result = append(result, command)
+	}
+	return result, nil
+}
+
+// exporterAction runs the exporter with a processor rendering its templates into the target directory
+func exporterAction(exporter providers.Exporter) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		targets, err := providers.TemplateTargets(exporter, workPath(c))
+		if err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Template)
+		}
+		files := make([]string, 0, len(targets))
+		for _, file := range targets {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		if err := tools.CheckFiles(files...); err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+
+		processor := templates.FSTemplateProcessor{
+			TemplatesFS:     exporter.Templates(),
+			TemplateTargets: targets,
+			Output:          templates.GetOutputTarget(c.Context),
+			Sink:            templates.GetSink(c.Context),
+		}
+		if err := exporter.Export(c, processor); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error exporting %s: %s", exporter.Name(), err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
+
+// hasFlag returns true if the command defines flag with the given name
+func hasFlag(command *cli.Command, name string) bool {
+	for _, flag := range command.Flags {
+		for _, flagName := range flag.Names() {
+			if flagName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
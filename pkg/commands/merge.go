@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// outputFlags are flags of export commands which point to output locations, they do not identify the exported object
+var outputFlags = map[string]bool{"tfworkpath": true, "bundlepath": true}
+
+// reconcileOutput runs the export action, stores generated files as the base of the next export and records the export
+// in the workspace state; with --merge flag generated files are merged with local edits and restored if the export fails
+func reconcileOutput(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		// files written into other sinks than the filesystem cannot be reconciled with the work path
+		if !templates.IsDirSink(templates.GetSink(ctx.Context)) {
+			return action(ctx)
+		}
+		dir := workPath(ctx)
+		before, err := workspace.Take(dir)
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error reading workspace: %s", err)), exitcode.Of(err))
+		}
+		recorder := workspace.NewRecorder()
+		ctx.Context = workspace.WithRecorder(ctx.Context, recorder)
+		if err := action(ctx); err != nil {
+			if tools.Merge {
+				if restoreErr := workspace.Restore(dir, before); restoreErr != nil {
+					warnings.Report(ctx.Context, warnings.Warning{
+						Product: "workspace",
+						Reason:  fmt.Sprintf("local files could not be restored after failed export: %s", restoreErr),
+					})
+				}
+			}
+			return err
+		}
+		result, err := workspace.Reconcile(dir, before, tools.Merge)
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error merging exported configuration: %s", err)), exitcode.Of(err))
+		}
+		for _, c := range result.Conflicts {
+			warnings.Report(ctx.Context, warnings.Warning{
+				Product: "workspace",
+				Object:  c.File,
+				Reason:  fmt.Sprintf("%d conflict(s) between local edits and re-exported configuration, resolve them between '%s' and '%s' markers", c.Count, workspace.MarkerOurs, workspace.MarkerTheirs),
+			})
+		}
+
+		reportDuplicates(ctx, dir, recorder.Objects())
+
+		export := workspace.Export{
+			Command:    ctx.Command.Name,
+			Args:       exportArgs(ctx),
+			ExportedAt: time.Now().UTC(),
+			Objects:    recorder.Objects(),
+			Files:      result.Generated,
+		}
+		state, err := workspace.LoadState(dir)
+		if err == nil {
+			state.Record(dir, export)
+			err = state.Save(dir)
+		}
+		if err == nil {
+			err = workspace.AppendHistory(dir, workspace.HistoryEntry{
+				Export:      export,
+				GlobalArgs:  globalArgs(ctx),
+				ToolVersion: ctx.App.Version,
+			})
+		}
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error saving workspace state: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
+
+// reportDuplicates warns about exported objects which are already managed by other workspaces of the directory tree,
+// as applying both configurations would make the workspaces overwrite each other's changes
+func reportDuplicates(ctx *cli.Context, dir string, objects []workspace.Object) {
+	duplicates, err := workspace.FindDuplicates(dir, objects)
+	if err != nil {
+		warnings.Report(ctx.Context, warnings.Warning{
+			Product: "workspace",
+			Reason:  fmt.Sprintf("other workspaces could not be checked for objects managed twice: %s", err),
+		})
+		return
+	}
+	for _, d := range duplicates {
+		object := d.Object.Name
+		if d.Object.ID != "" {
+			object = fmt.Sprintf("%s (%s)", d.Object.Name, d.Object.ID)
+		}
+		warnings.Report(ctx.Context, warnings.Warning{
+			Product: d.Object.Product,
+			Object:  object,
+			Reason:  fmt.Sprintf("already managed by workspace '%s' exported with %s, importing it into both workspaces makes their states compete for the same object", d.Workspace, d.Command),
+		})
+	}
+}
+
+// exportArgs returns arguments identifying the export in the workspace state, i.e. flags other than output locations followed by positional arguments
+func exportArgs(ctx *cli.Context) []string {
+	return append(flagArgs(ctx, ctx.Command.Flags, outputFlags), ctx.Args().Slice()...)
+}
+
+// globalArgs returns global flags set for the command
+func globalArgs(ctx *cli.Context) []string {
+	return flagArgs(ctx, ctx.App.Flags, nil)
+}
+
+// flagArgs returns flags set in the context or its parents in form of '--name=value', skipping the given ones
+func flagArgs(ctx *cli.Context, flags []cli.Flag, skip map[string]bool) []string {
+	args := []string{}
+	for _, f := range flags {
+		name := f.Names()[0]
+		if skip[name] || !ctx.IsSet(name) {
+			continue
+		}
+		if _, ok := f.(*cli.StringSliceFlag); ok {
+			args = append(args, fmt.Sprintf("--%s=%s", name, strings.Join(ctx.StringSlice(name), ",")))
+			continue
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", name, ctx.String(name)))
+	}
+	return args
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// selectOnly runs the export action and keeps only changes of blocks and files given with --only flag, reverting
+// the rest of the work path to its state before the export
+func selectOnly(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if len(tools.Only) == 0 {
+			return action(ctx)
+		}
+		dir := workPath(ctx)
+		before, err := workspace.TakeAll(dir)
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error reading workspace: %s", err)), exitcode.Of(err))
+		}
+		if err := action(ctx); err != nil {
+			return err
+		}
+		if err := workspace.Select(dir, before, tools.Only); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error selecting re-exported configuration: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
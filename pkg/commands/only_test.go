@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestSelectOnly(t *testing.T) {
+	defer func(only []string) { tools.Only = only }(tools.Only)
+	dir := t.TempDir()
+	tools.Only = []string{"akamai_edgekv.ns"}
+
+	path := filepath.Join(dir, "edgekv.tf")
+	local := "resource \"akamai_edgekv\" \"ns\" {\n  retention_in_seconds = 0\n}\n\n# local edit\nresource \"akamai_edgekv_group_items\" \"group\" {\n  group_name = \"local\"\n}\n"
+	require.NoError(t, os.WriteFile(path, []byte(local), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "import.sh"), []byte("local"), 0600))
+
+	set := flag.NewFlagSet("export-edgekv", flag.ContinueOnError)
+	set.String("tfworkpath", "", "")
+	require.NoError(t, set.Parse([]string{"--tfworkpath", dir}))
+	c := cli.NewContext(cli.NewApp(), set, nil)
+	c.Context = context.Background()
+
+	action := func(ctx *cli.Context) error {
+		if err := tools.CheckFiles(path, filepath.Join(dir, "import.sh")); err != nil {
+			return err
+		}
+		sink := templates.GetSink(ctx.Context)
+		exported := "resource \"akamai_edgekv\" \"ns\" {\n  retention_in_seconds = 15724800\n}\n\nresource \"akamai_edgekv_group_items\" \"group\" {\n  group_name = \"exported\"\n}\n"
+		if err := sink.WriteFile(path, []byte(exported)); err != nil {
+			return err
+		}
+		return sink.WriteFile(filepath.Join(dir, "import.sh"), []byte("exported"))
+	}
+	require.NoError(t, selectOnly(action)(c))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "resource \"akamai_edgekv\" \"ns\" {\n  retention_in_seconds = 15724800\n}\n\n# local edit\nresource \"akamai_edgekv_group_items\" \"group\" {\n  group_name = \"local\"\n}\n", string(content))
+	script, err := os.ReadFile(filepath.Join(dir, "import.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "local", string(script))
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/origins"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// inventoryOrigins runs the export action and writes origins.tf listing origins referenced by exported objects into the
+// work path, if it was requested
+func inventoryOrigins(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		// origins of objects exported by export-manifest are listed together once all of them are exported
+		if !tools.OriginsInventory || batch.IsNested(ctx.Context) {
+			return action(ctx)
+		}
+		collector := origins.NewCollector()
+		ctx.Context = origins.WithCollector(ctx.Context, collector)
+		if err := action(ctx); err != nil {
+			return err
+		}
+		if err := origins.Write(templates.GetSink(ctx.Context), workPath(ctx), collector.Entries()); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error writing origin inventory: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
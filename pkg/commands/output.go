@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// applyOutputTemplate runs the export action into the directory given by the output template, if it is set, and makes
+// the exported object available to template processors, which map names of generated files
+func applyOutputTemplate(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if tools.OutputTemplate == "" {
+			return action(ctx)
+		}
+		if err := templates.ValidateOutputTemplate(tools.OutputTemplate); err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		target := templates.NewOutputTarget(exportCommand(ctx), objectName(ctx))
+		ctx.Context = templates.WithOutputTarget(ctx.Context, target)
+		// batch export already runs each export in the directory given by the output template
+		if batch.IsNested(ctx.Context) {
+			return action(ctx)
+		}
+
+		dir, err := templates.OutputDir(tools.OutputTemplate, target)
+		if err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		dir = filepath.Join(workPath(ctx), filepath.FromSlash(dir))
+		if !tools.Status && templates.IsDirSink(templates.GetSink(ctx.Context)) {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return cli.Exit(color.RedString(fmt.Sprintf("Error creating output directory: %s", err)), exitcode.IO)
+			}
+		}
+		if err := ctx.Set("tfworkpath", dir); err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.General)
+		}
+		return action(ctx)
+	}
+}
+
+// exportCommand returns name of the export command, which is the parent command in case of subcommands such as 'export-iam all'
+func exportCommand(ctx *cli.Context) string {
+	for _, c := range ctx.Lineage() {
+		if c.Command != nil && strings.HasPrefix(c.Command.Name, "export-") {
+			return c.Command.Name
+		}
+	}
+	return ctx.Command.Name
+}
+
+// objectName returns name of the exported object given as the first argument, or name of the command if there is none
+func objectName(ctx *cli.Context) string {
+	if ctx.Args().Present() {
+		return ctx.Args().First()
+	}
+	return ctx.Command.Name
+}
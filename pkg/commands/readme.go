@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/readme"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// generateReadme runs the export action and writes README.md describing the exported configuration next to it, if it
+// was requested
+func generateReadme(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if !tools.Readme && tools.ReadmeTemplate == "" {
+			return action(ctx)
+		}
+		// invalid template is reported before the export is run
+		tmpl, err := readme.LoadTemplate(tools.ReadmeTemplate)
+		if err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		if err := action(ctx); err != nil {
+			return err
+		}
+		data := readme.Data{Command: exportCommand(ctx), Object: objectName(ctx)}
+		if collector := warnings.GetCollector(ctx.Context); collector != nil {
+			for _, w := range collector.Warnings() {
+				data.Warnings = append(data.Warnings, w.String())
+			}
+		}
+		if err := readme.Generate(templates.GetSink(ctx.Context), tmpl, workPath(ctx), data); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error generating README: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// selectSink runs the export action with generated files written into the output sink, if other than the filesystem was
+// requested; when files are streamed to the standard output, messages of the export are written to the error stream
+func selectSink(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		// batch export already runs each export with the output sink of the whole run
+		if tools.OutputSink == "" || tools.OutputSink == templates.SinkDir || batch.IsNested(ctx.Context) {
+			return action(ctx)
+		}
+		if batch.FilesystemCommands[ctx.Command.Name] {
+			err := fmt.Errorf("%w: '%s' writes only into the filesystem", templates.ErrInvalidOutputSink, ctx.Command.Name)
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		if flags := filesystemFlags(); len(flags) > 0 {
+			err := fmt.Errorf("%w: '%s' cannot be combined with %s, which work on files in the work path", templates.ErrInvalidOutputSink, tools.OutputSink, strings.Join(flags, ", "))
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+
+		term := terminal.Get(ctx.Context)
+		sink, err := templates.NewSink(tools.OutputSink, workPath(ctx), term)
+		if err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		ctx.Context = templates.WithSink(ctx.Context, sink)
+		if tools.OutputSink == templates.SinkStdout {
+			ctx.Context = terminal.Context(ctx.Context, terminal.New(os.Stderr, nil, term.Error()))
+		}
+		err = action(ctx)
+		if closeErr := sink.Close(); closeErr != nil && err == nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error writing exported configuration: %s", closeErr)), exitcode.Of(closeErr))
+		}
+		return err
+	}
+}
+
+// filesystemFlags returns set flags which read back generated files from the work path
+func filesystemFlags() []string {
+	var flags []string
+	if tools.Merge {
+		flags = append(flags, "--merge")
+	}
+	if tools.Status {
+		flags = append(flags, "--status")
+	}
+	if len(tools.Only) > 0 {
+		flags = append(flags, "--only")
+	}
+	if tools.Archive != "" {
+		flags = append(flags, "--archive")
+	}
+	if tools.Checksums || tools.SignKey != "" {
+		flags = append(flags, "--checksums")
+	}
+	if tools.Terragrunt {
+		flags = append(flags, "--terragrunt")
+	}
+	if tools.Readme || tools.ReadmeTemplate != "" {
+		flags = append(flags, "--readme")
+	}
+	if tools.ProviderVersion != "" {
+		flags = append(flags, "--check-provider-compat")
+	}
+	return flags
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrNotExported is returned when status is requested for an export which is not recorded in the workspace state
+var ErrNotExported = exitcode.New(exitcode.NotFound, "export not found in workspace state")
+
+// reportStatus runs next, unless --status flag is set. In such case the export action is run into a temporary directory
+// and generated files are compared with files recorded in the workspace state, to show which of them are stale.
+func reportStatus(action, next cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if !tools.Status {
+			return next(ctx)
+		}
+		term := terminal.Get(ctx.Context)
+		dir := workPath(ctx)
+		state, err := workspace.LoadState(dir)
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error reading workspace state: %s", err)), exitcode.Of(err))
+		}
+		args := exportArgs(ctx)
+		recorded := state.Find(ctx.Command.Name, args)
+		if recorded == nil {
+			err := fmt.Errorf("%w: '%s %s' was not exported into %s", ErrNotExported, ctx.Command.Name, strings.Join(args, " "), dir)
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+
+		tmp, err := os.MkdirTemp("", "cli-terraform-status")
+		if err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error creating temporary directory: %s", err)), exitcode.IO)
+		}
+		defer func() {
+			_ = os.RemoveAll(tmp)
+		}()
+		for name := range outputFlags {
+			if name == "tfworkpath" || ctx.IsSet(name) {
+				if err := ctx.Set(name, tmp); err != nil {
+					return cli.Exit(color.RedString(err.Error()), exitcode.General)
+				}
+			}
+		}
+		recorder := workspace.NewRecorder()
+		ctx.Context = workspace.WithRecorder(ctx.Context, recorder)
+		ctx.Context = terminal.Context(ctx.Context, terminal.New(terminal.DiscardWriter(), nil, term.Error()))
+		if err := action(ctx); err != nil {
+			return err
+		}
+		generated, err := workspace.Take(tmp)
+		if err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+
+		term.Printf("Exported at %s:\n", recorded.ExportedAt.Format("2006-01-02 15:04:05 MST"))
+		current := make(map[string]workspace.Object)
+		for _, o := range recorder.Objects() {
+			current[o.Product+"/"+o.ID+"/"+o.Name] = o
+		}
+		for _, o := range recorded.Objects {
+			version := o.Version
+			if c, ok := current[o.Product+"/"+o.ID+"/"+o.Name]; ok && c.Version != o.Version {
+				version = fmt.Sprintf("%s, current version %s", o.Version, c.Version)
+			}
+			if version == "" {
+				term.Printf("  %s '%s'\n", o.Product, o.Name)
+				continue
+			}
+			term.Printf("  %s '%s' version %s\n", o.Product, o.Name, version)
+		}
+		statuses := workspace.Compare(dir, recorded, generated)
+		for _, s := range statuses {
+			term.Printf("  %s: %s\n", s.File, s.Status)
+		}
+		if workspace.IsStale(statuses) {
+			term.Writeln(color.YellowString("Exported configuration is stale, export it again to update it"))
+			return nil
+		}
+		term.Writeln(color.GreenString("Exported configuration is up to date"))
+		return nil
+	}
+}
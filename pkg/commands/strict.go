@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// enforceStrict runs the export action and fails it in strict mode if any warning was reported during the export
+func enforceStrict(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		// objects exported by export-manifest are checked together once all of them are exported
+		if err := action(ctx); err != nil || !tools.Strict || batch.IsNested(ctx.Context) {
+			return err
+		}
+		if err := warnings.Strict(ctx.Context); err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		return nil
+	}
+}
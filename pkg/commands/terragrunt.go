@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/terragrunt"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// scaffoldTerragrunt runs the export action and writes terragrunt.hcl next to generated configuration, if it was requested
+func scaffoldTerragrunt(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if err := action(ctx); err != nil || !tools.Terragrunt {
+			return err
+		}
+		if err := terragrunt.Scaffold(workPath(ctx)); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error scaffolding terragrunt configuration: %s", err)), exitcode.Of(err))
+		}
+		return nil
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/akamai/cli-terraform/pkg/exitcode"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
 )
@@ -31,7 +33,7 @@ func requireValidWorkpath(ctx *cli.Context) error {
 	tfWorkPath := ctx.String("tfworkpath")
 	tfWorkPath = filepath.FromSlash(tfWorkPath)
 	if stat, err := os.Stat(tfWorkPath); err != nil || !stat.IsDir() {
-		return cli.Exit(color.RedString("Destination work path is not accessible"), 1)
+		return cli.Exit(color.RedString("Destination work path is not accessible"), exitcode.IO)
 	}
 	return nil
 }
@@ -48,6 +50,43 @@ func requireNArguments(n int) actionValidator {
 	}
 }
 
+// requireNArgumentsOrFlag requires n arguments, or no arguments when one of the flags replacing them is set
+func requireNArgumentsOrFlag(n int, flags ...string) actionValidator {
+	return func(ctx *cli.Context) error {
+		for _, flag := range flags {
+			if !ctx.IsSet(flag) {
+				continue
+			}
+			if ctx.NArg() != 0 {
+				if err := showHelpCommandWithErr(ctx, fmt.Sprintf("Invalid arguments usage, %s cannot be given together with --%s", ctx.Command.ArgsUsage, flag)); err != nil {
+					return err
+				}
+				osExiter(1)
+			}
+			return nil
+		}
+		return requireNArguments(n)(ctx)
+	}
+}
+
+func requireFlags(names ...string) actionValidator {
+	return func(ctx *cli.Context) error {
+		var missing []string
+		for _, name := range names {
+			if ctx.String(name) == "" {
+				missing = append(missing, "--"+name)
+			}
+		}
+		if len(missing) > 0 {
+			if err := showHelpCommandWithErr(ctx, fmt.Sprintf("Invalid flags usage, next flags are required: %s", strings.Join(missing, ", "))); err != nil {
+				return err
+			}
+			osExiter(1)
+		}
+		return nil
+	}
+}
+
 func validateSubCommands(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
 		return showHelpCommandWithErr(ctx, fmt.Sprintf("One of the subcommands is required : %s", getSubcommandsNames(ctx)))
@@ -183,6 +183,111 @@ func TestRequireNArguments(t *testing.T) {
 	})
 }
 
+func TestRequireNArgumentsOrFlag(t *testing.T) {
+	tests := map[string]struct {
+		args         []string
+		expectedExit bool
+		expectedErr  string
+	}{
+		"argument without flag": {
+			args: []string{"my_policy"},
+		},
+		"flag without argument": {
+			args: []string{"--policy-id", "12"},
+		},
+		"no argument and no flag": {
+			expectedExit: true,
+			expectedErr:  "Invalid arguments usage, next arguments are required: <policy_name>",
+		},
+		"argument and flag": {
+			args:         []string{"--policy-id", "12", "my_policy"},
+			expectedExit: true,
+			expectedErr:  "Invalid arguments usage, <policy_name> cannot be given together with --policy-id",
+		},
+		"other flag without argument": {
+			args: []string{"--all"},
+		},
+		"argument and other flag": {
+			args:         []string{"--all", "my_policy"},
+			expectedExit: true,
+			expectedErr:  "Invalid arguments usage, <policy_name> cannot be given together with --all",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			app := cli.NewApp()
+			app.Writer = io.Discard
+			errBuffer := &bytes.Buffer{}
+			app.ErrWriter = errBuffer
+
+			flagSet := flag.NewFlagSet("test", flag.PanicOnError)
+			flagSet.Int64("policy-id", 0, "")
+			flagSet.Bool("all", false, "")
+			require.NoError(t, flagSet.Parse(test.args))
+
+			ctx := cli.NewContext(app, flagSet, nil)
+			ctx.Command.ArgsUsage = "<policy_name>"
+
+			exitOsCalled := false
+			defer func(restore func(_ int)) {
+				osExiter = restore
+			}(osExiter)
+			osExiter = func(_ int) {
+				exitOsCalled = true
+			}
+
+			err := requireNArgumentsOrFlag(1, "policy-id", "all")(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedExit, exitOsCalled)
+			assert.Contains(t, errBuffer.String(), test.expectedErr)
+		})
+	}
+}
+
+func TestRequireFlags(t *testing.T) {
+	t.Run("required flags set", func(t *testing.T) {
+		app := cli.NewApp()
+		flagset := flag.NewFlagSet("test", flag.PanicOnError)
+		flagset.String("contract", "", "")
+		flagset.String("group", "", "")
+		assert.NoError(t, flagset.Parse([]string{"--contract", "ctr_1", "--group", "grp_1"}))
+
+		ctx := cli.NewContext(app, flagset, nil)
+
+		err := requireFlags("contract", "group")(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error missing flags", func(t *testing.T) {
+		app := cli.NewApp()
+		app.Writer = io.Discard
+		errBuffer := &bytes.Buffer{}
+		app.ErrWriter = errBuffer
+
+		flagset := flag.NewFlagSet("test", flag.PanicOnError)
+		flagset.String("contract", "", "")
+		flagset.String("group", "", "")
+		assert.NoError(t, flagset.Parse([]string{"--contract", "ctr_1"}))
+
+		ctx := cli.NewContext(app, flagset, nil)
+
+		exitOsCalled := false
+		// patch osExiter
+		defer func(restore func(_ int)) {
+			osExiter = restore
+		}(osExiter)
+		osExiter = func(_ int) {
+			exitOsCalled = true
+		}
+
+		err := requireFlags("contract", "group")(ctx)
+		assert.NoError(t, err)
+		assert.True(t, exitOsCalled)
+		assert.Contains(t, errBuffer.String(), "Invalid flags usage, next flags are required: --group")
+	})
+}
+
 func TestShowHelpCommandWithErr(t *testing.T) {
 	cmdName := "create-command"
 
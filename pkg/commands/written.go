@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/urfave/cli/v2"
+)
+
+// recordWrites makes sinks of the command record files written by the export and returns a function listing files in
+// dir written since it was called, relative to dir; files written into the filesystem directly, e.g. by export-zone,
+// are listed by their modification time
+func recordWrites(ctx *cli.Context) func(dir string) ([]string, error) {
+	start := time.Now().Truncate(time.Second)
+	stats := templates.GetWriteStats(ctx.Context)
+	if stats == nil {
+		stats = templates.NewWriteStats()
+		ctx.Context = templates.WithWriteStats(ctx.Context, stats)
+	}
+	return func(dir string) ([]string, error) {
+		return workspace.Written(dir, start, stats.Paths())
+	}
+}
@@ -0,0 +1,206 @@
+// Package compat contains code for checking generated configuration against a version of Akamai Terraform provider
+package compat
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type (
+	// Schema is a snapshot of provider versions in which resources, data sources and their attributes were introduced
+	Schema struct {
+		Resources   map[string]Type `json:"resources"`
+		DataSources map[string]Type `json:"dataSources"`
+	}
+
+	// Type describes a single resource or data source,
+	// Attributes contains only those attributes which were introduced later than the type itself
+	Type struct {
+		Since      string            `json:"since"`
+		Attributes map[string]string `json:"attributes,omitempty"`
+		// ImportIDs are documented formats of import IDs of the resource
+		ImportIDs []ImportID `json:"importIds,omitempty"`
+		// Arguments are attributes and nested blocks accepted by the type, arguments of types without them are not
+		// validated
+		Arguments []string `json:"arguments,omitempty"`
+		// Required are arguments which must be set in every block of the type
+		Required []string `json:"required,omitempty"`
+	}
+
+	// Version is a parsed provider version in form of major, minor and patch numbers
+	Version [3]int
+)
+
+var (
+	//go:embed schema.json
+	schemaSnapshot []byte
+
+	// ErrInvalidVersion is returned when provided provider version cannot be parsed
+	ErrInvalidVersion = exitcode.New(exitcode.General, "invalid provider version")
+	// ErrParsingConfiguration is returned when generated configuration cannot be read or parsed
+	ErrParsingConfiguration = exitcode.New(exitcode.Template, "unable to parse generated configuration")
+)
+
+// ParseVersion parses provider version in form of 'major.minor.patch', optionally prefixed with 'v'
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	parts := strings.Split(strings.TrimPrefix(s, "v"), ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("%w: '%s'", ErrInvalidVersion, s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("%w: '%s'", ErrInvalidVersion, s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// Less reports whether v is lower than other
+func (v Version) Less(other Version) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] < other[i]
+		}
+	}
+	return false
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// LoadSchema returns schema snapshot embedded in the binary
+func LoadSchema() (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(schemaSnapshot, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// Check verifies given Terraform files against the given provider version
+// and reports resources, data sources and attributes not available in that version as warnings, other files are skipped
+func Check(ctx context.Context, files []string, providerVersion string) error {
+	target, err := ParseVersion(providerVersion)
+	if err != nil {
+		return err
+	}
+	schema, err := LoadSchema()
+	if err != nil {
+		return err
+	}
+	found, err := schema.check(files, target)
+	if err != nil {
+		return err
+	}
+	for _, w := range found {
+		warnings.Report(ctx, w)
+	}
+	return nil
+}
+
+// check returns warnings for all configuration items in .tf files which require provider newer than target
+func (s *Schema) check(files []string, target Version) ([]warnings.Warning, error) {
+	var result []warnings.Warning
+	for _, file := range files {
+		if filepath.Ext(file) != ".tf" {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrParsingConfiguration, err)
+		}
+		f, diags := hclsyntax.ParseConfig(content, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%w: %s", ErrParsingConfiguration, diags.Error())
+		}
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if len(block.Labels) != 2 {
+				continue
+			}
+			var types map[string]Type
+			switch block.Type {
+			case "resource":
+				types = s.Resources
+			case "data":
+				types = s.DataSources
+			default:
+				continue
+			}
+			result = append(result, checkBlock(block, types, target)...)
+		}
+	}
+	return result, nil
+}
+
+func checkBlock(block *hclsyntax.Block, types map[string]Type, target Version) []warnings.Warning {
+	typeName, name := block.Labels[0], block.Labels[1]
+	t, ok := types[typeName]
+	if !ok {
+		return nil
+	}
+	object := typeName + "." + name
+	if block.Type == "data" {
+		object = "data." + object
+	}
+
+	since, err := ParseVersion(t.Since)
+	if err != nil {
+		return nil
+	}
+	if target.Less(since) {
+		return []warnings.Warning{{
+			Product: "provider",
+			Object:  object,
+			Reason:  fmt.Sprintf("%s '%s' requires provider version %s or newer, target version is %s", block.Type, typeName, since, target),
+		}}
+	}
+
+	var used []string
+	for attribute := range block.Body.Attributes {
+		used = append(used, attribute)
+	}
+	for _, nested := range block.Body.Blocks {
+		used = append(used, nested.Type)
+	}
+	sort.Strings(used)
+
+	var result []warnings.Warning
+	seen := make(map[string]bool)
+	for _, attribute := range used {
+		attributeSince, ok := t.Attributes[attribute]
+		if !ok || seen[attribute] {
+			continue
+		}
+		seen[attribute] = true
+		since, err := ParseVersion(attributeSince)
+		if err != nil || !target.Less(since) {
+			continue
+		}
+		result = append(result, warnings.Warning{
+			Product: "provider",
+			Object:  object,
+			Reason:  fmt.Sprintf("attribute '%s' requires provider version %s or newer, target version is %s", attribute, since, target),
+		})
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package compat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]struct {
+		version   string
+		expected  Version
+		withError bool
+	}{
+		"full version":     {version: "2.3.1", expected: Version{2, 3, 1}},
+		"prefixed version": {version: "v3.1.0", expected: Version{3, 1, 0}},
+		"minor version":    {version: "1.6", expected: Version{1, 6, 0}},
+		"empty":            {version: "", withError: true},
+		"too many parts":   {version: "1.2.3.4", withError: true},
+		"not a number":     {version: "1.x.0", withError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, err := ParseVersion(test.version)
+			if test.withError {
+				assert.True(t, errors.Is(err, ErrInvalidVersion), "expected: %s; got: %s", ErrInvalidVersion, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	assert.True(t, Version{1, 6, 0}.Less(Version{1, 12, 0}))
+	assert.True(t, Version{1, 12, 3}.Less(Version{2, 0, 0}))
+	assert.False(t, Version{2, 0, 0}.Less(Version{2, 0, 0}))
+	assert.False(t, Version{3, 1, 0}.Less(Version{2, 9, 9}))
+}
+
+func TestLoadSchema(t *testing.T) {
+	schema, err := LoadSchema()
+	require.NoError(t, err)
+	for name, types := range map[string]map[string]Type{"resources": schema.Resources, "data sources": schema.DataSources} {
+		assert.NotEmpty(t, types, name)
+		for typeName, typ := range types {
+			_, err := ParseVersion(typ.Since)
+			assert.NoError(t, err, typeName)
+			for attribute, since := range typ.Attributes {
+				_, err := ParseVersion(since)
+				assert.NoError(t, err, "%s.%s", typeName, attribute)
+			}
+			for _, required := range typ.Required {
+				assert.Contains(t, typ.Arguments, required, typeName)
+			}
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cloudletsWarnings := []warnings.Warning{
+		{
+			Product: "provider",
+			Object:  "data.akamai_cloudlets_edge_redirector_match_rule.match_rules_er",
+			Reason:  "data 'akamai_cloudlets_edge_redirector_match_rule' requires provider version 1.7.0 or newer, target version is %s",
+		},
+		{
+			Product: "provider",
+			Object:  "akamai_cloudlets_policy.policy",
+			Reason:  "resource 'akamai_cloudlets_policy' requires provider version 1.7.0 or newer, target version is %s",
+		},
+	}
+	propertyWarnings := []warnings.Warning{
+		{
+			Product: "provider",
+			Object:  "akamai_edge_hostname.test-edgesuite-net",
+			Reason:  "attribute 'use_cases' requires provider version 1.6.0 or newer, target version is %s",
+		},
+		{
+			Product: "provider",
+			Object:  "akamai_property.test-edgesuite-net",
+			Reason:  "attribute 'hostnames' requires provider version 1.5.0 or newer, target version is %s",
+		},
+		{
+			Product: "provider",
+			Object:  "akamai_property_activation.test-edgesuite-net",
+			Reason:  "attribute 'note' requires provider version 1.6.0 or newer, target version is %s",
+		},
+	}
+
+	exported := []string{"./testdata/export/modules/policy.tf", "./testdata/export/property.tf"}
+	tests := map[string]struct {
+		files     []string
+		version   string
+		expected  []warnings.Warning
+		withError error
+	}{
+		"configuration supported": {
+			files:   exported,
+			version: "2.0.0",
+		},
+		"resources not available": {
+			files:    exported,
+			version:  "1.6.0",
+			expected: cloudletsWarnings,
+		},
+		"resources and attributes not available": {
+			files:    exported,
+			version:  "1.0.0",
+			expected: append(append([]warnings.Warning{}, cloudletsWarnings...), propertyWarnings...),
+		},
+		"only given configuration is checked": {
+			files:    []string{"./testdata/export/property.tf", "./testdata/export/import.sh"},
+			version:  "1.0.0",
+			expected: propertyWarnings,
+		},
+		"invalid version": {
+			files:     exported,
+			version:   "latest",
+			withError: ErrInvalidVersion,
+		},
+		"invalid configuration": {
+			files:     []string{"./testdata/invalid/invalid.tf"},
+			version:   "2.0.0",
+			withError: ErrParsingConfiguration,
+		},
+		"missing file": {
+			files:     []string{"./testdata/missing.tf"},
+			version:   "2.0.0",
+			withError: ErrParsingConfiguration,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			collector := warnings.NewCollector()
+			ctx := warnings.WithCollector(context.Background(), collector)
+			err := Check(ctx, test.files, test.version)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var expected []warnings.Warning
+			for _, w := range test.expected {
+				w.Reason = fmt.Sprintf(w.Reason, test.version)
+				expected = append(expected, w)
+			}
+			if expected == nil {
+				expected = []warnings.Warning{}
+			}
+			assert.Equal(t, expected, collector.Warnings())
+		})
+	}
+}
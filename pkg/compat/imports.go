@@ -0,0 +1,186 @@
+package compat
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+)
+
+type (
+	// ImportID is a documented format of import ID of a resource. Placeholders are written as '<name>', or '<name:int>'
+	// for numeric values, other characters are literals separating them, e.g. '<config_id:int>:<security_policy_id>'.
+	ImportID struct {
+		// Since is the provider version which introduced the format, the version of the resource type when empty
+		Since  string `json:"since,omitempty"`
+		Format string `json:"format"`
+	}
+
+	// Import is a resource imported with terraform import
+	Import struct {
+		Address string
+		ID      string
+	}
+)
+
+var (
+	// ErrInvalidImportID is returned when import ID does not match any of documented formats of the imported resource
+	ErrInvalidImportID = exitcode.New(exitcode.Template, "invalid import ID")
+
+	placeholderRegexp = regexp.MustCompile(`<([a-z_]+)(:int)?>`)
+)
+
+// ValidateImportScript checks IDs of resources imported by 'terraform import' commands of the script against import ID
+// formats documented for the provider version, the latest formats are used when the version is empty
+func ValidateImportScript(script []byte, providerVersion string) error {
+	return ValidateImports(ParseImportScript(script), providerVersion)
+}
+
+// ValidateImports checks IDs of imported resources against import ID formats documented for the provider version, the
+// latest formats are used when the version is empty. Resources of types without documented formats are not checked.
+func ValidateImports(imports []Import, providerVersion string) error {
+	target := Version{1 << 30}
+	if providerVersion != "" {
+		var err error
+		if target, err = ParseVersion(providerVersion); err != nil {
+			return err
+		}
+	}
+	schema, err := LoadSchema()
+	if err != nil {
+		return err
+	}
+
+	var invalid []string
+	for _, imp := range imports {
+		formats := schema.importIDs(resourceType(imp.Address), target)
+		if len(formats) == 0 || matchesAny(imp.ID, formats) {
+			continue
+		}
+		invalid = append(invalid, fmt.Sprintf("'%s' of %s does not match %s", imp.ID, imp.Address, strings.Join(formats, " or ")))
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidImportID, strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+// ParseImportScript returns resources imported by 'terraform import' commands of the script
+func ParseImportScript(script []byte) []Import {
+	var imports []Import
+	for _, line := range bytes.Split(script, []byte("\n")) {
+		args := shellWords(string(line))
+		if len(args) < 2 || args[0] != "terraform" || args[1] != "import" {
+			continue
+		}
+		var operands []string
+		for _, arg := range args[2:] {
+			if !strings.HasPrefix(arg, "-") {
+				operands = append(operands, arg)
+			}
+		}
+		if len(operands) == 2 {
+			imports = append(imports, Import{Address: operands[0], ID: operands[1]})
+		}
+	}
+	return imports
+}
+
+// importIDs returns import ID formats of the resource type available in the target provider version
+func (s *Schema) importIDs(resourceType string, target Version) []string {
+	t, ok := s.Resources[resourceType]
+	if !ok {
+		return nil
+	}
+	var formats []string
+	for _, id := range t.ImportIDs {
+		since := id.Since
+		if since == "" {
+			since = t.Since
+		}
+		if v, err := ParseVersion(since); err == nil && !target.Less(v) {
+			formats = append(formats, id.Format)
+		}
+	}
+	return formats
+}
+
+// matchesAny returns true if the import ID matches any of the formats
+func matchesAny(id string, formats []string) bool {
+	for _, format := range formats {
+		if formatRegexp(format).MatchString(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatRegexp returns regular expression matching import IDs of the format, values of placeholders cannot contain
+// literal characters of the format, so that IDs with a wrong number of parts do not match
+func formatRegexp(format string) *regexp.Regexp {
+	literals := placeholderRegexp.ReplaceAllString(format, "")
+	value := ".+"
+	if literals != "" {
+		value = "[^" + regexp.QuoteMeta(literals) + "]+"
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, match := range placeholderRegexp.FindAllStringSubmatchIndex(format, -1) {
+		pattern.WriteString(regexp.QuoteMeta(format[last:match[0]]))
+		if match[4] >= 0 {
+			pattern.WriteString(`\d+`)
+		} else {
+			pattern.WriteString(value)
+		}
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(format[last:]))
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
+
+// resourceType returns type of the resource at the address, e.g. 'akamai_dns_record' for
+// 'module.zone.akamai_dns_record.www["a"]'
+func resourceType(address string) string {
+	parts := strings.Split(address, ".")
+	for len(parts) > 2 && parts[0] == "module" {
+		parts = parts[2:]
+	}
+	return parts[0]
+}
+
+// shellWords splits the line into words the way shell does for simple commands, with single and double quotes removed
+func shellWords(line string) []string {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
@@ -0,0 +1,100 @@
+package compat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportScript(t *testing.T) {
+	script := `terraform init
+terraform import akamai_gtm_domain.test_name "test.name.akadns.net"
+terraform import 'akamai_dns_record.example_com_A_records_1["www"]' example.com#www.example.com#A
+terraform import -var-file=terraform.tfvars module.security.akamai_appsec_configuration.config 12345
+# terraform import akamai_property.commented prp_1
+terraform import akamai_gtm_property.property "test.name.akadns.net:test property"`
+
+	assert.Equal(t, []Import{
+		{Address: "akamai_gtm_domain.test_name", ID: "test.name.akadns.net"},
+		{Address: `akamai_dns_record.example_com_A_records_1["www"]`, ID: "example.com#www.example.com#A"},
+		{Address: "module.security.akamai_appsec_configuration.config", ID: "12345"},
+		{Address: "akamai_gtm_property.property", ID: "test.name.akadns.net:test property"},
+	}, ParseImportScript([]byte(script)))
+}
+
+func TestValidateImports(t *testing.T) {
+	tests := map[string]struct {
+		givenImports    []Import
+		providerVersion string
+		withError       string
+	}{
+		"valid IDs": {
+			givenImports: []Import{
+				{Address: "module.security.akamai_appsec_security_policy.default", ID: "12345:abc_123"},
+				{Address: "module.security.akamai_appsec_advanced_settings_logging.logging", ID: "12345"},
+				{Address: "module.security.akamai_appsec_advanced_settings_logging.default", ID: "12345:abc_123"},
+				{Address: "akamai_property.property", ID: "prp_1,ctr_1,grp_1,3"},
+				{Address: "akamai_gtm_property.property", ID: "test.name.akadns.net:test property"},
+				{Address: `akamai_dns_record.records["www"]`, ID: "example.com#www.example.com#A"},
+			},
+		},
+		"resource types without formats are not checked": {
+			givenImports: []Import{
+				{Address: "akamai_unknown.unknown", ID: "a:b:c"},
+			},
+		},
+		"wrong number of parts": {
+			givenImports: []Import{
+				{Address: "module.security.akamai_appsec_rule.default_1", ID: "12345:abc_123"},
+			},
+			withError: "invalid import ID: '12345:abc_123' of module.security.akamai_appsec_rule.default_1 does not match <config_id:int>:<security_policy_id>:<rule_id:int>",
+		},
+		"wrong separator and non-numeric value": {
+			givenImports: []Import{
+				{Address: "akamai_cps_dv_enrollment.enrollment", ID: "1234:ctr_1"},
+				{Address: "akamai_edgeworker.edgeworker", ID: "abc"},
+			},
+			withError: "invalid import ID: '1234:ctr_1' of akamai_cps_dv_enrollment.enrollment does not match <enrollment_id:int>,<contract_id>; " +
+				"'abc' of akamai_edgeworker.edgeworker does not match <edgeworker_id:int>",
+		},
+		"invalid provider version": {
+			givenImports:    []Import{},
+			providerVersion: "a.b",
+			withError:       "invalid provider version: 'a.b'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateImports(test.givenImports, test.providerVersion)
+			if test.withError != "" {
+				assert.EqualError(t, err, test.withError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateImportScript(t *testing.T) {
+	err := ValidateImportScript([]byte("terraform import akamai_gtm_datacenter.dc \"test.name.akadns.net:dc1\""), "")
+	assert.True(t, errors.Is(err, ErrInvalidImportID), "expected: %s; got: %s", ErrInvalidImportID, err)
+}
+
+func TestImportIDs(t *testing.T) {
+	schema := Schema{Resources: map[string]Type{
+		"akamai_resource": {
+			Since: "1.0.0",
+			ImportIDs: []ImportID{
+				{Format: "<id>"},
+				{Since: "2.0.0", Format: "<id>,<contract_id>"},
+			},
+		},
+	}}
+
+	assert.Equal(t, []string{"<id>"}, schema.importIDs("akamai_resource", Version{1, 5, 0}))
+	assert.Equal(t, []string{"<id>", "<id>,<contract_id>"}, schema.importIDs("akamai_resource", Version{2, 0, 0}))
+	assert.Empty(t, schema.importIDs("akamai_other", Version{2, 0, 0}))
+}
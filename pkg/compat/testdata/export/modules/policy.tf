@@ -0,0 +1,9 @@
+data "akamai_cloudlets_edge_redirector_match_rule" "match_rules_er" {
+}
+
+resource "akamai_cloudlets_policy" "policy" {
+  name          = "test_policy"
+  cloudlet_code = "ER"
+  group_id      = "12345"
+  match_rules   = data.akamai_cloudlets_edge_redirector_match_rule.match_rules_er.json
+}
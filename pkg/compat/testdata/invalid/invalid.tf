@@ -0,0 +1,3 @@
+resource "akamai_property" "test" {
+  name =
+}
@@ -0,0 +1,106 @@
+package compat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+var (
+	// ErrSchemaMismatch is returned when generated configuration sets arguments unknown to the provider or misses
+	// required ones
+	ErrSchemaMismatch = exitcode.New(exitcode.Template, "configuration does not match provider schema")
+
+	// metaArguments are accepted by blocks of every resource and data source type
+	metaArguments = map[string]bool{"count": true, "depends_on": true, "for_each": true, "lifecycle": true, "provider": true}
+)
+
+// ValidateConfiguration checks resources and data sources of the configuration in file against arguments of their types
+// in the schema snapshot, so that generated configuration fails with the offending block instead of at terraform plan.
+// Arguments unknown to the provider and missing required arguments are reported, types without documented arguments
+// are not checked.
+func ValidateConfiguration(content []byte, file string) error {
+	schema, err := LoadSchema()
+	if err != nil {
+		return err
+	}
+	f, diags := hclsyntax.ParseConfig(content, file, hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("%w: %s", ErrParsingConfiguration, diags.Error())
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var mismatches []string
+	for _, block := range body.Blocks {
+		if len(block.Labels) != 2 {
+			continue
+		}
+		var types map[string]Type
+		switch block.Type {
+		case "resource":
+			types = schema.Resources
+		case "data":
+			types = schema.DataSources
+		default:
+			continue
+		}
+		if t, ok := types[block.Labels[0]]; ok && len(t.Arguments) > 0 {
+			mismatches = append(mismatches, validateBlock(block, t)...)
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// validateBlock returns descriptions of arguments of the block which are not accepted by its type or required by it
+// and missing, prefixed with the position and the address of the block
+func validateBlock(block *hclsyntax.Block, t Type) []string {
+	address := block.Labels[0] + "." + block.Labels[1]
+	if block.Type == "data" {
+		address = "data." + address
+	}
+	position := fmt.Sprintf("%s:%d: %s", block.DefRange().Filename, block.DefRange().Start.Line, address)
+
+	set := make(map[string]bool)
+	for name := range block.Body.Attributes {
+		set[name] = true
+	}
+	for _, nested := range block.Body.Blocks {
+		if nested.Type == "dynamic" && len(nested.Labels) == 1 {
+			set[nested.Labels[0]] = true
+			continue
+		}
+		set[nested.Type] = true
+	}
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	accepted := make(map[string]bool, len(t.Arguments))
+	for _, argument := range t.Arguments {
+		accepted[argument] = true
+	}
+	var result []string
+	for _, name := range names {
+		if !accepted[name] && !metaArguments[name] {
+			result = append(result, fmt.Sprintf("%s: unsupported argument '%s', expected one of: %s", position, name, strings.Join(t.Arguments, ", ")))
+		}
+	}
+	for _, name := range t.Required {
+		if !set[name] {
+			result = append(result, fmt.Sprintf("%s: missing required argument '%s'", position, name))
+		}
+	}
+	return result
+}
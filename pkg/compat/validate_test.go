@@ -0,0 +1,104 @@
+package compat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		content   string
+		expected  string
+		withError error
+	}{
+		"valid configuration": {
+			content: `
+resource "akamai_cloudlets_policy" "policy" {
+  name = "test_policy"
+  cloudlet_code = "ER"
+  group_id = "12345"
+  match_rules = data.akamai_cloudlets_edge_redirector_match_rule.match_rules_er.json
+  lifecycle {
+    ignore_changes = [description]
+  }
+}
+
+data "akamai_cloudlets_edge_redirector_match_rule" "match_rules_er" {
+  match_rules {
+    name = "r1"
+  }
+}
+`,
+		},
+		"dynamic block": {
+			content: `
+data "akamai_cloudlets_edge_redirector_match_rule" "match_rules" {
+  dynamic "match_rules" {
+    for_each = var.match_rules
+    content {
+      name = match_rules.value.name
+    }
+  }
+}
+`,
+		},
+		"types without arguments are not validated": {
+			content: `
+resource "akamai_edgeworker" "edgeworker" {
+  unknown = true
+}
+
+locals {
+  unknown = true
+}
+`,
+		},
+		"unsupported argument": {
+			content: `
+resource "akamai_cloudlets_policy_activation" "policy_activation" {
+  policy_id = 1
+  network = var.env
+  version = 2
+  properties = ["prp_0"]
+}
+`,
+			expected:  "policy.tf:2: akamai_cloudlets_policy_activation.policy_activation: unsupported argument 'properties', expected one of: associated_properties, network, policy_id, timeouts, version",
+			withError: ErrSchemaMismatch,
+		},
+		"missing required arguments": {
+			content: `
+resource "akamai_cloudlets_application_load_balancer" "load_balancer_test_origin" {
+  description = "test"
+}
+
+data "akamai_cloudlets_shared_policy" "policy" {
+}
+`,
+			expected: "policy.tf:2: akamai_cloudlets_application_load_balancer.load_balancer_test_origin: missing required argument 'data_centers'; " +
+				"policy.tf:2: akamai_cloudlets_application_load_balancer.load_balancer_test_origin: missing required argument 'origin_id'; " +
+				"policy.tf:6: data.akamai_cloudlets_shared_policy.policy: missing required argument 'policy_id'",
+			withError: ErrSchemaMismatch,
+		},
+		"invalid configuration": {
+			content:   `resource "akamai_cloudlets_policy" "policy" {`,
+			withError: ErrParsingConfiguration,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateConfiguration([]byte(test.content), "policy.tf")
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				if test.expected != "" {
+					assert.Contains(t, err.Error(), test.expected)
+				}
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
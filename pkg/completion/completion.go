@@ -0,0 +1,189 @@
+// Package completion contains code for generating shell completion scripts and completing object names
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli/pkg/autocomplete"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// cacheTTL is a time for which listed object names are reused by subsequent completions
+const cacheTTL = 5 * time.Minute
+
+var (
+	// ErrUnsupportedShell is returned when completion script is requested for an unknown shell
+	ErrUnsupportedShell = exitcode.New(exitcode.Unsupported, "unsupported shell")
+
+	scripts = map[string]string{
+		"bash": bashScript,
+		"zsh":  zshScript,
+		"fish": fishScript,
+	}
+)
+
+// Shells returns names of shells for which completion script can be generated
+func Shells() []string {
+	return []string{"bash", "fish", "zsh"}
+}
+
+// CmdCompletion is an entrypoint to completion command
+func CmdCompletion(c *cli.Context) error {
+	term := terminal.Get(c.Context)
+
+	script, err := generate(c.Args().First(), filepath.Base(os.Args[0]))
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error generating completion script: %s", err)), exitcode.Of(err))
+	}
+	term.Printf("%s", script)
+	return nil
+}
+
+func generate(shell, program string) (string, error) {
+	script, ok := scripts[shell]
+	if !ok {
+		return "", fmt.Errorf("%w: '%s', use one of: %s", ErrUnsupportedShell, shell, strings.Join(Shells(), ", "))
+	}
+	function := strings.NewReplacer("-", "_", ".", "_").Replace(program)
+	return strings.NewReplacer("{{program}}", program, "{{function}}", function).Replace(script), nil
+}
+
+// Objects returns completion function suggesting names of existing objects of given product as the command argument,
+// names are listed using the API and cached for a short time, so that completion stays responsive
+func Objects(product string) cli.BashCompleteFunc {
+	return func(c *cli.Context) {
+		if c.NArg() == 0 {
+			cachePath, err := cacheFile(edgegrid.GetEdgercSection(c), product)
+			if err == nil {
+				clients := discovery.NewClients(edgegrid.GetSession(c.Context))
+				// completion must not break the shell, failed listing just results in no suggestions
+				if names, err := objectNames(c.Context, product, cachePath, clients); err == nil {
+					for _, name := range names {
+						fmt.Fprintln(c.App.Writer, name)
+					}
+				}
+			}
+		}
+		autocomplete.Default(c)
+	}
+}
+
+func objectNames(ctx context.Context, product, cachePath string, clients discovery.Clients) ([]string, error) {
+	if names, ok := loadCache(cachePath, cacheTTL); ok {
+		return names, nil
+	}
+
+	objects, err := discovery.List(ctx, product, clients)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if object.Selected {
+			names = append(names, object.Name)
+		}
+	}
+	saveCache(cachePath, names)
+	return names, nil
+}
+
+func cacheFile(section, product string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "akamai-terraform", "completion", fmt.Sprintf("%s_%s.json", section, product)), nil
+}
+
+func loadCache(path string, ttl time.Duration) ([]string, bool) {
+	stat, err := os.Stat(path)
+	if err != nil || time.Since(stat.ModTime()) > ttl {
+		return nil, false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var names []string
+	if err := json.Unmarshal(content, &names); err != nil {
+		return nil, false
+	}
+	return names, true
+}
+
+// saveCache stores listed names, failures are ignored as the cache is only an optimization
+func saveCache(path string, names []string) {
+	content, err := json.Marshal(names)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, content, 0600)
+}
+
+const bashScript = `# bash completion for {{program}}, load with: source <({{program}} completion bash)
+_{{function}}_completion() {
+  local cur opts
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ "$cur" == "-"* ]]; then
+    opts=$( "${COMP_WORDS[@]:0:$COMP_CWORD}" "${cur}" --generate-bash-completion 2>/dev/null )
+  else
+    opts=$( "${COMP_WORDS[@]:0:$COMP_CWORD}" --generate-bash-completion 2>/dev/null )
+  fi
+  local IFS=$'\n'
+  COMPREPLY=( $(compgen -W "${opts}" -- "${cur}") )
+  return 0
+}
+
+complete -o bashdefault -o default -F _{{function}}_completion {{program}}
+`
+
+const zshScript = `#compdef {{program}}
+# zsh completion for {{program}}, load with: source <({{program}} completion zsh)
+_{{function}}_completion() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion 2>/dev/null)}")
+  else
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} --generate-bash-completion 2>/dev/null)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    compadd -a opts
+  else
+    _files
+  fi
+}
+
+compdef _{{function}}_completion {{program}}
+`
+
+const fishScript = `# fish completion for {{program}}, load with: {{program}} completion fish | source
+function __{{function}}_completion
+    set -l args (commandline -opc)
+    set -l cur (commandline -ct)
+    if string match -q -- '-*' $cur
+        $args $cur --generate-bash-completion 2>/dev/null
+    else
+        $args --generate-bash-completion 2>/dev/null
+    end
+end
+
+complete -c {{program}} -f -a '(__{{function}}_completion)'
+`
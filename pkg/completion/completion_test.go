@@ -0,0 +1,126 @@
+package completion
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		shell     string
+		expected  []string
+		withError error
+	}{
+		"bash": {
+			shell:    "bash",
+			expected: []string{"_akamai_terraform_completion()", "complete -o bashdefault -o default -F _akamai_terraform_completion akamai-terraform"},
+		},
+		"zsh": {
+			shell:    "zsh",
+			expected: []string{"#compdef akamai-terraform", "compdef _akamai_terraform_completion akamai-terraform"},
+		},
+		"fish": {
+			shell:    "fish",
+			expected: []string{"function __akamai_terraform_completion", "complete -c akamai-terraform -f -a '(__akamai_terraform_completion)'"},
+		},
+		"unsupported shell": {
+			shell:     "powershell",
+			withError: ErrUnsupportedShell,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, err := generate(test.shell, "akamai-terraform")
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotContains(t, script, "{{")
+			for _, expected := range test.expected {
+				assert.Contains(t, script, expected)
+			}
+		})
+	}
+}
+
+func TestObjectNames(t *testing.T) {
+	tests := map[string]struct {
+		cache     []byte
+		cacheAge  time.Duration
+		init      func(*gtm.Mock)
+		expected  []string
+		withError bool
+	}{
+		"names listed and cached": {
+			init: func(m *gtm.Mock) {
+				m.On("ListDomains", mock.Anything).Return([]*gtm.DomainItem{{Name: "first.akadns.net"}, {Name: "second.akadns.net"}}, nil).Once()
+			},
+			expected: []string{"first.akadns.net", "second.akadns.net"},
+		},
+		"names read from cache": {
+			cache:    []byte(`["cached.akadns.net"]`),
+			init:     func(_ *gtm.Mock) {},
+			expected: []string{"cached.akadns.net"},
+		},
+		"expired cache": {
+			cache:    []byte(`["cached.akadns.net"]`),
+			cacheAge: 2 * cacheTTL,
+			init: func(m *gtm.Mock) {
+				m.On("ListDomains", mock.Anything).Return([]*gtm.DomainItem{{Name: "test.akadns.net"}}, nil).Once()
+			},
+			expected: []string{"test.akadns.net"},
+		},
+		"invalid cache": {
+			cache: []byte(`{`),
+			init: func(m *gtm.Mock) {
+				m.On("ListDomains", mock.Anything).Return([]*gtm.DomainItem{{Name: "test.akadns.net"}}, nil).Once()
+			},
+			expected: []string{"test.akadns.net"},
+		},
+		"listing failed": {
+			init: func(m *gtm.Mock) {
+				m.On("ListDomains", mock.Anything).Return(nil, errors.New("oops")).Once()
+			},
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cachePath := filepath.Join(t.TempDir(), "completion", "default_gtm.json")
+			if test.cache != nil {
+				require.NoError(t, os.MkdirAll(filepath.Dir(cachePath), 0700))
+				require.NoError(t, os.WriteFile(cachePath, test.cache, 0600))
+				modTime := time.Now().Add(-test.cacheAge)
+				require.NoError(t, os.Chtimes(cachePath, modTime, modTime))
+			}
+			m := new(gtm.Mock)
+			test.init(m)
+
+			names, err := objectNames(context.Background(), discovery.ProductGTM, cachePath, discovery.Clients{GTM: m})
+			m.AssertExpectations(t)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, names)
+
+			cached, ok := loadCache(cachePath, cacheTTL)
+			assert.True(t, ok)
+			assert.Equal(t, test.expected, cached)
+		})
+	}
+}
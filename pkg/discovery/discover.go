@@ -0,0 +1,382 @@
+// Package discovery contains code for enumerating exportable objects existing on the account
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/appsec"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	cloudletsprovider "github.com/akamai/cli-terraform/pkg/providers/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// Clients groups API clients used to list objects of each product
+	Clients struct {
+		PAPI        papi.PAPI
+		DNS         dns.DNS
+		GTM         gtm.GTM
+		Cloudlets   cloudlets.Cloudlets
+		AppSec      appsec.APPSEC
+		EdgeWorkers edgeworkers.Edgeworkers
+	}
+
+	discoverFunc func(context.Context, Clients) ([]manifest.Object, error)
+)
+
+const (
+	// ProductProperty is a product name used for property manager properties
+	ProductProperty = "property"
+	// ProductDNS is a product name used for edge dns zones
+	ProductDNS = "dns"
+	// ProductGTM is a product name used for global traffic manager domains
+	ProductGTM = "gtm"
+	// ProductCloudlets is a product name used for cloudlets policies
+	ProductCloudlets = "cloudlets"
+	// ProductAppSec is a product name used for application security configurations
+	ProductAppSec = "appsec"
+	// ProductEdgeWorkers is a product name used for edgeworkers
+	ProductEdgeWorkers = "edgeworkers"
+)
+
+var discoverers = map[string]discoverFunc{
+	ProductProperty:    discoverProperties,
+	ProductDNS:         discoverZones,
+	ProductGTM:         discoverDomains,
+	ProductCloudlets:   discoverPolicies,
+	ProductAppSec:      discoverSecurityConfigurations,
+	ProductEdgeWorkers: discoverEdgeWorkers,
+}
+
+var (
+	// ErrUnsupportedProduct is returned when discovery is requested for an unknown product
+	ErrUnsupportedProduct = exitcode.New(exitcode.Unsupported, "unsupported product")
+	// ErrNothingDiscovered is returned when discovery failed for every requested product
+	ErrNothingDiscovered = exitcode.New(exitcode.API, "unable to discover any objects")
+)
+
+// Products returns names of all products supported by discovery
+func Products() []string {
+	products := make([]string, 0, len(discoverers))
+	for name := range discoverers {
+		products = append(products, name)
+	}
+	sort.Strings(products)
+	return products
+}
+
+// NewClients creates API clients of all products supported by discovery
+func NewClients(sess session.Session) Clients {
+	return Clients{
+		PAPI:        papi.Client(sess),
+		DNS:         dns.Client(sess),
+		GTM:         gtm.Client(sess),
+		Cloudlets:   cloudlets.Client(sess),
+		AppSec:      appsec.Client(sess),
+		EdgeWorkers: edgeworkers.Client(sess),
+	}
+}
+
+// List returns exportable objects of a single product
+func List(ctx context.Context, product string, clients Clients) ([]manifest.Object, error) {
+	discoverer, ok := discoverers[product]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s', use one of: %s", ErrUnsupportedProduct, product, strings.Join(Products(), ", "))
+	}
+	return discoverer(ctx, clients)
+}
+
+// CmdDiscover is an entrypoint to discover command
+func CmdDiscover(c *cli.Context) error {
+	ctx := c.Context
+	clients := NewClients(edgegrid.GetSession(ctx))
+
+	// tfWorkPath is a target directory for generated manifest
+	var tfWorkPath = "./"
+	if c.IsSet("tfworkpath") {
+		tfWorkPath = c.String("tfworkpath")
+	}
+	manifestPath := filepath.Join(tfWorkPath, "manifest.json")
+	if c.IsSet("output") {
+		manifestPath = c.String("output")
+	}
+	if err := tools.CheckFiles(manifestPath); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	products := Products()
+	if c.IsSet("products") {
+		products = tools.SplitList(c.StringSlice("products"))
+	}
+
+	section := edgegrid.GetEdgercSection(c)
+	m, err := discover(ctx, products, section, clients)
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error discovering account objects: %s", err)), exitcode.Of(err))
+	}
+	if err := m.Save(manifestPath); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	return nil
+}
+
+func discover(ctx context.Context, products []string, section string, clients Clients) (*manifest.Manifest, error) {
+	term := terminal.Get(ctx)
+
+	for _, product := range products {
+		if _, ok := discoverers[product]; !ok {
+			return nil, fmt.Errorf("%w: '%s', use one of: %s", ErrUnsupportedProduct, product, strings.Join(Products(), ", "))
+		}
+	}
+
+	term.Writeln("Discovering account objects")
+	m := manifest.Manifest{
+		Section: section,
+		Objects: make([]manifest.Object, 0),
+	}
+	var failed int
+	for _, product := range products {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		progress.Get(ctx).Start("Listing " + product + " objects")
+		objects, err := discoverers[product](ctx, clients)
+		if err != nil {
+			progress.Get(ctx).Fail()
+			warnings.Report(ctx, warnings.Warning{
+				Product: product,
+				Reason:  fmt.Sprintf("objects could not be listed: %s", err),
+			})
+			failed++
+			continue
+		}
+		if product == ProductDNS {
+			addNameservers(ctx, clients, objects)
+		}
+		m.Objects = append(m.Objects, objects...)
+		progress.Get(ctx).OK()
+	}
+	if failed > 0 && failed == len(products) {
+		return nil, ErrNothingDiscovered
+	}
+
+	term.Printf("Discovered %d objects\n", len(m.Objects))
+	return &m, nil
+}
+
+func discoverProperties(ctx context.Context, clients Clients) ([]manifest.Object, error) {
+	groups, err := clients.PAPI.GetGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []papi.GetPropertiesRequest
+	for _, group := range groups.Groups.Items {
+		for _, contractID := range group.ContractIDs {
+			requests = append(requests, papi.GetPropertiesRequest{
+				ContractID: contractID,
+				GroupID:    group.GroupID,
+			})
+		}
+	}
+
+	// properties are fetched concurrently, results are stored per request to keep the output order deterministic
+	results := make([][]*papi.Property, len(requests))
+	progress.Get(ctx).Total(len(requests))
+	err = tools.RunConcurrently(ctx, len(requests), func(ctx context.Context, i int) error {
+		properties, err := clients.PAPI.GetProperties(ctx, requests[i])
+		if err != nil {
+			return err
+		}
+		results[i] = properties.Properties.Items
+		progress.Get(ctx).Step()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []manifest.Object
+	seen := map[string]struct{}{}
+	for _, properties := range results {
+		for _, property := range properties {
+			if _, ok := seen[property.PropertyID]; ok {
+				continue
+			}
+			seen[property.PropertyID] = struct{}{}
+			objects = append(objects, manifest.Object{
+				Product:    ProductProperty,
+				Name:       property.PropertyName,
+				ID:         property.PropertyID,
+				ContractID: property.ContractID,
+				GroupID:    property.GroupID,
+				Command:    "export-property",
+				Args:       []string{property.PropertyName},
+				Selected:   true,
+			})
+		}
+	}
+	return objects, nil
+}
+
+func discoverZones(ctx context.Context, clients Clients) ([]manifest.Object, error) {
+	zones, err := clients.DNS.ListZones(ctx, dns.ZoneListQueryArgs{ShowAll: true})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]manifest.Object, 0, len(zones.Zones))
+	for _, zone := range zones.Zones {
+		objects = append(objects, manifest.Object{
+			Product:    ProductDNS,
+			Name:       zone.Zone,
+			ContractID: zone.ContractID,
+			Command:    "export-zone",
+			Args:       []string{"--createconfig", "--configonly", "--importscript", zone.Zone},
+			Selected:   true,
+		})
+	}
+	return objects, nil
+}
+
+// addNameservers fills in authoritative name servers of discovered zones, so that automation reading the manifest can
+// set delegation at the registrar; name servers are assigned per contract, so they are listed once for each contract
+func addNameservers(ctx context.Context, clients Clients, zones []manifest.Object) {
+	nameservers := make(map[string][]string)
+	for i, zone := range zones {
+		servers, ok := nameservers[zone.ContractID]
+		if !ok {
+			var err error
+			servers, err = clients.DNS.GetNameServerRecordList(ctx, zone.ContractID)
+			if err != nil {
+				warnings.Report(ctx, warnings.Warning{
+					Product: ProductDNS,
+					Object:  zone.Name,
+					Reason:  fmt.Sprintf("authoritative name servers could not be listed: %s", err),
+				})
+			}
+			nameservers[zone.ContractID] = servers
+		}
+		zones[i].Nameservers = servers
+	}
+}
+
+func discoverDomains(ctx context.Context, clients Clients) ([]manifest.Object, error) {
+	domains, err := clients.GTM.ListDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]manifest.Object, 0, len(domains))
+	for _, domain := range domains {
+		objects = append(objects, manifest.Object{
+			Product:  ProductGTM,
+			Name:     domain.Name,
+			Command:  "export-domain",
+			Args:     []string{domain.Name},
+			Selected: true,
+		})
+	}
+	return objects, nil
+}
+
+func discoverPolicies(ctx context.Context, clients Clients) ([]manifest.Object, error) {
+	var objects []manifest.Object
+	pageSize, offset := tools.PageSize(tools.PageSizeCloudlets, 1000), 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		policies, err := clients.Cloudlets.ListPolicies(ctx, cloudlets.ListPoliciesRequest{
+			Offset:   offset,
+			PageSize: &pageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			supported := cloudletsprovider.IsSupported(policy.CloudletCode)
+			if !supported {
+				warnings.Report(ctx, warnings.Warning{
+					Product: ProductCloudlets,
+					Object:  policy.Name,
+					Reason:  fmt.Sprintf("cloudlet type '%s' is not supported, policy is not selected for export", policy.CloudletCode),
+				})
+			}
+			objects = append(objects, manifest.Object{
+				Product:  ProductCloudlets,
+				Name:     policy.Name,
+				ID:       strconv.FormatInt(policy.PolicyID, 10),
+				GroupID:  strconv.FormatInt(policy.GroupID, 10),
+				Command:  "export-cloudlets-policy",
+				Args:     []string{policy.Name},
+				Selected: supported,
+			})
+		}
+		if len(policies) < pageSize {
+			break
+		}
+		offset += pageSize
+	}
+	return objects, nil
+}
+
+func discoverSecurityConfigurations(ctx context.Context, clients Clients) ([]manifest.Object, error) {
+	configurations, err := clients.AppSec.GetConfigurations(ctx, appsec.GetConfigurationsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]manifest.Object, 0, len(configurations.Configurations))
+	for _, configuration := range configurations.Configurations {
+		objects = append(objects, manifest.Object{
+			Product:  ProductAppSec,
+			Name:     configuration.Name,
+			ID:       strconv.Itoa(configuration.ID),
+			Command:  "export-appsec",
+			Args:     []string{configuration.Name},
+			Selected: true,
+		})
+	}
+	return objects, nil
+}
+
+func discoverEdgeWorkers(ctx context.Context, clients Clients) ([]manifest.Object, error) {
+	edgeWorkers, err := clients.EdgeWorkers.ListEdgeWorkersID(ctx, edgeworkers.ListEdgeWorkersIDRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]manifest.Object, 0, len(edgeWorkers.EdgeWorkers))
+	for _, edgeWorker := range edgeWorkers.EdgeWorkers {
+		id := strconv.Itoa(edgeWorker.EdgeWorkerID)
+		objects = append(objects, manifest.Object{
+			Product:  ProductEdgeWorkers,
+			Name:     edgeWorker.Name,
+			ID:       id,
+			GroupID:  strconv.FormatInt(edgeWorker.GroupID, 10),
+			Command:  "export-edgeworker",
+			Args:     []string{id},
+			Selected: true,
+		})
+	}
+	return objects, nil
+}
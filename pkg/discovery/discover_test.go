@@ -0,0 +1,243 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+var (
+	pageSize = 1000
+
+	expectListPolicies = func(c *cloudlets.Mock, offset int, policies []cloudlets.Policy, err error) *mock.Call {
+		call := c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{Offset: offset, PageSize: &pageSize})
+		if err != nil {
+			return call.Return(nil, err)
+		}
+		return call.Return(policies, nil)
+	}
+
+	expectListZones = func(d *dns.Mock, zones []*dns.ZoneResponse, err error) *mock.Call {
+		call := d.On("ListZones", mock.Anything, dns.ZoneListQueryArgs{ShowAll: true})
+		if err != nil {
+			return call.Return(nil, err)
+		}
+		return call.Return(&dns.ZoneListResponse{Zones: zones}, nil)
+	}
+
+	expectGetNameServerRecordList = func(d *dns.Mock, contractID string, nameservers []string, err error) *mock.Call {
+		call := d.On("GetNameServerRecordList", mock.Anything, contractID)
+		if err != nil {
+			return call.Return(nil, err)
+		}
+		return call.Return(nameservers, nil)
+	}
+
+	expectListDomains = func(g *gtm.Mock, domains []*gtm.DomainItem, err error) *mock.Call {
+		call := g.On("ListDomains", mock.Anything)
+		if err != nil {
+			return call.Return(nil, err)
+		}
+		return call.Return(domains, nil)
+	}
+)
+
+func TestDiscover(t *testing.T) {
+	tests := map[string]struct {
+		products         []string
+		init             func(*cloudlets.Mock, *dns.Mock, *gtm.Mock)
+		expected         []manifest.Object
+		expectedWarnings int
+		withError        error
+	}{
+		"discover cloudlets policies with paging": {
+			products: []string{ProductCloudlets},
+			init: func(c *cloudlets.Mock, _ *dns.Mock, _ *gtm.Mock) {
+				policies := make([]cloudlets.Policy, pageSize)
+				for i := range policies {
+					policies[i] = cloudlets.Policy{PolicyID: int64(i), GroupID: 1, Name: fmt.Sprintf("policy_%d", i), CloudletCode: "ER"}
+				}
+				expectListPolicies(c, 0, policies, nil).Once()
+				expectListPolicies(c, pageSize, []cloudlets.Policy{{PolicyID: 5000, GroupID: 2, Name: "last_policy", CloudletCode: "ALB"}}, nil).Once()
+			},
+		},
+		"unsupported cloudlet type is not selected": {
+			products: []string{ProductCloudlets},
+			init: func(c *cloudlets.Mock, _ *dns.Mock, _ *gtm.Mock) {
+				expectListPolicies(c, 0, []cloudlets.Policy{{PolicyID: 1, GroupID: 2, Name: "test_policy", CloudletCode: "XX"}}, nil).Once()
+			},
+			expected: []manifest.Object{
+				{
+					Product:  ProductCloudlets,
+					Name:     "test_policy",
+					ID:       "1",
+					GroupID:  "2",
+					Command:  "export-cloudlets-policy",
+					Args:     []string{"test_policy"},
+					Selected: false,
+				},
+			},
+			expectedWarnings: 1,
+		},
+		"discover zones and domains": {
+			products: []string{ProductDNS, ProductGTM},
+			init: func(_ *cloudlets.Mock, d *dns.Mock, g *gtm.Mock) {
+				expectListZones(d, []*dns.ZoneResponse{{Zone: "test.zone", ContractID: "ctr_1"}, {Zone: "other.zone", ContractID: "ctr_1"}}, nil).Once()
+				expectGetNameServerRecordList(d, "ctr_1", []string{"a1-1.akam.net", "a2-2.akam.net"}, nil).Once()
+				expectListDomains(g, []*gtm.DomainItem{{Name: "test.akadns.net"}}, nil).Once()
+			},
+			expected: []manifest.Object{
+				{
+					Product:     ProductDNS,
+					Name:        "test.zone",
+					ContractID:  "ctr_1",
+					Command:     "export-zone",
+					Args:        []string{"--createconfig", "--configonly", "--importscript", "test.zone"},
+					Selected:    true,
+					Nameservers: []string{"a1-1.akam.net", "a2-2.akam.net"},
+				},
+				{
+					Product:     ProductDNS,
+					Name:        "other.zone",
+					ContractID:  "ctr_1",
+					Command:     "export-zone",
+					Args:        []string{"--createconfig", "--configonly", "--importscript", "other.zone"},
+					Selected:    true,
+					Nameservers: []string{"a1-1.akam.net", "a2-2.akam.net"},
+				},
+				{
+					Product:  ProductGTM,
+					Name:     "test.akadns.net",
+					Command:  "export-domain",
+					Args:     []string{"test.akadns.net"},
+					Selected: true,
+				},
+			},
+		},
+		"zone without name servers": {
+			products: []string{ProductDNS},
+			init: func(_ *cloudlets.Mock, d *dns.Mock, _ *gtm.Mock) {
+				expectListZones(d, []*dns.ZoneResponse{{Zone: "test.zone", ContractID: "ctr_1"}}, nil).Once()
+				expectGetNameServerRecordList(d, "ctr_1", nil, fmt.Errorf("oops")).Once()
+			},
+			expected: []manifest.Object{
+				{
+					Product:    ProductDNS,
+					Name:       "test.zone",
+					ContractID: "ctr_1",
+					Command:    "export-zone",
+					Args:       []string{"--createconfig", "--configonly", "--importscript", "test.zone"},
+					Selected:   true,
+				},
+			},
+			expectedWarnings: 1,
+		},
+		"failed product is skipped": {
+			products: []string{ProductDNS, ProductGTM},
+			init: func(_ *cloudlets.Mock, d *dns.Mock, g *gtm.Mock) {
+				expectListZones(d, nil, fmt.Errorf("oops")).Once()
+				expectListDomains(g, []*gtm.DomainItem{{Name: "test.akadns.net"}}, nil).Once()
+			},
+			expected: []manifest.Object{
+				{
+					Product:  ProductGTM,
+					Name:     "test.akadns.net",
+					Command:  "export-domain",
+					Args:     []string{"test.akadns.net"},
+					Selected: true,
+				},
+			},
+			expectedWarnings: 1,
+		},
+		"all products failed": {
+			products: []string{ProductDNS, ProductGTM},
+			init: func(_ *cloudlets.Mock, d *dns.Mock, g *gtm.Mock) {
+				expectListZones(d, nil, fmt.Errorf("oops")).Once()
+				expectListDomains(g, nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrNothingDiscovered,
+		},
+		"unsupported product": {
+			products:  []string{ProductDNS, "unknown"},
+			init:      func(_ *cloudlets.Mock, _ *dns.Mock, _ *gtm.Mock) {},
+			withError: ErrUnsupportedProduct,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mc, md, mg := new(cloudlets.Mock), new(dns.Mock), new(gtm.Mock)
+			test.init(mc, md, mg)
+			collector := warnings.NewCollector()
+			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			ctx = warnings.WithCollector(ctx, collector)
+			m, err := discover(ctx, test.products, "test_section", Clients{Cloudlets: mc, DNS: md, GTM: mg})
+			mc.AssertExpectations(t)
+			md.AssertExpectations(t)
+			mg.AssertExpectations(t)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "test_section", m.Section)
+			assert.Len(t, collector.Warnings(), test.expectedWarnings)
+			if test.expected != nil {
+				assert.Equal(t, test.expected, m.Objects)
+				return
+			}
+			assert.Len(t, m.Objects, pageSize+1)
+			assert.Equal(t, manifest.Object{
+				Product:  ProductCloudlets,
+				Name:     "last_policy",
+				ID:       "5000",
+				GroupID:  "2",
+				Command:  "export-cloudlets-policy",
+				Args:     []string{"last_policy"},
+				Selected: true,
+			}, m.Objects[pageSize])
+		})
+	}
+}
+
+func TestDiscoverProperties(t *testing.T) {
+	m := new(papi.Mock)
+	m.On("GetGroups", mock.Anything).Return(&papi.GetGroupsResponse{
+		Groups: papi.GroupItems{Items: []*papi.Group{
+			{GroupID: "grp_1", ContractIDs: []string{"ctr_1", "ctr_2"}},
+			{GroupID: "grp_2", ContractIDs: []string{"ctr_1"}},
+		}},
+	}, nil).Once()
+	expectGetProperties := func(contractID, groupID string, properties ...*papi.Property) {
+		m.On("GetProperties", mock.Anything, papi.GetPropertiesRequest{ContractID: contractID, GroupID: groupID}).
+			Return(&papi.GetPropertiesResponse{Properties: papi.PropertiesItems{Items: properties}}, nil).Once()
+	}
+	expectGetProperties("ctr_1", "grp_1", &papi.Property{PropertyID: "prp_1", PropertyName: "first", ContractID: "ctr_1", GroupID: "grp_1"})
+	expectGetProperties("ctr_2", "grp_1", &papi.Property{PropertyID: "prp_2", PropertyName: "second", ContractID: "ctr_2", GroupID: "grp_1"})
+	expectGetProperties("ctr_1", "grp_2",
+		&papi.Property{PropertyID: "prp_3", PropertyName: "third", ContractID: "ctr_1", GroupID: "grp_2"},
+		&papi.Property{PropertyID: "prp_1", PropertyName: "first", ContractID: "ctr_1", GroupID: "grp_1"})
+
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+	objects, err := discoverProperties(ctx, Clients{PAPI: m})
+	require.NoError(t, err)
+	m.AssertExpectations(t)
+
+	var names []string
+	for _, o := range objects {
+		names = append(names, o.Name)
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, names)
+}
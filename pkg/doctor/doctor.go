@@ -0,0 +1,386 @@
+// Package doctor contains code for checking credentials and API permissions needed by exports before they are run
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/appsec"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	akaedgegrid "github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/iam"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/imaging"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/providers/mtls"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// Clients groups API clients used to check access to each product API
+	Clients struct {
+		discovery.Clients
+		IAM     iam.IAM
+		Imaging imaging.Imaging
+		CPS     cps.CPS
+		MTLS    mtls.Truststore
+	}
+
+	// Result is the outcome of the check of a single product API, Err is nil if the API is accessible
+	Result struct {
+		Product string
+		Err     error
+	}
+
+	checkFunc func(context.Context, Clients) error
+)
+
+const (
+	// ProductEdgeKV is a product name used for edgekv namespaces
+	ProductEdgeKV = "edgekv"
+	// ProductIAM is a product name used for identity and access management
+	ProductIAM = "iam"
+	// ProductImaging is a product name used for image and video manager policies
+	ProductImaging = "imaging"
+	// ProductCPS is a product name used for certificate provisioning system enrollments
+	ProductCPS = "cps"
+	// ProductMTLS is a product name used for mTLS Edge TrustStore CA sets
+	ProductMTLS = "mtls-truststore"
+)
+
+var (
+	checks = map[string]checkFunc{
+		discovery.ProductProperty:    checkProperty,
+		discovery.ProductDNS:         checkDNS,
+		discovery.ProductGTM:         checkGTM,
+		discovery.ProductCloudlets:   checkCloudlets,
+		discovery.ProductAppSec:      checkAppSec,
+		discovery.ProductEdgeWorkers: checkEdgeWorkers,
+		ProductEdgeKV:                checkEdgeKV,
+		ProductIAM:                   checkIAM,
+		ProductImaging:               checkImaging,
+		ProductCPS:                   checkCPS,
+		ProductMTLS:                  checkMTLS,
+	}
+
+	// commandProducts maps export commands to products whose APIs they call
+	commandProducts = map[string][]string{
+		"export-domain":           {discovery.ProductGTM},
+		"export-zone":             {discovery.ProductDNS},
+		"export-appsec":           {discovery.ProductAppSec},
+		"export-property":         {discovery.ProductProperty},
+		"export-cloudlets-policy": {discovery.ProductCloudlets},
+		"export-load-balancer":    {discovery.ProductCloudlets},
+		"export-edgekv":           {ProductEdgeKV},
+		"export-edgeworker":       {discovery.ProductEdgeWorkers},
+		"export-iam":              {ProductIAM},
+		"export-imaging":          {ProductImaging},
+		"export-cps":              {ProductCPS},
+		"export-mtls-truststore":  {ProductMTLS},
+		"export-edgehostnames":    {discovery.ProductProperty},
+	}
+
+	// ErrInvalidCredentials is returned when the credentials section is missing or incomplete, or credentials are rejected by the API
+	ErrInvalidCredentials = exitcode.New(exitcode.Auth, "invalid credentials")
+	// ErrMissingPermissions is returned when the API client is not allowed to call APIs of some of the checked products
+	ErrMissingPermissions = exitcode.New(exitcode.Auth, "missing API permissions")
+	// ErrCheckFailed is returned when APIs of some of the checked products could not be called for other reasons
+	ErrCheckFailed = exitcode.New(exitcode.API, "API check failed")
+	// ErrUnknownCommand is returned when checks are requested for a command which is not an export command
+	ErrUnknownCommand = exitcode.New(exitcode.General, "unknown export command")
+	// ErrUnsupportedProduct is returned when checks are requested for an unknown product
+	ErrUnsupportedProduct = exitcode.New(exitcode.Unsupported, "unsupported product")
+)
+
+// Products returns names of all products which can be checked
+func Products() []string {
+	products := make([]string, 0, len(checks))
+	for name := range checks {
+		products = append(products, name)
+	}
+	sort.Strings(products)
+	return products
+}
+
+// NewClients creates API clients of all products which can be checked
+func NewClients(sess session.Session) Clients {
+	return Clients{
+		Clients: discovery.NewClients(sess),
+		IAM:     iam.Client(sess),
+		Imaging: imaging.Client(sess),
+		CPS:     cps.Client(sess),
+		MTLS:    mtls.Client(sess),
+	}
+}
+
+// CmdDoctor is an entrypoint to doctor command
+func CmdDoctor(c *cli.Context) error {
+	ctx := c.Context
+	term := terminal.Get(ctx)
+
+	products, err := productsToCheck(c)
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	term.Writeln("Checking credentials")
+	section := edgegrid.GetEdgercSection(c)
+	config, err := edgegrid.GetEdgegridConfig(c)
+	if err == nil {
+		err = CheckConfig(config)
+	}
+	// session is not put in the context for doctor, so that invalid credentials are reported instead of failing the command
+	var sess session.Session
+	if err == nil {
+		sess, err = edgegrid.NewSession(config, edgegrid.GetSessionOptions(ctx)...)
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: section '%s' of %s: %s", ErrInvalidCredentials, section, edgegrid.GetEdgercPath(c), err)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	term.Printf("  section '%s' with host %s: %s\n", section, config.Host, color.GreenString("OK"))
+
+	term.Writeln("Checking API access")
+	results := Check(ctx, products, NewClients(sess))
+	if err := Report(term, results); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	return nil
+}
+
+// CmdSections is an entrypoint to sections command
+func CmdSections(c *cli.Context) error {
+	ctx := c.Context
+	term := terminal.Get(ctx)
+
+	products, err := productsToCheck(c)
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	path := edgegrid.GetEdgercPath(c)
+	sections, err := edgegrid.Sections(path)
+	if err == nil && len(sections) == 0 {
+		err = errors.New("no sections found")
+	}
+	if err != nil {
+		err = fmt.Errorf("%w: %s: %s", ErrInvalidCredentials, path, err)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	term.Printf("Sections of %s\n", path)
+	for _, section := range sections {
+		config, err := edgegrid.GetSectionConfig(c, section)
+		if err == nil {
+			err = CheckConfig(config)
+		}
+		var sess session.Session
+		if err == nil {
+			sess, err = edgegrid.NewSession(config, edgegrid.GetSessionOptions(ctx)...)
+		}
+		if err != nil {
+			term.Printf("  %s: %s\n", section, color.RedString("invalid credentials: %s", err))
+			continue
+		}
+		granted, denied := access(Check(ctx, products, NewClients(sess)))
+		line := fmt.Sprintf("  %s (%s): ", section, config.Host)
+		if len(granted) > 0 {
+			line += color.GreenString(strings.Join(granted, ", "))
+		} else {
+			line += color.RedString("no product APIs")
+		}
+		if len(denied) > 0 {
+			line += color.RedString("; no access to %s", strings.Join(denied, ", "))
+		}
+		term.Writeln(line)
+	}
+	return nil
+}
+
+// access splits products of the results into products whose APIs could be called and products whose could not
+func access(results []Result) (granted, denied []string) {
+	for _, r := range results {
+		if r.Err == nil {
+			granted = append(granted, r.Product)
+		} else {
+			denied = append(denied, r.Product)
+		}
+	}
+	return granted, denied
+}
+
+// productsToCheck returns products given by the products flag, products used by the export command given as the argument or all products
+func productsToCheck(c *cli.Context) ([]string, error) {
+	if c.NArg() > 1 {
+		return nil, fmt.Errorf("%w: expected at most one export command, got %d arguments", ErrUnknownCommand, c.NArg())
+	}
+	if c.Args().Present() {
+		command := c.Args().First()
+		products, ok := commandProducts[strings.Replace(command, "create-", "export-", 1)]
+		if !ok {
+			commands := make([]string, 0, len(commandProducts))
+			for name := range commandProducts {
+				commands = append(commands, name)
+			}
+			sort.Strings(commands)
+			return nil, fmt.Errorf("%w: '%s', use one of: %s", ErrUnknownCommand, command, strings.Join(commands, ", "))
+		}
+		return products, nil
+	}
+	if !c.IsSet("products") {
+		return Products(), nil
+	}
+	products := tools.SplitList(c.StringSlice("products"))
+	for _, product := range products {
+		if _, ok := checks[product]; !ok {
+			return nil, fmt.Errorf("%w: '%s', use one of: %s", ErrUnsupportedProduct, product, strings.Join(Products(), ", "))
+		}
+	}
+	return products, nil
+}
+
+// CheckConfig verifies that all credentials required to sign requests are present and valid
+func CheckConfig(config *akaedgegrid.Config) error {
+	return edgegrid.ValidateConfig(config)
+}
+
+// Check calls a cheap, read-only operation of API of each product, results are returned in order of products
+func Check(ctx context.Context, products []string, clients Clients) []Result {
+	results := make([]Result, 0, len(products))
+	for _, product := range products {
+		progress.Get(ctx).Start("Checking " + product + " API")
+		err := ctx.Err()
+		if err == nil {
+			err = checks[product](ctx, clients)
+		}
+		if err != nil {
+			progress.Get(ctx).Fail()
+		} else {
+			progress.Get(ctx).OK()
+		}
+		results = append(results, Result{Product: product, Err: err})
+	}
+	return results
+}
+
+// Report writes results of the checks, the returned error describes the most severe failure
+func Report(term terminal.Terminal, results []Result) error {
+	var rejected, forbidden, failed []string
+	for _, r := range results {
+		if r.Err == nil {
+			term.Printf("  %s: %s\n", r.Product, color.GreenString("OK"))
+			continue
+		}
+		switch apierrors.StatusCode(r.Err) {
+		case http.StatusUnauthorized:
+			rejected = append(rejected, r.Product)
+			term.Printf("  %s: %s\n", r.Product, color.RedString("credentials rejected (HTTP 401), check that they are valid and not expired: %s", r.Err))
+		case http.StatusForbidden:
+			forbidden = append(forbidden, r.Product)
+			term.Printf("  %s: %s\n", r.Product, color.RedString("missing permission (HTTP 403), grant the API client READ access to the %s API: %s", r.Product, r.Err))
+		default:
+			failed = append(failed, r.Product)
+			term.Printf("  %s: %s\n", r.Product, color.RedString("request failed: %s", r.Err))
+		}
+	}
+	switch {
+	case len(rejected) > 0:
+		return fmt.Errorf("%w: rejected by %s", ErrInvalidCredentials, strings.Join(rejected, ", "))
+	case len(forbidden) > 0:
+		return fmt.Errorf("%w: %s", ErrMissingPermissions, strings.Join(forbidden, ", "))
+	case len(failed) > 0:
+		return fmt.Errorf("%w: %s", ErrCheckFailed, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func checkProperty(ctx context.Context, clients Clients) error {
+	_, err := clients.PAPI.GetContracts(ctx)
+	return err
+}
+
+func checkDNS(ctx context.Context, clients Clients) error {
+	_, err := clients.DNS.ListZones(ctx, dns.ZoneListQueryArgs{PageSize: 1})
+	return err
+}
+
+func checkGTM(ctx context.Context, clients Clients) error {
+	_, err := clients.GTM.ListDomains(ctx)
+	return err
+}
+
+func checkCloudlets(ctx context.Context, clients Clients) error {
+	pageSize := 1
+	_, err := clients.Cloudlets.ListPolicies(ctx, cloudlets.ListPoliciesRequest{PageSize: &pageSize})
+	return err
+}
+
+func checkAppSec(ctx context.Context, clients Clients) error {
+	_, err := clients.AppSec.GetConfigurations(ctx, appsec.GetConfigurationsRequest{})
+	return err
+}
+
+func checkEdgeWorkers(ctx context.Context, clients Clients) error {
+	_, err := clients.EdgeWorkers.ListEdgeWorkersID(ctx, edgeworkers.ListEdgeWorkersIDRequest{})
+	return err
+}
+
+func checkEdgeKV(ctx context.Context, clients Clients) error {
+	_, err := clients.EdgeWorkers.ListEdgeKVNamespaces(ctx, edgeworkers.ListEdgeKVNamespacesRequest{Network: edgeworkers.NamespaceStagingNetwork})
+	return err
+}
+
+func checkIAM(ctx context.Context, clients Clients) error {
+	_, err := clients.IAM.ListGroups(ctx, iam.ListGroupsRequest{})
+	return err
+}
+
+// checkImaging lists policy sets of the first contract available to the API client, as listing requires a contract
+func checkImaging(ctx context.Context, clients Clients) error {
+	contractID, err := firstContract(ctx, clients)
+	if err != nil {
+		return err
+	}
+	_, err = clients.Imaging.ListPolicySets(ctx, imaging.ListPolicySetsRequest{ContractID: contractID})
+	return err
+}
+
+// checkCPS lists enrollments of the first contract available to the API client, as listing requires a contract
+func checkCPS(ctx context.Context, clients Clients) error {
+	contractID, err := firstContract(ctx, clients)
+	if err != nil {
+		return err
+	}
+	_, err = clients.CPS.ListEnrollments(ctx, cps.ListEnrollmentsRequest{ContractID: contractID})
+	return err
+}
+
+func checkMTLS(ctx context.Context, clients Clients) error {
+	_, err := clients.MTLS.ListCASets(ctx, mtls.ListCASetsRequest{})
+	return err
+}
+
+func firstContract(ctx context.Context, clients Clients) (string, error) {
+	contracts, err := clients.PAPI.GetContracts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("contract could not be determined: %w", err)
+	}
+	if len(contracts.Contracts.Items) == 0 {
+		return "", fmt.Errorf("contract could not be determined: no contracts available")
+	}
+	return strings.TrimPrefix(contracts.Contracts.Items[0].ContractID, "ctr_"), nil
+}
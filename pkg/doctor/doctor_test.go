@@ -0,0 +1,310 @@
+package doctor
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	akaedgegrid "github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/imaging"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	edgegrid "github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/providers/mtls"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestCheckConfig(t *testing.T) {
+	valid := akaedgegrid.Config{
+		Host:         "akab-host.luna.akamaiapis.net",
+		ClientToken:  "token",
+		ClientSecret: "secret",
+		AccessToken:  "access",
+	}
+	tests := map[string]struct {
+		config    func(akaedgegrid.Config) akaedgegrid.Config
+		withError string
+	}{
+		"valid": {
+			config: func(c akaedgegrid.Config) akaedgegrid.Config { return c },
+		},
+		"missing options": {
+			config: func(c akaedgegrid.Config) akaedgegrid.Config {
+				c.ClientSecret, c.AccessToken = "", ""
+				return c
+			},
+			withError: "missing client_secret, access_token",
+		},
+		"host with scheme": {
+			config: func(c akaedgegrid.Config) akaedgegrid.Config {
+				c.Host = "https://" + c.Host
+				return c
+			},
+			withError: "host must not contain the scheme",
+		},
+		"host with trailing slash": {
+			config: func(c akaedgegrid.Config) akaedgegrid.Config {
+				c.Host += "/"
+				return c
+			},
+			withError: "host must not contain '/' at the end",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := test.config(valid)
+			err := CheckConfig(&config)
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.withError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	contracts := &papi.GetContractsResponse{Contracts: papi.ContractsItems{Items: []*papi.Contract{{ContractID: "ctr_C-1"}}}}
+	tests := map[string]struct {
+		products []string
+		init     func(*papi.Mock, *dns.Mock, *imaging.Mock)
+		expected []Result
+	}{
+		"all accessible": {
+			products: []string{discovery.ProductProperty, discovery.ProductDNS},
+			init: func(p *papi.Mock, d *dns.Mock, _ *imaging.Mock) {
+				p.On("GetContracts", mock.Anything).Return(contracts, nil).Once()
+				d.On("ListZones", mock.Anything, dns.ZoneListQueryArgs{PageSize: 1}).Return(&dns.ZoneListResponse{}, nil).Once()
+			},
+			expected: []Result{{Product: discovery.ProductProperty}, {Product: discovery.ProductDNS}},
+		},
+		"missing permission": {
+			products: []string{discovery.ProductDNS},
+			init: func(_ *papi.Mock, d *dns.Mock, _ *imaging.Mock) {
+				d.On("ListZones", mock.Anything, dns.ZoneListQueryArgs{PageSize: 1}).Return(nil, &dns.Error{StatusCode: 403}).Once()
+			},
+			expected: []Result{{Product: discovery.ProductDNS, Err: &dns.Error{StatusCode: 403}}},
+		},
+		"imaging is checked with the first contract": {
+			products: []string{ProductImaging},
+			init: func(p *papi.Mock, _ *dns.Mock, i *imaging.Mock) {
+				p.On("GetContracts", mock.Anything).Return(contracts, nil).Once()
+				i.On("ListPolicySets", mock.Anything, imaging.ListPolicySetsRequest{ContractID: "C-1"}).Return([]imaging.PolicySet{}, nil).Once()
+			},
+			expected: []Result{{Product: ProductImaging}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, d, i := &papi.Mock{}, &dns.Mock{}, &imaging.Mock{}
+			test.init(p, d, i)
+			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			clients := Clients{Clients: discovery.Clients{PAPI: p, DNS: d}, Imaging: i}
+
+			results := Check(ctx, test.products, clients)
+			assert.Equal(t, test.expected, results)
+			p.AssertExpectations(t)
+			d.AssertExpectations(t)
+			i.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	tests := map[string]struct {
+		results   []Result
+		withError error
+	}{
+		"all accessible": {
+			results: []Result{{Product: discovery.ProductProperty}, {Product: discovery.ProductDNS}},
+		},
+		"missing permission": {
+			results:   []Result{{Product: discovery.ProductProperty}, {Product: discovery.ProductDNS, Err: fmt.Errorf("list zones: %w", &dns.Error{StatusCode: 403})}},
+			withError: ErrMissingPermissions,
+		},
+		"missing permission of mTLS truststore API": {
+			results:   []Result{{Product: ProductMTLS, Err: fmt.Errorf("list CA sets: %w", &mtls.Error{StatusCode: 403})}},
+			withError: ErrMissingPermissions,
+		},
+		"rejected credentials are reported before missing permissions": {
+			results: []Result{
+				{Product: discovery.ProductProperty, Err: &papi.Error{StatusCode: 401}},
+				{Product: discovery.ProductDNS, Err: &dns.Error{StatusCode: 403}},
+			},
+			withError: ErrInvalidCredentials,
+		},
+		"other failure": {
+			results:   []Result{{Product: discovery.ProductGTM, Err: fmt.Errorf("oops")}},
+			withError: ErrCheckFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			term := &terminal.Mock{}
+			term.On("Printf", "  %s: %s\n", mock.Anything).Times(len(test.results))
+
+			err := Report(term, test.results)
+			if test.withError != nil {
+				assert.ErrorIs(t, err, test.withError)
+			} else {
+				assert.NoError(t, err)
+			}
+			term.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccess(t *testing.T) {
+	tests := map[string]struct {
+		results         []Result
+		expectedGranted []string
+		expectedDenied  []string
+	}{
+		"all accessible": {
+			results:         []Result{{Product: discovery.ProductDNS}, {Product: discovery.ProductProperty}},
+			expectedGranted: []string{discovery.ProductDNS, discovery.ProductProperty},
+		},
+		"some denied": {
+			results: []Result{
+				{Product: discovery.ProductDNS, Err: &dns.Error{StatusCode: 403}},
+				{Product: discovery.ProductProperty},
+				{Product: ProductIAM, Err: fmt.Errorf("oops")},
+			},
+			expectedGranted: []string{discovery.ProductProperty},
+			expectedDenied:  []string{discovery.ProductDNS, ProductIAM},
+		},
+		"no results": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			granted, denied := access(test.results)
+			assert.Equal(t, test.expectedGranted, granted)
+			assert.Equal(t, test.expectedDenied, denied)
+		})
+	}
+}
+
+func TestProductsToCheck(t *testing.T) {
+	tests := map[string]struct {
+		args      []string
+		expected  []string
+		withError error
+	}{
+		"all products": {
+			expected: Products(),
+		},
+		"export command": {
+			args:     []string{"export-zone"},
+			expected: []string{discovery.ProductDNS},
+		},
+		"deprecated command name": {
+			args:     []string{"create-property"},
+			expected: []string{discovery.ProductProperty},
+		},
+		"products flag": {
+			args:     []string{"--products", "cps,iam"},
+			expected: []string{ProductCPS, ProductIAM},
+		},
+		"unknown command": {
+			args:      []string{"inventory"},
+			withError: ErrUnknownCommand,
+		},
+		"too many arguments": {
+			args:      []string{"export-zone", "export-domain"},
+			withError: ErrUnknownCommand,
+		},
+		"unsupported product": {
+			args:      []string{"--products", "foo"},
+			withError: ErrUnsupportedProduct,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set := flag.NewFlagSet("doctor", flag.ContinueOnError)
+			require.NoError(t, (&cli.StringSliceFlag{Name: "products"}).Apply(set))
+			require.NoError(t, set.Parse(test.args))
+			c := cli.NewContext(cli.NewApp(), set, nil)
+
+			products, err := productsToCheck(c)
+			if test.withError != nil {
+				assert.ErrorIs(t, err, test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, products)
+		})
+	}
+}
+
+func TestCmdDoctor(t *testing.T) {
+	edgerc := filepath.Join(t.TempDir(), ".edgerc")
+	require.NoError(t, os.WriteFile(edgerc, []byte("[default]\nhost = akab-host.luna.akamaiapis.net\nclient_token = token\nclient_secret = secret\naccess_token = access\n"), 0600))
+
+	tests := map[string]struct {
+		edgerc    string
+		status    int
+		withError error
+	}{
+		"API accessible": {
+			edgerc: edgerc,
+			status: http.StatusOK,
+		},
+		"missing permissions": {
+			edgerc:    edgerc,
+			status:    http.StatusForbidden,
+			withError: ErrMissingPermissions,
+		},
+		"invalid credentials": {
+			edgerc:    filepath.Join(t.TempDir(), "missing"),
+			withError: ErrInvalidCredentials,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.status)
+				_, err := w.Write([]byte(`{"zones": []}`))
+				assert.NoError(t, err)
+			}))
+			defer srv.Close()
+			transport, err := edgegrid.RedirectTransport(srv.URL, http.DefaultTransport)
+			require.NoError(t, err)
+
+			set := flag.NewFlagSet("doctor", flag.ContinueOnError)
+			set.String("edgerc", "", "")
+			set.String("section", "", "")
+			require.NoError(t, (&cli.StringSliceFlag{Name: "products"}).Apply(set))
+			require.NoError(t, set.Parse([]string{"--edgerc", test.edgerc, "--products", discovery.ProductDNS}))
+			c := cli.NewContext(cli.NewApp(), set, nil)
+			// session is not put in the context for doctor, only options of sessions
+			c.Context = terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			c.Context = edgegrid.WithSessionOptions(c.Context, []session.Option{session.WithClient(&http.Client{Transport: transport})})
+
+			err = CmdDoctor(c)
+			if test.withError != nil {
+				assert.ErrorContains(t, err, test.withError.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
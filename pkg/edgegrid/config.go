@@ -2,16 +2,31 @@
 package edgegrid
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/mitchellh/go-homedir"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/ini.v1"
 )
 
+// ErrInvalidSection is returned when the section given with the section flag is missing or its credentials are incomplete
+var ErrInvalidSection = exitcode.New(exitcode.Auth, "invalid section")
+
 // GetEdgegridConfig gets configuration from .edgerc file
 func GetEdgegridConfig(c *cli.Context) (*edgegrid.Config, error) {
+	return GetSectionConfig(c, GetEdgercSection(c))
+}
+
+// GetSectionConfig gets configuration of the section from .edgerc file, or from environment variables of the section
+func GetSectionConfig(c *cli.Context, section string) (*edgegrid.Config, error) {
 	edgercOps := []edgegrid.Option{
 		edgegrid.WithEnv(true),
 		edgegrid.WithFile(GetEdgercPath(c)),
-		edgegrid.WithSection(GetEdgercSection(c)),
+		edgegrid.WithSection(section),
 	}
 	config, err := edgegrid.New(edgercOps...)
 	if err != nil {
@@ -40,3 +55,64 @@ func GetEdgercSection(c *cli.Context) string {
 	}
 	return edgercSection
 }
+
+// Sections returns names of sections of the edgerc file in order of the file
+func Sections(path string) ([]string, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	file, err := ini.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	var sections []string
+	for _, section := range file.Sections() {
+		if section.Name() == ini.DefaultSection && len(section.Keys()) == 0 {
+			continue
+		}
+		sections = append(sections, section.Name())
+	}
+	return sections, nil
+}
+
+// ValidateSection checks that credentials of the section given with the section flag can be loaded and are complete,
+// so that commands fail before calling any API. Sections available in the edgerc file are listed when the section is missing.
+func ValidateSection(c *cli.Context) error {
+	section, path := GetEdgercSection(c), GetEdgercPath(c)
+	config, err := GetEdgegridConfig(c)
+	if err != nil {
+		if errors.Is(err, edgegrid.ErrSectionDoesNotExist) {
+			if sections, listErr := Sections(path); listErr == nil {
+				return fmt.Errorf("%w: section '%s' not found in %s, available sections: %s", ErrInvalidSection, section, path, strings.Join(sections, ", "))
+			}
+		}
+		return fmt.Errorf("%w: section '%s' of %s: %s", ErrInvalidSection, section, path, err)
+	}
+	if err := ValidateConfig(config); err != nil {
+		return fmt.Errorf("%w: section '%s' of %s: %s", ErrInvalidSection, section, path, err)
+	}
+	return nil
+}
+
+// ValidateConfig verifies that all credentials required to sign requests are present and valid
+func ValidateConfig(config *edgegrid.Config) error {
+	missing := make([]string, 0)
+	for _, option := range []struct{ name, value string }{
+		{"host", config.Host},
+		{"client_token", config.ClientToken},
+		{"client_secret", config.ClientSecret},
+		{"access_token", config.AccessToken},
+	} {
+		if option.value == "" {
+			missing = append(missing, option.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	if strings.HasPrefix(config.Host, "http://") || strings.HasPrefix(config.Host, "https://") {
+		return fmt.Errorf("host must not contain the scheme: %q", config.Host)
+	}
+	return config.Validate()
+}
@@ -192,3 +192,75 @@ func TestGetEdgercSection(t *testing.T) {
 		})
 	}
 }
+
+func TestSections(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		expected  []string
+		withError bool
+	}{
+		"single section": {
+			path:     "./testdata/.edgerc",
+			expected: []string{"test_section"},
+		},
+		"several sections with default": {
+			path:     "./testdata/edgerc-sections",
+			expected: []string{"default", "missing_secret", "host_with_scheme"},
+		},
+		"missing file": {
+			path:      "./testdata/missing",
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sections, err := Sections(test.path)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sections)
+		})
+	}
+}
+
+func TestValidateSection(t *testing.T) {
+	tests := map[string]struct {
+		section   string
+		withError string
+	}{
+		"valid section": {
+			section: "default",
+		},
+		"missing section": {
+			section:   "prod",
+			withError: "invalid section: section 'prod' not found in ./testdata/edgerc-sections, available sections: default, missing_secret, host_with_scheme",
+		},
+		"incomplete credentials": {
+			section:   "missing_secret",
+			withError: `invalid section: section 'missing_secret' of ./testdata/edgerc-sections: unable to load config from environment or .edgerc file: required option is missing from edgerc: "client_secret"`,
+		},
+		"host with scheme": {
+			section:   "host_with_scheme",
+			withError: "host must not contain the scheme",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set := flag.NewFlagSet("test", 0)
+			set.String("edgerc", "./testdata/edgerc-sections", "")
+			set.String("section", test.section, "")
+			err := ValidateSection(cli.NewContext(cli.NewApp(), set, nil))
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidSection)
+				assert.Contains(t, err.Error(), test.withError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
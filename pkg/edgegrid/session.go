@@ -3,32 +3,65 @@ package edgegrid
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgegrid"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
 	"github.com/urfave/cli/v2"
 )
 
 type ctxType string
 
-var sessionCtx ctxType = "session"
+type roundTripperFunc func(*http.Request) (*http.Response, error)
 
-// InitializeSession prepares a session.Session interface based on edgerc config
-func InitializeSession(c *cli.Context) (session.Session, error) {
+// APIURLEnv is the name of environment variable with URL of a server to which all API requests are sent
+// instead of the host from the credentials file, it is meant for running commands against a fake API server in tests
+const APIURLEnv = "AKAMAI_TF_API_URL"
+
+var (
+	sessionCtx        ctxType = "session"
+	sessionOptionsCtx ctxType = "sessionOptions"
+)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// InitializeSession prepares a session.Session interface based on edgerc config, additional options are applied on top of the defaults
+func InitializeSession(c *cli.Context, opts ...session.Option) (session.Session, error) {
 	edgerc, err := GetEdgegridConfig(c)
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve edgegrid configuration: %s", err)
 	}
-	s, err := session.New(
-		session.WithSigner(edgerc),
+	return NewSession(edgerc, opts...)
+}
+
+// NewSession prepares a session.Session interface signing requests with the config, additional options are applied on top of the defaults
+func NewSession(config *edgegrid.Config, opts ...session.Option) (session.Session, error) {
+	opts = append([]session.Option{
+		session.WithSigner(config),
 		session.WithHTTPTracing(os.Getenv("AKAMAI_HTTP_TRACE_ENABLED") == "true"),
-	)
+	}, opts...)
+	s, err := session.New(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initialize edgegrid session: %s", err)
 	}
 	return s, nil
 }
 
+// WithSessionOptions puts options of sessions created by commands which call APIs with credentials of several sections in context
+func WithSessionOptions(ctx context.Context, opts []session.Option) context.Context {
+	return context.WithValue(ctx, sessionOptionsCtx, opts)
+}
+
+// GetSessionOptions retrieves options of sessions from context, nil is returned if there are none
+func GetSessionOptions(ctx context.Context) []session.Option {
+	opts, _ := ctx.Value(sessionOptionsCtx).([]session.Option)
+	return opts
+}
+
 // WithSession puts a session.Session in context
 func WithSession(ctx context.Context, session session.Session) context.Context {
 	return context.WithValue(ctx, sessionCtx, session)
@@ -44,3 +77,19 @@ func GetSession(ctx context.Context) session.Session {
 
 	return s
 }
+
+// RedirectTransport returns http.RoundTripper which sends all requests to the server at apiURL using the next round tripper,
+// requests are signed for the original host before they are redirected
+func RedirectTransport(apiURL string, next http.RoundTripper) (http.RoundTripper, error) {
+	target, err := url.Parse(apiURL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid %s value '%s'", APIURLEnv, apiURL)
+	}
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		r.URL.Scheme = target.Scheme
+		r.URL.Host = target.Host
+		r.Host = target.Host
+		return next.RoundTrip(r)
+	}), nil
+}
@@ -3,6 +3,9 @@ package edgegrid
 import (
 	"context"
 	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
@@ -74,3 +77,24 @@ func TestGetSession(t *testing.T) {
 	})
 
 }
+
+func TestRedirectTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(r.Method + " " + r.URL.String()))
+		assert.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	transport, err := RedirectTransport(srv.URL, http.DefaultTransport)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get("https://akab-host.luna.akamaiapis.net/edgekv/v1/networks/staging/namespaces/test?details=true")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "GET /edgekv/v1/networks/staging/namespaces/test?details=true", string(body))
+
+	_, err = RedirectTransport("localhost", http.DefaultTransport)
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+// Package exitcode defines exit codes returned by commands together with error categories they represent
+package exitcode
+
+import (
+	"context"
+	"errors"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Exit codes returned by commands
+const (
+	// OK is returned when command finished successfully
+	OK = 0
+	// General is returned for errors which do not belong to any other category, e.g. invalid arguments
+	General = 1
+	// Auth is returned when credentials could not be loaded or session could not be initialized
+	Auth = 2
+	// NotFound is returned when requested object does not exist
+	NotFound = 3
+	// Unsupported is returned when requested object cannot be exported
+	Unsupported = 4
+	// API is returned when an API request failed
+	API = 5
+	// Template is returned when generating terraform configuration from templates failed
+	Template = 6
+	// IO is returned when reading or writing local files failed
+	IO = 7
+	// Timeout is returned when the command did not finish within the time given with timeout flag, the value follows
+	// convention of the timeout utility
+	Timeout = 124
+	// Interrupted is returned when the command was interrupted with SIGINT or SIGTERM, the value follows shell convention
+	Interrupted = 130
+)
+
+var categories = map[int]string{
+	OK:          "ok",
+	General:     "general",
+	Auth:        "auth",
+	NotFound:    "not_found",
+	Unsupported: "unsupported",
+	API:         "api",
+	Template:    "template",
+	IO:          "io",
+	Timeout:     "timeout",
+	Interrupted: "interrupted",
+}
+
+// Error is an error with assigned exit code, it implements cli.ExitCoder
+// It is meant to be used for sentinel errors, so that the exit code is preserved when the error is wrapped
+type Error struct {
+	code    int
+	message string
+}
+
+// New returns a new error assigned to the given exit code
+func New(code int, message string) *Error {
+	return &Error{code: code, message: message}
+}
+
+// Error returns error message
+func (e *Error) Error() string {
+	return e.message
+}
+
+// ExitCode returns exit code assigned to the error
+func (e *Error) ExitCode() int {
+	return e.code
+}
+
+// Of returns exit code for the given error
+// Exit code of the first error in the chain which carries one is returned, Timeout is returned for an expired deadline
+// and General if there is none
+func Of(err error) int {
+	if err == nil {
+		return OK
+	}
+	var coder cli.ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout
+	}
+	return General
+}
+
+// Category returns name of the error category represented by the given exit code
+func Category(code int) string {
+	if name, ok := categories[code]; ok {
+		return name
+	}
+	return categories[General]
+}
@@ -0,0 +1,77 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+func TestOf(t *testing.T) {
+	errNotFound := New(NotFound, "object not found")
+	tests := map[string]struct {
+		err              error
+		expectedCode     int
+		expectedCategory string
+	}{
+		"no error": {
+			err:              nil,
+			expectedCode:     OK,
+			expectedCategory: "ok",
+		},
+		"plain error": {
+			err:              errors.New("oops"),
+			expectedCode:     General,
+			expectedCategory: "general",
+		},
+		"sentinel error": {
+			err:              errNotFound,
+			expectedCode:     NotFound,
+			expectedCategory: "not_found",
+		},
+		"wrapped sentinel error": {
+			err:              fmt.Errorf("%w: %s", errNotFound, "oops"),
+			expectedCode:     NotFound,
+			expectedCategory: "not_found",
+		},
+		"cli exit error": {
+			err:              cli.Exit("oops", IO),
+			expectedCode:     IO,
+			expectedCategory: "io",
+		},
+		"expired deadline": {
+			err:              fmt.Errorf("fetching policies: %w", context.DeadlineExceeded),
+			expectedCode:     Timeout,
+			expectedCategory: "timeout",
+		},
+		"interrupted": {
+			err:              cli.Exit("interrupted", Interrupted),
+			expectedCode:     Interrupted,
+			expectedCategory: "interrupted",
+		},
+		"unknown exit code": {
+			err:              cli.Exit("oops", 42),
+			expectedCode:     42,
+			expectedCategory: "general",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code := Of(test.err)
+			assert.Equal(t, test.expectedCode, code)
+			assert.Equal(t, test.expectedCategory, Category(code))
+		})
+	}
+}
+
+func TestSentinelIdentity(t *testing.T) {
+	errAPI := New(API, "api error")
+	wrapped := fmt.Errorf("%w: %s", errAPI, "oops")
+	assert.True(t, errors.Is(wrapped, errAPI))
+	assert.False(t, errors.Is(wrapped, New(API, "api error")))
+	assert.Equal(t, "api error: oops", wrapped.Error())
+}
@@ -0,0 +1,294 @@
+// Package fakedata contains code for generating fake account data, such as cloudlets policies, DNS zones, properties
+// and their rule trees, of configurable size, for benchmarks and tests which cannot use a live account
+package fakedata
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/archive"
+)
+
+// Generator generates fake account data, generators created with the same seed generate the same data when their
+// methods are called in the same order
+type Generator struct {
+	rand *rand.Rand
+	// id is the last ID given to a generated object, IDs are unique within the generator
+	id int64
+}
+
+var (
+	words = []string{"alpha", "beta", "cart", "checkout", "delta", "edge", "images", "login", "media", "mobile", "news",
+		"origin", "promo", "search", "shop", "static", "store", "video", "web", "www"}
+
+	// cloudletCodes are cloudlet types of generated policies, match rules are generated for each of them
+	cloudletCodes = []string{"ALB", "ER", "FR"}
+
+	// behaviors are generated behaviors with their options
+	behaviors = []papi.RuleBehavior{
+		{Name: "caching", Options: papi.RuleOptionsMap{"behavior": "MAX_AGE", "mustRevalidate": false, "ttl": "1d"}},
+		{Name: "gzipResponse", Options: papi.RuleOptionsMap{"behavior": "ORIGIN_RESPONSE"}},
+		{Name: "http2", Options: papi.RuleOptionsMap{"enabled": ""}},
+		{Name: "prefetch", Options: papi.RuleOptionsMap{"enabled": true}},
+		{Name: "downstreamCache", Options: papi.RuleOptionsMap{"behavior": "ALLOW", "allowBehavior": "LESSER", "sendHeaders": "CACHE_CONTROL_AND_EXPIRES", "sendPrivate": false}},
+		{Name: "modifyOutgoingResponseHeader", Options: papi.RuleOptionsMap{"action": "ADD", "customHeaderName": "X-Generated", "newHeaderValue": "true", "standardAddHeaderName": "OTHER"}},
+	}
+
+	// criteria are generated criteria with their options
+	criteria = []papi.RuleBehavior{
+		{Name: "fileExtension", Options: papi.RuleOptionsMap{"matchCaseSensitive": false, "matchOperator": "IS_ONE_OF", "values": []interface{}{"css", "js", "png"}}},
+		{Name: "path", Options: papi.RuleOptionsMap{"matchCaseSensitive": false, "matchOperator": "MATCHES_ONE_OF", "normalize": false, "values": []interface{}{"/static/*"}}},
+		{Name: "requestMethod", Options: papi.RuleOptionsMap{"matchOperator": "IS", "value": "GET"}},
+	}
+
+	// recordTypes are types of generated record sets
+	recordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT"}
+)
+
+// New returns a Generator seeded with the seed
+func New(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Policies returns n legacy cloudlets policies of ALB, ER and FR cloudlet types in up to 10 groups, about half of them
+// active on staging network
+func (g *Generator) Policies(n int) []cloudlets.Policy {
+	policies := make([]cloudlets.Policy, 0, n)
+	for i := 0; i < n; i++ {
+		policy := cloudlets.Policy{
+			PolicyID:     g.nextID(),
+			GroupID:      int64(1000 + g.rand.Intn(10)),
+			Name:         fmt.Sprintf("%s_%s_%d", g.word(), g.word(), i),
+			Description:  fmt.Sprintf("generated policy %d", i),
+			CloudletCode: cloudletCodes[g.rand.Intn(len(cloudletCodes))],
+			APIVersion:   "2.0",
+			Activations:  []cloudlets.PolicyActivation{},
+		}
+		if g.rand.Intn(2) == 0 {
+			policy.Activations = append(policy.Activations, cloudlets.PolicyActivation{
+				APIVersion:   "2.0",
+				Network:      "staging",
+				PolicyInfo:   cloudlets.PolicyInfo{PolicyID: policy.PolicyID, Name: policy.Name, Version: 1, Status: "active"},
+				PropertyInfo: cloudlets.PropertyInfo{Name: g.hostname(), Version: 1, GroupID: policy.GroupID, Status: "active"},
+			})
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// PolicyVersion returns the version of the policy with the number of match rules of the cloudlet type of the policy,
+// match rules of load balancers refer to origins named 'origin_<n>' with n below 10
+func (g *Generator) PolicyVersion(policy cloudlets.Policy, version int64, rules int) *cloudlets.PolicyVersion {
+	matchRules := make(cloudlets.MatchRules, 0, rules)
+	for i := 0; i < rules; i++ {
+		name := fmt.Sprintf("rule %d", i)
+		path := fmt.Sprintf("/%s/%d", g.word(), i)
+		matches := []cloudlets.MatchCriteria{{MatchType: "path", MatchValue: path, MatchOperator: "equals"}}
+		if g.rand.Intn(3) == 0 {
+			matches = append(matches, cloudlets.MatchCriteria{MatchType: "header", MatchOperator: "equals", ObjectMatchValue: &cloudlets.ObjectMatchValueObject{
+				Type:    cloudlets.Object,
+				Name:    "X-Generated",
+				Options: &cloudlets.Options{Value: []string{g.word()}},
+			}})
+		}
+		switch policy.CloudletCode {
+		case "ALB":
+			matchRules = append(matchRules, &cloudlets.MatchRuleALB{
+				Name:            name,
+				Type:            cloudlets.MatchRuleTypeALB,
+				Matches:         toALB(matches),
+				ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: fmt.Sprintf("origin_%d", g.rand.Intn(10))},
+			})
+		case "FR":
+			matchRules = append(matchRules, &cloudlets.MatchRuleFR{
+				Name:            name,
+				Type:            cloudlets.MatchRuleTypeFR,
+				Matches:         toFR(matches),
+				ForwardSettings: cloudlets.ForwardSettingsFR{PathAndQS: "/new" + path},
+			})
+		default:
+			matchRules = append(matchRules, &cloudlets.MatchRuleER{
+				Name:        name,
+				Type:        cloudlets.MatchRuleTypeER,
+				MatchURL:    "https://" + g.hostname() + path,
+				StatusCode:  301,
+				RedirectURL: "https://" + g.hostname() + "/",
+			})
+		}
+	}
+	return &cloudlets.PolicyVersion{
+		PolicyID:        policy.PolicyID,
+		Version:         version,
+		Description:     fmt.Sprintf("version %d of %s", version, policy.Name),
+		CreatedBy:       "generator",
+		RevisionID:      g.nextID(),
+		MatchRuleFormat: "1.0",
+		MatchRules:      matchRules,
+	}
+}
+
+// Zone returns the primary zone and the number of its record sets, besides the SOA and NS record sets of the zone
+func (g *Generator) Zone(name string, records int) (*dns.ZoneResponse, []dns.Recordset) {
+	zone := &dns.ZoneResponse{
+		Zone:            name,
+		Type:            "PRIMARY",
+		ContractID:      "ctr_1-1TJZFW",
+		ActivationState: "ACTIVE",
+	}
+	recordsets := []dns.Recordset{
+		{Name: name, Type: "SOA", TTL: 86400, Rdata: []string{fmt.Sprintf("a1-1.akam.net. hostmaster.%s. 1 3600 600 604800 300", name)}},
+		{Name: name, Type: "NS", TTL: 86400, Rdata: []string{"a1-1.akam.net.", "a2-2.akam.net."}},
+	}
+	for i := 0; i < records; i++ {
+		recordset := dns.Recordset{
+			Name: fmt.Sprintf("%s%d.%s", g.word(), i, name),
+			Type: recordTypes[g.rand.Intn(len(recordTypes))],
+			TTL:  300 * (1 + g.rand.Intn(12)),
+		}
+		switch recordset.Type {
+		case "A":
+			recordset.Rdata = []string{fmt.Sprintf("192.0.2.%d", 1+g.rand.Intn(254))}
+		case "AAAA":
+			recordset.Rdata = []string{fmt.Sprintf("2001:db8::%x", 1+g.rand.Intn(0xfffe))}
+		case "CNAME":
+			recordset.Rdata = []string{g.hostname() + ".edgesuite.net."}
+		case "MX":
+			recordset.Rdata = []string{fmt.Sprintf("10 mail%d.%s.", i, name)}
+		default:
+			recordset.Rdata = []string{fmt.Sprintf("\"generated=%s\"", g.word())}
+		}
+		recordsets = append(recordsets, recordset)
+	}
+	return zone, recordsets
+}
+
+// Property returns the property with its latest version active on staging network
+func (g *Generator) Property(name string) *papi.Property {
+	latest := 1 + g.rand.Intn(20)
+	return &papi.Property{
+		AccountID:      "act_1-1TJZFB",
+		AssetID:        fmt.Sprintf("aid_%d", g.nextID()),
+		ContractID:     "ctr_1-1TJZFW",
+		GroupID:        fmt.Sprintf("grp_%d", 1000+g.rand.Intn(10)),
+		LatestVersion:  latest,
+		ProductID:      "prd_Fresca",
+		PropertyID:     fmt.Sprintf("prp_%d", g.nextID()),
+		PropertyName:   name,
+		RuleFormat:     "v2023-01-05",
+		StagingVersion: &latest,
+	}
+}
+
+// RuleTree returns the default rule of a rule tree of the depth, in which each rule above the depth has the number of
+// children, and each rule has up to the number of behaviors; child rules have criteria
+func (g *Generator) RuleTree(depth, children, behaviorsPerRule int) papi.Rules {
+	rules := g.rule("default", depth, children, behaviorsPerRule)
+	rules.Criteria = nil
+	rules.Behaviors = append([]papi.RuleBehavior{{Name: "origin", Options: papi.RuleOptionsMap{
+		"originType":         "CUSTOMER",
+		"hostname":           "origin." + g.hostname(),
+		"forwardHostHeader":  "REQUEST_HOST_HEADER",
+		"cacheKeyHostname":   "ORIGIN_HOSTNAME",
+		"httpPort":           float64(80),
+		"httpsPort":          float64(443),
+		"verificationMode":   "PLATFORM_SETTINGS",
+		"compress":           true,
+		"enableTrueClientIp": false,
+	}}}, rules.Behaviors...)
+	rules.Variables = []papi.RuleVariable{{Name: "PMUSER_GENERATED", Value: g.word(), Hidden: false, Sensitive: false}}
+	return rules
+}
+
+func (g *Generator) rule(name string, depth, children, behaviorsPerRule int) papi.Rules {
+	rules := papi.Rules{
+		Name:                name,
+		Comments:            fmt.Sprintf("generated rule %s", name),
+		CriteriaMustSatisfy: papi.RuleCriteriaMustSatisfyAll,
+		Criteria:            []papi.RuleBehavior{copyBehavior(criteria[g.rand.Intn(len(criteria))])},
+	}
+	for _, i := range g.rand.Perm(len(behaviors))[:min(behaviorsPerRule, len(behaviors))] {
+		rules.Behaviors = append(rules.Behaviors, copyBehavior(behaviors[i]))
+	}
+	if depth > 1 {
+		for i := 0; i < children; i++ {
+			rules.Children = append(rules.Children, g.rule(fmt.Sprintf("%s %d", title(g.word()), i), depth-1, children, behaviorsPerRule))
+		}
+	}
+	return rules
+}
+
+// Response returns the response to the request in the format of responses recorded with --archive-api-responses flag,
+// which is used by fixtures of testutils.NewAPIServer; the body is marshaled into JSON
+func Response(method, url string, statusCode int, body interface{}) (archive.Response, error) {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return archive.Response{}, err
+	}
+	return archive.Response{Method: method, URL: url, StatusCode: statusCode, Body: string(content)}, nil
+}
+
+// WriteFixture writes the responses into the fixture file at path
+func WriteFixture(path string, responses []archive.Response) error {
+	content, err := json.MarshalIndent(responses, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+func (g *Generator) nextID() int64 {
+	g.id++
+	return g.id
+}
+
+func (g *Generator) word() string {
+	return words[g.rand.Intn(len(words))]
+}
+
+func (g *Generator) hostname() string {
+	return fmt.Sprintf("%s.%s.example.com", g.word(), g.word())
+}
+
+// copyBehavior returns copy of the behavior, whose options can be changed without changing the original
+func copyBehavior(behavior papi.RuleBehavior) papi.RuleBehavior {
+	options := make(papi.RuleOptionsMap, len(behavior.Options))
+	for k, v := range behavior.Options {
+		options[k] = v
+	}
+	behavior.Options = options
+	return behavior
+}
+
+func toALB(matches []cloudlets.MatchCriteria) []cloudlets.MatchCriteriaALB {
+	result := make([]cloudlets.MatchCriteriaALB, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, cloudlets.MatchCriteriaALB(match))
+	}
+	return result
+}
+
+func toFR(matches []cloudlets.MatchCriteria) []cloudlets.MatchCriteriaFR {
+	result := make([]cloudlets.MatchCriteriaFR, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, cloudlets.MatchCriteriaFR(match))
+	}
+	return result
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func title(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
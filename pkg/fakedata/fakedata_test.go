@@ -0,0 +1,137 @@
+package fakedata
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/archive"
+	"github.com/akamai/cli-terraform/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameSeed(t *testing.T) {
+	generate := func(seed int64) ([]cloudlets.Policy, papi.Rules, []dns.Recordset) {
+		g := New(seed)
+		policies := g.Policies(20)
+		rules := g.RuleTree(3, 2, 3)
+		_, recordsets := g.Zone("example.com", 20)
+		return policies, rules, recordsets
+	}
+	policies, rules, recordsets := generate(1)
+	samePolicies, sameRules, sameRecordsets := generate(1)
+	otherPolicies, _, _ := generate(2)
+
+	assert.Equal(t, policies, samePolicies)
+	assert.Equal(t, rules, sameRules)
+	assert.Equal(t, recordsets, sameRecordsets)
+	assert.NotEqual(t, policies, otherPolicies)
+}
+
+func TestPolicies(t *testing.T) {
+	policies := New(1).Policies(100)
+	require.Len(t, policies, 100)
+
+	ids := make(map[int64]bool)
+	names := make(map[string]bool)
+	for _, policy := range policies {
+		assert.False(t, ids[policy.PolicyID], "duplicate ID %d", policy.PolicyID)
+		assert.False(t, names[policy.Name], "duplicate name %s", policy.Name)
+		ids[policy.PolicyID], names[policy.Name] = true, true
+		assert.Contains(t, cloudletCodes, policy.CloudletCode)
+		for _, activation := range policy.Activations {
+			assert.Equal(t, policy.PolicyID, activation.PolicyInfo.PolicyID)
+		}
+	}
+}
+
+func TestPolicyVersion(t *testing.T) {
+	g := New(1)
+	for _, code := range cloudletCodes {
+		t.Run(code, func(t *testing.T) {
+			policy := cloudlets.Policy{PolicyID: 1, Name: "policy", CloudletCode: code}
+			version := g.PolicyVersion(policy, 3, 50)
+			assert.Equal(t, int64(3), version.Version)
+			require.Len(t, version.MatchRules, 50)
+
+			// generated versions are valid responses of the API
+			content, err := json.Marshal(version)
+			require.NoError(t, err)
+			var parsed cloudlets.PolicyVersion
+			require.NoError(t, json.Unmarshal(content, &parsed))
+			assert.Equal(t, version.MatchRules, parsed.MatchRules)
+		})
+	}
+}
+
+func TestZone(t *testing.T) {
+	zone, recordsets := New(1).Zone("example.com", 500)
+	assert.Equal(t, "example.com", zone.Zone)
+	require.Len(t, recordsets, 502)
+	assert.Equal(t, "SOA", recordsets[0].Type)
+	assert.Equal(t, "NS", recordsets[1].Type)
+	for _, recordset := range recordsets[2:] {
+		assert.Contains(t, recordTypes, recordset.Type)
+		assert.NotEmpty(t, recordset.Rdata)
+	}
+}
+
+func TestRuleTree(t *testing.T) {
+	tests := map[string]struct {
+		depth, children, behaviors int
+		expectedRules              int
+	}{
+		"default rule only": {depth: 1, children: 5, behaviors: 2, expectedRules: 1},
+		"three levels":      {depth: 3, children: 4, behaviors: 3, expectedRules: 1 + 4 + 16},
+		"more behaviors than available": {
+			depth: 2, children: 2, behaviors: 100, expectedRules: 3,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rules := New(1).RuleTree(test.depth, test.children, test.behaviors)
+			assert.Equal(t, "default", rules.Name)
+			assert.Empty(t, rules.Criteria)
+			assert.Equal(t, "origin", rules.Behaviors[0].Name)
+			assert.Equal(t, test.expectedRules, countRules(rules))
+
+			_, err := json.Marshal(rules)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWriteFixture(t *testing.T) {
+	policies := New(1).Policies(3)
+	response, err := Response(http.MethodGet, "https://host/cloudlets/api/v2/policies", http.StatusOK, policies)
+	require.NoError(t, err)
+	fixture := filepath.Join(t.TempDir(), "api-responses.json")
+	require.NoError(t, WriteFixture(fixture, []archive.Response{response}))
+
+	srv := testutils.NewAPIServer(t, fixture)
+	resp, err := http.Get(srv.URL + "/cloudlets/api/v2/policies")
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var parsed []cloudlets.Policy
+	require.NoError(t, json.Unmarshal(body, &parsed))
+	assert.Equal(t, policies, parsed)
+}
+
+func countRules(rules papi.Rules) int {
+	n := 1
+	for _, child := range rules.Children {
+		n += countRules(child)
+	}
+	return n
+}
@@ -0,0 +1,196 @@
+// Package identity resolves contracts and groups given in flags and arguments by their ID or human-readable name
+package identity
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+)
+
+type (
+	// Contract is a contract of the account
+	Contract struct {
+		ID string
+		// Name is the contract type name, the only name of a contract returned by the API, empty when it is not known
+		Name string
+	}
+
+	// Group is a group of the account
+	Group struct {
+		ID string
+		// Name is empty when it is not known
+		Name        string
+		ContractIDs []string
+	}
+
+	// Resolver resolves contracts and groups using contracts and groups accessible to the API client. Both are fetched
+	// at most once.
+	Resolver struct {
+		client    papi.PAPI
+		contracts []Contract
+		groups    []Group
+	}
+)
+
+var (
+	// ErrFetching is returned when contracts or groups could not be fetched to resolve a name
+	ErrFetching = exitcode.New(exitcode.API, "fetching contracts and groups")
+	// ErrNotFound is returned when no contract or group has the given name
+	ErrNotFound = exitcode.New(exitcode.NotFound, "contract or group not found")
+	// ErrAmbiguous is returned when more than one contract or group has the given name
+	ErrAmbiguous = exitcode.New(exitcode.General, "ambiguous name, use ID instead")
+
+	contractIDRegexp = regexp.MustCompile(`^(ctr_)?[0-9A-Z]-[0-9A-Z]+$`)
+	groupIDRegexp    = regexp.MustCompile(`^(grp_)?[0-9]+$`)
+)
+
+// NewResolver returns a resolver of contracts and groups using given client
+func NewResolver(client papi.PAPI) *Resolver {
+	return &Resolver{client: client}
+}
+
+// Contract returns the contract with given ID, with or without ctr_ prefix, or name. The ID is returned as given, as
+// APIs differ in whether they expect the prefix. Name of the contract given by ID is only informative, so it is left
+// empty when contracts cannot be fetched or the contract is not among them.
+func (r *Resolver) Contract(ctx context.Context, value string) (Contract, error) {
+	value = strings.TrimSpace(value)
+	if contractIDRegexp.MatchString(value) {
+		contract := Contract{ID: value}
+		contracts, err := r.getContracts(ctx)
+		if err != nil {
+			reportUnknownName(ctx, "contract", value, err)
+			return contract, nil
+		}
+		for _, c := range contracts {
+			if c.ID == withPrefix(value, "ctr_") {
+				contract.Name = c.Name
+			}
+		}
+		return contract, nil
+	}
+
+	contracts, err := r.getContracts(ctx)
+	if err != nil {
+		return Contract{}, fmt.Errorf("%w: %s", ErrFetching, err)
+	}
+	var matches []Contract
+	var ids []string
+	for _, c := range contracts {
+		if strings.EqualFold(c.Name, value) {
+			matches = append(matches, c)
+			ids = append(ids, c.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Contract{}, fmt.Errorf("%w: contract '%s'", ErrNotFound, value)
+	case 1:
+		return matches[0], nil
+	default:
+		return Contract{}, fmt.Errorf("%w: contract '%s' matches %s", ErrAmbiguous, value, strings.Join(ids, ", "))
+	}
+}
+
+// Group returns the group with given ID, with or without grp_ prefix, or name. Groups are looked up among groups of the
+// contract when contractID is not empty. As for contracts, the ID is returned as given and the name is only informative.
+func (r *Resolver) Group(ctx context.Context, value, contractID string) (Group, error) {
+	value = strings.TrimSpace(value)
+	if groupIDRegexp.MatchString(value) {
+		group := Group{ID: value}
+		groups, err := r.getGroups(ctx)
+		if err != nil {
+			reportUnknownName(ctx, "group", value, err)
+			return group, nil
+		}
+		for _, g := range groups {
+			if g.ID == withPrefix(value, "grp_") {
+				group.Name, group.ContractIDs = g.Name, g.ContractIDs
+			}
+		}
+		return group, nil
+	}
+
+	groups, err := r.getGroups(ctx)
+	if err != nil {
+		return Group{}, fmt.Errorf("%w: %s", ErrFetching, err)
+	}
+	var matches []Group
+	var ids []string
+	for _, g := range groups {
+		if strings.EqualFold(g.Name, value) && (contractID == "" || hasContract(g, contractID)) {
+			matches = append(matches, g)
+			ids = append(ids, g.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Group{}, fmt.Errorf("%w: group '%s'", ErrNotFound, value)
+	case 1:
+		return matches[0], nil
+	default:
+		return Group{}, fmt.Errorf("%w: group '%s' matches %s", ErrAmbiguous, value, strings.Join(ids, ", "))
+	}
+}
+
+func (r *Resolver) getContracts(ctx context.Context) ([]Contract, error) {
+	if r.contracts != nil {
+		return r.contracts, nil
+	}
+	response, err := r.client.GetContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contracts := make([]Contract, 0, len(response.Contracts.Items))
+	for _, c := range response.Contracts.Items {
+		contracts = append(contracts, Contract{ID: c.ContractID, Name: c.ContractTypeName})
+	}
+	r.contracts = contracts
+	return contracts, nil
+}
+
+func (r *Resolver) getGroups(ctx context.Context) ([]Group, error) {
+	if r.groups != nil {
+		return r.groups, nil
+	}
+	response, err := r.client.GetGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]Group, 0, len(response.Groups.Items))
+	for _, g := range response.Groups.Items {
+		groups = append(groups, Group{ID: g.GroupID, Name: g.GroupName, ContractIDs: g.ContractIDs})
+	}
+	r.groups = groups
+	return groups, nil
+}
+
+// hasContract returns true if the group belongs to the contract given with or without ctr_ prefix
+func hasContract(group Group, contractID string) bool {
+	for _, id := range group.ContractIDs {
+		if id == withPrefix(contractID, "ctr_") {
+			return true
+		}
+	}
+	return false
+}
+
+// reportUnknownName warns that the name of the object given by ID will be missing from the configuration
+func reportUnknownName(ctx context.Context, product, id string, err error) {
+	warnings.Report(ctx, warnings.Warning{
+		Product: product,
+		Object:  id,
+		Reason:  fmt.Sprintf("name could not be resolved, it is omitted from the configuration: %s", err),
+	})
+}
+
+func withPrefix(id, prefix string) string {
+	if strings.HasPrefix(id, prefix) {
+		return id
+	}
+	return prefix + id
+}
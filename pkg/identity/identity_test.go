@@ -0,0 +1,217 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	getContractsResponse = papi.GetContractsResponse{
+		Contracts: papi.ContractsItems{Items: []*papi.Contract{
+			{ContractID: "ctr_1-AB123", ContractTypeName: "DIRECT_CUSTOMER"},
+			{ContractID: "ctr_2-CD456", ContractTypeName: "INDIRECT_CUSTOMER"},
+			{ContractID: "ctr_3-EF789", ContractTypeName: "INDIRECT_CUSTOMER"},
+		}},
+	}
+
+	getGroupsResponse = papi.GetGroupsResponse{
+		Groups: papi.GroupItems{Items: []*papi.Group{
+			{GroupID: "grp_1", GroupName: "Production", ContractIDs: []string{"ctr_1-AB123"}},
+			{GroupID: "grp_2", GroupName: "Staging", ContractIDs: []string{"ctr_1-AB123"}},
+			{GroupID: "grp_3", GroupName: "Staging", ContractIDs: []string{"ctr_2-CD456"}},
+		}},
+	}
+)
+
+func TestContract(t *testing.T) {
+	tests := map[string]struct {
+		value            string
+		init             func(*papi.Mock)
+		expected         Contract
+		expectedWarnings []warnings.Warning
+		withError        error
+	}{
+		"ID with prefix": {
+			value: "ctr_1-AB123",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(&getContractsResponse, nil).Once()
+			},
+			expected: Contract{ID: "ctr_1-AB123", Name: "DIRECT_CUSTOMER"},
+		},
+		"ID without prefix is returned as given": {
+			value: "1-AB123",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(&getContractsResponse, nil).Once()
+			},
+			expected: Contract{ID: "1-AB123", Name: "DIRECT_CUSTOMER"},
+		},
+		"ID of contract not listed": {
+			value: "ctr_9-ZZ999",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(&getContractsResponse, nil).Once()
+			},
+			expected: Contract{ID: "ctr_9-ZZ999"},
+		},
+		"ID when contracts cannot be fetched": {
+			value: "ctr_1-AB123",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			expected: Contract{ID: "ctr_1-AB123"},
+			expectedWarnings: []warnings.Warning{
+				{Product: "contract", Object: "ctr_1-AB123", Reason: "name could not be resolved, it is omitted from the configuration: oops"},
+			},
+		},
+		"name": {
+			value: "direct_customer",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(&getContractsResponse, nil).Once()
+			},
+			expected: Contract{ID: "ctr_1-AB123", Name: "DIRECT_CUSTOMER"},
+		},
+		"ambiguous name": {
+			value: "INDIRECT_CUSTOMER",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(&getContractsResponse, nil).Once()
+			},
+			withError: ErrAmbiguous,
+		},
+		"name not found": {
+			value: "RESELLER",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(&getContractsResponse, nil).Once()
+			},
+			withError: ErrNotFound,
+		},
+		"name when contracts cannot be fetched": {
+			value: "DIRECT_CUSTOMER",
+			init: func(c *papi.Mock) {
+				c.On("GetContracts", mock.Anything).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetching,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := new(papi.Mock)
+			test.init(client)
+			collector := warnings.NewCollector()
+			ctx := warnings.WithCollector(context.Background(), collector)
+			contract, err := NewResolver(client).Contract(ctx, test.value)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, contract)
+			assert.ElementsMatch(t, test.expectedWarnings, collector.Warnings())
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	tests := map[string]struct {
+		value      string
+		contractID string
+		init       func(*papi.Mock)
+		expected   Group
+		withError  error
+	}{
+		"ID without prefix": {
+			value: "1",
+			init: func(c *papi.Mock) {
+				c.On("GetGroups", mock.Anything).Return(&getGroupsResponse, nil).Once()
+			},
+			expected: Group{ID: "1", Name: "Production", ContractIDs: []string{"ctr_1-AB123"}},
+		},
+		"ID when groups cannot be fetched": {
+			value: "grp_1",
+			init: func(c *papi.Mock) {
+				c.On("GetGroups", mock.Anything).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			expected: Group{ID: "grp_1"},
+		},
+		"name": {
+			value: "production",
+			init: func(c *papi.Mock) {
+				c.On("GetGroups", mock.Anything).Return(&getGroupsResponse, nil).Once()
+			},
+			expected: Group{ID: "grp_1", Name: "Production", ContractIDs: []string{"ctr_1-AB123"}},
+		},
+		"name in contract": {
+			value:      "Staging",
+			contractID: "2-CD456",
+			init: func(c *papi.Mock) {
+				c.On("GetGroups", mock.Anything).Return(&getGroupsResponse, nil).Once()
+			},
+			expected: Group{ID: "grp_3", Name: "Staging", ContractIDs: []string{"ctr_2-CD456"}},
+		},
+		"ambiguous name": {
+			value: "Staging",
+			init: func(c *papi.Mock) {
+				c.On("GetGroups", mock.Anything).Return(&getGroupsResponse, nil).Once()
+			},
+			withError: ErrAmbiguous,
+		},
+		"name not found in contract": {
+			value:      "Production",
+			contractID: "ctr_2-CD456",
+			init: func(c *papi.Mock) {
+				c.On("GetGroups", mock.Anything).Return(&getGroupsResponse, nil).Once()
+			},
+			withError: ErrNotFound,
+		},
+		"name when groups cannot be fetched": {
+			value: "Production",
+			init: func(c *papi.Mock) {
+				c.On("GetGroups", mock.Anything).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetching,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := new(papi.Mock)
+			test.init(client)
+			ctx := warnings.WithCollector(context.Background(), warnings.NewCollector())
+			group, err := NewResolver(client).Group(ctx, test.value, test.contractID)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, group)
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolverFetchesOnce(t *testing.T) {
+	client := new(papi.Mock)
+	client.On("GetContracts", mock.Anything).Return(&getContractsResponse, nil).Once()
+	client.On("GetGroups", mock.Anything).Return(&getGroupsResponse, nil).Once()
+	resolver := NewResolver(client)
+
+	contract, err := resolver.Contract(context.Background(), "ctr_1-AB123")
+	require.NoError(t, err)
+	_, err = resolver.Contract(context.Background(), "DIRECT_CUSTOMER")
+	require.NoError(t, err)
+	group, err := resolver.Group(context.Background(), "Staging", contract.ID)
+	require.NoError(t, err)
+	_, err = resolver.Group(context.Background(), "grp_2", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "grp_2", group.ID)
+	client.AssertExpectations(t)
+}
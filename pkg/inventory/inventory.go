@@ -0,0 +1,361 @@
+// Package inventory contains code for reporting exportable objects and their sizes without generating Terraform configuration
+package inventory
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/appsec"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// Item is a single exportable object with its size
+	Item struct {
+		Product string `json:"product"`
+		Name    string `json:"name"`
+		ID      string `json:"id,omitempty"`
+		// Metric describes what Size counts, e.g. rules of a property or recordsets of a zone
+		Metric string `json:"metric"`
+		// Size is nil when it could not be measured
+		Size *int `json:"size"`
+	}
+
+	// sizer measures the size of an object of a single product
+	sizer struct {
+		metric  string
+		measure func(context.Context, discovery.Clients, manifest.Object) (int, error)
+	}
+)
+
+const (
+	// FormatTable is the default, human readable report format
+	FormatTable = "table"
+	// FormatJSON formats the report as JSON array of items
+	FormatJSON = "json"
+	// FormatCSV formats the report as CSV with a header row
+	FormatCSV = "csv"
+)
+
+var (
+	sizers = map[string]sizer{
+		discovery.ProductProperty:    {metric: "rules", measure: countRules},
+		discovery.ProductDNS:         {metric: "recordsets", measure: countRecordsets},
+		discovery.ProductGTM:         {metric: "properties", measure: countGTMProperties},
+		discovery.ProductCloudlets:   {metric: "versions", measure: countPolicyVersions},
+		discovery.ProductAppSec:      {metric: "versions", measure: countConfigurationVersions},
+		discovery.ProductEdgeWorkers: {metric: "versions", measure: countEdgeWorkerVersions},
+	}
+
+	// ErrInvalidFormat is returned when the requested report format is not supported
+	ErrInvalidFormat = exitcode.New(exitcode.General, "invalid report format")
+	// ErrNothingListed is returned when objects could not be listed for any of the requested products
+	ErrNothingListed = exitcode.New(exitcode.API, "unable to list any objects")
+	// ErrWritingReport is returned when the report cannot be written
+	ErrWritingReport = exitcode.New(exitcode.IO, "unable to write inventory report")
+)
+
+// CmdInventory is an entrypoint to inventory command
+func CmdInventory(c *cli.Context) error {
+	ctx := c.Context
+
+	// format flag is not named json, as it would shadow the global flag printing the summary of the run
+	format := FormatTable
+	if c.IsSet("format") {
+		format = c.String("format")
+	}
+	if format != FormatTable && format != FormatJSON && format != FormatCSV {
+		err := fmt.Errorf("%w: '%s'", ErrInvalidFormat, format)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	clients := discovery.NewClients(edgegrid.GetSession(ctx))
+	products := discovery.Products()
+	if c.IsSet("products") {
+		products = tools.SplitList(c.StringSlice("products"))
+	}
+
+	items, err := List(ctx, products, clients)
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error listing account objects: %s", err)), exitcode.Of(err))
+	}
+
+	var w io.Writer = c.App.Writer
+	if c.IsSet("output") {
+		f, err := os.Create(c.String("output"))
+		if err != nil {
+			err = fmt.Errorf("%w: %s", ErrWritingReport, err)
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+	if err := Write(w, format, items); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	return nil
+}
+
+// List returns exportable objects of given products with their sizes. Products which cannot be listed
+// and objects which cannot be measured are reported as warnings, the latter are returned without size.
+func List(ctx context.Context, products []string, clients discovery.Clients) ([]Item, error) {
+	for _, product := range products {
+		if _, ok := sizers[product]; !ok {
+			return nil, fmt.Errorf("%w: '%s', use one of: %s", discovery.ErrUnsupportedProduct, product, strings.Join(discovery.Products(), ", "))
+		}
+	}
+
+	var items []Item
+	var failed int
+	for _, product := range products {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		progress.Get(ctx).Start("Listing " + product + " objects")
+		objects, err := discovery.List(ctx, product, clients)
+		if err != nil {
+			progress.Get(ctx).Fail()
+			warnings.Report(ctx, warnings.Warning{
+				Product: product,
+				Reason:  fmt.Sprintf("objects could not be listed: %s", err),
+			})
+			failed++
+			continue
+		}
+		progress.Get(ctx).OK()
+
+		progress.Get(ctx).Start("Measuring " + product + " objects")
+		measured, err := measure(ctx, product, clients, objects)
+		if err != nil {
+			progress.Get(ctx).Fail()
+			return nil, err
+		}
+		progress.Get(ctx).OK()
+		items = append(items, measured...)
+	}
+	if failed > 0 && failed == len(products) {
+		return nil, ErrNothingListed
+	}
+	return items, nil
+}
+
+// measure fetches sizes of objects concurrently, results are stored per object to keep the output order deterministic
+func measure(ctx context.Context, product string, clients discovery.Clients, objects []manifest.Object) ([]Item, error) {
+	s := sizers[product]
+	items := make([]Item, len(objects))
+	progress.Get(ctx).Total(len(objects))
+	err := tools.RunConcurrently(ctx, len(objects), func(ctx context.Context, i int) error {
+		object := objects[i]
+		items[i] = Item{Product: product, Name: object.Name, ID: object.ID, Metric: s.metric}
+		size, err := s.measure(ctx, clients, object)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			warnings.Report(ctx, warnings.Warning{
+				Product: product,
+				Object:  object.Name,
+				Reason:  fmt.Sprintf("%s could not be counted: %s", s.metric, err),
+			})
+		} else {
+			items[i].Size = &size
+		}
+		progress.Get(ctx).Step()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Write writes items to w in the given format
+func Write(w io.Writer, format string, items []Item) error {
+	var err error
+	switch format {
+	case FormatJSON:
+		err = writeJSON(w, items)
+	case FormatCSV:
+		err = writeCSV(w, items)
+	case FormatTable:
+		err = writeTable(w, items)
+	default:
+		return fmt.Errorf("%w: '%s'", ErrInvalidFormat, format)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWritingReport, err)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, items []Item) error {
+	if items == nil {
+		items = []Item{}
+	}
+	content, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(content))
+	return err
+}
+
+func writeCSV(w io.Writer, items []Item) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"product", "name", "id", "metric", "size"}); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := cw.Write([]string{item.Product, item.Name, item.ID, item.Metric, formatSize(item.Size, "")}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTable(w io.Writer, items []Item) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PRODUCT\tNAME\tID\tSIZE")
+	totals := make(map[string]int)
+	var products []string
+	for _, item := range items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s %s\n", item.Product, item.Name, item.ID, formatSize(item.Size, "?"), item.Metric)
+		if _, ok := totals[item.Product]; !ok {
+			products = append(products, item.Product)
+		}
+		totals[item.Product]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	summary := make([]string, 0, len(products))
+	for _, product := range products {
+		summary = append(summary, fmt.Sprintf("%s: %d", product, totals[product]))
+	}
+	_, err := fmt.Fprintf(w, "\nTotal %d objects (%s)\n", len(items), strings.Join(summary, ", "))
+	return err
+}
+
+func formatSize(size *int, unknown string) string {
+	if size == nil {
+		return unknown
+	}
+	return strconv.Itoa(*size)
+}
+
+func countRules(ctx context.Context, clients discovery.Clients, object manifest.Object) (int, error) {
+	property, err := clients.PAPI.GetProperty(ctx, papi.GetPropertyRequest{
+		ContractID: object.ContractID,
+		GroupID:    object.GroupID,
+		PropertyID: object.ID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	ruleTree, err := clients.PAPI.GetRuleTree(ctx, papi.GetRuleTreeRequest{
+		PropertyID:      object.ID,
+		PropertyVersion: property.Property.LatestVersion,
+		ContractID:      object.ContractID,
+		GroupID:         object.GroupID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return countChildRules(ruleTree.Rules), nil
+}
+
+// countChildRules returns number of rules in the tree, including the default rule
+func countChildRules(rules papi.Rules) int {
+	count := 1
+	for _, child := range rules.Children {
+		count += countChildRules(child)
+	}
+	return count
+}
+
+func countRecordsets(ctx context.Context, clients discovery.Clients, object manifest.Object) (int, error) {
+	// only the first, single element page is fetched, the total is taken from metadata
+	recordsets, err := clients.DNS.GetRecordsets(ctx, object.Name, dns.RecordsetQueryArgs{PageSize: 1})
+	if err != nil {
+		return 0, err
+	}
+	return recordsets.Metadata.TotalElements, nil
+}
+
+func countGTMProperties(ctx context.Context, clients discovery.Clients, object manifest.Object) (int, error) {
+	properties, err := clients.GTM.ListProperties(ctx, object.Name)
+	if err != nil {
+		return 0, err
+	}
+	return len(properties), nil
+}
+
+func countPolicyVersions(ctx context.Context, clients discovery.Clients, object manifest.Object) (int, error) {
+	policyID, err := strconv.ParseInt(object.ID, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	pageSize, offset := tools.PageSize(tools.PageSizeCloudlets, 1000), 0
+	for {
+		versions, err := clients.Cloudlets.ListPolicyVersions(ctx, cloudlets.ListPolicyVersionsRequest{
+			PolicyID: policyID,
+			Offset:   offset,
+			PageSize: &pageSize,
+		})
+		if err != nil {
+			return 0, err
+		}
+		count += len(versions)
+		if len(versions) < pageSize {
+			return count, nil
+		}
+		offset += pageSize
+	}
+}
+
+func countConfigurationVersions(ctx context.Context, clients discovery.Clients, object manifest.Object) (int, error) {
+	configID, err := strconv.Atoi(object.ID)
+	if err != nil {
+		return 0, err
+	}
+	versions, err := clients.AppSec.GetConfigurationVersions(ctx, appsec.GetConfigurationVersionsRequest{ConfigID: configID})
+	if err != nil {
+		return 0, err
+	}
+	return len(versions.VersionList), nil
+}
+
+func countEdgeWorkerVersions(ctx context.Context, clients discovery.Clients, object manifest.Object) (int, error) {
+	edgeWorkerID, err := strconv.Atoi(object.ID)
+	if err != nil {
+		return 0, err
+	}
+	versions, err := clients.EdgeWorkers.ListEdgeWorkerVersions(ctx, edgeworkers.ListEdgeWorkerVersionsRequest{EdgeWorkerID: edgeWorkerID})
+	if err != nil {
+		return 0, err
+	}
+	return len(versions.EdgeWorkerVersions), nil
+}
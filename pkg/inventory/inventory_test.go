@@ -0,0 +1,245 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func size(n int) *int {
+	return &n
+}
+
+func TestList(t *testing.T) {
+	expectListZones := func(d *dns.Mock, err error, zones ...*dns.ZoneResponse) {
+		call := d.On("ListZones", mock.Anything, dns.ZoneListQueryArgs{ShowAll: true})
+		if err != nil {
+			call.Return(nil, err).Once()
+			return
+		}
+		call.Return(&dns.ZoneListResponse{Zones: zones}, nil).Once()
+	}
+	expectGetRecordsets := func(d *dns.Mock, zone string, total int, err error) {
+		call := d.On("GetRecordsets", mock.Anything, zone, []dns.RecordsetQueryArgs{{PageSize: 1}})
+		if err != nil {
+			call.Return(nil, err).Once()
+			return
+		}
+		call.Return(&dns.RecordSetResponse{Metadata: dns.MetadataH{TotalElements: total}}, nil).Once()
+	}
+	expectListDomains := func(g *gtm.Mock, err error, domains ...*gtm.DomainItem) {
+		call := g.On("ListDomains", mock.Anything)
+		if err != nil {
+			call.Return(nil, err).Once()
+			return
+		}
+		call.Return(domains, nil).Once()
+	}
+
+	tests := map[string]struct {
+		products         []string
+		init             func(*dns.Mock, *gtm.Mock)
+		expected         []Item
+		expectedWarnings int
+		withError        error
+	}{
+		"zones and domains with sizes": {
+			products: []string{discovery.ProductDNS, discovery.ProductGTM},
+			init: func(d *dns.Mock, g *gtm.Mock) {
+				expectListZones(d, nil, &dns.ZoneResponse{Zone: "first.zone"}, &dns.ZoneResponse{Zone: "second.zone"})
+				expectGetRecordsets(d, "first.zone", 12, nil)
+				expectGetRecordsets(d, "second.zone", 3, nil)
+				expectListDomains(g, nil, &gtm.DomainItem{Name: "test.akadns.net"})
+				g.On("ListProperties", mock.Anything, "test.akadns.net").Return([]*gtm.Property{{Name: "a"}, {Name: "b"}}, nil).Once()
+			},
+			expected: []Item{
+				{Product: discovery.ProductDNS, Name: "first.zone", Metric: "recordsets", Size: size(12)},
+				{Product: discovery.ProductDNS, Name: "second.zone", Metric: "recordsets", Size: size(3)},
+				{Product: discovery.ProductGTM, Name: "test.akadns.net", Metric: "properties", Size: size(2)},
+			},
+		},
+		"object which cannot be measured has no size": {
+			products: []string{discovery.ProductDNS},
+			init: func(d *dns.Mock, _ *gtm.Mock) {
+				expectListZones(d, nil, &dns.ZoneResponse{Zone: "first.zone"})
+				expectGetRecordsets(d, "first.zone", 0, fmt.Errorf("oops"))
+			},
+			expected:         []Item{{Product: discovery.ProductDNS, Name: "first.zone", Metric: "recordsets"}},
+			expectedWarnings: 1,
+		},
+		"failed product is skipped": {
+			products: []string{discovery.ProductDNS, discovery.ProductGTM},
+			init: func(d *dns.Mock, g *gtm.Mock) {
+				expectListZones(d, fmt.Errorf("oops"))
+				expectListDomains(g, nil)
+			},
+			expectedWarnings: 1,
+		},
+		"all products failed": {
+			products: []string{discovery.ProductDNS, discovery.ProductGTM},
+			init: func(d *dns.Mock, g *gtm.Mock) {
+				expectListZones(d, fmt.Errorf("oops"))
+				expectListDomains(g, fmt.Errorf("oops"))
+			},
+			withError: ErrNothingListed,
+		},
+		"unsupported product": {
+			products:  []string{discovery.ProductDNS, "unknown"},
+			init:      func(_ *dns.Mock, _ *gtm.Mock) {},
+			withError: discovery.ErrUnsupportedProduct,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			md, mg := new(dns.Mock), new(gtm.Mock)
+			test.init(md, mg)
+			collector := warnings.NewCollector()
+			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			ctx = warnings.WithCollector(ctx, collector)
+			items, err := List(ctx, test.products, discovery.Clients{DNS: md, GTM: mg})
+			md.AssertExpectations(t)
+			mg.AssertExpectations(t)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, items)
+			assert.Len(t, collector.Warnings(), test.expectedWarnings)
+		})
+	}
+}
+
+func TestCountRules(t *testing.T) {
+	m := new(papi.Mock)
+	m.On("GetProperty", mock.Anything, papi.GetPropertyRequest{ContractID: "ctr_1", GroupID: "grp_1", PropertyID: "prp_1"}).
+		Return(&papi.GetPropertyResponse{Property: &papi.Property{LatestVersion: 3}}, nil).Once()
+	m.On("GetRuleTree", mock.Anything, papi.GetRuleTreeRequest{PropertyID: "prp_1", PropertyVersion: 3, ContractID: "ctr_1", GroupID: "grp_1"}).
+		Return(&papi.GetRuleTreeResponse{Rules: papi.Rules{
+			Name: "default",
+			Children: []papi.Rules{
+				{Name: "first", Children: []papi.Rules{{Name: "nested"}}},
+				{Name: "second"},
+			},
+		}}, nil).Once()
+
+	count, err := countRules(context.Background(), discovery.Clients{PAPI: m}, manifest.Object{ID: "prp_1", ContractID: "ctr_1", GroupID: "grp_1"})
+	require.NoError(t, err)
+	m.AssertExpectations(t)
+	assert.Equal(t, 4, count)
+}
+
+func TestCountPolicyVersions(t *testing.T) {
+	m := new(cloudlets.Mock)
+	pageSize := 1000
+	m.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 5, PageSize: &pageSize}).
+		Return(make([]cloudlets.PolicyVersion, pageSize), nil).Once()
+	m.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 5, Offset: pageSize, PageSize: &pageSize}).
+		Return(make([]cloudlets.PolicyVersion, 2), nil).Once()
+
+	count, err := countPolicyVersions(context.Background(), discovery.Clients{Cloudlets: m}, manifest.Object{ID: "5"})
+	require.NoError(t, err)
+	m.AssertExpectations(t)
+	assert.Equal(t, pageSize+2, count)
+}
+
+func TestWrite(t *testing.T) {
+	items := []Item{
+		{Product: discovery.ProductProperty, Name: "test, property", ID: "prp_1", Metric: "rules", Size: size(42)},
+		{Product: discovery.ProductDNS, Name: "test.zone", Metric: "recordsets"},
+	}
+
+	tests := map[string]struct {
+		format    string
+		items     []Item
+		expected  string
+		withError error
+	}{
+		"json": {
+			format: FormatJSON,
+			items:  items,
+			expected: `[
+  {
+    "product": "property",
+    "name": "test, property",
+    "id": "prp_1",
+    "metric": "rules",
+    "size": 42
+  },
+  {
+    "product": "dns",
+    "name": "test.zone",
+    "metric": "recordsets",
+    "size": null
+  }
+]
+`,
+		},
+		"empty json": {
+			format:   FormatJSON,
+			expected: "[]\n",
+		},
+		"csv": {
+			format:   FormatCSV,
+			items:    items,
+			expected: "product,name,id,metric,size\nproperty,\"test, property\",prp_1,rules,42\ndns,test.zone,,recordsets,\n",
+		},
+		"table": {
+			format: FormatTable,
+			items:  items,
+			expected: "PRODUCT   NAME            ID     SIZE\n" +
+				"property  test, property  prp_1  42 rules\n" +
+				"dns       test.zone              ? recordsets\n" +
+				"\nTotal 2 objects (property: 1, dns: 1)\n",
+		},
+		"unknown format": {
+			format:    "xml",
+			withError: ErrInvalidFormat,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Write(&buf, test.format, test.items)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestCmdInventoryInvalidFormat(t *testing.T) {
+	set := flag.NewFlagSet("inventory", flag.ContinueOnError)
+	set.String("format", "", "")
+	require.NoError(t, set.Parse([]string{"--format", "xml"}))
+	c := cli.NewContext(cli.NewApp(), set, nil)
+	c.Context = context.Background()
+
+	err := CmdInventory(c)
+	var exitErr cli.ExitCoder
+	require.True(t, errors.As(err, &exitErr), "expected exit error; got: %s", err)
+	assert.Equal(t, exitcode.General, exitErr.ExitCode())
+	assert.Contains(t, err.Error(), "invalid report format: 'xml'")
+}
@@ -0,0 +1,296 @@
+// Package lint contains code for checking exported configuration for patterns which are prone to drift
+package lint
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// Finding is a drift-prone pattern found in the configuration together with a suggested fix
+	Finding struct {
+		File    string
+		Line    int
+		Rule    string
+		Message string
+		Fix     string
+	}
+
+	// activation describes a resource activating a version of a configuration resource
+	activation struct {
+		configType string
+		// versionAttribute is the attribute of the configuration holding the version to activate
+		versionAttribute string
+		// dataSource means that the version is read from a data source of the configuration type
+		dataSource bool
+	}
+
+	block struct {
+		file   string
+		typ    string
+		name   string
+		syntax *hclsyntax.Block
+	}
+)
+
+// Names of lint rules
+const (
+	RuleHardcodedVersion = "hardcoded-version"
+	RuleMixedActivation  = "mixed-activation"
+	RuleUnsanitizedName  = "unsanitized-name"
+)
+
+var (
+	activations = map[string]activation{
+		"akamai_appsec_activations":                             {configType: "akamai_appsec_configuration", versionAttribute: "latest_version", dataSource: true},
+		"akamai_cloudlets_application_load_balancer_activation": {configType: "akamai_cloudlets_application_load_balancer", versionAttribute: "version"},
+		"akamai_cloudlets_policy_activation":                    {configType: "akamai_cloudlets_policy", versionAttribute: "version"},
+		"akamai_edgeworkers_activation":                         {configType: "akamai_edgeworker", versionAttribute: "version"},
+		"akamai_mtlstruststore_ca_set_activation":               {configType: "akamai_mtlstruststore_ca_set", versionAttribute: "latest_version"},
+		"akamai_property_activation":                            {configType: "akamai_property", versionAttribute: "latest_version"},
+	}
+
+	// validName matches names accepted by Terraform, which generated names are escaped into
+	validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+	// ErrReadingConfiguration is returned when configuration cannot be read or parsed
+	ErrReadingConfiguration = exitcode.New(exitcode.Template, "unable to read configuration")
+	// ErrUnknownRule is returned when an ignored rule does not exist
+	ErrUnknownRule = exitcode.New(exitcode.General, "unknown lint rule")
+	// ErrFindings is returned when any drift-prone pattern is found
+	ErrFindings = exitcode.New(exitcode.General, "drift-prone configuration")
+)
+
+// Rules returns names of all lint rules
+func Rules() []string {
+	return []string{RuleHardcodedVersion, RuleMixedActivation, RuleUnsanitizedName}
+}
+
+func isRule(name string) bool {
+	for _, rule := range Rules() {
+		if rule == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CmdLint is an entrypoint to lint command
+func CmdLint(c *cli.Context) error {
+	term := terminal.Get(c.Context)
+	dir := c.Args().First()
+	findings, err := Lint(dir, c.StringSlice("ignore"))
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error linting configuration: %s", err)), exitcode.Of(err))
+	}
+	if len(findings) == 0 {
+		term.Printf("No drift-prone patterns found in '%s'\n", dir)
+		return nil
+	}
+	for _, f := range findings {
+		term.Printf("%s:%d: [%s] %s\n", f.File, f.Line, f.Rule, f.Message)
+		term.Printf("  fix: %s\n", f.Fix)
+	}
+	return cli.Exit(color.RedString(fmt.Sprintf("%s in '%s': %d finding(s)", ErrFindings, dir, len(findings))), exitcode.Of(ErrFindings))
+}
+
+// Lint checks configuration in root and its subdirectories for activations of hardcoded versions, activations declared
+// together with the configuration they activate and names which are not sanitized, findings of ignored rules are skipped
+func Lint(root string, ignore []string) ([]Finding, error) {
+	ignored := make(map[string]bool)
+	for _, rule := range ignore {
+		ignored[rule] = true
+	}
+	for rule := range ignored {
+		if !isRule(rule) {
+			return nil, fmt.Errorf("%w: '%s', expected one of: %s", ErrUnknownRule, rule, strings.Join(Rules(), ", "))
+		}
+	}
+	blocks, err := load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, b := range blocks {
+		line := b.syntax.TypeRange.Start.Line
+		if b.name != "" && !validName.MatchString(b.name) {
+			findings = append(findings, Finding{
+				File:    b.file,
+				Line:    line,
+				Rule:    RuleUnsanitizedName,
+				Message: fmt.Sprintf("%s name '%s' is not sanitized", b.syntax.Type, b.name),
+				Fix:     renameFix(b),
+			})
+		}
+		a, ok := activations[b.typ]
+		if b.syntax.Type != "resource" || !ok {
+			continue
+		}
+		address := b.typ + "." + b.name
+		if attr, ok := b.syntax.Body.Attributes["version"]; ok && len(attr.Expr.Variables()) == 0 {
+			findings = append(findings, Finding{
+				File:    b.file,
+				Line:    attr.SrcRange.Start.Line,
+				Rule:    RuleHardcodedVersion,
+				Message: fmt.Sprintf("%s activates hardcoded version", address),
+				Fix:     fmt.Sprintf("refer to the version of the configuration, e.g. version = %s", versionReference(b, a, blocks)),
+			})
+		}
+		for _, other := range blocks {
+			if other.file == b.file && other.syntax.Type == "resource" && other.typ == a.configType {
+				findings = append(findings, Finding{
+					File:    b.file,
+					Line:    line,
+					Rule:    RuleMixedActivation,
+					Message: fmt.Sprintf("%s is declared together with %s.%s it activates", address, other.typ, other.name),
+					Fix:     "move the activation into a separate configuration applied once the change of the configuration is reviewed, or at least into its own file",
+				})
+				break
+			}
+		}
+	}
+
+	result := make([]Finding, 0, len(findings))
+	for _, f := range findings {
+		if !ignored[f.Rule] {
+			result = append(result, f)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result, nil
+}
+
+// versionReference returns expression referring to the version of the configuration activated by the block, the
+// configuration is found among references of the block, or among configurations declared in the same directory
+func versionReference(b block, a activation, blocks []block) string {
+	configType := a.configType
+	if a.dataSource {
+		configType = "data." + configType
+	}
+	attributes := make([]string, 0, len(b.syntax.Body.Attributes))
+	for attr := range b.syntax.Body.Attributes {
+		attributes = append(attributes, attr)
+	}
+	sort.Strings(attributes)
+	name := ""
+	for _, attr := range attributes {
+		for _, traversal := range b.syntax.Body.Attributes[attr].Expr.Variables() {
+			if root, ref := referencedName(traversal); name == "" && root == configType {
+				name = ref
+			}
+		}
+	}
+	if name == "" {
+		for _, other := range blocks {
+			if filepath.Dir(other.file) == filepath.Dir(b.file) && other.typ == a.configType && (other.syntax.Type == "data") == a.dataSource {
+				name = other.name
+				break
+			}
+		}
+	}
+	if name == "" {
+		name = "<name>"
+	}
+	return fmt.Sprintf("%s.%s.%s", configType, name, a.versionAttribute)
+}
+
+// referencedName returns the type and the name of the resource or data source the traversal refers to
+func referencedName(traversal hcl.Traversal) (string, string) {
+	root := traversal.RootName()
+	steps := traversal[1:]
+	if root == "data" && len(steps) > 0 {
+		if attr, ok := steps[0].(hcl.TraverseAttr); ok {
+			root = "data." + attr.Name
+			steps = steps[1:]
+		}
+	}
+	if len(steps) == 0 {
+		return root, ""
+	}
+	attr, ok := steps[0].(hcl.TraverseAttr)
+	if !ok {
+		return root, ""
+	}
+	return root, attr.Name
+}
+
+// renameFix suggests the sanitized name, a moved block keeps resources and modules in the state
+func renameFix(b block) string {
+	name, err := tools.EscapeName(b.name)
+	if err != nil || name == "" {
+		name = "<name>"
+	}
+	switch b.syntax.Type {
+	case "resource":
+		return fmt.Sprintf("rename it to '%s' and add a moved block from %s.%s to %s.%s", name, b.typ, b.name, b.typ, name)
+	case "module":
+		return fmt.Sprintf("rename it to '%s' and add a moved block from module.%s to module.%s", name, b.name, name)
+	}
+	return fmt.Sprintf("rename it to '%s' and update references to it", name)
+}
+
+// load parses blocks of all .tf files in root and its subdirectories
+func load(root string) ([]block, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != root && workspace.SkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".tf" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingConfiguration, err)
+	}
+	sort.Strings(paths)
+
+	var blocks []block
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrReadingConfiguration, err)
+		}
+		f, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%w: %s", ErrReadingConfiguration, diags.Error())
+		}
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, b := range body.Blocks {
+			switch {
+			case (b.Type == "resource" || b.Type == "data") && len(b.Labels) == 2:
+				blocks = append(blocks, block{file: path, typ: b.Labels[0], name: b.Labels[1], syntax: b})
+			case (b.Type == "module" || b.Type == "variable" || b.Type == "output") && len(b.Labels) == 1:
+				blocks = append(blocks, block{file: path, name: b.Labels[0], syntax: b})
+			}
+		}
+	}
+	return blocks, nil
+}
@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	policyActivation := filepath.Join("testdata", "workspace", "cloudlets", "policy-activation.tf")
+	property := filepath.Join("testdata", "workspace", "property", "property.tf")
+	variables := filepath.Join("testdata", "workspace", "property", "variables.tf")
+
+	tests := map[string]struct {
+		dir       string
+		ignore    []string
+		expected  []Finding
+		withError error
+	}{
+		"all rules": {
+			dir: "./testdata/workspace",
+			expected: []Finding{
+				{
+					File:    policyActivation,
+					Line:    10,
+					Rule:    RuleHardcodedVersion,
+					Message: "akamai_edgeworkers_activation.edgeworker_activation activates hardcoded version",
+					Fix:     "refer to the version of the configuration, e.g. version = akamai_edgeworker.<name>.version",
+				},
+				{
+					File:    property,
+					Line:    1,
+					Rule:    RuleUnsanitizedName,
+					Message: "resource name 'www.example.com' is not sanitized",
+					Fix:     "rename it to 'wwwexamplecom' and add a moved block from akamai_edge_hostname.www.example.com to akamai_edge_hostname.wwwexamplecom",
+				},
+				{
+					File:    property,
+					Line:    15,
+					Rule:    RuleMixedActivation,
+					Message: "akamai_property_activation.example-com is declared together with akamai_property.example-com it activates",
+					Fix:     "move the activation into a separate configuration applied once the change of the configuration is reviewed, or at least into its own file",
+				},
+				{
+					File:    property,
+					Line:    18,
+					Rule:    RuleHardcodedVersion,
+					Message: "akamai_property_activation.example-com activates hardcoded version",
+					Fix:     "refer to the version of the configuration, e.g. version = akamai_property.example-com.latest_version",
+				},
+				{
+					File:    variables,
+					Line:    14,
+					Rule:    RuleUnsanitizedName,
+					Message: "variable name '2nd contact' is not sanitized",
+					Fix:     "rename it to 'ak_2nd_contact' and update references to it",
+				},
+			},
+		},
+		"ignored rules": {
+			dir:    "./testdata/workspace/property",
+			ignore: []string{RuleMixedActivation, RuleUnsanitizedName, RuleMixedActivation},
+			expected: []Finding{
+				{
+					File:    property,
+					Line:    18,
+					Rule:    RuleHardcodedVersion,
+					Message: "akamai_property_activation.example-com activates hardcoded version",
+					Fix:     "refer to the version of the configuration, e.g. version = akamai_property.example-com.latest_version",
+				},
+			},
+		},
+		"no findings": {
+			dir:      "./testdata/workspace/cloudlets",
+			ignore:   []string{RuleHardcodedVersion},
+			expected: []Finding{},
+		},
+		"unknown rule": {
+			dir:       "./testdata/workspace",
+			ignore:    []string{"hardcoded-names"},
+			withError: ErrUnknownRule,
+		},
+		"invalid configuration": {
+			dir:       "./testdata/invalid",
+			withError: ErrReadingConfiguration,
+		},
+		"missing directory": {
+			dir:       "./testdata/missing",
+			withError: ErrReadingConfiguration,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			findings, err := Lint(test.dir, test.ignore)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, findings)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+resource "akamai_cloudlets_policy_activation" "policy_activation" {
+  policy_id = tonumber(akamai_cloudlets_policy.policy.id)
+  network   = var.env
+  version   = akamai_cloudlets_policy.policy.version
+}
+
+resource "akamai_edgeworkers_activation" "edgeworker_activation" {
+  edgeworker_id = 4242
+  network       = var.env
+  version       = "1.0.3"
+}
@@ -0,0 +1,5 @@
+resource "akamai_cloudlets_policy" "policy" {
+  name          = "redirects"
+  cloudlet_code = "ER"
+  group_id      = "grp_12345"
+}
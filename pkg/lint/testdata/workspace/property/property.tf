@@ -0,0 +1,20 @@
+resource "akamai_edge_hostname" "www.example.com" {
+  contract_id   = var.contract_id
+  group_id      = var.group_id
+  ip_behavior   = "IPV6_COMPLIANCE"
+  edge_hostname = "www.example.com.edgesuite.net"
+}
+
+resource "akamai_property" "example-com" {
+  name        = "example.com"
+  contract_id = var.contract_id
+  group_id    = var.group_id
+  product_id  = "prd_Fresca"
+}
+
+resource "akamai_property_activation" "example-com" {
+  property_id = akamai_property.example-com.id
+  contact     = ["user@example.com"]
+  version     = 5
+  network     = upper(var.env)
+}
@@ -0,0 +1,17 @@
+variable "contract_id" {
+  type = string
+}
+
+variable "group_id" {
+  type = string
+}
+
+variable "env" {
+  type    = string
+  default = "staging"
+}
+
+variable "2nd contact" {
+  type    = string
+  default = "admin@example.com"
+}
@@ -0,0 +1,78 @@
+// Package manifest contains code for reading and writing export manifests
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+)
+
+type (
+	// Manifest describes a set of account objects which can be exported in a single run
+	Manifest struct {
+		Section string   `json:"section,omitempty"`
+		Objects []Object `json:"objects"`
+	}
+
+	// Object describes a single exportable object and the export command used to generate its configuration
+	Object struct {
+		Product    string   `json:"product"`
+		Name       string   `json:"name"`
+		ID         string   `json:"id,omitempty"`
+		ContractID string   `json:"contractId,omitempty"`
+		GroupID    string   `json:"groupId,omitempty"`
+		Command    string   `json:"command"`
+		Args       []string `json:"args"`
+		Selected   bool     `json:"selected"`
+		// Nameservers are authoritative name servers of edge dns zones, to be set as their delegation at the registrar
+		Nameservers []string `json:"nameservers,omitempty"`
+		// Dir is the path of the subdirectory into which the object is exported instead of '<product>/<name>', with
+		// segments separated by '/'
+		Dir string `json:"dir,omitempty"`
+	}
+)
+
+var (
+	// ErrReadingManifest is returned when manifest file could not be read or parsed
+	ErrReadingManifest = exitcode.New(exitcode.IO, "reading manifest")
+	// ErrSavingManifest is returned when manifest file could not be written
+	ErrSavingManifest = exitcode.New(exitcode.IO, "saving manifest")
+)
+
+// Load reads manifest from the given path
+func Load(path string) (*Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingManifest, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingManifest, err)
+	}
+	return &m, nil
+}
+
+// Save writes manifest to the given path
+func (m *Manifest) Save(path string) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingManifest, err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingManifest, err)
+	}
+	return nil
+}
+
+// Selected returns only objects marked as selected for export
+func (m *Manifest) Selected() []Object {
+	var selected []Object
+	for _, o := range m.Objects {
+		if o.Selected {
+			selected = append(selected, o)
+		}
+	}
+	return selected
+}
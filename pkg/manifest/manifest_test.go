@@ -0,0 +1,62 @@
+package manifest
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	m := Manifest{
+		Section: "test_section",
+		Objects: []Object{
+			{
+				Product:  "cloudlets",
+				Name:     "test_policy",
+				ID:       "123",
+				GroupID:  "234",
+				Command:  "export-cloudlets-policy",
+				Args:     []string{"test_policy"},
+				Selected: true,
+			},
+			{
+				Product: "dns",
+				Name:    "test.zone",
+				Command: "export-zone",
+				Args:    []string{"test.zone"},
+			},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, m.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, m, *loaded)
+	assert.Equal(t, m.Objects[:1], loaded.Selected())
+}
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		path      string
+		withError error
+	}{
+		"file does not exist": {
+			path:      "testdata/not_existing.json",
+			withError: ErrReadingManifest,
+		},
+		"invalid json": {
+			path:      "testdata/invalid.json",
+			withError: ErrReadingManifest,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(test.path)
+			assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+		})
+	}
+}
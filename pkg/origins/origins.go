@@ -0,0 +1,135 @@
+// Package origins contains code for collecting origins referenced by objects exported in a run into a consolidated
+// inventory, which helps to find origins shared by properties and cloudlets policies
+package origins
+
+import (
+	"context"
+	"embed"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+)
+
+type (
+	// Origin is an origin referenced by an exported object, identified by its ID, its hostname or both
+	Origin struct {
+		ID       string
+		Hostname string
+	}
+
+	// Entry is an origin of the inventory with objects referencing it
+	Entry struct {
+		// Key identifies the origin in the inventory, it is the ID of the origin or its hostname if the ID is not known
+		Key      string
+		ID       string
+		Hostname string
+		// UsedBy are exported objects referencing the origin, e.g. 'property example.com', sorted
+		UsedBy []string
+	}
+
+	// TFData represents the data used in origins template
+	TFData struct {
+		Origins []Entry
+	}
+
+	// Collector collects origins referenced by objects exported in a run
+	Collector struct {
+		mu      sync.Mutex
+		entries map[string]*Entry
+	}
+
+	ctxType string
+)
+
+// FileName is the name of generated origin inventory file
+const FileName = "origins.tf"
+
+var (
+	//go:embed templates/*
+	templateFiles embed.FS
+
+	collectorCtx ctxType = "originsCollector"
+)
+
+// NewCollector returns a new Collector
+func NewCollector() *Collector {
+	return &Collector{entries: make(map[string]*Entry)}
+}
+
+// WithCollector puts a Collector in context
+func WithCollector(ctx context.Context, c *Collector) context.Context {
+	return context.WithValue(ctx, collectorCtx, c)
+}
+
+// GetCollector retrieves a Collector from context, nil is returned if origins are not collected
+func GetCollector(ctx context.Context) *Collector {
+	c, _ := ctx.Value(collectorCtx).(*Collector)
+	return c
+}
+
+// Record registers origins referenced by the exported object, e.g. 'property example.com'; it does nothing if origins
+// are not collected
+func Record(ctx context.Context, object string, origins ...Origin) {
+	c := GetCollector(ctx)
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, origin := range origins {
+		key := origin.ID
+		if key == "" {
+			key = origin.Hostname
+		}
+		if key == "" {
+			continue
+		}
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &Entry{Key: key, ID: origin.ID}
+			c.entries[key] = entry
+		}
+		if entry.Hostname == "" {
+			entry.Hostname = origin.Hostname
+		}
+		if !contains(entry.UsedBy, object) {
+			entry.UsedBy = append(entry.UsedBy, object)
+			sort.Strings(entry.UsedBy)
+		}
+	}
+}
+
+// Entries returns collected origins sorted by their keys
+func (c *Collector) Entries() []Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]Entry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		e := *entry
+		e.UsedBy = append([]string(nil), entry.UsedBy...)
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// Write writes origins.tf with locals listing the origins into dir of the sink, nothing is written when there are none
+func Write(sink templates.OutputSink, dir string, entries []Entry) error {
+	processor := templates.FSTemplateProcessor{
+		TemplatesFS:     templateFiles,
+		TemplateTargets: map[string]string{"origins.tmpl": filepath.Join(dir, FileName)},
+		Sink:            sink,
+	}
+	return processor.ProcessTemplates(TFData{Origins: entries})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
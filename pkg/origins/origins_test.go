@@ -0,0 +1,61 @@
+package origins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	collector := NewCollector()
+	ctx := WithCollector(context.Background(), collector)
+
+	Record(ctx, "property example.com", Origin{Hostname: "origin.example.com"}, Origin{ID: "dc_east"})
+	Record(ctx, "cloudlets policy my_policy", Origin{ID: "alb_1"}, Origin{ID: "dc_east", Hostname: "east.example.com"})
+	Record(ctx, "property example.org", Origin{Hostname: "origin.example.com"}, Origin{Hostname: "origin.example.com"}, Origin{})
+	Record(context.Background(), "property ignored.com", Origin{Hostname: "ignored.example.com"})
+
+	assert.Equal(t, []Entry{
+		{Key: "alb_1", ID: "alb_1", UsedBy: []string{"cloudlets policy my_policy"}},
+		{Key: "dc_east", ID: "dc_east", Hostname: "east.example.com", UsedBy: []string{"cloudlets policy my_policy", "property example.com"}},
+		{Key: "origin.example.com", Hostname: "origin.example.com", UsedBy: []string{"property example.com", "property example.org"}},
+	}, collector.Entries())
+}
+
+func TestWrite(t *testing.T) {
+	tests := map[string]struct {
+		entries      []Entry
+		expectedFile string
+	}{
+		"origins shared by objects": {
+			entries: []Entry{
+				{Key: "alb_1", ID: "alb_1", UsedBy: []string{"cloudlets policy my_policy"}},
+				{Key: "dc_east", ID: "dc_east", Hostname: "east.example.com", UsedBy: []string{"cloudlets policy my_policy", "property example.com"}},
+				{Key: "origin.example.com", Hostname: "origin.example.com", UsedBy: []string{"property example.com", "property example.org"}},
+			},
+			expectedFile: "./testdata/origins.tf",
+		},
+		"no origins": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, Write(templates.DirSink{}, dir, test.entries))
+			if test.expectedFile == "" {
+				assert.NoFileExists(t, filepath.Join(dir, FileName))
+				return
+			}
+			expected, err := os.ReadFile(test.expectedFile)
+			require.NoError(t, err)
+			result, err := os.ReadFile(filepath.Join(dir, FileName))
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(result))
+		})
+	}
+}
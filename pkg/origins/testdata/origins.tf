@@ -0,0 +1,20 @@
+# Origins referenced by objects exported in this run, keyed by origin ID or hostname
+locals {
+  origins = {
+    "alb_1" = {
+      origin_id = "alb_1"
+      hostname  = null
+      used_by   = ["cloudlets policy my_policy"]
+    }
+    "dc_east" = {
+      origin_id = "dc_east"
+      hostname  = "east.example.com"
+      used_by   = ["cloudlets policy my_policy", "property example.com"]
+    }
+    "origin.example.com" = {
+      origin_id = null
+      hostname  = "origin.example.com"
+      used_by   = ["property example.com", "property example.org"]
+    }
+  }
+}
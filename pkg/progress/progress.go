@@ -0,0 +1,237 @@
+// Package progress contains code for reporting progress of long-running operations
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+)
+
+type (
+	// Reporter reports progress of the operation currently performed by the command
+	Reporter interface {
+		// Start begins reporting of a new operation described by the formatted message
+		Start(f string, args ...interface{})
+		// Total sets number of steps of the current operation, so that step counts and ETA can be reported
+		Total(n int)
+		// Step marks a single step of the current operation as done, it is safe for concurrent use
+		Step()
+		// OK finishes the current operation with success
+		OK()
+		// Fail finishes the current operation with failure
+		Fail()
+		// Cancel finishes the current operation as interrupted, it does nothing if no operation is running
+		Cancel()
+	}
+
+	// Event describes a single change of the operation progress
+	Event struct {
+		Event      string  `json:"event"`
+		Message    string  `json:"message"`
+		Done       int     `json:"done,omitempty"`
+		Total      int     `json:"total,omitempty"`
+		ETASeconds float64 `json:"etaSeconds,omitempty"`
+		Elapsed    float64 `json:"elapsedSeconds"`
+	}
+
+	reporter struct {
+		mu       sync.Mutex
+		emit     func(Event)
+		throttle bool
+		now      func() time.Time
+		message  string
+		running  bool
+		started  time.Time
+		total    int
+		done     int
+	}
+
+	contextKey string
+)
+
+const (
+	// EventStart is emitted when an operation starts
+	EventStart = "start"
+	// EventProgress is emitted when steps of an operation are done
+	EventProgress = "progress"
+	// EventOK is emitted when an operation finishes with success
+	EventOK = "ok"
+	// EventFail is emitted when an operation finishes with failure
+	EventFail = "fail"
+	// EventCancel is emitted when a running operation is interrupted
+	EventCancel = "cancel"
+)
+
+var spinnerStatusCancel = terminal.SpinnerStatus(fmt.Sprintf("... [%s]\n", color.YellowString("CANCELLED")))
+
+const reporterKey contextKey = "progress"
+
+// WithReporter puts a Reporter in context
+func WithReporter(ctx context.Context, r Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey, r)
+}
+
+// Get retrieves a Reporter from context, falling back to the terminal spinner when none was configured
+func Get(ctx context.Context) Reporter {
+	if r, ok := ctx.Value(reporterKey).(Reporter); ok {
+		return r
+	}
+	return NewSpinner(terminal.Get(ctx))
+}
+
+// New returns a reporter suitable for the given terminal: JSON events when json output is requested,
+// spinner when attached to a TTY and plain log lines otherwise (e.g. in CI pipelines)
+func New(term terminal.Terminal, jsonOutput bool) Reporter {
+	switch {
+	case jsonOutput:
+		return NewJSON(term.Error())
+	case term.IsTTY():
+		return NewSpinner(term)
+	default:
+		return NewLog(term.Error())
+	}
+}
+
+// NewSpinner returns a reporter displaying progress using terminal spinner
+func NewSpinner(term terminal.Terminal) Reporter {
+	return newReporter(func(e Event) {
+		switch e.Event {
+		case EventStart:
+			term.Spinner().Start("%s", e.Message)
+		case EventProgress:
+			_, _ = term.Spinner().Write([]byte(formatSteps(e)))
+		case EventOK:
+			term.Spinner().OK()
+		case EventFail:
+			term.Spinner().Fail()
+		case EventCancel:
+			term.Spinner().Stop(spinnerStatusCancel)
+		}
+	}, false)
+}
+
+// NewLog returns a reporter writing progress as plain log lines
+func NewLog(w io.Writer) Reporter {
+	return newReporter(func(e Event) {
+		switch e.Event {
+		case EventStart:
+			fmt.Fprintf(w, "%s...\n", e.Message)
+		case EventProgress:
+			fmt.Fprintf(w, "%s... %s\n", e.Message, formatSteps(e))
+		case EventOK:
+			fmt.Fprintf(w, "%s... [%s] (%s)\n", e.Message, color.GreenString("OK"), formatSeconds(e.Elapsed))
+		case EventFail:
+			fmt.Fprintf(w, "%s... [%s] (%s)\n", e.Message, color.RedString("FAIL"), formatSeconds(e.Elapsed))
+		case EventCancel:
+			fmt.Fprintf(w, "%s... [%s] (%s)\n", e.Message, color.YellowString("CANCELLED"), formatSeconds(e.Elapsed))
+		}
+	}, true)
+}
+
+// NewJSON returns a reporter writing progress events as JSON lines
+func NewJSON(w io.Writer) Reporter {
+	return newReporter(func(e Event) {
+		content, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+		fmt.Fprintln(w, string(content))
+	}, true)
+}
+
+// NewDiscard returns a reporter which ignores all progress, e.g. of operations run in parallel which progress is reported in aggregate
+func NewDiscard() Reporter {
+	return newReporter(func(Event) {}, false)
+}
+
+func newReporter(emit func(Event), throttle bool) *reporter {
+	return &reporter{emit: emit, throttle: throttle, now: time.Now}
+}
+
+func (r *reporter) Start(f string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.message = fmt.Sprintf(f, args...)
+	r.started = r.now()
+	r.running = true
+	r.total, r.done = 0, 0
+	r.emit(r.event(EventStart, r.started))
+}
+
+func (r *reporter) Total(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.total = n
+}
+
+func (r *reporter) Step() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done++
+	if r.total <= 0 {
+		return
+	}
+	// log lines and events are only emitted for every 10% of steps, so that large exports do not flood the output
+	if r.throttle && r.done != r.total && r.done*10/r.total == (r.done-1)*10/r.total {
+		return
+	}
+	r.emit(r.event(EventProgress, r.now()))
+}
+
+func (r *reporter) OK() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running = false
+	r.emit(r.event(EventOK, r.now()))
+}
+
+func (r *reporter) Fail() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running = false
+	r.emit(r.event(EventFail, r.now()))
+}
+
+func (r *reporter) Cancel() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.running {
+		return
+	}
+	r.running = false
+	r.emit(r.event(EventCancel, r.now()))
+}
+
+func (r *reporter) event(name string, now time.Time) Event {
+	elapsed := now.Sub(r.started)
+	e := Event{
+		Event:   name,
+		Message: r.message,
+		Elapsed: elapsed.Round(time.Millisecond).Seconds(),
+	}
+	if r.total > 0 {
+		e.Done, e.Total = r.done, r.total
+	}
+	if name == EventProgress && r.done > 0 && r.done < r.total {
+		eta := elapsed * time.Duration(r.total-r.done) / time.Duration(r.done)
+		e.ETASeconds = eta.Round(time.Second).Seconds()
+	}
+	return e
+}
+
+func formatSteps(e Event) string {
+	if e.ETASeconds > 0 {
+		return fmt.Sprintf("%d/%d, ETA %s", e.Done, e.Total, formatSeconds(e.ETASeconds))
+	}
+	return fmt.Sprintf("%d/%d", e.Done, e.Total)
+}
+
+func formatSeconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).String()
+}
@@ -0,0 +1,206 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock returns times advancing by a second on each call
+func fakeClock() func() time.Time {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewJSON(buf).(*reporter)
+	r.now = fakeClock()
+
+	r.Start("Fetching %s", "users")
+	r.Total(20)
+	for i := 0; i < 20; i++ {
+		r.Step()
+	}
+	r.OK()
+
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e Event
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		events = append(events, e)
+	}
+
+	// 1 start, 10 throttled progress events and 1 ok
+	require.Len(t, events, 12)
+	assert.Equal(t, Event{Event: EventStart, Message: "Fetching users"}, events[0])
+	assert.Equal(t, Event{Event: EventProgress, Message: "Fetching users", Done: 2, Total: 20, ETASeconds: 9, Elapsed: 1}, events[1])
+	assert.Equal(t, Event{Event: EventProgress, Message: "Fetching users", Done: 20, Total: 20, Elapsed: 10}, events[10])
+	assert.Equal(t, Event{Event: EventOK, Message: "Fetching users", Done: 20, Total: 20, Elapsed: 11}, events[11])
+}
+
+func TestLogReporter(t *testing.T) {
+	tests := map[string]struct {
+		run      func(Reporter)
+		expected []string
+	}{
+		"success without steps": {
+			run: func(r Reporter) {
+				r.Start("Saving TF configurations")
+				r.OK()
+			},
+			expected: []string{
+				"Saving TF configurations...",
+				"Saving TF configurations... [OK] (1s)",
+			},
+		},
+		"failure with steps": {
+			run: func(r Reporter) {
+				r.Start("Fetching load balancers")
+				r.Total(4)
+				r.Step()
+				r.Step()
+				r.Fail()
+			},
+			expected: []string{
+				"Fetching load balancers...",
+				"Fetching load balancers... 1/4, ETA 3s",
+				"Fetching load balancers... 2/4, ETA 2s",
+				"Fetching load balancers... [FAIL] (3s)",
+			},
+		},
+		"cancel of running operation": {
+			run: func(r Reporter) {
+				r.Start("Fetching rules")
+				r.Cancel()
+				r.Cancel()
+			},
+			expected: []string{
+				"Fetching rules...",
+				"Fetching rules... [CANCELLED] (1s)",
+			},
+		},
+		"cancel without running operation": {
+			run: func(r Reporter) {
+				r.Start("Saving TF configurations")
+				r.OK()
+				r.Cancel()
+			},
+			expected: []string{
+				"Saving TF configurations...",
+				"Saving TF configurations... [OK] (1s)",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			r := NewLog(buf).(*reporter)
+			r.now = fakeClock()
+
+			test.run(r)
+			assert.Equal(t, test.expected, strings.Split(strings.TrimSpace(stripColors(buf.String())), "\n"))
+		})
+	}
+}
+
+func TestSpinnerReporter(t *testing.T) {
+	term := &terminal.Mock{}
+	term.On("Spinner").Return(term)
+	term.On("Start", "%s", []interface{}{"Fetching policy"}).Return().Once()
+	term.On("Write", []byte("1/2")).Return(3, nil).Once()
+	term.On("Write", []byte("2/2")).Return(3, nil).Once()
+	term.On("OK").Return().Once()
+
+	r := NewSpinner(term)
+	r.Start("Fetching policy")
+	r.Total(2)
+	r.Step()
+	r.Step()
+	r.OK()
+	term.AssertExpectations(t)
+}
+
+func TestConcurrentSteps(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewJSON(buf).(*reporter)
+	r.Start("Fetching properties")
+	r.Total(100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Step()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, r.done)
+}
+
+func TestDiscardReporter(t *testing.T) {
+	r := NewDiscard()
+	r.Start("test")
+	r.Total(2)
+	r.Step()
+	r.Step()
+	r.OK()
+	assert.Equal(t, 2, r.(*reporter).done)
+}
+
+func TestGet(t *testing.T) {
+	r := NewLog(&bytes.Buffer{})
+	ctx := WithReporter(context.Background(), r)
+	assert.Equal(t, r, Get(ctx))
+
+	term := &terminal.Mock{}
+	ctx = terminal.Context(context.Background(), term)
+	assert.NotNil(t, Get(ctx))
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]struct {
+		json     bool
+		tty      bool
+		expected string
+	}{
+		"json":   {json: true, expected: `{"event":"start","message":"test","elapsedSeconds":0}`},
+		"tty":    {tty: true},
+		"no tty": {expected: "test..."},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			term := &terminal.Mock{}
+			term.On("Error").Return(buf).Maybe()
+			term.On("IsTTY").Return(test.tty).Maybe()
+			term.On("Spinner").Return(term).Maybe()
+			term.On("Start", "%s", mock.Anything).Return().Maybe()
+
+			New(term, test.json).Start("test")
+			assert.Equal(t, test.expected, strings.TrimSpace(buf.String()))
+		})
+	}
+}
+
+func stripColors(s string) string {
+	for _, code := range []string{"\x1b[32m", "\x1b[31m", "\x1b[0m"} {
+		s = strings.ReplaceAll(s, code, "")
+	}
+	return s
+}
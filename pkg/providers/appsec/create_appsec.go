@@ -14,9 +14,13 @@ import (
 	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/appsec"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -28,15 +32,19 @@ var client appsec.APPSEC
 
 var (
 	// ErrFetchingPolicy is returned when fetching policy fails
-	ErrFetchingPolicy = errors.New("unable to fetch policy with given name")
+	ErrFetchingPolicy = exitcode.New(exitcode.API, "unable to fetch policy with given name")
 	// ErrFetchingVersion is returned when fetching policy version fails
-	ErrFetchingVersion = errors.New("unable to fetch latest policy version")
+	ErrFetchingVersion = exitcode.New(exitcode.API, "unable to fetch latest policy version")
 	// ErrCloudletTypeNotSupported is returned when a provided cloudlet type is not yet supported
-	ErrCloudletTypeNotSupported = errors.New("cloudlet type not supported")
+	ErrCloudletTypeNotSupported = exitcode.New(exitcode.Unsupported, "cloudlet type not supported")
 	// ErrSavingFiles is returned when an issue with processing templates occurs
-	ErrSavingFiles = errors.New("saving terraform project files")
+	ErrSavingFiles = exitcode.New(exitcode.IO, "saving terraform project files")
 
 	section string
+
+	// notEntitled lists parts of the configuration skipped by template functions because the API client is not
+	// entitled to their APIs, in order they were skipped; they are reported as warnings once templates are processed
+	notEntitled []string
 )
 
 // CmdCreateAppsec is an entrypoint to create-appsec command
@@ -51,30 +59,72 @@ func CmdCreateAppsec(c *cli.Context) error {
 		tfWorkPath = c.String("tfworkpath")
 	}
 
+	// Save our section for use later
+	section = edgegrid.GetEdgercSection(c)
+
+	processor, err := newProcessor(ctx, tfWorkPath, "")
+	if err != nil {
+		return cli.NewExitError(color.RedString(err.Error()), 1)
+	}
+
+	appsecName := c.Args().First()
+
+	// the clone is a separate root configuration next to the exported one
+	var cloneProcessor templates.TemplateProcessor
+	if cloneName := c.String("clone-as"); cloneName != "" {
+		if cloneName == appsecName {
+			return cli.NewExitError(color.RedString("Error exporting appsec config HCL: name of the clone must differ from the name of the exported configuration"), 1)
+		}
+		abs, err := filepath.Abs(tfWorkPath)
+		if err != nil {
+			return cli.NewExitError(color.RedString(err.Error()), 1)
+		}
+		dirName, err := tools.EscapeName(cloneName)
+		if err != nil {
+			return cli.NewExitError(color.RedString(err.Error()), 1)
+		}
+		cloneDir := filepath.Join(filepath.Dir(abs), dirName)
+		if cloneDir == abs {
+			return cli.NewExitError(color.RedString("Error exporting appsec config HCL: the clone would be written into the export directory %s, use a different tfworkpath", abs), 1)
+		}
+		cloneProcessor, err = newProcessor(ctx, cloneDir, cloneName)
+		if err != nil {
+			return cli.NewExitError(color.RedString(err.Error()), 1)
+		}
+	}
+
+	if err := createAppsec(ctx, appsecName, client, processor, cloneProcessor); err != nil {
+		return cli.NewExitError(color.RedString(fmt.Sprintf("Error exporting appsec config HCL: %s", err)), 1)
+	}
+	return nil
+}
+
+// newProcessor returns template processor writing the configuration into tfWorkPath. If cloneName is set, the
+// configuration is a parameterized copy for creating a new security configuration with the given name, which is not
+// imported.
+func newProcessor(ctx context.Context, tfWorkPath, cloneName string) (templates.TemplateProcessor, error) {
 	// Directory Paths
 	modulesPath := filepath.Join(tfWorkPath, "modules")
 	securityModulePath := filepath.Join(modulesPath, "security")
 	activateSecurityModulePath := filepath.Join(modulesPath, "activate-security")
 	paths := []string{modulesPath, securityModulePath, activateSecurityModulePath}
 
-	for _, path := range paths {
-		err := os.MkdirAll(path, 0755)
-		if err != nil {
-			return cli.NewExitError(color.RedString(err.Error()), 1)
+	// files written into other sinks than the filesystem do not need directories
+	if templates.IsDirSink(templates.GetSink(ctx)) {
+		for _, path := range paths {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	// File Paths
 	appsecPath := filepath.Join(tfWorkPath, "appsec.tf")
 
-	err := tools.CheckFiles(appsecPath)
-	if err != nil {
-		return cli.NewExitError(color.RedString(err.Error()), 1)
+	if err := tools.CheckFiles(appsecPath); err != nil {
+		return nil, err
 	}
 
-	// Save our section for use later
-	section = edgegrid.GetEdgercSection(c)
-
 	// Template to path mappings
 	templateToFile := map[string]string{
 		"appsec.tmpl":                         appsecPath,
@@ -108,10 +158,15 @@ func CmdCreateAppsec(c *cli.Context) error {
 		"variables.tmpl":                               filepath.Join(tfWorkPath, "appsec-variables.tf"),
 		"versions.tmpl":                                filepath.Join(tfWorkPath, "appsec-versions.tf"),
 	}
+	// the clone creates a new configuration, there is nothing to import
+	if cloneName != "" {
+		delete(templateToFile, "imports.tmpl")
+	}
 
 	// Provide custom helper functions to get data that does not exist in the security config export
 	additionalFuncs := template.FuncMap{
 		"exportJSON":            exportJSON,
+		"getCloneName":          func() string { return cloneName },
 		"getConfigDescription":  getConfigDescription,
 		"getCustomRuleNameByID": getCustomRuleNameByID,
 		"getMalwareNameByID":    getMalwareNameByID,
@@ -127,55 +182,81 @@ func CmdCreateAppsec(c *cli.Context) error {
 	}
 
 	// The template processor
-	processor := templates.FSTemplateProcessor{
+	return templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 		AdditionalFuncs: additionalFuncs,
-	}
-
-	appsecName := c.Args().First()
-	if err = createAppsec(ctx, appsecName, client, processor); err != nil {
-		return cli.NewExitError(color.RedString(fmt.Sprintf("Error exporting appsec config HCL: %s", err)), 1)
-	}
-	return nil
+	}, nil
 }
 
-func createAppsec(ctx context.Context, configName string, client appsec.APPSEC, templateProcessor templates.TemplateProcessor) error {
-
-	term := terminal.Get(ctx)
-
-	fmt.Println("Configuring Appsec")
-	term.Spinner().Start("Finding appsec configuration " + configName)
+func createAppsec(ctx context.Context, configName string, client appsec.APPSEC, templateProcessor, cloneProcessor templates.TemplateProcessor) error {
+	terminal.Get(ctx).Printf("Configuring Appsec\n")
+	progress.Get(ctx).Start("Finding appsec configuration " + configName)
 
 	id, version, err := findConfigurationIDByName(ctx, configName, client)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingPolicy, err)
 	}
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Fetching appsec configuration " + configName)
+	progress.Get(ctx).Start("Fetching appsec configuration " + configName)
 
 	configuration, err := exportConfiguration(ctx, id, version, client)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingPolicy, err)
 	}
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Saving TF configurations")
+	notEntitled = nil
+	defer reportNotEntitled(ctx, configName)
+
+	progress.Get(ctx).Start("Saving TF configurations")
 	if err := templateProcessor.ProcessTemplates(configuration); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrSavingFiles, err)
 	}
-	term.Spinner().OK()
-	fmt.Printf("Terraform configuration for configuration '%s' was saved successfully\n", configName)
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for configuration '%s' was saved successfully\n", configName)
+
+	if cloneProcessor != nil {
+		progress.Get(ctx).Start("Saving TF configurations of the clone")
+		if err := cloneProcessor.ProcessTemplates(configuration); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrSavingFiles, err)
+		}
+		progress.Get(ctx).OK()
+	}
 
 	return nil
 }
 
+// skipNotEntitled records the part of the configuration skipped because the API client is not entitled to its API
+func skipNotEntitled(part string) {
+	for _, skipped := range notEntitled {
+		if skipped == part {
+			return
+		}
+	}
+	notEntitled = append(notEntitled, part)
+}
+
+// reportNotEntitled reports parts of the configuration skipped because the API client is not entitled to their APIs
+func reportNotEntitled(ctx context.Context, configName string) {
+	for _, part := range notEntitled {
+		warnings.Report(ctx, warnings.Warning{
+			Product: "appsec",
+			Object:  configName,
+			Reason:  fmt.Sprintf("%s skipped, the API client or the contract is not entitled to it (HTTP 403)", part),
+		})
+	}
+}
+
 // Find the id of a security configuration if we know its name
 func findConfigurationIDByName(ctx context.Context, name string, client appsec.APPSEC) (int, int, error) {
 	getConfigurationsResponse, err := client.GetConfigurations(ctx, appsec.GetConfigurationsRequest{
@@ -210,16 +291,20 @@ func exportConfiguration(ctx context.Context, id int, version int, client appsec
 // Get the description for the given security configuration id
 func getConfigDescription(configid int) (string, error) {
 
+	description := "Created by Terraform"
 	getConfigurationResponse, err := client.GetConfiguration(context.Background(), appsec.GetConfigurationRequest{
 		ConfigID: configid,
 	})
+	if apierrors.NotEntitled(err) {
+		skipNotEntitled("description of the configuration")
+		return description, nil
+	}
 	if err != nil {
 		return "", err
 	}
 
-	description := getConfigurationResponse.Description
-	if description == "" {
-		description = "Created by Terraform"
+	if getConfigurationResponse.Description != "" {
+		description = getConfigurationResponse.Description
 	}
 
 	return description, nil
@@ -277,6 +362,11 @@ func getWAFMode(configid int, version int, policyid string) (string, error) {
 		PolicyID: policyid,
 		Version:  version,
 	})
+	// the WAF mode and rule actions depending on it are not exported
+	if apierrors.NotEntitled(err) {
+		skipNotEntitled(fmt.Sprintf("WAF mode of security policy %s", policyid))
+		return "", nil
+	}
 	if err != nil {
 		return "", err
 	}
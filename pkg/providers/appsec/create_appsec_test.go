@@ -1,7 +1,9 @@
 package appsec
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -13,6 +15,8 @@ import (
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/appsec"
 	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -48,6 +52,18 @@ func TestGetConfigDescription(t *testing.T) {
 	assert.Equal(t, "Created by Terraform", description)
 }
 
+func TestGetConfigDescriptionNotEntitled(t *testing.T) {
+	ma := new(appsec.Mock)
+	ma.On("GetConfiguration", mock.Anything, appsec.GetConfigurationRequest{ConfigID: 12345}).Return(nil, &appsec.Error{StatusCode: 403})
+	client = ma
+	notEntitled = nil
+
+	description, err := getConfigDescription(12345)
+	assert.NoError(t, err)
+	assert.Equal(t, "Created by Terraform", description)
+	assert.Equal(t, []string{"description of the configuration"}, notEntitled)
+}
+
 func TestGetWAFMode(t *testing.T) {
 	mocks := func(c *appsec.Mock) {
 		c.On("GetWAFMode", mock.Anything, appsec.GetWAFModeRequest{ConfigID: 12345, Version: 1, PolicyID: "ASE1_156138"}).Return(&appsec.GetWAFModeResponse{Mode: "KRS"}, nil)
@@ -63,6 +79,68 @@ func TestGetWAFMode(t *testing.T) {
 	assert.Equal(t, "KRS", wafMode)
 }
 
+func TestGetWAFModeNotEntitled(t *testing.T) {
+	ma := new(appsec.Mock)
+	ma.On("GetWAFMode", mock.Anything, appsec.GetWAFModeRequest{ConfigID: 12345, Version: 1, PolicyID: "ASE1_156138"}).Return(nil, &appsec.Error{StatusCode: 403})
+	ma.On("GetWAFMode", mock.Anything, appsec.GetWAFModeRequest{ConfigID: 12345, Version: 1, PolicyID: "ASE1_156139"}).Return(nil, &appsec.Error{StatusCode: 500})
+	client = ma
+	notEntitled = nil
+
+	// the WAF mode of each policy is read by several templates, it is reported once
+	for i := 0; i < 2; i++ {
+		wafMode, err := getWAFMode(12345, 1, "ASE1_156138")
+		assert.NoError(t, err)
+		assert.Empty(t, wafMode)
+	}
+	assert.Equal(t, []string{"WAF mode of security policy ASE1_156138"}, notEntitled)
+
+	_, err := getWAFMode(12345, 1, "ASE1_156139")
+	assert.Error(t, err)
+}
+
+func TestProcessWAFTemplateNotEntitled(t *testing.T) {
+	ma := new(appsec.Mock)
+	ma.On("GetWAFMode", mock.Anything, mock.Anything).Return(nil, &appsec.Error{StatusCode: 403})
+	client = ma
+	notEntitled = nil
+
+	dir := t.TempDir()
+	processor := templates.FSTemplateProcessor{
+		TemplatesFS: templateFiles,
+		TemplateTargets: map[string]string{
+			"modules-security-waf.tmpl": filepath.Join(dir, "waf.tf"),
+			"imports.tmpl":              filepath.Join(dir, "import.sh"),
+		},
+		AdditionalFuncs: template.FuncMap{
+			"getCustomRuleNameByID": getCustomRuleNameByID,
+			"getRepNameByID":        getRepNameByID,
+			"getRuleNameByID":       getRuleNameByID,
+			"getRuleDescByID":       getRuleDescByID,
+			"getRateNameByID":       getRateNameByID,
+			"getMalwareNameByID":    getMalwareNameByID,
+			"getPolicyNameByID":     getPolicyNameByID,
+			"getWAFMode":            getWAFMode,
+			"getCloneName":          func() string { return "" },
+			"getConfigDescription":  getConfigDescription,
+			"getPrefixFromID":       getPrefixFromID,
+			"getSection":            getSection,
+			"isStructuredRule":      isStructuredRule,
+			"exportJSON":            exportJSON,
+		},
+	}
+	require.NoError(t, processor.ProcessTemplates(getExportConfiguratonResponse("ase")))
+
+	waf, err := ioutil.ReadFile(filepath.Join(dir, "waf.tf"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(waf), "akamai_appsec_waf_mode")
+	assert.NotContains(t, string(waf), "akamai_appsec_rule\"")
+	imports, err := ioutil.ReadFile(filepath.Join(dir, "import.sh"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(imports), "akamai_appsec_waf_mode")
+	assert.Contains(t, string(imports), "akamai_appsec_waf_protection")
+	assert.NotEmpty(t, notEntitled)
+}
+
 func TestExportCustomDenyList(t *testing.T) {
 
 	testdata := `{
@@ -295,6 +373,7 @@ func TestProcessPolicyTemplates(t *testing.T) {
 		"getMalwareNameByID":    getMalwareNameByID,
 		"getPolicyNameByID":     getPolicyNameByID,
 		"getWAFMode":            getWAFMode,
+		"getCloneName":          func() string { return "" },
 		"getConfigDescription":  getConfigDescription,
 		"getPrefixFromID":       getPrefixFromID,
 		"getSection":            getSection,
@@ -377,3 +456,123 @@ func TestProcessPolicyTemplates(t *testing.T) {
 	}
 	require.NoError(t, os.RemoveAll("./testdata/res"))
 }
+
+func TestProcessCloneTemplates(t *testing.T) {
+	ma := new(appsec.Mock)
+	ma.On("GetWAFMode", mock.Anything, mock.Anything).Return(&appsec.GetWAFModeResponse{Mode: "KRS"}, nil)
+	client = ma
+
+	dir := "./testdata/res/ase_clone"
+	processor, err := newProcessor(context.Background(), dir, "TFDEMO clone")
+	require.NoError(t, err)
+	require.NoError(t, processor.ProcessTemplates(getExportConfiguratonResponse("ase")))
+
+	// files of the clone differing from the faithful export
+	files := []string{
+		"appsec.tf",
+		"appsec-variables.tf",
+		filepath.Join("modules", "security", "policies.tf"),
+		filepath.Join("modules", "security", "selected-hostnames.tf"),
+		filepath.Join("modules", "security", "variables.tf"),
+	}
+	for _, f := range files {
+		expected, err := ioutil.ReadFile(filepath.Join("./testdata/ase_clone", f))
+		require.NoError(t, err)
+		result, err := ioutil.ReadFile(filepath.Join(dir, f))
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), string(result), f)
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "appsec-import.sh"))
+	require.NoError(t, os.RemoveAll("./testdata/res"))
+}
+
+func TestCreateAppsec(t *testing.T) {
+	configuration := getExportConfiguratonResponse("ase")
+	var configurations appsec.GetConfigurationsResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"configurations": [{"id": 79947, "name": "TFDEMO", "latestVersion": 1}]}`), &configurations))
+
+	tests := map[string]struct {
+		init             func(*appsec.Mock, *mockProcessor, *mockProcessor)
+		clone            bool
+		expectedWarnings int
+		withError        error
+	}{
+		"export": {
+			init: func(c *appsec.Mock, p, _ *mockProcessor) {
+				c.On("GetConfigurations", mock.Anything, appsec.GetConfigurationsRequest{}).
+					Return(&configurations, nil).Once()
+				c.On("GetExportConfiguration", mock.Anything, appsec.GetExportConfigurationRequest{ConfigID: 79947, Version: 1}).
+					Return(configuration, nil).Once()
+				p.On("ProcessTemplates", configuration).Return(nil).Once()
+			},
+		},
+		"export with clone": {
+			init: func(c *appsec.Mock, p, cp *mockProcessor) {
+				c.On("GetConfigurations", mock.Anything, appsec.GetConfigurationsRequest{}).
+					Return(&configurations, nil).Once()
+				c.On("GetExportConfiguration", mock.Anything, appsec.GetExportConfigurationRequest{ConfigID: 79947, Version: 1}).
+					Return(configuration, nil).Once()
+				p.On("ProcessTemplates", configuration).Return(nil).Once()
+				cp.On("ProcessTemplates", configuration).Return(nil).Once()
+			},
+			clone: true,
+		},
+		"error saving clone": {
+			init: func(c *appsec.Mock, p, cp *mockProcessor) {
+				c.On("GetConfigurations", mock.Anything, appsec.GetConfigurationsRequest{}).
+					Return(&configurations, nil).Once()
+				c.On("GetExportConfiguration", mock.Anything, appsec.GetExportConfigurationRequest{ConfigID: 79947, Version: 1}).
+					Return(configuration, nil).Once()
+				p.On("ProcessTemplates", configuration).Return(nil).Once()
+				cp.On("ProcessTemplates", configuration).Return(templates.ErrSavingFiles).Once()
+			},
+			clone:     true,
+			withError: ErrSavingFiles,
+		},
+		"export without entitlement to WAF mode": {
+			init: func(c *appsec.Mock, p, _ *mockProcessor) {
+				c.On("GetConfigurations", mock.Anything, appsec.GetConfigurationsRequest{}).
+					Return(&configurations, nil).Once()
+				c.On("GetExportConfiguration", mock.Anything, appsec.GetExportConfigurationRequest{ConfigID: 79947, Version: 1}).
+					Return(configuration, nil).Once()
+				p.On("ProcessTemplates", configuration).Return(nil).Once().Run(func(mock.Arguments) {
+					skipNotEntitled("WAF mode of security policy ASE1_156138")
+				})
+			},
+			expectedWarnings: 1,
+		},
+		"configuration not found": {
+			init: func(c *appsec.Mock, _, _ *mockProcessor) {
+				c.On("GetConfigurations", mock.Anything, appsec.GetConfigurationsRequest{}).
+					Return(&appsec.GetConfigurationsResponse{}, nil).Once()
+			},
+			withError: ErrFetchingPolicy,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mc := new(appsec.Mock)
+			mp := new(mockProcessor)
+			mcp := new(mockProcessor)
+			test.init(mc, mp, mcp)
+			var cloneProcessor templates.TemplateProcessor
+			if test.clone {
+				cloneProcessor = mcp
+			}
+			collector := warnings.NewCollector()
+			ctx := warnings.WithCollector(context.Background(), collector)
+			ctx = terminal.Context(ctx, terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			err := createAppsec(ctx, "TFDEMO", mc, mp, cloneProcessor)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			mc.AssertExpectations(t)
+			mp.AssertExpectations(t)
+			mcp.AssertExpectations(t)
+			assert.Len(t, collector.Warnings(), test.expectedWarnings)
+		})
+	}
+}
@@ -1,12 +1,28 @@
 terraform init
 terraform import module.security.akamai_appsec_configuration.config 79947
-terraform import module.security.akamai_appsec_advanced_settings_logging.logging 79947
 terraform import module.security.akamai_appsec_advanced_settings_prefetch.prefetch 79947
-terraform import module.security.akamai_appsec_advanced_settings_pragma_header.pragma_header 79947
 terraform import module.security.akamai_appsec_custom_rule.custom_rule_1_60088542 79947:60088542
 terraform import module.security.akamai_appsec_custom_deny.deny_message_deny_custom_78842 79947:deny_custom_78842
-terraform import module.security.akamai_appsec_match_target.website_4262513 79947:4262513
 terraform import module.security.akamai_appsec_security_policy.default_policy 79947:ASE1_156138
+terraform import module.security.akamai_appsec_rate_policy.page_view_requests 79947:177906
+terraform import module.security.akamai_appsec_rate_policy.origin_error 79947:177907
+terraform import module.security.akamai_appsec_rate_policy.post_page_requests 79947:177908
+terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_high_threat 79947:3017089
+terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_low_threat 79947:3017090
+terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_high_threat 79947:3017091
+terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_low_threat 79947:3017092
+terraform import module.security.akamai_appsec_reputation_profile.web_attackers_high_threat 79947:3017093
+terraform import module.security.akamai_appsec_reputation_profile.web_attackers_low_threat 79947:3017094
+terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_high_threat 79947:3017095
+terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_low_threat 79947:3017096
+terraform import module.security.akamai_appsec_selected_hostnames.hostnames 79947
+terraform import module.activate-security.akamai_appsec_activations.appsecactivation 79947:1:STAGING
+terraform import module.security.akamai_appsec_malware_policy.fms_configuration_1 79947:1187
+terraform import module.security.akamai_appsec_malware_policy.fms_configuration_2 79947:1186
+terraform import module.security.akamai_appsec_malware_policy.fms_configuration_3 79947:1185
+terraform import module.security.akamai_appsec_advanced_settings_logging.logging 79947
+terraform import module.security.akamai_appsec_advanced_settings_pragma_header.pragma_header 79947
+terraform import module.security.akamai_appsec_match_target.website_4262513 79947:4262513
 terraform import module.security.akamai_appsec_waf_protection.default_policy 79947:ASE1_156138
 terraform import module.security.akamai_appsec_api_constraints_protection.default_policy 79947:ASE1_156138
 terraform import module.security.akamai_appsec_ip_geo_protection.default_policy 79947:ASE1_156138
@@ -15,6 +31,19 @@ terraform import module.security.akamai_appsec_rate_protection.default_policy 79
 terraform import module.security.akamai_appsec_reputation_protection.default_policy 79947:ASE1_156138
 terraform import module.security.akamai_appsec_slowpost_protection.default_policy 79947:ASE1_156138
 terraform import module.security.akamai_appsec_waf_mode.default_policy 79947:ASE1_156138
+terraform import module.security.akamai_appsec_api_request_constraints.default_policy_12345 79947:ASE1_156138:12345
+terraform import module.security.akamai_appsec_api_request_constraints.default_policy_12346 79947:ASE1_156138:12346
+terraform import module.security.akamai_appsec_reputation_profile_action.default_policy_3017089 79947:ASE1_156138:3017089
+terraform import module.security.akamai_appsec_rate_policy_action.default_policy_page_view_requests 79947:ASE1_156138:177906
+terraform import module.security.akamai_appsec_rate_policy_action.default_policy_origin_error 79947:ASE1_156138:177907
+terraform import module.security.akamai_appsec_rate_policy_action.default_policy_post_page_requests 79947:ASE1_156138:177908
+terraform import module.security.akamai_appsec_malware_policy_action.default_policy_fms_configuration_1 79947:ASE1_156138:1187
+terraform import module.security.akamai_appsec_malware_policy_action.default_policy_fms_configuration_2 79947:ASE1_156138:1186
+terraform import module.security.akamai_appsec_malware_policy_action.default_policy_fms_configuration_3 79947:ASE1_156138:1185
+terraform import module.security.akamai_appsec_ip_geo.default_policy 79947:ASE1_156138
+terraform import module.security.akamai_appsec_penalty_box.default_policy 79947:ASE1_156138
+terraform import module.security.akamai_appsec_slow_post.default_policy 79947:ASE1_156138
+terraform import module.security.akamai_appsec_siem_settings.siem 79947
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackcmd_injection_950002 79947:ASE1_156138:950002
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackcmd_injection_950006 79947:ASE1_156138:950006
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attacksql_injection_950007 79947:ASE1_156138:950007
@@ -169,8 +198,11 @@ terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attack
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackcmd_injection_3000171 79947:ASE1_156138:3000171
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackprotocol_3000173 79947:ASE1_156138:3000173
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackprotocol_3000174 79947:ASE1_156138:3000174
+
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackprotocol_3000175 79947:ASE1_156138:3000175
+
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackprotocol_3000176 79947:ASE1_156138:3000176
+
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackprotocol_3000177 79947:ASE1_156138:3000177
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackplatform_3000179 79947:ASE1_156138:3000179
 terraform import module.security.akamai_appsec_rule.default_policy_aseweb_attackpolicy_3000180 79947:ASE1_156138:3000180
@@ -186,35 +218,3 @@ terraform import module.security.akamai_appsec_attack_group.default_policy_CMD 7
 terraform import module.security.akamai_appsec_attack_group.default_policy_LFI 79947:ASE1_156138:LFI
 terraform import module.security.akamai_appsec_attack_group.default_policy_RFI 79947:ASE1_156138:RFI
 terraform import module.security.akamai_appsec_attack_group.default_policy_PLATFORM 79947:ASE1_156138:PLATFORM
-terraform import module.security.akamai_appsec_api_request_constraints.default_policy_12345 79947:ASE1_156138:12345
-terraform import module.security.akamai_appsec_api_request_constraints.default_policy_12346 79947:ASE1_156138:12346
-terraform import module.security.akamai_appsec_reputation_profile_action.default_policy_3017089 79947:ASE1_156138:3017089
-terraform import module.security.akamai_appsec_rate_policy_action.default_policy_page_view_requests 79947:ASE1_156138:177906
-terraform import module.security.akamai_appsec_rate_policy_action.default_policy_origin_error 79947:ASE1_156138:177907
-terraform import module.security.akamai_appsec_rate_policy_action.default_policy_post_page_requests 79947:ASE1_156138:177908
-terraform import module.security.akamai_appsec_malware_policy_action.default_policy_fms_configuration_1 79947:ASE1_156138:1187
-terraform import module.security.akamai_appsec_malware_policy_action.default_policy_fms_configuration_2 79947:ASE1_156138:1186
-terraform import module.security.akamai_appsec_malware_policy_action.default_policy_fms_configuration_3 79947:ASE1_156138:1185
-terraform import module.security.akamai_appsec_ip_geo.default_policy 79947:ASE1_156138
-terraform import module.security.akamai_appsec_penalty_box.default_policy 79947:ASE1_156138
-terraform import module.security.akamai_appsec_slow_post.default_policy 79947:ASE1_156138
-
-terraform import module.security.akamai_appsec_rate_policy.page_view_requests 79947:177906
-
-terraform import module.security.akamai_appsec_rate_policy.origin_error 79947:177907
-
-terraform import module.security.akamai_appsec_rate_policy.post_page_requests 79947:177908
-terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_high_threat 79947:3017089
-terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_low_threat 79947:3017090
-terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_high_threat 79947:3017091
-terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_low_threat 79947:3017092
-terraform import module.security.akamai_appsec_reputation_profile.web_attackers_high_threat 79947:3017093
-terraform import module.security.akamai_appsec_reputation_profile.web_attackers_low_threat 79947:3017094
-terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_high_threat 79947:3017095
-terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_low_threat 79947:3017096
-terraform import module.security.akamai_appsec_siem_settings.siem 79947
-terraform import module.security.akamai_appsec_selected_hostnames.hostnames 79947
-terraform import module.activate-security.akamai_appsec_activations.appsecactivation 79947:1:STAGING
-terraform import module.security.akamai_appsec_malware_policy.fms_configuration_1 79947:1187
-terraform import module.security.akamai_appsec_malware_policy.fms_configuration_2 79947:1186
-terraform import module.security.akamai_appsec_malware_policy.fms_configuration_3 79947:1185
@@ -0,0 +1,46 @@
+variable "group_name" {
+  type    = string
+  default = ""
+}
+
+variable "contract_id" {
+  type    = string
+  default = ""
+}
+
+variable "name" {
+  type    = string
+  default = "TFDEMO clone"
+}
+
+variable "description" {
+  type    = string
+  default = "Cloned from TFDEMO"
+}
+
+variable "hostnames" {
+  description = "Hostnames protected by the new configuration"
+  type        = list(string)
+}
+
+variable "security_policy_names" {
+  type = map(string)
+  default = {
+    default_policy = "Default Policy"
+  }
+}
+
+variable "emails" {
+  type    = list(string)
+  default = ["noreply@example.org"]
+}
+
+variable "activation_note" {
+  type    = string
+  default = "Activated by Terraform"
+}
+
+variable "network" {
+  type    = string
+  default = "STAGING"
+}
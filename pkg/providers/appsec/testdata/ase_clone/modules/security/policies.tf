@@ -0,0 +1,7 @@
+resource "akamai_appsec_security_policy" "default_policy" {
+  config_id              = akamai_appsec_configuration.config.config_id
+  default_settings       = true
+  security_policy_name   = var.security_policy_names["default_policy"]
+  security_policy_prefix = "ASE1"
+}
+
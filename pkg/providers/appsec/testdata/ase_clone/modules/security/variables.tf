@@ -0,0 +1,23 @@
+variable "name" {
+  type = string
+}
+
+variable "hostnames" {
+  type = list(string)
+}
+
+variable "description" {
+  type = string
+}
+
+variable "contract_id" {
+  type = string
+}
+
+variable "group_name" {
+  type = string
+}
+
+variable "security_policy_names" {
+  type = map(string)
+}
@@ -1,16 +1,33 @@
 terraform init
 terraform import module.security.akamai_appsec_configuration.config 32641
-terraform import module.security.akamai_appsec_advanced_settings_logging.logging 32641
 terraform import module.security.akamai_appsec_advanced_settings_prefetch.prefetch 32641
-terraform import module.security.akamai_appsec_advanced_settings_pragma_header.pragma_header 32641
 terraform import module.security.akamai_appsec_custom_rule.custom_rule_1_60088542 32641:60088542
 terraform import module.security.akamai_appsec_custom_deny.deny_message_deny_custom_78842 32641:deny_custom_78842
 terraform import module.security.akamai_appsec_custom_deny.deny_message_2_deny_custom_80270 32641:deny_custom_80270
+terraform import module.security.akamai_appsec_security_policy.policy2 32641:hard_150670
+terraform import module.security.akamai_appsec_security_policy.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_security_policy.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_rate_policy.high_rate 32641:172320
+terraform import module.security.akamai_appsec_rate_policy.low_rate 32641:172321
+terraform import module.security.akamai_appsec_reputation_profile.web_attackers_high_threat 32641:2670508
+terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_high_threat 32641:2670509
+terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_high_threat 32641:2670510
+terraform import module.security.akamai_appsec_reputation_profile.web_attackers_low_threat 32641:2670511
+terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_low_threat 32641:2670512
+terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_low_threat 32641:2670513
+terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_low_threat 32641:2670514
+terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_high_threat 32641:2670515
+terraform import module.security.akamai_appsec_selected_hostnames.hostnames 32641
+terraform import module.activate-security.akamai_appsec_activations.appsecactivation 32641:45:STAGING
+terraform import module.security.akamai_appsec_malware_policy.fms_configuration_1 32641:1187
+terraform import module.security.akamai_appsec_malware_policy.fms_configuration_2 32641:1186
+terraform import module.security.akamai_appsec_malware_policy.fms_configuration_3 32641:1185
+terraform import module.security.akamai_appsec_advanced_settings_logging.logging 32641
+terraform import module.security.akamai_appsec_advanced_settings_pragma_header.pragma_header 32641
 terraform import module.security.akamai_appsec_match_target.website_4092331 32641:4092331
 terraform import module.security.akamai_appsec_match_target.website_2034325 32641:2034325
 terraform import module.security.akamai_appsec_match_target.website_4092261 32641:4092261
 terraform import module.security.akamai_appsec_match_target.api_4124908 32641:4124908
-terraform import module.security.akamai_appsec_security_policy.policy2 32641:hard_150670
 terraform import module.security.akamai_appsec_waf_protection.policy2 32641:hard_150670
 terraform import module.security.akamai_appsec_api_constraints_protection.policy2 32641:hard_150670
 terraform import module.security.akamai_appsec_ip_geo_protection.policy2 32641:hard_150670
@@ -19,6 +36,50 @@ terraform import module.security.akamai_appsec_rate_protection.policy2 32641:har
 terraform import module.security.akamai_appsec_reputation_protection.policy2 32641:hard_150670
 terraform import module.security.akamai_appsec_slowpost_protection.policy2 32641:hard_150670
 terraform import module.security.akamai_appsec_waf_mode.policy2 32641:hard_150670
+terraform import module.security.akamai_appsec_rate_policy_action.policy2_high_rate 32641:hard_150670:172320
+terraform import module.security.akamai_appsec_rate_policy_action.policy2_low_rate 32641:hard_150670:172321
+terraform import module.security.akamai_appsec_malware_policy_action.policy2_fms_configuration_1 32641:hard_150670:1187
+terraform import module.security.akamai_appsec_malware_policy_action.policy2_fms_configuration_2 32641:hard_150670:1186
+terraform import module.security.akamai_appsec_malware_policy_action.policy2_fms_configuration_3 32641:hard_150670:1185
+terraform import module.security.akamai_appsec_ip_geo.policy2 32641:hard_150670
+terraform import module.security.akamai_appsec_slow_post.policy2 32641:hard_150670
+terraform import module.security.akamai_appsec_waf_protection.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_api_constraints_protection.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_ip_geo_protection.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_malware_protection.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_rate_protection.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_reputation_protection.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_slowpost_protection.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_waf_mode.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_api_request_constraints.andrew_767805 32641:last_150674:767805
+terraform import module.security.akamai_appsec_reputation_profile_action.andrew_2670508 32641:last_150674:2670508
+terraform import module.security.akamai_appsec_reputation_profile_action.andrew_2670509 32641:last_150674:2670509
+terraform import module.security.akamai_appsec_ip_geo.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_penalty_box.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_slow_post.andrew 32641:last_150674
+terraform import module.security.akamai_appsec_waf_protection.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_api_constraints_protection.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_ip_geo_protection.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_malware_protection.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_rate_protection.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_reputation_protection.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_slowpost_protection.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_waf_mode.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_custom_rule_action.policy1_60088542 32641:easy_80433:60088542
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670508 32641:easy_80433:2670508
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670509 32641:easy_80433:2670509
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670510 32641:easy_80433:2670510
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670511 32641:easy_80433:2670511
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670512 32641:easy_80433:2670512
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670513 32641:easy_80433:2670513
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670514 32641:easy_80433:2670514
+terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670515 32641:easy_80433:2670515
+terraform import module.security.akamai_appsec_rate_policy_action.policy1_high_rate 32641:easy_80433:172320
+terraform import module.security.akamai_appsec_rate_policy_action.policy1_low_rate 32641:easy_80433:172321
+terraform import module.security.akamai_appsec_ip_geo.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_slow_post.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_advanced_settings_logging.policy1 32641:easy_80433
+terraform import module.security.akamai_appsec_siem_settings.siem 32641
 terraform import module.security.akamai_appsec_rule.policy2_akamaipragma_deflection_699989 32641:hard_150670:699989
 terraform import module.security.akamai_appsec_rule.policy2_akamaibot_detect_3_v4_699996 32641:hard_150670:699996
 terraform import module.security.akamai_appsec_rule.policy2_owasp_crsweb_attacksession_fixation_950000 32641:hard_150670:950000
@@ -303,22 +364,6 @@ terraform import module.security.akamai_appsec_attack_group.policy2_TROJAN 32641
 terraform import module.security.akamai_appsec_attack_group.policy2_DDOS 32641:hard_150670:DDOS
 terraform import module.security.akamai_appsec_attack_group.policy2_IN 32641:hard_150670:IN
 terraform import module.security.akamai_appsec_attack_group.policy2_OUT 32641:hard_150670:OUT
-terraform import module.security.akamai_appsec_rate_policy_action.policy2_high_rate 32641:hard_150670:172320
-terraform import module.security.akamai_appsec_rate_policy_action.policy2_low_rate 32641:hard_150670:172321
-terraform import module.security.akamai_appsec_malware_policy_action.policy2_fms_configuration_1 32641:hard_150670:1187
-terraform import module.security.akamai_appsec_malware_policy_action.policy2_fms_configuration_2 32641:hard_150670:1186
-terraform import module.security.akamai_appsec_malware_policy_action.policy2_fms_configuration_3 32641:hard_150670:1185
-terraform import module.security.akamai_appsec_ip_geo.policy2 32641:hard_150670
-terraform import module.security.akamai_appsec_slow_post.policy2 32641:hard_150670
-terraform import module.security.akamai_appsec_security_policy.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_waf_protection.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_api_constraints_protection.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_ip_geo_protection.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_malware_protection.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_rate_protection.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_reputation_protection.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_slowpost_protection.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_waf_mode.andrew 32641:last_150674
 terraform import module.security.akamai_appsec_rule.andrew_aseweb_attackcmd_injection_950002 32641:last_150674:950002
 terraform import module.security.akamai_appsec_rule.andrew_aseweb_attackcmd_injection_950006 32641:last_150674:950006
 terraform import module.security.akamai_appsec_rule.andrew_aseweb_attacksql_injection_950007 32641:last_150674:950007
@@ -493,21 +538,6 @@ terraform import module.security.akamai_appsec_attack_group.andrew_CMD 32641:las
 terraform import module.security.akamai_appsec_attack_group.andrew_LFI 32641:last_150674:LFI
 terraform import module.security.akamai_appsec_attack_group.andrew_RFI 32641:last_150674:RFI
 terraform import module.security.akamai_appsec_attack_group.andrew_PLATFORM 32641:last_150674:PLATFORM
-terraform import module.security.akamai_appsec_api_request_constraints.andrew_767805 32641:last_150674:767805
-terraform import module.security.akamai_appsec_reputation_profile_action.andrew_2670508 32641:last_150674:2670508
-terraform import module.security.akamai_appsec_reputation_profile_action.andrew_2670509 32641:last_150674:2670509
-terraform import module.security.akamai_appsec_ip_geo.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_penalty_box.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_slow_post.andrew 32641:last_150674
-terraform import module.security.akamai_appsec_security_policy.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_waf_protection.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_api_constraints_protection.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_ip_geo_protection.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_malware_protection.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_rate_protection.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_reputation_protection.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_slowpost_protection.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_waf_mode.policy1 32641:easy_80433
 terraform import module.security.akamai_appsec_rule.policy1_akamaipragma_deflection_699989 32641:easy_80433:699989
 terraform import module.security.akamai_appsec_rule.policy1_akamaibot_detect_3_v4_699996 32641:easy_80433:699996
 terraform import module.security.akamai_appsec_rule.policy1_owasp_crsweb_attacksession_fixation_950000 32641:easy_80433:950000
@@ -775,14 +805,15 @@ terraform import module.security.akamai_appsec_rule.policy1_akamaiprotocol_viola
 terraform import module.security.akamai_appsec_rule.policy1_akamaiprotocol_violationhttp_desync_3000075 32641:easy_80433:3000075
 terraform import module.security.akamai_appsec_rule.policy1_akamaiprotocol_violationhttp_desync_3000076 32641:easy_80433:3000076
 terraform import module.security.akamai_appsec_rule.policy1_akamaiprotocol_violationhttp_desync_3000077 32641:easy_80433:3000077
+
 terraform import module.security.akamai_appsec_rule.policy1_akamaiweb_attacksharepoint_deserial_3000079 32641:easy_80433:3000079
+
 terraform import module.security.akamai_appsec_rule.policy1_aseweb_attackxss_3000080 32641:easy_80433:3000080
 terraform import module.security.akamai_appsec_rule.policy1_aseweb_attackxss_3000081 32641:easy_80433:3000081
 terraform import module.security.akamai_appsec_rule.policy1_owasp_crsweb_attackxss_3000082 32641:easy_80433:3000082
 terraform import module.security.akamai_appsec_rule.policy1_owasp_crsweb_attackplatform_3000083 32641:easy_80433:3000083
 terraform import module.security.akamai_appsec_rule.policy1_owasp_crsweb_attackplatform_3000084 32641:easy_80433:3000084
 terraform import module.security.akamai_appsec_rule.policy1_akamaiweb_attackproxy_header_detected_3000999 32641:easy_80433:3000999
-terraform import module.security.akamai_appsec_custom_rule_action.policy1_60088542 32641:easy_80433:60088542
 terraform import module.security.akamai_appsec_attack_group.policy1_SQL 32641:easy_80433:SQL
 terraform import module.security.akamai_appsec_attack_group.policy1_XSS 32641:easy_80433:XSS
 terraform import module.security.akamai_appsec_attack_group.policy1_CMD 32641:easy_80433:CMD
@@ -792,34 +823,3 @@ terraform import module.security.akamai_appsec_attack_group.policy1_PHP 32641:ea
 terraform import module.security.akamai_appsec_attack_group.policy1_TROJAN 32641:easy_80433:TROJAN
 terraform import module.security.akamai_appsec_attack_group.policy1_IN 32641:easy_80433:IN
 terraform import module.security.akamai_appsec_attack_group.policy1_OUT 32641:easy_80433:OUT
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670508 32641:easy_80433:2670508
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670509 32641:easy_80433:2670509
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670510 32641:easy_80433:2670510
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670511 32641:easy_80433:2670511
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670512 32641:easy_80433:2670512
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670513 32641:easy_80433:2670513
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670514 32641:easy_80433:2670514
-terraform import module.security.akamai_appsec_reputation_profile_action.policy1_2670515 32641:easy_80433:2670515
-terraform import module.security.akamai_appsec_rate_policy_action.policy1_high_rate 32641:easy_80433:172320
-terraform import module.security.akamai_appsec_rate_policy_action.policy1_low_rate 32641:easy_80433:172321
-terraform import module.security.akamai_appsec_ip_geo.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_slow_post.policy1 32641:easy_80433
-terraform import module.security.akamai_appsec_advanced_settings_logging.policy1 32641:easy_80433
-
-terraform import module.security.akamai_appsec_rate_policy.high_rate 32641:172320
-
-terraform import module.security.akamai_appsec_rate_policy.low_rate 32641:172321
-terraform import module.security.akamai_appsec_reputation_profile.web_attackers_high_threat 32641:2670508
-terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_high_threat 32641:2670509
-terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_high_threat 32641:2670510
-terraform import module.security.akamai_appsec_reputation_profile.web_attackers_low_threat 32641:2670511
-terraform import module.security.akamai_appsec_reputation_profile.dos_attackers_low_threat 32641:2670512
-terraform import module.security.akamai_appsec_reputation_profile.scanning_tools_low_threat 32641:2670513
-terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_low_threat 32641:2670514
-terraform import module.security.akamai_appsec_reputation_profile.web_scrapers_high_threat 32641:2670515
-terraform import module.security.akamai_appsec_siem_settings.siem 32641
-terraform import module.security.akamai_appsec_selected_hostnames.hostnames 32641
-terraform import module.activate-security.akamai_appsec_activations.appsecactivation 32641:45:STAGING
-terraform import module.security.akamai_appsec_malware_policy.fms_configuration_1 32641:1187
-terraform import module.security.akamai_appsec_malware_policy.fms_configuration_2 32641:1186
-terraform import module.security.akamai_appsec_malware_policy.fms_configuration_3 32641:1185
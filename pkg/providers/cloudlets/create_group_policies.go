@@ -0,0 +1,240 @@
+package cloudlets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// groupPolicy is a policy of the exported group or account, either a legacy policy or a shared policy of Cloudlets API v3
+type groupPolicy struct {
+	ID           int64
+	GroupID      int64
+	Name         string
+	CloudletCode string
+}
+
+// skippedPolicy is a policy which is not exported, listed in the summary printed at the end of the export
+type skippedPolicy struct {
+	groupPolicy
+	Reason string
+}
+
+// ErrListingGroupPolicies is returned when policies of the group or the account cannot be listed
+var ErrListingGroupPolicies = exitcode.New(exitcode.API, "unable to list policies")
+
+// CmdCreateGroupPolicies is an entrypoint to export-cloudlets-policy command with all flag, which exports every policy
+// of the group given with group-id into its own subdirectory of tfworkpath, named after the policy; without group-id
+// every policy of the account is exported into '<group id>/<policy name>' subdirectories. Policies of unsupported
+// cloudlet types are skipped, with continue-on-unsupported flag the command fails only if no policy was exported
+func CmdCreateGroupPolicies(c *cli.Context) error {
+	ctx := c.Context
+	sess := edgegrid.GetSession(c.Context)
+	client := cloudlets.Client(sess)
+	clientShared := shared.Client(sess)
+
+	// tfWorkPath is a root directory under which each policy is exported into its own subdirectory
+	var tfWorkPath = "./"
+	if c.IsSet("tfworkpath") {
+		tfWorkPath = c.String("tfworkpath")
+	}
+
+	groupID := c.Int64("group-id")
+	if c.IsSet("group-id") && groupID <= 0 {
+		return cli.Exit(color.RedString("group-id flag must be positive"), exitcode.General)
+	}
+	for _, flag := range []string{"policy-id", "version"} {
+		if c.IsSet(flag) {
+			return cli.Exit(color.RedString(fmt.Sprintf("%s flag cannot be used with all flag", flag)), exitcode.General)
+		}
+	}
+
+	scope := policiesScope(groupID)
+	terminal.Get(ctx).Printf("Configuring Policies of %s\n", strings.ToUpper(scope[:1])+scope[1:])
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching policies of %s", scope))
+	policies, skipped, err := listGroupPolicies(ctx, groupID, client, clientShared)
+	if err != nil {
+		progress.Get(ctx).Fail()
+		err = fmt.Errorf("%w: %s", ErrListingGroupPolicies, err)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting policies: %s", err)), exitcode.Of(err))
+	}
+	progress.Get(ctx).OK()
+	if len(policies) == 0 {
+		terminal.Get(ctx).Printf("No policies found in %s\n", scope)
+		printSkippedPolicies(ctx, skipped)
+		if len(skipped) > 0 && c.Bool("continue-on-unsupported") {
+			err = fmt.Errorf("%w: all %d policies of %s are not supported", batch.ErrNothingExported, len(skipped), scope)
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		return nil
+	}
+	err = batch.Run(c, groupPolicyObjects(policies, policyArgs(c), groupID <= 0), tfWorkPath)
+	printSkippedPolicies(ctx, skipped)
+	return err
+}
+
+// listGroupPolicies returns legacy and shared policies of the group which can be exported, sorted by their names, and
+// policies which are skipped as their cloudlet types are not supported; groupID 0 lists policies of all groups, sorted
+// by their groups first. Skipped policies are reported as warnings, shared policies are skipped with a warning when
+// the client has no access to Cloudlets API v3
+func listGroupPolicies(ctx context.Context, groupID int64, client cloudlets.Cloudlets, clientShared shared.Policies) ([]groupPolicy, []skippedPolicy, error) {
+	inScope := func(id int64) bool {
+		return groupID <= 0 || id == groupID
+	}
+	var policies []groupPolicy
+	pageSize, offset := tools.PageSize(tools.PageSizeCloudlets, 1000), 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		page, err := client.ListPolicies(ctx, cloudlets.ListPoliciesRequest{
+			Offset:   offset,
+			PageSize: &pageSize,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range page {
+			if inScope(p.GroupID) {
+				policies = append(policies, groupPolicy{ID: p.PolicyID, GroupID: p.GroupID, Name: p.Name, CloudletCode: p.CloudletCode})
+			}
+		}
+		if len(page) < pageSize {
+			break
+		}
+		offset += pageSize
+	}
+
+	for page := 0; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		result, err := clientShared.ListPolicies(ctx, shared.ListPoliciesRequest{Page: page, Size: pageSize})
+		if apierrors.NotEntitled(err) {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "cloudlets policy",
+				Object:  policiesScope(groupID),
+				Reason:  fmt.Sprintf("shared policies are skipped: %s", err),
+			})
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range result.Content {
+			if inScope(p.GroupID) {
+				policies = append(policies, groupPolicy{ID: p.ID, GroupID: p.GroupID, Name: p.Name, CloudletCode: p.CloudletType})
+			}
+		}
+		if len(result.Content) < pageSize || page+1 >= result.Page.TotalPages {
+			break
+		}
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].GroupID != policies[j].GroupID && groupID <= 0 {
+			return policies[i].GroupID < policies[j].GroupID
+		}
+		return policies[i].Name < policies[j].Name
+	})
+	supported := make([]groupPolicy, 0, len(policies))
+	var skipped []skippedPolicy
+	for _, policy := range policies {
+		if !IsSupported(policy.CloudletCode) {
+			reason := fmt.Sprintf("%s: %s", ErrCloudletTypeNotSupported, policy.CloudletCode)
+			warnings.Report(ctx, warnings.Warning{
+				Product: "cloudlets policy",
+				Object:  policy.Name,
+				Reason:  fmt.Sprintf("policy is skipped: %s", reason),
+			})
+			skipped = append(skipped, skippedPolicy{groupPolicy: policy, Reason: reason})
+			continue
+		}
+		supported = append(supported, policy)
+	}
+	return supported, skipped, nil
+}
+
+// policiesScope describes policies listed for groupID, 0 meaning all groups of the account
+func policiesScope(groupID int64) string {
+	if groupID > 0 {
+		return fmt.Sprintf("group %d", groupID)
+	}
+	return "account"
+}
+
+// printSkippedPolicies prints the summary of policies which are not exported
+func printSkippedPolicies(ctx context.Context, skipped []skippedPolicy) {
+	if len(skipped) == 0 {
+		return
+	}
+	term := terminal.Get(ctx)
+	term.Printf("Skipped %d unsupported policies:\n", len(skipped))
+	for _, policy := range skipped {
+		term.Printf("  %s (ID %d, group %d): %s\n", policy.Name, policy.ID, policy.GroupID, policy.Reason)
+	}
+}
+
+// groupPolicyObjects returns objects exporting the policies by their IDs with the arguments, each into a subdirectory
+// named after the policy, nested in a subdirectory named after the group of the policy when byGroup is set; a policy
+// whose name is already used gets its ID appended to the name of its subdirectory
+func groupPolicyObjects(policies []groupPolicy, args []string, byGroup bool) []manifest.Object {
+	objects := make([]manifest.Object, 0, len(policies))
+	used := make(map[string]bool, len(policies))
+	for _, policy := range policies {
+		parent := ""
+		if byGroup {
+			parent = fmt.Sprintf("%d/", policy.GroupID)
+		}
+		dir := parent + policy.Name
+		if used[dir] {
+			dir = fmt.Sprintf("%s%s_%d", parent, policy.Name, policy.ID)
+		}
+		used[dir] = true
+		objects = append(objects, manifest.Object{
+			Product:  "cloudlets",
+			Name:     policy.Name,
+			ID:       strconv.FormatInt(policy.ID, 10),
+			Command:  "export-cloudlets-policy",
+			Args:     append([]string{"--policy-id", strconv.FormatInt(policy.ID, 10)}, args...),
+			Selected: true,
+			Dir:      dir,
+		})
+	}
+	return objects
+}
+
+// policyArgs returns flags of the command which are passed to exports of single policies
+func policyArgs(c *cli.Context) []string {
+	var args []string
+	for _, flag := range []string{"read-only", "as-data", "match-rules-module", "match-rules-json", "split-match-rules", "skip-activations", "import-blocks", "full-history", "as-module", "migrate-to-shared", "tfvars"} {
+		if c.Bool(flag) {
+			args = append(args, "--"+flag)
+		}
+	}
+	for _, flag := range []string{"resource-prefix", "resource-naming", "active-version"} {
+		if c.IsSet(flag) {
+			args = append(args, "--"+flag, c.String(flag))
+		}
+	}
+	if c.IsSet("version-history") {
+		args = append(args, "--version-history", strconv.Itoa(c.Int("version-history")))
+	}
+	return args
+}
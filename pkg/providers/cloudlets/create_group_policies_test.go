@@ -0,0 +1,321 @@
+package cloudlets
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/fakedata"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestListGroupPolicies(t *testing.T) {
+	pageSize := 2
+	tests := map[string]struct {
+		groupID          int64
+		init             func(*cloudlets.Mock, *shared.Mock)
+		expected         []groupPolicy
+		expectedSkipped  []skippedPolicy
+		expectedWarnings []string
+		withError        string
+	}{
+		"legacy and shared policies of the group": {
+			groupID: 123,
+			init: func(c *cloudlets.Mock, s *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{PolicyID: 1, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+					{PolicyID: 2, GroupID: 456, Name: "other group", CloudletCode: "ER"},
+				}, nil).Once()
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 2}).Return([]cloudlets.Policy{
+					{PolicyID: 3, GroupID: 123, Name: "balancer", CloudletCode: "ALB"},
+				}, nil).Once()
+				s.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{ID: 11, GroupID: 123, Name: "forwards", CloudletType: "FR"}},
+					Page:    shared.Page{Size: pageSize, TotalElements: 1, TotalPages: 1},
+				}, nil).Once()
+			},
+			expected: []groupPolicy{
+				{ID: 3, GroupID: 123, Name: "balancer", CloudletCode: "ALB"},
+				{ID: 11, GroupID: 123, Name: "forwards", CloudletCode: "FR"},
+				{ID: 1, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+			},
+		},
+		"policies of all groups of the account": {
+			init: func(c *cloudlets.Mock, s *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{PolicyID: 1, GroupID: 456, Name: "redirects", CloudletCode: "ER"},
+					{PolicyID: 2, GroupID: 123, Name: "mobile", CloudletCode: "MMB"},
+				}, nil).Once()
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 2}).Return([]cloudlets.Policy{
+					{PolicyID: 3, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+				}, nil).Once()
+				s.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{ID: 11, GroupID: 456, Name: "forwards", CloudletType: "FR"}},
+					Page:    shared.Page{Size: pageSize, TotalElements: 1, TotalPages: 1},
+				}, nil).Once()
+			},
+			expected: []groupPolicy{
+				{ID: 3, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+				{ID: 11, GroupID: 456, Name: "forwards", CloudletCode: "FR"},
+				{ID: 1, GroupID: 456, Name: "redirects", CloudletCode: "ER"},
+			},
+			expectedSkipped: []skippedPolicy{
+				{groupPolicy: groupPolicy{ID: 2, GroupID: 123, Name: "mobile", CloudletCode: "MMB"}, Reason: "cloudlet type not supported: MMB"},
+			},
+			expectedWarnings: []string{"policy is skipped: cloudlet type not supported: MMB"},
+		},
+		"policy of unsupported cloudlet type is skipped": {
+			groupID: 123,
+			init: func(c *cloudlets.Mock, s *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{PolicyID: 1, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+					{PolicyID: 2, GroupID: 123, Name: "mobile", CloudletCode: "MMB"},
+				}, nil).Once()
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 2}).Return([]cloudlets.Policy{}, nil).Once()
+				s.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+					Page: shared.Page{Size: pageSize},
+				}, nil).Once()
+			},
+			expected: []groupPolicy{{ID: 1, GroupID: 123, Name: "redirects", CloudletCode: "ER"}},
+			expectedSkipped: []skippedPolicy{
+				{groupPolicy: groupPolicy{ID: 2, GroupID: 123, Name: "mobile", CloudletCode: "MMB"}, Reason: "cloudlet type not supported: MMB"},
+			},
+			expectedWarnings: []string{"policy is skipped: cloudlet type not supported: MMB"},
+		},
+		"shared policies skipped without access": {
+			groupID: 123,
+			init: func(c *cloudlets.Mock, s *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{PolicyID: 1, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+				}, nil).Once()
+				s.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(nil, &shared.Error{StatusCode: 403, Title: "Forbidden"}).Once()
+			},
+			expected:         []groupPolicy{{ID: 1, GroupID: 123, Name: "redirects", CloudletCode: "ER"}},
+			expectedWarnings: []string{"shared policies are skipped"},
+		},
+		"error listing policies": {
+			init: func(c *cloudlets.Mock, s *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: "oops",
+		},
+		"error listing shared policies": {
+			init: func(c *cloudlets.Mock, s *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{}, nil).Once()
+				s.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(nil, &shared.Error{StatusCode: 500, Title: "Internal Server Error"}).Once()
+			},
+			withError: "Internal Server Error",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools.PageSizes = map[string]int{tools.PageSizeCloudlets: pageSize}
+			defer func() { tools.PageSizes = nil }()
+			client := new(cloudlets.Mock)
+			clientShared := new(shared.Mock)
+			test.init(client, clientShared)
+			collector := warnings.NewCollector()
+			ctx := warnings.WithCollector(context.Background(), collector)
+
+			policies, skipped, err := listGroupPolicies(ctx, test.groupID, client, clientShared)
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, policies)
+			assert.Equal(t, test.expectedSkipped, skipped)
+			require.Len(t, collector.Warnings(), len(test.expectedWarnings))
+			for i, warning := range test.expectedWarnings {
+				assert.Contains(t, collector.Warnings()[i].Reason, warning)
+			}
+			client.AssertExpectations(t)
+			clientShared.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListGroupPoliciesPages(t *testing.T) {
+	pageSize := 7
+	tools.PageSizes = map[string]int{tools.PageSizeCloudlets: pageSize}
+	defer func() { tools.PageSizes = nil }()
+	policies := fakedata.New(1).Policies(100)
+
+	client := new(cloudlets.Mock)
+	for offset := 0; offset <= len(policies); offset += pageSize {
+		end := offset + pageSize
+		if end > len(policies) {
+			end = len(policies)
+		}
+		page := policies[offset:end]
+		client.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: offset}).Return(page, nil).Once()
+	}
+	clientShared := new(shared.Mock)
+	clientShared.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+		Page: shared.Page{Size: pageSize},
+	}, nil).Once()
+
+	var expected []groupPolicy
+	for _, policy := range policies {
+		if policy.GroupID == 1001 {
+			expected = append(expected, groupPolicy{ID: policy.PolicyID, GroupID: policy.GroupID, Name: policy.Name, CloudletCode: policy.CloudletCode})
+		}
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		return expected[i].Name < expected[j].Name
+	})
+
+	result, _, err := listGroupPolicies(context.Background(), 1001, client, clientShared)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result)
+	assert.Equal(t, expected, result)
+	client.AssertExpectations(t)
+}
+
+func TestGroupPolicyObjects(t *testing.T) {
+	policies := []groupPolicy{
+		{ID: 1, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+		{ID: 11, GroupID: 123, Name: "redirects", CloudletCode: "ER"},
+		{ID: 12, GroupID: 456, Name: "redirects", CloudletCode: "ER"},
+	}
+
+	objects := groupPolicyObjects(policies[:2], []string{"--read-only"}, false)
+	assert.Equal(t, []manifest.Object{
+		{
+			Product:  "cloudlets",
+			Name:     "redirects",
+			ID:       "1",
+			Command:  "export-cloudlets-policy",
+			Args:     []string{"--policy-id", "1", "--read-only"},
+			Selected: true,
+			Dir:      "redirects",
+		},
+		{
+			Product:  "cloudlets",
+			Name:     "redirects",
+			ID:       "11",
+			Command:  "export-cloudlets-policy",
+			Args:     []string{"--policy-id", "11", "--read-only"},
+			Selected: true,
+			Dir:      "redirects_11",
+		},
+	}, objects)
+
+	objects = groupPolicyObjects(policies, nil, true)
+	dirs := make([]string, 0, len(objects))
+	for _, object := range objects {
+		dirs = append(dirs, object.Dir)
+	}
+	assert.Equal(t, []string{"123/redirects", "123/redirects_11", "456/redirects"}, dirs)
+}
+
+func TestPrintSkippedPolicies(t *testing.T) {
+	tests := map[string]struct {
+		skipped []skippedPolicy
+		init    func(*terminal.Mock)
+	}{
+		"nothing skipped": {
+			init: func(*terminal.Mock) {},
+		},
+		"skipped policies": {
+			skipped: []skippedPolicy{
+				{groupPolicy: groupPolicy{ID: 2, GroupID: 123, Name: "mobile", CloudletCode: "MMB"}, Reason: "cloudlet type not supported: MMB"},
+				{groupPolicy: groupPolicy{ID: 5, GroupID: 456, Name: "limits", CloudletCode: "VP"}, Reason: "cloudlet type not supported: VP"},
+			},
+			init: func(term *terminal.Mock) {
+				term.On("Printf", "Skipped %d unsupported policies:\n", []interface{}{2}).Once()
+				term.On("Printf", "  %s (ID %d, group %d): %s\n", []interface{}{"mobile", int64(2), int64(123), "cloudlet type not supported: MMB"}).Once()
+				term.On("Printf", "  %s (ID %d, group %d): %s\n", []interface{}{"limits", int64(5), int64(456), "cloudlet type not supported: VP"}).Once()
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			term := &terminal.Mock{}
+			test.init(term)
+
+			printSkippedPolicies(terminal.Context(context.Background(), term), test.skipped)
+			term.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPolicyArgs(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"no flags": {},
+		"flags passed to exports of policies": {
+			args:     []string{"--group-id", "123", "--all", "--match-rules-module", "--version-history", "3"},
+			expected: []string{"--match-rules-module", "--version-history", "3"},
+		},
+		"match rules as JSON": {
+			args:     []string{"--all", "--match-rules-json"},
+			expected: []string{"--match-rules-json"},
+		},
+		"activations skipped": {
+			args:     []string{"--all", "--skip-activations"},
+			expected: []string{"--skip-activations"},
+		},
+		"import blocks": {
+			args:     []string{"--all", "--import-blocks"},
+			expected: []string{"--import-blocks"},
+		},
+		"full history": {
+			args:     []string{"--all", "--full-history"},
+			expected: []string{"--full-history"},
+		},
+		"as data": {
+			args:     []string{"--all", "--as-data"},
+			expected: []string{"--as-data"},
+		},
+		"resource naming": {
+			args:     []string{"--all", "--resource-prefix", "edge_", "--resource-naming", "hash"},
+			expected: []string{"--resource-prefix", "edge_", "--resource-naming", "hash"},
+		},
+		"tfvars": {
+			args:     []string{"--all", "--tfvars"},
+			expected: []string{"--tfvars"},
+		},
+		"active version": {
+			args:     []string{"--all", "--active-version", "production"},
+			expected: []string{"--active-version", "production"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set := flag.NewFlagSet("test", flag.ContinueOnError)
+			set.Int64("group-id", 0, "")
+			set.Bool("all", false, "")
+			set.Bool("read-only", false, "")
+			set.Bool("as-data", false, "")
+			set.Bool("match-rules-module", false, "")
+			set.Bool("match-rules-json", false, "")
+			set.Bool("skip-activations", false, "")
+			set.Bool("import-blocks", false, "")
+			set.Bool("full-history", false, "")
+			set.Bool("tfvars", false, "")
+			set.String("resource-prefix", "", "")
+			set.String("resource-naming", "", "")
+			set.String("active-version", "", "")
+			set.Int("version-history", 0, "")
+			require.NoError(t, set.Parse(test.args))
+
+			assert.Equal(t, test.expected, policyArgs(cli.NewContext(cli.NewApp(), set, nil)))
+		})
+	}
+}
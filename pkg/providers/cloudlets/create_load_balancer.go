@@ -0,0 +1,138 @@
+package cloudlets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/origins"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// ErrFetchingLoadBalancer is returned when fetching versions or activations of the load balancer fails
+	ErrFetchingLoadBalancer = exitcode.New(exitcode.API, "unable to fetch load balancer")
+
+	errLoadBalancerNotFound = errors.New("load balancer does not exist or has no versions")
+)
+
+// CmdCreateLoadBalancer is an entrypoint to export-load-balancer command, which exports the latest version of the
+// application load balancer with the given origin ID and its activations without any policy
+func CmdCreateLoadBalancer(c *cli.Context) error {
+	ctx := c.Context
+	sess := edgegrid.GetSession(c.Context)
+	client := cloudlets.Client(sess)
+
+	// tfWorkPath is a target directory for generated terraform resources
+	var tfWorkPath = "./"
+	if c.IsSet("tfworkpath") {
+		tfWorkPath = c.String("tfworkpath")
+	}
+
+	loadBalancerPath := filepath.Join(tfWorkPath, "load-balancer.tf")
+	variablesPath := filepath.Join(tfWorkPath, "variables.tf")
+	importPath := filepath.Join(tfWorkPath, "import.sh")
+	importBlocksPath := filepath.Join(tfWorkPath, "imports.tf")
+	if err := tools.CheckFiles(loadBalancerPath, variablesPath, importPath, importBlocksPath); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	processor := templates.FSTemplateProcessor{
+		TemplatesFS: templateFiles,
+		TemplateTargets: map[string]string{
+			"load-balancer-standalone.tmpl": loadBalancerPath,
+			"variables.tmpl":                variablesPath,
+			"load-balancer-imports.tmpl":    importPath,
+		},
+		Output: templates.GetOutputTarget(ctx),
+		Sink:   templates.GetSink(ctx),
+	}
+	options := policyOptions{
+		skipActivations: c.Bool("skip-activations"),
+		importBlocks:    c.Bool("import-blocks"),
+		resourcePrefix:  c.String("resource-prefix"),
+	}
+	if err := ValidateResourceNaming("", options.resourcePrefix); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.General)
+	}
+	if options.importBlocks {
+		processor.TemplateTargets["load-balancer-import-blocks.tmpl"] = importBlocksPath
+		delete(processor.TemplateTargets, "load-balancer-imports.tmpl")
+	}
+
+	originID := c.Args().First()
+	section := edgegrid.GetEdgercSection(c)
+	if err := createLoadBalancer(ctx, originID, section, options, client, processor); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting load balancer HCL: %s", err)), exitcode.Of(err))
+	}
+	return nil
+}
+
+// createLoadBalancer exports the latest version of the load balancer with its activations, unless they are skipped by
+// the options, importing them with import blocks instead of the import script when the options set import blocks
+func createLoadBalancer(ctx context.Context, originID, section string, options policyOptions, client cloudlets.Cloudlets, templateProcessor templates.TemplateProcessor) error {
+	terminal.Get(ctx).Printf("Configuring Load Balancer\n")
+	progress.Get(ctx).Start("Fetching load balancer " + originID)
+
+	tfData := TFPolicyData{
+		Section:         section,
+		SkipActivations: options.skipActivations,
+		ImportBlocks:    options.importBlocks,
+		ResourcePrefix:  options.resourcePrefix,
+	}
+	originIDs := []string{originID}
+	steps := 2
+	if options.skipActivations {
+		steps = 1
+	}
+	progress.Get(ctx).Total(steps)
+
+	var err error
+	tfData.LoadBalancers, err = getLoadBalancers(ctx, client, originIDs)
+	if apierrors.StatusCode(err) == http.StatusNotFound || (err == nil && len(tfData.LoadBalancers) == 0) {
+		err = fmt.Errorf("%w: %s", errLoadBalancerNotFound, originID)
+	}
+	if err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrFetchingLoadBalancer, err)
+	}
+	if !options.skipActivations {
+		if tfData.LoadBalancerActivations, err = getLoadBalancerActivations(ctx, client, originIDs); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingLoadBalancer, err)
+		}
+		reportInactiveLoadBalancerVersions(ctx, tfData.LoadBalancers, tfData.LoadBalancerActivations)
+	}
+
+	loadBalancer := tfData.LoadBalancers[0]
+	workspace.RecordObject(ctx, workspace.Object{
+		Product: "cloudlets-load-balancer",
+		ID:      loadBalancer.OriginID,
+		Name:    loadBalancer.OriginID,
+		Version: strconv.FormatInt(loadBalancer.Version, 10),
+	})
+	origins.Record(ctx, "cloudlets load balancer "+originID, loadBalancerOrigins(tfData.LoadBalancers)...)
+
+	progress.Get(ctx).OK()
+	progress.Get(ctx).Start("Saving TF configurations ")
+	if err = templateProcessor.ProcessTemplates(tfData); err != nil {
+		progress.Get(ctx).Fail()
+		return err
+	}
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for load balancer '%s' was saved successfully\n", originID)
+	return nil
+}
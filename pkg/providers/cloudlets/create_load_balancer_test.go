@@ -0,0 +1,224 @@
+package cloudlets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/testutils"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateLoadBalancer(t *testing.T) {
+	section := "test_section"
+	versions := []cloudlets.LoadBalancerVersion{
+		{OriginID: "test_origin", Version: 1},
+		{OriginID: "test_origin", Version: 2, BalancingType: cloudlets.BalancingTypeWeighted},
+	}
+	activations := []cloudlets.LoadBalancerActivation{
+		{
+			ActivatedDate: "2021-10-29T00:00:20.000Z",
+			Network:       cloudlets.LoadBalancerActivationNetworkStaging,
+			OriginID:      "test_origin",
+			Status:        cloudlets.LoadBalancerActivationStatusActive,
+			Version:       2,
+		},
+	}
+	tests := map[string]struct {
+		init            func(*cloudlets.Mock, *mockProcessor)
+		skipActivations bool
+		withError       error
+	}{
+		"latest version with activations": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return(versions, nil).Once()
+				c.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{OriginID: "test_origin"}).
+					Return(activations, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Section:                 section,
+					LoadBalancers:           versions[1:],
+					LoadBalancerActivations: activations,
+				}).Return(nil).Once()
+			},
+		},
+		"activations skipped": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return(versions, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Section:         section,
+					LoadBalancers:   versions[1:],
+					SkipActivations: true,
+				}).Return(nil).Once()
+			},
+			skipActivations: true,
+		},
+		"load balancer without versions": {
+			init: func(c *cloudlets.Mock, _ *mockProcessor) {
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return([]cloudlets.LoadBalancerVersion{}, nil).Once()
+			},
+			withError: ErrFetchingLoadBalancer,
+		},
+		"load balancer not found": {
+			init: func(c *cloudlets.Mock, _ *mockProcessor) {
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return(nil, &cloudlets.Error{StatusCode: http.StatusNotFound}).Once()
+			},
+			withError: ErrFetchingLoadBalancer,
+		},
+		"error listing activations": {
+			init: func(c *cloudlets.Mock, _ *mockProcessor) {
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return(versions, nil).Once()
+				c.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{OriginID: "test_origin"}).
+					Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingLoadBalancer,
+		},
+		"error processing template": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return(versions, nil).Once()
+				p.On("ProcessTemplates", mock.Anything).Return(templates.ErrSavingFiles).Once()
+			},
+			skipActivations: true,
+			withError:       templates.ErrSavingFiles,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mc := new(cloudlets.Mock)
+			mp := new(mockProcessor)
+			test.init(mc, mp)
+			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			err := createLoadBalancer(ctx, "test_origin", section, policyOptions{skipActivations: test.skipActivations}, mc, mp)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			mc.AssertExpectations(t)
+			mp.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessLoadBalancerTemplates(t *testing.T) {
+	loadBalancers := []cloudlets.LoadBalancerVersion{
+		{
+			OriginID:      "test_origin",
+			Description:   "test description",
+			BalancingType: cloudlets.BalancingTypeWeighted,
+			DataCenters: []cloudlets.DataCenter{
+				{
+					City:      "Boston",
+					Continent: "NA",
+					Country:   "US",
+					Hostname:  "test-hostname",
+					Latitude:  tools.Float64Ptr(102.78108),
+					Longitude: tools.Float64Ptr(-116.07064),
+					OriginID:  "test_origin",
+					Percent:   tools.Float64Ptr(100),
+				},
+			},
+			Version: 2,
+		},
+	}
+	tests := map[string]struct {
+		givenData    TFPolicyData
+		dir          string
+		importBlocks bool
+	}{
+		"load balancer with activations": {
+			givenData: TFPolicyData{
+				Section:       "test_section",
+				LoadBalancers: loadBalancers,
+				LoadBalancerActivations: []cloudlets.LoadBalancerActivation{
+					{
+						Network:  cloudlets.LoadBalancerActivationNetworkStaging,
+						OriginID: "test_origin",
+						Status:   cloudlets.LoadBalancerActivationStatusActive,
+						Version:  2,
+					},
+				},
+			},
+			dir: "load_balancer",
+		},
+		"load balancer with activations skipped": {
+			givenData: TFPolicyData{
+				Section:         "test_section",
+				LoadBalancers:   loadBalancers,
+				SkipActivations: true,
+			},
+			dir: "load_balancer_skip_activations",
+		},
+		"load balancer with import blocks": {
+			givenData: TFPolicyData{
+				Section:       "test_section",
+				LoadBalancers: loadBalancers,
+				LoadBalancerActivations: []cloudlets.LoadBalancerActivation{
+					{
+						Network:  cloudlets.LoadBalancerActivationNetworkStaging,
+						OriginID: "test_origin",
+						Status:   cloudlets.LoadBalancerActivationStatusActive,
+						Version:  2,
+					},
+				},
+				ImportBlocks: true,
+			},
+			dir:          "load_balancer_import_blocks",
+			importBlocks: true,
+		},
+		"load balancer with resource prefix": {
+			givenData: TFPolicyData{
+				Section:       "test_section",
+				LoadBalancers: loadBalancers,
+				LoadBalancerActivations: []cloudlets.LoadBalancerActivation{
+					{
+						Network:  cloudlets.LoadBalancerActivationNetworkStaging,
+						OriginID: "test_origin",
+						Status:   cloudlets.LoadBalancerActivationStatusActive,
+						Version:  2,
+					},
+				},
+				ResourcePrefix: "edge_",
+			},
+			dir: "load_balancer_resource_prefix",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.MkdirAll(fmt.Sprintf("./testdata/res/%s", test.dir), 0755))
+			processor := templates.FSTemplateProcessor{
+				TemplatesFS: templateFiles,
+				TemplateTargets: map[string]string{
+					"load-balancer-standalone.tmpl": fmt.Sprintf("./testdata/res/%s/load-balancer.tf", test.dir),
+					"variables.tmpl":                fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+					"load-balancer-imports.tmpl":    fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
+				},
+			}
+			importFile := "import.sh"
+			if test.importBlocks {
+				importFile = "imports.tf"
+				delete(processor.TemplateTargets, "load-balancer-imports.tmpl")
+				processor.TemplateTargets["load-balancer-import-blocks.tmpl"] = fmt.Sprintf("./testdata/res/%s/%s", test.dir, importFile)
+			}
+			require.NoError(t, processor.ProcessTemplates(test.givenData))
+
+			testutils.AssertFiles(t, fmt.Sprintf("./testdata/%s", test.dir), fmt.Sprintf("./testdata/res/%s", test.dir), "load-balancer.tf", "variables.tf", importFile)
+		})
+	}
+}
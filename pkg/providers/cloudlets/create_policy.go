@@ -6,15 +6,25 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"net/http"
 	"path/filepath"
 	"reflect"
 	"sort"
-	"text/template"
+	"strconv"
+	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
+	"github.com/akamai/cli-terraform/pkg/batch"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/origins"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -24,6 +34,8 @@ type (
 	// TFPolicyData represents the data used in policy templates
 	TFPolicyData struct {
 		Name                    string
+		PolicyID                int64
+		Version                 int64
 		CloudletCode            string
 		Description             string
 		GroupID                 int64
@@ -32,7 +44,46 @@ type (
 		PolicyActivations       map[string]TFPolicyActivationData
 		LoadBalancers           []cloudlets.LoadBalancerVersion
 		LoadBalancerActivations []cloudlets.LoadBalancerActivation
-		Section                 string
+		// MissingLoadBalancers are origin IDs forwarded to by match rules whose load balancers do not exist or have no
+		// versions, exported as commented out placeholders of load balancer resources
+		MissingLoadBalancers []string
+		Section              string
+		// MatchRulesLocals are match rules passed to the generic match rules module, set when match rules are exported
+		// with the module instead of fully expanded data source
+		MatchRulesLocals []string
+		// MatchRulesJSON are match rules written into match-rules.json, set when match rules are exported as JSON
+		// loaded by the configuration instead of fully expanded data source
+		MatchRulesJSON string
+		// MatchRulesFiles are slash separated paths of files in the rules directory, one per match rule in the order
+		// of the policy, set when match rules are split into files loaded by the configuration
+		MatchRulesFiles []string
+		// VersionHistory are the latest versions of the policy annotated as comments, newest first
+		VersionHistory []TFPolicyVersionHistory
+		// NoVersions is set when the policy has no versions, the policy is exported with a placeholder for match rules
+		NoVersions bool
+		// IsShared is set for shared policies of Cloudlets API v3, which have no match rule format and whose
+		// activations are exported as separate resources per network
+		IsShared bool
+		// SkipActivations is set when activations of the policy and its load balancers are managed outside of
+		// Terraform, their resources are not exported
+		SkipActivations bool
+		// ImportBlocks is set when resources are imported with import blocks of Terraform 1.5 in imports.tf instead of
+		// the import script
+		ImportBlocks bool
+		// ResourcePrefix is prepended to names of exported resources, data sources, local values and modules, so that
+		// several policies can be exported into one workspace
+		ResourcePrefix string
+		// ResourceNaming is the strategy deriving names of the policy resources from the policy name, empty value keeps
+		// the static names
+		ResourceNaming string
+		// AsModule is set when the policy is exported as a module parameterized with its group, activation network
+		// and properties, instantiated by the root configuration with values of terraform.tfvars
+		AsModule bool
+		// MigrateToShared is set when the legacy policy is exported with the shape of the shared policy resource and
+		// commands importing it, which help migrating the policy to a shared policy of Cloudlets API v3
+		MigrateToShared bool
+		// EdgercPath is the path of the credentials file used by the export, written into terraform.tfvars
+		EdgercPath string
 	}
 
 	// TFPolicyActivationData represents data used in policy activation resource templates
@@ -43,9 +94,58 @@ type (
 	}
 )
 
+// matchRulesForm is the form in which match rules of the exported policy are written
+type matchRulesForm int
+
+const (
+	// matchRulesExpanded writes match rules as the fully expanded data source of the cloudlet type
+	matchRulesExpanded matchRulesForm = iota
+	// matchRulesModule writes match rules as locals consumed by the generic match rules module
+	matchRulesModule
+	// matchRulesJSON writes match rules into match-rules.json, loaded by the configuration with jsondecode
+	matchRulesJSON
+	// matchRulesSplit writes every match rule into its own JSON file of the rules directory, loaded by the
+	// configuration with jsondecode in the order of the policy
+	matchRulesSplit
+)
+
+// policyOptions are options of the export of a single policy given by flags of the command
+type policyOptions struct {
+	rulesForm       matchRulesForm
+	versionHistory  int
+	skipActivations bool
+	importBlocks    bool
+	resourcePrefix  string
+	resourceNaming  string
+	// historyDir is the directory into which match rules of every version of the policy are written, set when full
+	// version history is exported
+	historyDir string
+	// policyDir is the directory of the exported policy, into which files of split match rules are written
+	policyDir       string
+	asModule        bool
+	migrateToShared bool
+	// activeNetwork is the network whose active version of the policy is exported instead of its latest version, set
+	// with active-version flag
+	activeNetwork network
+	// edgercPath is the path of the credentials file written into terraform.tfvars, set with tfvars flag
+	edgercPath string
+}
+
 //go:embed templates/*
 var templateFiles embed.FS
 
+// matchRulesDataSources are data sources building match rules of cloudlet types
+var matchRulesDataSources = map[string]string{
+	"ALB": "akamai_cloudlets_application_load_balancer_match_rule",
+	"AP":  "akamai_cloudlets_api_prioritization_match_rule",
+	"AS":  "akamai_cloudlets_audience_segmentation_match_rule",
+	"CD":  "akamai_cloudlets_phased_release_match_rule",
+	"ER":  "akamai_cloudlets_edge_redirector_match_rule",
+	"FR":  "akamai_cloudlets_forward_rewrite_match_rule",
+	"IG":  "akamai_cloudlets_request_control_match_rule",
+	"VP":  "akamai_cloudlets_visitor_prioritization_match_rule",
+}
+
 var supportedCloudlets = map[string]struct{}{
 	"ALB": {},
 	"AP":  {},
@@ -57,13 +157,76 @@ var supportedCloudlets = map[string]struct{}{
 	"VP":  {},
 }
 
+// MatchRulesDataSource returns the data source building match rules of the cloudlet type of the policy
+func (d TFPolicyData) MatchRulesDataSource() string {
+	return matchRulesDataSources[d.CloudletCode]
+}
+
+// LoadBalancerImported returns true if the load balancer is active on the import network, on which activation
+// resources are created with the default value of env variable, so that its activation can be imported
+func (d TFPolicyData) LoadBalancerImported(originID string) bool {
+	n := d.importNetwork()
+	for _, activation := range d.LoadBalancerActivations {
+		if activation.OriginID == originID && activation.Network == loadBalancerNetworks[n] {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyActivationImported returns true if the policy activation resource is exported and the policy is active on
+// staging network, on which the resource is created with the default value of env variable
+func (d TFPolicyData) PolicyActivationImported() bool {
+	if d.SkipActivations {
+		return false
+	}
+	staging, ok := d.PolicyActivations[policyActivationKeys[networkStaging]]
+	if !ok {
+		return false
+	}
+	prod, ok := d.PolicyActivations[policyActivationKeys[networkProduction]]
+	return !ok || reflect.DeepEqual(prod.Properties, staging.Properties)
+}
+
+// EnvVariable returns true if variables.tf declares env variable, which is used by the activation resource of the
+// policy on a single network or by activations of its load balancers; activations of shared policies are exported per
+// network, and the variable is commented out when the policy has different properties on each network
+func (d TFPolicyData) EnvVariable() bool {
+	if d.SkipActivations {
+		return false
+	}
+	if d.IsShared || len(d.PolicyActivations) == 0 {
+		return len(d.LoadBalancers) > 0
+	}
+	staging, onStaging := d.PolicyActivations[policyActivationKeys[networkStaging]]
+	prod, onProd := d.PolicyActivations[policyActivationKeys[networkProduction]]
+	if onStaging && onProd {
+		return reflect.DeepEqual(prod.Properties, staging.Properties)
+	}
+	return onStaging || onProd
+}
+
+// IsSupported returns true if policies of the given cloudlet type can be exported
+func IsSupported(cloudletCode string) bool {
+	_, ok := supportedCloudlets[cloudletCode]
+	return ok
+}
+
 var (
 	// ErrFetchingPolicy is returned when fetching policy fails
-	ErrFetchingPolicy = errors.New("unable to fetch policy with given name")
+	ErrFetchingPolicy = exitcode.New(exitcode.API, "unable to fetch policy with given name")
 	// ErrFetchingVersion is returned when fetching policy version fails
-	ErrFetchingVersion = errors.New("unable to fetch latest policy version")
+	ErrFetchingVersion = exitcode.New(exitcode.API, "unable to fetch policy version")
+	// ErrFetchingVersionHistory is returned when fetching versions of the policy for its history fails
+	ErrFetchingVersionHistory = exitcode.New(exitcode.API, "unable to fetch policy version history")
+	// ErrFetchingPolicyProperties is returned when fetching properties which use the shared policy fails
+	ErrFetchingPolicyProperties = exitcode.New(exitcode.API, "unable to fetch properties of shared policy")
 	// ErrCloudletTypeNotSupported is returned when a provided cloudlet type is not yet supported
-	ErrCloudletTypeNotSupported = errors.New("cloudlet type not supported")
+	ErrCloudletTypeNotSupported = exitcode.New(exitcode.Unsupported, "cloudlet type not supported")
+
+	errNoPolicyVersions      = errors.New("no policy versions found for given policy")
+	errPolicyNotFound        = errors.New("policy does not exist")
+	errPolicyVersionNotFound = errors.New("policy version does not exist")
 )
 
 // CmdCreatePolicy is an entrypoint to create-policy command
@@ -71,6 +234,7 @@ func CmdCreatePolicy(c *cli.Context) error {
 	ctx := c.Context
 	sess := edgegrid.GetSession(c.Context)
 	client := cloudlets.Client(sess)
+	clientShared := shared.Client(sess)
 
 	// tfWorkPath is a target directory for generated terraform resources
 	var tfWorkPath = "./"
@@ -78,15 +242,65 @@ func CmdCreatePolicy(c *cli.Context) error {
 		tfWorkPath = c.String("tfworkpath")
 	}
 
-	policyPath := filepath.Join(tfWorkPath, "policy.tf")
-	matchRulesPath := filepath.Join(tfWorkPath, "match-rules.tf")
-	loadBalancerPath := filepath.Join(tfWorkPath, "load-balancer.tf")
-	variablesPath := filepath.Join(tfWorkPath, "variables.tf")
-	importPath := filepath.Join(tfWorkPath, "import.sh")
+	// configPath is a directory of the policy configuration, which is the module of the policy when it is exported as
+	// a module instantiated by the root configuration in tfWorkPath
+	configPath := tfWorkPath
+	asModule := c.Bool("as-module")
+	if asModule {
+		configPath = filepath.Join(tfWorkPath, "modules", "policy")
+	}
 
-	err := tools.CheckFiles(policyPath, matchRulesPath, loadBalancerPath, variablesPath, importPath)
+	policyPath := filepath.Join(configPath, "policy.tf")
+	matchRulesPath := filepath.Join(configPath, "match-rules.tf")
+	loadBalancerPath := filepath.Join(configPath, "load-balancer.tf")
+	variablesPath := filepath.Join(configPath, "variables.tf")
+	importPath := filepath.Join(tfWorkPath, "import.sh")
+	importBlocksPath := filepath.Join(tfWorkPath, "imports.tf")
+	historyPath := filepath.Join(configPath, "versions")
+	matchRulesModulePath := filepath.Join(configPath, "modules", "match-rules", "main.tf")
+	matchRulesJSONPath := filepath.Join(configPath, "match-rules.json")
+	matchRulesDirPath := filepath.Join(configPath, matchRulesDir)
+	outputsPath := filepath.Join(tfWorkPath, "outputs.tf")
+	migrationPath := filepath.Join(configPath, "shared-policy-migration.tf")
+	rootPath := filepath.Join(tfWorkPath, "main.tf")
+	rootVariablesPath := filepath.Join(tfWorkPath, "variables.tf")
+	tfvarsPath := filepath.Join(tfWorkPath, "terraform.tfvars")
+	tfvarsExamplePath := filepath.Join(tfWorkPath, "terraform.tfvars.example")
+
+	files := []string{policyPath, matchRulesPath, loadBalancerPath, variablesPath}
+	// import blocks replace the import script
+	if c.Bool("import-blocks") {
+		files = append(files, importBlocksPath)
+	} else {
+		files = append(files, importPath)
+	}
+	if c.Bool("match-rules-module") {
+		files = append(files, matchRulesModulePath)
+	}
+	if c.Bool("match-rules-json") {
+		files = append(files, matchRulesJSONPath)
+	}
+	if c.Bool("full-history") {
+		files = append(files, historyPath)
+	}
+	if c.Bool("as-data") {
+		files = append(files, outputsPath)
+	}
+	if c.Bool("split-match-rules") {
+		files = append(files, matchRulesDirPath)
+	}
+	if c.Bool("migrate-to-shared") {
+		files = append(files, migrationPath)
+	}
+	if asModule {
+		files = append(files, rootPath, rootVariablesPath, tfvarsPath)
+	}
+	if c.Bool("tfvars") {
+		files = append(files, tfvarsPath, tfvarsExamplePath)
+	}
+	err := tools.CheckFiles(files...)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 	templateToFile := map[string]string{
 		"policy.tmpl":        policyPath,
@@ -95,124 +309,432 @@ func CmdCreatePolicy(c *cli.Context) error {
 		"variables.tmpl":     variablesPath,
 		"imports.tmpl":       importPath,
 	}
+	options := policyOptions{
+		skipActivations: c.Bool("skip-activations"),
+		importBlocks:    c.Bool("import-blocks"),
+		resourcePrefix:  c.String("resource-prefix"),
+		resourceNaming:  c.String("resource-naming"),
+	}
+	if err = ValidateResourceNaming(options.resourceNaming, options.resourcePrefix); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.General)
+	}
+	if c.Bool("match-rules-module") && c.Bool("match-rules-json") {
+		return cli.Exit(color.RedString("match-rules-module flag cannot be used with match-rules-json flag"), exitcode.General)
+	}
+	if c.Bool("split-match-rules") {
+		for _, flag := range []string{"match-rules-module", "match-rules-json"} {
+			if c.Bool(flag) {
+				return cli.Exit(color.RedString(fmt.Sprintf("split-match-rules flag cannot be used with %s flag", flag)), exitcode.General)
+			}
+		}
+		options.rulesForm = matchRulesSplit
+		options.policyDir = configPath
+		templateToFile["match-rules-jsondecode.tmpl"] = matchRulesPath
+		delete(templateToFile, "match-rules.tmpl")
+	}
+	if c.Bool("match-rules-module") {
+		options.rulesForm = matchRulesModule
+		templateToFile["match-rules-locals.tmpl"] = matchRulesPath
+		templateToFile["match-rules-module.tmpl"] = matchRulesModulePath
+		delete(templateToFile, "match-rules.tmpl")
+	}
+	if c.Bool("match-rules-json") {
+		options.rulesForm = matchRulesJSON
+		templateToFile["match-rules-jsondecode.tmpl"] = matchRulesPath
+		templateToFile["match-rules-json.tmpl"] = matchRulesJSONPath
+		delete(templateToFile, "match-rules.tmpl")
+	}
+	if options.importBlocks {
+		templateToFile["import-blocks.tmpl"] = importBlocksPath
+		delete(templateToFile, "imports.tmpl")
+	}
+	if c.Bool("migrate-to-shared") {
+		options.migrateToShared = true
+		templateToFile["shared-policy-migration.tmpl"] = migrationPath
+	}
+	if asModule {
+		options.asModule = true
+		templateToFile["module-variables.tmpl"] = variablesPath
+		templateToFile["module-root.tmpl"] = rootPath
+		templateToFile["module-root-variables.tmpl"] = rootVariablesPath
+		templateToFile["module-tfvars.tmpl"] = tfvarsPath
+		delete(templateToFile, "variables.tmpl")
+	}
+	if c.Bool("read-only") && c.Bool("as-data") {
+		return cli.Exit(color.RedString("read-only flag cannot be used with as-data flag"), exitcode.General)
+	}
+	if readOnlyFlag := readOnlyMode(c); readOnlyFlag != "" {
+		for _, flag := range []string{"match-rules-module", "match-rules-json", "split-match-rules", "skip-activations", "import-blocks", "full-history", "as-module", "migrate-to-shared"} {
+			if c.Bool(flag) {
+				return cli.Exit(color.RedString(fmt.Sprintf("%s flag cannot be used with %s flag", flag, readOnlyFlag)), exitcode.General)
+			}
+		}
+		for _, flag := range []string{"resource-prefix", "resource-naming"} {
+			if c.IsSet(flag) {
+				return cli.Exit(color.RedString(fmt.Sprintf("%s flag cannot be used with %s flag", flag, readOnlyFlag)), exitcode.General)
+			}
+		}
+		templateToFile = map[string]string{
+			"policy-read-only.tmpl": policyPath,
+			"variables.tmpl":        variablesPath,
+		}
+		if c.Bool("as-data") {
+			templateToFile["policy-outputs.tmpl"] = outputsPath
+		}
+	}
+	if c.Bool("tfvars") {
+		if asModule {
+			return cli.Exit(color.RedString("tfvars flag cannot be used with as-module flag, which writes terraform.tfvars of the module"), exitcode.General)
+		}
+		options.edgercPath = edgegrid.GetEdgercPath(c)
+		templateToFile["tfvars.tmpl"] = tfvarsPath
+		templateToFile["tfvars-example.tmpl"] = tfvarsExamplePath
+	}
 
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
-		AdditionalFuncs: template.FuncMap{
-			"deepequal": reflect.DeepEqual,
-		},
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
+	}
+
+	options.versionHistory = c.Int("version-history")
+	if options.versionHistory < 0 {
+		return cli.Exit(color.RedString("version-history flag must not be negative"), exitcode.General)
+	}
+	if c.Bool("full-history") {
+		if c.IsSet("version-history") {
+			return cli.Exit(color.RedString("version-history flag cannot be used with full-history flag"), exitcode.General)
+		}
+		options.historyDir = historyPath
+	}
+
+	policyID := c.Int64("policy-id")
+	if c.IsSet("policy-id") && policyID <= 0 {
+		return cli.Exit(color.RedString("policy-id flag must be positive"), exitcode.General)
+	}
+
+	for _, flag := range []string{"group-id", "continue-on-unsupported"} {
+		if c.IsSet(flag) {
+			return cli.Exit(color.RedString(fmt.Sprintf("%s flag requires all flag", flag)), exitcode.General)
+		}
+	}
+
+	version := c.Int64("version")
+	if c.IsSet("version") && version <= 0 {
+		return cli.Exit(color.RedString("version flag must be positive"), exitcode.General)
+	}
+	if c.IsSet("active-version") {
+		if c.IsSet("version") {
+			return cli.Exit(color.RedString("version flag cannot be used with active-version flag"), exitcode.General)
+		}
+		if options.activeNetwork, err = normalizeNetwork(c.String("active-version")); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("invalid active-version flag: %s", err)), exitcode.General)
+		}
 	}
 
 	policyName := c.Args().First()
 	section := edgegrid.GetEdgercSection(c)
-	if err = createPolicy(ctx, policyName, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting policy HCL: %s", err)), 1)
+	if err = createPolicy(ctx, policyName, policyID, version, section, options, client, clientShared, processor); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting policy HCL: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
 
-func createPolicy(ctx context.Context, policyName, section string, client cloudlets.Cloudlets, templateProcessor templates.TemplateProcessor) error {
-	term := terminal.Get(ctx)
+// readOnlyMode returns the flag exporting the policy as a data source instead of managed resources, either read-only
+// or as-data, which adds outputs of the policy to its locals; empty when the policy is exported as resources
+func readOnlyMode(c *cli.Context) string {
+	for _, flag := range []string{"read-only", "as-data"} {
+		if c.Bool(flag) {
+			return flag
+		}
+	}
+	return ""
+}
 
-	fmt.Println("Configuring Policy")
-	term.Spinner().Start("Fetching policy " + policyName)
+// createPolicy exports the policy with the ID when it is positive, otherwise the policy is looked up by its name. The
+// given version of the policy is exported when it is positive, otherwise its latest version, or the version active on
+// the network given with active-version flag.
+func createPolicy(ctx context.Context, policyName string, policyID, version int64, section string, options policyOptions, client cloudlets.Cloudlets, clientShared shared.Policies, templateProcessor templates.TemplateProcessor) error {
+	terminal.Get(ctx).Printf("Configuring Policy\n")
+	if policyID > 0 {
+		progress.Get(ctx).Start(fmt.Sprintf("Fetching policy with ID %d", policyID))
+	} else {
+		progress.Get(ctx).Start("Fetching policy " + policyName)
+	}
 
-	policy, err := findPolicyByName(ctx, policyName, client)
+	policy, err := getPolicy(ctx, policyName, policyID, client)
+	if errors.Is(err, errPolicyNotFound) {
+		// policies managed by Cloudlets API v3 are not returned by the legacy API
+		sharedPolicy, sharedErr := getSharedPolicy(ctx, policyName, policyID, clientShared)
+		if sharedErr == nil {
+			return createSharedPolicy(ctx, sharedPolicy, version, section, options, client, clientShared, templateProcessor)
+		}
+		if !isSharedPolicyLookupSkipped(sharedErr) {
+			err = sharedErr
+		}
+	}
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingPolicy, err)
 	}
 	if _, ok := supportedCloudlets[policy.CloudletCode]; !ok {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrCloudletTypeNotSupported, policy.CloudletCode)
 	}
 
 	tfPolicyData := TFPolicyData{
-		Section:      section,
-		Name:         policy.Name,
-		CloudletCode: policy.CloudletCode,
-		GroupID:      policy.GroupID,
+		Section:         section,
+		Name:            policy.Name,
+		CloudletCode:    policy.CloudletCode,
+		GroupID:         policy.GroupID,
+		SkipActivations: options.skipActivations,
+		ImportBlocks:    options.importBlocks,
+		ResourcePrefix:  options.resourcePrefix,
+		ResourceNaming:  options.resourceNaming,
+		AsModule:        options.asModule,
+		EdgercPath:      options.edgercPath,
+	}
+	if options.migrateToShared {
+		markSharedMigration(ctx, &tfPolicyData)
+	}
+	activations := getPolicyActivations(ctx, policy)
+	if options.activeNetwork != "" {
+		version = activeVersion(ctx, policy.Name, activations, options.activeNetwork)
 	}
 
-	policyVersion, err := getLatestPolicyVersion(ctx, policy.PolicyID, client)
+	policyVersion, err := getPolicyVersion(ctx, policy.PolicyID, version, client)
+	if errors.Is(err, errNoPolicyVersions) {
+		warnings.Report(ctx, warnings.Warning{
+			Product: "cloudlets policy",
+			Object:  policy.Name,
+			Reason:  "policy has no versions, only the policy is exported with a placeholder for match rules",
+		})
+		workspace.RecordObject(ctx, workspace.Object{
+			Product: "cloudlets",
+			ID:      strconv.FormatInt(policy.PolicyID, 10),
+			Name:    policy.Name,
+		})
+		tfPolicyData.PolicyID = policy.PolicyID
+		tfPolicyData.Description = policy.Description
+		tfPolicyData.NoVersions = true
+		tfPolicyData.PolicyActivations = activations
+		return processPolicyTemplates(ctx, policy.Name, tfPolicyData, templateProcessor)
+	}
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
 	}
+	workspace.RecordObject(ctx, workspace.Object{
+		Product: "cloudlets",
+		ID:      strconv.FormatInt(policy.PolicyID, 10),
+		Name:    policy.Name,
+		Version: strconv.FormatInt(policyVersion.Version, 10),
+	})
+	tfPolicyData.PolicyID = policy.PolicyID
+	tfPolicyData.Version = policyVersion.Version
 	tfPolicyData.Description = policyVersion.Description
 	tfPolicyData.MatchRuleFormat = policyVersion.MatchRuleFormat
 	tfPolicyData.MatchRules = policyVersion.MatchRules
-
-	tfPolicyData.PolicyActivations = make(map[string]TFPolicyActivationData)
-	if activationStaging := getActiveVersionAndProperties(policy, cloudlets.PolicyActivationNetworkStaging); activationStaging != nil {
-		tfPolicyData.PolicyActivations["staging"] = *activationStaging
+	if err = formatMatchRules(ctx, &tfPolicyData, options); err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
 	}
-	if activationProd := getActiveVersionAndProperties(policy, cloudlets.PolicyActivationNetworkProduction); activationProd != nil {
-		tfPolicyData.PolicyActivations["prod"] = *activationProd
+
+	if options.versionHistory > 0 {
+		if tfPolicyData.VersionHistory, err = getVersionHistory(ctx, policy.PolicyID, options.versionHistory, client); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingVersionHistory, err)
+		}
 	}
 
+	tfPolicyData.PolicyActivations = activations
+
 	if tfPolicyData.CloudletCode == "ALB" {
-		originIDs, err := getOriginIDs(policyVersion.MatchRules)
-		if err != nil {
-			term.Spinner().Fail()
+		if err := addLoadBalancers(ctx, client, &tfPolicyData); err != nil {
+			progress.Get(ctx).Fail()
 			return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
 		}
-		tfPolicyData.LoadBalancers, err = getLoadBalancers(ctx, client, originIDs)
-		if err != nil {
-			term.Spinner().Fail()
-			return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
+	}
+
+	if options.historyDir != "" {
+		if err = writePolicyVersionRules(ctx, policy, options.historyDir, &tfPolicyData, client); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingVersionHistory, err)
 		}
-		tfPolicyData.LoadBalancerActivations, err = getLoadBalancerActivations(ctx, client, originIDs)
+	}
+
+	return processPolicyTemplates(ctx, policy.Name, tfPolicyData, templateProcessor)
+}
+
+// writePolicyVersionRules fills the history of all versions of the policy and writes their match rules into dir
+func writePolicyVersionRules(ctx context.Context, policy *cloudlets.Policy, dir string, tfPolicyData *TFPolicyData, client cloudlets.Cloudlets) error {
+	history, err := getVersionHistory(ctx, policy.PolicyID, 0, client)
+	if err != nil {
+		return err
+	}
+	tfPolicyData.VersionHistory = history
+	return writeVersionRules(ctx, policy.Name, dir, history, func(ctx context.Context, version int64) (cloudlets.MatchRules, error) {
+		policyVersion, err := client.GetPolicyVersion(ctx, cloudlets.GetPolicyVersionRequest{PolicyID: policy.PolicyID, Version: version})
 		if err != nil {
-			term.Spinner().Fail()
-			return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
+			return nil, err
+		}
+		return policyVersion.MatchRules, nil
+	})
+}
+
+// formatMatchRules fills match rules of the policy in the form in which they are written, other than the data source;
+// split match rules are written into their files in the policy directory
+func formatMatchRules(ctx context.Context, tfPolicyData *TFPolicyData, options policyOptions) error {
+	var err error
+	switch options.rulesForm {
+	case matchRulesModule:
+		tfPolicyData.MatchRulesLocals, err = matchRulesLocals(tfPolicyData.MatchRules)
+	case matchRulesJSON:
+		tfPolicyData.MatchRulesJSON, err = matchRulesJSONContent(tfPolicyData.MatchRules)
+	case matchRulesSplit:
+		if tfPolicyData.MatchRulesFiles, err = matchRuleFiles(tfPolicyData.MatchRules); err != nil {
+			return err
 		}
+		err = writeMatchRuleFiles(ctx, options.policyDir, tfPolicyData.MatchRules, tfPolicyData.MatchRulesFiles)
+	}
+	return err
+}
 
+// addLoadBalancers fills load balancers referenced by match rules of the ALB policy and their activations, unless
+// activations are skipped; origins without load balancers are reported as warnings and left for placeholders
+func addLoadBalancers(ctx context.Context, client cloudlets.Cloudlets, tfPolicyData *TFPolicyData) error {
+	originIDs, err := getOriginIDs(tfPolicyData.MatchRules)
+	if err != nil {
+		return err
+	}
+	// each origin requires fetching load balancer versions and activations
+	steps := 2
+	if tfPolicyData.SkipActivations {
+		steps = 1
+	}
+	progress.Get(ctx).Total(steps * len(originIDs))
+	if tfPolicyData.LoadBalancers, err = getLoadBalancers(ctx, client, originIDs); err != nil {
+		return err
+	}
+	tfPolicyData.MissingLoadBalancers = missingLoadBalancers(originIDs, tfPolicyData.LoadBalancers)
+	for _, originID := range tfPolicyData.MissingLoadBalancers {
+		warnings.Report(ctx, warnings.Warning{
+			Product: "cloudlets load balancer",
+			Object:  originID,
+			Reason:  fmt.Sprintf("%s, a placeholder is exported for match rules forwarding to the origin", errLoadBalancerNotFound),
+		})
 	}
+	if !tfPolicyData.SkipActivations {
+		found := loadBalancerOriginIDs(tfPolicyData.LoadBalancers)
+		// activations are not fetched for missing load balancers
+		progress.Get(ctx).Total(len(originIDs) + len(found))
+		if tfPolicyData.LoadBalancerActivations, err = getLoadBalancerActivations(ctx, client, found); err != nil {
+			return err
+		}
+		reportInactiveLoadBalancerVersions(ctx, tfPolicyData.LoadBalancers, tfPolicyData.LoadBalancerActivations)
+	}
+	origins.Record(ctx, "cloudlets policy "+tfPolicyData.Name, loadBalancerOrigins(tfPolicyData.LoadBalancers)...)
+	return nil
+}
 
-	term.Spinner().OK()
-	term.Spinner().Start("Saving TF configurations ")
+// processPolicyTemplates saves configuration of the policy
+func processPolicyTemplates(ctx context.Context, policyName string, tfPolicyData TFPolicyData, templateProcessor templates.TemplateProcessor) error {
+	progress.Get(ctx).OK()
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err := templateProcessor.ProcessTemplates(tfPolicyData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
-	fmt.Printf("Terraform configuration for policy '%s' was saved successfully\n", policy.Name)
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for policy '%s' was saved successfully\n", policyName)
 
 	return nil
 }
 
+// getLoadBalancerActivations returns activations of load balancers of the origins, fetched concurrently and listed in
+// the order of the origins
 func getLoadBalancerActivations(ctx context.Context, client cloudlets.Cloudlets, originIDs []string) ([]cloudlets.LoadBalancerActivation, error) {
-	activations := make([]cloudlets.LoadBalancerActivation, 0)
-	for _, originID := range originIDs {
-		activation, err := getApplicationLoadBalancerActivation(ctx, client, originID, cloudlets.LoadBalancerActivationNetworkProduction)
+	byOrigin := make([][]cloudlets.LoadBalancerActivation, len(originIDs))
+	err := tools.RunConcurrently(ctx, len(originIDs), func(ctx context.Context, i int) error {
+		originActivations, err := getApplicationLoadBalancerActivations(ctx, client, originIDs[i])
 		if err != nil {
-			return nil, err
-		}
-		if activation != nil {
-			activations = append(activations, *activation)
+			return err
 		}
+		byOrigin[i] = originActivations
+		progress.Get(ctx).Step()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	activations := make([]cloudlets.LoadBalancerActivation, 0)
+	for _, originActivations := range byOrigin {
+		activations = append(activations, originActivations...)
+	}
+	return activations, nil
+}
 
-		activation, err = getApplicationLoadBalancerActivation(ctx, client, originID, cloudlets.LoadBalancerActivationNetworkStaging)
-		if err != nil {
-			return nil, err
+// reportInactiveLoadBalancerVersions warns about load balancers whose exported version is not the version active on
+// a network; activation resources refer to the version of the exported load balancer, so applying the configuration
+// on the network activates the exported version instead of the active one
+func reportInactiveLoadBalancerVersions(ctx context.Context, loadBalancers []cloudlets.LoadBalancerVersion, activations []cloudlets.LoadBalancerActivation) {
+	exported := make(map[string]int64, len(loadBalancers))
+	for _, loadBalancer := range loadBalancers {
+		exported[loadBalancer.OriginID] = loadBalancer.Version
+	}
+	for _, activation := range activations {
+		version, ok := exported[activation.OriginID]
+		if !ok || version == activation.Version {
+			continue
 		}
-		if activation != nil {
-			activations = append(activations, *activation)
+		warnings.Report(ctx, warnings.Warning{
+			Product: "cloudlets load balancer",
+			Object:  activation.OriginID,
+			Reason: fmt.Sprintf("version %d is active on %s network, applying the configuration activates exported version %d",
+				activation.Version, strings.ToLower(string(activation.Network)), version),
+		})
+	}
+}
+
+// missingLoadBalancers returns origin IDs which have no load balancer among loadBalancers
+func missingLoadBalancers(originIDs []string, loadBalancers []cloudlets.LoadBalancerVersion) []string {
+	found := make(map[string]bool, len(loadBalancers))
+	for _, loadBalancer := range loadBalancers {
+		found[loadBalancer.OriginID] = true
+	}
+	var missing []string
+	for _, originID := range originIDs {
+		if !found[originID] {
+			missing = append(missing, originID)
 		}
 	}
-	return activations, nil
+	return missing
+}
+
+// loadBalancerOriginIDs returns origin IDs of the load balancers
+func loadBalancerOriginIDs(loadBalancers []cloudlets.LoadBalancerVersion) []string {
+	originIDs := make([]string, 0, len(loadBalancers))
+	for _, loadBalancer := range loadBalancers {
+		originIDs = append(originIDs, loadBalancer.OriginID)
+	}
+	return originIDs
 }
 
+// getLoadBalancers returns the latest versions of load balancers of the origins, fetched concurrently and listed in the
+// order of the origins; origins which are not found or have no versions are left out
 func getLoadBalancers(ctx context.Context, client cloudlets.Cloudlets, originIDs []string) ([]cloudlets.LoadBalancerVersion, error) {
-	loadBalancers := make([]cloudlets.LoadBalancerVersion, 0)
-	for _, originID := range originIDs {
+	latest := make([]*cloudlets.LoadBalancerVersion, len(originIDs))
+	err := tools.RunConcurrently(ctx, len(originIDs), func(ctx context.Context, i int) error {
 		versions, err := client.ListLoadBalancerVersions(ctx, cloudlets.ListLoadBalancerVersionsRequest{
-			OriginID: originID,
+			OriginID: originIDs[i],
 		})
+		if apierrors.StatusCode(err) == http.StatusNotFound {
+			progress.Get(ctx).Step()
+			return nil
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		var ver int64
@@ -224,7 +746,18 @@ func getLoadBalancers(ctx context.Context, client cloudlets.Cloudlets, originIDs
 			}
 		}
 		if ver > 0 {
-			loadBalancers = append(loadBalancers, loadBalancerVersion)
+			latest[i] = &loadBalancerVersion
+		}
+		progress.Get(ctx).Step()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	loadBalancers := make([]cloudlets.LoadBalancerVersion, 0, len(originIDs))
+	for _, loadBalancer := range latest {
+		if loadBalancer != nil {
+			loadBalancers = append(loadBalancers, *loadBalancer)
 		}
 	}
 	return loadBalancers, nil
@@ -247,77 +780,179 @@ func getOriginIDs(rules cloudlets.MatchRules) ([]string, error) {
 	for originID := range originIDs {
 		result = append(result, originID)
 	}
+	// load balancers are exported and imported in a stable order
+	sort.Strings(result)
 	return result, nil
 }
 
-func getApplicationLoadBalancerActivation(ctx context.Context, client cloudlets.Cloudlets, originID string, network cloudlets.LoadBalancerActivationNetwork) (*cloudlets.LoadBalancerActivation, error) {
+// loadBalancerOrigins returns origins of load balancers and of their data centers
+func loadBalancerOrigins(loadBalancers []cloudlets.LoadBalancerVersion) []origins.Origin {
+	var result []origins.Origin
+	for _, loadBalancer := range loadBalancers {
+		result = append(result, origins.Origin{ID: loadBalancer.OriginID})
+		for _, dataCenter := range loadBalancer.DataCenters {
+			result = append(result, origins.Origin{ID: dataCenter.OriginID, Hostname: dataCenter.Hostname})
+		}
+	}
+	return result
+}
+
+// getApplicationLoadBalancerActivations returns the newest activation of the load balancer on production and staging
+// network, in this order, with network normalized to values accepted by the provider
+func getApplicationLoadBalancerActivations(ctx context.Context, client cloudlets.Cloudlets, originID string) ([]cloudlets.LoadBalancerActivation, error) {
 	activations, err := client.ListLoadBalancerActivations(ctx, cloudlets.ListLoadBalancerActivationsRequest{OriginID: originID})
-	filteredActivations := make([]cloudlets.LoadBalancerActivation, 0)
 	if err != nil {
 		return nil, err
 	}
 
+	newest := make(map[network]cloudlets.LoadBalancerActivation)
 	for _, act := range activations {
-		if act.Network == network {
-			filteredActivations = append(filteredActivations, act)
+		n, err := normalizeNetwork(string(act.Network))
+		if err != nil {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "cloudlets load balancer",
+				Object:  originID,
+				Reason:  fmt.Sprintf("activation of version %d is skipped: %s", act.Version, err),
+			})
+			continue
 		}
+		// The API is not providing any id to match the status of the activation request within the list of the activation statuses.
+		// The recommended solution is to get the newest activation which is most likely the right one.
+		if current, ok := newest[n]; ok && current.ActivatedDate >= act.ActivatedDate {
+			continue
+		}
+		act.Network = loadBalancerNetworks[n]
+		newest[n] = act
 	}
 
-	// The API is not providing any id to match the status of the activation request within the list of the activation statuses.
-	// The recommended solution is to get the newest activation which is most likely the right one.
-	// So we sort by ActivatedDate to get the newest activation.
-	sort.Slice(filteredActivations, func(i, j int) bool {
-		return activations[i].ActivatedDate > activations[j].ActivatedDate
-	})
+	result := make([]cloudlets.LoadBalancerActivation, 0, len(newest))
+	for _, n := range []network{networkProduction, networkStaging} {
+		if act, ok := newest[n]; ok {
+			result = append(result, act)
+		}
+	}
+	return result, nil
+}
 
-	if len(filteredActivations) > 0 {
-		return &filteredActivations[0], nil
+// getPolicy returns the policy with the ID when it is positive, skipping the listing of all policies, otherwise the
+// policy is looked up by its name
+func getPolicy(ctx context.Context, name string, id int64, client cloudlets.Cloudlets) (*cloudlets.Policy, error) {
+	if id <= 0 {
+		return findPolicyByName(ctx, name, client)
+	}
+	policy, err := client.GetPolicy(ctx, cloudlets.GetPolicyRequest{PolicyID: id})
+	if apierrors.StatusCode(err) == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: ID %d", errPolicyNotFound, id)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return nil, nil
+	return policy, nil
 }
 
+// findPolicyByName pages through policies of the account until the policy with the name is found; exports nested in
+// a batch run, e.g. of export-manifest, share a single listing of all policies of the account instead
 func findPolicyByName(ctx context.Context, name string, client cloudlets.Cloudlets) (*cloudlets.Policy, error) {
-	pageSize, offset := 1000, 0
-	var policy *cloudlets.Policy
+	var policies []cloudlets.Policy
+	if batch.IsNested(ctx) {
+		listed, err := batch.Memo(ctx, "cloudlets-policies", func() (interface{}, error) {
+			return listPolicies(ctx, client, "")
+		})
+		if err != nil {
+			return nil, err
+		}
+		policies = listed.([]cloudlets.Policy)
+	} else {
+		var err error
+		if policies, err = listPolicies(ctx, client, name); err != nil {
+			return nil, err
+		}
+	}
+	for i := range policies {
+		if policies[i].Name == name {
+			policy := policies[i]
+			return &policy, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: '%s'", errPolicyNotFound, name)
+}
+
+// listPolicies returns policies of the account, listing stops at the page with the policy named stopAt when it is not
+// empty; pages which fail with transient errors are retried
+func listPolicies(ctx context.Context, client cloudlets.Cloudlets, stopAt string) ([]cloudlets.Policy, error) {
+	pageSize, offset := tools.PageSize(tools.PageSizeCloudlets, 1000), 0
+	var result []cloudlets.Policy
 	for {
-		policies, err := client.ListPolicies(ctx, cloudlets.ListPoliciesRequest{
-			Offset:   offset,
-			PageSize: &pageSize,
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var policies []cloudlets.Policy
+		err := apierrors.Retry(ctx, func() error {
+			var err error
+			policies, err = client.ListPolicies(ctx, cloudlets.ListPoliciesRequest{
+				Offset:   offset,
+				PageSize: &pageSize,
+			})
+			return err
 		})
 		if err != nil {
 			return nil, err
 		}
+		result = append(result, policies...)
 		for _, p := range policies {
-			if p.Name == name {
-				policy = &p
-				return policy, nil
+			if stopAt != "" && p.Name == stopAt {
+				return result, nil
 			}
 		}
 		if len(policies) < pageSize {
-			break
+			return result, nil
 		}
 		offset += pageSize
 	}
-	return nil, fmt.Errorf("policy '%s' does not exist", name)
 }
 
+// getPolicyVersion returns the given version of the policy when it is positive, otherwise its latest version
+func getPolicyVersion(ctx context.Context, policyID, version int64, client cloudlets.Cloudlets) (*cloudlets.PolicyVersion, error) {
+	if version <= 0 {
+		return getLatestPolicyVersion(ctx, policyID, client)
+	}
+	policyVersion, err := client.GetPolicyVersion(ctx, cloudlets.GetPolicyVersionRequest{
+		PolicyID: policyID,
+		Version:  version,
+	})
+	if apierrors.StatusCode(err) == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %d", errPolicyVersionNotFound, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policyVersion, nil
+}
+
+// getLatestPolicyVersion returns the version of the policy with the highest number, pages of versions which fail with
+// transient errors are retried
 func getLatestPolicyVersion(ctx context.Context, policyID int64, client cloudlets.Cloudlets) (*cloudlets.PolicyVersion, error) {
 	var version int64
-	pageSize, offset := 1000, 0
+	pageSize, offset := tools.PageSize(tools.PageSizeCloudlets, 1000), 0
 	for {
-		versions, err := client.ListPolicyVersions(ctx, cloudlets.ListPolicyVersionsRequest{
-			PolicyID:     policyID,
-			IncludeRules: false,
-			PageSize:     &pageSize,
-			Offset:       offset,
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var versions []cloudlets.PolicyVersion
+		err := apierrors.Retry(ctx, func() error {
+			var err error
+			versions, err = client.ListPolicyVersions(ctx, cloudlets.ListPolicyVersionsRequest{
+				PolicyID:     policyID,
+				IncludeRules: false,
+				PageSize:     &pageSize,
+				Offset:       offset,
+			})
+			return err
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		if len(versions) == 0 {
-			return nil, fmt.Errorf("no policy versions found for given policy")
-		}
 		for _, v := range versions {
 			if v.Version > version {
 				version = v.Version
@@ -328,6 +963,9 @@ func getLatestPolicyVersion(ctx context.Context, policyID int64, client cloudlet
 		}
 		offset += pageSize
 	}
+	if version == 0 {
+		return nil, errNoPolicyVersions
+	}
 	policyVersion, err := client.GetPolicyVersion(ctx, cloudlets.GetPolicyVersionRequest{
 		PolicyID: policyID,
 		Version:  version,
@@ -338,22 +976,40 @@ func getLatestPolicyVersion(ctx context.Context, policyID int64, client cloudlet
 	return policyVersion, nil
 }
 
-func getActiveVersionAndProperties(policy *cloudlets.Policy, network cloudlets.PolicyActivationNetwork) *TFPolicyActivationData {
-	var version int64
-	var associatedProperties []string
+// activeVersion returns the version of the policy active on the network, 0 when the policy is not active on the
+// network, in which case its latest version is exported with a warning
+func activeVersion(ctx context.Context, policyName string, activations map[string]TFPolicyActivationData, n network) int64 {
+	if activation, ok := activations[policyActivationKeys[n]]; ok {
+		return activation.Version
+	}
+	warnings.Report(ctx, warnings.Warning{
+		Product: "cloudlets policy",
+		Object:  policyName,
+		Reason:  fmt.Sprintf("policy is not active on %s network, its latest version is exported", n),
+	})
+	return 0
+}
+
+// getPolicyActivations returns active version and associated properties of the policy on each network it is active on,
+// keyed by staging and prod
+func getPolicyActivations(ctx context.Context, policy *cloudlets.Policy) map[string]TFPolicyActivationData {
+	activations := make(map[string]TFPolicyActivationData)
 	for _, activation := range policy.Activations {
-		if activation.Network != network {
+		n, err := normalizeNetwork(string(activation.Network))
+		if err != nil {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "cloudlets policy",
+				Object:  policy.Name,
+				Reason:  fmt.Sprintf("activation on property '%s' is skipped: %s", activation.PropertyInfo.Name, err),
+			})
 			continue
 		}
-		version = activation.PolicyInfo.Version
-		associatedProperties = append(associatedProperties, activation.PropertyInfo.Name)
-	}
-	if associatedProperties == nil {
-		return nil
-	}
-	return &TFPolicyActivationData{
-		PolicyID:   policy.PolicyID,
-		Version:    version,
-		Properties: associatedProperties,
+		key := policyActivationKeys[n]
+		data := activations[key]
+		data.PolicyID = policy.PolicyID
+		data.Version = activation.PolicyInfo.Version
+		data.Properties = append(data.Properties, activation.PropertyInfo.Name)
+		activations[key] = data
 	}
+	return activations
 }
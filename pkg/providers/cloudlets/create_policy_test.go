@@ -3,21 +3,29 @@ package cloudlets
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
-	"reflect"
+	"sync"
 	"testing"
-	"text/template"
+	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/origins"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
 	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/testutils"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
 )
 
 type mockProcessor struct {
@@ -44,8 +52,14 @@ func TestCreatePolicy(t *testing.T) {
 	section := "test_section"
 	pageSize := 1000
 	tests := map[string]struct {
-		init      func(*cloudlets.Mock, *mockProcessor)
-		withError error
+		init            func(*cloudlets.Mock, *mockProcessor)
+		initShared      func(*shared.Mock)
+		policyID        int64
+		version         int64
+		versionHistory  int
+		skipActivations bool
+		activeNetwork   network
+		withError       error
 	}{
 		"fetch latest version of policy and produce output ALB": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
@@ -132,10 +146,12 @@ func TestCreatePolicy(t *testing.T) {
 				}
 				c.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{
 					OriginID: origin.OriginID,
-				}).Return(activations, nil).Twice()
+				}).Return(activations, nil).Once()
 
 				p.On("ProcessTemplates", TFPolicyData{
 					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           2,
 					Section:           section,
 					CloudletCode:      "ALB",
 					Description:       "version 2 description",
@@ -159,6 +175,123 @@ func TestCreatePolicy(t *testing.T) {
 				}).Return(nil).Once()
 			},
 		},
+		"fetch latest version of policy ALB with activations skipped": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     2,
+						GroupID:      234,
+						Name:         "test_policy",
+						CloudletCode: "ALB",
+						Activations: []cloudlets.PolicyActivation{
+							{
+								Network:      "staging",
+								PolicyInfo:   cloudlets.PolicyInfo{PolicyID: 2, Version: 1},
+								PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp_1"},
+							},
+						},
+					},
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).
+					Return([]cloudlets.PolicyVersion{{PolicyID: 2, Version: 1}}, nil).Once()
+				matchRules := cloudlets.MatchRules{
+					&cloudlets.MatchRuleALB{
+						Name:            "some rule",
+						Type:            "albMatchRule",
+						ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: "test_origin"},
+					},
+				}
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 2, Version: 1}).Return(&cloudlets.PolicyVersion{
+					PolicyID:        2,
+					Version:         1,
+					MatchRules:      matchRules,
+					MatchRuleFormat: "1.0",
+				}, nil).Once()
+				versionList := []cloudlets.LoadBalancerVersion{{OriginID: "test_origin", Version: 1}}
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return(versionList, nil).Once()
+
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:         "test_policy",
+					PolicyID:     2,
+					Version:      1,
+					Section:      section,
+					CloudletCode: "ALB",
+					GroupID:      234,
+					PolicyActivations: map[string]TFPolicyActivationData{
+						"staging": {PolicyID: 2, Version: 1, Properties: []string{"test_prp_1"}},
+					},
+					MatchRuleFormat: "1.0",
+					MatchRules:      matchRules,
+					LoadBalancers:   versionList,
+					SkipActivations: true,
+				}).Return(nil).Once()
+			},
+			skipActivations: true,
+		},
+		"fetch latest version of policy ALB forwarding to missing load balancer": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     2,
+						GroupID:      234,
+						Name:         "test_policy",
+						CloudletCode: "ALB",
+					},
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).
+					Return([]cloudlets.PolicyVersion{{PolicyID: 2, Version: 1}}, nil).Once()
+				matchRules := cloudlets.MatchRules{
+					&cloudlets.MatchRuleALB{
+						Name:            "some rule",
+						Type:            "albMatchRule",
+						ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: "test_origin"},
+					},
+					&cloudlets.MatchRuleALB{
+						Name:            "deleted origin rule",
+						Type:            "albMatchRule",
+						ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: "deleted_origin"},
+					},
+				}
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 2, Version: 1}).Return(&cloudlets.PolicyVersion{
+					PolicyID:        2,
+					Version:         1,
+					MatchRules:      matchRules,
+					MatchRuleFormat: "1.0",
+				}, nil).Once()
+				versionList := []cloudlets.LoadBalancerVersion{{OriginID: "test_origin", Version: 1}}
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "test_origin"}).
+					Return(versionList, nil).Once()
+				c.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "deleted_origin"}).
+					Return(nil, &cloudlets.Error{StatusCode: http.StatusNotFound}).Once()
+				activations := []cloudlets.LoadBalancerActivation{
+					{
+						ActivatedDate: "2021-10-29T00:00:20.000Z",
+						Network:       cloudlets.LoadBalancerActivationNetworkStaging,
+						OriginID:      "test_origin",
+						Status:        cloudlets.LoadBalancerActivationStatusActive,
+						Version:       1,
+					},
+				}
+				c.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{OriginID: "test_origin"}).
+					Return(activations, nil).Once()
+
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:                    "test_policy",
+					PolicyID:                2,
+					Version:                 1,
+					Section:                 section,
+					CloudletCode:            "ALB",
+					GroupID:                 234,
+					PolicyActivations:       map[string]TFPolicyActivationData{},
+					MatchRuleFormat:         "1.0",
+					MatchRules:              matchRules,
+					LoadBalancers:           versionList,
+					LoadBalancerActivations: activations,
+					MissingLoadBalancers:    []string{"deleted_origin"},
+				}).Return(nil).Once()
+			},
+		},
 		"fetch latest version of policy and produce output with activations ER": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
 				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
@@ -239,6 +372,8 @@ func TestCreatePolicy(t *testing.T) {
 				}, nil).Once()
 				p.On("ProcessTemplates", TFPolicyData{
 					Name:            "test_policy",
+					PolicyID:        2,
+					Version:         2,
 					Section:         section,
 					CloudletCode:    "ER",
 					Description:     "version 2 description",
@@ -348,6 +483,8 @@ func TestCreatePolicy(t *testing.T) {
 				}, nil).Once()
 				p.On("ProcessTemplates", TFPolicyData{
 					Name:            "test_policy",
+					PolicyID:        2,
+					Version:         2,
 					Section:         section,
 					CloudletCode:    "CD",
 					Description:     "version 2 description",
@@ -428,6 +565,8 @@ func TestCreatePolicy(t *testing.T) {
 				}, nil).Once()
 				p.On("ProcessTemplates", TFPolicyData{
 					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           2,
 					Section:           section,
 					CloudletCode:      "ER",
 					Description:       "version 2 description",
@@ -446,6 +585,83 @@ func TestCreatePolicy(t *testing.T) {
 				}).Return(nil).Once()
 			},
 		},
+		"fetch latest version of policy and produce output with version history": {
+			versionHistory: 1,
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     1,
+						GroupID:      123,
+						Name:         "some policy",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+					{
+						PolicyID:     2,
+						GroupID:      234,
+						Name:         "test_policy",
+						Description:  "test_policy description",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{
+					{
+						PolicyID: 2,
+						Version:  1,
+					},
+					{
+						PolicyID:        2,
+						Version:         2,
+						Description:     "version 2 description",
+						MatchRuleFormat: "1.0",
+						CreatedBy:       "jsmith",
+						CreateDate:      1633046400000,
+					},
+				}, nil).Twice()
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{
+					PolicyID: 2,
+					Version:  2,
+				}).Return(&cloudlets.PolicyVersion{
+					PolicyID:    2,
+					Version:     2,
+					Description: "version 2 description",
+					MatchRules: cloudlets.MatchRules{
+						&cloudlets.MatchRuleER{
+							Name:  "some rule",
+							Type:  "ER",
+							Start: 1,
+							End:   2,
+							ID:    1234,
+						},
+					},
+					MatchRuleFormat: "1.0",
+				}, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           2,
+					Section:           section,
+					CloudletCode:      "ER",
+					Description:       "version 2 description",
+					GroupID:           234,
+					PolicyActivations: map[string]TFPolicyActivationData{},
+					VersionHistory: []TFPolicyVersionHistory{
+						{Version: 2, Description: "version 2 description", CreatedBy: "jsmith", CreateDate: "2021-10-01T00:00:00Z"},
+					},
+					MatchRuleFormat: "1.0",
+					MatchRules: cloudlets.MatchRules{
+						&cloudlets.MatchRuleER{
+							Name:  "some rule",
+							Type:  "ER",
+							Start: 1,
+							End:   2,
+							ID:    1234,
+						},
+					},
+				}).Return(nil).Once()
+			},
+		},
 		"fetch latest version of policy and produce output without activations AP": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
 				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
@@ -499,6 +715,8 @@ func TestCreatePolicy(t *testing.T) {
 				}, nil).Once()
 				p.On("ProcessTemplates", TFPolicyData{
 					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           2,
 					Section:           section,
 					CloudletCode:      "AP",
 					Description:       "version 2 description",
@@ -571,6 +789,8 @@ func TestCreatePolicy(t *testing.T) {
 				}, nil).Once()
 				p.On("ProcessTemplates", TFPolicyData{
 					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           2,
 					Section:           section,
 					CloudletCode:      "AS",
 					Description:       "version 2 description",
@@ -590,120 +810,540 @@ func TestCreatePolicy(t *testing.T) {
 				}).Return(nil).Once()
 			},
 		},
-		"error fetching policy": {
-			init: func(c *cloudlets.Mock, p *mockProcessor) {
-				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return(nil, fmt.Errorf("oops")).Once()
-			},
-			withError: ErrFetchingPolicy,
-		},
-		"error policy not found": {
+		"policy without versions": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
 				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
 					{
-						PolicyID:     1,
-						GroupID:      123,
-						Name:         "some policy",
+						PolicyID:     2,
+						GroupID:      234,
+						Name:         "test_policy",
+						Description:  "test_policy description",
 						CloudletID:   0,
 						CloudletCode: "ER",
 					},
 				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{}, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:              "test_policy",
+					PolicyID:          2,
+					Section:           section,
+					CloudletCode:      "ER",
+					Description:       "test_policy description",
+					GroupID:           234,
+					PolicyActivations: map[string]TFPolicyActivationData{},
+					NoVersions:        true,
+				}).Return(nil).Once()
 			},
-			withError: ErrFetchingPolicy,
 		},
-		"unsupported cloudlet type": {
+		"policy by ID": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
-				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
-					{
-						PolicyID:     1,
-						GroupID:      123,
-						Name:         "some policy",
-						CloudletID:   0,
-						CloudletCode: "ER",
-					},
-					{
-						PolicyID:     2,
-						GroupID:      234,
-						Name:         "test_policy",
-						Description:  "test_policy description",
-						CloudletID:   0,
-						CloudletCode: "ABC",
-					},
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 2}).Return(&cloudlets.Policy{
+					PolicyID:     2,
+					GroupID:      234,
+					Name:         "test_policy",
+					Description:  "test_policy description",
+					CloudletCode: "ER",
 				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{}, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:              "test_policy",
+					PolicyID:          2,
+					Section:           section,
+					CloudletCode:      "ER",
+					Description:       "test_policy description",
+					GroupID:           234,
+					PolicyActivations: map[string]TFPolicyActivationData{},
+					NoVersions:        true,
+				}).Return(nil).Once()
 			},
-			withError: ErrCloudletTypeNotSupported,
+			policyID: 2,
 		},
-		"error listing versions": {
+		"shared policy by ID": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
-				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
-					{
-						PolicyID:     1,
-						GroupID:      123,
-						Name:         "some policy",
-						CloudletID:   0,
-						CloudletCode: "ER",
-					},
-					{
-						PolicyID:     2,
-						GroupID:      234,
-						Name:         "test_policy",
-						Description:  "test_policy description",
-						CloudletID:   0,
-						CloudletCode: "ER",
-					},
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 11}).Return(nil, &cloudlets.Error{StatusCode: 404}).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:              "test_policy",
+					PolicyID:          11,
+					Section:           section,
+					CloudletCode:      "ER",
+					GroupID:           234,
+					IsShared:          true,
+					PolicyActivations: map[string]TFPolicyActivationData{},
+					NoVersions:        true,
+				}).Return(nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("GetPolicy", mock.Anything, shared.GetPolicyRequest{PolicyID: 11}).Return(&shared.Policy{
+					ID:           11,
+					Name:         "test_policy",
+					CloudletType: "ER",
+					GroupID:      234,
+					PolicyType:   "SHARED",
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, shared.ListPolicyVersionsRequest{PolicyID: 11, Size: pageSize}).Return(&shared.ListPolicyVersionsResponse{
+					Page: shared.Page{Size: pageSize},
 				}, nil).Once()
-				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return(nil, fmt.Errorf("oops")).Once()
 			},
-			withError: ErrFetchingVersion,
+			policyID: 11,
 		},
-		"error fetching latest version": {
+		"error policy ID not found": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
-				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
-					{
-						PolicyID:     1,
-						GroupID:      123,
-						Name:         "some policy",
-						CloudletID:   0,
-						CloudletCode: "ER",
-					},
-					{
-						PolicyID:     2,
-						GroupID:      234,
-						Name:         "test_policy",
-						Description:  "test_policy description",
-						CloudletID:   0,
-						CloudletCode: "ER",
-					},
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 3}).Return(nil, &cloudlets.Error{StatusCode: 404}).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("GetPolicy", mock.Anything, shared.GetPolicyRequest{PolicyID: 3}).Return(nil, &shared.Error{StatusCode: 404}).Once()
+			},
+			policyID:  3,
+			withError: ErrFetchingPolicy,
+		},
+		"given version of policy": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 2}).Return(&cloudlets.Policy{
+					PolicyID:     2,
+					GroupID:      234,
+					Name:         "test_policy",
+					CloudletCode: "ER",
 				}, nil).Once()
-				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{
-					{
-						PolicyID: 2,
-						Version:  1,
-					},
-					{
-						PolicyID:        2,
-						Version:         2,
-						Description:     "version 2 description",
-						MatchRuleFormat: "1.0",
-					},
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 2, Version: 5}).Return(&cloudlets.PolicyVersion{
+					PolicyID:        2,
+					Version:         5,
+					Description:     "version 5 description",
+					MatchRuleFormat: "1.0",
 				}, nil).Once()
-				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{
-					PolicyID: 2,
-					Version:  2,
-				}).Return(nil, fmt.Errorf("oops")).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           5,
+					Section:           section,
+					CloudletCode:      "ER",
+					Description:       "version 5 description",
+					GroupID:           234,
+					MatchRuleFormat:   "1.0",
+					PolicyActivations: map[string]TFPolicyActivationData{},
+				}).Return(nil).Once()
+			},
+			policyID: 2,
+			version:  5,
+		},
+		"error given version of policy not found": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 2}).Return(&cloudlets.Policy{
+					PolicyID:     2,
+					GroupID:      234,
+					Name:         "test_policy",
+					CloudletCode: "ER",
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 2, Version: 5}).Return(nil, &cloudlets.Error{StatusCode: 404}).Once()
 			},
+			policyID:  2,
+			version:   5,
 			withError: ErrFetchingVersion,
 		},
-		"error processing template": {
+		"given version of shared policy": {
 			init: func(c *cloudlets.Mock, p *mockProcessor) {
-				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
-					{
-						PolicyID:     1,
-						GroupID:      123,
-						Name:         "some policy",
-						CloudletID:   0,
-						CloudletCode: "ER",
-					},
-					{
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 11}).Return(nil, &cloudlets.Error{StatusCode: 404}).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:              "test_policy",
+					PolicyID:          11,
+					Version:           2,
+					Section:           section,
+					CloudletCode:      "ER",
+					Description:       "version 2 description",
+					GroupID:           234,
+					IsShared:          true,
+					PolicyActivations: map[string]TFPolicyActivationData{},
+					MatchRules: cloudlets.MatchRules{
+						&cloudlets.MatchRuleER{Name: "some rule", Type: "erMatchRule", RedirectURL: "/a", StatusCode: 301},
+					},
+				}).Return(nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("GetPolicy", mock.Anything, shared.GetPolicyRequest{PolicyID: 11}).Return(&shared.Policy{
+					ID:           11,
+					Name:         "test_policy",
+					CloudletType: "ER",
+					GroupID:      234,
+					PolicyType:   "SHARED",
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, shared.GetPolicyVersionRequest{PolicyID: 11, Version: 2}).Return(&shared.PolicyVersion{
+					PolicyID:    11,
+					Version:     2,
+					Description: "version 2 description",
+					MatchRules: cloudlets.MatchRules{
+						&cloudlets.MatchRuleER{Name: "some rule", Type: "erMatchRule", RedirectURL: "/a", StatusCode: 301},
+					},
+				}, nil).Once()
+			},
+			policyID: 11,
+			version:  2,
+		},
+		"version of policy active on production": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 2}).Return(&cloudlets.Policy{
+					PolicyID:     2,
+					GroupID:      234,
+					Name:         "test_policy",
+					CloudletCode: "ER",
+					Activations: []cloudlets.PolicyActivation{
+						{Network: "staging", PolicyInfo: cloudlets.PolicyInfo{Version: 3}, PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp"}},
+						{Network: "prod", PolicyInfo: cloudlets.PolicyInfo{Version: 2}, PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp"}},
+					},
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 2, Version: 2}).Return(&cloudlets.PolicyVersion{
+					PolicyID:        2,
+					Version:         2,
+					Description:     "version 2 description",
+					MatchRuleFormat: "1.0",
+				}, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:            "test_policy",
+					PolicyID:        2,
+					Version:         2,
+					Section:         section,
+					CloudletCode:    "ER",
+					Description:     "version 2 description",
+					GroupID:         234,
+					MatchRuleFormat: "1.0",
+					PolicyActivations: map[string]TFPolicyActivationData{
+						"staging": {PolicyID: 2, Version: 3, Properties: []string{"test_prp"}},
+						"prod":    {PolicyID: 2, Version: 2, Properties: []string{"test_prp"}},
+					},
+				}).Return(nil).Once()
+			},
+			policyID:      2,
+			activeNetwork: networkProduction,
+		},
+		"policy not active on the network of active version": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 2}).Return(&cloudlets.Policy{
+					PolicyID:     2,
+					GroupID:      234,
+					Name:         "test_policy",
+					CloudletCode: "ER",
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{
+					{PolicyID: 2, Version: 1},
+					{PolicyID: 2, Version: 4},
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 2, Version: 4}).Return(&cloudlets.PolicyVersion{
+					PolicyID:        2,
+					Version:         4,
+					Description:     "version 4 description",
+					MatchRuleFormat: "1.0",
+				}, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           4,
+					Section:           section,
+					CloudletCode:      "ER",
+					Description:       "version 4 description",
+					GroupID:           234,
+					MatchRuleFormat:   "1.0",
+					PolicyActivations: map[string]TFPolicyActivationData{},
+				}).Return(nil).Once()
+			},
+			policyID:      2,
+			activeNetwork: networkStaging,
+		},
+		"version of shared policy active on staging": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 11}).Return(nil, &cloudlets.Error{StatusCode: 404}).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:         "test_policy",
+					PolicyID:     11,
+					Version:      2,
+					Section:      section,
+					CloudletCode: "ER",
+					Description:  "version 2 description",
+					GroupID:      234,
+					IsShared:     true,
+					PolicyActivations: map[string]TFPolicyActivationData{
+						"staging": {PolicyID: 11, Version: 2},
+					},
+					SkipActivations: true,
+				}).Return(nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("GetPolicy", mock.Anything, shared.GetPolicyRequest{PolicyID: 11}).Return(&shared.Policy{
+					ID:           11,
+					Name:         "test_policy",
+					CloudletType: "ER",
+					GroupID:      234,
+					PolicyType:   "SHARED",
+					CurrentActivations: shared.CurrentActivations{
+						Staging: shared.ActivationInfo{Effective: &shared.Activation{
+							Network: "STAGING", Operation: shared.OperationActivation, PolicyID: 11, PolicyVersion: 2, Status: shared.StatusSuccess,
+						}},
+					},
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, shared.GetPolicyVersionRequest{PolicyID: 11, Version: 2}).Return(&shared.PolicyVersion{
+					PolicyID:    11,
+					Version:     2,
+					Description: "version 2 description",
+				}, nil).Once()
+			},
+			policyID:        11,
+			skipActivations: true,
+			activeNetwork:   networkStaging,
+		},
+		"error given version of shared policy not found": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("GetPolicy", mock.Anything, cloudlets.GetPolicyRequest{PolicyID: 11}).Return(nil, &cloudlets.Error{StatusCode: 404}).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("GetPolicy", mock.Anything, shared.GetPolicyRequest{PolicyID: 11}).Return(&shared.Policy{
+					ID:           11,
+					Name:         "test_policy",
+					CloudletType: "ER",
+					GroupID:      234,
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, shared.GetPolicyVersionRequest{PolicyID: 11, Version: 2}).Return(nil, &shared.Error{StatusCode: 404}).Once()
+			},
+			policyID:  11,
+			version:   2,
+			withError: ErrFetchingVersion,
+		},
+		"error fetching policy": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingPolicy,
+		},
+		"error policy not found": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     1,
+						GroupID:      123,
+						Name:         "some policy",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+				}, nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{ID: 11, Name: "other shared policy", CloudletType: "ER"}},
+					Page:    shared.Page{Size: pageSize, TotalElements: 1, TotalPages: 1},
+				}, nil).Once()
+			},
+			withError: ErrFetchingPolicy,
+		},
+		"error policy not found and not entitled to shared policies": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{}, nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(nil, &shared.Error{StatusCode: 403}).Once()
+			},
+			withError: ErrFetchingPolicy,
+		},
+		"error listing shared policies": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{}, nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(nil, &shared.Error{StatusCode: 500}).Once()
+			},
+			withError: ErrFetchingPolicy,
+		},
+		"shared policy": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{}, nil).Once()
+				p.On("ProcessTemplates", TFPolicyData{
+					Name:         "test_policy",
+					PolicyID:     11,
+					Version:      3,
+					Section:      section,
+					CloudletCode: "ER",
+					Description:  "version 3 description",
+					GroupID:      234,
+					IsShared:     true,
+					PolicyActivations: map[string]TFPolicyActivationData{
+						"staging": {PolicyID: 11, Version: 3, Properties: []string{"prp_0", "prp_1"}},
+					},
+					MatchRules: cloudlets.MatchRules{
+						&cloudlets.MatchRuleER{Name: "some rule", Type: "erMatchRule", RedirectURL: "/a", StatusCode: 301},
+					},
+					VersionHistory: []TFPolicyVersionHistory{
+						{Version: 3, Description: "version 3 description", CreatedBy: "jsmith", CreateDate: "2024-01-03T10:00:00Z"},
+					},
+				}).Return(nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{
+						ID:           11,
+						Name:         "test_policy",
+						CloudletType: "ER",
+						GroupID:      234,
+						PolicyType:   "SHARED",
+						CurrentActivations: shared.CurrentActivations{
+							Staging: shared.ActivationInfo{Effective: &shared.Activation{
+								Network: "STAGING", Operation: shared.OperationActivation, PolicyID: 11, PolicyVersion: 3, Status: shared.StatusSuccess,
+							}},
+						},
+					}},
+					Page: shared.Page{Size: pageSize, TotalElements: 1, TotalPages: 1},
+				}, nil).Once()
+				c.On("ListPolicyProperties", mock.Anything, shared.ListPolicyPropertiesRequest{PolicyID: 11, Size: pageSize}).Return(&shared.ListPolicyPropertiesResponse{
+					Content: []shared.PolicyProperty{
+						{ID: 1, Name: "prp_1", GroupID: 234, Network: "STAGING", Version: 2},
+						{ID: 1, Name: "prp_1", GroupID: 234, Network: "STAGING", Version: 3},
+						{ID: 2, Name: "prp_0", GroupID: 234, Network: "STAGING", Version: 1},
+						{ID: 3, Name: "prp_2", GroupID: 234, Network: "PRODUCTION", Version: 1},
+					},
+					Page: shared.Page{Size: pageSize, TotalElements: 4, TotalPages: 1},
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, shared.ListPolicyVersionsRequest{PolicyID: 11, Size: pageSize}).Return(&shared.ListPolicyVersionsResponse{
+					Content: []shared.PolicyVersion{
+						{PolicyID: 11, Version: 2, Description: "version 2", CreatedBy: "jsmith", CreatedDate: "2024-01-02T10:00:00Z"},
+						{PolicyID: 11, Version: 3, Description: "version 3 description", CreatedBy: "jsmith", CreatedDate: "2024-01-03T10:00:00Z"},
+					},
+					Page: shared.Page{Size: pageSize, TotalElements: 2, TotalPages: 1},
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, shared.GetPolicyVersionRequest{PolicyID: 11, Version: 3}).Return(&shared.PolicyVersion{
+					PolicyID:    11,
+					Version:     3,
+					Description: "version 3 description",
+					MatchRules: cloudlets.MatchRules{
+						&cloudlets.MatchRuleER{Name: "some rule", Type: "erMatchRule", RedirectURL: "/a", StatusCode: 301},
+					},
+				}, nil).Once()
+			},
+			versionHistory: 1,
+		},
+		"error listing properties of shared policy": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{}, nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{
+						ID:           11,
+						Name:         "test_policy",
+						CloudletType: "ER",
+						GroupID:      234,
+						PolicyType:   "SHARED",
+						CurrentActivations: shared.CurrentActivations{
+							Production: shared.ActivationInfo{Effective: &shared.Activation{
+								Network: "PRODUCTION", Operation: shared.OperationActivation, PolicyID: 11, PolicyVersion: 3, Status: shared.StatusSuccess,
+							}},
+						},
+					}},
+					Page: shared.Page{Size: pageSize, TotalElements: 1, TotalPages: 1},
+				}, nil).Once()
+				c.On("ListPolicyProperties", mock.Anything, shared.ListPolicyPropertiesRequest{PolicyID: 11, Size: pageSize}).Return(nil, &shared.Error{StatusCode: 500}).Once()
+			},
+			withError: ErrFetchingPolicyProperties,
+		},
+		"shared policy of unsupported cloudlet type": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{}, nil).Once()
+			},
+			initShared: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Size: pageSize}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{ID: 11, Name: "test_policy", CloudletType: "XX"}},
+					Page:    shared.Page{Size: pageSize, TotalElements: 1, TotalPages: 1},
+				}, nil).Once()
+			},
+			withError: ErrCloudletTypeNotSupported,
+		},
+		"unsupported cloudlet type": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     1,
+						GroupID:      123,
+						Name:         "some policy",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+					{
+						PolicyID:     2,
+						GroupID:      234,
+						Name:         "test_policy",
+						Description:  "test_policy description",
+						CloudletID:   0,
+						CloudletCode: "ABC",
+					},
+				}, nil).Once()
+			},
+			withError: ErrCloudletTypeNotSupported,
+		},
+		"error listing versions": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     1,
+						GroupID:      123,
+						Name:         "some policy",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+					{
+						PolicyID:     2,
+						GroupID:      234,
+						Name:         "test_policy",
+						Description:  "test_policy description",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingVersion,
+		},
+		"error fetching latest version": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     1,
+						GroupID:      123,
+						Name:         "some policy",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+					{
+						PolicyID:     2,
+						GroupID:      234,
+						Name:         "test_policy",
+						Description:  "test_policy description",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+				}, nil).Once()
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{
+					{
+						PolicyID: 2,
+						Version:  1,
+					},
+					{
+						PolicyID:        2,
+						Version:         2,
+						Description:     "version 2 description",
+						MatchRuleFormat: "1.0",
+					},
+				}, nil).Once()
+				c.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{
+					PolicyID: 2,
+					Version:  2,
+				}).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingVersion,
+		},
+		"error processing template": {
+			init: func(c *cloudlets.Mock, p *mockProcessor) {
+				c.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+					{
+						PolicyID:     1,
+						GroupID:      123,
+						Name:         "some policy",
+						CloudletID:   0,
+						CloudletCode: "ER",
+					},
+					{
 						PolicyID:     2,
 						GroupID:      234,
 						Name:         "test_policy",
@@ -744,6 +1384,8 @@ func TestCreatePolicy(t *testing.T) {
 				}, nil).Once()
 				p.On("ProcessTemplates", TFPolicyData{
 					Name:              "test_policy",
+					PolicyID:          2,
+					Version:           2,
 					Section:           section,
 					CloudletCode:      "ER",
 					Description:       "version 2 description",
@@ -768,16 +1410,21 @@ func TestCreatePolicy(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			mc := new(cloudlets.Mock)
+			ms := new(shared.Mock)
 			mp := new(mockProcessor)
 			test.init(mc, mp)
+			if test.initShared != nil {
+				test.initShared(ms)
+			}
 			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
-			err := createPolicy(ctx, "test_policy", section, mc, mp)
+			err := createPolicy(ctx, "test_policy", test.policyID, test.version, section, policyOptions{versionHistory: test.versionHistory, skipActivations: test.skipActivations, activeNetwork: test.activeNetwork}, mc, ms, mp)
 			if test.withError != nil {
 				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
 				return
 			}
 			require.NoError(t, err)
 			mc.AssertExpectations(t)
+			ms.AssertExpectations(t)
 			mp.AssertExpectations(t)
 		})
 	}
@@ -785,9 +1432,19 @@ func TestCreatePolicy(t *testing.T) {
 
 func TestProcessPolicyTemplates(t *testing.T) {
 	tests := map[string]struct {
-		givenData    TFPolicyData
-		dir          string
-		filesToCheck []string
+		givenData        TFPolicyData
+		dir              string
+		filesToCheck     []string
+		readOnly         bool
+		asData           bool
+		matchRulesModule bool
+		matchRulesJSON   bool
+		splitMatchRules  bool
+		importBlocks     bool
+		asModule         bool
+		migrateToShared  bool
+		tfvars           bool
+		varNaming        string
 	}{
 		"policy with ER match rules and activations": {
 			givenData: TFPolicyData{
@@ -1949,38 +2606,1069 @@ func TestProcessPolicyTemplates(t *testing.T) {
 			dir:          "with_match_rules_ig",
 			filesToCheck: []string{"policy.tf", "match-rules.tf", "variables.tf", "import.sh"},
 		},
-	}
-
-	for name, test := range tests {
-		t.Run(name, func(t *testing.T) {
-			require.NoError(t, os.MkdirAll(fmt.Sprintf("./testdata/res/%s", test.dir), 0755))
-			processor := templates.FSTemplateProcessor{
-				TemplatesFS: templateFiles,
-				TemplateTargets: map[string]string{
-					"policy.tmpl":        fmt.Sprintf("./testdata/res/%s/policy.tf", test.dir),
-					"match-rules.tmpl":   fmt.Sprintf("./testdata/res/%s/match-rules.tf", test.dir),
-					"load-balancer.tmpl": fmt.Sprintf("./testdata/res/%s/load-balancer.tf", test.dir),
-					"variables.tmpl":     fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
-					"imports.tmpl":       fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
-				},
-				AdditionalFuncs: template.FuncMap{
-					"deepequal": reflect.DeepEqual,
-				},
-			}
-			require.NoError(t, processor.ProcessTemplates(test.givenData))
-
-			for _, f := range test.filesToCheck {
-				expected, err := ioutil.ReadFile(fmt.Sprintf("./testdata/%s/%s", test.dir, f))
-				require.NoError(t, err)
-				result, err := ioutil.ReadFile(fmt.Sprintf("./testdata/res/%s/%s", test.dir, f))
-				require.NoError(t, err)
-				assert.Equal(t, string(expected), string(result))
-			}
-		})
-	}
-}
-
-func TestFindPolicy(t *testing.T) {
+		"policy with ER match rules module": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:  "r1",
+						Start: 1,
+						End:   2,
+						Matches: []cloudlets.MatchCriteriaER{
+							{
+								MatchType:     "extension",
+								MatchValue:    "txt",
+								MatchOperator: "equals",
+							},
+							{
+								MatchOperator: "equals",
+								MatchType:     "header",
+								ObjectMatchValue: cloudlets.ObjectMatchValueObject{
+									Type: "object",
+									Name: `ER\`,
+									Options: &cloudlets.Options{
+										Value:            []string{"y"},
+										ValueHasWildcard: true,
+									},
+								},
+							},
+						},
+						UseRelativeURL: "copy_scheme_hostname",
+						StatusCode:     307,
+						RedirectURL:    "/abc/sss",
+						MatchURL:       "test.url",
+					},
+					cloudlets.MatchRuleER{
+						Name:                     "r2",
+						StatusCode:               301,
+						RedirectURL:              "/ddd",
+						UseIncomingQueryString:   true,
+						UseIncomingSchemeAndHost: true,
+						Disabled:                 true,
+					},
+				},
+			},
+			dir:              "match_rules_module_er",
+			filesToCheck:     []string{"policy.tf", "match-rules.tf", "modules/match-rules/main.tf"},
+			matchRulesModule: true,
+		},
+		"policy with ER match rules as JSON": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				MatchRules: cloudlets.MatchRules{
+					&cloudlets.MatchRuleER{
+						Name:  "r1",
+						Type:  cloudlets.MatchRuleTypeER,
+						ID:    1234,
+						Start: 1,
+						End:   2,
+						Matches: []cloudlets.MatchCriteriaER{
+							{
+								MatchType:     "extension",
+								MatchValue:    "txt",
+								MatchOperator: "equals",
+							},
+						},
+						StatusCode:  307,
+						RedirectURL: "/abc?a=1&b=<2>",
+					},
+					&cloudlets.MatchRuleER{
+						Name:                   "r2",
+						Type:                   cloudlets.MatchRuleTypeER,
+						StatusCode:             301,
+						RedirectURL:            "/ddd",
+						UseIncomingQueryString: true,
+						Disabled:               true,
+					},
+				},
+			},
+			dir:            "match_rules_json_er",
+			filesToCheck:   []string{"policy.tf", "match-rules.tf", "match-rules.json"},
+			matchRulesJSON: true,
+		},
+		"policy with match rules split into files": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				MatchRules: cloudlets.MatchRules{
+					&cloudlets.MatchRuleER{
+						Name:        "Redirect /old",
+						Type:        cloudlets.MatchRuleTypeER,
+						ID:          1234,
+						StatusCode:  301,
+						RedirectURL: "/new?a=1&b=2",
+					},
+					&cloudlets.MatchRuleER{
+						Type:        cloudlets.MatchRuleTypeER,
+						StatusCode:  302,
+						RedirectURL: "/unnamed",
+					},
+					&cloudlets.MatchRuleER{
+						Name:        "redirect_old",
+						Type:        cloudlets.MatchRuleTypeER,
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+						Disabled:    true,
+					},
+				},
+			},
+			dir:             "split_match_rules",
+			filesToCheck:    []string{"policy.tf", "match-rules.tf", "rules/redirect_old.json", "rules/rule.json", "rules/redirect_old_2.json"},
+			splitMatchRules: true,
+		},
+		"policy without versions with match rules as JSON": {
+			givenData: TFPolicyData{
+				Name:         "test_policy_export",
+				Section:      "test_section",
+				CloudletCode: "ER",
+				GroupID:      12345,
+				NoVersions:   true,
+			},
+			dir:            "match_rules_json_no_versions",
+			filesToCheck:   []string{"policy.tf", "match-rules.tf"},
+			matchRulesJSON: true,
+		},
+		"policy with ALB match rules module": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ALB",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleALB{
+						Name: "r1",
+						Matches: []cloudlets.MatchCriteriaALB{
+							{
+								MatchOperator: "equals",
+								MatchType:     "range",
+								ObjectMatchValue: &cloudlets.ObjectMatchValueRange{
+									Type:  "range",
+									Value: []int64{1, 50},
+								},
+							},
+							{
+								MatchType:     "method",
+								MatchOperator: "equals",
+								CaseSensitive: true,
+								ObjectMatchValue: cloudlets.ObjectMatchValueSimple{
+									Type:  "simple",
+									Value: []string{"GET"},
+								},
+							},
+						},
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "test_origin",
+						},
+					},
+					cloudlets.MatchRuleALB{
+						Name:          "r2",
+						MatchesAlways: true,
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "test_origin",
+						},
+					},
+				},
+			},
+			dir:              "match_rules_module_alb",
+			filesToCheck:     []string{"policy.tf", "match-rules.tf", "modules/match-rules/main.tf"},
+			matchRulesModule: true,
+		},
+		"policy with ER match rules and activations skipped": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"prod": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+					"staging": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				SkipActivations: true,
+			},
+			dir:          "skip_activations_er",
+			filesToCheck: []string{"policy.tf", "variables.tf", "import.sh"},
+		},
+		"policy with ALB match rules and activations skipped": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ALB",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleALB{
+						Name: "r1",
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "test_origin",
+						},
+					},
+				},
+				LoadBalancers: []cloudlets.LoadBalancerVersion{
+					{
+						OriginID:      "test_origin",
+						BalancingType: cloudlets.BalancingTypeWeighted,
+						DataCenters: []cloudlets.DataCenter{
+							{
+								City:      "Boston",
+								Continent: "NA",
+								Country:   "US",
+								Hostname:  "test-hostname",
+								Latitude:  tools.Float64Ptr(102.78108),
+								Longitude: tools.Float64Ptr(-116.07064),
+								OriginID:  "test_origin",
+								Percent:   tools.Float64Ptr(100),
+							},
+						},
+						Version: 2,
+					},
+				},
+				SkipActivations: true,
+			},
+			dir:          "skip_activations_alb",
+			filesToCheck: []string{"policy.tf", "load-balancer.tf", "variables.tf", "import.sh"},
+		},
+		"policy with ALB match rules forwarding to missing load balancer": {
+			givenData: TFPolicyData{
+				Name:              "test_policy_export",
+				Section:           "test_section",
+				CloudletCode:      "ALB",
+				Description:       "Testing exported policy",
+				GroupID:           12345,
+				MatchRuleFormat:   "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleALB{
+						Name: "r1",
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "test_origin",
+						},
+					},
+					cloudlets.MatchRuleALB{
+						Name: "r2",
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "deleted_origin",
+						},
+					},
+				},
+				LoadBalancers: []cloudlets.LoadBalancerVersion{
+					{
+						OriginID:      "test_origin",
+						BalancingType: cloudlets.BalancingTypeWeighted,
+						DataCenters: []cloudlets.DataCenter{
+							{
+								City:      "Boston",
+								Continent: "NA",
+								Country:   "US",
+								Hostname:  "test-hostname",
+								Latitude:  tools.Float64Ptr(102.78108),
+								Longitude: tools.Float64Ptr(-116.07064),
+								OriginID:  "test_origin",
+								Percent:   tools.Float64Ptr(100),
+							},
+						},
+						Version: 2,
+					},
+				},
+				MissingLoadBalancers: []string{"deleted_origin"},
+			},
+			dir:          "missing_load_balancer_alb",
+			filesToCheck: []string{"policy.tf", "load-balancer.tf", "variables.tf", "import.sh"},
+		},
+		"policy with ALB match rules and import blocks": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ALB",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleALB{
+						Name: "r1",
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "origin_a",
+						},
+					},
+					cloudlets.MatchRuleALB{
+						Name: "r2",
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "origin_b",
+						},
+					},
+				},
+				LoadBalancers: []cloudlets.LoadBalancerVersion{
+					{OriginID: "origin_a", BalancingType: cloudlets.BalancingTypeWeighted, Version: 1},
+					{OriginID: "origin_b", BalancingType: cloudlets.BalancingTypeWeighted, Version: 1},
+				},
+				LoadBalancerActivations: []cloudlets.LoadBalancerActivation{
+					{Network: cloudlets.LoadBalancerActivationNetworkStaging, OriginID: "origin_a", Status: cloudlets.LoadBalancerActivationStatusActive, Version: 1},
+					{Network: cloudlets.LoadBalancerActivationNetworkProduction, OriginID: "origin_b", Status: cloudlets.LoadBalancerActivationStatusActive, Version: 1},
+				},
+				ImportBlocks: true,
+			},
+			dir:          "import_blocks_alb",
+			filesToCheck: []string{"policy.tf", "imports.tf"},
+			importBlocks: true,
+		},
+		"policy with version history": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				VersionHistory: []TFPolicyVersionHistory{
+					{Version: 3, Description: "Redirect legacy paths", CreatedBy: "jsmith", CreateDate: "2021-10-01T00:00:00Z"},
+					{Version: 2, CreatedBy: "jdoe", CreateDate: "2021-09-15T12:30:00Z"},
+				},
+			},
+			dir:          "with_version_history",
+			filesToCheck: []string{"policy.tf"},
+		},
+		"policy without versions": {
+			givenData: TFPolicyData{
+				Name:              "test_policy_export",
+				PolicyID:          2,
+				Section:           "test_section",
+				CloudletCode:      "ER",
+				Description:       "Testing exported policy",
+				GroupID:           12345,
+				PolicyActivations: map[string]TFPolicyActivationData{},
+				NoVersions:        true,
+			},
+			dir:          "no_versions",
+			filesToCheck: []string{"policy.tf", "match-rules.tf", "variables.tf", "import.sh"},
+		},
+		"policy with full version history": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				VersionHistory: []TFPolicyVersionHistory{
+					{Version: 2, CreatedBy: "jdoe", CreateDate: "2021-09-15T12:30:00Z", RulesFile: "versions/2.json"},
+					{Version: 1, Description: "Initial version", CreatedBy: "jsmith", CreateDate: "2021-09-01T00:00:00Z", RulesFile: "versions/1.json"},
+				},
+			},
+			dir:          "with_full_version_history",
+			filesToCheck: []string{"policy.tf"},
+		},
+		"policy with ALB match rules, resource prefix and snake case naming": {
+			givenData: TFPolicyData{
+				Name:            "Test-Policy Export",
+				Section:         "test_section",
+				CloudletCode:    "ALB",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleALB{
+						Name: "r1",
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "test_origin",
+						},
+					},
+				},
+				LoadBalancers: []cloudlets.LoadBalancerVersion{
+					{OriginID: "test_origin", BalancingType: cloudlets.BalancingTypeWeighted, Version: 2},
+				},
+				LoadBalancerActivations: []cloudlets.LoadBalancerActivation{
+					{Network: cloudlets.LoadBalancerActivationNetworkStaging, OriginID: "test_origin", Status: cloudlets.LoadBalancerActivationStatusActive, Version: 2},
+				},
+				ResourcePrefix: "edge_",
+				ResourceNaming: ResourceNamingSnakeCase,
+			},
+			dir:          "resource_naming_snake_case",
+			filesToCheck: []string{"policy.tf", "match-rules.tf", "load-balancer.tf", "import.sh"},
+		},
+		"policy as module": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"prod": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+					"staging": {
+						PolicyID:   2,
+						Version:    2,
+						Properties: []string{"prp_1", "prp_2"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				AsModule: true,
+			},
+			dir: "as_module",
+			filesToCheck: []string{"main.tf", "variables.tf", "terraform.tfvars", "import.sh", "modules/policy/policy.tf",
+				"modules/policy/match-rules.tf", "modules/policy/variables.tf"},
+			asModule: true,
+		},
+		"policy as module with camel case variables": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"prod": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+					"staging": {
+						PolicyID:   2,
+						Version:    2,
+						Properties: []string{"prp_1", "prp_2"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				AsModule: true,
+			},
+			dir:          "as_module_var_naming_camel",
+			filesToCheck: []string{"main.tf", "variables.tf", "terraform.tfvars", "modules/policy/policy.tf", "modules/policy/variables.tf"},
+			asModule:     true,
+			varNaming:    tools.VarNamingCamel,
+		},
+		"ALB policy active on production as module with import blocks": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ALB",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"prod": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleALB{
+						Name: "r1",
+						ForwardSettings: cloudlets.ForwardSettingsALB{
+							OriginID: "test_origin",
+						},
+					},
+				},
+				LoadBalancers: []cloudlets.LoadBalancerVersion{
+					{OriginID: "test_origin", BalancingType: cloudlets.BalancingTypeWeighted, Version: 2},
+				},
+				LoadBalancerActivations: []cloudlets.LoadBalancerActivation{
+					{Network: cloudlets.LoadBalancerActivationNetworkStaging, OriginID: "test_origin", Status: cloudlets.LoadBalancerActivationStatusActive, Version: 2},
+					{Network: cloudlets.LoadBalancerActivationNetworkProduction, OriginID: "test_origin", Status: cloudlets.LoadBalancerActivationStatusActive, Version: 1},
+				},
+				ImportBlocks: true,
+				AsModule:     true,
+			},
+			dir: "as_module_alb_import_blocks",
+			filesToCheck: []string{"main.tf", "variables.tf", "terraform.tfvars", "imports.tf", "modules/policy/policy.tf",
+				"modules/policy/load-balancer.tf", "modules/policy/variables.tf"},
+			importBlocks: true,
+			asModule:     true,
+		},
+		"shared policy as module with activations skipped": {
+			givenData: TFPolicyData{
+				Name:         "test_policy_export",
+				PolicyID:     11,
+				Version:      3,
+				Section:      "test_section",
+				CloudletCode: "ER",
+				GroupID:      12345,
+				IsShared:     true,
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {PolicyID: 11, Version: 3},
+				},
+				SkipActivations: true,
+				AsModule:        true,
+			},
+			dir:          "as_module_skip_activations",
+			filesToCheck: []string{"main.tf", "variables.tf", "terraform.tfvars", "import.sh", "modules/policy/policy.tf", "modules/policy/variables.tf"},
+			asModule:     true,
+		},
+		"policy with migration to shared policy": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    2,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				MigrateToShared: true,
+			},
+			dir:             "migrate_to_shared",
+			filesToCheck:    []string{"policy.tf", "shared-policy-migration.tf", "import.sh"},
+			migrateToShared: true,
+		},
+		"policy with tfvars": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    2,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				EdgercPath: "/home/test/.edgerc",
+			},
+			dir:          "tfvars",
+			filesToCheck: []string{"variables.tf", "terraform.tfvars", "terraform.tfvars.example"},
+			tfvars:       true,
+		},
+		"policy without activations with tfvars": {
+			givenData: TFPolicyData{
+				Name:              "test_policy_export",
+				Section:           "test_section",
+				CloudletCode:      "ER",
+				GroupID:           12345,
+				PolicyActivations: map[string]TFPolicyActivationData{},
+				NoVersions:        true,
+				EdgercPath:        "~/.edgerc",
+			},
+			dir:          "tfvars_no_activations",
+			filesToCheck: []string{"variables.tf", "terraform.tfvars", "terraform.tfvars.example"},
+			tfvars:       true,
+		},
+		"policy with tfvars and camel case variables": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    2,
+						Properties: []string{"prp_0"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				EdgercPath: "/home/test/.edgerc",
+			},
+			dir:          "tfvars_var_naming_camel",
+			filesToCheck: []string{"policy.tf", "variables.tf", "terraform.tfvars", "terraform.tfvars.example"},
+			tfvars:       true,
+			varNaming:    tools.VarNamingCamel,
+		},
+		"policy with match rules JSON, import blocks and migration to shared policy": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "FR",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleFR{
+						Name: "r1",
+						ForwardSettings: cloudlets.ForwardSettingsFR{
+							PathAndQS: "/ddd",
+						},
+					},
+				},
+				ImportBlocks:    true,
+				SkipActivations: true,
+				MigrateToShared: true,
+			},
+			dir:             "migrate_to_shared_import_blocks",
+			filesToCheck:    []string{"policy.tf", "match-rules.tf", "shared-policy-migration.tf", "imports.tf"},
+			matchRulesJSON:  true,
+			importBlocks:    true,
+			migrateToShared: true,
+		},
+		"policy with match rules module and hash naming": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				Description:     "Testing exported policy",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"prod": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+					"staging": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_1"},
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				ResourceNaming: ResourceNamingHash,
+			},
+			dir:              "resource_naming_hash",
+			filesToCheck:     []string{"policy.tf", "match-rules.tf", "import.sh"},
+			matchRulesModule: true,
+		},
+		"shared policy with import blocks and resource prefix": {
+			givenData: TFPolicyData{
+				Name:         "test_policy_export",
+				PolicyID:     2,
+				Section:      "test_section",
+				CloudletCode: "ER",
+				Description:  "Testing exported policy",
+				GroupID:      12345,
+				IsShared:     true,
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID: 2,
+						Version:  1,
+					},
+				},
+				MatchRules: cloudlets.MatchRules{
+					cloudlets.MatchRuleER{
+						Name:        "r1",
+						StatusCode:  301,
+						RedirectURL: "/ddd",
+					},
+				},
+				ImportBlocks:   true,
+				ResourcePrefix: "edge_",
+			},
+			dir:          "resource_prefix_import_blocks",
+			filesToCheck: []string{"policy.tf", "match-rules.tf", "imports.tf"},
+			importBlocks: true,
+		},
+		"read-only policy without versions": {
+			givenData: TFPolicyData{
+				Name:              "test_policy_export",
+				PolicyID:          2,
+				Section:           "test_section",
+				CloudletCode:      "ER",
+				GroupID:           12345,
+				PolicyActivations: map[string]TFPolicyActivationData{},
+				NoVersions:        true,
+			},
+			dir:          "read_only_no_versions",
+			filesToCheck: []string{"policy.tf", "variables.tf"},
+			readOnly:     true,
+		},
+		"read-only policy": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				PolicyID:        2,
+				Version:         3,
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    3,
+						Properties: []string{"prp_0"},
+					},
+					"prod": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+				},
+			},
+			dir:          "read_only",
+			filesToCheck: []string{"policy.tf", "variables.tf"},
+			readOnly:     true,
+		},
+		"shared policy with match rules and activation": {
+			givenData: TFPolicyData{
+				Name:         "test_policy_export",
+				PolicyID:     11,
+				Version:      3,
+				Section:      "test_section",
+				CloudletCode: "ER",
+				Description:  "Testing exported shared policy",
+				GroupID:      12345,
+				IsShared:     true,
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {PolicyID: 11, Version: 3, Properties: []string{"prp_0", "prp_1"}},
+					"prod":    {PolicyID: 11, Version: 2},
+				},
+				MatchRules: cloudlets.MatchRules{
+					&cloudlets.MatchRuleER{Name: "r1", Type: "erMatchRule", RedirectURL: "/a", StatusCode: 301, UseRelativeURL: "none"},
+				},
+			},
+			dir:          "shared_policy",
+			filesToCheck: []string{"policy.tf", "match-rules.tf", "variables.tf", "import.sh"},
+		},
+		"shared policy without activations": {
+			givenData: TFPolicyData{
+				Name:              "test_policy_export",
+				PolicyID:          11,
+				Version:           3,
+				Section:           "test_section",
+				CloudletCode:      "ER",
+				GroupID:           12345,
+				IsShared:          true,
+				PolicyActivations: map[string]TFPolicyActivationData{},
+			},
+			dir:          "shared_policy_no_activations",
+			filesToCheck: []string{"policy.tf", "variables.tf", "import.sh"},
+		},
+		"read-only shared policy": {
+			givenData: TFPolicyData{
+				Name:         "test_policy_export",
+				PolicyID:     11,
+				Version:      3,
+				Section:      "test_section",
+				CloudletCode: "ER",
+				GroupID:      12345,
+				IsShared:     true,
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {PolicyID: 11, Version: 3},
+				},
+			},
+			dir:          "read_only_shared",
+			filesToCheck: []string{"policy.tf", "variables.tf"},
+			readOnly:     true,
+		},
+		"policy as data": {
+			givenData: TFPolicyData{
+				Name:            "test_policy_export",
+				PolicyID:        2,
+				Version:         3,
+				Section:         "test_section",
+				CloudletCode:    "ER",
+				GroupID:         12345,
+				MatchRuleFormat: "1.0",
+				PolicyActivations: map[string]TFPolicyActivationData{
+					"staging": {
+						PolicyID:   2,
+						Version:    3,
+						Properties: []string{"prp_0"},
+					},
+					"prod": {
+						PolicyID:   2,
+						Version:    1,
+						Properties: []string{"prp_0"},
+					},
+				},
+			},
+			dir:          "as_data",
+			filesToCheck: []string{"policy.tf", "variables.tf", "outputs.tf"},
+			readOnly:     true,
+			asData:       true,
+		},
+		"shared policy without versions as data": {
+			givenData: TFPolicyData{
+				Name:              "test_policy_export",
+				PolicyID:          11,
+				Section:           "test_section",
+				CloudletCode:      "ER",
+				GroupID:           12345,
+				IsShared:          true,
+				PolicyActivations: map[string]TFPolicyActivationData{},
+				NoVersions:        true,
+			},
+			dir:          "as_data_shared_no_versions",
+			filesToCheck: []string{"policy.tf", "variables.tf", "outputs.tf"},
+			readOnly:     true,
+			asData:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools.VarNaming = test.varNaming
+			defer func() { tools.VarNaming = "" }()
+			configDir := fmt.Sprintf("./testdata/res/%s", test.dir)
+			if test.asModule {
+				configDir += "/modules/policy"
+			}
+			require.NoError(t, os.MkdirAll(configDir, 0755))
+			processor := templates.FSTemplateProcessor{
+				TemplatesFS: templateFiles,
+				TemplateTargets: map[string]string{
+					"policy.tmpl":        fmt.Sprintf("%s/policy.tf", configDir),
+					"match-rules.tmpl":   fmt.Sprintf("%s/match-rules.tf", configDir),
+					"load-balancer.tmpl": fmt.Sprintf("%s/load-balancer.tf", configDir),
+					"variables.tmpl":     fmt.Sprintf("%s/variables.tf", configDir),
+					"imports.tmpl":       fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
+				},
+			}
+			if test.matchRulesModule {
+				locals, err := matchRulesLocals(test.givenData.MatchRules)
+				require.NoError(t, err)
+				test.givenData.MatchRulesLocals = locals
+				delete(processor.TemplateTargets, "match-rules.tmpl")
+				processor.TemplateTargets["match-rules-locals.tmpl"] = fmt.Sprintf("./testdata/res/%s/match-rules.tf", test.dir)
+				processor.TemplateTargets["match-rules-module.tmpl"] = fmt.Sprintf("./testdata/res/%s/modules/match-rules/main.tf", test.dir)
+			}
+			if test.matchRulesJSON {
+				content, err := matchRulesJSONContent(test.givenData.MatchRules)
+				require.NoError(t, err)
+				test.givenData.MatchRulesJSON = content
+				delete(processor.TemplateTargets, "match-rules.tmpl")
+				processor.TemplateTargets["match-rules-jsondecode.tmpl"] = fmt.Sprintf("./testdata/res/%s/match-rules.tf", test.dir)
+				processor.TemplateTargets["match-rules-json.tmpl"] = fmt.Sprintf("./testdata/res/%s/match-rules.json", test.dir)
+			}
+			if test.splitMatchRules {
+				options := policyOptions{rulesForm: matchRulesSplit, policyDir: fmt.Sprintf("./testdata/res/%s", test.dir)}
+				require.NoError(t, formatMatchRules(context.Background(), &test.givenData, options))
+				delete(processor.TemplateTargets, "match-rules.tmpl")
+				processor.TemplateTargets["match-rules-jsondecode.tmpl"] = fmt.Sprintf("./testdata/res/%s/match-rules.tf", test.dir)
+			}
+			if test.importBlocks {
+				delete(processor.TemplateTargets, "imports.tmpl")
+				processor.TemplateTargets["import-blocks.tmpl"] = fmt.Sprintf("./testdata/res/%s/imports.tf", test.dir)
+			}
+			if test.asModule {
+				delete(processor.TemplateTargets, "variables.tmpl")
+				processor.TemplateTargets["module-variables.tmpl"] = fmt.Sprintf("%s/variables.tf", configDir)
+				processor.TemplateTargets["module-root.tmpl"] = fmt.Sprintf("./testdata/res/%s/main.tf", test.dir)
+				processor.TemplateTargets["module-root-variables.tmpl"] = fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir)
+				processor.TemplateTargets["module-tfvars.tmpl"] = fmt.Sprintf("./testdata/res/%s/terraform.tfvars", test.dir)
+			}
+			if test.tfvars {
+				processor.TemplateTargets["tfvars.tmpl"] = fmt.Sprintf("./testdata/res/%s/terraform.tfvars", test.dir)
+				processor.TemplateTargets["tfvars-example.tmpl"] = fmt.Sprintf("./testdata/res/%s/terraform.tfvars.example", test.dir)
+			}
+			if test.migrateToShared {
+				processor.TemplateTargets["shared-policy-migration.tmpl"] = fmt.Sprintf("%s/shared-policy-migration.tf", configDir)
+			}
+			if test.readOnly {
+				processor.TemplateTargets = map[string]string{
+					"policy-read-only.tmpl": fmt.Sprintf("./testdata/res/%s/policy.tf", test.dir),
+					"variables.tmpl":        fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+				}
+			}
+			if test.asData {
+				processor.TemplateTargets["policy-outputs.tmpl"] = fmt.Sprintf("./testdata/res/%s/outputs.tf", test.dir)
+			}
+			require.NoError(t, processor.ProcessTemplates(test.givenData))
+
+			testutils.AssertFiles(t, fmt.Sprintf("./testdata/%s", test.dir), fmt.Sprintf("./testdata/res/%s", test.dir), test.filesToCheck...)
+		})
+	}
+}
+
+func TestEnvVariable(t *testing.T) {
+	staging := TFPolicyActivationData{PolicyID: 2, Version: 1, Properties: []string{"prp_0"}}
+	prod := TFPolicyActivationData{PolicyID: 2, Version: 1, Properties: []string{"prp_1"}}
+	loadBalancers := []cloudlets.LoadBalancerVersion{{OriginID: "test_origin", Version: 1}}
+	tests := map[string]struct {
+		givenData TFPolicyData
+		expected  bool
+	}{
+		"active on staging": {
+			givenData: TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{"staging": staging}},
+			expected:  true,
+		},
+		"active on production": {
+			givenData: TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{"prod": prod}},
+			expected:  true,
+		},
+		"active on both networks with the same properties": {
+			givenData: TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{"staging": staging, "prod": staging}},
+			expected:  true,
+		},
+		"active on both networks with different properties": {
+			givenData: TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{"staging": staging, "prod": prod}},
+		},
+		"not active": {
+			givenData: TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{}},
+		},
+		"not active with load balancers": {
+			givenData: TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{}, LoadBalancers: loadBalancers},
+			expected:  true,
+		},
+		"shared policy": {
+			givenData: TFPolicyData{IsShared: true, PolicyActivations: map[string]TFPolicyActivationData{"staging": staging}},
+		},
+		"shared policy with load balancers": {
+			givenData: TFPolicyData{IsShared: true, LoadBalancers: loadBalancers},
+			expected:  true,
+		},
+		"activations skipped": {
+			givenData: TFPolicyData{SkipActivations: true, PolicyActivations: map[string]TFPolicyActivationData{"staging": staging}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.givenData.EnvVariable())
+		})
+	}
+}
+
+func TestCreatePolicyImportOrder(t *testing.T) {
+	pageSize := 1000
+	client := new(cloudlets.Mock)
+	client.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+		{
+			PolicyID:     2,
+			GroupID:      234,
+			Name:         "test_policy",
+			CloudletCode: "ALB",
+			Activations: []cloudlets.PolicyActivation{
+				{
+					Network:      "staging",
+					PolicyInfo:   cloudlets.PolicyInfo{Version: 1},
+					PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp_1"},
+				},
+			},
+		},
+	}, nil).Once()
+	client.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).
+		Return([]cloudlets.PolicyVersion{{PolicyID: 2, Version: 1}}, nil).Once()
+	client.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 2, Version: 1}).Return(&cloudlets.PolicyVersion{
+		PolicyID: 2,
+		Version:  1,
+		MatchRules: cloudlets.MatchRules{
+			&cloudlets.MatchRuleALB{Name: "r1", Type: "albMatchRule", ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: "origin_b"}},
+			&cloudlets.MatchRuleALB{Name: "r2", Type: "albMatchRule", ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: "origin_a"}},
+		},
+		MatchRuleFormat: "1.0",
+	}, nil).Once()
+	for _, originID := range []string{"origin_a", "origin_b"} {
+		client.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: originID}).
+			Return([]cloudlets.LoadBalancerVersion{{OriginID: originID, Version: 1, BalancingType: cloudlets.BalancingTypeWeighted}}, nil).Once()
+	}
+	// origin_a is active on staging, origin_b only on production
+	client.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{OriginID: "origin_a"}).
+		Return([]cloudlets.LoadBalancerActivation{{Network: "STAGING", OriginID: "origin_a", Status: cloudlets.LoadBalancerActivationStatusActive, Version: 1}}, nil).Once()
+	client.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{OriginID: "origin_b"}).
+		Return([]cloudlets.LoadBalancerActivation{{Network: "PRODUCTION", OriginID: "origin_b", Status: cloudlets.LoadBalancerActivationStatusActive, Version: 1}}, nil).Once()
+
+	dir := "./testdata/res/import_order"
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	processor := templates.FSTemplateProcessor{
+		TemplatesFS:     templateFiles,
+		TemplateTargets: map[string]string{"imports.tmpl": dir + "/import.sh"},
+	}
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+	require.NoError(t, createPolicy(ctx, "test_policy", 0, 0, "test_section", policyOptions{}, client, new(shared.Mock), processor))
+
+	client.AssertExpectations(t)
+	testutils.AssertFiles(t, "./testdata/import_order", dir, "import.sh")
+}
+
+func TestFindPolicy(t *testing.T) {
+	defer func(retries int, backoff time.Duration) {
+		tools.Retries, tools.RetryBackoff = retries, backoff
+	}(tools.Retries, tools.RetryBackoff)
+	tools.Retries, tools.RetryBackoff = 3, time.Millisecond
 	pageSize := 1000
 	preparePoliciesPage := func(pageSize, startingID int64) []cloudlets.Policy {
 		policies := make([]cloudlets.Policy, 0, pageSize)
@@ -2033,6 +3721,29 @@ func TestFindPolicy(t *testing.T) {
 			},
 			withError: true,
 		},
+		"policy found after transient error on 3rd page": {
+			policyName: "test_policy",
+			init: func(m *cloudlets.Mock) {
+				m.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).
+					Return(preparePoliciesPage(1000, 0), nil).Once()
+				m.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 1000}).
+					Return(preparePoliciesPage(1000, 1000), nil).Once()
+				m.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 2000}).
+					Return(nil, &cloudlets.Error{StatusCode: 502}).Once()
+				m.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 2000}).Return([]cloudlets.Policy{
+					{PolicyID: 1234567, Name: "test_policy"},
+				}, nil).Once()
+			},
+			expectedID: 1234567,
+		},
+		"error listing policies after retries are exhausted": {
+			policyName: "test_policy",
+			init: func(m *cloudlets.Mock) {
+				m.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).
+					Return(nil, &cloudlets.Error{StatusCode: 503}).Times(4)
+			},
+			withError: true,
+		},
 		"error listing policies": {
 			policyName: "test_policy",
 			init: func(m *cloudlets.Mock) {
@@ -2061,7 +3772,59 @@ func TestFindPolicy(t *testing.T) {
 	}
 }
 
+func TestFindPolicyCanceledContext(t *testing.T) {
+	m := new(cloudlets.Mock)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := findPolicyByName(ctx, "test_policy", m)
+	assert.True(t, errors.Is(err, context.Canceled), "expected: %s; got: %s", context.Canceled, err)
+	m.AssertNotCalled(t, "ListPolicies", mock.Anything, mock.Anything)
+}
+
+func TestFindPolicyInBatch(t *testing.T) {
+	pageSize := 1000
+	m := new(cloudlets.Mock)
+	m.On("ListPolicies", mock.Anything, cloudlets.ListPoliciesRequest{PageSize: &pageSize, Offset: 0}).Return([]cloudlets.Policy{
+		{PolicyID: 1, Name: "policy_1"},
+		{PolicyID: 2, Name: "policy_2"},
+		{PolicyID: 3, Name: "policy_3"},
+	}, nil).Once()
+
+	var mu sync.Mutex
+	found := make(map[string]int64)
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{{
+		Name:  "export-cloudlets-policy",
+		Flags: []cli.Flag{&cli.StringFlag{Name: "tfworkpath"}},
+		Action: func(c *cli.Context) error {
+			policy, err := findPolicyByName(c.Context, c.Args().First(), m)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			found[policy.Name] = policy.PolicyID
+			return nil
+		},
+	}}
+	c := cli.NewContext(app, flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	c.Context = terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+	var objects []manifest.Object
+	for _, name := range []string{"policy_1", "policy_2", "policy_3"} {
+		objects = append(objects, manifest.Object{Product: "cloudlets", Name: name, Command: "export-cloudlets-policy", Args: []string{name}})
+	}
+
+	require.NoError(t, batch.Run(c, objects, t.TempDir()))
+	m.AssertExpectations(t)
+	assert.Equal(t, map[string]int64{"policy_1": 1, "policy_2": 2, "policy_3": 3}, found)
+}
+
 func TestGetLatestPolicyVersion(t *testing.T) {
+	defer func(retries int, backoff time.Duration) {
+		tools.Retries, tools.RetryBackoff = retries, backoff
+	}(tools.Retries, tools.RetryBackoff)
+	tools.Retries, tools.RetryBackoff = 3, time.Millisecond
 	pageSize := 1000
 	prepareVersionsPage := func(pageSize, startingVersion int64) []cloudlets.PolicyVersion {
 		versions := make([]cloudlets.PolicyVersion, 0, pageSize)
@@ -2100,6 +3863,18 @@ func TestGetLatestPolicyVersion(t *testing.T) {
 			},
 			expected: 2499,
 		},
+		"policy version found after transient error": {
+			policyID: 123,
+			init: func(m *cloudlets.Mock) {
+				m.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 123, PageSize: &pageSize, Offset: 0}).
+					Return(nil, &cloudlets.Error{StatusCode: 429}).Once()
+				m.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 123, PageSize: &pageSize, Offset: 0}).
+					Return(prepareVersionsPage(3, 1), nil).Once()
+				m.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 123, Version: 3}).
+					Return(&cloudlets.PolicyVersion{Version: 3}, nil).Once()
+			},
+			expected: 3,
+		},
 		"no policy versions found": {
 			policyID: 123,
 			init: func(m *cloudlets.Mock) {
@@ -2108,6 +3883,18 @@ func TestGetLatestPolicyVersion(t *testing.T) {
 			},
 			withError: true,
 		},
+		"empty page after full page of versions": {
+			policyID: 123,
+			init: func(m *cloudlets.Mock) {
+				m.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 123, PageSize: &pageSize, Offset: 0}).
+					Return(prepareVersionsPage(1000, 1), nil).Once()
+				m.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 123, PageSize: &pageSize, Offset: 1000}).
+					Return([]cloudlets.PolicyVersion{}, nil).Once()
+				m.On("GetPolicyVersion", mock.Anything, cloudlets.GetPolicyVersionRequest{PolicyID: 123, Version: 1000}).
+					Return(&cloudlets.PolicyVersion{Version: 1000}, nil).Once()
+			},
+			expected: 1000,
+		},
 		"error listing policy versions": {
 			policyID: 123,
 			init: func(m *cloudlets.Mock) {
@@ -2142,3 +3929,229 @@ func TestGetLatestPolicyVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestActiveVersion(t *testing.T) {
+	collector := warnings.NewCollector()
+	ctx := warnings.WithCollector(context.Background(), collector)
+	activations := map[string]TFPolicyActivationData{
+		"staging": {PolicyID: 2, Version: 3},
+	}
+
+	assert.Equal(t, int64(3), activeVersion(ctx, "test_policy", activations, networkStaging))
+	assert.Empty(t, collector.Warnings())
+	assert.Equal(t, int64(0), activeVersion(ctx, "test_policy", activations, networkProduction))
+	assert.Equal(t, []warnings.Warning{{
+		Product: "cloudlets policy",
+		Object:  "test_policy",
+		Reason:  "policy is not active on production network, its latest version is exported",
+	}}, collector.Warnings())
+}
+
+func TestGetPolicyActivations(t *testing.T) {
+	collector := warnings.NewCollector()
+	ctx := warnings.WithCollector(context.Background(), collector)
+	policy := &cloudlets.Policy{
+		PolicyID: 2,
+		Name:     "test_policy",
+		Activations: []cloudlets.PolicyActivation{
+			{Network: "PRODUCTION", PolicyInfo: cloudlets.PolicyInfo{Version: 1}, PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp_1"}},
+			{Network: "staging", PolicyInfo: cloudlets.PolicyInfo{Version: 2}, PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp_1"}},
+			{Network: "production", PolicyInfo: cloudlets.PolicyInfo{Version: 1}, PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp_2"}},
+			{Network: "qa", PolicyInfo: cloudlets.PolicyInfo{Version: 3}, PropertyInfo: cloudlets.PropertyInfo{Name: "test_prp_3"}},
+		},
+	}
+
+	assert.Equal(t, map[string]TFPolicyActivationData{
+		"staging": {PolicyID: 2, Version: 2, Properties: []string{"test_prp_1"}},
+		"prod":    {PolicyID: 2, Version: 1, Properties: []string{"test_prp_1", "test_prp_2"}},
+	}, getPolicyActivations(ctx, policy))
+	assert.Equal(t, []warnings.Warning{{
+		Product: "cloudlets policy",
+		Object:  "test_policy",
+		Reason:  "activation on property 'test_prp_3' is skipped: unsupported activation network: 'qa'",
+	}}, collector.Warnings())
+}
+
+func TestGetApplicationLoadBalancerActivations(t *testing.T) {
+	tests := map[string]struct {
+		activations      []cloudlets.LoadBalancerActivation
+		expected         []cloudlets.LoadBalancerActivation
+		expectedWarnings []warnings.Warning
+	}{
+		"newest activation on each network": {
+			activations: []cloudlets.LoadBalancerActivation{
+				{ActivatedDate: "2021-10-29T00:00:10.000Z", Network: "STAGING", Version: 1},
+				{ActivatedDate: "2021-10-29T00:00:30.000Z", Network: "staging", Version: 3},
+				{ActivatedDate: "2021-10-29T00:00:20.000Z", Network: "Staging", Version: 2},
+				{ActivatedDate: "2021-10-29T00:00:10.000Z", Network: "prod", Version: 1},
+			},
+			expected: []cloudlets.LoadBalancerActivation{
+				{ActivatedDate: "2021-10-29T00:00:10.000Z", Network: cloudlets.LoadBalancerActivationNetworkProduction, Version: 1},
+				{ActivatedDate: "2021-10-29T00:00:30.000Z", Network: cloudlets.LoadBalancerActivationNetworkStaging, Version: 3},
+			},
+		},
+		"unknown network is skipped": {
+			activations: []cloudlets.LoadBalancerActivation{
+				{ActivatedDate: "2021-10-29T00:00:10.000Z", Network: "production", Version: 1},
+				{ActivatedDate: "2021-10-29T00:00:20.000Z", Network: "qa", Version: 2},
+			},
+			expected: []cloudlets.LoadBalancerActivation{
+				{ActivatedDate: "2021-10-29T00:00:10.000Z", Network: cloudlets.LoadBalancerActivationNetworkProduction, Version: 1},
+			},
+			expectedWarnings: []warnings.Warning{{
+				Product: "cloudlets load balancer",
+				Object:  "test_origin",
+				Reason:  "activation of version 2 is skipped: unsupported activation network: 'qa'",
+			}},
+		},
+		"no activations": {
+			expected: []cloudlets.LoadBalancerActivation{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			collector := warnings.NewCollector()
+			ctx := warnings.WithCollector(context.Background(), collector)
+			m := new(cloudlets.Mock)
+			m.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{OriginID: "test_origin"}).
+				Return(test.activations, nil).Once()
+
+			activations, err := getApplicationLoadBalancerActivations(ctx, m, "test_origin")
+			require.NoError(t, err)
+			m.AssertExpectations(t)
+			assert.Equal(t, test.expected, activations)
+			assert.ElementsMatch(t, test.expectedWarnings, collector.Warnings())
+		})
+	}
+}
+
+func TestReportInactiveLoadBalancerVersions(t *testing.T) {
+	collector := warnings.NewCollector()
+	ctx := warnings.WithCollector(context.Background(), collector)
+	loadBalancers := []cloudlets.LoadBalancerVersion{
+		{OriginID: "origin_1", Version: 3},
+		{OriginID: "origin_2", Version: 1},
+	}
+	activations := []cloudlets.LoadBalancerActivation{
+		{OriginID: "origin_1", Network: cloudlets.LoadBalancerActivationNetworkProduction, Version: 2},
+		{OriginID: "origin_1", Network: cloudlets.LoadBalancerActivationNetworkStaging, Version: 3},
+		{OriginID: "origin_2", Network: cloudlets.LoadBalancerActivationNetworkStaging, Version: 1},
+		{OriginID: "origin_3", Network: cloudlets.LoadBalancerActivationNetworkStaging, Version: 5},
+	}
+
+	reportInactiveLoadBalancerVersions(ctx, loadBalancers, activations)
+	assert.Equal(t, []warnings.Warning{{
+		Product: "cloudlets load balancer",
+		Object:  "origin_1",
+		Reason:  "version 2 is active on production network, applying the configuration activates exported version 3",
+	}}, collector.Warnings())
+}
+
+func TestGetLoadBalancersConcurrently(t *testing.T) {
+	defer func(c int) { tools.Concurrency = c }(tools.Concurrency)
+	tools.Concurrency = 8
+
+	m := new(cloudlets.Mock)
+	var originIDs []string
+	var expectedVersions []cloudlets.LoadBalancerVersion
+	var expectedActivations []cloudlets.LoadBalancerActivation
+	for i := 0; i < 40; i++ {
+		originID := fmt.Sprintf("origin_%02d", i)
+		originIDs = append(originIDs, originID)
+		latest := cloudlets.LoadBalancerVersion{OriginID: originID, Version: int64(i + 2)}
+		activation := cloudlets.LoadBalancerActivation{Network: cloudlets.LoadBalancerActivationNetworkStaging, OriginID: originID, Version: latest.Version}
+		m.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: originID}).
+			Return([]cloudlets.LoadBalancerVersion{{OriginID: originID, Version: 1}, latest}, nil).Once()
+		m.On("ListLoadBalancerActivations", mock.Anything, cloudlets.ListLoadBalancerActivationsRequest{OriginID: originID}).
+			Return([]cloudlets.LoadBalancerActivation{activation}, nil).Once()
+		expectedVersions = append(expectedVersions, latest)
+		expectedActivations = append(expectedActivations, activation)
+	}
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+
+	loadBalancers, err := getLoadBalancers(ctx, m, originIDs)
+	require.NoError(t, err)
+	assert.Equal(t, expectedVersions, loadBalancers)
+
+	activations, err := getLoadBalancerActivations(ctx, m, originIDs)
+	require.NoError(t, err)
+	assert.Equal(t, expectedActivations, activations)
+	m.AssertExpectations(t)
+}
+
+func TestGetLoadBalancersError(t *testing.T) {
+	m := new(cloudlets.Mock)
+	m.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "origin_a"}).
+		Return([]cloudlets.LoadBalancerVersion{{OriginID: "origin_a", Version: 1}}, nil).Maybe()
+	m.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "origin_b"}).
+		Return(nil, fmt.Errorf("oops")).Once()
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+
+	_, err := getLoadBalancers(ctx, m, []string{"origin_a", "origin_b"})
+	assert.EqualError(t, err, "oops")
+}
+
+func TestGetLoadBalancersNotFound(t *testing.T) {
+	m := new(cloudlets.Mock)
+	m.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "origin_a"}).
+		Return([]cloudlets.LoadBalancerVersion{{OriginID: "origin_a", Version: 1}}, nil).Once()
+	m.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "origin_b"}).
+		Return(nil, &cloudlets.Error{StatusCode: http.StatusNotFound}).Once()
+	m.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "origin_c"}).
+		Return([]cloudlets.LoadBalancerVersion{}, nil).Once()
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+
+	originIDs := []string{"origin_a", "origin_b", "origin_c"}
+	loadBalancers, err := getLoadBalancers(ctx, m, originIDs)
+	require.NoError(t, err)
+	assert.Equal(t, []cloudlets.LoadBalancerVersion{{OriginID: "origin_a", Version: 1}}, loadBalancers)
+	assert.Equal(t, []string{"origin_b", "origin_c"}, missingLoadBalancers(originIDs, loadBalancers))
+	m.AssertExpectations(t)
+}
+
+func TestAddLoadBalancersReportsMissing(t *testing.T) {
+	m := new(cloudlets.Mock)
+	m.On("ListLoadBalancerVersions", mock.Anything, cloudlets.ListLoadBalancerVersionsRequest{OriginID: "deleted_origin"}).
+		Return(nil, &cloudlets.Error{StatusCode: http.StatusNotFound}).Once()
+	collector := warnings.NewCollector()
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+	ctx = warnings.WithCollector(ctx, collector)
+	tfPolicyData := TFPolicyData{
+		Name: "test_policy",
+		MatchRules: cloudlets.MatchRules{
+			&cloudlets.MatchRuleALB{ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: "deleted_origin"}},
+		},
+	}
+
+	require.NoError(t, addLoadBalancers(ctx, m, &tfPolicyData))
+	assert.Empty(t, tfPolicyData.LoadBalancers)
+	assert.Empty(t, tfPolicyData.LoadBalancerActivations)
+	assert.Equal(t, []string{"deleted_origin"}, tfPolicyData.MissingLoadBalancers)
+	assert.Equal(t, []warnings.Warning{{
+		Product: "cloudlets load balancer",
+		Object:  "deleted_origin",
+		Reason:  "load balancer does not exist or has no versions, a placeholder is exported for match rules forwarding to the origin",
+	}}, collector.Warnings())
+	m.AssertExpectations(t)
+}
+
+func TestLoadBalancerOrigins(t *testing.T) {
+	loadBalancers := []cloudlets.LoadBalancerVersion{
+		{
+			OriginID: "alb_1",
+			DataCenters: []cloudlets.DataCenter{
+				{OriginID: "dc_east", Hostname: "east.example.com"},
+				{OriginID: "dc_west"},
+			},
+		},
+		{OriginID: "alb_2"},
+	}
+	assert.Equal(t, []origins.Origin{
+		{ID: "alb_1"},
+		{ID: "dc_east", Hostname: "east.example.com"},
+		{ID: "dc_west"},
+		{ID: "alb_2"},
+	}, loadBalancerOrigins(loadBalancers))
+	assert.Empty(t, loadBalancerOrigins(nil))
+}
@@ -0,0 +1,330 @@
+package cloudlets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+)
+
+// createSharedPolicy exports the given version of the shared policy managed by Cloudlets API v3, or its latest version
+// when the version is not positive, or its version active on the network given with active-version flag. Load
+// balancers of ALB policies are still managed by the legacy API and are fetched with its client.
+func createSharedPolicy(ctx context.Context, policy *shared.Policy, version int64, section string, options policyOptions, client cloudlets.Cloudlets, clientShared shared.Policies, templateProcessor templates.TemplateProcessor) error {
+	if _, ok := supportedCloudlets[policy.CloudletType]; !ok {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrCloudletTypeNotSupported, policy.CloudletType)
+	}
+
+	tfPolicyData := TFPolicyData{
+		Section:           section,
+		Name:              policy.Name,
+		PolicyID:          policy.ID,
+		CloudletCode:      policy.CloudletType,
+		Description:       policy.Description,
+		GroupID:           policy.GroupID,
+		IsShared:          true,
+		PolicyActivations: getSharedPolicyActivations(policy),
+		SkipActivations:   options.skipActivations,
+		ImportBlocks:      options.importBlocks,
+		ResourcePrefix:    options.resourcePrefix,
+		ResourceNaming:    options.resourceNaming,
+		AsModule:          options.asModule,
+		EdgercPath:        options.edgercPath,
+	}
+	if options.migrateToShared {
+		markSharedMigration(ctx, &tfPolicyData)
+	}
+	if options.activeNetwork != "" {
+		version = activeVersion(ctx, policy.Name, tfPolicyData.PolicyActivations, options.activeNetwork)
+	}
+
+	var versions []shared.PolicyVersion
+	var err error
+	if !options.skipActivations && len(tfPolicyData.PolicyActivations) > 0 {
+		properties, err := listSharedPolicyProperties(ctx, policy.ID, clientShared)
+		if err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingPolicyProperties, err)
+		}
+		addSharedPolicyProperties(ctx, policy.Name, tfPolicyData.PolicyActivations, properties)
+	}
+	// versions are listed only to find the latest one or for the history
+	if version <= 0 || options.versionHistory > 0 || options.historyDir != "" {
+		if versions, err = listSharedPolicyVersions(ctx, policy.ID, clientShared); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
+		}
+	}
+	if version <= 0 && len(versions) == 0 {
+		warnings.Report(ctx, warnings.Warning{
+			Product: "cloudlets policy",
+			Object:  policy.Name,
+			Reason:  "policy has no versions, only the policy is exported with a placeholder for match rules",
+		})
+		workspace.RecordObject(ctx, workspace.Object{
+			Product: "cloudlets",
+			ID:      strconv.FormatInt(policy.ID, 10),
+			Name:    policy.Name,
+		})
+		tfPolicyData.NoVersions = true
+		return processPolicyTemplates(ctx, policy.Name, tfPolicyData, templateProcessor)
+	}
+
+	if version <= 0 {
+		version = versions[0].Version
+	}
+	policyVersion, err := clientShared.GetPolicyVersion(ctx, shared.GetPolicyVersionRequest{
+		PolicyID: policy.ID,
+		Version:  version,
+	})
+	if apierrors.StatusCode(err) == http.StatusNotFound {
+		err = fmt.Errorf("%w: %d", errPolicyVersionNotFound, version)
+	}
+	if err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
+	}
+	workspace.RecordObject(ctx, workspace.Object{
+		Product: "cloudlets",
+		ID:      strconv.FormatInt(policy.ID, 10),
+		Name:    policy.Name,
+		Version: strconv.FormatInt(policyVersion.Version, 10),
+	})
+	tfPolicyData.Version = policyVersion.Version
+	tfPolicyData.Description = policyVersion.Description
+	tfPolicyData.MatchRules = policyVersion.MatchRules
+	if err = formatMatchRules(ctx, &tfPolicyData, options); err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
+	}
+	if options.versionHistory > 0 {
+		tfPolicyData.VersionHistory = sharedVersionHistory(versions, options.versionHistory)
+	}
+	if options.historyDir != "" {
+		tfPolicyData.VersionHistory = sharedVersionHistory(versions, len(versions))
+	}
+
+	if tfPolicyData.CloudletCode == "ALB" {
+		if err := addLoadBalancers(ctx, client, &tfPolicyData); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingVersion, err)
+		}
+	}
+
+	if options.historyDir != "" {
+		err = writeVersionRules(ctx, policy.Name, options.historyDir, tfPolicyData.VersionHistory, func(ctx context.Context, version int64) (cloudlets.MatchRules, error) {
+			policyVersion, err := clientShared.GetPolicyVersion(ctx, shared.GetPolicyVersionRequest{PolicyID: policy.ID, Version: version})
+			if err != nil {
+				return nil, err
+			}
+			return policyVersion.MatchRules, nil
+		})
+		if err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingVersionHistory, err)
+		}
+	}
+
+	return processPolicyTemplates(ctx, policy.Name, tfPolicyData, templateProcessor)
+}
+
+// getSharedPolicy returns the shared policy with the ID when it is positive, otherwise the policy is looked up by its
+// name; errPolicyNotFound is returned if there is none
+func getSharedPolicy(ctx context.Context, name string, id int64, client shared.Policies) (*shared.Policy, error) {
+	if id <= 0 {
+		return findSharedPolicyByName(ctx, name, client)
+	}
+	policy, err := client.GetPolicy(ctx, shared.GetPolicyRequest{PolicyID: id})
+	if apierrors.StatusCode(err) == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: ID %d", errPolicyNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// findSharedPolicyByName returns the shared policy with the given name, errPolicyNotFound is returned if there is none
+func findSharedPolicyByName(ctx context.Context, name string, client shared.Policies) (*shared.Policy, error) {
+	pageSize := tools.PageSize(tools.PageSizeCloudlets, 1000)
+	for page := 0; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		policies, err := client.ListPolicies(ctx, shared.ListPoliciesRequest{Page: page, Size: pageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range policies.Content {
+			if p.Name == name {
+				return &p, nil
+			}
+		}
+		if len(policies.Content) < pageSize || page+1 >= policies.Page.TotalPages {
+			break
+		}
+	}
+	return nil, fmt.Errorf("%w: '%s'", errPolicyNotFound, name)
+}
+
+// listSharedPolicyVersions returns all versions of the shared policy, newest first
+func listSharedPolicyVersions(ctx context.Context, policyID int64, client shared.Policies) ([]shared.PolicyVersion, error) {
+	var versions []shared.PolicyVersion
+	pageSize := tools.PageSize(tools.PageSizeCloudlets, 1000)
+	for page := 0; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := client.ListPolicyVersions(ctx, shared.ListPolicyVersionsRequest{
+			PolicyID: policyID,
+			Page:     page,
+			Size:     pageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, result.Content...)
+		if len(result.Content) < pageSize || page+1 >= result.Page.TotalPages {
+			break
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions, nil
+}
+
+// sharedVersionHistory returns metadata of at most count latest versions, which are sorted newest first, with
+// descriptions flattened into a single line, so that they fit into a comment
+func sharedVersionHistory(versions []shared.PolicyVersion, count int) []TFPolicyVersionHistory {
+	if len(versions) > count {
+		versions = versions[:count]
+	}
+	history := make([]TFPolicyVersionHistory, 0, len(versions))
+	for _, version := range versions {
+		history = append(history, TFPolicyVersionHistory{
+			Version:     version.Version,
+			Description: strings.Join(strings.Fields(version.Description), " "),
+			CreatedBy:   version.CreatedBy,
+			CreateDate:  version.CreatedDate,
+		})
+	}
+	return history
+}
+
+// getSharedPolicyActivations returns the version of the shared policy in effect on each network it is active on, keyed
+// by staging and prod. Properties using the policy are listed separately, see addSharedPolicyProperties.
+func getSharedPolicyActivations(policy *shared.Policy) map[string]TFPolicyActivationData {
+	activations := make(map[string]TFPolicyActivationData)
+	for n, info := range map[network]shared.ActivationInfo{
+		networkStaging:    policy.CurrentActivations.Staging,
+		networkProduction: policy.CurrentActivations.Production,
+	} {
+		activation := info.Effective
+		if activation == nil || activation.Operation != shared.OperationActivation || activation.Status != shared.StatusSuccess {
+			continue
+		}
+		activations[policyActivationKeys[n]] = TFPolicyActivationData{
+			PolicyID: policy.ID,
+			Version:  activation.PolicyVersion,
+		}
+	}
+	return activations
+}
+
+// TFSharedPolicyActivationData represents an activation of the shared policy on the network, exported as its own
+// resource
+type TFSharedPolicyActivationData struct {
+	TFPolicyActivationData
+	Network string
+}
+
+// SharedPolicyActivations returns activations of the shared policy on staging and production networks, in this order,
+// each exported as a separate activation resource as the shared policy can be active on both networks at once
+func (d TFPolicyData) SharedPolicyActivations() []TFSharedPolicyActivationData {
+	var activations []TFSharedPolicyActivationData
+	for _, n := range []network{networkStaging, networkProduction} {
+		if activation, ok := d.PolicyActivations[policyActivationKeys[n]]; ok {
+			activations = append(activations, TFSharedPolicyActivationData{TFPolicyActivationData: activation, Network: string(n)})
+		}
+	}
+	return activations
+}
+
+// isSharedPolicyLookupSkipped returns true if the error of looking up a shared policy means that the policy is not
+// shared, either because there is no shared policy with the name, or because the client has no access to the API
+func isSharedPolicyLookupSkipped(err error) bool {
+	return errors.Is(err, errPolicyNotFound) || apierrors.NotEntitled(err)
+}
+
+// listSharedPolicyProperties returns all properties which use the shared policy on any network
+func listSharedPolicyProperties(ctx context.Context, policyID int64, client shared.Policies) ([]shared.PolicyProperty, error) {
+	var properties []shared.PolicyProperty
+	pageSize := tools.PageSize(tools.PageSizeCloudlets, 1000)
+	for page := 0; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := client.ListPolicyProperties(ctx, shared.ListPolicyPropertiesRequest{
+			PolicyID: policyID,
+			Page:     page,
+			Size:     pageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, result.Content...)
+		if len(result.Content) < pageSize || page+1 >= result.Page.TotalPages {
+			break
+		}
+	}
+	return properties, nil
+}
+
+// addSharedPolicyProperties sets names of the properties using the shared policy on each network as associated
+// properties of its activation on the network, sorted and without duplicates of several property versions. Properties
+// on networks on which the policy is not active are ignored.
+func addSharedPolicyProperties(ctx context.Context, policyName string, activations map[string]TFPolicyActivationData, properties []shared.PolicyProperty) {
+	names := make(map[string]map[string]bool)
+	for _, property := range properties {
+		n, err := normalizeNetwork(property.Network)
+		if err != nil {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "cloudlets policy",
+				Object:  policyName,
+				Reason:  fmt.Sprintf("property '%s' is skipped: %s", property.Name, err),
+			})
+			continue
+		}
+		key := policyActivationKeys[n]
+		if _, ok := activations[key]; !ok {
+			continue
+		}
+		if names[key] == nil {
+			names[key] = make(map[string]bool)
+		}
+		names[key][property.Name] = true
+	}
+	for key, set := range names {
+		activation := activations[key]
+		activation.Properties = make([]string, 0, len(set))
+		for name := range set {
+			activation.Properties = append(activation.Properties, name)
+		}
+		sort.Strings(activation.Properties)
+		activations[key] = activation
+	}
+}
@@ -0,0 +1,154 @@
+package cloudlets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/providers/cloudlets/shared"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSharedPolicy(t *testing.T) {
+	tests := map[string]struct {
+		init      func(*shared.Mock)
+		expected  int64
+		withError error
+	}{
+		"policy found on 2nd page": {
+			init: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Page: 0, Size: 2}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+					Page:    shared.Page{Size: 2, TotalElements: 3, TotalPages: 2},
+				}, nil).Once()
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Page: 1, Size: 2}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{ID: 3, Name: "test_policy"}},
+					Page:    shared.Page{Number: 1, Size: 2, TotalElements: 3, TotalPages: 2},
+				}, nil).Once()
+			},
+			expected: 3,
+		},
+		"last full page": {
+			init: func(c *shared.Mock) {
+				c.On("ListPolicies", mock.Anything, shared.ListPoliciesRequest{Page: 0, Size: 2}).Return(&shared.ListPoliciesResponse{
+					Content: []shared.Policy{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+					Page:    shared.Page{Size: 2, TotalElements: 2, TotalPages: 1},
+				}, nil).Once()
+			},
+			withError: errPolicyNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools.PageSizes = map[string]int{tools.PageSizeCloudlets: 2}
+			defer func() { tools.PageSizes = nil }()
+			client := new(shared.Mock)
+			test.init(client)
+			policy, err := findSharedPolicyByName(context.Background(), "test_policy", client)
+			if test.withError != nil {
+				assert.ErrorIs(t, err, test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, policy.ID)
+			client.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListSharedPolicyVersions(t *testing.T) {
+	client := new(shared.Mock)
+	client.On("ListPolicyVersions", mock.Anything, shared.ListPolicyVersionsRequest{PolicyID: 11, Size: 1000}).Return(&shared.ListPolicyVersionsResponse{
+		Content: []shared.PolicyVersion{{Version: 1}, {Version: 3}, {Version: 2}},
+		Page:    shared.Page{Size: 1000, TotalElements: 3, TotalPages: 1},
+	}, nil).Once()
+
+	versions, err := listSharedPolicyVersions(context.Background(), 11, client)
+	require.NoError(t, err)
+	assert.Equal(t, []shared.PolicyVersion{{Version: 3}, {Version: 2}, {Version: 1}}, versions)
+	client.AssertExpectations(t)
+}
+
+func TestSharedVersionHistory(t *testing.T) {
+	versions := []shared.PolicyVersion{
+		{Version: 3, Description: "multi\nline  description", CreatedBy: "jsmith", CreatedDate: "2024-01-03T10:00:00Z"},
+		{Version: 2, CreatedDate: "2024-01-02T10:00:00Z"},
+		{Version: 1, CreatedDate: "2024-01-01T10:00:00Z"},
+	}
+	assert.Equal(t, []TFPolicyVersionHistory{
+		{Version: 3, Description: "multi line description", CreatedBy: "jsmith", CreateDate: "2024-01-03T10:00:00Z"},
+		{Version: 2, CreateDate: "2024-01-02T10:00:00Z"},
+	}, sharedVersionHistory(versions, 2))
+}
+
+func TestGetSharedPolicyActivations(t *testing.T) {
+	policy := &shared.Policy{
+		ID: 11,
+		CurrentActivations: shared.CurrentActivations{
+			Production: shared.ActivationInfo{
+				Effective: &shared.Activation{Operation: "DEACTIVATION", PolicyVersion: 1, Status: shared.StatusSuccess},
+			},
+			Staging: shared.ActivationInfo{
+				Effective: &shared.Activation{Operation: shared.OperationActivation, PolicyVersion: 2, Status: shared.StatusSuccess},
+				Latest:    &shared.Activation{Operation: shared.OperationActivation, PolicyVersion: 3, Status: "IN_PROGRESS"},
+			},
+		},
+	}
+	assert.Equal(t, map[string]TFPolicyActivationData{
+		"staging": {PolicyID: 11, Version: 2},
+	}, getSharedPolicyActivations(policy))
+}
+
+func TestListSharedPolicyProperties(t *testing.T) {
+	client := new(shared.Mock)
+	client.On("ListPolicyProperties", mock.Anything, shared.ListPolicyPropertiesRequest{PolicyID: 11, Size: 2}).Return(&shared.ListPolicyPropertiesResponse{
+		Content: []shared.PolicyProperty{{Name: "prp_0"}, {Name: "prp_1"}},
+		Page:    shared.Page{Size: 2, TotalElements: 3, TotalPages: 2},
+	}, nil).Once()
+	client.On("ListPolicyProperties", mock.Anything, shared.ListPolicyPropertiesRequest{PolicyID: 11, Page: 1, Size: 2}).Return(&shared.ListPolicyPropertiesResponse{
+		Content: []shared.PolicyProperty{{Name: "prp_2"}},
+		Page:    shared.Page{Number: 1, Size: 2, TotalElements: 3, TotalPages: 2},
+	}, nil).Once()
+
+	tools.PageSizes = map[string]int{tools.PageSizeCloudlets: 2}
+	defer func() { tools.PageSizes = nil }()
+	properties, err := listSharedPolicyProperties(context.Background(), 11, client)
+	require.NoError(t, err)
+	assert.Equal(t, []shared.PolicyProperty{{Name: "prp_0"}, {Name: "prp_1"}, {Name: "prp_2"}}, properties)
+	client.AssertExpectations(t)
+}
+
+func TestAddSharedPolicyProperties(t *testing.T) {
+	activations := map[string]TFPolicyActivationData{
+		"staging": {PolicyID: 11, Version: 2},
+		"prod":    {PolicyID: 11, Version: 1},
+	}
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+	addSharedPolicyProperties(ctx, "test_policy", activations, []shared.PolicyProperty{
+		{Name: "prp_1", Network: "STAGING", Version: 1},
+		{Name: "prp_0", Network: "staging", Version: 4},
+		{Name: "prp_1", Network: "STAGING", Version: 2},
+		{Name: "prp_2", Network: "PRODUCTION", Version: 1},
+		{Name: "prp_3", Network: "UNKNOWN", Version: 1},
+	})
+	assert.Equal(t, map[string]TFPolicyActivationData{
+		"staging": {PolicyID: 11, Version: 2, Properties: []string{"prp_0", "prp_1"}},
+		"prod":    {PolicyID: 11, Version: 1, Properties: []string{"prp_2"}},
+	}, activations)
+}
+
+func TestSharedPolicyActivations(t *testing.T) {
+	data := TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{
+		"prod":    {PolicyID: 11, Version: 1},
+		"staging": {PolicyID: 11, Version: 2, Properties: []string{"prp_0"}},
+	}}
+	assert.Equal(t, []TFSharedPolicyActivationData{
+		{TFPolicyActivationData: TFPolicyActivationData{PolicyID: 11, Version: 2, Properties: []string{"prp_0"}}, Network: "staging"},
+		{TFPolicyActivationData: TFPolicyActivationData{PolicyID: 11, Version: 1}, Network: "production"},
+	}, data.SharedPolicyActivations())
+	assert.Empty(t, TFPolicyData{}.SharedPolicyActivations())
+}
@@ -0,0 +1,50 @@
+package cloudlets
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+)
+
+// matchRulesJSONContent returns match rules as indented JSON accepted by match_rules of the policy resource, without
+// IDs set by the API; empty content is returned for no match rules, so that match-rules.json is not written
+func matchRulesJSONContent(rules cloudlets.MatchRules) (string, error) {
+	if len(rules) == 0 {
+		return "", nil
+	}
+	objects, err := matchRuleObjects(rules)
+	if err != nil {
+		return "", err
+	}
+	return indentedJSON(objects)
+}
+
+// matchRuleObjects returns match rules as JSON objects without IDs set by the API
+func matchRuleObjects(rules cloudlets.MatchRules) ([]map[string]interface{}, error) {
+	content, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(content, &objects); err != nil {
+		return nil, err
+	}
+	for _, object := range objects {
+		delete(object, "id")
+	}
+	return objects, nil
+}
+
+// indentedJSON returns value encoded as indented JSON followed by a line break
+func indentedJSON(value interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// redirect URLs with query strings are kept readable
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,81 @@
+package cloudlets
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchRulesJSONContent(t *testing.T) {
+	tests := map[string]struct {
+		rules    cloudlets.MatchRules
+		expected string
+	}{
+		"no match rules": {},
+		"IDs are omitted and URLs are not escaped": {
+			rules: cloudlets.MatchRules{
+				&cloudlets.MatchRuleER{
+					Name:        "r1",
+					Type:        cloudlets.MatchRuleTypeER,
+					ID:          123,
+					StatusCode:  301,
+					RedirectURL: "/a?b=1&c=<2>",
+				},
+			},
+			expected: `[
+  {
+    "name": "r1",
+    "redirectURL": "/a?b=1&c=<2>",
+    "statusCode": 301,
+    "type": "erMatchRule",
+    "useIncomingQueryString": false,
+    "useIncomingSchemeAndHost": false
+  }
+]
+`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			content, err := matchRulesJSONContent(test.rules)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, content)
+		})
+	}
+}
+
+func TestMatchRulesJSONContentRoundTrip(t *testing.T) {
+	rules := cloudlets.MatchRules{
+		&cloudlets.MatchRuleFR{
+			Name: "r1",
+			Type: cloudlets.MatchRuleTypeFR,
+			Matches: []cloudlets.MatchCriteriaFR{
+				{
+					MatchType:     "header",
+					MatchOperator: "equals",
+					ObjectMatchValue: &cloudlets.ObjectMatchValueSimple{
+						Type:  "simple",
+						Value: []string{"a\"b"},
+					},
+				},
+			},
+			ForwardSettings: cloudlets.ForwardSettingsFR{PathAndQS: "/path?x=1"},
+		},
+		&cloudlets.MatchRuleALB{
+			Name:            "r2",
+			Type:            cloudlets.MatchRuleTypeALB,
+			MatchesAlways:   true,
+			ForwardSettings: cloudlets.ForwardSettingsALB{OriginID: "origin_1"},
+		},
+	}
+	content, err := matchRulesJSONContent(rules)
+	require.NoError(t, err)
+
+	// match rules written into match-rules.json are read back by the provider
+	var parsed cloudlets.MatchRules
+	require.NoError(t, json.Unmarshal([]byte(content), &parsed))
+	assert.Equal(t, rules, parsed)
+}
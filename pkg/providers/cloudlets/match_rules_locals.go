@@ -0,0 +1,142 @@
+package cloudlets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+var (
+	// attributeOrder is the order of attributes in match rules locals, other attributes follow in alphabetical order
+	attributeOrder = []string{"name", "start", "end", "matches", "match_type", "match_value", "match_operator", "type", "value"}
+
+	// skippedAttributes are attributes of match rules set by the API or not exported by match rules templates
+	skippedAttributes = map[string]bool{"id": true, "type": true, "use_incoming_scheme_and_host": true}
+)
+
+// matchRulesLocals returns match rules as HCL objects with snake case attributes, consumed by the generic match rules
+// module. Attributes with zero values are omitted, as the module uses them as defaults, so that policies with many
+// similar rules stay short.
+func matchRulesLocals(rules cloudlets.MatchRules) ([]string, error) {
+	locals := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		content, err := json.Marshal(rule)
+		if err != nil {
+			return nil, err
+		}
+		var attributes map[string]interface{}
+		if err := json.Unmarshal(content, &attributes); err != nil {
+			return nil, err
+		}
+		for name := range attributes {
+			if skippedAttributes[snakeCase(name)] {
+				delete(attributes, name)
+			}
+		}
+		locals = append(locals, hclValue(attributes, 0))
+	}
+	return locals, nil
+}
+
+// hclValue returns value decoded from JSON as HCL expression, match rules and lists of objects within them, such as
+// matches, are written on multiple lines
+func hclValue(value interface{}, depth int) string {
+	switch v := value.(type) {
+	case string:
+		return `"` + tools.EscapeQuotedStringLit(v) + `"`
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		multiline := depth == 1 && len(v) > 0
+		for _, item := range v {
+			if _, ok := item.(map[string]interface{}); !ok {
+				multiline = false
+			}
+			items = append(items, hclValue(item, depth+1))
+		}
+		if multiline {
+			return "[\n" + strings.Join(items, ",\n") + ",\n]"
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		attributes := make(map[string]string, len(v))
+		for name, item := range v {
+			if isZero(item) {
+				continue
+			}
+			name = snakeCase(name)
+			names = append(names, name)
+			attributes[name] = hclValue(item, depth+1)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if oi, oj := attributeIndex(names[i]), attributeIndex(names[j]); oi != oj {
+				return oi < oj
+			}
+			return names[i] < names[j]
+		})
+		items := make([]string, 0, len(names))
+		for _, name := range names {
+			items = append(items, fmt.Sprintf("%s = %s", name, attributes[name]))
+		}
+		if depth == 0 {
+			return "{\n" + strings.Join(items, "\n") + "\n}"
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	}
+	return "null"
+}
+
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		for _, item := range v {
+			if !isZero(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func attributeIndex(name string) int {
+	for i, n := range attributeOrder {
+		if n == name {
+			return i
+		}
+	}
+	return len(attributeOrder)
+}
+
+// snakeCase converts JSON names of match rules attributes, e.g. matchURL or checkIPs, to names of terraform attributes
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package cloudlets
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchRulesLocals(t *testing.T) {
+	tests := map[string]struct {
+		rules    cloudlets.MatchRules
+		expected []string
+	}{
+		"zero values and API attributes are omitted": {
+			rules: cloudlets.MatchRules{
+				cloudlets.MatchRuleER{
+					Name:                     "r1",
+					Type:                     "erMatchRule",
+					ID:                       123,
+					StatusCode:               301,
+					RedirectURL:              "/${path}",
+					UseIncomingSchemeAndHost: true,
+				},
+			},
+			expected: []string{"{\nname = \"r1\"\nredirect_url = \"/$${path}\"\nstatus_code = 301\n}"},
+		},
+		"matches with object match values": {
+			rules: cloudlets.MatchRules{
+				cloudlets.MatchRuleFR{
+					Name: "r1",
+					Matches: []cloudlets.MatchCriteriaFR{
+						{
+							MatchType:     "header",
+							MatchOperator: "equals",
+							CheckIPs:      "CONNECTING_IP",
+							ObjectMatchValue: cloudlets.ObjectMatchValueSimple{
+								Type:  "simple",
+								Value: []string{"a\"b"},
+							},
+						},
+					},
+					ForwardSettings: cloudlets.ForwardSettingsFR{
+						PathAndQS: "/path",
+					},
+				},
+				cloudlets.MatchRuleFR{
+					Name:     "r2",
+					Disabled: true,
+				},
+			},
+			expected: []string{
+				"{\nname = \"r1\"\nmatches = [\n{ match_type = \"header\", match_operator = \"equals\", check_ips = \"CONNECTING_IP\", object_match_value = { type = \"simple\", value = [\"a\\\"b\"] } },\n]\nforward_settings = { path_and_qs = \"/path\" }\n}",
+				"{\nname = \"r2\"\ndisabled = true\n}",
+			},
+		},
+		"no rules": {
+			rules:    cloudlets.MatchRules{},
+			expected: []string{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			locals, err := matchRulesLocals(test.rules)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, locals)
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"name":            "name",
+		"matchURL":        "match_url",
+		"checkIPs":        "check_ips",
+		"pathAndQS":       "path_and_qs",
+		"useRelativeUrl":  "use_relative_url",
+		"originId":        "origin_id",
+		"forwardSettings": "forward_settings",
+	} {
+		assert.Equal(t, expected, snakeCase(name))
+	}
+}
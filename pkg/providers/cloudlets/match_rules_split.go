@@ -0,0 +1,73 @@
+package cloudlets
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/secrets"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// matchRulesDir is the directory of the policy into which match rules are written one file per rule
+const matchRulesDir = "rules"
+
+// matchRuleFiles returns slash separated paths of files of match rules relative to the policy, one per rule in the
+// order of the policy. Files are named after the rules in snake case, so that adding or removing a rule does not
+// rename files of other rules; unnamed rules are named rule and repeated names get a numeric suffix, e.g. rule_2.
+func matchRuleFiles(rules cloudlets.MatchRules) ([]string, error) {
+	objects, err := matchRuleObjects(rules)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(objects))
+	used := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		name, _ := object["name"].(string)
+		base := strings.Trim(invalidResourceName.ReplaceAllString(snakeCase(name), "_"), "_")
+		if base == "" {
+			base = "rule"
+		}
+		fileName := base
+		for i := 2; used[fileName]; i++ {
+			fileName = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[fileName] = true
+		files = append(files, matchRulesDir+"/"+fileName+".json")
+	}
+	return files, nil
+}
+
+// writeMatchRuleFiles writes every match rule as a JSON object into its file, files are slash separated paths
+// relative to the policy directory dir as returned by matchRuleFiles
+func writeMatchRuleFiles(ctx context.Context, dir string, rules cloudlets.MatchRules, files []string) error {
+	objects, err := matchRuleObjects(rules)
+	if err != nil {
+		return err
+	}
+	if len(objects) != len(files) {
+		return fmt.Errorf("%d match rules do not match %d files", len(objects), len(files))
+	}
+	sink := templates.GetSink(ctx)
+	for i, object := range objects {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		content, err := indentedJSON(object)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(files[i]))
+		filtered, err := secrets.Filter(sink, path, []byte(content))
+		if err != nil {
+			return err
+		}
+		if err = sink.WriteFile(path, tools.ApplyLineEndings(filtered)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
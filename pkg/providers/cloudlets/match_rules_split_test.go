@@ -0,0 +1,77 @@
+package cloudlets
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchRuleFiles(t *testing.T) {
+	tests := map[string]struct {
+		rules    cloudlets.MatchRules
+		expected []string
+	}{
+		"no match rules": {
+			expected: []string{},
+		},
+		"files named after rules in the order of the policy": {
+			rules: cloudlets.MatchRules{
+				&cloudlets.MatchRuleER{Name: "Redirect /old"},
+				&cloudlets.MatchRuleER{Name: "2nd rule"},
+				&cloudlets.MatchRuleER{Name: "matchURL"},
+			},
+			expected: []string{"rules/redirect_old.json", "rules/2nd_rule.json", "rules/match_url.json"},
+		},
+		"unnamed and repeated rules": {
+			rules: cloudlets.MatchRules{
+				&cloudlets.MatchRuleER{Name: "r1"},
+				&cloudlets.MatchRuleER{},
+				&cloudlets.MatchRuleER{Name: "R1"},
+				&cloudlets.MatchRuleER{Name: "---"},
+				&cloudlets.MatchRuleER{Name: "r1"},
+			},
+			expected: []string{"rules/r1.json", "rules/rule.json", "rules/r1_2.json", "rules/rule_2.json", "rules/r1_3.json"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			files, err := matchRuleFiles(test.rules)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, files)
+		})
+	}
+}
+
+func TestWriteMatchRuleFiles(t *testing.T) {
+	rules := cloudlets.MatchRules{
+		&cloudlets.MatchRuleER{Name: "r1", Type: cloudlets.MatchRuleTypeER, ID: 1234, StatusCode: 301, RedirectURL: "/a?b=1&c=2"},
+	}
+
+	t.Run("rule per file", func(t *testing.T) {
+		sink := templates.NewMemorySink()
+		ctx := templates.WithSink(context.Background(), sink)
+		require.NoError(t, writeMatchRuleFiles(ctx, "out", rules, []string{"rules/r1.json"}))
+
+		assert.Equal(t, map[string][]byte{
+			filepath.Join("out", "rules", "r1.json"): []byte(`{
+  "name": "r1",
+  "redirectURL": "/a?b=1&c=2",
+  "statusCode": 301,
+  "type": "erMatchRule",
+  "useIncomingQueryString": false,
+  "useIncomingSchemeAndHost": false
+}
+`),
+		}, sink.Files())
+	})
+
+	t.Run("files do not match rules", func(t *testing.T) {
+		ctx := templates.WithSink(context.Background(), templates.NewMemorySink())
+		assert.EqualError(t, writeMatchRuleFiles(ctx, "out", rules, nil), "1 match rules do not match 0 files")
+	})
+}
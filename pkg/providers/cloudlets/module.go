@@ -0,0 +1,43 @@
+package cloudlets
+
+// ModuleName returns the name of the module block instantiating the policy exported as a module
+func (d TFPolicyData) ModuleName() string {
+	return d.PolicyResourceName()
+}
+
+// ResourceAddressPrefix returns the prefix of addresses of resources in the import script and import blocks, which
+// is the address of the module when the policy is exported as a module
+func (d TFPolicyData) ResourceAddressPrefix() string {
+	if !d.AsModule {
+		return ""
+	}
+	return "module." + d.ModuleName() + "."
+}
+
+// ImportNetwork returns the network on which activations are imported, which is the default value of env variable
+func (d TFPolicyData) ImportNetwork() string {
+	return string(d.importNetwork())
+}
+
+// ModuleActivation returns the activation of the policy exported as a module on the import network, nil when the
+// policy is not active on the network
+func (d TFPolicyData) ModuleActivation() *TFPolicyActivationData {
+	activation, ok := d.PolicyActivations[policyActivationKeys[d.importNetwork()]]
+	if !ok {
+		return nil
+	}
+	return &activation
+}
+
+// importNetwork returns staging network, unless the policy exported as a module is active on production only, in
+// which case the module is instantiated for production
+func (d TFPolicyData) importNetwork() network {
+	if d.AsModule {
+		_, staging := d.PolicyActivations[policyActivationKeys[networkStaging]]
+		_, prod := d.PolicyActivations[policyActivationKeys[networkProduction]]
+		if prod && !staging {
+			return networkProduction
+		}
+	}
+	return networkStaging
+}
@@ -0,0 +1,54 @@
+package cloudlets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModuleImportNetwork(t *testing.T) {
+	tests := map[string]struct {
+		data               TFPolicyData
+		expectedNetwork    string
+		expectedActivation *TFPolicyActivationData
+		expectedPrefix     string
+	}{
+		"policy not exported as module": {
+			data: TFPolicyData{PolicyActivations: map[string]TFPolicyActivationData{
+				"prod": {PolicyID: 2, Version: 1},
+			}},
+			expectedNetwork: "staging",
+		},
+		"module of policy active on both networks": {
+			data: TFPolicyData{AsModule: true, PolicyActivations: map[string]TFPolicyActivationData{
+				"prod":    {PolicyID: 2, Version: 1, Properties: []string{"prp_0"}},
+				"staging": {PolicyID: 2, Version: 2, Properties: []string{"prp_1"}},
+			}},
+			expectedNetwork:    "staging",
+			expectedActivation: &TFPolicyActivationData{PolicyID: 2, Version: 2, Properties: []string{"prp_1"}},
+			expectedPrefix:     "module.policy.",
+		},
+		"module of policy active on production only": {
+			data: TFPolicyData{AsModule: true, PolicyActivations: map[string]TFPolicyActivationData{
+				"prod": {PolicyID: 2, Version: 1, Properties: []string{"prp_0"}},
+			}},
+			expectedNetwork:    "production",
+			expectedActivation: &TFPolicyActivationData{PolicyID: 2, Version: 1, Properties: []string{"prp_0"}},
+			expectedPrefix:     "module.policy.",
+		},
+		"module of inactive policy named after the policy": {
+			data:            TFPolicyData{Name: "My Policy", AsModule: true, ResourcePrefix: "edge_", ResourceNaming: ResourceNamingSnakeCase},
+			expectedNetwork: "staging",
+			expectedPrefix:  "module.edge_my_policy.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedNetwork, test.data.ImportNetwork())
+			assert.Equal(t, test.expectedPrefix, test.data.ResourceAddressPrefix())
+			if test.data.AsModule {
+				assert.Equal(t, test.expectedActivation, test.data.ModuleActivation())
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package cloudlets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+)
+
+// network is an activation network accepted by activation resources of the provider
+type network string
+
+const (
+	networkStaging    network = "staging"
+	networkProduction network = "production"
+)
+
+var (
+	// ErrUnsupportedNetwork is returned when an activation is reported on a network not accepted by the provider
+	ErrUnsupportedNetwork = exitcode.New(exitcode.Unsupported, "unsupported activation network")
+
+	// networkLabels maps lower case labels of activation networks returned by the API to networks of the provider
+	networkLabels = map[string]network{
+		"staging":    networkStaging,
+		"prod":       networkProduction,
+		"production": networkProduction,
+	}
+
+	// policyActivationKeys are keys of policy activations per network used by templates
+	policyActivationKeys = map[network]string{
+		networkStaging:    "staging",
+		networkProduction: "prod",
+	}
+
+	// loadBalancerNetworks are networks of load balancer activations as expected by the provider
+	loadBalancerNetworks = map[network]cloudlets.LoadBalancerActivationNetwork{
+		networkStaging:    cloudlets.LoadBalancerActivationNetworkStaging,
+		networkProduction: cloudlets.LoadBalancerActivationNetworkProduction,
+	}
+)
+
+// normalizeNetwork returns the provider network of a label returned by the API, such as prod, production or PRODUCTION
+func normalizeNetwork(label string) (network, error) {
+	n, ok := networkLabels[strings.ToLower(strings.TrimSpace(label))]
+	if !ok {
+		return "", fmt.Errorf("%w: '%s'", ErrUnsupportedNetwork, label)
+	}
+	return n, nil
+}
@@ -0,0 +1,38 @@
+package cloudlets
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeNetwork(t *testing.T) {
+	tests := map[string]struct {
+		label     string
+		expected  network
+		withError error
+	}{
+		"staging":            {label: "staging", expected: networkStaging},
+		"upper case staging": {label: "STAGING", expected: networkStaging},
+		"prod":               {label: "prod", expected: networkProduction},
+		"production":         {label: "production", expected: networkProduction},
+		"upper case":         {label: "PRODUCTION", expected: networkProduction},
+		"mixed case":         {label: " Prod ", expected: networkProduction},
+		"empty":              {label: "", withError: ErrUnsupportedNetwork},
+		"unknown":            {label: "qa", withError: ErrUnsupportedNetwork},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n, err := normalizeNetwork(test.label)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, n)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cloudlets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Strategies accepted by resource-naming flag, deriving names of the policy resources
+const (
+	// ResourceNamingStatic keeps the same names for every policy, e.g. akamai_cloudlets_policy.policy
+	ResourceNamingStatic = "static"
+	// ResourceNamingSnakeCase names the resources after the policy in snake case
+	ResourceNamingSnakeCase = "snake_case"
+	// ResourceNamingHash names the resources after the policy in snake case followed by a short hash of the policy
+	// name, so that policies whose names differ only in characters replaced in snake case do not collide
+	ResourceNamingHash = "hash"
+)
+
+var (
+	resourcePrefix      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	invalidResourceName = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// ValidateResourceNaming checks that naming is one of the supported strategies, empty value meaning the static names,
+// and that prefix can start a terraform identifier
+func ValidateResourceNaming(naming, prefix string) error {
+	switch naming {
+	case "", ResourceNamingStatic, ResourceNamingSnakeCase, ResourceNamingHash:
+	default:
+		return fmt.Errorf("resource naming '%s' is not supported, expected one of: %s, %s, %s", naming, ResourceNamingStatic, ResourceNamingSnakeCase, ResourceNamingHash)
+	}
+	if prefix != "" && !resourcePrefix.MatchString(prefix) {
+		return fmt.Errorf("prefix '%s' is not a valid start of resource name", prefix)
+	}
+	return nil
+}
+
+// PolicyResourceName returns the name of the policy resource following the naming strategy, prefixed with the resource
+// prefix, e.g. policy or my_prefix_test_policy
+func (d TFPolicyData) PolicyResourceName() string {
+	return d.ResourcePrefix + d.policyBaseName("policy")
+}
+
+// ActivationResourceName returns the name of the policy activation resource, e.g. policy_activation
+func (d TFPolicyData) ActivationResourceName() string {
+	return d.ResourcePrefix + d.policyBaseName("policy") + "_activation"
+}
+
+// MatchRulesName returns the name of match rules data source, local value and module of the policy, e.g. match_rules;
+// data sources of fully expanded match rules are suffixed with the cloudlet code
+func (d TFPolicyData) MatchRulesName() string {
+	base := d.policyBaseName("")
+	if base == "" {
+		return d.ResourcePrefix + "match_rules"
+	}
+	return d.ResourcePrefix + base + "_match_rules"
+}
+
+// LoadBalancerResourceName returns the name of the resource of the load balancer with the given origin ID
+func (d TFPolicyData) LoadBalancerResourceName(originID string) string {
+	return d.ResourcePrefix + "load_balancer_" + originID
+}
+
+// LoadBalancerActivationResourceName returns the name of the activation resource of the load balancer with the given
+// origin ID
+func (d TFPolicyData) LoadBalancerActivationResourceName(originID string) string {
+	return d.ResourcePrefix + "load_balancer_activation_" + originID
+}
+
+// policyBaseName returns the name of the policy following the naming strategy, static names use staticName
+func (d TFPolicyData) policyBaseName(staticName string) string {
+	switch d.ResourceNaming {
+	case ResourceNamingSnakeCase:
+		return resourceName(d.Name)
+	case ResourceNamingHash:
+		sum := sha256.Sum256([]byte(d.Name))
+		return resourceName(d.Name) + "_" + hex.EncodeToString(sum[:])[:8]
+	}
+	return staticName
+}
+
+// resourceName converts name of the policy to snake case name of terraform resource, e.g. MyPolicy-v2.1 becomes
+// my_policy_v2_1; names starting with a digit are prefixed with an underscore
+func resourceName(name string) string {
+	result := strings.Trim(invalidResourceName.ReplaceAllString(snakeCase(name), "_"), "_")
+	if result == "" {
+		return "policy"
+	}
+	if result[0] >= '0' && result[0] <= '9' {
+		return "_" + result
+	}
+	return result
+}
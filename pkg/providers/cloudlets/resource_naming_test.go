@@ -0,0 +1,92 @@
+package cloudlets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateResourceNaming(t *testing.T) {
+	tests := map[string]struct {
+		naming    string
+		prefix    string
+		withError bool
+	}{
+		"default naming":          {},
+		"static naming":           {naming: ResourceNamingStatic},
+		"snake case with prefix":  {naming: ResourceNamingSnakeCase, prefix: "edge_"},
+		"hash":                    {naming: ResourceNamingHash},
+		"unsupported naming":      {naming: "camel", withError: true},
+		"prefix starting a digit": {prefix: "1_", withError: true},
+		"prefix with dot":         {prefix: "edge.", withError: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateResourceNaming(test.naming, test.prefix)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestResourceNames(t *testing.T) {
+	tests := map[string]struct {
+		data               TFPolicyData
+		expectedPolicy     string
+		expectedActivation string
+		expectedMatchRules string
+	}{
+		"static names": {
+			data:               TFPolicyData{Name: "test_policy"},
+			expectedPolicy:     "policy",
+			expectedActivation: "policy_activation",
+			expectedMatchRules: "match_rules",
+		},
+		"static names with prefix": {
+			data:               TFPolicyData{Name: "test_policy", ResourcePrefix: "edge_", ResourceNaming: ResourceNamingStatic},
+			expectedPolicy:     "edge_policy",
+			expectedActivation: "edge_policy_activation",
+			expectedMatchRules: "edge_match_rules",
+		},
+		"snake case": {
+			data:               TFPolicyData{Name: "MyPolicy-v2.1", ResourceNaming: ResourceNamingSnakeCase},
+			expectedPolicy:     "my_policy_v2_1",
+			expectedActivation: "my_policy_v2_1_activation",
+			expectedMatchRules: "my_policy_v2_1_match_rules",
+		},
+		"snake case starting with a digit": {
+			data:               TFPolicyData{Name: "2021_redirects", ResourceNaming: ResourceNamingSnakeCase},
+			expectedPolicy:     "_2021_redirects",
+			expectedActivation: "_2021_redirects_activation",
+			expectedMatchRules: "_2021_redirects_match_rules",
+		},
+		"hash with prefix": {
+			data:               TFPolicyData{Name: "test_policy", ResourcePrefix: "edge_", ResourceNaming: ResourceNamingHash},
+			expectedPolicy:     "edge_test_policy_d4fcbcc6",
+			expectedActivation: "edge_test_policy_d4fcbcc6_activation",
+			expectedMatchRules: "edge_test_policy_d4fcbcc6_match_rules",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedPolicy, test.data.PolicyResourceName())
+			assert.Equal(t, test.expectedActivation, test.data.ActivationResourceName())
+			assert.Equal(t, test.expectedMatchRules, test.data.MatchRulesName())
+		})
+	}
+}
+
+func TestResourceNamesHashDistinguishesPolicies(t *testing.T) {
+	first := TFPolicyData{Name: "test-policy", ResourceNaming: ResourceNamingHash}
+	second := TFPolicyData{Name: "test_policy", ResourceNaming: ResourceNamingHash}
+	assert.NotEqual(t, first.PolicyResourceName(), second.PolicyResourceName())
+}
+
+func TestLoadBalancerResourceNames(t *testing.T) {
+	data := TFPolicyData{ResourcePrefix: "edge_", ResourceNaming: ResourceNamingHash}
+	assert.Equal(t, "edge_load_balancer_test_origin", data.LoadBalancerResourceName("test_origin"))
+	assert.Equal(t, "edge_load_balancer_activation_test_origin", data.LoadBalancerActivationResourceName("test_origin"))
+}
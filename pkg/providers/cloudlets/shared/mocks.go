@@ -0,0 +1,55 @@
+//revive:disable:exported
+
+package shared
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type Mock struct {
+	mock.Mock
+}
+
+var _ Policies = &Mock{}
+
+func (m *Mock) ListPolicies(ctx context.Context, req ListPoliciesRequest) (*ListPoliciesResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ListPoliciesResponse), args.Error(1)
+}
+
+func (m *Mock) GetPolicy(ctx context.Context, req GetPolicyRequest) (*Policy, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Policy), args.Error(1)
+}
+
+func (m *Mock) ListPolicyVersions(ctx context.Context, req ListPolicyVersionsRequest) (*ListPolicyVersionsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ListPolicyVersionsResponse), args.Error(1)
+}
+
+func (m *Mock) GetPolicyVersion(ctx context.Context, req GetPolicyVersionRequest) (*PolicyVersion, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PolicyVersion), args.Error(1)
+}
+
+func (m *Mock) ListPolicyProperties(ctx context.Context, req ListPolicyPropertiesRequest) (*ListPolicyPropertiesResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ListPolicyPropertiesResponse), args.Error(1)
+}
@@ -0,0 +1,297 @@
+// Package shared contains a client of Cloudlets API v3, which manages shared policies
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+)
+
+type (
+	// Policies is a Cloudlets API v3 interface, the API is not covered by edgegrid-golang v3, so only calls needed by
+	// the export are implemented here
+	Policies interface {
+		// ListPolicies lists shared policies available to the API client
+		//
+		// See: https://techdocs.akamai.com/cloudlets/reference/get-policies
+		ListPolicies(context.Context, ListPoliciesRequest) (*ListPoliciesResponse, error)
+		// GetPolicy fetches a shared policy by its ID
+		//
+		// See: https://techdocs.akamai.com/cloudlets/reference/get-policy
+		GetPolicy(context.Context, GetPolicyRequest) (*Policy, error)
+		// ListPolicyVersions lists versions of a shared policy, without match rules
+		//
+		// See: https://techdocs.akamai.com/cloudlets/reference/get-policy-versions
+		ListPolicyVersions(context.Context, ListPolicyVersionsRequest) (*ListPolicyVersionsResponse, error)
+		// GetPolicyVersion fetches a version of a shared policy together with its match rules
+		//
+		// See: https://techdocs.akamai.com/cloudlets/reference/get-policy-version
+		GetPolicyVersion(context.Context, GetPolicyVersionRequest) (*PolicyVersion, error)
+		// ListPolicyProperties lists properties which use a shared policy on each network
+		//
+		// See: https://techdocs.akamai.com/cloudlets/reference/get-policy-properties
+		ListPolicyProperties(context.Context, ListPolicyPropertiesRequest) (*ListPolicyPropertiesResponse, error)
+	}
+
+	// ListPoliciesRequest contains query parameters used to list shared policies, pages are numbered from 0
+	ListPoliciesRequest struct {
+		Page int
+		Size int
+	}
+
+	// GetPolicyRequest contains path parameters used to fetch a shared policy
+	GetPolicyRequest struct {
+		PolicyID int64
+	}
+
+	// ListPolicyVersionsRequest contains path and query parameters used to list versions of a shared policy
+	ListPolicyVersionsRequest struct {
+		PolicyID int64
+		Page     int
+		Size     int
+	}
+
+	// GetPolicyVersionRequest contains path parameters used to fetch a version of a shared policy
+	GetPolicyVersionRequest struct {
+		PolicyID int64
+		Version  int64
+	}
+
+	// ListPolicyPropertiesRequest contains path and query parameters used to list properties of a shared policy
+	ListPolicyPropertiesRequest struct {
+		PolicyID int64
+		Page     int
+		Size     int
+	}
+
+	// ListPoliciesResponse represents a page of shared policies
+	ListPoliciesResponse struct {
+		Content []Policy `json:"content"`
+		Page    Page     `json:"page"`
+	}
+
+	// ListPolicyVersionsResponse represents a page of versions of a shared policy
+	ListPolicyVersionsResponse struct {
+		Content []PolicyVersion `json:"content"`
+		Page    Page            `json:"page"`
+	}
+
+	// ListPolicyPropertiesResponse represents a page of properties which use a shared policy
+	ListPolicyPropertiesResponse struct {
+		Content []PolicyProperty `json:"content"`
+		Page    Page             `json:"page"`
+	}
+
+	// Page describes a page of a paginated list
+	Page struct {
+		Number        int `json:"number"`
+		Size          int `json:"size"`
+		TotalElements int `json:"totalElements"`
+		TotalPages    int `json:"totalPages"`
+	}
+
+	// Policy represents a shared policy
+	Policy struct {
+		ID                 int64              `json:"id"`
+		Name               string             `json:"name"`
+		CloudletType       string             `json:"cloudletType"`
+		Description        string             `json:"description"`
+		GroupID            int64              `json:"groupId"`
+		PolicyType         string             `json:"policyType"`
+		CurrentActivations CurrentActivations `json:"currentActivations"`
+	}
+
+	// CurrentActivations are the current activations of a shared policy on each network
+	CurrentActivations struct {
+		Production ActivationInfo `json:"production"`
+		Staging    ActivationInfo `json:"staging"`
+	}
+
+	// ActivationInfo holds the effective activation of a shared policy on a network, which is in effect on the
+	// network, and the latest one, which may be still pending; both are nil if there is none
+	ActivationInfo struct {
+		Effective *Activation `json:"effective"`
+		Latest    *Activation `json:"latest"`
+	}
+
+	// Activation represents an activation or deactivation of a version of a shared policy
+	Activation struct {
+		ID            int64  `json:"id"`
+		Network       string `json:"network"`
+		Operation     string `json:"operation"`
+		PolicyID      int64  `json:"policyId"`
+		PolicyVersion int64  `json:"policyVersion"`
+		Status        string `json:"status"`
+	}
+
+	// PolicyVersion represents a version of a shared policy, match rules are returned only when fetching the version
+	PolicyVersion struct {
+		ID          int64                `json:"id"`
+		PolicyID    int64                `json:"policyId"`
+		Version     int64                `json:"version"`
+		Description string               `json:"description"`
+		Immutable   bool                 `json:"immutable"`
+		CreatedBy   string               `json:"createdBy"`
+		CreatedDate string               `json:"createdDate"`
+		MatchRules  cloudlets.MatchRules `json:"matchRules,omitempty"`
+	}
+
+	// PolicyProperty represents a version of a property which uses a shared policy on the network
+	PolicyProperty struct {
+		ID      int64  `json:"id"`
+		Name    string `json:"name"`
+		GroupID int64  `json:"groupId"`
+		Network string `json:"network"`
+		Version int64  `json:"version"`
+	}
+
+	// Error is a Cloudlets API v3 error
+	Error struct {
+		Type       string `json:"type,omitempty"`
+		Title      string `json:"title,omitempty"`
+		Detail     string `json:"detail,omitempty"`
+		Instance   string `json:"instance,omitempty"`
+		StatusCode int    `json:"status,omitempty"`
+	}
+
+	policies struct {
+		session.Session
+	}
+)
+
+const (
+	// OperationActivation is the operation of an activation, as opposed to a deactivation
+	OperationActivation = "ACTIVATION"
+	// StatusSuccess is the status of a finished activation
+	StatusSuccess = "SUCCESS"
+)
+
+var (
+	// ErrListPolicies is returned when ListPolicies fails
+	ErrListPolicies = errors.New("list shared policies")
+	// ErrGetPolicy is returned when GetPolicy fails
+	ErrGetPolicy = errors.New("get shared policy")
+	// ErrListPolicyVersions is returned when ListPolicyVersions fails
+	ErrListPolicyVersions = errors.New("list shared policy versions")
+	// ErrGetPolicyVersion is returned when GetPolicyVersion fails
+	ErrGetPolicyVersion = errors.New("get shared policy version")
+	// ErrListPolicyProperties is returned when ListPolicyProperties fails
+	ErrListPolicyProperties = errors.New("list shared policy properties")
+)
+
+// Client returns new Cloudlets API v3 client
+func Client(sess session.Session) Policies {
+	return &policies{Session: sess}
+}
+
+func (p *policies) ListPolicies(ctx context.Context, params ListPoliciesRequest) (*ListPoliciesResponse, error) {
+	uri := "/cloudlets/v3/policies?" + pageQuery(params.Page, params.Size)
+	var result ListPoliciesResponse
+	if err := p.get(ctx, uri, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrListPolicies, err)
+	}
+	return &result, nil
+}
+
+func (p *policies) GetPolicy(ctx context.Context, params GetPolicyRequest) (*Policy, error) {
+	if params.PolicyID <= 0 {
+		return nil, fmt.Errorf("%w: positive policy ID is required", ErrGetPolicy)
+	}
+	var result Policy
+	if err := p.get(ctx, fmt.Sprintf("/cloudlets/v3/policies/%d", params.PolicyID), &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrGetPolicy, err)
+	}
+	return &result, nil
+}
+
+func (p *policies) ListPolicyVersions(ctx context.Context, params ListPolicyVersionsRequest) (*ListPolicyVersionsResponse, error) {
+	if params.PolicyID <= 0 {
+		return nil, fmt.Errorf("%w: positive policy ID is required", ErrListPolicyVersions)
+	}
+	uri := fmt.Sprintf("/cloudlets/v3/policies/%d/versions?%s", params.PolicyID, pageQuery(params.Page, params.Size))
+	var result ListPolicyVersionsResponse
+	if err := p.get(ctx, uri, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrListPolicyVersions, err)
+	}
+	return &result, nil
+}
+
+func (p *policies) GetPolicyVersion(ctx context.Context, params GetPolicyVersionRequest) (*PolicyVersion, error) {
+	if params.PolicyID <= 0 || params.Version <= 0 {
+		return nil, fmt.Errorf("%w: positive policy ID and version are required", ErrGetPolicyVersion)
+	}
+	uri := fmt.Sprintf("/cloudlets/v3/policies/%d/versions/%d", params.PolicyID, params.Version)
+	var result PolicyVersion
+	if err := p.get(ctx, uri, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrGetPolicyVersion, err)
+	}
+	return &result, nil
+}
+
+func (p *policies) ListPolicyProperties(ctx context.Context, params ListPolicyPropertiesRequest) (*ListPolicyPropertiesResponse, error) {
+	if params.PolicyID <= 0 {
+		return nil, fmt.Errorf("%w: positive policy ID is required", ErrListPolicyProperties)
+	}
+	uri := fmt.Sprintf("/cloudlets/v3/policies/%d/properties?%s", params.PolicyID, pageQuery(params.Page, params.Size))
+	var result ListPolicyPropertiesResponse
+	if err := p.get(ctx, uri, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrListPolicyProperties, err)
+	}
+	return &result, nil
+}
+
+// pageQuery returns query parameters of the page, the size is left to the API default when not positive
+func pageQuery(page, size int) string {
+	query := url.Values{"page": []string{strconv.Itoa(page)}}
+	if size > 0 {
+		query.Set("size", strconv.Itoa(size))
+	}
+	return query.Encode()
+}
+
+func (p *policies) get(ctx context.Context, uri string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %s", err)
+	}
+	resp, err := p.Exec(req, out)
+	if err != nil {
+		return fmt.Errorf("request failed: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return parseError(resp)
+	}
+	return nil
+}
+
+// parseError parses an error from the response
+func parseError(r *http.Response) error {
+	result := Error{StatusCode: r.StatusCode}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		result.Title = "Failed to read error body"
+		result.Detail = err.Error()
+		return &result
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		result.Title = string(body)
+	}
+	result.StatusCode = r.StatusCode
+	return &result
+}
+
+func (e *Error) Error() string {
+	msg, err := json.MarshalIndent(e, "", "\t")
+	if err != nil {
+		return fmt.Sprintf("error marshaling API error: %s", err)
+	}
+	return fmt.Sprintf("API error: \n%s", msg)
+}
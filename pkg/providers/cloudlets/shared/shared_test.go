@@ -0,0 +1,102 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoliciesClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		switch r.URL.RequestURI() {
+		case "/cloudlets/v3/policies?page=0&size=100":
+			_, _ = w.Write([]byte(`{"content":[{"id":11,"name":"shared_er","cloudletType":"ER","groupId":123,"policyType":"SHARED",
+				"currentActivations":{"production":{"effective":null,"latest":null},"staging":{"effective":{"id":5,"network":"STAGING","operation":"ACTIVATION","policyId":11,"policyVersion":2,"status":"SUCCESS"},"latest":null}}}],
+				"page":{"number":0,"size":100,"totalElements":1,"totalPages":1}}`))
+		case "/cloudlets/v3/policies/11":
+			_, _ = w.Write([]byte(`{"id":11,"name":"shared_er","cloudletType":"ER","groupId":123,"description":"redirects","policyType":"SHARED"}`))
+		case "/cloudlets/v3/policies/11/versions?page=1":
+			_, _ = w.Write([]byte(`{"content":[{"id":21,"policyId":11,"version":2,"description":"second","immutable":true,"createdBy":"jsmith","createdDate":"2024-01-02T10:00:00Z"}],"page":{"number":1,"size":1,"totalElements":2,"totalPages":2}}`))
+		case "/cloudlets/v3/policies/11/properties?page=0&size=100":
+			_, _ = w.Write([]byte(`{"content":[{"id":31,"name":"example.com","groupId":123,"network":"STAGING","version":4}],"page":{"number":0,"size":100,"totalElements":1,"totalPages":1}}`))
+		case "/cloudlets/v3/policies/11/versions/2":
+			_, _ = w.Write([]byte(`{"id":21,"policyId":11,"version":2,"description":"second","matchRules":[{"type":"erMatchRule","name":"r1","redirectURL":"/a","statusCode":301}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"not-found","title":"Not Found","status":404}`))
+		}
+	}))
+	defer srv.Close()
+
+	transport, err := edgegrid.RedirectTransport(srv.URL, http.DefaultTransport)
+	require.NoError(t, err)
+	sess, err := session.New(session.WithClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+	client := Client(sess)
+	ctx := context.Background()
+
+	list, err := client.ListPolicies(ctx, ListPoliciesRequest{Size: 100})
+	require.NoError(t, err)
+	assert.Equal(t, &ListPoliciesResponse{
+		Content: []Policy{{
+			ID:           11,
+			Name:         "shared_er",
+			CloudletType: "ER",
+			GroupID:      123,
+			PolicyType:   "SHARED",
+			CurrentActivations: CurrentActivations{
+				Staging: ActivationInfo{Effective: &Activation{ID: 5, Network: "STAGING", Operation: OperationActivation, PolicyID: 11, PolicyVersion: 2, Status: StatusSuccess}},
+			},
+		}},
+		Page: Page{Size: 100, TotalElements: 1, TotalPages: 1},
+	}, list)
+
+	policy, err := client.GetPolicy(ctx, GetPolicyRequest{PolicyID: 11})
+	require.NoError(t, err)
+	assert.Equal(t, &Policy{ID: 11, Name: "shared_er", CloudletType: "ER", GroupID: 123, Description: "redirects", PolicyType: "SHARED"}, policy)
+
+	versions, err := client.ListPolicyVersions(ctx, ListPolicyVersionsRequest{PolicyID: 11, Page: 1})
+	require.NoError(t, err)
+	assert.Equal(t, &ListPolicyVersionsResponse{
+		Content: []PolicyVersion{{ID: 21, PolicyID: 11, Version: 2, Description: "second", Immutable: true, CreatedBy: "jsmith", CreatedDate: "2024-01-02T10:00:00Z"}},
+		Page:    Page{Number: 1, Size: 1, TotalElements: 2, TotalPages: 2},
+	}, versions)
+
+	version, err := client.GetPolicyVersion(ctx, GetPolicyVersionRequest{PolicyID: 11, Version: 2})
+	require.NoError(t, err)
+	assert.Equal(t, &PolicyVersion{
+		ID:          21,
+		PolicyID:    11,
+		Version:     2,
+		Description: "second",
+		MatchRules:  cloudlets.MatchRules{&cloudlets.MatchRuleER{Type: "erMatchRule", Name: "r1", RedirectURL: "/a", StatusCode: 301}},
+	}, version)
+
+	properties, err := client.ListPolicyProperties(ctx, ListPolicyPropertiesRequest{PolicyID: 11, Size: 100})
+	require.NoError(t, err)
+	assert.Equal(t, &ListPolicyPropertiesResponse{
+		Content: []PolicyProperty{{ID: 31, Name: "example.com", GroupID: 123, Network: "STAGING", Version: 4}},
+		Page:    Page{Size: 100, TotalElements: 1, TotalPages: 1},
+	}, properties)
+
+	_, err = client.GetPolicyVersion(ctx, GetPolicyVersionRequest{PolicyID: 11, Version: 9})
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr), "expected API error, got: %s", err)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "Not Found", apiErr.Title)
+
+	_, err = client.ListPolicyVersions(ctx, ListPolicyVersionsRequest{})
+	assert.True(t, errors.Is(err, ErrListPolicyVersions), "expected: %s; got: %s", ErrListPolicyVersions, err)
+
+	_, err = client.ListPolicyProperties(ctx, ListPolicyPropertiesRequest{})
+	assert.True(t, errors.Is(err, ErrListPolicyProperties), "expected: %s; got: %s", ErrListPolicyProperties, err)
+}
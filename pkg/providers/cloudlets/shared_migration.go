@@ -0,0 +1,35 @@
+package cloudlets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akamai/cli-terraform/pkg/warnings"
+)
+
+// sharedMigrationCloudlets are cloudlet types of legacy policies which can be migrated to shared policies
+var sharedMigrationCloudlets = map[string]struct{}{
+	"AS": {},
+	"ER": {},
+	"FR": {},
+}
+
+// markSharedMigration sets the legacy policy to be exported with the shape of the shared policy and a migration note,
+// policies which cannot be migrated are reported as warnings
+func markSharedMigration(ctx context.Context, tfPolicyData *TFPolicyData) {
+	reason := ""
+	if tfPolicyData.IsShared {
+		reason = "policy is already shared"
+	} else if _, ok := sharedMigrationCloudlets[tfPolicyData.CloudletCode]; !ok {
+		reason = fmt.Sprintf("policies of cloudlet type %s cannot be migrated", tfPolicyData.CloudletCode)
+	}
+	if reason != "" {
+		warnings.Report(ctx, warnings.Warning{
+			Product: "cloudlets policy",
+			Object:  tfPolicyData.Name,
+			Reason:  fmt.Sprintf("migration to shared policy is skipped: %s", reason),
+		})
+		return
+	}
+	tfPolicyData.MigrateToShared = true
+}
@@ -0,0 +1,38 @@
+package cloudlets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkSharedMigration(t *testing.T) {
+	tests := map[string]struct {
+		data     TFPolicyData
+		expected bool
+	}{
+		"legacy ER policy": {
+			data:     TFPolicyData{Name: "test_policy", CloudletCode: "ER"},
+			expected: true,
+		},
+		"legacy FR policy": {
+			data:     TFPolicyData{Name: "test_policy", CloudletCode: "FR"},
+			expected: true,
+		},
+		"legacy ALB policy": {
+			data: TFPolicyData{Name: "test_policy", CloudletCode: "ALB"},
+		},
+		"shared ER policy": {
+			data: TFPolicyData{Name: "test_policy", CloudletCode: "ER", IsShared: true},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			markSharedMigration(ctx, &test.data)
+			assert.Equal(t, test.expected, test.data.MigrateToShared)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+output "policy_id" {
+  description = "ID of the policy"
+  value       = local.policy_id
+}
+
+output "policy_version" {
+  description = "Exported version of the policy, null when the policy has no versions"
+  value       = local.policy_version
+}
+
+output "policy_name" {
+  description = "Name of the policy"
+  value       = local.policy_name
+}
+
+output "cloudlet_code" {
+  description = "Code of the cloudlet type of the policy"
+  value       = local.cloudlet_code
+}
+
+output "group_id" {
+  description = "ID of the group of the policy"
+  value       = local.group_id
+}
+
+output "match_rule_format" {
+  description = "Format of match rules of the policy"
+  value       = local.match_rule_format
+}
+
+output "match_rules" {
+  description = "Match rules of the exported version of the policy"
+  value       = local.match_rules
+}
+
+output "staging_version" {
+  description = "Version of the policy active on staging network"
+  value       = local.staging_version
+}
+
+output "production_version" {
+  description = "Version of the policy active on production network"
+  value       = local.production_version
+}
@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+data "akamai_cloudlets_policy" "policy" {
+  policy_id = 2
+  version   = 3
+}
+
+locals {
+  policy_id          = 2
+  policy_version     = 3
+  policy_name        = "test_policy_export"
+  cloudlet_code      = "ER"
+  group_id           = 12345
+  match_rule_format  = "1.0"
+  match_rules        = jsondecode(data.akamai_cloudlets_policy.policy.match_rules)
+  staging_version    = 3
+  production_version = 1
+}
@@ -0,0 +1,16 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type    = string
+  default = "test_section"
+}
+
+/*
+variable "env" {
+  type    = string
+  default = "staging"
+}
+*/
@@ -0,0 +1,3 @@
+terraform init
+terraform import module.policy.akamai_cloudlets_policy.policy test_policy_export
+terraform import module.policy.akamai_cloudlets_policy_activation.policy_activation 2:staging
\ No newline at end of file
@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+module "policy" {
+  source                = "./modules/policy"
+  group_id              = var.group_id
+  env                   = var.env
+  associated_properties = var.associated_properties
+}
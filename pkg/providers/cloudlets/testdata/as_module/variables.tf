@@ -0,0 +1,23 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type = string
+}
+
+variable "group_id" {
+  description = "ID of the group of the policy"
+  type        = string
+}
+
+variable "env" {
+  description = "Network on which the policy and its load balancers are activated, staging or production"
+  type        = string
+}
+
+variable "associated_properties" {
+  description = "Properties associated with the policy on the network"
+  type        = list(string)
+}
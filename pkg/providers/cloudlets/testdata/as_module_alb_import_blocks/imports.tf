@@ -0,0 +1,19 @@
+import {
+  to = module.policy.akamai_cloudlets_application_load_balancer.load_balancer_test_origin
+  id = "test_origin"
+}
+
+import {
+  to = module.policy.akamai_cloudlets_application_load_balancer_activation.load_balancer_activation_test_origin
+  id = "test_origin,production"
+}
+
+import {
+  to = module.policy.akamai_cloudlets_policy.policy
+  id = "test_policy_export"
+}
+
+import {
+  to = module.policy.akamai_cloudlets_policy_activation.policy_activation
+  id = "2:production"
+}
@@ -0,0 +1,12 @@
+resource "akamai_cloudlets_application_load_balancer" "load_balancer_test_origin" {
+  origin_id      = "test_origin"
+  description    = ""
+  balancing_type = "WEIGHTED"
+}
+
+resource "akamai_cloudlets_application_load_balancer_activation" "load_balancer_activation_test_origin" {
+  origin_id = akamai_cloudlets_application_load_balancer.load_balancer_test_origin.origin_id
+  network   = var.env
+  version   = akamai_cloudlets_application_load_balancer.load_balancer_test_origin.version
+}
+
@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 5.6.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+resource "akamai_cloudlets_policy" "policy" {
+  name          = "test_policy_export"
+  cloudlet_code = "ER"
+  description   = ""
+  group_id      = var.group_id
+  is_shared     = true
+}
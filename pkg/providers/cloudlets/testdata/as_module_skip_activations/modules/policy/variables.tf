@@ -0,0 +1,4 @@
+variable "group_id" {
+  description = "ID of the group of the policy"
+  type        = string
+}
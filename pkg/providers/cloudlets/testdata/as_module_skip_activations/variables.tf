@@ -0,0 +1,13 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type = string
+}
+
+variable "group_id" {
+  description = "ID of the group of the policy"
+  type        = string
+}
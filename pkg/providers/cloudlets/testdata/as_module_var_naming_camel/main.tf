@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgercPath
+  config_section = var.configSection
+}
+
+module "policy" {
+  source               = "./modules/policy"
+  groupId              = var.groupId
+  env                  = var.env
+  associatedProperties = var.associatedProperties
+}
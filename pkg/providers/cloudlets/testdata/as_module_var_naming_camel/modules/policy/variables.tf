@@ -0,0 +1,14 @@
+variable "groupId" {
+  description = "ID of the group of the policy"
+  type        = string
+}
+
+variable "env" {
+  description = "Network on which the policy and its load balancers are activated, staging or production"
+  type        = string
+}
+
+variable "associatedProperties" {
+  description = "Properties associated with the policy on the network"
+  type        = list(string)
+}
@@ -0,0 +1,24 @@
+import {
+  to = akamai_cloudlets_application_load_balancer.load_balancer_origin_a
+  id = "origin_a"
+}
+
+import {
+  to = akamai_cloudlets_application_load_balancer_activation.load_balancer_activation_origin_a
+  id = "origin_a,staging"
+}
+
+import {
+  to = akamai_cloudlets_application_load_balancer.load_balancer_origin_b
+  id = "origin_b"
+}
+
+import {
+  to = akamai_cloudlets_policy.policy
+  id = "test_policy_export"
+}
+
+import {
+  to = akamai_cloudlets_policy_activation.policy_activation
+  id = "2:staging"
+}
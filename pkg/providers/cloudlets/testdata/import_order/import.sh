@@ -0,0 +1,6 @@
+terraform init
+terraform import akamai_cloudlets_application_load_balancer.load_balancer_origin_a origin_a
+terraform import akamai_cloudlets_application_load_balancer.load_balancer_origin_b origin_b
+terraform import akamai_cloudlets_application_load_balancer_activation.load_balancer_activation_origin_a origin_a,staging
+terraform import akamai_cloudlets_policy.policy test_policy
+terraform import akamai_cloudlets_policy_activation.policy_activation 2:staging
\ No newline at end of file
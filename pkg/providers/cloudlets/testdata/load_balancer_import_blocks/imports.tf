@@ -0,0 +1,10 @@
+import {
+  to = akamai_cloudlets_application_load_balancer.load_balancer_test_origin
+  id = "test_origin"
+}
+
+import {
+  to = akamai_cloudlets_application_load_balancer_activation.load_balancer_activation_test_origin
+  id = "test_origin,staging"
+}
+
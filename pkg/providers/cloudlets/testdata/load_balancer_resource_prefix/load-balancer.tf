@@ -0,0 +1,42 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_cloudlets_application_load_balancer" "edge_load_balancer_test_origin" {
+  origin_id      = "test_origin"
+  description    = "test description"
+  balancing_type = "WEIGHTED"
+
+  data_centers {
+    latitude                          = 102.78108
+    longitude                         = -116.07064
+    continent                         = "NA"
+    country                           = "US"
+    origin_id                         = "test_origin"
+    percent                           = 100
+    cloud_service                     = false
+    liveness_hosts                    = []
+    hostname                          = "test-hostname"
+    state_or_province                 = ""
+    city                              = "Boston"
+    cloud_server_host_header_override = false
+  }
+}
+
+resource "akamai_cloudlets_application_load_balancer_activation" "edge_load_balancer_activation_test_origin" {
+  origin_id = akamai_cloudlets_application_load_balancer.edge_load_balancer_test_origin.origin_id
+  network   = var.env
+  version   = akamai_cloudlets_application_load_balancer.edge_load_balancer_test_origin.version
+}
+
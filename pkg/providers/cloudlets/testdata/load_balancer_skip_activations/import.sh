@@ -0,0 +1,2 @@
+terraform init
+terraform import akamai_cloudlets_application_load_balancer.load_balancer_test_origin test_origin
\ No newline at end of file
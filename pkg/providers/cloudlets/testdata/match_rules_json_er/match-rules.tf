@@ -0,0 +1,5 @@
+
+locals {
+  # match rules of the policy are kept in match-rules.json
+  match_rules = jsondecode(file("${path.module}/match-rules.json"))
+}
@@ -0,0 +1,23 @@
+
+locals {
+  match_rules = [
+    {
+      name = "r1"
+      matches = [
+        { match_type = "range", match_operator = "equals", object_match_value = { type = "range", value = [1, 50] } },
+        { match_type = "method", match_operator = "equals", case_sensitive = true, object_match_value = { type = "simple", value = ["GET"] } },
+      ]
+      forward_settings = { origin_id = "test_origin" }
+    },
+    {
+      name             = "r2"
+      forward_settings = { origin_id = "test_origin" }
+      matches_always   = true
+    },
+  ]
+}
+
+module "match_rules" {
+  source      = "./modules/match-rules"
+  match_rules = local.match_rules
+}
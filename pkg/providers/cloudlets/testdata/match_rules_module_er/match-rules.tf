@@ -0,0 +1,30 @@
+
+locals {
+  match_rules = [
+    {
+      name  = "r1"
+      start = 1
+      end   = 2
+      matches = [
+        { match_type = "extension", match_value = "txt", match_operator = "equals" },
+        { match_type = "header", match_operator = "equals", object_match_value = { name = "ER\\", type = "object", options = { value = ["y"], value_has_wildcard = true } } },
+      ]
+      match_url        = "test.url"
+      redirect_url     = "/abc/sss"
+      status_code      = 307
+      use_relative_url = "copy_scheme_hostname"
+    },
+    {
+      name                      = "r2"
+      disabled                  = true
+      redirect_url              = "/ddd"
+      status_code               = 301
+      use_incoming_query_string = true
+    },
+  ]
+}
+
+module "match_rules" {
+  source      = "./modules/match-rules"
+  match_rules = local.match_rules
+}
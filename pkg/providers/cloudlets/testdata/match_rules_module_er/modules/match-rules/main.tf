@@ -0,0 +1,67 @@
+
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+variable "match_rules" {
+  description = "Match rules of the policy, attributes with zero values can be omitted"
+  type        = any
+}
+
+data "akamai_cloudlets_edge_redirector_match_rule" "match_rules" {
+  dynamic "match_rules" {
+    for_each = var.match_rules
+    content {
+      name  = try(match_rules.value.name, "")
+      start = try(match_rules.value.start, 0)
+      end   = try(match_rules.value.end, 0)
+      dynamic "matches" {
+        for_each = try(match_rules.value.matches, [])
+        content {
+          match_type     = try(matches.value.match_type, "")
+          match_value    = try(matches.value.match_value, "")
+          match_operator = try(matches.value.match_operator, "")
+          case_sensitive = try(matches.value.case_sensitive, false)
+          negate         = try(matches.value.negate, false)
+          check_ips      = try(matches.value.check_ips, "")
+          dynamic "object_match_value" {
+            for_each = try([matches.value.object_match_value], [])
+            content {
+              type                = object_match_value.value.type
+              value               = object_match_value.value.type == "object" ? null : try(object_match_value.value.value, [])
+              name                = object_match_value.value.type == "object" ? try(object_match_value.value.name, "") : null
+              name_case_sensitive = object_match_value.value.type == "object" ? try(object_match_value.value.name_case_sensitive, false) : null
+              name_has_wildcard   = object_match_value.value.type == "object" ? try(object_match_value.value.name_has_wildcard, false) : null
+              dynamic "options" {
+                for_each = try([object_match_value.value.options], [])
+                content {
+                  value                = try(options.value.value, [])
+                  value_has_wildcard   = try(options.value.value_has_wildcard, false)
+                  value_case_sensitive = try(options.value.value_case_sensitive, false)
+                  value_escaped        = try(options.value.value_escaped, false)
+                }
+              }
+            }
+          }
+        }
+      }
+      use_relative_url          = try(match_rules.value.use_relative_url, "")
+      status_code               = try(match_rules.value.status_code, 0)
+      redirect_url              = try(match_rules.value.redirect_url, "")
+      use_incoming_query_string = try(match_rules.value.use_incoming_query_string, false)
+      match_url                 = try(match_rules.value.match_url, "")
+      disabled                  = try(match_rules.value.disabled, false)
+    }
+  }
+}
+
+output "json" {
+  description = "Match rules of the policy in JSON format"
+  value       = data.akamai_cloudlets_edge_redirector_match_rule.match_rules.json
+}
@@ -0,0 +1,8 @@
+terraform init
+terraform import akamai_cloudlets_policy.policy test_policy_export
+terraform import akamai_cloudlets_policy_activation.policy_activation 2:staging
+
+# After migration of the policy to a shared policy, see shared-policy-migration.tf:
+# terraform state rm akamai_cloudlets_policy.policy akamai_cloudlets_policy_activation.policy_activation
+# terraform import akamai_cloudlets_policy.policy test_policy_export
+# terraform import akamai_cloudlets_policy_activation.policy_activation <shared policy ID>:staging
\ No newline at end of file
@@ -0,0 +1,17 @@
+
+# Migration of the policy to a shared policy of Cloudlets API v3:
+#   1. Migrate the policy to a shared policy in Cloudlets Policy Manager, keeping its name.
+#   2. Replace akamai_cloudlets_policy.policy in policy.tf with the resource below and set version of
+#      akamai/akamai provider to ">= 5.6.0".
+#   3. Remove the legacy policy from the state and import the shared policy with the commands at the end of
+#      import.sh.
+/*
+resource "akamai_cloudlets_policy" "policy" {
+  name = "test_policy_export"
+  cloudlet_code = "ER"
+  description = "Testing exported policy"
+  group_id = "12345"
+  is_shared = true
+  match_rules = data.akamai_cloudlets_edge_redirector_match_rule.match_rules_er.json
+}
+*/
@@ -0,0 +1,13 @@
+import {
+  to = akamai_cloudlets_policy.policy
+  id = "test_policy_export"
+}
+
+# After migration of the policy to a shared policy, see shared-policy-migration.tf, remove the legacy policy from the
+# state and import the shared policy:
+# terraform state rm akamai_cloudlets_policy.policy
+#
+# import {
+#   to = akamai_cloudlets_policy.policy
+#   id = "test_policy_export"
+# }
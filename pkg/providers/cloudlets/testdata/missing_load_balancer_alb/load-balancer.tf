@@ -0,0 +1,45 @@
+resource "akamai_cloudlets_application_load_balancer" "load_balancer_test_origin" {
+  origin_id      = "test_origin"
+  description    = ""
+  balancing_type = "WEIGHTED"
+
+  data_centers {
+    latitude                          = 102.78108
+    longitude                         = -116.07064
+    continent                         = "NA"
+    country                           = "US"
+    origin_id                         = "test_origin"
+    percent                           = 100
+    cloud_service                     = false
+    liveness_hosts                    = []
+    hostname                          = "test-hostname"
+    state_or_province                 = ""
+    city                              = "Boston"
+    cloud_server_host_header_override = false
+  }
+}
+
+# PLACEHOLDER: match rules forward to origin 'deleted_origin' whose load balancer does not exist or has no versions,
+# define its first version and uncomment the resource, or change the origin of the match rules
+/*
+resource "akamai_cloudlets_application_load_balancer" "load_balancer_deleted_origin" {
+  origin_id = "deleted_origin"
+  balancing_type = "WEIGHTED"
+
+  data_centers {
+    latitude = 0
+    longitude = 0
+    continent = ""
+    country = ""
+    origin_id = ""
+    percent = 100
+  }
+}
+*/
+
+resource "akamai_cloudlets_application_load_balancer_activation" "load_balancer_activation_test_origin" {
+  origin_id = akamai_cloudlets_application_load_balancer.load_balancer_test_origin.origin_id
+  network   = var.env
+  version   = akamai_cloudlets_application_load_balancer.load_balancer_test_origin.version
+}
+
@@ -0,0 +1,7 @@
+
+# The policy has no versions, define match rules of its first version with the data source and uncomment match_rules of
+# the policy
+/*
+data "akamai_cloudlets_edge_redirector_match_rule" "match_rules" {
+}
+*/
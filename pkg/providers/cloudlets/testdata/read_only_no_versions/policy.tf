@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+locals {
+  policy_id      = 2
+  policy_version = null
+  policy_name    = "test_policy_export"
+  cloudlet_code  = "ER"
+  group_id       = 12345
+  match_rules    = []
+}
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 5.6.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+data "akamai_cloudlets_shared_policy" "policy" {
+  policy_id = 11
+  version   = 3
+}
+
+locals {
+  policy_id       = 11
+  policy_version  = 3
+  policy_name     = "test_policy_export"
+  cloudlet_code   = "ER"
+  group_id        = 12345
+  match_rules     = jsondecode(data.akamai_cloudlets_shared_policy.policy.match_rules)
+  staging_version = 3
+}
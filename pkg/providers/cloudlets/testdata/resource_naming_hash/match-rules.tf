@@ -0,0 +1,15 @@
+
+locals {
+  test_policy_export_61ef7820_match_rules = [
+    {
+      name         = "r1"
+      redirect_url = "/ddd"
+      status_code  = 301
+    },
+  ]
+}
+
+module "test_policy_export_61ef7820_match_rules" {
+  source      = "./modules/match-rules"
+  match_rules = local.test_policy_export_61ef7820_match_rules
+}
@@ -0,0 +1,32 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_cloudlets_policy" "test_policy_export_61ef7820" {
+  name              = "test_policy_export"
+  cloudlet_code     = "ER"
+  description       = "Testing exported policy"
+  group_id          = "12345"
+  match_rule_format = "1.0"
+  match_rules       = module.test_policy_export_61ef7820_match_rules.json
+}
+
+/*
+resource "akamai_cloudlets_policy_activation" "test_policy_export_61ef7820_activation" {
+  policy_id = tonumber(akamai_cloudlets_policy.test_policy_export_61ef7820.id)
+  network = var.env
+  version = akamai_cloudlets_policy.test_policy_export_61ef7820.version
+  associated_properties = [ "UNKNOWN_CHANGE_ME" ]
+}
+*/
@@ -0,0 +1,5 @@
+terraform init
+terraform import akamai_cloudlets_application_load_balancer.edge_load_balancer_test_origin test_origin
+terraform import akamai_cloudlets_application_load_balancer_activation.edge_load_balancer_activation_test_origin test_origin,staging
+terraform import akamai_cloudlets_policy.edge_test_policy_export Test-Policy Export
+terraform import akamai_cloudlets_policy_activation.edge_test_policy_export_activation 2:staging
\ No newline at end of file
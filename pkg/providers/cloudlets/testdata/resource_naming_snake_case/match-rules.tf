@@ -0,0 +1,13 @@
+data "akamai_cloudlets_application_load_balancer_match_rule" "edge_test_policy_export_match_rules_alb" {
+  match_rules {
+    name           = "r1"
+    start          = 0
+    end            = 0
+    match_url      = ""
+    matches_always = false
+    forward_settings {
+      origin_id = "test_origin"
+    }
+    disabled = false
+  }
+}
@@ -0,0 +1,9 @@
+import {
+  to = akamai_cloudlets_policy.edge_policy
+  id = "test_policy_export"
+}
+
+import {
+  to = akamai_cloudlets_policy_activation.edge_policy_activation_staging
+  id = "2:staging"
+}
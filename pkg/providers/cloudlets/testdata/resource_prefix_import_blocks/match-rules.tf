@@ -0,0 +1,13 @@
+data "akamai_cloudlets_edge_redirector_match_rule" "edge_match_rules_er" {
+  match_rules {
+    name                      = "r1"
+    start                     = 0
+    end                       = 0
+    use_relative_url          = ""
+    status_code               = 301
+    redirect_url              = "/ddd"
+    match_url                 = ""
+    use_incoming_query_string = false
+    disabled                  = false
+  }
+}
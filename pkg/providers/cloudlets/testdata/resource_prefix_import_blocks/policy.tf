@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 5.6.0"
+    }
+  }
+  required_version = ">= 1.5"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_cloudlets_policy" "edge_policy" {
+  name          = "test_policy_export"
+  cloudlet_code = "ER"
+  description   = "Testing exported policy"
+  group_id      = "12345"
+  is_shared     = true
+  match_rules   = data.akamai_cloudlets_edge_redirector_match_rule.edge_match_rules_er.json
+}
+
+resource "akamai_cloudlets_policy_activation" "edge_policy_activation_staging" {
+  policy_id = tonumber(akamai_cloudlets_policy.edge_policy.id)
+  network   = "staging"
+  version   = 1
+}
@@ -0,0 +1,4 @@
+terraform init
+terraform import akamai_cloudlets_policy.policy test_policy_export
+terraform import akamai_cloudlets_policy_activation.policy_activation_staging 11:staging
+terraform import akamai_cloudlets_policy_activation.policy_activation_production 11:production
\ No newline at end of file
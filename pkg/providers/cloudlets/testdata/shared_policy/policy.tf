@@ -0,0 +1,36 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 5.6.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_cloudlets_policy" "policy" {
+  name          = "test_policy_export"
+  cloudlet_code = "ER"
+  description   = "Testing exported shared policy"
+  group_id      = "12345"
+  is_shared     = true
+  match_rules   = data.akamai_cloudlets_edge_redirector_match_rule.match_rules_er.json
+}
+
+resource "akamai_cloudlets_policy_activation" "policy_activation_staging" {
+  policy_id             = tonumber(akamai_cloudlets_policy.policy.id)
+  network               = "staging"
+  version               = akamai_cloudlets_policy.policy.version
+  associated_properties = ["prp_0", "prp_1"]
+}
+
+resource "akamai_cloudlets_policy_activation" "policy_activation_production" {
+  policy_id = tonumber(akamai_cloudlets_policy.policy.id)
+  network   = "production"
+  version   = 2
+}
@@ -0,0 +1,9 @@
+
+locals {
+  # match rules of the policy are kept in rules directory, one file per rule in the order of the policy
+  match_rules = [
+    jsondecode(file("${path.module}/rules/redirect_old.json")),
+    jsondecode(file("${path.module}/rules/rule.json")),
+    jsondecode(file("${path.module}/rules/redirect_old_2.json")),
+  ]
+}
@@ -0,0 +1,14 @@
+variable "edgercPath" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "configSection" {
+  type    = string
+  default = "test_section"
+}
+
+variable "env" {
+  type    = string
+  default = "staging"
+}
@@ -0,0 +1,218 @@
+package cloudlets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// ErrReadingRules is returned when the match rules file cannot be read
+	ErrReadingRules = exitcode.New(exitcode.IO, "unable to read match rules")
+	// ErrRulesFormat is returned when the file holds neither an array of match rules nor an object with 'matchRules'
+	ErrRulesFormat = exitcode.New(exitcode.General, "match rules must be a JSON array or an object with 'matchRules' array")
+	// ErrInvalidRules is returned when any of the match rules does not conform to the schema of the cloudlet type
+	ErrInvalidRules = exitcode.New(exitcode.General, "invalid match rules")
+	// ErrCloudletTypeMismatch is returned when the cloudlet type given with the flag differs from the one of the policy
+	ErrCloudletTypeMismatch = exitcode.New(exitcode.General, "cloudlet type does not match the policy")
+)
+
+// matchRuleTypes maps cloudlet codes to types of their match rules
+var matchRuleTypes = map[string]cloudlets.MatchRuleType{
+	"ALB": cloudlets.MatchRuleTypeALB,
+	"AP":  cloudlets.MatchRuleTypeAP,
+	"AS":  cloudlets.MatchRuleTypeAS,
+	"CD":  cloudlets.MatchRuleTypePR,
+	"ER":  cloudlets.MatchRuleTypeER,
+	"FR":  cloudlets.MatchRuleTypeFR,
+	"IG":  cloudlets.MatchRuleTypeRC,
+	"VP":  cloudlets.MatchRuleTypeVP,
+}
+
+// readOnlyRuleFields are fields of match rules returned by the API which are not sent back, they are not reported as unknown
+var readOnlyRuleFields = map[string]bool{"akaRuleId": true, "location": true}
+
+// CmdValidateRules is an entrypoint to validate-rules command
+func CmdValidateRules(c *cli.Context) error {
+	ctx := c.Context
+	path := c.Args().First()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("%w: %s", ErrReadingRules, err)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	cloudletType := strings.ToUpper(c.String("cloudlet-type"))
+	if c.IsSet("policy") {
+		client := cloudlets.Client(edgegrid.GetSession(ctx))
+		policy, err := findPolicyByName(ctx, c.String("policy"), client)
+		if err != nil {
+			err = fmt.Errorf("%w: %s", ErrFetchingPolicy, err)
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		if cloudletType != "" && cloudletType != policy.CloudletCode {
+			err = fmt.Errorf("%w: '%s' is given, but policy '%s' is '%s'", ErrCloudletTypeMismatch, cloudletType, policy.Name, policy.CloudletCode)
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		cloudletType = policy.CloudletCode
+	}
+
+	problems, err := validateRules(content, cloudletType)
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	term := terminal.Get(ctx)
+	if len(problems) == 0 {
+		term.Printf("Match rules in '%s' are valid\n", path)
+		return nil
+	}
+	for _, problem := range problems {
+		term.Printf("  %s\n", problem)
+	}
+	return cli.Exit(color.RedString(fmt.Sprintf("%s in '%s': %d problem(s) found", ErrInvalidRules, path, len(problems))), exitcode.Of(ErrInvalidRules))
+}
+
+// validateRules checks that every match rule in the content conforms to the schema of the cloudlet type and survives the
+// round-trip through the API client, i.e. contains no fields which would be dropped, and returns found problems; the
+// cloudlet type is taken from the first rule when empty
+func validateRules(content []byte, cloudletType string) ([]string, error) {
+	rules, err := rawRules(content)
+	if err != nil {
+		return nil, err
+	}
+	var expected cloudlets.MatchRuleType
+	if cloudletType != "" {
+		ruleType, ok := matchRuleTypes[cloudletType]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrCloudletTypeNotSupported, cloudletType)
+		}
+		expected = ruleType
+	}
+
+	var problems []string
+	for i, raw := range rules {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			problems = append(problems, fmt.Sprintf("rule %d: %s", i, err))
+			continue
+		}
+		prefix := fmt.Sprintf("rule %d", i)
+		if name, ok := fields["name"].(string); ok && name != "" {
+			prefix = fmt.Sprintf("rule %d '%s'", i, name)
+		}
+		ruleType, _ := fields["type"].(string)
+		if expected == "" {
+			expected = cloudlets.MatchRuleType(ruleType)
+		}
+		if ruleType != string(expected) {
+			problems = append(problems, fmt.Sprintf("%s: type '%s' is not '%s'", prefix, ruleType, expected))
+			continue
+		}
+
+		var parsed cloudlets.MatchRules
+		if err := json.Unmarshal(append(append([]byte("["), raw...), ']'), &parsed); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", prefix, err))
+			continue
+		}
+		if err := parsed[0].Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", prefix, err))
+		}
+		roundTrip, err := json.Marshal(parsed[0])
+		if err != nil {
+			return nil, err
+		}
+		var kept map[string]interface{}
+		if err := json.Unmarshal(roundTrip, &kept); err != nil {
+			return nil, err
+		}
+		for _, field := range droppedFields(fields, kept, "") {
+			problems = append(problems, fmt.Sprintf("%s: unknown field '%s'", prefix, field))
+		}
+	}
+	return problems, nil
+}
+
+// rawRules returns match rules from either a JSON array or an object with 'matchRules' array, e.g. a policy version
+func rawRules(content []byte) ([]json.RawMessage, error) {
+	var rules []json.RawMessage
+	if err := json.Unmarshal(content, &rules); err == nil {
+		return rules, nil
+	}
+	var version struct {
+		MatchRules []json.RawMessage `json:"matchRules"`
+	}
+	if err := json.Unmarshal(content, &version); err != nil || version.MatchRules == nil {
+		return nil, ErrRulesFormat
+	}
+	return version.MatchRules, nil
+}
+
+// droppedFields returns sorted paths of fields with non-empty values which are present in the original rule, but missing
+// after the round-trip, as the API client does not know them
+func droppedFields(original, kept map[string]interface{}, parent string) []string {
+	var dropped []string
+	for key, value := range original {
+		path := key
+		if parent != "" {
+			path = parent + "." + key
+		}
+		if parent == "" && readOnlyRuleFields[key] {
+			continue
+		}
+		keptValue, ok := kept[key]
+		if !ok {
+			if !isEmpty(value) {
+				dropped = append(dropped, path)
+			}
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if k, ok := keptValue.(map[string]interface{}); ok {
+				dropped = append(dropped, droppedFields(v, k, path)...)
+			}
+		case []interface{}:
+			k, ok := keptValue.([]interface{})
+			if !ok {
+				continue
+			}
+			for i := 0; i < len(v) && i < len(k); i++ {
+				vm, vok := v[i].(map[string]interface{})
+				km, kok := k[i].(map[string]interface{})
+				if vok && kok {
+					dropped = append(dropped, droppedFields(vm, km, fmt.Sprintf("%s[%d]", path, i))...)
+				}
+			}
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// isEmpty reports whether the value is omitted when marshaled with omitempty
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package cloudlets
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRules(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		content        string
+		cloudletType   string
+		expectProblems []string
+		withError      error
+	}{
+		"valid rules": {
+			path: "./testdata/validate_rules/valid_rules.json",
+		},
+		"valid rules of given cloudlet type": {
+			path:         "./testdata/validate_rules/valid_rules.json",
+			cloudletType: "ER",
+		},
+		"policy version with read-only fields": {
+			path: "./testdata/validate_rules/policy_version.json",
+		},
+		"invalid rules": {
+			path: "./testdata/validate_rules/invalid_rules.json",
+			expectProblems: []string{
+				"rule 0 'bad status': StatusCode: value '200' is invalid. Must be one of: 301, 302, 303, 307 or 308.",
+				"rule 1 'typo': unknown field 'matches[0].negated'",
+				"rule 1 'typo': unknown field 'redirectUrl'",
+				"rule 2 'other cloudlet': type 'frMatchRule' is not 'erMatchRule'",
+			},
+		},
+		"rules of other cloudlet type": {
+			path:         "./testdata/validate_rules/valid_rules.json",
+			cloudletType: "FR",
+			expectProblems: []string{
+				"rule 0 'old blog': type 'erMatchRule' is not 'frMatchRule'",
+			},
+		},
+		"rule without type": {
+			content:        `[{"name": "no type"}]`,
+			cloudletType:   "VP",
+			expectProblems: []string{"rule 0 'no type': type '' is not 'vpMatchRule'"},
+		},
+		"unsupported cloudlet type": {
+			content:      `[]`,
+			cloudletType: "XX",
+			withError:    ErrCloudletTypeNotSupported,
+		},
+		"neither array nor policy version": {
+			content:   `{"name": "rule"}`,
+			withError: ErrRulesFormat,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			content := []byte(test.content)
+			if test.path != "" {
+				var err error
+				content, err = os.ReadFile(test.path)
+				require.NoError(t, err)
+			}
+			problems, err := validateRules(content, test.cloudletType)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectProblems, problems)
+		})
+	}
+}
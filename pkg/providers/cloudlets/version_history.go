@@ -0,0 +1,111 @@
+package cloudlets
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/secrets"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// TFPolicyVersionHistory represents metadata of a policy version annotated as a comment in the policy template
+type TFPolicyVersionHistory struct {
+	Version     int64
+	Description string
+	CreatedBy   string
+	CreateDate  string
+	// RulesFile is the slash separated path of the file with match rules of the version, relative to the policy, set
+	// when full version history is exported
+	RulesFile string
+}
+
+// versionRulesFetcher returns match rules of the given version of the policy
+type versionRulesFetcher func(ctx context.Context, version int64) (cloudlets.MatchRules, error)
+
+// getVersionHistory returns metadata of at most count latest versions of the policy, newest first, or of all versions
+// when count is not positive. Deleted versions are skipped and descriptions are flattened into a single line, so that
+// they fit into a comment.
+func getVersionHistory(ctx context.Context, policyID int64, count int, client cloudlets.Cloudlets) ([]TFPolicyVersionHistory, error) {
+	var versions []cloudlets.PolicyVersion
+	pageSize, offset := tools.PageSize(tools.PageSizeCloudlets, 1000), 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := client.ListPolicyVersions(ctx, cloudlets.ListPolicyVersionsRequest{
+			PolicyID:     policyID,
+			IncludeRules: false,
+			PageSize:     &pageSize,
+			Offset:       offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, page...)
+		if len(page) < pageSize {
+			break
+		}
+		offset += pageSize
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	history := make([]TFPolicyVersionHistory, 0, len(versions))
+	for _, version := range versions {
+		if count > 0 && len(history) == count {
+			break
+		}
+		if version.Deleted {
+			continue
+		}
+		history = append(history, TFPolicyVersionHistory{
+			Version:     version.Version,
+			Description: strings.Join(strings.Fields(version.Description), " "),
+			CreatedBy:   version.CreatedBy,
+			CreateDate:  time.UnixMilli(version.CreateDate).UTC().Format(time.RFC3339),
+		})
+	}
+	return history, nil
+}
+
+// writeVersionRules writes match rules of every version of the history, fetched concurrently with fetchRules, into
+// '<version>.json' files in dir, in the form of match-rules.json, and sets paths of the files in the history
+func writeVersionRules(ctx context.Context, policyName, dir string, history []TFPolicyVersionHistory, fetchRules versionRulesFetcher) error {
+	progress.Get(ctx).OK()
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching %d versions of policy %s", len(history), policyName))
+	progress.Get(ctx).Total(len(history))
+	return tools.RunConcurrently(ctx, len(history), func(ctx context.Context, i int) error {
+		rules, err := fetchRules(ctx, history[i].Version)
+		if err != nil {
+			return fmt.Errorf("version %d: %w", history[i].Version, err)
+		}
+		content, err := matchRulesJSONContent(rules)
+		if err != nil {
+			return err
+		}
+		if content == "" {
+			content = "[]\n"
+		}
+		name := fmt.Sprintf("%d.json", history[i].Version)
+		path := filepath.Join(dir, name)
+		sink := templates.GetSink(ctx)
+		filtered, err := secrets.Filter(sink, path, []byte(content))
+		if err != nil {
+			return err
+		}
+		if err = sink.WriteFile(path, tools.ApplyLineEndings(filtered)); err != nil {
+			return err
+		}
+		history[i].RulesFile = filepath.Base(dir) + "/" + name
+		progress.Get(ctx).Step()
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package cloudlets
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cloudlets"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetVersionHistory(t *testing.T) {
+	pageSize := 1000
+	tests := map[string]struct {
+		count     int
+		init      func(*cloudlets.Mock)
+		expected  []TFPolicyVersionHistory
+		withError bool
+	}{
+		"latest versions newest first": {
+			count: 2,
+			init: func(c *cloudlets.Mock) {
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{
+					{Version: 1, Description: "initial", CreateDate: 1633046400000},
+					{Version: 3, Description: "redirect\nlegacy  paths", CreatedBy: "jsmith", CreateDate: 1633132800000},
+					{Version: 2, CreatedBy: "jdoe", CreateDate: 1633089600000},
+				}, nil).Once()
+			},
+			expected: []TFPolicyVersionHistory{
+				{Version: 3, Description: "redirect legacy paths", CreatedBy: "jsmith", CreateDate: "2021-10-02T00:00:00Z"},
+				{Version: 2, CreatedBy: "jdoe", CreateDate: "2021-10-01T12:00:00Z"},
+			},
+		},
+		"deleted versions are skipped": {
+			count: 2,
+			init: func(c *cloudlets.Mock) {
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{
+					{Version: 1, Description: "initial", CreateDate: 1633046400000},
+					{Version: 2, Deleted: true, CreateDate: 1633089600000},
+				}, nil).Once()
+			},
+			expected: []TFPolicyVersionHistory{
+				{Version: 1, Description: "initial", CreateDate: "2021-10-01T00:00:00Z"},
+			},
+		},
+		"all versions": {
+			init: func(c *cloudlets.Mock) {
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return([]cloudlets.PolicyVersion{
+					{Version: 1, Description: "initial", CreateDate: 1633046400000},
+					{Version: 3, CreatedBy: "jsmith", CreateDate: 1633132800000},
+					{Version: 2, CreatedBy: "jdoe", CreateDate: 1633089600000},
+				}, nil).Once()
+			},
+			expected: []TFPolicyVersionHistory{
+				{Version: 3, CreatedBy: "jsmith", CreateDate: "2021-10-02T00:00:00Z"},
+				{Version: 2, CreatedBy: "jdoe", CreateDate: "2021-10-01T12:00:00Z"},
+				{Version: 1, Description: "initial", CreateDate: "2021-10-01T00:00:00Z"},
+			},
+		},
+		"error listing versions": {
+			count: 1,
+			init: func(c *cloudlets.Mock) {
+				c.On("ListPolicyVersions", mock.Anything, cloudlets.ListPolicyVersionsRequest{PolicyID: 2, PageSize: &pageSize, Offset: 0}).Return(nil, errors.New("oops")).Once()
+			},
+			withError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mc := new(cloudlets.Mock)
+			test.init(mc)
+			history, err := getVersionHistory(context.Background(), 2, test.count, mc)
+			mc.AssertExpectations(t)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, history)
+		})
+	}
+}
+
+func TestWriteVersionRules(t *testing.T) {
+	rules := map[int64]cloudlets.MatchRules{
+		2: {&cloudlets.MatchRuleER{Name: "r1", Type: cloudlets.MatchRuleTypeER, ID: 1234, StatusCode: 301, RedirectURL: "/a?b=1&c=2"}},
+		1: nil,
+	}
+	fetchRules := func(_ context.Context, version int64) (cloudlets.MatchRules, error) {
+		r, ok := rules[version]
+		if !ok {
+			return nil, errors.New("oops")
+		}
+		return r, nil
+	}
+
+	t.Run("rules of every version", func(t *testing.T) {
+		sink := templates.NewMemorySink()
+		ctx := templates.WithSink(terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter())), sink)
+		history := []TFPolicyVersionHistory{{Version: 2}, {Version: 1}}
+		require.NoError(t, writeVersionRules(ctx, "test_policy", filepath.Join("out", "versions"), history, fetchRules))
+
+		assert.Equal(t, []TFPolicyVersionHistory{{Version: 2, RulesFile: "versions/2.json"}, {Version: 1, RulesFile: "versions/1.json"}}, history)
+		files := sink.Files()
+		assert.Equal(t, `[
+  {
+    "name": "r1",
+    "redirectURL": "/a?b=1&c=2",
+    "statusCode": 301,
+    "type": "erMatchRule",
+    "useIncomingQueryString": false,
+    "useIncomingSchemeAndHost": false
+  }
+]
+`, string(files[filepath.Join("out", "versions", "2.json")]))
+		assert.Equal(t, "[]\n", string(files[filepath.Join("out", "versions", "1.json")]))
+	})
+
+	t.Run("error fetching rules", func(t *testing.T) {
+		ctx := templates.WithSink(terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter())), templates.NewMemorySink())
+		err := writeVersionRules(ctx, "test_policy", "versions", []TFPolicyVersionHistory{{Version: 3}}, fetchRules)
+		assert.EqualError(t, err, "version 3: oops")
+	})
+}
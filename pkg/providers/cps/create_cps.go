@@ -3,14 +3,17 @@ package cps
 import (
 	"context"
 	"embed"
-	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/identity"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
@@ -24,6 +27,7 @@ type (
 		Enrollment          cps.Enrollment
 		EnrollmentID        int
 		ContractID          string
+		ContractName        string
 		Section             string
 		CertificateECDSA    string
 		TrustChainECDSA     string
@@ -38,11 +42,13 @@ var templateFiles embed.FS
 
 var (
 	// ErrFetchingEnrollment is returned when fetching enrollment fails
-	ErrFetchingEnrollment = errors.New("unable to fetch enrollment with given id")
+	ErrFetchingEnrollment = exitcode.New(exitcode.API, "unable to fetch enrollment with given id")
 	// ErrFetchingCertificateHistory is returned when fetching certificate history fails
-	ErrFetchingCertificateHistory = errors.New("unable to fetch certificate history with given id")
+	ErrFetchingCertificateHistory = exitcode.New(exitcode.API, "unable to fetch certificate history with given id")
 	// ErrUnsupportedEnrollmentType is returned when user try to export OV or EV enrollments
-	ErrUnsupportedEnrollmentType = errors.New("supporting export of dv and third-party enrollments but got")
+	ErrUnsupportedEnrollmentType = exitcode.New(exitcode.Unsupported, "supporting export of dv and third-party enrollments but got")
+	// ErrSavingFiles is returned when the directory for exported enrollment cannot be created
+	ErrSavingFiles = exitcode.New(exitcode.IO, "saving terraform project files")
 )
 
 // CmdCreateCPS is an entrypoint to create-cps command
@@ -56,13 +62,46 @@ func CmdCreateCPS(c *cli.Context) error {
 	if c.IsSet("tfworkpath") {
 		tfWorkPath = c.String("tfworkpath")
 	}
+	processor, err := newTemplateProcessor(tfWorkPath, c.Bool("read-only"), templates.GetOutputTarget(ctx), templates.GetSink(ctx))
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+
+	enrollmentID, err := strconv.Atoi(c.Args().Get(0))
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	contract, err := identity.NewResolver(papi.Client(sess)).Contract(ctx, c.Args().Get(1))
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting enrollment HCL: %s", err)), exitcode.Of(err))
+	}
+	section := edgegrid.GetEdgercSection(c)
+	if err = createCPS(ctx, contract, enrollmentID, section, client, processor); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting enrollment HCL: %s", err)), exitcode.Of(err))
+	}
+	return nil
+}
+
+// ExportEnrollment exports the enrollment into tfWorkPath, creating the directory if needed. It is used to export
+// enrollments of certificates used by other exported objects, readOnly means that the enrollment is exported as a data source.
+func ExportEnrollment(ctx context.Context, contractID string, enrollmentID int, section, tfWorkPath string, readOnly bool, client cps.CPS) error {
+	processor, err := newTemplateProcessor(tfWorkPath, readOnly, nil, templates.GetSink(ctx))
+	if err != nil {
+		return err
+	}
+	return createCPS(ctx, identity.Contract{ID: contractID}, enrollmentID, section, client, processor)
+}
+
+// newTemplateProcessor returns processor writing enrollment configuration into tfWorkPath, unless its files already exist,
+// readOnly means that the enrollment is written as a data source and no import script is written, file names are mapped
+// with the output template if output is not nil and files are written into sink
+func newTemplateProcessor(tfWorkPath string, readOnly bool, output *templates.OutputTarget, sink templates.OutputSink) (templates.TemplateProcessor, error) {
 	enrollmentPath := filepath.Join(tfWorkPath, "enrollment.tf")
 	variablesPath := filepath.Join(tfWorkPath, "variables.tf")
 	importPath := filepath.Join(tfWorkPath, "import.sh")
 
-	err := tools.CheckFiles(enrollmentPath, variablesPath, importPath)
-	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+	if err := tools.CheckFiles(enrollmentPath, variablesPath, importPath); err != nil {
+		return nil, err
 	}
 
 	templateToFile := map[string]string{
@@ -70,58 +109,54 @@ func CmdCreateCPS(c *cli.Context) error {
 		"variables.tmpl":  variablesPath,
 		"imports.tmpl":    importPath,
 	}
+	if readOnly {
+		templateToFile = map[string]string{
+			"enrollment-read-only.tmpl": enrollmentPath,
+			"variables.tmpl":            variablesPath,
+		}
+	}
 
-	processor := templates.FSTemplateProcessor{
+	return templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
-	}
-
-	enrollmentID, err := strconv.Atoi(c.Args().Get(0))
-	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
-	}
-	contractID := c.Args().Get(1)
-	section := edgegrid.GetEdgercSection(c)
-	if err = createCPS(ctx, contractID, enrollmentID, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting enrollment HCL: %s", err)), 1)
-	}
-	return nil
+		Output:          output,
+		Sink:            sink,
+	}, nil
 }
 
-func createCPS(ctx context.Context, contractID string, enrollmentID int,
+func createCPS(ctx context.Context, contract identity.Contract, enrollmentID int,
 	section string, client cps.CPS, templateProcessor templates.TemplateProcessor) error {
-	term := terminal.Get(ctx)
-
-	fmt.Println("Exporting CPS configuration")
+	terminal.Get(ctx).Printf("Exporting CPS configuration\n")
 
-	term.Spinner().Start(fmt.Sprintf("Fetching enrollment for the given id %d", enrollmentID))
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching enrollment for the given id %d", enrollmentID))
 	enrollment, err := client.GetEnrollment(ctx, cps.GetEnrollmentRequest{
 		EnrollmentID: enrollmentID,
 	})
 	if err != nil || enrollment == nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingEnrollment, err)
 	}
 
 	if enrollment.ValidationType != "third-party" && enrollment.ValidationType != "dv" {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrUnsupportedEnrollmentType, enrollment.ValidationType)
 	}
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfData := TFCPSData{
 		Enrollment:   *enrollment,
 		EnrollmentID: enrollmentID,
-		ContractID:   contractID,
+		ContractID:   contract.ID,
+		ContractName: contract.Name,
 		Section:      section,
 	}
 
 	if enrollment.ValidationType == "third-party" {
-		term.Spinner().Start("Retrieving certificate history ")
+		progress.Get(ctx).Start("Retrieving certificate history ")
 		certHistory, err := client.GetChangeHistory(ctx, cps.GetChangeHistoryRequest{EnrollmentID: enrollmentID})
 		if err != nil {
-			term.Spinner().Fail()
+			progress.Get(ctx).Fail()
 			return fmt.Errorf("%w: %s", ErrFetchingCertificateHistory, err)
 		}
 		certificateECDSA, trustChainECDSA, certificateRSA, trustChainRSA := getCertificatesFromChangeHistory(certHistory)
@@ -132,16 +167,16 @@ func createCPS(ctx context.Context, contractID string, enrollmentID int,
 		if certificateECDSA == "" && certificateRSA == "" {
 			tfData.NoUploadCertificate = true
 		}
-		term.Spinner().OK()
+		progress.Get(ctx).OK()
 	}
 
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err := templateProcessor.ProcessTemplates(tfData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
-	fmt.Printf("Terraform configuration for enrollment '%d' was saved successfully\n", enrollmentID)
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for enrollment '%d' was saved successfully\n", enrollmentID)
 
 	return nil
 }
@@ -6,12 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
 	"testing"
-	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/identity"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/stretchr/testify/assert"
@@ -39,11 +38,6 @@ var (
 				"variables.tmpl":  fmt.Sprintf("./testdata/res/%s/variables.tf", testdir),
 				"imports.tmpl":    fmt.Sprintf("./testdata/res/%s/import.sh", testdir),
 			},
-			AdditionalFuncs: template.FuncMap{
-				"ToLower": func(val string) string {
-					return strings.ToLower(val)
-				},
-			},
 		}
 	}
 
@@ -636,7 +630,7 @@ func TestCreateCPS(t *testing.T) {
 			mp := processor(test.dataDir)
 			test.init(mi)
 			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
-			err := createCPS(ctx, test.contractID, test.enrollmentID, section, mi, mp)
+			err := createCPS(ctx, identity.Contract{ID: test.contractID}, test.enrollmentID, section, mi, mp)
 			if test.withError != nil {
 				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
 				return
@@ -662,6 +656,7 @@ func TestProcessEnrollmentTemplates(t *testing.T) {
 		givenData    TFCPSData
 		dir          string
 		filesToCheck []string
+		readOnly     bool
 	}{
 		"dv enrollment": {
 			givenData: TFCPSData{
@@ -693,6 +688,17 @@ func TestProcessEnrollmentTemplates(t *testing.T) {
 			dir:          "dv_enrollment_min",
 			filesToCheck: []string{"enrollment.tf", "variables.tf", "import.sh"},
 		},
+		"dv enrollment with contract name": {
+			givenData: TFCPSData{
+				Enrollment:   enrollmentDVMin,
+				EnrollmentID: 1,
+				ContractID:   "ctr_1",
+				ContractName: "DIRECT_CUSTOMER",
+				Section:      "test_section",
+			},
+			dir:          "dv_enrollment_contract_name",
+			filesToCheck: []string{"enrollment.tf"},
+		},
 		"third party enrollment with all fields set": {
 			givenData: TFCPSData{
 				Enrollment:       enrollmentThirdPartyAll,
@@ -707,12 +713,32 @@ func TestProcessEnrollmentTemplates(t *testing.T) {
 			dir:          "third_party_enrollment_all_fields_ecdsa_rsa",
 			filesToCheck: []string{"enrollment.tf", "variables.tf", "import.sh"},
 		},
+		"read-only enrollment": {
+			givenData: TFCPSData{
+				Enrollment:   enrollmentDV,
+				EnrollmentID: 1,
+				ContractID:   "ctr_1",
+				Section:      "test_section",
+			},
+			dir:          "read_only_enrollment",
+			filesToCheck: []string{"enrollment.tf", "variables.tf"},
+			readOnly:     true,
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			require.NoError(t, os.MkdirAll(fmt.Sprintf("./testdata/res/%s", test.dir), 0755))
-			require.NoError(t, processor(test.dir).ProcessTemplates(test.givenData))
+			var p templates.TemplateProcessor = processor(test.dir)
+			if test.readOnly {
+				var err error
+				p, err = newTemplateProcessor(fmt.Sprintf("./testdata/res/%s", test.dir), true, nil, templates.DirSink{})
+				require.NoError(t, err)
+			}
+			require.NoError(t, p.ProcessTemplates(test.givenData))
+			if test.readOnly {
+				assert.NoFileExists(t, fmt.Sprintf("./testdata/res/%s/import.sh", test.dir))
+			}
 
 			for _, f := range test.filesToCheck {
 				expected, err := os.ReadFile(fmt.Sprintf("./testdata/%s/%s", test.dir, f))
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 3.1.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+data "akamai_cps_enrollment" "enrollment_id_1" {
+  enrollment_id = 1
+}
+
+locals {
+  enrollment_id    = 1
+  contract_id      = "ctr_1"
+  validation_type  = "dv"
+  certificate_type = "san"
+  common_name      = "test.akamai.com"
+  sans             = ["test.akamai.com"]
+}
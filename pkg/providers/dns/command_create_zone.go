@@ -27,8 +27,13 @@ import (
 	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/workspace"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -50,6 +55,12 @@ type configStruct struct {
 	createConfig           bool
 	recordNames            []string
 	importScript           bool
+	gtmDelegation          gtmDelegation
+	explicitRecords        bool
+	// recordTypes are types of exported recordsets, all types are exported when empty
+	recordTypes map[string]bool
+	// excludedRecordTypes are types of recordsets left out of the export
+	excludedRecordTypes map[string]bool
 }
 
 type fetchConfigStruct struct {
@@ -63,6 +74,7 @@ var contractid string
 
 var fullZoneImportList *zoneImportListStruct
 var fullZoneConfigMap map[string]Types
+var fullZoneForEachMap map[string]string
 
 // work defs
 var moduleFolder = "modules"
@@ -78,6 +90,7 @@ func CmdCreateZone(c *cli.Context) error {
 
 	sess := edgegrid.GetSession(ctx)
 	configDNS := dns.Client(sess)
+	configGTM := gtm.Client(sess)
 
 	// uppercase characters cause issues with TF and the generated config
 	zoneName = strings.ToLower(c.Args().Get(0))
@@ -88,11 +101,12 @@ func CmdCreateZone(c *cli.Context) error {
 	fmt.Println("Configuring Zone")
 	zoneObject, err := configDNS.GetZone(ctx, zoneName)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		fmt.Println("Error: " + err.Error())
-		return cli.Exit(color.RedString("Zone retrieval failed"), 1)
+		return cli.Exit(color.RedString("Zone retrieval failed"), exitcode.API)
 	}
 	contractid = zoneObject.ContractID // grab for use later
+	workspace.RecordObject(ctx, workspace.Object{Product: "dns", Name: zoneName})
 	// normalize zone name for zone resource name
 	resourceZoneName := normalizeResourceName(zoneName)
 	if configuration.shouldCreateImportList {
@@ -100,46 +114,38 @@ func CmdCreateZone(c *cli.Context) error {
 		if err != nil {
 			return err
 		}
-		term.Spinner().OK()
+		progress.Get(ctx).OK()
 	}
 
 	if configuration.createConfig {
 		// Read in resources list
 		zoneImportList, err := retrieveZoneImportList(resourceZoneName, configuration)
 		if err != nil {
-			term.Spinner().Fail()
-			return cli.Exit(color.RedString("Failed to read json zone resources file"), 1)
+			progress.Get(ctx).Fail()
+			return cli.Exit(color.RedString("Failed to read json zone resources file"), exitcode.IO)
 		}
-		// if segmenting recordsets by name, make sure module folder exists
-		if configuration.fetchConfig.ModSegment {
-			modulePath := filepath.Join(configuration.tfWorkPath, moduleFolder)
-			if !createDirectory(modulePath) {
-				term.Spinner().Fail()
-				return cli.Exit(color.RedString("Failed to create modules folder."), 1)
-			}
-		}
-		term.Spinner().Start("Creating zone configuration file ")
-		err = createZoneConfigFile(ctx, zoneImportList, resourceZoneName, zoneObject, configDNS, configuration)
+		progress.Get(ctx).Start("Creating zone configuration file ")
+		err = createZoneConfigFile(ctx, zoneImportList, resourceZoneName, zoneObject, configDNS, configGTM, configuration)
 		if err != nil {
-			term.Spinner().Fail()
+			progress.Get(ctx).Fail()
 			return err
 		}
 
-		err = createDNSVarsConfig(err, term, configuration.tfWorkPath)
+		err = createDNSVarsConfig(ctx, err, configuration.tfWorkPath)
 		if err != nil {
 			return err
 		}
-		term.Spinner().OK()
+		progress.Get(ctx).OK()
 	}
 
 	if configuration.importScript {
-		term.Spinner().Start("Creating zone import script file")
-		err := createImportScript(resourceZoneName, term, configuration)
+		progress.Get(ctx).Start("Creating zone import script file")
+		err := createImportScript(ctx, resourceZoneName, configuration)
 		if err != nil {
-			term.Spinner().Fail()
+			progress.Get(ctx).Fail()
 			return err
 		}
-		term.Spinner().OK()
+		progress.Get(ctx).OK()
 	}
 
 	fmt.Println("Zone configuration completed")
@@ -148,20 +154,20 @@ func CmdCreateZone(c *cli.Context) error {
 }
 
 func createImportList(ctx context.Context, term terminal.Terminal, configDNS dns.DNS, resourceZoneName string, configuration configStruct) error {
-	term.Spinner().Start("Inventorying zone and recordsets ")
+	progress.Get(ctx).Start("Inventorying zone and recordsets ")
 	recordsets, err := inventorZone(ctx, configDNS, configuration)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		fmt.Println("Error: " + err.Error())
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Creating Zone Resources list file ")
+	progress.Get(ctx).Start("Creating Zone Resources list file ")
 	// pathname and exists?
-	err = createZoneResourceListFile(resourceZoneName, recordsets, configuration.tfWorkPath)
+	err = createZoneResourceListFile(ctx, resourceZoneName, recordsets, configuration.tfWorkPath)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
 	return nil
@@ -197,20 +203,69 @@ func setConfiguration(c *cli.Context) configStruct {
 	if c.IsSet("importscript") {
 		executionConfig.importScript = true
 	}
+	if c.IsSet("explicit-records") {
+		executionConfig.explicitRecords = true
+	}
+	if c.IsSet("record-types") {
+		executionConfig.recordTypes = recordTypeSet(tools.SplitList(c.StringSlice("record-types")))
+	}
+	if c.IsSet("exclude-record-types") {
+		executionConfig.excludedRecordTypes = recordTypeSet(tools.SplitList(c.StringSlice("exclude-record-types")))
+	}
+	if c.IsSet("gtm-domain") {
+		executionConfig.gtmDelegation = gtmDelegation{
+			Domain:      c.String("gtm-domain"),
+			Subdomain:   c.String("gtm-subdomain"),
+			Nameservers: c.StringSlice("gtm-nameserver"),
+		}
+	}
 
 	return executionConfig
 }
 
-func createZoneConfigFile(ctx context.Context, zoneImportList *zoneImportListStruct, resourceZoneName string, zoneObject *dns.ZoneResponse, configDNS dns.DNS, configuration configStruct) error {
+// recordTypeSet returns the set of record types, in upper case
+func recordTypeSet(types []string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, recordType := range types {
+		set[strings.ToUpper(recordType)] = true
+	}
+	return set
+}
+
+// includesRecordType returns true if recordsets of the type are exported, i.e. the type is listed with record-types
+// flag, if it is set, and is not listed with exclude-record-types flag
+func (c configStruct) includesRecordType(recordType string) bool {
+	recordType = strings.ToUpper(recordType)
+	if len(c.recordTypes) > 0 && !c.recordTypes[recordType] {
+		return false
+	}
+	return !c.excludedRecordTypes[recordType]
+}
+
+// consolidateRecords returns true if structurally identical recordsets are consolidated into resources with for_each,
+// recordsets segmented into modules by name are always written separately
+func (c configStruct) consolidateRecords() bool {
+	return !c.explicitRecords && !c.fetchConfig.ModSegment
+}
+
+func createZoneConfigFile(ctx context.Context, zoneImportList *zoneImportListStruct, resourceZoneName string, zoneObject *dns.ZoneResponse, configDNS dns.DNS, configGTM gtm.GTM, configuration configStruct) error {
 	// see if configuration file already exists and exclude any resources already represented.
 	var configImportList *zoneImportListStruct
 	var zoneTypeMap map[string]map[string]bool
 	var err error
-	zoneTFfileHandle, zonetfConfig, err = openZoneConfigFile(resourceZoneName, configuration.tfWorkPath)
+	zoneTFfileHandle, zonetfConfig, err = openZoneConfigFile(ctx, resourceZoneName, configuration.tfWorkPath)
 	if err != nil {
-		return cli.Exit(color.RedString("Failed to open/create zone config file."), 1)
+		return cli.Exit(color.RedString("Failed to open/create zone config file."), exitcode.IO)
+	}
+	// recordsets consolidated by previous config generation into the existing zone config
+	fullZoneForEachMap = make(map[string]string)
+	if len(zonetfConfig) > 0 {
+		fullZoneForEachMap, err = retrieveForEachConfig(resourceZoneName, configuration.tfWorkPath)
+		if err != nil {
+			return cli.Exit(color.RedString("Failed to read consolidated recordsets file."), exitcode.IO)
+		}
 	}
-	configImportList, zoneTypeMap = reconcileZoneResourceTargets(zoneImportList, resourceZoneName, zonetfConfig)
+	configImportList, zoneTypeMap = reconcileZoneResourceTargets(zoneImportList, resourceZoneName, zonetfConfig, fullZoneForEachMap)
 	defer zoneTFfileHandle.Close()
 	fileUtils := fileUtilsProcessor{}
 
@@ -218,23 +273,31 @@ func createZoneConfigFile(ctx context.Context, zoneImportList *zoneImportListStr
 	if err != nil {
 		return err
 	}
-	err = fileUtils.appendRootModuleTF(zonetfConfig)
+	err = fileUtils.appendRootModuleTF(ctx, zonetfConfig)
 	if err != nil {
 		fmt.Println(err.Error())
-		return cli.Exit(color.RedString("Failed. Couldn't write to zone config"), 1)
+		return cli.Exit(color.RedString("Failed. Couldn't write to zone config"), exitcode.IO)
 	}
 
 	// process Recordsets.
-	fullZoneConfigMap, err = processRecordsets(ctx, configDNS, configImportList.Zone, resourceZoneName, zoneTypeMap, fileUtils, configuration)
+	fullZoneConfigMap, err = processRecordsets(ctx, configDNS, configImportList.Zone, resourceZoneName, zoneTypeMap, fullZoneForEachMap, fileUtils, configuration)
 	if err != nil {
-		return cli.Exit(color.RedString("Failed to process recordsets."), 1)
+		return cli.Exit(color.RedString("Failed to process recordsets."), exitcode.API)
+	}
+	if configuration.gtmDelegation.Domain != "" {
+		if err := checkGTMDelegation(ctx, configDNS, configGTM, zoneName, resourceZoneName, configuration.gtmDelegation, fileUtils, configuration); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Failed to check GTM delegation: %s", err)), exitcode.Of(err))
+		}
 	}
 	// Save config map for import script generation
 	resourceConfigFilename := createResourceConfigFilename(resourceZoneName, configuration.tfWorkPath)
-	err = saveResourceConfigFile(err, resourceConfigFilename)
+	err = saveResourceConfigFile(ctx, resourceConfigFilename)
 	if err != nil {
 		return err
 	}
+	if err := saveForEachConfigFile(ctx, fullZoneForEachMap, createForEachConfigFilename(resourceZoneName, configuration.tfWorkPath)); err != nil {
+		return cli.Exit(color.RedString("Unable to write consolidated recordsets file"), exitcode.IO)
+	}
 	return nil
 }
 
@@ -245,128 +308,98 @@ func calculateTfConfig(ctx context.Context, zoneObject *dns.ZoneResponse, resour
 		if strings.Contains(zonetfConfig, "module") && strings.Contains(zonetfConfig, "zonename") {
 			if !config.fetchConfig.ModSegment {
 				// already have a top level zone config and its modularized!
-				return cli.Exit(color.RedString("Failed. Existing zone config is modularized"), 1)
+				return cli.Exit(color.RedString("Failed. Existing zone config is modularized"), exitcode.General)
 			}
 		} else if config.fetchConfig.ModSegment {
 			// already have a top level zone config and its not modularized!
-			return cli.Exit(color.RedString("Failed. Existing zone config is not modularized"), 1)
+			return cli.Exit(color.RedString("Failed. Existing zone config is not modularized"), exitcode.General)
 		}
 	} else {
 		// if tf pre existed, zone has to exist by definition
 		zonetfConfig, err = processZone(ctx, zoneObject, resourceZoneName, config.fetchConfig.ModSegment, fileUtils, config.tfWorkPath)
 		if err != nil {
 			fmt.Println(err.Error())
-			return cli.Exit(color.RedString("Failed. Couldn't initialize zone config"), 1)
+			return cli.Exit(color.RedString("Failed. Couldn't initialize zone config"), exitcode.IO)
 		}
 	}
 	return nil
 }
 
-func saveResourceConfigFile(err error, resourceConfigFilename string) error {
+func saveResourceConfigFile(ctx context.Context, resourceConfigFilename string) error {
 	resourceConfigJSON, err := json.MarshalIndent(&fullZoneConfigMap, "", "  ")
 	if err != nil {
-		return cli.Exit(color.RedString("Unable to generate json formatted zone config"), 1)
+		return cli.Exit(color.RedString("Unable to generate json formatted zone config"), exitcode.Template)
 	}
-	f, err := os.Create(resourceConfigFilename)
+	err = templates.GetSink(ctx).WriteFile(resourceConfigFilename, tools.ApplyLineEndings(resourceConfigJSON))
 	if err != nil {
-		return cli.Exit(color.RedString("Unable to create resource config file"), 1)
-	}
-	defer f.Close()
-	_, err = f.WriteString(string(resourceConfigJSON))
-	if err != nil {
-		return cli.Exit(color.RedString("Unable to write zone resource config file"), 1)
-	}
-	err = f.Sync()
-	if err != nil {
-		return err
+		return cli.Exit(color.RedString("Unable to write zone resource config file"), exitcode.IO)
 	}
 	return nil
 }
 
-func createDNSVarsConfig(err error, term terminal.Terminal, tfWorkPath string) error {
+func createDNSVarsConfig(ctx context.Context, err error, tfWorkPath string) error {
 	// Need create dnsvars.tf dependency
 	dnsvarsFilename := filepath.Join(tfWorkPath, "dnsvars.tf")
-	// see if exists already.
-	//if _, err := os.Stat(dnsvarsFilename); err != nil {
-	dnsvarsHandle, err := os.Create(dnsvarsFilename)
-	//}
-	if err != nil {
-		term.Spinner().Fail()
-		return cli.Exit(color.RedString("Unable to create dnsvars config file"), 1)
-	}
-	defer dnsvarsHandle.Close()
-	_, err = dnsvarsHandle.WriteString(fmt.Sprintf(useTemplate(nil, "dnsvars.tmpl", true), contractid))
+	err = templates.GetSink(ctx).WriteFile(dnsvarsFilename, tools.ApplyLineEndings(templates.RenameVariables([]byte(fmt.Sprintf(useTemplate(nil, "dnsvars.tmpl", true), contractid)))))
 	if err != nil {
-		term.Spinner().Fail()
-		return cli.Exit(color.RedString("Unable to write dnsvars config file"), 1)
-	}
-	err = dnsvarsHandle.Sync()
-	if err != nil {
-		return err
+		progress.Get(ctx).Fail()
+		return cli.Exit(color.RedString("Unable to write dnsvars config file"), exitcode.IO)
 	}
 	return nil
 }
 
-func createImportScript(resourceZoneName string, term terminal.Terminal, configuration configStruct) error {
+func createImportScript(ctx context.Context, resourceZoneName string, configuration configStruct) error {
 	fullZoneConfigMap, _ = retrieveZoneResourceConfig(resourceZoneName, configuration)
+	if !configuration.createConfig {
+		forEach, err := retrieveForEachConfig(resourceZoneName, configuration.tfWorkPath)
+		if err != nil {
+			return cli.Exit(color.RedString("Failed to read consolidated recordsets file"), exitcode.IO)
+		}
+		fullZoneForEachMap = forEach
+	}
 	importScriptFilename := filepath.Join(configuration.tfWorkPath, resourceZoneName+"_resource_import.script")
 	if _, err := os.Stat(importScriptFilename); err == nil {
 		// File exists. Bail
-		term.Spinner().OK()
+		progress.Get(ctx).OK()
 	}
-	scriptContent, err := buildZoneImportScript(zoneName, fullZoneConfigMap, resourceZoneName)
+	scriptContent, err := buildZoneImportScript(zoneName, fullZoneConfigMap, fullZoneForEachMap, resourceZoneName)
 
 	if err != nil {
-		return cli.Exit(color.RedString("Import script content generation failed"), 1)
+		return cli.Exit(color.RedString("Import script content generation failed"), exitcode.Template)
 	}
-	f, err := os.Create(importScriptFilename)
-	if err != nil {
-		return cli.Exit(color.RedString("Unable to create import script file"), 1)
+	if err := templates.ValidateImports([]byte(scriptContent)); err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
-	defer f.Close()
-	_, err = f.WriteString(scriptContent)
+	err = templates.GetSink(ctx).WriteFile(importScriptFilename, tools.ApplyLineEndings([]byte(scriptContent)))
 	if err != nil {
-		return cli.Exit(color.RedString("Unable to write import script file"), 1)
-	}
-	err = f.Sync()
-	if err != nil {
-		return err
+		return cli.Exit(color.RedString("Unable to write import script file"), exitcode.IO)
 	}
 	return nil
 }
 
-func createZoneResourceListFile(resourceZoneName string, recordsets map[string]Types, tfWorkPath string) error {
+func createZoneResourceListFile(ctx context.Context, resourceZoneName string, recordsets map[string]Types, tfWorkPath string) error {
 	importListFilename := createImportListFilename(resourceZoneName, tfWorkPath)
 	if _, err := os.Stat(importListFilename); err == nil {
-		return cli.Exit(color.RedString("Resource list file exists. Remove to continue."), 1)
+		return cli.Exit(color.RedString("Resource list file exists. Remove to continue."), exitcode.IO)
 	}
 	fullZoneImportList = &zoneImportListStruct{}
 	fullZoneImportList.Zone = zoneName
 	fullZoneImportList.Recordsets = recordsets
-	err := saveImportListToFile(importListFilename)
+	err := saveImportListToFile(ctx, importListFilename)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func saveImportListToFile(importListFilename string) error {
+func saveImportListToFile(ctx context.Context, importListFilename string) error {
 	importListJSON, err := json.MarshalIndent(fullZoneImportList, "", "  ")
 	if err != nil {
-		return cli.Exit(color.RedString("Unable to generate json formatted zone resource list"), 1)
-	}
-	f, err := os.Create(importListFilename)
-	if err != nil {
-		return cli.Exit(color.RedString("Unable to create zone resources file"), 1)
+		return cli.Exit(color.RedString("Unable to generate json formatted zone resource list"), exitcode.Template)
 	}
-	defer f.Close()
-	_, err = f.WriteString(string(importListJSON))
+	err = templates.GetSink(ctx).WriteFile(importListFilename, tools.ApplyLineEndings(importListJSON))
 	if err != nil {
-		return cli.Exit(color.RedString("Unable to write zone resources file"), 1)
-	}
-	err = f.Sync()
-	if err != nil {
-		return err
+		return cli.Exit(color.RedString("Unable to write zone resources file"), exitcode.IO)
 	}
 	return nil
 }
@@ -377,7 +410,7 @@ func inventorZone(ctx context.Context, configDNS dns.DNS, configuration configSt
 	if len(configuration.recordNames) == 0 {
 		recordsetNames, err := configDNS.GetZoneNames(ctx, zoneName)
 		if err != nil {
-			return nil, cli.Exit(color.RedString("Zone Name retrieval failed"), 1)
+			return nil, cli.Exit(color.RedString("Zone Name retrieval failed"), exitcode.API)
 		}
 		configuration.recordNames = recordsetNames.Names
 	}
@@ -387,9 +420,19 @@ func inventorZone(ctx context.Context, configDNS dns.DNS, configuration configSt
 		} else {
 			nameTypesResp, err := configDNS.GetZoneNameTypes(ctx, zname, zoneName)
 			if err != nil {
-				return nil, cli.Exit(color.RedString("Zone Name types retrieval failed"), 1)
+				return nil, cli.Exit(color.RedString("Zone Name types retrieval failed"), exitcode.API)
 			}
-			recordsets[zname] = nameTypesResp.Types
+			types := make(Types, 0, len(nameTypesResp.Types))
+			for _, recordType := range nameTypesResp.Types {
+				if configuration.includesRecordType(recordType) {
+					types = append(types, recordType)
+				}
+			}
+			// names without recordsets of exported types are left out of the resources file
+			if len(types) == 0 && len(nameTypesResp.Types) > 0 {
+				continue
+			}
+			recordsets[zname] = types
 		}
 	}
 	return recordsets, nil
@@ -413,38 +456,19 @@ func createNamedModulePath(modName, tfWorkPath string) string {
 	return fpath
 }
 
-// Utility func
-func createDirectory(dirName string) bool {
-
-	stat, err := os.Stat(dirName)
-	if err == nil && stat.IsDir() {
-		return true
-	}
-	if os.IsNotExist(err) {
-		errDir := os.MkdirAll(dirName, 0755)
-		if errDir != nil {
-			panic(err)
-		}
-		return true
-	}
-	if stat.Mode().IsRegular() {
-		return false
-	}
-
-	return false
-}
-
-func buildZoneImportScript(zone string, zoneConfigMap map[string]Types, resourceName string) (string, error) {
+func buildZoneImportScript(zone string, zoneConfigMap map[string]Types, forEach map[string]string, resourceName string) (string, error) {
 	data := ImportData{
-		Zone:          zone,
-		ZoneConfigMap: zoneConfigMap,
-		ResourceName:  resourceName,
+		Zone:           zone,
+		ZoneConfigMap:  zoneConfigMap,
+		ForEachRecords: forEach,
+		ResourceName:   resourceName,
 	}
-	return useTemplate(&data, "import-script.tmpl", true), nil
+	return string(templates.OrderImports([]byte(useTemplate(&data, "import-script.tmpl", true)))), nil
 }
 
-// remove any resources already present in existing zone tf configuration
-func reconcileZoneResourceTargets(zoneImportList *zoneImportListStruct, zoneName, tfConfig string) (*zoneImportListStruct, map[string]map[string]bool) {
+// remove any resources already present in existing zone tf configuration, including recordsets consolidated into
+// resources with for_each
+func reconcileZoneResourceTargets(zoneImportList *zoneImportListStruct, zoneName, tfConfig string, forEach map[string]string) (*zoneImportListStruct, map[string]map[string]bool) {
 
 	zoneTypeMap := make(map[string]map[string]bool)
 	// populate zoneTypeMap
@@ -455,6 +479,9 @@ func reconcileZoneResourceTargets(zoneImportList *zoneImportListStruct, zoneName
 		revisedTypeList := make([]string, 0, len(typeList))
 		for _, ntype := range typeList {
 			normalName := createUniqueRecordsetName(zoneName, zname, ntype)
+			if blockName, ok := forEach[forEachKey(zname, ntype)]; ok {
+				normalName = blockName
+			}
 			if !strings.Contains(tfConfig, `"`+normalName+`"`) {
 				typeMap[ntype] = true
 				revisedTypeList = append(revisedTypeList, ntype)
@@ -470,8 +497,14 @@ func reconcileZoneResourceTargets(zoneImportList *zoneImportListStruct, zoneName
 
 }
 
-func openZoneConfigFile(zoneName string, tfWorkPath string) (*os.File, string, error) {
+// openZoneConfigFile opens the zone config file, which is written directly and not through the output sink, as
+// generated configuration is appended to it; a new file is recorded in the journal of the context
+func openZoneConfigFile(ctx context.Context, zoneName string, tfWorkPath string) (*os.File, string, error) {
 	tfFilename := tools.CreateTFFilename(zoneName, tfWorkPath)
+	if err := templates.Track(ctx, tfFilename); err != nil {
+		fmt.Println(err.Error())
+		return nil, "", err
+	}
 	var tfHandle *os.File
 	tfHandle, err := os.OpenFile(tfFilename, os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil && err != io.EOF {
@@ -0,0 +1,30 @@
+package dns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventorZoneRecordTypes(t *testing.T) {
+	defer func(name string) { zoneName = name }(zoneName)
+	zoneName = "example.com"
+
+	m := new(dns.Mock)
+	m.On("GetZoneNames", mock.Anything, "example.com").
+		Return(&dns.ZoneNamesResponse{Names: []string{"example.com", "_acme-challenge.example.com"}}, nil).Once()
+	m.On("GetZoneNameTypes", mock.Anything, "example.com", "example.com").
+		Return(&dns.ZoneNameTypesResponse{Types: []string{"A", "MX", "TXT"}}, nil).Once()
+	m.On("GetZoneNameTypes", mock.Anything, "_acme-challenge.example.com", "example.com").
+		Return(&dns.ZoneNameTypesResponse{Types: []string{"TXT"}}, nil).Once()
+
+	config := configStruct{recordTypes: recordTypeSet([]string{"a", "txt"}), excludedRecordTypes: recordTypeSet([]string{"txt"})}
+	recordsets, err := inventorZone(context.Background(), m, config)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Types{"example.com": {"A"}}, recordsets)
+	m.AssertExpectations(t)
+}
@@ -0,0 +1,111 @@
+package dns
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/batch"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrReadingZonesFile is returned when the list of zones given with zones-file flag cannot be read
+var ErrReadingZonesFile = exitcode.New(exitcode.IO, "unable to read zones file")
+
+// stdin is read by zones-file flag set to '-'
+var stdin io.Reader = os.Stdin
+
+// CmdCreateZones is an entrypoint to export-zone command with zones-file flag, which exports every zone listed in the
+// file, or in standard input for '-', into its own subdirectory of tfworkpath named after the zone, like export-manifest
+// exports its objects
+func CmdCreateZones(c *cli.Context) error {
+	// tfWorkPath is a root directory under which each zone is exported into its own subdirectory
+	var tfWorkPath = "./"
+	if c.IsSet("tfworkpath") {
+		tfWorkPath = c.String("tfworkpath")
+	}
+
+	// record names and GTM delegations are names within a single zone
+	for _, flag := range []string{"recordname", "gtm-domain", "gtm-subdomain", "gtm-nameserver"} {
+		if c.IsSet(flag) {
+			return cli.Exit(color.RedString(fmt.Sprintf("%s flag cannot be used with zones-file flag", flag)), exitcode.General)
+		}
+	}
+
+	zones, err := readZonesFile(c.String("zones-file"))
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	return batch.Run(c, zoneObjects(zones, zoneArgs(c)), tfWorkPath)
+}
+
+// readZonesFile returns zones listed in the file, one per line, or in standard input when path is '-'; blank lines and
+// lines starting with '#' are skipped, zones are lowercased and listed once in the order of the file
+func readZonesFile(path string) ([]string, error) {
+	in := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrReadingZonesFile, err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		in = f
+	}
+
+	var zones []string
+	listed := make(map[string]bool)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		zone := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if zone == "" || strings.HasPrefix(zone, "#") || listed[zone] {
+			continue
+		}
+		listed[zone] = true
+		zones = append(zones, zone)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingZonesFile, err)
+	}
+	return zones, nil
+}
+
+// zoneObjects returns objects exporting the zones with the arguments, each into a subdirectory named after the zone
+func zoneObjects(zones []string, args []string) []manifest.Object {
+	objects := make([]manifest.Object, 0, len(zones))
+	for _, zone := range zones {
+		zoneArgs := append(append([]string{}, args...), zone)
+		objects = append(objects, manifest.Object{
+			Product:  "dns",
+			Name:     zone,
+			Command:  "export-zone",
+			Args:     zoneArgs,
+			Selected: true,
+			Dir:      zone,
+		})
+	}
+	return objects
+}
+
+// zoneArgs returns flags of the command which are passed to exports of single zones
+func zoneArgs(c *cli.Context) []string {
+	var args []string
+	for _, flag := range []string{"resources", "createconfig", "importscript", "segmentconfig", "explicit-records", "configonly", "namesonly"} {
+		if c.Bool(flag) {
+			args = append(args, "--"+flag)
+		}
+	}
+	for _, flag := range []string{"record-types", "exclude-record-types"} {
+		if c.IsSet(flag) {
+			args = append(args, "--"+flag, strings.Join(tools.SplitList(c.StringSlice(flag)), ","))
+		}
+	}
+	return args
+}
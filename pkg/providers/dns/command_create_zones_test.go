@@ -0,0 +1,100 @@
+package dns
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestReadZonesFile(t *testing.T) {
+	content := "example.com\n\n  # legacy zones\nExample.org \nexample.com\r\ntest.example.net"
+	expected := []string{"example.com", "example.org", "test.example.net"}
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "zones.txt")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		zones, err := readZonesFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, zones)
+	})
+
+	t.Run("standard input", func(t *testing.T) {
+		defer func(r io.Reader) { stdin = r }(stdin)
+		stdin = strings.NewReader(content)
+		zones, err := readZonesFile("-")
+		require.NoError(t, err)
+		assert.Equal(t, expected, zones)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := readZonesFile(filepath.Join(t.TempDir(), "missing.txt"))
+		assert.True(t, errors.Is(err, ErrReadingZonesFile), "expected: %s; got: %s", ErrReadingZonesFile, err)
+	})
+}
+
+func TestZoneObjects(t *testing.T) {
+	objects := zoneObjects([]string{"example.com", "example.org"}, []string{"--createconfig", "--importscript"})
+	assert.Equal(t, []manifest.Object{
+		{
+			Product:  "dns",
+			Name:     "example.com",
+			Command:  "export-zone",
+			Args:     []string{"--createconfig", "--importscript", "example.com"},
+			Selected: true,
+			Dir:      "example.com",
+		},
+		{
+			Product:  "dns",
+			Name:     "example.org",
+			Command:  "export-zone",
+			Args:     []string{"--createconfig", "--importscript", "example.org"},
+			Selected: true,
+			Dir:      "example.org",
+		},
+	}, objects)
+}
+
+func TestZoneArgs(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"no flags": {
+			args: []string{"--zones-file", "zones.txt"},
+		},
+		"config and import script": {
+			args:     []string{"--zones-file", "zones.txt", "--createconfig", "--configonly", "--importscript"},
+			expected: []string{"--createconfig", "--importscript", "--configonly"},
+		},
+		"resources with segments": {
+			args:     []string{"--zones-file", "zones.txt", "--resources", "--segmentconfig", "--namesonly", "--explicit-records"},
+			expected: []string{"--resources", "--segmentconfig", "--explicit-records", "--namesonly"},
+		},
+		"record types": {
+			args:     []string{"--zones-file", "zones.txt", "--createconfig", "--record-types", "A, AAAA", "--record-types", "CNAME", "--exclude-record-types", "TXT"},
+			expected: []string{"--createconfig", "--record-types", "A,AAAA,CNAME", "--exclude-record-types", "TXT"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set := flag.NewFlagSet("export-zone", flag.ContinueOnError)
+			set.String("zones-file", "", "")
+			for _, name := range []string{"resources", "createconfig", "importscript", "segmentconfig", "explicit-records", "configonly", "namesonly"} {
+				set.Bool(name, false, "")
+			}
+			set.Var(cli.NewStringSlice(), "record-types", "")
+			set.Var(cli.NewStringSlice(), "exclude-record-types", "")
+			require.NoError(t, set.Parse(test.args))
+			assert.Equal(t, test.expected, zoneArgs(cli.NewContext(cli.NewApp(), set, nil)))
+		})
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/akamai/cli-terraform/pkg/secrets"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
 )
 
 type fileUtils interface {
 	createModuleTF(ctx context.Context, modName string, content string, tfWorkPath string) error
-	appendRootModuleTF(configText string) error
+	appendRootModuleTF(ctx context.Context, configText string) error
 }
 
 type fileUtilsProcessor struct {
@@ -22,33 +25,33 @@ func (fileUtilsProcessor) createModuleTF(ctx context.Context, modName, content,
 	term := terminal.Get(ctx)
 	term.Printf("Creating zone name %s module configuration file...", modName)
 	namedmodulePath := createNamedModulePath(modName, tfWorkPath)
-	if !createDirectory(namedmodulePath) {
-		return fmt.Errorf("failed to create name module folder: %s", namedmodulePath)
-	}
 	moduleFilename := filepath.Join(namedmodulePath, normalizeResourceName(modName)+".tf")
 	if _, err := os.Stat(moduleFilename); err == nil {
 		// File exists.
 		return fmt.Errorf("module configuration file already exists: %s", moduleFilename)
 	}
-	f, err := os.Create(moduleFilename)
+	sink := templates.GetSink(ctx)
+	filtered, err := secrets.Filter(sink, moduleFilename, templates.RenameVariables([]byte(content)))
 	if err != nil {
-		return fmt.Errorf("failed to create name module configuration file: %s", namedmodulePath)
+		return err
 	}
-	defer f.Close()
-	_, err = f.WriteString(content)
-	if err != nil {
+	// the sink creates the module folder
+	if err = sink.WriteFile(moduleFilename, tools.ApplyLineEndings(filtered)); err != nil {
 		return fmt.Errorf("failed to write name module configuration: %s", namedmodulePath)
 	}
-	f.Sync()
 
 	return nil
 }
 
 // Flush string to root module TF file
-func (fileUtilsProcessor) appendRootModuleTF(configText string) error {
+func (fileUtilsProcessor) appendRootModuleTF(ctx context.Context, configText string) error {
 
 	// save top level Zone TF config
-	_, err := zoneTFfileHandle.Write([]byte(configText))
+	filtered, err := secrets.Filter(templates.GetSink(ctx), zoneTFfileHandle.Name(), templates.RenameVariables([]byte(configText)))
+	if err != nil {
+		return err
+	}
+	_, err = zoneTFfileHandle.Write(tools.ApplyLineEndings(filtered))
 	if err != nil {
 		return fmt.Errorf("failed to save zone configuration file")
 	}
@@ -23,7 +23,7 @@ func (m *fileutilsmock) createModuleTF(_ context.Context, modName string, conten
 	args := m.Called(modName, content, tfWorkPath)
 	return args.Error(0)
 }
-func (m *fileutilsmock) appendRootModuleTF(configText string) error {
+func (m *fileutilsmock) appendRootModuleTF(_ context.Context, configText string) error {
 	m.appendRootArg = configText
 	args := m.Called(configText)
 	return args.Error(0)
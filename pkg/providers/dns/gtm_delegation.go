@@ -0,0 +1,160 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+)
+
+// gtmDelegation describes a subdomain of the zone which is delegated to a GTM domain exported together with the zone
+type gtmDelegation struct {
+	// Domain is the name of the GTM domain
+	Domain string
+	// Subdomain is the name in the zone delegated to the GTM domain, the GTM domain itself is delegated when empty
+	Subdomain string
+	// Nameservers are targets of NS records of the delegation, NS records cannot be generated when empty
+	Nameservers []string
+}
+
+// delegationTTL is the TTL of generated NS records
+const delegationTTL = 86400
+
+var (
+	// ErrFetchingGTMDomain is returned when GTM domain delegated from the zone could not be fetched
+	ErrFetchingGTMDomain = exitcode.New(exitcode.API, "unable to fetch GTM domain")
+	// ErrInvalidGTMDelegation is returned when the delegated subdomain does not belong to the zone
+	ErrInvalidGTMDelegation = exitcode.New(exitcode.General, "invalid GTM delegation")
+	// ErrFetchingDelegationRecords is returned when NS or glue records of the delegation could not be fetched
+	ErrFetchingDelegationRecords = exitcode.New(exitcode.API, "unable to fetch records of GTM delegation")
+)
+
+// checkGTMDelegation cross-validates that the zone contains NS records delegating the subdomain to the GTM domain and
+// glue records of name servers within the zone; missing NS records are generated into the zone configuration when name
+// servers are known, other inconsistencies are reported as warnings
+func checkGTMDelegation(ctx context.Context, client dns.DNS, gtmClient gtm.GTM, zone, resourceZoneName string, delegation gtmDelegation, fileUtils fileUtils, config configStruct) error {
+	if _, err := gtmClient.GetDomain(ctx, delegation.Domain); err != nil {
+		return fmt.Errorf("%w: '%s': %s", ErrFetchingGTMDomain, delegation.Domain, err)
+	}
+	subdomain := normalizeDNSName(delegation.Subdomain)
+	if subdomain == "" {
+		subdomain = normalizeDNSName(delegation.Domain)
+	}
+	if subdomain == zone || !inZone(subdomain, zone) {
+		return fmt.Errorf("%w: '%s' is not a subdomain of zone '%s', set the delegated subdomain with --gtm-subdomain", ErrInvalidGTMDelegation, subdomain, zone)
+	}
+	expected := make([]string, 0, len(delegation.Nameservers))
+	for _, ns := range delegation.Nameservers {
+		expected = append(expected, normalizeDNSName(ns))
+	}
+	sort.Strings(expected)
+
+	record, err := getRecord(ctx, client, zone, subdomain, "NS")
+	if err != nil {
+		return err
+	}
+	var targets []string
+	switch {
+	case record != nil:
+		for _, target := range record.Target {
+			targets = append(targets, normalizeDNSName(target))
+		}
+		sort.Strings(targets)
+		if len(expected) > 0 && strings.Join(targets, ",") != strings.Join(expected, ",") {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "zone",
+				Object:  subdomain,
+				Reason: fmt.Sprintf("NS records delegating to GTM domain '%s' point to %s instead of %s, they are exported as they are",
+					delegation.Domain, strings.Join(targets, ", "), strings.Join(expected, ", ")),
+			})
+		}
+	case len(expected) == 0:
+		warnings.Report(ctx, warnings.Warning{
+			Product: "zone",
+			Object:  subdomain,
+			Reason:  fmt.Sprintf("NS records delegating to GTM domain '%s' are missing, set --gtm-nameserver to generate them", delegation.Domain),
+		})
+		return nil
+	default:
+		targets = expected
+		if err := writeDelegationRecord(ctx, resourceZoneName, subdomain, targets, fileUtils, config); err != nil {
+			return err
+		}
+		warnings.Report(ctx, warnings.Warning{
+			Product: "zone",
+			Object:  subdomain,
+			Reason:  fmt.Sprintf("NS records delegating to GTM domain '%s' are missing, they were generated and are created on apply", delegation.Domain),
+		})
+	}
+
+	for _, target := range targets {
+		if !inZone(target, zone) {
+			continue
+		}
+		hasGlue := false
+		for _, recordType := range []string{"A", "AAAA"} {
+			glue, err := getRecord(ctx, client, zone, target, recordType)
+			if err != nil {
+				return err
+			}
+			hasGlue = hasGlue || glue != nil
+		}
+		if !hasGlue {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "zone",
+				Object:  target,
+				Reason:  fmt.Sprintf("name server of GTM delegation '%s' is within the zone, but has no A or AAAA glue records", subdomain),
+			})
+		}
+	}
+	return nil
+}
+
+// writeDelegationRecord adds NS record set delegating the subdomain to the zone configuration
+func writeDelegationRecord(ctx context.Context, resourceZoneName, subdomain string, targets []string, fileUtils fileUtils, config configStruct) error {
+	quoted := make([]string, 0, len(targets))
+	for _, target := range targets {
+		quoted = append(quoted, fmt.Sprintf("%q", target))
+	}
+	modName := createUniqueRecordsetName(resourceZoneName, subdomain, "NS")
+	data := RecordsetData{
+		BlockName: modName,
+		ResourceFields: map[string]string{
+			"name":       fmt.Sprintf("%q", subdomain),
+			"recordtype": `"NS"`,
+			"ttl":        fmt.Sprint(delegationTTL),
+			"target":     "[" + strings.Join(quoted, ", ") + "]",
+		},
+		TfWorkPath: config.tfWorkPath,
+	}
+	return writeRecordset(ctx, modName, data, fileUtils, config)
+}
+
+// getRecord returns record set of the given name and type, nil is returned if the zone does not contain it
+func getRecord(ctx context.Context, client dns.DNS, zone, name, recordType string) (*dns.RecordBody, error) {
+	record, err := client.GetRecord(ctx, zone, name, recordType)
+	if err != nil {
+		var e *dns.Error
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %s %s: %s", ErrFetchingDelegationRecords, name, recordType, err)
+	}
+	return record, nil
+}
+
+// inZone reports whether the name is the zone or a name within it
+func inZone(name, zone string) bool {
+	return name == zone || strings.HasSuffix(name, "."+zone)
+}
+
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
@@ -0,0 +1,124 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckGTMDelegation(t *testing.T) {
+	notFound := &dns.Error{StatusCode: http.StatusNotFound, Title: "Not Found"}
+	tests := map[string]struct {
+		delegation     gtmDelegation
+		init           func(*dns.Mock, *gtm.Mock, *fileutilsmock)
+		expectWarnings []string
+		expectPath     string
+		withError      error
+	}{
+		"delegation with glue records": {
+			delegation: gtmDelegation{Domain: "gtm.example.com", Nameservers: []string{"ns1.example.com."}},
+			init: func(d *dns.Mock, g *gtm.Mock, _ *fileutilsmock) {
+				g.On("GetDomain", mock.Anything, "gtm.example.com").Return(&gtm.Domain{Name: "gtm.example.com"}, nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "gtm.example.com", "NS").
+					Return(&dns.RecordBody{Name: "gtm.example.com", RecordType: "NS", Target: []string{"NS1.example.com."}}, nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "ns1.example.com", "A").
+					Return(&dns.RecordBody{Target: []string{"192.0.2.1"}}, nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "ns1.example.com", "AAAA").Return(nil, notFound).Once()
+			},
+		},
+		"missing NS records are generated": {
+			delegation: gtmDelegation{Domain: "example.akadns.net", Subdomain: "gtm.example.com", Nameservers: []string{"ns1.example.com", "a1-1.akagtm.org"}},
+			init: func(d *dns.Mock, g *gtm.Mock, f *fileutilsmock) {
+				g.On("GetDomain", mock.Anything, "example.akadns.net").Return(&gtm.Domain{Name: "example.akadns.net"}, nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "gtm.example.com", "NS").Return(nil, notFound).Once()
+				f.On("appendRootModuleTF", mock.Anything).Return(nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "ns1.example.com", "A").Return(nil, notFound).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "ns1.example.com", "AAAA").Return(nil, notFound).Once()
+			},
+			expectWarnings: []string{
+				"zone 'gtm.example.com': NS records delegating to GTM domain 'example.akadns.net' are missing, they were generated and are created on apply",
+				"zone 'ns1.example.com': name server of GTM delegation 'gtm.example.com' is within the zone, but has no A or AAAA glue records",
+			},
+			expectPath: "./testdata/gtm_delegation/expected_ns_record.tf",
+		},
+		"missing NS records without name servers": {
+			delegation: gtmDelegation{Domain: "gtm.example.com"},
+			init: func(d *dns.Mock, g *gtm.Mock, _ *fileutilsmock) {
+				g.On("GetDomain", mock.Anything, "gtm.example.com").Return(&gtm.Domain{Name: "gtm.example.com"}, nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "gtm.example.com", "NS").Return(nil, notFound).Once()
+			},
+			expectWarnings: []string{
+				"zone 'gtm.example.com': NS records delegating to GTM domain 'gtm.example.com' are missing, set --gtm-nameserver to generate them",
+			},
+		},
+		"NS records pointing elsewhere": {
+			delegation: gtmDelegation{Domain: "gtm.example.com", Nameservers: []string{"a1-1.akagtm.org"}},
+			init: func(d *dns.Mock, g *gtm.Mock, _ *fileutilsmock) {
+				g.On("GetDomain", mock.Anything, "gtm.example.com").Return(&gtm.Domain{Name: "gtm.example.com"}, nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "gtm.example.com", "NS").
+					Return(&dns.RecordBody{Target: []string{"ns.other.net."}}, nil).Once()
+			},
+			expectWarnings: []string{
+				"zone 'gtm.example.com': NS records delegating to GTM domain 'gtm.example.com' point to ns.other.net instead of a1-1.akagtm.org, they are exported as they are",
+			},
+		},
+		"GTM domain outside of the zone": {
+			delegation: gtmDelegation{Domain: "example.akadns.net"},
+			init: func(_ *dns.Mock, g *gtm.Mock, _ *fileutilsmock) {
+				g.On("GetDomain", mock.Anything, "example.akadns.net").Return(&gtm.Domain{Name: "example.akadns.net"}, nil).Once()
+			},
+			withError: ErrInvalidGTMDelegation,
+		},
+		"GTM domain not found": {
+			delegation: gtmDelegation{Domain: "gtm.example.com"},
+			init: func(_ *dns.Mock, g *gtm.Mock, _ *fileutilsmock) {
+				g.On("GetDomain", mock.Anything, "gtm.example.com").Return(nil, errors.New("not found")).Once()
+			},
+			withError: ErrFetchingGTMDomain,
+		},
+		"fetching NS records fails": {
+			delegation: gtmDelegation{Domain: "gtm.example.com"},
+			init: func(d *dns.Mock, g *gtm.Mock, _ *fileutilsmock) {
+				g.On("GetDomain", mock.Anything, "gtm.example.com").Return(&gtm.Domain{Name: "gtm.example.com"}, nil).Once()
+				d.On("GetRecord", mock.Anything, "example.com", "gtm.example.com", "NS").
+					Return(nil, &dns.Error{StatusCode: http.StatusForbidden}).Once()
+			},
+			withError: ErrFetchingDelegationRecords,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, g, f := new(dns.Mock), new(gtm.Mock), new(fileutilsmock)
+			test.init(d, g, f)
+			collector := warnings.NewCollector()
+			ctx := warnings.WithCollector(context.Background(), collector)
+
+			err := checkGTMDelegation(ctx, d, g, "example.com", "example_com", test.delegation, f, configStruct{})
+			d.AssertExpectations(t)
+			g.AssertExpectations(t)
+			f.AssertExpectations(t)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			var reported []string
+			for _, w := range collector.Warnings() {
+				reported = append(reported, w.String())
+			}
+			assert.Equal(t, test.expectWarnings, reported)
+			if test.expectPath != "" {
+				require.FileExists(t, test.expectPath)
+				assertFileWithContent(t, test.expectPath, f.appendRootArg)
+			}
+		})
+	}
+}
@@ -8,7 +8,15 @@ import (
 
 func TestCreatingImportingScript(t *testing.T) {
 	zoneConfigMap := map[string]Types{"a": {"b", "c", "d"}, "e": {"f", "g", "h"}}
-	importScript, err := buildZoneImportScript("some-zone", zoneConfigMap, "resource_name")
+	importScript, err := buildZoneImportScript("some-zone", zoneConfigMap, nil, "resource_name")
 	require.NoError(t, err)
 	assertFileWithContent(t, "./testdata/import_script/import.sh", importScript)
 }
+
+func TestCreatingImportingScriptForEach(t *testing.T) {
+	zoneConfigMap := map[string]Types{"a": {"b", "c"}, "e": {"c"}}
+	forEach := map[string]string{"a#c": "resource_name_c_records_1", "e#c": "resource_name_c_records_1"}
+	importScript, err := buildZoneImportScript("some-zone", zoneConfigMap, forEach, "resource_name")
+	require.NoError(t, err)
+	assertFileWithContent(t, "./testdata/import_script/import_foreach.sh", importScript)
+}
@@ -7,6 +7,7 @@ import (
 	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/cli-terraform/pkg/templates"
 )
 
 //go:embed templates/*
@@ -40,8 +41,10 @@ type (
 	ImportData struct {
 		Zone          string
 		ZoneConfigMap map[string]Types
-		ResourceName  string
-		TfWorkPath    string
+		// ForEachRecords maps recordsets consolidated with for_each, keyed by name and type joined with '#', to their resources
+		ForEachRecords map[string]string
+		ResourceName   string
+		TfWorkPath     string
 	}
 )
 
@@ -50,7 +53,7 @@ var funcs = template.FuncMap{
 	"checkForResource":          checkForResource,
 	"createUniqueRecordsetName": createUniqueRecordsetName,
 }
-var tmpl = template.Must(template.New("template").Funcs(funcs).ParseFS(templateFiles, "**/*.tmpl"))
+var tmpl = template.Must(template.New("template").Funcs(templates.Funcs()).Funcs(funcs).ParseFS(templateFiles, "**/*.tmpl"))
 
 func useTemplate(data interface{}, templateName string, trimBeginning bool) string {
 	buf := bytes.Buffer{}
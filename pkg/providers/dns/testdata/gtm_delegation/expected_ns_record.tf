@@ -0,0 +1,8 @@
+
+resource "akamai_dns_record" "example_com_gtm_example_com_NS" {
+    zone = local.zone
+    name = "gtm.example.com"
+    recordtype = "NS"
+    target = ["a1-1.akagtm.org", "ns1.example.com"]
+    ttl = 86400
+}
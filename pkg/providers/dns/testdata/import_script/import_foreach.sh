@@ -0,0 +1,5 @@
+terraform init
+terraform import akamai_dns_zone.resource_name some-zone
+terraform import akamai_dns_record.resource_name_a_b some-zone#a#b
+terraform import 'akamai_dns_record.resource_name_c_records_1["a"]' some-zone#a#c
+terraform import 'akamai_dns_record.resource_name_c_records_1["e"]' some-zone#e#c
\ No newline at end of file
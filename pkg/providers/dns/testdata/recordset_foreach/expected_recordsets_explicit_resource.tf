@@ -0,0 +1,8 @@
+
+resource "akamai_dns_record" "zoneName_example_com_A" {
+  zone       = local.zone
+  name       = "example.com"
+  recordtype = "A"
+  target     = ["10.0.0.1"]
+  ttl        = 300
+}
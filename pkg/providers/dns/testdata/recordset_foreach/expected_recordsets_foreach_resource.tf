@@ -0,0 +1,26 @@
+
+variable "zoneName_CNAME_records_2" {
+  description = "Targets of CNAME records by record name"
+  type        = map(list(string))
+  default = {
+    "tenant0.example.com" = ["tenant0.edgekey.net"]
+    "tenant1.example.com" = ["tenant1.edgekey.net"]
+    "tenant2.example.com" = ["tenant2.edgekey.net"]
+    "tenant3.example.com" = ["tenant3.edgekey.net"]
+    "tenant4.example.com" = ["tenant4.edgekey.net"]
+    "tenant5.example.com" = ["tenant5.edgekey.net"]
+    "tenant6.example.com" = ["tenant6.edgekey.net"]
+    "tenant7.example.com" = ["tenant7.edgekey.net"]
+    "tenant8.example.com" = ["tenant8.edgekey.net"]
+    "tenant9.example.com" = ["tenant9.edgekey.net"]
+  }
+}
+
+resource "akamai_dns_record" "zoneName_CNAME_records_2" {
+  for_each   = var.zoneName_CNAME_records_2
+  zone       = local.zone
+  name       = each.key
+  target     = each.value
+  recordtype = "CNAME"
+  ttl        = 300
+}
@@ -30,3 +30,12 @@ resource "akamai_dns_zone" "_0007770b-08a8-4b5f-a46b-081b772ba605-sbodden-calvin
   }
 }
 
+data "akamai_authorities_set" "_0007770b-08a8-4b5f-a46b-081b772ba605-sbodden-calvin_com" {
+  contract = var.contractid
+}
+
+output "_0007770b-08a8-4b5f-a46b-081b772ba605-sbodden-calvin_com_nameservers" {
+  description = "Authoritative name servers of the zone, to be set as its delegation at the registrar"
+  value       = data.akamai_authorities_set._0007770b-08a8-4b5f-a46b-081b772ba605-sbodden-calvin_com.authorities
+}
+
@@ -0,0 +1,161 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// minForEachRecords is the minimal number of structurally identical recordsets consolidated into a single resource
+// with for_each, smaller groups are written as separate resources
+const minForEachRecords = 10
+
+type (
+	// ForEachData represents a struct passed to for_each recordset template
+	ForEachData struct {
+		BlockName      string
+		RecordType     string
+		ResourceFields map[string]string
+		Targets        map[string]string
+	}
+
+	// recordsetBlock is a recordset resource collected before deciding whether it is consolidated
+	recordsetBlock struct {
+		Name string
+		Type string
+		Data RecordsetData
+	}
+)
+
+// writeRecordsetBlocks writes collected recordsets into the zone configuration. Recordsets of the same type which differ
+// only in their names and targets are consolidated into a single resource with for_each over a map variable of their
+// targets, if there are at least minForEachRecords of them. Names of resources of consolidated recordsets are added
+// into forEach keyed by forEachKey, names already present there are not reused.
+func writeRecordsetBlocks(ctx context.Context, resourceZoneName string, blocks []recordsetBlock, forEach map[string]string, fileUtils fileUtils, config configStruct) error {
+	groups := make(map[string][]recordsetBlock)
+	for _, block := range blocks {
+		if key := groupKey(block); key != "" {
+			groups[key] = append(groups[key], block)
+		}
+	}
+	keys := make([]string, 0, len(groups))
+	consolidated := make(map[string]bool)
+	for key, group := range groups {
+		if len(group) < minForEachRecords {
+			continue
+		}
+		keys = append(keys, key)
+		for _, block := range group {
+			consolidated[forEachKey(block.Name, block.Type)] = true
+		}
+	}
+	sort.Strings(keys)
+
+	for _, block := range blocks {
+		if consolidated[forEachKey(block.Name, block.Type)] {
+			continue
+		}
+		if err := writeRecordset(ctx, block.Data.BlockName, block.Data, fileUtils, config); err != nil {
+			return err
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, blockName := range forEach {
+		used[blockName] = true
+	}
+	for _, key := range keys {
+		group := groups[key]
+		recordType := group[0].Type
+		var blockName string
+		for i := 1; blockName == "" || used[blockName]; i++ {
+			blockName = fmt.Sprintf("%s_%s_records_%d", normalizeResourceName(resourceZoneName), recordType, i)
+		}
+		used[blockName] = true
+		data := ForEachData{
+			BlockName:      blockName,
+			RecordType:     recordType,
+			ResourceFields: make(map[string]string),
+			Targets:        make(map[string]string),
+		}
+		for name, value := range group[0].Data.ResourceFields {
+			if name != "name" && name != "target" {
+				data.ResourceFields[name] = value
+			}
+		}
+		for _, block := range group {
+			data.Targets[block.Name] = block.Data.ResourceFields["target"]
+			forEach[forEachKey(block.Name, block.Type)] = blockName
+		}
+		if err := fileUtils.appendRootModuleTF(ctx, useTemplate(&data, "foreach-set.tmpl", false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupKey returns the key shared by recordsets which can be consolidated, that is all their fields except the name
+// and target, or an empty string if the recordset has no target
+func groupKey(block recordsetBlock) string {
+	if _, ok := block.Data.ResourceFields["target"]; !ok {
+		return ""
+	}
+	fields := make([]string, 0, len(block.Data.ResourceFields))
+	for name, value := range block.Data.ResourceFields {
+		if name != "name" && name != "target" {
+			fields = append(fields, name+"="+value)
+		}
+	}
+	sort.Strings(fields)
+	return block.Type + "\n" + strings.Join(fields, "\n")
+}
+
+// forEachKey returns the key identifying the recordset in the map of consolidated recordsets
+func forEachKey(name, recordType string) string {
+	return name + "#" + recordType
+}
+
+// Utility method to create full path of the file listing consolidated recordsets
+func createForEachConfigFilename(resourceName, tfWorkPath string) string {
+
+	return filepath.Join(tfWorkPath, resourceName+"_foreach.json")
+
+}
+
+// saveForEachConfigFile saves resources of consolidated recordsets for import script generation, the file is not
+// created if no recordsets were consolidated
+func saveForEachConfigFile(ctx context.Context, forEach map[string]string, forEachConfigFilename string) error {
+	if len(forEach) == 0 {
+		return nil
+	}
+	forEachJSON, err := json.MarshalIndent(forEach, "", "  ")
+	if err != nil {
+		return err
+	}
+	return templates.GetSink(ctx).WriteFile(forEachConfigFilename, tools.ApplyLineEndings(forEachJSON))
+}
+
+// retrieveForEachConfig reads resources of consolidated recordsets saved by previous config generation, an empty map
+// is returned if there are none
+func retrieveForEachConfig(rscName, tfWorkPath string) (map[string]string, error) {
+	forEach := make(map[string]string)
+	content, err := ioutil.ReadFile(createForEachConfigFilename(rscName, tfWorkPath))
+	if os.IsNotExist(err) {
+		return forEach, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &forEach); err != nil {
+		return nil, err
+	}
+	return forEach, nil
+}
@@ -0,0 +1,91 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRecordsetBlocks(t *testing.T) {
+	cname := func(i int) recordsetBlock {
+		name := fmt.Sprintf("tenant%d.example.com", i)
+		return recordsetBlock{Name: name, Type: "CNAME", Data: RecordsetData{
+			BlockName: createUniqueRecordsetName("zoneName", name, "CNAME"),
+			ResourceFields: map[string]string{
+				"name":       fmt.Sprintf("%q", name),
+				"recordtype": `"CNAME"`,
+				"target":     fmt.Sprintf(`["tenant%d.edgekey.net"]`, i),
+				"ttl":        "300",
+			},
+		}}
+	}
+	a := recordsetBlock{Name: "example.com", Type: "A", Data: RecordsetData{
+		BlockName: "zoneName_example_com_A",
+		ResourceFields: map[string]string{
+			"name":       `"example.com"`,
+			"recordtype": `"A"`,
+			"target":     `["10.0.0.1"]`,
+			"ttl":        "300",
+		},
+	}}
+
+	tests := map[string]struct {
+		blocks          []recordsetBlock
+		forEach         map[string]string
+		expectedAppends int
+		expectedForEach map[string]string
+		expectRootPath  string
+	}{
+		"identical recordsets are consolidated": {
+			blocks: []recordsetBlock{
+				a, cname(0), cname(1), cname(2), cname(3), cname(4), cname(5), cname(6), cname(7), cname(8), cname(9),
+			},
+			forEach:         map[string]string{"old.example.com#CNAME": "zoneName_CNAME_records_1"},
+			expectedAppends: 2,
+			expectedForEach: map[string]string{
+				"old.example.com#CNAME":     "zoneName_CNAME_records_1",
+				"tenant0.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant1.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant2.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant3.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant4.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant5.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant6.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant7.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant8.example.com#CNAME": "zoneName_CNAME_records_2",
+				"tenant9.example.com#CNAME": "zoneName_CNAME_records_2",
+			},
+			expectRootPath: "./testdata/recordset_foreach/expected_recordsets_foreach_resource.tf",
+		},
+		"too few identical recordsets": {
+			blocks:          []recordsetBlock{cname(0), cname(1), cname(2), a},
+			forEach:         map[string]string{},
+			expectedAppends: 4,
+			expectedForEach: map[string]string{},
+			expectRootPath:  "./testdata/recordset_foreach/expected_recordsets_explicit_resource.tf",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fus := new(fileutilsmock)
+			fus.On("appendRootModuleTF", mock.Anything).Return(nil).Times(test.expectedAppends)
+
+			err := writeRecordsetBlocks(context.Background(), "zoneName", test.blocks, test.forEach, fus, configStruct{})
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedForEach, test.forEach)
+			assertFileWithContent(t, test.expectRootPath, fus.appendRootArg)
+			fus.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConsolidateRecords(t *testing.T) {
+	assert.True(t, configStruct{}.consolidateRecords())
+	assert.False(t, configStruct{explicitRecords: true}.consolidateRecords())
+	assert.False(t, configStruct{fetchConfig: fetchConfigStruct{ModSegment: true}}.consolidateRecords())
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/shirou/gopsutil/mem"
 )
 
@@ -61,11 +62,13 @@ func createParamsMap(params []string) *map[string]string {
 
 }
 
-// Process recordset resources
-func processRecordsets(ctx context.Context, client dns.DNS, zone string, resourceZoneName string, zoneTypeMap map[string]map[string]bool, fileUtils fileUtils, config configStruct) (map[string]Types, error) {
+// Process recordset resources. Names of resources of recordsets consolidated with for_each are added into forEach.
+func processRecordsets(ctx context.Context, client dns.DNS, zone string, resourceZoneName string, zoneTypeMap map[string]map[string]bool, forEach map[string]string, fileUtils fileUtils, config configStruct) (map[string]Types, error) {
 
 	// returned variable. That map later will be used to create import script
 	var importScriptConfig = make(map[string]Types)
+	// recordsets are written after all are fetched when identical ones are consolidated
+	var blocks []recordsetBlock
 
 	queryArgs := getQueryArguments()
 	nameRecordSetsResp, err := client.GetRecordsets(ctx, zone, queryArgs)
@@ -73,6 +76,9 @@ func processRecordsets(ctx context.Context, client dns.DNS, zone string, resourc
 		return importScriptConfig, fmt.Errorf("failed to read record set %s", err.Error())
 	}
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if config.fetchConfig.ConfigOnly {
 			// can specify record names with config only
 			for _, recname := range config.recordNames {
@@ -88,19 +94,12 @@ func processRecordsets(ctx context.Context, client dns.DNS, zone string, resourc
 			recordMap := getRecordMap(ctx, client, recordset)
 			modName := createUniqueRecordsetName(resourceZoneName, recordset.Name, recordset.Type)
 			data := RecordsetData{BlockName: modName, ResourceFields: recordMap, TfWorkPath: config.tfWorkPath}
-			if config.fetchConfig.ModSegment {
-				// process as module
-				if err := fileUtils.appendRootModuleTF(useTemplate(&data, "module-set.tmpl", false)); err != nil {
-					return nil, err
-				}
-				if err := fileUtils.createModuleTF(ctx, modName, useTemplate(&data, "recordset-modsegment.tmpl", true), config.tfWorkPath); err != nil {
-					return nil, err
-				}
-			} else {
-				// add to toplevel TF
-				if err := fileUtils.appendRootModuleTF(useTemplate(&data, "resource-set.tmpl", false)); err != nil {
-					return nil, err
-				}
+			if config.consolidateRecords() {
+				blocks = append(blocks, recordsetBlock{Name: recordset.Name, Type: recordset.Type, Data: data})
+				continue
+			}
+			if err := writeRecordset(ctx, modName, data, fileUtils, config); err != nil {
+				return nil, err
 			}
 		}
 
@@ -114,10 +113,27 @@ func processRecordsets(ctx context.Context, client dns.DNS, zone string, resourc
 		}
 	}
 
+	if err := writeRecordsetBlocks(ctx, resourceZoneName, blocks, forEach, fileUtils, config); err != nil {
+		return nil, err
+	}
+
 	return importScriptConfig, nil
 
 }
 
+// writeRecordset adds record set resource to the zone configuration, either directly or as a module
+func writeRecordset(ctx context.Context, modName string, data RecordsetData, fileUtils fileUtils, config configStruct) error {
+	if config.fetchConfig.ModSegment {
+		// process as module
+		if err := fileUtils.appendRootModuleTF(ctx, useTemplate(&data, "module-set.tmpl", false)); err != nil {
+			return err
+		}
+		return fileUtils.createModuleTF(ctx, modName, useTemplate(&data, "recordset-modsegment.tmpl", true), config.tfWorkPath)
+	}
+	// add to toplevel TF
+	return fileUtils.appendRootModuleTF(ctx, useTemplate(&data, "resource-set.tmpl", false))
+}
+
 func updateImportScriptConfig(importScriptConfig map[string]Types, recordset dns.Recordset) {
 	if _, ok := importScriptConfig[recordset.Name]; !ok {
 		importScriptConfig[recordset.Name] = Types{}
@@ -126,6 +142,9 @@ func updateImportScriptConfig(importScriptConfig map[string]Types, recordset dns
 }
 
 func shouldProcessRecordset(zoneTypeMap map[string]map[string]bool, recordset dns.Recordset, config configStruct) bool {
+	if !config.includesRecordType(recordset.Type) {
+		return false
+	}
 	if config.fetchConfig.ConfigOnly {
 		// combination of recordnames and config only valid
 		if len(config.recordNames) > 0 {
@@ -145,12 +164,15 @@ func shouldProcessRecordset(zoneTypeMap map[string]map[string]bool, recordset dn
 }
 
 func getQueryArguments() dns.RecordsetQueryArgs {
-	v, _ := mem.VirtualMemory()
-	maxPageSize := (v.Free / 2) / 512 // use max half of free memory. Assume avg recordset size is 512 bytes
-	if maxPageSize > uint64(maxInt/512) {
-		maxPageSize = uint64(maxInt / 512)
+	pagesize := tools.PageSize(tools.PageSizeDNS, 0)
+	if pagesize == 0 {
+		v, _ := mem.VirtualMemory()
+		maxPageSize := (v.Free / 2) / 512 // use max half of free memory. Assume avg recordset size is 512 bytes
+		if maxPageSize > uint64(maxInt/512) {
+			maxPageSize = uint64(maxInt / 512)
+		}
+		pagesize = int(maxPageSize)
 	}
-	pagesize := int(maxPageSize)
 
 	// get recordsets
 	queryArgs := dns.RecordsetQueryArgs{PageSize: pagesize, SortBy: "name, type", Page: 1}
@@ -2,11 +2,14 @@ package dns
 
 import (
 	"context"
+	"flag"
 	"testing"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
 )
 
 func TestProcessStringNoQuotes(t *testing.T) {
@@ -83,7 +86,7 @@ func TestProcessRecordset(t *testing.T) {
 			zoneTypeMap := make(map[string]map[string]bool)
 			zoneTypeMap["someName"] = map[string]bool{"someType": true}
 			config := configStruct{fetchConfig: fetchConfigStruct{ModSegment: test.mod}}
-			processingResult, _ := processRecordsets(ctx, m, zone, "zoneName", zoneTypeMap, fus, config)
+			processingResult, _ := processRecordsets(ctx, m, zone, "zoneName", zoneTypeMap, map[string]string{}, fus, config)
 
 			assert.Equal(t, 1, len(processingResult))
 			types, nameExist := processingResult[recordset.Name]
@@ -99,3 +102,62 @@ func TestProcessRecordset(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldProcessRecordset(t *testing.T) {
+	zoneTypeMap := map[string]map[string]bool{
+		"example.com":                     {"A": true, "TXT": true},
+		"_acme-challenge.www.example.com": {"TXT": true},
+	}
+	tests := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"all types": {
+			expected: []string{"example.com A", "example.com TXT", "_acme-challenge.www.example.com TXT"},
+		},
+		"record types": {
+			args:     []string{"--record-types", "a,CNAME"},
+			expected: []string{"example.com A"},
+		},
+		"excluded record types": {
+			args:     []string{"--exclude-record-types", "TXT"},
+			expected: []string{"example.com A"},
+		},
+		"record types given multiple times": {
+			args:     []string{"--record-types", "A", "--record-types", "txt"},
+			expected: []string{"example.com A", "example.com TXT", "_acme-challenge.www.example.com TXT"},
+		},
+		"record types with excluded record types": {
+			args:     []string{"--record-types", "A,TXT", "--exclude-record-types", "A"},
+			expected: []string{"example.com TXT", "_acme-challenge.www.example.com TXT"},
+		},
+		"config only with excluded record types": {
+			args:     []string{"--configonly", "--exclude-record-types", "A"},
+			expected: []string{"example.com TXT", "_acme-challenge.www.example.com TXT", "www.example.com CNAME"},
+		},
+	}
+	recordsets := []dns.Recordset{
+		{Name: "example.com", Type: "A"},
+		{Name: "example.com", Type: "TXT"},
+		{Name: "_acme-challenge.www.example.com", Type: "TXT"},
+		{Name: "www.example.com", Type: "CNAME"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set := flag.NewFlagSet("export-zone", flag.ContinueOnError)
+			set.Bool("configonly", false, "")
+			set.Var(cli.NewStringSlice(), "record-types", "")
+			set.Var(cli.NewStringSlice(), "exclude-record-types", "")
+			require.NoError(t, set.Parse(test.args))
+			config := setConfiguration(cli.NewContext(cli.NewApp(), set, nil))
+
+			var processed []string
+			for _, recordset := range recordsets {
+				if shouldProcessRecordset(zoneTypeMap, recordset, config) {
+					processed = append(processed, recordset.Name+" "+recordset.Type)
+				}
+			}
+			assert.Equal(t, test.expected, processed)
+		})
+	}
+}
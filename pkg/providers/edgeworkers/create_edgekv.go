@@ -4,14 +4,13 @@ package edgeworkers
 import (
 	"context"
 	"embed"
-	"errors"
 	"fmt"
 	"path/filepath"
-	"strings"
-	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
@@ -36,7 +35,7 @@ var (
 	templateFiles embed.FS
 
 	// ErrFetchingEdgeKV is returned when fetching edgekv fails
-	ErrFetchingEdgeKV = errors.New("unable to fetch edgekv with given namespace_name and network")
+	ErrFetchingEdgeKV = exitcode.New(exitcode.API, "unable to fetch edgekv with given namespace_name and network")
 )
 
 // CmdCreateEdgeKV is an entrypoint to create-edgekv command
@@ -57,7 +56,7 @@ func CmdCreateEdgeKV(c *cli.Context) error {
 
 	err := tools.CheckFiles(edgeKVPath, variablesPath, importPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 	templateToFile := map[string]string{
 		"edgekv.tmpl":           edgeKVPath,
@@ -68,11 +67,8 @@ func CmdCreateEdgeKV(c *cli.Context) error {
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
-		AdditionalFuncs: template.FuncMap{
-			"ToLower": func(network edgeworkers.ActivationNetwork) string {
-				return strings.ToLower(string(network))
-			},
-		},
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 	}
 
 	namespace := c.Args().First()
@@ -80,19 +76,18 @@ func CmdCreateEdgeKV(c *cli.Context) error {
 	section := edgegrid.GetEdgercSection(c)
 
 	if err = createEdgeKV(ctx, namespace, network, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting edgekv HCL: %s", err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting edgekv HCL: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
 
 func createEdgeKV(ctx context.Context, namespace string, network edgeworkers.NamespaceNetwork, section string, client edgeworkers.Edgeworkers, templateProcessor templates.TemplateProcessor) error {
-	term := terminal.Get(ctx)
-	fmt.Println("Configuring EdgeKV")
-	term.Spinner().Start("Fetching EdgeKV "+namespace, "")
+	terminal.Get(ctx).Printf("Configuring EdgeKV\n")
+	progress.Get(ctx).Start("Fetching EdgeKV %s", namespace)
 
 	edgeKV, err := getEdgeKV(ctx, namespace, network, client)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingEdgeKV, err)
 	}
 
@@ -109,14 +104,14 @@ func createEdgeKV(ctx context.Context, namespace string, network edgeworkers.Nam
 		tfEdgeKVData.GroupID = *edgeKV.GroupID
 	}
 
-	term.Spinner().OK()
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).OK()
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err := templateProcessor.ProcessTemplates(tfEdgeKVData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", templates.ErrSavingFiles, err)
 	}
-	term.Spinner().OK()
-	fmt.Printf("Terraform configuration for edgeKV '%s' on network '%s' was saved successfully\n", edgeKV.Name, network)
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for edgeKV '%s' on network '%s' was saved successfully\n", edgeKV.Name, network)
 
 	return nil
 }
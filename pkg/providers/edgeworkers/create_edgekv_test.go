@@ -6,9 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"strings"
 	"testing"
-	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
 	"github.com/akamai/cli-terraform/pkg/templates"
@@ -184,11 +182,6 @@ func TestProcessEdgeKVTemplates(t *testing.T) {
 					"edgekv-variables.tmpl": fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
 					"edgekv-imports.tmpl":   fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
 				},
-				AdditionalFuncs: template.FuncMap{
-					"ToLower": func(network edgeworkers.ActivationNetwork) string {
-						return strings.ToLower(string(network))
-					},
-				},
 			}
 			require.NoError(t, processor.ProcessTemplates(test.givenData))
 
@@ -2,18 +2,17 @@ package edgeworkers
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
-	"text/template"
 	"time"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
@@ -35,7 +34,7 @@ type (
 
 var (
 	// ErrFetchingEdgeWorker is returned when fetching edgeworker fails
-	ErrFetchingEdgeWorker = errors.New("unable to fetch edgeworker with given edgeworker_id")
+	ErrFetchingEdgeWorker = exitcode.New(exitcode.API, "unable to fetch edgeworker with given edgeworker_id")
 )
 
 // CmdCreateEdgeWorker is an entrypoint to create-edgeworker command
@@ -60,57 +59,59 @@ func CmdCreateEdgeWorker(c *cli.Context) error {
 	}
 	bundleDir = filepath.FromSlash(bundleDir)
 	if stat, err := os.Stat(bundleDir); err != nil || !stat.IsDir() {
-		return cli.Exit(color.RedString("Bundle path is not accessible"), 1)
+		return cli.Exit(color.RedString("Bundle path is not accessible"), exitcode.IO)
 	}
 
 	err := tools.CheckFiles(edgeWorkerPath, variablesPath, importPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 	templateToFile := map[string]string{
 		"edgeworker.tmpl":           edgeWorkerPath,
 		"edgeworker-variables.tmpl": variablesPath,
 		"edgeworker-imports.tmpl":   importPath,
 	}
+	if c.Bool("read-only") {
+		templateToFile = map[string]string{
+			"edgeworker-read-only.tmpl": edgeWorkerPath,
+			"edgeworker-variables.tmpl": variablesPath,
+		}
+	}
 
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
-		AdditionalFuncs: template.FuncMap{
-			"ToLower": func(network edgeworkers.ActivationNetwork) string {
-				return strings.ToLower(string(network))
-			},
-		},
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 	}
 
 	edgeWorkerID, err := strconv.Atoi(c.Args().First())
 	if err != nil {
-		return cli.Exit(color.RedString("edgeworker_id is not a valid integer"), 1)
+		return cli.Exit(color.RedString("edgeworker_id is not a valid integer"), exitcode.General)
 	}
 	section := edgegrid.GetEdgercSection(c)
 
 	if err = createEdgeWorker(ctx, edgeWorkerID, bundleDir, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting edgeworker HCL: %s", err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting edgeworker HCL: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
 
 func createEdgeWorker(ctx context.Context, edgeWorkerID int, bundleDir, section string, client edgeworkers.Edgeworkers, templateProcessor templates.TemplateProcessor) error {
-	term := terminal.Get(ctx)
-	fmt.Println("Configuring EdgeWorker")
-	term.Spinner().Start(fmt.Sprintf("Fetching EdgeWorker %d", edgeWorkerID), "")
+	terminal.Get(ctx).Printf("Configuring EdgeWorker\n")
+	progress.Get(ctx).Start("Fetching EdgeWorker %d", edgeWorkerID)
 
 	edgeWorker, err := client.GetEdgeWorkerID(ctx, edgeworkers.GetEdgeWorkerIDRequest{
 		EdgeWorkerID: edgeWorkerID,
 	})
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingEdgeWorker, err)
 	}
 
 	localBundle, err := getEdgeWorkerBundle(ctx, edgeWorkerID, bundleDir, client)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingEdgeWorker, err)
 	}
 
@@ -123,14 +124,14 @@ func createEdgeWorker(ctx context.Context, edgeWorkerID int, bundleDir, section
 		Section:        section,
 	}
 
-	term.Spinner().OK()
-	term.Spinner().Start("Saving TF configurations ", "")
+	progress.Get(ctx).OK()
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err := templateProcessor.ProcessTemplates(tfEdgeWorkerData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", templates.ErrSavingFiles, err)
 	}
-	term.Spinner().OK()
-	fmt.Printf("Terraform configuration for edgeworker '%s' with edgeworker_id '%d' was saved successfully\n", edgeWorker.Name, edgeWorkerID)
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for edgeworker '%s' with edgeworker_id '%d' was saved successfully\n", edgeWorker.Name, edgeWorkerID)
 
 	return nil
 }
@@ -169,14 +170,11 @@ func getEdgeWorkerBundle(ctx context.Context, edgeWorkerID int, bundlePath strin
 	}
 
 	localBundle := filepath.Join(bundlePath, version+".tgz")
-	f, err := os.OpenFile(localBundle, os.O_CREATE|os.O_WRONLY, 0755)
+	content, err := io.ReadAll(bundleContent)
 	if err != nil {
 		return "", err
 	}
-	if _, err := io.Copy(f, bundleContent); err != nil {
-		return "", err
-	}
-	if err := f.Close(); err != nil {
+	if err := templates.GetSink(ctx).WriteFile(localBundle, content); err != nil {
 		return "", err
 	}
 
@@ -7,9 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"strings"
 	"testing"
-	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/edgeworkers"
 	"github.com/akamai/cli-terraform/pkg/templates"
@@ -214,6 +212,7 @@ func TestProcessEdgeWorkerTemplates(t *testing.T) {
 		givenData    TFEdgeWorkerData
 		dir          string
 		filesToCheck []string
+		readOnly     bool
 	}{
 		"edgeworker with no local bundle": {
 			givenData: TFEdgeWorkerData{
@@ -238,6 +237,18 @@ func TestProcessEdgeWorkerTemplates(t *testing.T) {
 			dir:          "edgeworker_with_local_bundle",
 			filesToCheck: []string{"edgeworker.tf", "variables.tf", "import.sh"},
 		},
+		"read-only edgeworker": {
+			givenData: TFEdgeWorkerData{
+				EdgeWorkerID:   123,
+				Name:           "test_edgeworker",
+				GroupID:        1,
+				ResourceTierID: 2,
+				Section:        "test_section",
+			},
+			dir:          "edgeworker_read_only",
+			filesToCheck: []string{"edgeworker.tf", "variables.tf"},
+			readOnly:     true,
+		},
 	}
 
 	for name, test := range tests {
@@ -250,11 +261,12 @@ func TestProcessEdgeWorkerTemplates(t *testing.T) {
 					"edgeworker-variables.tmpl": fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
 					"edgeworker-imports.tmpl":   fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
 				},
-				AdditionalFuncs: template.FuncMap{
-					"ToLower": func(network edgeworkers.ActivationNetwork) string {
-						return strings.ToLower(string(network))
-					},
-				},
+			}
+			if test.readOnly {
+				processor.TemplateTargets = map[string]string{
+					"edgeworker-read-only.tmpl": fmt.Sprintf("./testdata/res/%s/edgeworker.tf", test.dir),
+					"edgeworker-variables.tmpl": fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+				}
 			}
 			require.NoError(t, processor.ProcessTemplates(test.givenData))
 
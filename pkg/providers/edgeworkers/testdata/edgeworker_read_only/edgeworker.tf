@@ -0,0 +1,25 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+data "akamai_edgeworker" "edgeworker" {
+  edgeworker_id = 123
+}
+
+locals {
+  edgeworker_id    = 123
+  name             = "test_edgeworker"
+  group_id         = 1
+  resource_tier_id = 2
+}
@@ -18,7 +18,6 @@ package gtm
 import (
 	"context"
 	"embed"
-	"errors"
 	"fmt"
 	"path/filepath"
 	"regexp"
@@ -27,6 +26,8 @@ import (
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
@@ -58,6 +59,7 @@ type (
 		GeoMaps                     []*gtm.GeoMap
 		AsMaps                      []*gtm.AsMap
 		Properties                  []*gtm.Property
+		LivenessSecrets             []LivenessSecret
 	}
 
 	// TFDatacenterData represents the data used for processing a datacenter
@@ -86,7 +88,7 @@ var (
 	subWithUnderscoreRegexp               = regexp.MustCompile(`[^\w-_]`)
 	mustStartWithLetterOrUnderscoreRegexp = regexp.MustCompile("^[^a-zA-Z_]")
 	// ErrFetchingDomain is returned when fetching domain fails
-	ErrFetchingDomain = errors.New("unable to fetch domain with given name")
+	ErrFetchingDomain = exitcode.New(exitcode.API, "unable to fetch domain with given name")
 )
 
 // CmdCreateDomain is an entrypoint to create-domain command
@@ -108,6 +110,7 @@ func CmdCreateDomain(c *cli.Context) error {
 	propertiesPath := filepath.Join(tfWorkPath, "properties.tf")
 	resourcesPath := filepath.Join(tfWorkPath, "resources.tf")
 	variablesPath := filepath.Join(tfWorkPath, "variables.tf")
+	tfvarsExamplePath := filepath.Join(tfWorkPath, "terraform.tfvars.example")
 
 	templateToFile := map[string]string{
 		"datacenters.tmpl": datacentersPath,
@@ -117,19 +120,21 @@ func CmdCreateDomain(c *cli.Context) error {
 		"properties.tmpl":  propertiesPath,
 		"resources.tmpl":   resourcesPath,
 		"variables.tmpl":   variablesPath,
+		"tfvars.tmpl":      tfvarsExamplePath,
 	}
 
-	err := tools.CheckFiles(datacentersPath, domainPath, importPath, mapsPath, propertiesPath, resourcesPath, variablesPath)
+	err := tools.CheckFiles(datacentersPath, domainPath, importPath, mapsPath, propertiesPath, resourcesPath, variablesPath, tfvarsExamplePath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 		AdditionalFuncs: template.FuncMap{
 			"normalize":   normalizeResourceName,
-			"toUpper":     strings.ToUpper,
 			"isDefaultDC": isDefaultDatacenter,
 		},
 	}
@@ -137,7 +142,7 @@ func CmdCreateDomain(c *cli.Context) error {
 	domainName := c.Args().First()
 	section := edgegrid.GetEdgercSection(c)
 	if err := createDomain(ctx, client, domainName, section, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting domain HCL: %s", err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting domain HCL: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
@@ -146,10 +151,10 @@ func createDomain(ctx context.Context, client gtm.GTM, domainName, section strin
 	term := terminal.Get(ctx)
 
 	term.Writeln("Configuring Domain")
-	term.Spinner().Start(fmt.Sprintf("Fetching domain %s", domainName))
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching domain %s", domainName))
 	domain, err := client.GetDomain(ctx, domainName)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingDomain, err)
 	}
 
@@ -176,14 +181,15 @@ func createDomain(ctx context.Context, client gtm.GTM, domainName, section strin
 	}
 
 	tfDomainData.getDatacenters(domain)
-	term.Spinner().OK()
+	tfDomainData.findLivenessSecrets()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Saving TF configurations")
+	progress.Get(ctx).Start("Saving TF configurations")
 	if err := templateProcessor.ProcessTemplates(tfDomainData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	term.Writeln(fmt.Sprintf("Terraform configuration for policy '%s' was saved successfully\n", domain.Name))
 
@@ -8,12 +8,12 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
 	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -404,6 +404,7 @@ func TestProcessDomainTemplates(t *testing.T) {
 		givenData    interface{}
 		dir          string
 		filesToCheck []string
+		varNaming    string
 	}{
 		"import script correct": {
 			givenData: TFDomainData{
@@ -1005,10 +1006,141 @@ func TestProcessDomainTemplates(t *testing.T) {
 			dir:          "with_qtr_properties",
 			filesToCheck: []string{"domain.tf", "datacenters.tf", "properties.tf", "variables.tf", "import.sh"},
 		},
+		"simple domain with liveness test secrets": {
+			givenData: TFDomainData{
+				Section:                 "test_section",
+				Name:                    "test.name.akadns.net",
+				NormalizedName:          "test_name",
+				Type:                    "basic",
+				Comment:                 "test",
+				EmailNotificationList:   []string{"john@akamai.com", "jdoe@akamai.com"},
+				DefaultTimeoutPenalty:   10,
+				LoadImbalancePercentage: 50,
+				DefaultErrorPenalty:     90,
+				CnameCoalescingEnabled:  true,
+				LoadFeedback:            true,
+				DefaultDatacenters: []TFDatacenterData{
+					{
+						Nickname: "DEFAULT",
+						ID:       5400,
+					},
+				},
+				Properties: []*gtm.Property{
+					{
+						Name:                 "test property1",
+						Type:                 "weighted-round-robin",
+						ScoreAggregationType: "worst",
+						DynamicTTL:           60,
+						HandoutLimit:         8,
+						HandoutMode:          "normal",
+						TrafficTargets: []*gtm.TrafficTarget{
+							{
+								DatacenterId: 5400,
+								Enabled:      true,
+								Weight:       1,
+								Servers:      []string{"1.2.3.4"},
+							},
+						},
+						LivenessTests: []*gtm.LivenessTest{
+							{
+								Name:               "HTTPS",
+								TestInterval:       60,
+								TestObject:         "/health",
+								HttpError3xx:       true,
+								HttpError4xx:       true,
+								HttpError5xx:       true,
+								TestObjectProtocol: "HTTPS",
+								TestObjectPort:     443,
+								TestTimeout:        10,
+								TestObjectUsername: "monitor",
+								TestObjectPassword: "secret",
+								HttpHeaders: []*gtm.HttpHeader{
+									{Name: "Authorization", Value: "Bearer abc"},
+									{Name: "Accept", Value: "text/html"},
+								},
+							},
+						},
+					},
+				},
+				LivenessSecrets: []LivenessSecret{
+					{Variable: "test_property1_https_test_object_password", Property: "test property1", Test: "HTTPS", Field: "test_object_password"},
+					{Variable: "test_property1_https_http_header_authorization", Property: "test property1", Test: "HTTPS", Field: "http_header_Authorization"},
+				},
+			},
+			dir:          "with_liveness_secrets",
+			filesToCheck: []string{"domain.tf", "properties.tf", "variables.tf", "import.sh", "terraform.tfvars.example"},
+		},
+		"simple domain with liveness test secrets and camel case variables": {
+			givenData: TFDomainData{
+				Section:                 "test_section",
+				Name:                    "test.name.akadns.net",
+				NormalizedName:          "test_name",
+				Type:                    "basic",
+				Comment:                 "test",
+				EmailNotificationList:   []string{"john@akamai.com", "jdoe@akamai.com"},
+				DefaultTimeoutPenalty:   10,
+				LoadImbalancePercentage: 50,
+				DefaultErrorPenalty:     90,
+				CnameCoalescingEnabled:  true,
+				LoadFeedback:            true,
+				DefaultDatacenters: []TFDatacenterData{
+					{
+						Nickname: "DEFAULT",
+						ID:       5400,
+					},
+				},
+				Properties: []*gtm.Property{
+					{
+						Name:                 "test property1",
+						Type:                 "weighted-round-robin",
+						ScoreAggregationType: "worst",
+						DynamicTTL:           60,
+						HandoutLimit:         8,
+						HandoutMode:          "normal",
+						TrafficTargets: []*gtm.TrafficTarget{
+							{
+								DatacenterId: 5400,
+								Enabled:      true,
+								Weight:       1,
+								Servers:      []string{"1.2.3.4"},
+							},
+						},
+						LivenessTests: []*gtm.LivenessTest{
+							{
+								Name:               "HTTPS",
+								TestInterval:       60,
+								TestObject:         "/health",
+								HttpError3xx:       true,
+								HttpError4xx:       true,
+								HttpError5xx:       true,
+								TestObjectProtocol: "HTTPS",
+								TestObjectPort:     443,
+								TestTimeout:        10,
+								TestObjectUsername: "monitor",
+								TestObjectPassword: "secret",
+								HttpHeaders: []*gtm.HttpHeader{
+									{Name: "Authorization", Value: "Bearer abc"},
+									{Name: "Accept", Value: "text/html"},
+								},
+							},
+						},
+					},
+				},
+				LivenessSecrets: []LivenessSecret{
+					{Variable: "test_property1_https_test_object_password", Property: "test property1", Test: "HTTPS", Field: "test_object_password"},
+					{Variable: "test_property1_https_http_header_authorization", Property: "test property1", Test: "HTTPS", Field: "http_header_Authorization"},
+				},
+			},
+			dir:          "with_liveness_secrets_var_naming_camel",
+			filesToCheck: []string{"properties.tf", "variables.tf", "terraform.tfvars.example"},
+			varNaming:    tools.VarNamingCamel,
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			tools.VarNaming = test.varNaming
+			defer func() { tools.VarNaming = "" }()
 			outDir := filepath.Join("./testdata/res", test.dir)
 			require.NoError(t, os.MkdirAll(outDir, 0755))
 			processor := templates.FSTemplateProcessor{
@@ -1021,10 +1153,10 @@ func TestProcessDomainTemplates(t *testing.T) {
 					"resources.tmpl":   filepath.Join(outDir, "resources.tf"),
 					"properties.tmpl":  filepath.Join(outDir, "properties.tf"),
 					"variables.tmpl":   filepath.Join(outDir, "variables.tf"),
+					"tfvars.tmpl":      filepath.Join(outDir, "terraform.tfvars.example"),
 				},
 				AdditionalFuncs: template.FuncMap{
 					"normalize":   normalizeResourceName,
-					"toUpper":     strings.ToUpper,
 					"isDefaultDC": isDefaultDatacenter,
 				},
 			}
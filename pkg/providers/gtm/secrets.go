@@ -0,0 +1,74 @@
+package gtm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// LivenessSecret is a secret of a liveness test promoted to a sensitive variable, Field is the name of the attribute of
+// the liveness test, values of HTTP headers are named 'http_header_<header name>'
+type LivenessSecret struct {
+	Variable string
+	Property string
+	Test     string
+	Field    string
+}
+
+var (
+	// sensitiveHeader matches names of HTTP headers carrying credentials
+	sensitiveHeader = regexp.MustCompile(`(?i)auth|token|secret|password|cookie|session|api[-_]?key`)
+	// sensitiveRequest matches request strings carrying credentials, e.g. in a header or query string
+	sensitiveRequest = regexp.MustCompile(`(?i)authorization|cookie|password|token|secret|api[-_]?key`)
+)
+
+// findLivenessSecrets collects secrets of liveness tests of all properties: passwords and client private keys of test
+// objects, and HTTP headers and request strings which carry credentials
+func (d *TFDomainData) findLivenessSecrets() {
+	d.LivenessSecrets = nil
+	declared := make(map[string]bool)
+	add := func(property *gtm.Property, test *gtm.LivenessTest, field string) {
+		base := normalizeResourceName(fmt.Sprintf("%s_%s_%s", property.Name, test.Name, field))
+		base = strings.ToLower(strings.ReplaceAll(base, "-", "_"))
+		name := base
+		for i := 2; declared[name]; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+		}
+		declared[name] = true
+		name = tools.VariableName(name)
+		d.LivenessSecrets = append(d.LivenessSecrets, LivenessSecret{Variable: name, Property: property.Name, Test: test.Name, Field: field})
+	}
+
+	for _, property := range d.Properties {
+		for _, test := range property.LivenessTests {
+			if test.TestObjectPassword != "" {
+				add(property, test, "test_object_password")
+			}
+			if test.SslClientPrivateKey != "" {
+				add(property, test, "ssl_client_private_key")
+			}
+			if test.RequestString != "" && sensitiveRequest.MatchString(test.RequestString) {
+				add(property, test, "request_string")
+			}
+			for _, header := range test.HttpHeaders {
+				if header.Value != "" && sensitiveHeader.MatchString(header.Name) {
+					add(property, test, "http_header_"+header.Name)
+				}
+			}
+		}
+	}
+}
+
+// LivenessSecretValue returns reference to the variable holding the field of the liveness test if it is a secret,
+// otherwise the value is returned as a quoted string
+func (d TFDomainData) LivenessSecretValue(property, test, field, value string) string {
+	for _, secret := range d.LivenessSecrets {
+		if secret.Property == property && secret.Test == test && secret.Field == field {
+			return "var." + secret.Variable
+		}
+	}
+	return `"` + value + `"`
+}
@@ -0,0 +1,94 @@
+package gtm
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindLivenessSecrets(t *testing.T) {
+	tests := map[string]struct {
+		givenProperties []*gtm.Property
+		expected        []LivenessSecret
+	}{
+		"no secrets": {
+			givenProperties: []*gtm.Property{
+				{
+					Name: "property",
+					LivenessTests: []*gtm.LivenessTest{
+						{
+							Name:          "HTTP",
+							RequestString: "GET / HTTP/1.1",
+							HttpHeaders:   []*gtm.HttpHeader{{Name: "Accept", Value: "text/html"}},
+						},
+					},
+				},
+			},
+		},
+		"secrets of tests": {
+			givenProperties: []*gtm.Property{
+				{
+					Name: "property",
+					LivenessTests: []*gtm.LivenessTest{
+						{
+							Name:                "HTTPS",
+							TestObjectPassword:  "password",
+							SslClientPrivateKey: "key",
+							HttpHeaders: []*gtm.HttpHeader{
+								{Name: "X-Api-Key", Value: "abc"},
+								{Name: "Cookie", Value: ""},
+							},
+						},
+						{
+							Name:          "TCP",
+							RequestString: "Authorization: Basic abc",
+						},
+					},
+				},
+			},
+			expected: []LivenessSecret{
+				{Variable: "property_https_test_object_password", Property: "property", Test: "HTTPS", Field: "test_object_password"},
+				{Variable: "property_https_ssl_client_private_key", Property: "property", Test: "HTTPS", Field: "ssl_client_private_key"},
+				{Variable: "property_https_http_header_x_api_key", Property: "property", Test: "HTTPS", Field: "http_header_X-Api-Key"},
+				{Variable: "property_tcp_request_string", Property: "property", Test: "TCP", Field: "request_string"},
+			},
+		},
+		"duplicate variable names": {
+			givenProperties: []*gtm.Property{
+				{
+					Name:          "a b",
+					LivenessTests: []*gtm.LivenessTest{{Name: "test", TestObjectPassword: "password"}},
+				},
+				{
+					Name:          "a-b",
+					LivenessTests: []*gtm.LivenessTest{{Name: "test", TestObjectPassword: "password"}},
+				},
+			},
+			expected: []LivenessSecret{
+				{Variable: "a_b_test_test_object_password", Property: "a b", Test: "test", Field: "test_object_password"},
+				{Variable: "a_b_test_test_object_password_2", Property: "a-b", Test: "test", Field: "test_object_password"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := TFDomainData{Properties: test.givenProperties}
+			data.findLivenessSecrets()
+			assert.Equal(t, test.expected, data.LivenessSecrets)
+		})
+	}
+}
+
+func TestLivenessSecretValue(t *testing.T) {
+	data := TFDomainData{
+		LivenessSecrets: []LivenessSecret{
+			{Variable: "property_https_test_object_password", Property: "property", Test: "HTTPS", Field: "test_object_password"},
+		},
+	}
+
+	assert.Equal(t, "var.property_https_test_object_password", data.LivenessSecretValue("property", "HTTPS", "test_object_password", "password"))
+	assert.Equal(t, `"monitor"`, data.LivenessSecretValue("property", "HTTPS", "test_object_username", "monitor"))
+	assert.Equal(t, `"password"`, data.LivenessSecretValue("other", "HTTPS", "test_object_password", "password"))
+}
@@ -0,0 +1,3 @@
+terraform init
+terraform import akamai_gtm_domain.test_name "test.name.akadns.net"
+terraform import akamai_gtm_property.test_property1 "test.name.akadns.net:test property1"
\ No newline at end of file
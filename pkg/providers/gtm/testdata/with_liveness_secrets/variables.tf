@@ -0,0 +1,33 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type    = string
+  default = "test_section"
+}
+
+variable "contractid" {
+  type        = string
+  default     = ""
+  description = "Value unknown at the time of import. Please update."
+}
+
+variable "groupid" {
+  type        = string
+  default     = ""
+  description = "Value unknown at the time of import. Please update."
+}
+
+variable "test_property1_https_test_object_password" {
+  type        = string
+  sensitive   = true
+  description = "test_object_password of liveness test 'HTTPS' of property 'test property1'"
+}
+
+variable "test_property1_https_http_header_authorization" {
+  type        = string
+  sensitive   = true
+  description = "http_header_Authorization of liveness test 'HTTPS' of property 'test property1'"
+}
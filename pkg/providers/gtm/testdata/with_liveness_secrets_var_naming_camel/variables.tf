@@ -0,0 +1,33 @@
+variable "edgercPath" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "configSection" {
+  type    = string
+  default = "test_section"
+}
+
+variable "contractid" {
+  type        = string
+  default     = ""
+  description = "Value unknown at the time of import. Please update."
+}
+
+variable "groupid" {
+  type        = string
+  default     = ""
+  description = "Value unknown at the time of import. Please update."
+}
+
+variable "testProperty1HttpsTestObjectPassword" {
+  type        = string
+  sensitive   = true
+  description = "test_object_password of liveness test 'HTTPS' of property 'test property1'"
+}
+
+variable "testProperty1HttpsHttpHeaderAuthorization" {
+  type        = string
+  sensitive   = true
+  description = "http_header_Authorization of liveness test 'HTTPS' of property 'test property1'"
+}
@@ -5,11 +5,11 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
-	"errors"
 	"fmt"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/iam"
-	"github.com/akamai/cli/pkg/terminal"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/warnings"
 	"github.com/urfave/cli/v2"
 )
 
@@ -73,7 +73,7 @@ var (
 	templateFiles embed.FS
 
 	// ErrFetchingUsers is returned when fetching users fails
-	ErrFetchingUsers = errors.New("unable to fetch users under this account")
+	ErrFetchingUsers = exitcode.New(exitcode.API, "unable to fetch users under this account")
 )
 
 // CmdCreateIAM is an entrypoint to create-iam command. This is only for action validation purpose
@@ -81,7 +81,7 @@ func CmdCreateIAM(_ *cli.Context) error {
 	return nil
 }
 
-func getTFUsers(ctx context.Context, client iam.IAM, users []iam.UserListItem, term terminal.Terminal) ([]*TFUser, error) {
+func getTFUsers(ctx context.Context, client iam.IAM, users []iam.UserListItem) ([]*TFUser, error) {
 	res := make([]*TFUser, 0)
 	for _, v := range users {
 		user, err := client.GetUser(ctx, iam.GetUserRequest{
@@ -91,10 +91,11 @@ func getTFUsers(ctx context.Context, client iam.IAM, users []iam.UserListItem, t
 			Notifications: true,
 		})
 		if err != nil {
-			_, err := term.Writeln(fmt.Sprintf("[WARN] Unable to fetch user of ID '%s' - skipping:\n%s", v.IdentityID, err))
-			if err != nil {
-				return nil, err
-			}
+			warnings.Report(ctx, warnings.Warning{
+				Product: "iam user",
+				Object:  v.IdentityID,
+				Reason:  fmt.Sprintf("unable to fetch user, skipped: %s", err),
+			})
 			continue
 		}
 		tfUser, err := getTFUser(user)
@@ -2,12 +2,13 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path/filepath"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/iam"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
@@ -17,9 +18,9 @@ import (
 
 var (
 	// ErrFetchingGroups is returned when fetching groups fails
-	ErrFetchingGroups = errors.New("unable to fetch groups under this account")
+	ErrFetchingGroups = exitcode.New(exitcode.API, "unable to fetch groups under this account")
 	// ErrFetchingRoles is returned when fetching roles fails
-	ErrFetchingRoles = errors.New("unable to fetch roles under this account")
+	ErrFetchingRoles = exitcode.New(exitcode.API, "unable to fetch roles under this account")
 )
 
 // CmdCreateIAMAll is an entrypoint to create-iam all command
@@ -42,7 +43,7 @@ func CmdCreateIAMAll(c *cli.Context) error {
 
 	err := tools.CheckFiles(groupsPath, importPath, rolesPath, usersPath, variablesPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 
 	templateToFile := map[string]string{
@@ -56,12 +57,14 @@ func CmdCreateIAMAll(c *cli.Context) error {
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 	}
 
 	section := edgegrid.GetEdgercSection(c)
 
 	if err := createIAMAll(ctx, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
@@ -73,23 +76,23 @@ func createIAMAll(ctx context.Context, section string, client iam.IAM, templateP
 		return err
 	}
 
-	term.Spinner().Start("Fetching all available users")
+	progress.Get(ctx).Start("Fetching all available users")
 	users, err := client.ListUsers(ctx, iam.ListUsersRequest{Actions: true})
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingUsers, err)
 	}
-	tfUsers, err := getTFUsers(ctx, client, filterUsers(users), term)
+	tfUsers, err := getTFUsers(ctx, client, filterUsers(users))
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Fetching all available groups")
+	progress.Get(ctx).Start("Fetching all available groups")
 	groups, err := client.ListGroups(ctx, iam.ListGroupsRequest{Actions: true})
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingGroups, err)
 	}
 	tfGroups := make([]TFGroup, 0)
@@ -98,24 +101,24 @@ func createIAMAll(ctx context.Context, section string, client iam.IAM, templateP
 			tfGroups = append(tfGroups, getTFGroup(&innerGroup))
 		}
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Fetching all available roles")
+	progress.Get(ctx).Start("Fetching all available roles")
 	roles, err := client.ListRoles(ctx, iam.ListRolesRequest{
 		Actions:       true,
 		IgnoreContext: true,
 		Users:         true,
 	})
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingRoles, err)
 	}
 	tfRoles, err := getTFRoles(ctx, client, roles)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingRoles, err)
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfData := TFData{
 		TFUsers:    tfUsers,
@@ -125,12 +128,12 @@ func createIAMAll(ctx context.Context, section string, client iam.IAM, templateP
 		Subcommand: "all",
 	}
 
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err = templateProcessor.ProcessTemplates(tfData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	_, err = term.Writeln("Terraform configuration was saved successfully")
 	if err != nil {
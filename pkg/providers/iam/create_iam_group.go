@@ -2,13 +2,14 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/iam"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
@@ -18,9 +19,9 @@ import (
 
 var (
 	// ErrFetchingUsersWithinGroup is returned when fetching users within group fails
-	ErrFetchingUsersWithinGroup = errors.New("unable to fetch users within group")
+	ErrFetchingUsersWithinGroup = exitcode.New(exitcode.API, "unable to fetch users within group")
 	// ErrFetchingRolesWithinGroup is returned when fetching roles within group fails
-	ErrFetchingRolesWithinGroup = errors.New("unable to fetch roles within group")
+	ErrFetchingRolesWithinGroup = exitcode.New(exitcode.API, "unable to fetch roles within group")
 )
 
 // CmdCreateIAMGroup is an entrypoint to create-iam group command
@@ -43,7 +44,7 @@ func CmdCreateIAMGroup(c *cli.Context) error {
 
 	err := tools.CheckFiles(groupPath, usersPath, rolesPath, variablesPath, importPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 
 	templateToFile := map[string]string{
@@ -57,15 +58,17 @@ func CmdCreateIAMGroup(c *cli.Context) error {
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 	}
 
 	section := edgegrid.GetEdgercSection(c)
 	groupID, err := strconv.ParseInt(c.Args().First(), 10, 64)
 	if err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Wrong format of group id %v must be a number: %s", groupID, err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Wrong format of group id %v must be a number: %s", groupID, err)), exitcode.General)
 	}
 	if err = createIAMGroupByID(ctx, groupID, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
@@ -77,33 +80,33 @@ func createIAMGroupByID(ctx context.Context, groupID int64, section string, clie
 		return err
 	}
 
-	term.Spinner().Start("Fetching group by id " + strconv.FormatInt(groupID, 10))
+	progress.Get(ctx).Start("Fetching group by id " + strconv.FormatInt(groupID, 10))
 	group, err := client.GetGroup(ctx, iam.GetGroupRequest{
 		GroupID: groupID,
 	})
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfGroup := getTFGroup(group)
 
-	term.Spinner().Start("Fetching users within group with id " + strconv.FormatInt(groupID, 10))
-	tfUsers, err := getUsersWithinGroup(ctx, client, groupID, term)
+	progress.Get(ctx).Start("Fetching users within group with id " + strconv.FormatInt(groupID, 10))
+	tfUsers, err := getUsersWithinGroup(ctx, client, groupID)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Fetching user's relative roles within group " + strconv.FormatInt(groupID, 10))
+	progress.Get(ctx).Start("Fetching user's relative roles within group " + strconv.FormatInt(groupID, 10))
 	tfRoles, err := getRolesWithinGroup(ctx, client, groupID)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfData := TFData{
 		TFUsers: tfUsers,
@@ -115,12 +118,12 @@ func createIAMGroupByID(ctx context.Context, groupID int64, section string, clie
 		Subcommand: "group",
 	}
 
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err = templateProcessor.ProcessTemplates(tfData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 	_, err = term.Writeln(fmt.Sprintf("Terraform configuration for group with id '%v' was saved successfully", groupID))
 	if err != nil {
 		return nil
@@ -129,7 +132,7 @@ func createIAMGroupByID(ctx context.Context, groupID int64, section string, clie
 	return nil
 }
 
-func getUsersWithinGroup(ctx context.Context, client iam.IAM, groupID int64, term terminal.Terminal) ([]*TFUser, error) {
+func getUsersWithinGroup(ctx context.Context, client iam.IAM, groupID int64) ([]*TFUser, error) {
 	users, err := client.ListUsers(ctx, iam.ListUsersRequest{
 		Actions: true,
 		GroupID: tools.Int64Ptr(groupID),
@@ -138,7 +141,7 @@ func getUsersWithinGroup(ctx context.Context, client iam.IAM, groupID int64, ter
 		return nil, fmt.Errorf("%w: %v with error %s", ErrFetchingUsersWithinGroup, groupID, err)
 	}
 
-	return getTFUsers(ctx, client, filterUsers(users), term)
+	return getTFUsers(ctx, client, filterUsers(users))
 }
 
 func getRolesWithinGroup(ctx context.Context, client iam.IAM, groupID int64) ([]TFRole, error) {
@@ -2,15 +2,17 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path/filepath"
 	"strconv"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/iam"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -18,7 +20,7 @@ import (
 
 var (
 	// ErrFetchingRole is returned when fetching role fails
-	ErrFetchingRole = errors.New("unable to fetch role by role_id")
+	ErrFetchingRole = exitcode.New(exitcode.API, "unable to fetch role by role_id")
 )
 
 // CmdCreateIAMRole is an entrypoint to create-iam role command
@@ -41,7 +43,7 @@ func CmdCreateIAMRole(c *cli.Context) error {
 
 	err := tools.CheckFiles(userPath, groupPath, rolesPath, variablesPath, importPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 
 	templateToFile := map[string]string{
@@ -55,16 +57,18 @@ func CmdCreateIAMRole(c *cli.Context) error {
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 	}
 
 	section := edgegrid.GetEdgercSection(c)
 	roleID, err := strconv.ParseInt(c.Args().First(), 10, 64)
 	if err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Wrong format of role id %v must be a number: %s", roleID, err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Wrong format of role id %v must be a number: %s", roleID, err)), exitcode.General)
 	}
 
 	if err = createIAMRoleByID(ctx, roleID, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
@@ -75,7 +79,7 @@ func createIAMRoleByID(ctx context.Context, roleID int64, section string, client
 	if err != nil {
 		return err
 	}
-	term.Spinner().Start(fmt.Sprintf("Fetching role by role_id %d", roleID))
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching role by role_id %d", roleID))
 
 	role, err := client.GetRole(ctx, iam.GetRoleRequest{
 		ID:           roleID,
@@ -83,10 +87,10 @@ func createIAMRoleByID(ctx context.Context, roleID int64, section string, client
 		Users:        true,
 	})
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingRole, err)
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfRole := TFRole{
 		RoleID:          role.RoleID,
@@ -95,22 +99,23 @@ func createIAMRoleByID(ctx context.Context, roleID int64, section string, client
 		GrantedRoles:    getGrantedRolesID(role.GrantedRoles),
 	}
 
-	term.Spinner().Start(fmt.Sprintf("Fetching users with the given role %d", roleID))
-	users, err := getUsersByRole(ctx, term, role.Users, client)
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching users with the given role %d", roleID))
+	users, err := getUsersByRole(ctx, role.Users, client)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfUsers := make([]*TFUser, 0)
 	tfGroups := make([]TFGroup, 0)
 
-	term.Spinner().Start(fmt.Sprintf("Fetching groups for users related within role %d", roleID))
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching groups for users related within role %d", roleID))
+	progress.Get(ctx).Total(len(users))
 	for _, user := range users {
 		userData, err := getTFUser(user)
 		if err != nil {
-			term.Spinner().Fail()
+			progress.Get(ctx).Fail()
 			return err
 		}
 
@@ -120,14 +125,15 @@ func createIAMRoleByID(ctx context.Context, roleID int64, section string, client
 		if len(authGrantsList) > 0 {
 			groupsData, err := getTFUserGroups(ctx, client, authGrantsList)
 			if err != nil {
-				term.Spinner().Fail()
+				progress.Get(ctx).Fail()
 				return err
 			}
 
 			tfGroups = appendUniqueGroups(tfGroups, groupsData)
 		}
+		progress.Get(ctx).Step()
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfData := TFData{
 		TFUsers:  tfUsers,
@@ -140,12 +146,12 @@ func createIAMRoleByID(ctx context.Context, roleID int64, section string, client
 		Subcommand: "role",
 	}
 
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err = templateProcessor.ProcessTemplates(tfData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 	_, err = term.Writeln(fmt.Sprintf("Terraform configuration for role with id '%d' was saved successfully", tfRole.RoleID))
 	if err != nil {
 		return nil
@@ -171,7 +177,7 @@ func appendUniqueGroups(tfGroups []TFGroup, groupsData []TFGroup) []TFGroup {
 	return tfGroups
 }
 
-func getUsersByRole(ctx context.Context, term terminal.Terminal, roleUsers []iam.RoleUser, client iam.IAM) ([]*iam.User, error) {
+func getUsersByRole(ctx context.Context, roleUsers []iam.RoleUser, client iam.IAM) ([]*iam.User, error) {
 	users := make([]*iam.User, 0)
 
 	for _, roleUser := range roleUsers {
@@ -182,10 +188,11 @@ func getUsersByRole(ctx context.Context, term terminal.Terminal, roleUsers []iam
 			Notifications: true,
 		})
 		if err != nil {
-			_, err := term.Writeln(fmt.Sprintf("[WARN] Unable to fetch user of ID '%s' - skipping:\n%s", roleUser.UIIdentityID, err))
-			if err != nil {
-				return nil, err
-			}
+			warnings.Report(ctx, warnings.Warning{
+				Product: "iam user",
+				Object:  roleUser.UIIdentityID,
+				Reason:  fmt.Sprintf("unable to fetch user, skipped: %s", err),
+			})
 			continue
 		}
 
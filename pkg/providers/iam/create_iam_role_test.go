@@ -298,7 +298,7 @@ func TestGetUsersByRole(t *testing.T) {
 					AuthGrants:    true,
 					Notifications: true,
 				}).Return(nil, fmt.Errorf("an error")).Once()
-				t.On("Writeln", []interface{}{"[WARN] Unable to fetch user of ID 'a' - skipping:\nan error"}).Return(0, nil).Once()
+				t.On("Writeln", []interface{}{"Warning: iam user 'a': unable to fetch user, skipped: an error"}).Return(0, nil).Once()
 				m.On("GetUser", mock.Anything, iam.GetUserRequest{
 					IdentityID:    "b",
 					Actions:       true,
@@ -329,14 +329,14 @@ func TestGetUsersByRole(t *testing.T) {
 					AuthGrants:    true,
 					Notifications: true,
 				}).Return(nil, fmt.Errorf("an error")).Once()
-				t.On("Writeln", []interface{}{"[WARN] Unable to fetch user of ID 'a' - skipping:\nan error"}).Return(0, nil).Once()
+				t.On("Writeln", []interface{}{"Warning: iam user 'a': unable to fetch user, skipped: an error"}).Return(0, nil).Once()
 				m.On("GetUser", mock.Anything, iam.GetUserRequest{
 					IdentityID:    "b",
 					Actions:       true,
 					AuthGrants:    true,
 					Notifications: true,
 				}).Return(nil, fmt.Errorf("another error")).Once()
-				t.On("Writeln", []interface{}{"[WARN] Unable to fetch user of ID 'b' - skipping:\nanother error"}).Return(0, nil).Once()
+				t.On("Writeln", []interface{}{"Warning: iam user 'b': unable to fetch user, skipped: another error"}).Return(0, nil).Once()
 			},
 		},
 	}
@@ -346,7 +346,7 @@ func TestGetUsersByRole(t *testing.T) {
 			term := terminal.Mock{}
 			test.init(&client, &term)
 
-			result, err := getUsersByRole(context.Background(), &term, test.roleUsers, &client)
+			result, err := getUsersByRole(terminal.Context(context.Background(), &term), test.roleUsers, &client)
 			if test.withError != nil {
 				require.Error(t, err)
 				assert.True(t, errors.Is(err, test.withError))
@@ -2,12 +2,13 @@ package iam
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"path/filepath"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/iam"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/akamai/cli/pkg/terminal"
@@ -17,11 +18,11 @@ import (
 
 var (
 	// ErrFetchingUser is returned when fetching user fails
-	ErrFetchingUser = errors.New("unable to fetch user by email")
+	ErrFetchingUser = exitcode.New(exitcode.API, "unable to fetch user by email")
 	// ErrUserNotExist is returned when user does not exist
-	ErrUserNotExist = errors.New("user does not exist with given email")
+	ErrUserNotExist = exitcode.New(exitcode.NotFound, "user does not exist with given email")
 	// ErrMarshalUserAuthGrants is returned when marshal user auth grants failed
-	ErrMarshalUserAuthGrants = errors.New("unable to marshal AuthGrants ")
+	ErrMarshalUserAuthGrants = exitcode.New(exitcode.Template, "unable to marshal AuthGrants ")
 )
 
 // CmdCreateIAMUser is an entrypoint to create-iam user command
@@ -44,7 +45,7 @@ func CmdCreateIAMUser(c *cli.Context) error {
 
 	err := tools.CheckFiles(userPath, groupPath, rolesPath, variablesPath, importPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 
 	templateToFile := map[string]string{
@@ -58,12 +59,14 @@ func CmdCreateIAMUser(c *cli.Context) error {
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 	}
 
 	section := edgegrid.GetEdgercSection(c)
 	email := c.Args().First()
 	if err = createIAMUserByEmail(ctx, email, section, client, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), 1)
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting HCL for IAM: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
@@ -74,18 +77,18 @@ func createIAMUserByEmail(ctx context.Context, userEmail, section string, client
 	if err != nil {
 		return err
 	}
-	term.Spinner().Start("Fetching user by email " + userEmail)
+	progress.Get(ctx).Start("Fetching user by email " + userEmail)
 
 	user, err := getUserByEmail(ctx, client, userEmail)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfUserData, err := getTFUser(user)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
 
@@ -100,29 +103,29 @@ func createIAMUserByEmail(ctx context.Context, userEmail, section string, client
 	}
 
 	if len(authGrantsList) > 0 {
-		term.Spinner().Start("Fetching roles for user " + userEmail)
+		progress.Get(ctx).Start("Fetching roles for user " + userEmail)
 		tfData.TFRoles, err = getTFUserRoles(ctx, client, authGrantsList)
 		if err != nil {
-			term.Spinner().Fail()
+			progress.Get(ctx).Fail()
 			return err
 		}
-		term.Spinner().OK()
+		progress.Get(ctx).OK()
 
-		term.Spinner().Start("Fetching groups for user " + userEmail)
+		progress.Get(ctx).Start("Fetching groups for user " + userEmail)
 		tfData.TFGroups, err = getTFUserGroups(ctx, client, authGrantsList)
 		if err != nil {
-			term.Spinner().Fail()
+			progress.Get(ctx).Fail()
 			return err
 		}
-		term.Spinner().OK()
+		progress.Get(ctx).OK()
 	}
 
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err = templateProcessor.ProcessTemplates(tfData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 	_, err = term.Writeln(fmt.Sprintf("Terraform configuration for user with email '%s' was saved successfully", tfUserData.Email))
 	if err != nil {
 		return nil
@@ -1,9 +1,9 @@
 terraform init
-terraform import akamai_iam_user.iam_user_001 001
-terraform import akamai_iam_user.iam_user_002 002
 terraform import akamai_iam_role.role_id_201 201
 terraform import akamai_iam_role.role_id_202 202
 terraform import akamai_iam_group.group_id_101 101
 terraform import akamai_iam_group.group_id_102 102
 terraform import akamai_iam_group.group_id_112 112
 terraform import akamai_iam_group.group_id_123 123
+terraform import akamai_iam_user.iam_user_001 001
+terraform import akamai_iam_user.iam_user_002 002
@@ -1,4 +1,4 @@
 terraform init
-terraform import akamai_iam_user.iam_user_123 123
 terraform import akamai_iam_role.role_id_12345 12345
 terraform import akamai_iam_group.group_id_56789 56789
+terraform import akamai_iam_user.iam_user_123 123
@@ -7,20 +7,24 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
-	"strings"
 	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/imaging"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/identity"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/secrets"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -32,15 +36,19 @@ type (
 		PolicySet TFPolicySet
 		Policies  []TFPolicy
 		Section   string
+		// Rollback is set when previous version of any policy is exported, it adds the variable selecting policies to roll back
+		Rollback bool
 	}
 
 	// TFPolicySet represents policy set data used in templates
 	TFPolicySet struct {
 		ID         string
 		ContractID string
-		Name       string
-		Region     string
-		Type       string
+		// ContractName is empty when it is not known
+		ContractName string
+		Name         string
+		Region       string
+		Type         string
 	}
 
 	// TFPolicy represents policy data used in templates
@@ -49,6 +57,16 @@ type (
 		ActivateOnProduction bool
 		JSON                 string
 		Policy               imaging.PolicyInput
+		// PreviousJSON is the path of JSON file with previous version of the policy, used when the policy is rolled back
+		PreviousJSON string
+	}
+
+	// policyExport holds options of the policies export shared by image and video policies
+	policyExport struct {
+		policySetID, contractID string
+		tfWorkPath, jsonDir     string
+		schema                  bool
+		previous                bool
 	}
 )
 
@@ -59,11 +77,11 @@ var (
 	// RemoveSymbols is a regexp used to remove special characters from policy json file names.
 	RemoveSymbols = regexp.MustCompile(`[^\w]`)
 	// ErrFetchingPolicySet is returned when fetching policy set fails
-	ErrFetchingPolicySet = errors.New("unable to fetch policy set with given name")
+	ErrFetchingPolicySet = exitcode.New(exitcode.API, "unable to fetch policy set with given name")
 	// ErrFetchingPolicy is returned when fetching policy set fails
-	ErrFetchingPolicy = errors.New("unable to fetch policy with given name")
+	ErrFetchingPolicy = exitcode.New(exitcode.API, "unable to fetch policy with given name")
 	// ErrCreateDir is returned when error occurred creating directory
-	ErrCreateDir = errors.New("cannot create directory")
+	ErrCreateDir = exitcode.New(exitcode.IO, "cannot create directory")
 )
 
 // maxDepth value has to match the MaxPolicyDepth value in terraform imaging subprovider
@@ -86,18 +104,19 @@ func CmdCreateImaging(c *cli.Context) error {
 
 	err := tools.CheckFiles(imagingPath, variablesPath, importPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 
 	jsonDir := "."
 	if c.IsSet("policy-json-dir") {
 		jsonDir = c.String("policy-json-dir")
 	}
-	if !c.IsSet("schema") {
+	// files written into other sinks than the filesystem do not need directories
+	if !c.IsSet("schema") && templates.IsDirSink(templates.GetSink(ctx)) {
 		jsonDirPath := path.Join(tfWorkPath, jsonDir)
 		err = ensureDirExists(jsonDirPath)
 		if err != nil {
-			return cli.Exit(color.RedString(err.Error()), 1)
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 		}
 	}
 
@@ -110,68 +129,79 @@ func CmdCreateImaging(c *cli.Context) error {
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
 		AdditionalFuncs: template.FuncMap{
-			"ToLower": func(val string) string {
-				return strings.ToLower(val)
-			},
 			"RemoveSymbols": func(val string) string {
 				return RemoveSymbols.ReplaceAllString(val, "_")
 			},
 		},
 	}
 
-	contractID, policySetID := c.Args().Get(0), c.Args().Get(1)
+	contract, err := identity.NewResolver(papi.Client(sess)).Contract(ctx, c.Args().Get(0))
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting policy HCL: %s", err)), exitcode.Of(err))
+	}
+	policySetID := c.Args().Get(1)
 	section := edgegrid.GetEdgercSection(c)
-	if err = createImaging(ctx, contractID, policySetID, tfWorkPath, jsonDir, section, client, processor, tools.Schema); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting policy HCL: %s", err)), 1)
+	if err = createImaging(ctx, contract, policySetID, tfWorkPath, jsonDir, section, client, processor, tools.Schema, c.Bool("previous-version")); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting policy HCL: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
 
-func createImaging(ctx context.Context, contractID, policySetID, tfWorkPath, jsonDir, section string, client imaging.Imaging, templateProcessor templates.TemplateProcessor, schema bool) error {
-	term := terminal.Get(ctx)
-
-	fmt.Println("Exporting Image and Video Manager configuration")
-	term.Spinner().Start("Fetching policy set " + policySetID)
+func createImaging(ctx context.Context, contract identity.Contract, policySetID, tfWorkPath, jsonDir, section string, client imaging.Imaging, templateProcessor templates.TemplateProcessor, schema, previous bool) error {
+	terminal.Get(ctx).Printf("Exporting Image and Video Manager configuration\n")
+	contractID := contract.ID
+	progress.Get(ctx).Start("Fetching policy set " + policySetID)
 
 	policySet, err := client.GetPolicySet(ctx, imaging.GetPolicySetRequest{
 		PolicySetID: policySetID,
 		ContractID:  contractID,
 	})
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingPolicySet, err)
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Fetching policies for the given policy set " + policySetID)
+	progress.Get(ctx).Start("Fetching policies for the given policy set " + policySetID)
 	policies, err := getPolicies(ctx, policySetID, contractID, client)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingPolicy, err)
 	}
 
+	export := policyExport{
+		policySetID: policySetID,
+		contractID:  contractID,
+		tfWorkPath:  tfWorkPath,
+		jsonDir:     jsonDir,
+		schema:      schema,
+		previous:    previous,
+	}
 	var tfPoliciesData []TFPolicy
 	switch policySet.Type {
 	case string(imaging.TypeImage):
-		tfPoliciesData, err = getPoliciesImageData(ctx, policies, policySetID, contractID, tfWorkPath, jsonDir, client, schema)
+		tfPoliciesData, err = getPoliciesImageData(ctx, policies, export, client)
 	case string(imaging.TypeVideo):
-		tfPoliciesData, err = getPoliciesVideoData(ctx, policies, policySetID, contractID, tfWorkPath, jsonDir, client, schema)
+		tfPoliciesData, err = getPoliciesVideoData(ctx, policies, export, client)
 	}
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingPolicy, err)
 	}
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	tfData := TFImagingData{
 		PolicySet: TFPolicySet{
-			ID:         policySet.ID,
-			ContractID: contractID,
-			Name:       policySet.Name,
-			Region:     string(policySet.Region),
-			Type:       policySet.Type,
+			ID:           policySet.ID,
+			ContractID:   contractID,
+			ContractName: contract.Name,
+			Name:         policySet.Name,
+			Region:       string(policySet.Region),
+			Type:         policySet.Type,
 		},
 		Section: section,
 	}
@@ -180,14 +210,19 @@ func createImaging(ctx context.Context, contractID, policySetID, tfWorkPath, jso
 	if tfPoliciesData != nil {
 		tfData.Policies = tfPoliciesData
 	}
+	for _, policy := range tfPoliciesData {
+		if policy.PreviousJSON != "" {
+			tfData.Rollback = true
+		}
+	}
 
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err := templateProcessor.ProcessTemplates(tfData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return err
 	}
-	term.Spinner().OK()
-	fmt.Printf("Terraform configuration for policy set '%s' was saved successfully\n", policySet.ID)
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for policy set '%s' was saved successfully\n", policySet.ID)
 
 	return nil
 }
@@ -215,7 +250,7 @@ func getPolicies(ctx context.Context, policySetID, contractID string, client ima
 	return stagingPolicies.Items, nil
 }
 
-func getPoliciesImageData(ctx context.Context, policies []imaging.PolicyOutput, policySetID, contractID, tfWorkPath, jsonDir string, client imaging.Imaging, schema bool) ([]TFPolicy, error) {
+func getPoliciesImageData(ctx context.Context, policies []imaging.PolicyOutput, export policyExport, client imaging.Imaging) ([]TFPolicy, error) {
 	var tfPoliciesData []TFPolicy
 
 	for _, policyOutput := range policies {
@@ -233,8 +268,8 @@ func getPoliciesImageData(ctx context.Context, policies []imaging.PolicyOutput,
 		policyProductionOutput, err := client.GetPolicy(ctx, imaging.GetPolicyRequest{
 			PolicyID:    policy.ID,
 			Network:     imaging.PolicyNetworkProduction,
-			ContractID:  contractID,
-			PolicySetID: policySetID,
+			ContractID:  export.contractID,
+			PolicySetID: export.policySetID,
 		})
 		if err != nil {
 			var e *imaging.Error
@@ -257,7 +292,16 @@ func getPoliciesImageData(ctx context.Context, policies []imaging.PolicyOutput,
 			}
 		}
 
-		if schema {
+		var previousJSON string
+		if export.previous {
+			var previous imaging.PolicyInputImage
+			previousJSON, err = getPreviousPolicyJSON(ctx, client, export, policy.ID, policy.PreviousVersion, &previous)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if export.schema {
 			// we store JSON as PolicyInput, so we need to convert it from PolicyInput via JSON representation
 			var policyInput imaging.PolicyInputImage
 			if err := json.Unmarshal([]byte(policyJSON), &policyInput); err != nil {
@@ -270,11 +314,11 @@ func getPoliciesImageData(ctx context.Context, policies []imaging.PolicyOutput,
 				PolicyID:             policy.ID,
 				ActivateOnProduction: activateOnProduction,
 				Policy:               &policyInput,
+				PreviousJSON:         previousJSON,
 			})
 		} else {
-			jsonPath := filepath.Join(jsonDir, RemoveSymbols.ReplaceAllString(policy.ID, "_")+".json")
-			err = ioutil.WriteFile(filepath.Join(tfWorkPath, jsonPath), []byte(policyJSON), 0644)
-			if err != nil {
+			jsonPath := filepath.Join(export.jsonDir, RemoveSymbols.ReplaceAllString(policy.ID, "_")+".json")
+			if err := writePolicyJSON(ctx, export.tfWorkPath, jsonPath, policyJSON); err != nil {
 				return nil, err
 			}
 
@@ -282,6 +326,7 @@ func getPoliciesImageData(ctx context.Context, policies []imaging.PolicyOutput,
 				PolicyID:             policy.ID,
 				ActivateOnProduction: activateOnProduction,
 				JSON:                 jsonPath,
+				PreviousJSON:         previousJSON,
 			})
 		}
 	}
@@ -289,7 +334,7 @@ func getPoliciesImageData(ctx context.Context, policies []imaging.PolicyOutput,
 	return tfPoliciesData, nil
 }
 
-func getPoliciesVideoData(ctx context.Context, policies []imaging.PolicyOutput, policySetID, contractID, tfWorkPath, jsonDir string, client imaging.Imaging, schema bool) ([]TFPolicy, error) {
+func getPoliciesVideoData(ctx context.Context, policies []imaging.PolicyOutput, export policyExport, client imaging.Imaging) ([]TFPolicy, error) {
 	var tfPoliciesData []TFPolicy
 
 	for _, policyOutput := range policies {
@@ -307,8 +352,8 @@ func getPoliciesVideoData(ctx context.Context, policies []imaging.PolicyOutput,
 		policyProductionOutput, err := client.GetPolicy(ctx, imaging.GetPolicyRequest{
 			PolicyID:    policy.ID,
 			Network:     imaging.PolicyNetworkProduction,
-			ContractID:  contractID,
-			PolicySetID: policySetID,
+			ContractID:  export.contractID,
+			PolicySetID: export.policySetID,
 		})
 		if err != nil {
 			var e *imaging.Error
@@ -331,7 +376,16 @@ func getPoliciesVideoData(ctx context.Context, policies []imaging.PolicyOutput,
 			}
 		}
 
-		if schema {
+		var previousJSON string
+		if export.previous {
+			var previous imaging.PolicyInputVideo
+			previousJSON, err = getPreviousPolicyJSON(ctx, client, export, policy.ID, policy.PreviousVersion, &previous)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if export.schema {
 			// we store JSON as PolicyInput, so we need to convert it from PolicyInput via JSON representation
 			var policyInput imaging.PolicyInputVideo
 			if err := json.Unmarshal([]byte(policyJSON), &policyInput); err != nil {
@@ -341,11 +395,11 @@ func getPoliciesVideoData(ctx context.Context, policies []imaging.PolicyOutput,
 				PolicyID:             policy.ID,
 				ActivateOnProduction: activateOnProduction,
 				Policy:               &policyInput,
+				PreviousJSON:         previousJSON,
 			})
 		} else {
-			jsonPath := filepath.Join(jsonDir, RemoveSymbols.ReplaceAllString(policy.ID, "_")+".json")
-			err = ioutil.WriteFile(filepath.Join(tfWorkPath, jsonPath), []byte(policyJSON), 0644)
-			if err != nil {
+			jsonPath := filepath.Join(export.jsonDir, RemoveSymbols.ReplaceAllString(policy.ID, "_")+".json")
+			if err := writePolicyJSON(ctx, export.tfWorkPath, jsonPath, policyJSON); err != nil {
 				return nil, err
 			}
 
@@ -353,6 +407,7 @@ func getPoliciesVideoData(ctx context.Context, policies []imaging.PolicyOutput,
 				PolicyID:             policy.ID,
 				ActivateOnProduction: activateOnProduction,
 				JSON:                 jsonPath,
+				PreviousJSON:         previousJSON,
 			})
 		}
 	}
@@ -360,6 +415,57 @@ func getPoliciesVideoData(ctx context.Context, policies []imaging.PolicyOutput,
 	return tfPoliciesData, nil
 }
 
+// writePolicyJSON writes JSON of the policy into jsonPath relative to tfWorkPath
+func writePolicyJSON(ctx context.Context, tfWorkPath, jsonPath, policyJSON string) error {
+	sink := templates.GetSink(ctx)
+	content, err := secrets.Filter(sink, filepath.Join(tfWorkPath, jsonPath), []byte(policyJSON))
+	if err != nil {
+		return err
+	}
+	return sink.WriteFile(filepath.Join(tfWorkPath, jsonPath), tools.ApplyLineEndings(content))
+}
+
+// getPreviousPolicyJSON writes previous version of the policy found in its history on staging network into JSON file
+// next to the current one and returns its path, previous is the policy input the history entry is decoded into.
+// Empty path is returned for the first version of the policy or when the previous version is missing in the history.
+func getPreviousPolicyJSON(ctx context.Context, client imaging.Imaging, export policyExport, policyID string, version int, previous imaging.PolicyInput) (string, error) {
+	if version == 0 {
+		return "", nil
+	}
+	history, err := client.GetPolicyHistory(ctx, imaging.GetPolicyHistoryRequest{
+		PolicyID:    policyID,
+		Network:     imaging.PolicyNetworkStaging,
+		ContractID:  export.contractID,
+		PolicySetID: export.policySetID,
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, item := range history.Items {
+		if item.Version != version {
+			continue
+		}
+		if err := json.Unmarshal([]byte(item.Policy), previous); err != nil {
+			return "", err
+		}
+		previousJSON, err := json.MarshalIndent(previous, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		jsonPath := filepath.Join(export.jsonDir, RemoveSymbols.ReplaceAllString(policyID, "_")+"_previous.json")
+		if err := writePolicyJSON(ctx, export.tfWorkPath, jsonPath, string(previousJSON)); err != nil {
+			return "", err
+		}
+		return jsonPath, nil
+	}
+	warnings.Report(ctx, warnings.Warning{
+		Product: "imaging policy",
+		Object:  policyID,
+		Reason:  fmt.Sprintf("previous version %d is not in the policy history, the policy cannot be rolled back", version),
+	})
+	return "", nil
+}
+
 func getPolicyImageJSON(policy *imaging.PolicyOutputImage) (string, error) {
 	policyJSON, err := json.MarshalIndent(policy, "", "  ")
 	if err != nil {
@@ -9,12 +9,12 @@ import (
 	"log"
 	"os"
 	"path"
-	"strings"
 	"testing"
 	"text/template"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/imaging"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/identity"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/stretchr/testify/assert"
@@ -608,9 +608,6 @@ var (
 				"imports.tmpl":   fmt.Sprintf("./testdata/res/%s/import.sh", testdir),
 			},
 			AdditionalFuncs: template.FuncMap{
-				"ToLower": func(val string) string {
-					return strings.ToLower(val)
-				},
 				"RemoveSymbols": func(val string) string {
 					return RemoveSymbols.ReplaceAllString(val, "_")
 				},
@@ -652,6 +649,23 @@ var (
 		return call.Return(policyOutput, nil)
 	}
 
+	expectGetPolicyHistory = func(i *imaging.Mock, policyID string, items []imaging.PolicyHistoryItem, err error) *mock.Call {
+		call := i.On(
+			"GetPolicyHistory",
+			mock.Anything,
+			imaging.GetPolicyHistoryRequest{
+				PolicyID:    policyID,
+				Network:     imaging.PolicyNetworkStaging,
+				ContractID:  "ctr_123",
+				PolicySetID: "test_policyset_id",
+			},
+		)
+		if err != nil {
+			return call.Return(nil, err)
+		}
+		return call.Return(&imaging.GetPolicyHistoryResponse{Items: items, TotalItems: len(items)}, nil)
+	}
+
 	expectListPolicies = func(i *imaging.Mock, policySetID, contractID, itemKind string, network imaging.PolicyNetwork,
 		items imaging.PolicyOutputs, totalItems int, err error) *mock.Call {
 		call := i.On(
@@ -684,6 +698,7 @@ func TestCreateImaging(t *testing.T) {
 		jsonDir      string
 		withError    error
 		schema       bool
+		previous     bool
 	}{
 		"fetch policy set with given id and contract and no policies": {
 			init: func(i *imaging.Mock) {
@@ -842,6 +857,72 @@ func TestCreateImaging(t *testing.T) {
 			dataDir:      "json/video_policies_diff_prod",
 			filesToCheck: []string{"_auto.json", "test_policy_video.json", "imaging.tf", "import.sh", "variables.tf"},
 		},
+		"fetch policy set with image policies and previous versions": {
+			init: func(i *imaging.Mock) {
+				policy := *imagePoliciesOutputs[1].(*imaging.PolicyOutputImage)
+				policy.PreviousVersion = 1
+				expectGetPolicySet(i, "test_policyset_id", "ctr_123", "some policy set", "IMAGE", "EMEA", nil).Once()
+				expectListPolicies(i, "test_policyset_id", "ctr_123", "POLICY", imaging.PolicyNetworkStaging,
+					imaging.PolicyOutputs{imagePoliciesOutputs[0], &policy}, 2, nil).Once()
+				expectGetPolicy(i, policiesRequests[0], imagePoliciesOutputs[0], nil)
+				expectGetPolicy(i, policiesRequests[1], &policy, nil)
+				// policyID: .auto has no previous version, so its history is not fetched
+				expectGetPolicyHistory(i, "test_policy_image", []imaging.PolicyHistoryItem{
+					{ID: "test_policy_image", Version: 2, Policy: `{"breakpoints":{"widths":[420,640,1024,2048,5000]}}`},
+					{ID: "test_policy_image", Version: 1, Policy: `{"breakpoints":{"widths":[320,640]},"output":{"perceptualQuality":"medium"},"video":false}`},
+				}, nil)
+			},
+			dataDir:      "json/image_policies_previous",
+			previous:     true,
+			filesToCheck: []string{"_auto.json", "test_policy_image.json", "test_policy_image_previous.json", "imaging.tf", "import.sh", "variables.tf"},
+		},
+		"fetch policy set with video policies and previous versions as schema": {
+			init: func(i *imaging.Mock) {
+				policy := *videoPoliciesOutputs[1].(*imaging.PolicyOutputVideo)
+				policy.PreviousVersion = 1
+				expectGetPolicySet(i, "test_policyset_id", "ctr_123", "some policy set", "VIDEO", "EMEA", nil).Once()
+				expectListPolicies(i, "test_policyset_id", "ctr_123", "POLICY", imaging.PolicyNetworkStaging,
+					imaging.PolicyOutputs{videoPoliciesOutputs[0], &policy}, 2, nil).Once()
+				expectGetPolicy(i, policiesRequests[0], videoPoliciesOutputs[0], nil)
+				expectGetPolicy(i, policiesRequests[2], &policy, nil)
+				expectGetPolicyHistory(i, "test_policy_video", []imaging.PolicyHistoryItem{
+					{ID: "test_policy_video", Version: 1, Policy: `{"breakpoints":{"widths":[320,640]},"video":true}`},
+				}, nil)
+			},
+			dataDir:      "json/video_policies_schema_previous",
+			schema:       true,
+			previous:     true,
+			filesToCheck: []string{"test_policy_video_previous.json", "imaging.tf", "import.sh", "variables.tf"},
+		},
+		"previous version missing in policy history": {
+			init: func(i *imaging.Mock) {
+				policy := *videoPoliciesOutputs[1].(*imaging.PolicyOutputVideo)
+				policy.PreviousVersion = 1
+				expectGetPolicySet(i, "test_policyset_id", "ctr_123", "some policy set", "VIDEO", "EMEA", nil).Once()
+				expectListPolicies(i, "test_policyset_id", "ctr_123", "POLICY", imaging.PolicyNetworkStaging,
+					imaging.PolicyOutputs{videoPoliciesOutputs[0], &policy}, 2, nil).Once()
+				expectGetPolicy(i, policiesRequests[0], videoPoliciesOutputs[0], nil)
+				expectGetPolicy(i, policiesRequests[2], &policy, nil)
+				expectGetPolicyHistory(i, "test_policy_video", []imaging.PolicyHistoryItem{}, nil)
+			},
+			dataDir:      "json/video_policies_schema",
+			schema:       true,
+			previous:     true,
+			filesToCheck: []string{"imaging.tf", "import.sh", "variables.tf"},
+		},
+		"error fetching policy history": {
+			init: func(i *imaging.Mock) {
+				policy := *imagePoliciesOutputs[0].(*imaging.PolicyOutputImage)
+				policy.PreviousVersion = 1
+				expectGetPolicySet(i, "test_policyset_id", "ctr_123", "some policy set", "IMAGE", "EMEA", nil).Once()
+				expectListPolicies(i, "test_policyset_id", "ctr_123", "POLICY", imaging.PolicyNetworkStaging,
+					imaging.PolicyOutputs{&policy}, 1, nil).Once()
+				expectGetPolicy(i, policiesRequests[0], &policy, nil)
+				expectGetPolicyHistory(i, ".auto", nil, fmt.Errorf("oops"))
+			},
+			previous:  true,
+			withError: ErrFetchingPolicy,
+		},
 		"error fetching policy set": {
 			init: func(i *imaging.Mock) {
 				expectGetPolicySet(i, "test_policyset_id", "ctr_123", "some policy set", "VIDEO", "EMEA", fmt.Errorf("oops")).Once()
@@ -879,7 +960,7 @@ func TestCreateImaging(t *testing.T) {
 			mp := processor(test.dataDir)
 			test.init(mi)
 			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
-			err := createImaging(ctx, "ctr_123", "test_policyset_id", tfWorkPath, test.jsonDir, section, mi, mp, test.schema)
+			err := createImaging(ctx, identity.Contract{ID: "ctr_123"}, "test_policyset_id", tfWorkPath, test.jsonDir, section, mi, mp, test.schema, test.previous)
 			if test.withError != nil {
 				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
 				return
@@ -920,6 +1001,21 @@ func TestProcessPolicyTemplates(t *testing.T) {
 			dir:          "only_policy_set",
 			filesToCheck: []string{"imaging.tf", "variables.tf", "import.sh"},
 		},
+		"policy set with contract name": {
+			givenData: TFImagingData{
+				PolicySet: TFPolicySet{
+					ID:           "test_policyset_id",
+					ContractID:   "ctr_123",
+					ContractName: "DIRECT_CUSTOMER",
+					Name:         "some policy set",
+					Region:       "EMEA",
+					Type:         "IMAGE",
+				},
+				Section: "test_section",
+			},
+			dir:          "policy_set_with_contract_name",
+			filesToCheck: []string{"imaging.tf"},
+		},
 		"policy set with image policies": {
 			givenData: TFImagingData{
 				PolicySet: TFPolicySet{
@@ -0,0 +1,15 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type    = string
+  default = "test_section"
+}
+
+variable "rollback_policies" {
+  type        = set(string)
+  default     = []
+  description = "IDs of policies rolled back to their previous version, exported next to the current one with _previous.json suffix"
+}
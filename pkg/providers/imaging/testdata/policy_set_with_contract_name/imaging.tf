@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_imaging_policy_set" "policyset" {
+  name        = "some policy set"
+  region      = "EMEA"
+  type        = "IMAGE"
+  contract_id = "ctr_123" # DIRECT_CUSTOMER
+}
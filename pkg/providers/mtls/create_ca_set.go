@@ -0,0 +1,179 @@
+// Package mtls contains code for exporting mTLS Edge Truststore CA sets
+package mtls
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// TFData represents the data used in CA set templates
+	TFData struct {
+		CASetID            string
+		CASetName          string
+		ResourceName       string
+		Description        string
+		Version            int
+		VersionDescription string
+		AllowInsecureSHA1  bool
+		Certificates       []Certificate
+		Activations        []TFActivation
+		Section            string
+	}
+
+	// TFActivation represents an activation of a CA set version on a network
+	TFActivation struct {
+		Network string
+		Version int
+		// Latest means that the activated version is the exported one, so the activation refers to the CA set resource
+		Latest bool
+	}
+)
+
+// Networks on which CA set versions are activated
+const (
+	NetworkStaging    = "STAGING"
+	NetworkProduction = "PRODUCTION"
+)
+
+var (
+	//go:embed templates/*
+	templateFiles embed.FS
+
+	// ErrFetchingCASet is returned when fetching CA set fails
+	ErrFetchingCASet = exitcode.New(exitcode.API, "unable to fetch CA set")
+	// ErrFetchingCASetVersion is returned when fetching version of CA set fails
+	ErrFetchingCASetVersion = exitcode.New(exitcode.API, "unable to fetch CA set version")
+	// ErrNoVersion is returned when CA set has no version which could be exported
+	ErrNoVersion = exitcode.New(exitcode.NotFound, "CA set has no version")
+)
+
+// CmdCreateCASet is an entrypoint to export-mtls-truststore command
+func CmdCreateCASet(c *cli.Context) error {
+	ctx := c.Context
+	sess := edgegrid.GetSession(c.Context)
+	client := Client(sess)
+
+	// tfWorkPath is a target directory for generated terraform resources
+	var tfWorkPath = "./"
+	if c.IsSet("tfworkpath") {
+		tfWorkPath = c.String("tfworkpath")
+	}
+
+	caSetPath := filepath.Join(tfWorkPath, "mtls-truststore.tf")
+	variablesPath := filepath.Join(tfWorkPath, "variables.tf")
+	importPath := filepath.Join(tfWorkPath, "import.sh")
+
+	err := tools.CheckFiles(caSetPath, variablesPath, importPath)
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	templateToFile := map[string]string{
+		"mtls-truststore.tmpl":           caSetPath,
+		"mtls-truststore-variables.tmpl": variablesPath,
+		"mtls-truststore-imports.tmpl":   importPath,
+	}
+
+	processor := templates.FSTemplateProcessor{
+		TemplatesFS:     templateFiles,
+		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
+	}
+
+	caSetID := c.Args().First()
+	section := edgegrid.GetEdgercSection(c)
+	if err = createCASet(ctx, caSetID, section, client, processor); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting mTLS truststore HCL: %s", err)), exitcode.Of(err))
+	}
+	return nil
+}
+
+func createCASet(ctx context.Context, caSetID, section string, client Truststore, templateProcessor templates.TemplateProcessor) error {
+	terminal.Get(ctx).Printf("Exporting mTLS Edge Truststore configuration\n")
+	progress.Get(ctx).Start("Fetching CA set " + caSetID)
+
+	caSet, err := client.GetCASet(ctx, GetCASetRequest{CASetID: caSetID})
+	if err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrFetchingCASet, err)
+	}
+	if caSet.LatestVersion == nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: '%s'", ErrNoVersion, caSet.CASetName)
+	}
+
+	version, err := client.GetCASetVersion(ctx, GetCASetVersionRequest{CASetID: caSetID, Version: *caSet.LatestVersion})
+	if err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrFetchingCASetVersion, err)
+	}
+	progress.Get(ctx).OK()
+
+	resourceName, err := tools.EscapeName(caSet.CASetName)
+	if err != nil {
+		return err
+	}
+	tfData := TFData{
+		CASetID:            caSet.CASetID,
+		CASetName:          caSet.CASetName,
+		ResourceName:       resourceName,
+		Description:        caSet.Description,
+		Version:            version.Version,
+		VersionDescription: version.Description,
+		AllowInsecureSHA1:  version.AllowInsecureSHA1,
+		Certificates:       version.Certificates,
+		Activations:        activations(caSet, version.Version),
+		Section:            section,
+	}
+	workspace.RecordObject(ctx, workspace.Object{
+		Product: "mtls-truststore",
+		ID:      caSet.CASetID,
+		Name:    caSet.CASetName,
+		Version: fmt.Sprint(version.Version),
+	})
+
+	progress.Get(ctx).Start("Saving TF configurations ")
+	if err := templateProcessor.ProcessTemplates(tfData); err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", templates.ErrSavingFiles, err)
+	}
+	progress.Get(ctx).OK()
+	terminal.Get(ctx).Printf("Terraform configuration for CA set '%s' was saved successfully\n", caSet.CASetName)
+
+	return nil
+}
+
+// activations returns activations of the CA set on staging and production, in this order, if any version is active there
+func activations(caSet *CASet, exported int) []TFActivation {
+	var result []TFActivation
+	for _, active := range []struct {
+		network string
+		version *int
+	}{
+		{NetworkStaging, caSet.StagingVersion},
+		{NetworkProduction, caSet.ProductionVersion},
+	} {
+		if active.version == nil {
+			continue
+		}
+		result = append(result, TFActivation{
+			Network: active.network,
+			Version: *active.version,
+			Latest:  *active.version == exported,
+		})
+	}
+	return result
+}
@@ -0,0 +1,200 @@
+package mtls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProcessor struct {
+	mock.Mock
+}
+
+func (m *mockProcessor) ProcessTemplates(i interface{}) error {
+	args := m.Called(i)
+	return args.Error(0)
+}
+
+var (
+	intPtr = func(i int) *int {
+		return &i
+	}
+
+	certificates = []Certificate{
+		{CertificatePEM: "-----BEGIN CERTIFICATE-----\nMIIBroot\n-----END CERTIFICATE-----\n", Description: "root CA"},
+		{CertificatePEM: "-----BEGIN CERTIFICATE-----\nMIIBintermediate\n-----END CERTIFICATE-----\n"},
+	}
+
+	expectGetCASet = func(m *Mock, caSet *CASet, err error) *mock.Call {
+		call := m.On("GetCASet", mock.Anything, GetCASetRequest{CASetID: "12345"})
+		if err != nil {
+			return call.Return(nil, err)
+		}
+		return call.Return(caSet, nil)
+	}
+
+	expectGetCASetVersion = func(m *Mock, version int, err error) *mock.Call {
+		call := m.On("GetCASetVersion", mock.Anything, GetCASetVersionRequest{CASetID: "12345", Version: version})
+		if err != nil {
+			return call.Return(nil, err)
+		}
+		return call.Return(&CASetVersion{
+			Version:      version,
+			Description:  "rotated intermediate",
+			Certificates: certificates,
+		}, nil)
+	}
+)
+
+func TestCreateCASet(t *testing.T) {
+	section := "test_section"
+	caSet := &CASet{
+		CASetID:           "12345",
+		CASetName:         "client-ca",
+		Description:       "client certificates",
+		LatestVersion:     intPtr(3),
+		StagingVersion:    intPtr(3),
+		ProductionVersion: intPtr(2),
+	}
+
+	tests := map[string]struct {
+		init      func(*Mock, *mockProcessor)
+		withError error
+	}{
+		"CA set with activations": {
+			init: func(m *Mock, p *mockProcessor) {
+				expectGetCASet(m, caSet, nil).Once()
+				expectGetCASetVersion(m, 3, nil).Once()
+				p.On("ProcessTemplates", TFData{
+					CASetID:            "12345",
+					CASetName:          "client-ca",
+					ResourceName:       "clientca",
+					Description:        "client certificates",
+					Version:            3,
+					VersionDescription: "rotated intermediate",
+					Certificates:       certificates,
+					Activations: []TFActivation{
+						{Network: NetworkStaging, Version: 3, Latest: true},
+						{Network: NetworkProduction, Version: 2},
+					},
+					Section: section,
+				}).Return(nil).Once()
+			},
+		},
+		"error fetching CA set": {
+			init: func(m *Mock, p *mockProcessor) {
+				expectGetCASet(m, nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingCASet,
+		},
+		"CA set without version": {
+			init: func(m *Mock, p *mockProcessor) {
+				expectGetCASet(m, &CASet{CASetID: "12345", CASetName: "client-ca"}, nil).Once()
+			},
+			withError: ErrNoVersion,
+		},
+		"error fetching CA set version": {
+			init: func(m *Mock, p *mockProcessor) {
+				expectGetCASet(m, caSet, nil).Once()
+				expectGetCASetVersion(m, 3, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingCASetVersion,
+		},
+		"error processing template": {
+			init: func(m *Mock, p *mockProcessor) {
+				expectGetCASet(m, caSet, nil).Once()
+				expectGetCASetVersion(m, 3, nil).Once()
+				p.On("ProcessTemplates", mock.Anything).Return(fmt.Errorf("oops")).Once()
+			},
+			withError: templates.ErrSavingFiles,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := new(Mock)
+			p := new(mockProcessor)
+			test.init(m, p)
+			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			err := createCASet(ctx, "12345", section, m, p)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			m.AssertExpectations(t)
+			p.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessCASetTemplates(t *testing.T) {
+	tests := map[string]struct {
+		givenData    TFData
+		dir          string
+		filesToCheck []string
+	}{
+		"CA set with activations": {
+			givenData: TFData{
+				CASetID:            "12345",
+				CASetName:          "client-ca",
+				ResourceName:       "clientca",
+				Description:        "client certificates",
+				Version:            3,
+				VersionDescription: "rotated intermediate",
+				Certificates:       certificates,
+				Activations: []TFActivation{
+					{Network: NetworkStaging, Version: 3, Latest: true},
+					{Network: NetworkProduction, Version: 2},
+				},
+				Section: "test_section",
+			},
+			dir:          "ca_set_with_activations",
+			filesToCheck: []string{"mtls-truststore.tf", "variables.tf", "import.sh"},
+		},
+		"inactive CA set allowing SHA-1": {
+			givenData: TFData{
+				CASetID:           "12345",
+				CASetName:         "legacy \"clients\"",
+				ResourceName:      "legacy_clients",
+				Version:           1,
+				AllowInsecureSHA1: true,
+				Certificates:      certificates[:1],
+				Section:           "test_section",
+			},
+			dir:          "ca_set_inactive",
+			filesToCheck: []string{"mtls-truststore.tf", "variables.tf", "import.sh"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.MkdirAll(fmt.Sprintf("./testdata/res/%s", test.dir), 0755))
+			processor := templates.FSTemplateProcessor{
+				TemplatesFS: templateFiles,
+				TemplateTargets: map[string]string{
+					"mtls-truststore.tmpl":           fmt.Sprintf("./testdata/res/%s/mtls-truststore.tf", test.dir),
+					"mtls-truststore-variables.tmpl": fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+					"mtls-truststore-imports.tmpl":   fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
+				},
+			}
+			require.NoError(t, processor.ProcessTemplates(test.givenData))
+
+			for _, f := range test.filesToCheck {
+				expected, err := os.ReadFile(fmt.Sprintf("./testdata/%s/%s", test.dir, f))
+				require.NoError(t, err)
+				result, err := os.ReadFile(fmt.Sprintf("./testdata/res/%s/%s", test.dir, f))
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(result))
+			}
+		})
+	}
+}
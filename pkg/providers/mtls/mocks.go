@@ -0,0 +1,39 @@
+//revive:disable:exported
+
+package mtls
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type Mock struct {
+	mock.Mock
+}
+
+var _ Truststore = &Mock{}
+
+func (m *Mock) ListCASets(ctx context.Context, req ListCASetsRequest) (*ListCASetsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ListCASetsResponse), args.Error(1)
+}
+
+func (m *Mock) GetCASet(ctx context.Context, req GetCASetRequest) (*CASet, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CASet), args.Error(1)
+}
+
+func (m *Mock) GetCASetVersion(ctx context.Context, req GetCASetVersionRequest) (*CASetVersion, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CASetVersion), args.Error(1)
+}
@@ -0,0 +1,2 @@
+terraform init
+terraform import akamai_mtlstruststore_ca_set.legacy_clients 12345
@@ -0,0 +1,29 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 8.1.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_mtlstruststore_ca_set" "legacy_clients" {
+  name                = "legacy \"clients\""
+  allow_insecure_sha1 = true
+  certificates = [
+    {
+      certificate_pem = <<EOT
+-----BEGIN CERTIFICATE-----
+MIIBroot
+-----END CERTIFICATE-----
+EOT
+      description     = "root CA"
+    },
+  ]
+}
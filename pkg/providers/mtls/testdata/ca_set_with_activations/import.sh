@@ -0,0 +1,4 @@
+terraform init
+terraform import akamai_mtlstruststore_ca_set.clientca 12345
+terraform import akamai_mtlstruststore_ca_set_activation.clientca_staging 12345:STAGING
+terraform import akamai_mtlstruststore_ca_set_activation.clientca_production 12345:PRODUCTION
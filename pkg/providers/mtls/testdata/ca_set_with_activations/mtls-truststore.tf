@@ -0,0 +1,50 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 8.1.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_mtlstruststore_ca_set" "clientca" {
+  name                = "client-ca"
+  description         = "client certificates"
+  version_description = "rotated intermediate"
+  allow_insecure_sha1 = false
+  certificates = [
+    {
+      certificate_pem = <<EOT
+-----BEGIN CERTIFICATE-----
+MIIBroot
+-----END CERTIFICATE-----
+EOT
+      description     = "root CA"
+    },
+    {
+      certificate_pem = <<EOT
+-----BEGIN CERTIFICATE-----
+MIIBintermediate
+-----END CERTIFICATE-----
+EOT
+    },
+  ]
+}
+
+resource "akamai_mtlstruststore_ca_set_activation" "clientca_staging" {
+  ca_set_id = akamai_mtlstruststore_ca_set.clientca.id
+  version   = akamai_mtlstruststore_ca_set.clientca.latest_version
+  network   = "STAGING"
+}
+
+resource "akamai_mtlstruststore_ca_set_activation" "clientca_production" {
+  ca_set_id = akamai_mtlstruststore_ca_set.clientca.id
+  version   = 2
+  network   = "PRODUCTION"
+}
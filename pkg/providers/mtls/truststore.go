@@ -0,0 +1,179 @@
+package mtls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+)
+
+type (
+	// Truststore is an mTLS Edge Truststore API interface, the API is not covered by edgegrid-golang v3, so only calls
+	// needed by the export are implemented here
+	Truststore interface {
+		// ListCASets lists CA sets available to the API client
+		//
+		// See: https://techdocs.akamai.com/mtls-edge-truststore/reference/get-ca-sets
+		ListCASets(context.Context, ListCASetsRequest) (*ListCASetsResponse, error)
+		// GetCASet fetches a CA set by its ID
+		//
+		// See: https://techdocs.akamai.com/mtls-edge-truststore/reference/get-ca-set
+		GetCASet(context.Context, GetCASetRequest) (*CASet, error)
+		// GetCASetVersion fetches a version of a CA set together with its certificates
+		//
+		// See: https://techdocs.akamai.com/mtls-edge-truststore/reference/get-ca-set-version
+		GetCASetVersion(context.Context, GetCASetVersionRequest) (*CASetVersion, error)
+	}
+
+	// ListCASetsRequest contains query parameters used to list CA sets
+	ListCASetsRequest struct {
+		CASetNamePrefix string
+	}
+
+	// GetCASetRequest contains path parameters used to fetch a CA set
+	GetCASetRequest struct {
+		CASetID string
+	}
+
+	// GetCASetVersionRequest contains path parameters used to fetch a CA set version
+	GetCASetVersionRequest struct {
+		CASetID string
+		Version int
+	}
+
+	// ListCASetsResponse represents a response object returned when listing CA sets
+	ListCASetsResponse struct {
+		CASets []CASet `json:"caSets"`
+	}
+
+	// CASet represents a CA set, versions are nil if the CA set has no version or no version is active on the network
+	CASet struct {
+		CASetID           string `json:"caSetId"`
+		CASetName         string `json:"caSetName"`
+		Description       string `json:"description"`
+		LatestVersion     *int   `json:"latestVersion"`
+		StagingVersion    *int   `json:"stagingVersion"`
+		ProductionVersion *int   `json:"productionVersion"`
+	}
+
+	// CASetVersion represents a version of a CA set
+	CASetVersion struct {
+		Version           int           `json:"version"`
+		Description       string        `json:"description"`
+		AllowInsecureSHA1 bool          `json:"allowInsecureSha1"`
+		Certificates      []Certificate `json:"certificates"`
+	}
+
+	// Certificate represents a CA certificate of a CA set version
+	Certificate struct {
+		CertificatePEM string `json:"certificatePem"`
+		Description    string `json:"description"`
+	}
+
+	// Error is an mTLS Edge Truststore API error
+	Error struct {
+		Type       string `json:"type,omitempty"`
+		Title      string `json:"title,omitempty"`
+		Detail     string `json:"detail,omitempty"`
+		Instance   string `json:"instance,omitempty"`
+		StatusCode int    `json:"status,omitempty"`
+	}
+
+	truststore struct {
+		session.Session
+	}
+)
+
+var (
+	// ErrListCASets is returned when ListCASets fails
+	ErrListCASets = errors.New("list CA sets")
+	// ErrGetCASet is returned when GetCASet fails
+	ErrGetCASet = errors.New("get CA set")
+	// ErrGetCASetVersion is returned when GetCASetVersion fails
+	ErrGetCASetVersion = errors.New("get CA set version")
+)
+
+// Client returns new mTLS Edge Truststore API client
+func Client(sess session.Session) Truststore {
+	return &truststore{Session: sess}
+}
+
+func (t *truststore) ListCASets(ctx context.Context, params ListCASetsRequest) (*ListCASetsResponse, error) {
+	uri := "/mtls-edge-truststore/v2/ca-sets"
+	if params.CASetNamePrefix != "" {
+		uri += "?" + url.Values{"caSetNamePrefix": []string{params.CASetNamePrefix}}.Encode()
+	}
+	var result ListCASetsResponse
+	if err := t.get(ctx, uri, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrListCASets, err)
+	}
+	return &result, nil
+}
+
+func (t *truststore) GetCASet(ctx context.Context, params GetCASetRequest) (*CASet, error) {
+	if params.CASetID == "" {
+		return nil, fmt.Errorf("%w: CA set ID is required", ErrGetCASet)
+	}
+	var result CASet
+	if err := t.get(ctx, "/mtls-edge-truststore/v2/ca-sets/"+url.PathEscape(params.CASetID), &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrGetCASet, err)
+	}
+	return &result, nil
+}
+
+func (t *truststore) GetCASetVersion(ctx context.Context, params GetCASetVersionRequest) (*CASetVersion, error) {
+	if params.CASetID == "" || params.Version <= 0 {
+		return nil, fmt.Errorf("%w: CA set ID and positive version are required", ErrGetCASetVersion)
+	}
+	uri := fmt.Sprintf("/mtls-edge-truststore/v2/ca-sets/%s/versions/%s", url.PathEscape(params.CASetID), strconv.Itoa(params.Version))
+	var result CASetVersion
+	if err := t.get(ctx, uri, &result); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrGetCASetVersion, err)
+	}
+	return &result, nil
+}
+
+func (t *truststore) get(ctx context.Context, uri string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %s", err)
+	}
+	resp, err := t.Exec(req, out)
+	if err != nil {
+		return fmt.Errorf("request failed: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return parseError(resp)
+	}
+	return nil
+}
+
+// parseError parses an error from the response
+func parseError(r *http.Response) error {
+	result := Error{StatusCode: r.StatusCode}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		result.Title = "Failed to read error body"
+		result.Detail = err.Error()
+		return &result
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		result.Title = string(body)
+	}
+	result.StatusCode = r.StatusCode
+	return &result
+}
+
+func (e *Error) Error() string {
+	msg, err := json.MarshalIndent(e, "", "\t")
+	if err != nil {
+		return fmt.Sprintf("error marshaling API error: %s", err)
+	}
+	return fmt.Sprintf("API error: \n%s", msg)
+}
@@ -0,0 +1,70 @@
+package mtls
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/session"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruststoreClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		switch r.URL.RequestURI() {
+		case "/mtls-edge-truststore/v2/ca-sets?caSetNamePrefix=client":
+			_, _ = w.Write([]byte(`{"caSets":[{"caSetId":"12345","caSetName":"client-ca","latestVersion":3}]}`))
+		case "/mtls-edge-truststore/v2/ca-sets/12345":
+			_, _ = w.Write([]byte(`{"caSetId":"12345","caSetName":"client-ca","description":"client certificates","latestVersion":3,"stagingVersion":3,"productionVersion":null}`))
+		case "/mtls-edge-truststore/v2/ca-sets/12345/versions/3":
+			_, _ = w.Write([]byte(`{"version":3,"description":"rotated","allowInsecureSha1":false,"certificates":[{"certificatePem":"-----BEGIN CERTIFICATE-----\n","description":"root"}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"type":"forbidden","title":"Forbidden","status":403}`))
+		}
+	}))
+	defer srv.Close()
+
+	transport, err := edgegrid.RedirectTransport(srv.URL, http.DefaultTransport)
+	require.NoError(t, err)
+	sess, err := session.New(session.WithClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+	client := Client(sess)
+	ctx := context.Background()
+
+	list, err := client.ListCASets(ctx, ListCASetsRequest{CASetNamePrefix: "client"})
+	require.NoError(t, err)
+	assert.Equal(t, &ListCASetsResponse{CASets: []CASet{{CASetID: "12345", CASetName: "client-ca", LatestVersion: intPtr(3)}}}, list)
+
+	caSet, err := client.GetCASet(ctx, GetCASetRequest{CASetID: "12345"})
+	require.NoError(t, err)
+	assert.Equal(t, &CASet{
+		CASetID:        "12345",
+		CASetName:      "client-ca",
+		Description:    "client certificates",
+		LatestVersion:  intPtr(3),
+		StagingVersion: intPtr(3),
+	}, caSet)
+
+	version, err := client.GetCASetVersion(ctx, GetCASetVersionRequest{CASetID: "12345", Version: 3})
+	require.NoError(t, err)
+	assert.Equal(t, &CASetVersion{
+		Version:      3,
+		Description:  "rotated",
+		Certificates: []Certificate{{CertificatePEM: "-----BEGIN CERTIFICATE-----\n", Description: "root"}},
+	}, version)
+
+	_, err = client.GetCASet(ctx, GetCASetRequest{CASetID: "999"})
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr), "expected API error, got: %s", err)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, "Forbidden", apiErr.Title)
+
+	_, err = client.GetCASetVersion(ctx, GetCASetVersionRequest{CASetID: "12345"})
+	assert.True(t, errors.Is(err, ErrGetCASetVersion), "expected: %s; got: %s", ErrGetCASetVersion, err)
+}
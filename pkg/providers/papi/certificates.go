@@ -0,0 +1,184 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
+	"github.com/akamai/cli-terraform/pkg/apierrors"
+	cpsprovider "github.com/akamai/cli-terraform/pkg/providers/cps"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+)
+
+type (
+	// Certificate describes CPS enrollment of the certificate used by a hostname
+	Certificate struct {
+		EnrollmentID int
+		CommonName   string
+		Status       string
+		Expiry       string
+	}
+
+	// certificateOptions enables cross-check of CPS managed hostnames with enrollments of their certificates
+	certificateOptions struct {
+		client cps.CPS
+		// export means that enrollments are exported into sibling directories of the property configuration
+		export bool
+		// readOnly means that exported enrollments are written as data sources
+		readOnly bool
+	}
+)
+
+// Statuses of certificates
+const (
+	CertificateDeployed       = "deployed"
+	CertificatePendingChanges = "pending changes"
+	CertificateNotDeployed    = "not deployed"
+	CertificateUnknown        = "unknown"
+)
+
+// cpsManaged is the certificate provisioning type of hostnames using certificates managed in CPS
+const cpsManaged = "CPS_MANAGED"
+
+// annotateCertificates finds CPS enrollments covering CPS managed hostnames of the property and sets their certificates.
+// Hostnames which are not covered by any enrollment and enrollments which status cannot be fetched are reported as warnings.
+func annotateCertificates(ctx context.Context, client cps.CPS, propertyName, contractID string, hostnames map[string]Hostname) error {
+	var managed []string
+	for key, hostname := range hostnames {
+		if hostname.CertProvisioningType == cpsManaged {
+			managed = append(managed, key)
+		}
+	}
+	if len(managed) == 0 {
+		return nil
+	}
+	sort.Strings(managed)
+
+	enrollments, err := client.ListEnrollments(ctx, cps.ListEnrollmentsRequest{ContractID: strings.TrimPrefix(contractID, "ctr_")})
+	if apierrors.NotEntitled(err) {
+		warnings.Report(ctx, warnings.Warning{
+			Product: "property",
+			Object:  propertyName,
+			Reason:  "certificates of CPS managed hostnames skipped, the API client or the contract is not entitled to CPS (HTTP 403)",
+		})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	certificates := make(map[int]*Certificate)
+	for _, key := range managed {
+		hostname := hostnames[key]
+		enrollment, id := findEnrollment(enrollments.Enrollments, hostname.Hostname)
+		if enrollment == nil {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "property",
+				Object:  propertyName,
+				Reason:  fmt.Sprintf("hostname '%s' uses CPS managed certificate, but no enrollment covering it was found on the contract", hostname.Hostname),
+			})
+			continue
+		}
+		certificate, ok := certificates[id]
+		if !ok {
+			certificate = &Certificate{EnrollmentID: id, CommonName: enrollment.CSR.CN}
+			certificate.Status, certificate.Expiry, err = certificateStatus(ctx, client, id, enrollment)
+			if err != nil {
+				certificate.Status = CertificateUnknown
+				warnings.Report(ctx, warnings.Warning{
+					Product: "property",
+					Object:  propertyName,
+					Reason:  fmt.Sprintf("status of certificate of CPS enrollment %d could not be fetched: %s", id, err),
+				})
+			}
+			certificates[id] = certificate
+			workspace.RecordObject(ctx, workspace.Object{Product: "cps", ID: strconv.Itoa(id), Name: certificate.CommonName, Referenced: true})
+		}
+		hostname.Certificate = certificate
+		hostnames[key] = hostname
+	}
+	return nil
+}
+
+// findEnrollment returns enrollment which common name or SANs cover the hostname, together with its id
+func findEnrollment(enrollments []cps.Enrollment, hostname string) (*cps.Enrollment, int) {
+	for i, enrollment := range enrollments {
+		if enrollment.CSR == nil {
+			continue
+		}
+		names := append([]string{enrollment.CSR.CN}, enrollment.CSR.SANS...)
+		for _, name := range names {
+			if !coversHostname(name, hostname) {
+				continue
+			}
+			id, err := strconv.Atoi(path.Base(enrollment.Location))
+			if err != nil {
+				break
+			}
+			return &enrollments[i], id
+		}
+	}
+	return nil, 0
+}
+
+// coversHostname returns true if the certificate name, which may be a wildcard, matches the hostname
+func coversHostname(name, hostname string) bool {
+	name, hostname = strings.ToLower(name), strings.ToLower(hostname)
+	if !strings.HasPrefix(name, "*.") {
+		return name == hostname
+	}
+	i := strings.Index(hostname, ".")
+	return i > 0 && hostname[i:] == name[1:]
+}
+
+// certificateStatus returns status of the certificate of the enrollment and expiry of the certificate deployed on production
+func certificateStatus(ctx context.Context, client cps.CPS, id int, enrollment *cps.Enrollment) (string, string, error) {
+	deployments, err := client.ListDeployments(ctx, cps.ListDeploymentsRequest{EnrollmentID: id})
+	if err != nil {
+		return "", "", err
+	}
+	var expiry string
+	if deployments.Production != nil {
+		expiry = deployments.Production.PrimaryCertificate.Expiry
+	}
+	switch {
+	case len(enrollment.PendingChanges) > 0:
+		return CertificatePendingChanges, expiry, nil
+	case deployments.Production == nil:
+		return CertificateNotDeployed, expiry, nil
+	}
+	return CertificateDeployed, expiry, nil
+}
+
+// exportEnrollments exports CPS enrollments of certificates used by hostnames into directories named 'cps-<enrollment id>'
+// within tfWorkPath; enrollments which cannot be exported are reported as warnings
+func exportEnrollments(ctx context.Context, client cps.CPS, propertyName, contractID, section, tfWorkPath string, readOnly bool, hostnames map[string]Hostname) {
+	ids := make(map[int]bool)
+	for _, hostname := range hostnames {
+		if hostname.Certificate != nil {
+			ids[hostname.Certificate.EnrollmentID] = true
+		}
+	}
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Ints(sorted)
+
+	for _, id := range sorted {
+		dir := filepath.Join(tfWorkPath, fmt.Sprintf("cps-%d", id))
+		if err := cpsprovider.ExportEnrollment(ctx, strings.TrimPrefix(contractID, "ctr_"), id, section, dir, readOnly, client); err != nil {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "property",
+				Object:  propertyName,
+				Reason:  fmt.Sprintf("CPS enrollment %d could not be exported into %s: %s", id, dir, err),
+			})
+		}
+	}
+}
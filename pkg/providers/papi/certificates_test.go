@@ -0,0 +1,155 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotateCertificates(t *testing.T) {
+	enrollments := &cps.ListEnrollmentsResponse{Enrollments: []cps.Enrollment{
+		{Location: "/cps/v2/enrollments/100", CSR: &cps.CSR{CN: "www.example.com", SANS: []string{"api.example.com"}}},
+		{Location: "/cps/v2/enrollments/200", CSR: &cps.CSR{CN: "*.test.com"}, PendingChanges: []string{"/cps/v2/enrollments/200/changes/1"}},
+	}}
+	expectListEnrollments := func(c *cps.Mock, err error) {
+		call := c.On("ListEnrollments", mock.Anything, cps.ListEnrollmentsRequest{ContractID: "C-1"})
+		if err != nil {
+			call.Return(nil, err).Once()
+			return
+		}
+		call.Return(enrollments, nil).Once()
+	}
+
+	tests := map[string]struct {
+		hostnames        map[string]Hostname
+		init             func(*cps.Mock)
+		expected         map[string]*Certificate
+		expectedWarnings int
+		withError        bool
+	}{
+		"hostnames without CPS managed certificates are skipped": {
+			hostnames: map[string]Hostname{"www.example.com": {Hostname: "www.example.com", CertProvisioningType: "DEFAULT"}},
+			init:      func(*cps.Mock) {},
+			expected:  map[string]*Certificate{"www.example.com": nil},
+		},
+		"hostnames covered by enrollments": {
+			hostnames: map[string]Hostname{
+				"www.example.com": {Hostname: "www.example.com", CertProvisioningType: cpsManaged},
+				"api.example.com": {Hostname: "api.example.com", CertProvisioningType: cpsManaged},
+				"a.test.com":      {Hostname: "a.test.com", CertProvisioningType: cpsManaged},
+			},
+			init: func(c *cps.Mock) {
+				expectListEnrollments(c, nil)
+				c.On("ListDeployments", mock.Anything, cps.ListDeploymentsRequest{EnrollmentID: 100}).Return(&cps.ListDeploymentsResponse{
+					Production: &cps.Deployment{PrimaryCertificate: cps.DeploymentCertificate{Expiry: "2023-01-01T00:00:00Z"}},
+				}, nil).Once()
+				c.On("ListDeployments", mock.Anything, cps.ListDeploymentsRequest{EnrollmentID: 200}).Return(&cps.ListDeploymentsResponse{}, nil).Once()
+			},
+			expected: map[string]*Certificate{
+				"www.example.com": {EnrollmentID: 100, CommonName: "www.example.com", Status: CertificateDeployed, Expiry: "2023-01-01T00:00:00Z"},
+				"api.example.com": {EnrollmentID: 100, CommonName: "www.example.com", Status: CertificateDeployed, Expiry: "2023-01-01T00:00:00Z"},
+				"a.test.com":      {EnrollmentID: 200, CommonName: "*.test.com", Status: CertificatePendingChanges},
+			},
+		},
+		"hostname not covered and status not fetched": {
+			hostnames: map[string]Hostname{
+				"other.com":  {Hostname: "other.com", CertProvisioningType: cpsManaged},
+				"b.test.com": {Hostname: "b.test.com", CertProvisioningType: cpsManaged},
+			},
+			init: func(c *cps.Mock) {
+				expectListEnrollments(c, nil)
+				c.On("ListDeployments", mock.Anything, cps.ListDeploymentsRequest{EnrollmentID: 200}).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			expected: map[string]*Certificate{
+				"other.com":  nil,
+				"b.test.com": {EnrollmentID: 200, CommonName: "*.test.com", Status: CertificateUnknown},
+			},
+			expectedWarnings: 2,
+		},
+		"enrollments not listed": {
+			hostnames: map[string]Hostname{"www.example.com": {Hostname: "www.example.com", CertProvisioningType: cpsManaged}},
+			init: func(c *cps.Mock) {
+				expectListEnrollments(c, fmt.Errorf("oops"))
+			},
+			withError: true,
+		},
+		"not entitled to CPS": {
+			hostnames: map[string]Hostname{"www.example.com": {Hostname: "www.example.com", CertProvisioningType: cpsManaged}},
+			init: func(c *cps.Mock) {
+				expectListEnrollments(c, fmt.Errorf("list enrollments: %w", &cps.Error{StatusCode: 403}))
+			},
+			expected:         map[string]*Certificate{"www.example.com": nil},
+			expectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &cps.Mock{}
+			test.init(c)
+			collector := warnings.NewCollector()
+			ctx := warnings.WithCollector(context.Background(), collector)
+
+			err := annotateCertificates(ctx, c, "test.edgesuite.net", "ctr_C-1", test.hostnames)
+			c.AssertExpectations(t)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for key, certificate := range test.expected {
+				assert.Equal(t, certificate, test.hostnames[key].Certificate, key)
+			}
+			assert.Len(t, collector.Warnings(), test.expectedWarnings)
+		})
+	}
+}
+
+func TestCoversHostname(t *testing.T) {
+	assert.True(t, coversHostname("www.example.com", "WWW.example.com"))
+	assert.True(t, coversHostname("*.example.com", "www.example.com"))
+	assert.False(t, coversHostname("*.example.com", "example.com"))
+	assert.False(t, coversHostname("*.example.com", "a.www.example.com"))
+	assert.False(t, coversHostname("www.example.com", "api.example.com"))
+}
+
+func TestExportEnrollments(t *testing.T) {
+	c := &cps.Mock{}
+	c.On("GetEnrollment", mock.Anything, cps.GetEnrollmentRequest{EnrollmentID: 100}).
+		Return(&cps.Enrollment{ValidationType: "dv", CSR: &cps.CSR{CN: "www.example.com"}}, nil).Once()
+	c.On("GetEnrollment", mock.Anything, cps.GetEnrollmentRequest{EnrollmentID: 200}).
+		Return(nil, fmt.Errorf("oops")).Once()
+	sink := templates.NewMemorySink()
+	collector := warnings.NewCollector()
+	ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+	ctx = warnings.WithCollector(templates.WithSink(ctx, sink), collector)
+	hostnames := map[string]Hostname{
+		"www.example.com": {Hostname: "www.example.com", Certificate: &Certificate{EnrollmentID: 100}},
+		"api.example.com": {Hostname: "api.example.com", Certificate: &Certificate{EnrollmentID: 100}},
+		"a.test.com":      {Hostname: "a.test.com", Certificate: &Certificate{EnrollmentID: 200}},
+		"other.com":       {Hostname: "other.com"},
+	}
+
+	tfWorkPath := filepath.Join("export", "property")
+	exportEnrollments(ctx, c, "test.edgesuite.net", "ctr_C-1", "default", tfWorkPath, true, hostnames)
+	c.AssertExpectations(t)
+
+	files := make([]string, 0, len(sink.Files()))
+	for path := range sink.Files() {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	assert.Equal(t, []string{filepath.Join(tfWorkPath, "cps-100", "enrollment.tf"), filepath.Join(tfWorkPath, "cps-100", "variables.tf")}, files)
+	require.Len(t, collector.Warnings(), 1)
+	assert.Contains(t, collector.Warnings()[0].Reason, filepath.Join(tfWorkPath, "cps-200"))
+}
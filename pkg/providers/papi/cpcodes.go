@@ -0,0 +1,73 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+)
+
+// CPCode is a CP code used by property rules, referenced with akamai_cp_code data source when existing CP codes and
+// edge hostnames are referenced instead of managed
+type CPCode struct {
+	ID   int
+	Name string
+	// ResourceName is the name of the data source and of the rules template variable replacing the CP code ID in rules
+	ResourceName string
+}
+
+// referenceCPCodes replaces IDs of CP codes in cpCode behaviors of the rule and its children with rules template
+// variables and returns the CP codes, sorted by ID. CP codes without name cannot be looked up by the data source, their
+// IDs are kept in rules as they are.
+func referenceCPCodes(ctx context.Context, propertyName string, rule *papi.Rules) []CPCode {
+	found := make(map[int]CPCode)
+	replaceCPCodes(ctx, propertyName, rule, "", found)
+
+	cpCodes := make([]CPCode, 0, len(found))
+	for _, cpCode := range found {
+		cpCodes = append(cpCodes, cpCode)
+		workspace.RecordObject(ctx, workspace.Object{Product: "cpcode", ID: fmt.Sprintf("cpc_%d", cpCode.ID), Name: cpCode.Name, Referenced: true})
+	}
+	sort.Slice(cpCodes, func(i, j int) bool {
+		return cpCodes[i].ID < cpCodes[j].ID
+	})
+	return cpCodes
+}
+
+func replaceCPCodes(ctx context.Context, propertyName string, rule *papi.Rules, parentPath string, found map[int]CPCode) {
+	path := rule.Name
+	if parentPath != "" {
+		path = parentPath + "/" + rule.Name
+	}
+	for _, behavior := range rule.Behaviors {
+		if behavior.Name != "cpCode" {
+			continue
+		}
+		value, ok := behavior.Options["value"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := value["id"].(float64)
+		if !ok {
+			continue
+		}
+		name, _ := value["name"].(string)
+		if name == "" {
+			warnings.Report(ctx, warnings.Warning{
+				Product: "property",
+				Object:  propertyName,
+				Reason:  fmt.Sprintf("CP code %d in rule '%s' has no name, its ID is kept in the rules", int(id), path),
+			})
+			continue
+		}
+		cpCode := CPCode{ID: int(id), Name: name, ResourceName: fmt.Sprintf("cp_code_%d", int(id))}
+		found[cpCode.ID] = cpCode
+		value["id"] = fmt.Sprintf("${env.%s}", cpCode.ResourceName)
+	}
+	for i := range rule.Children {
+		replaceCPCodes(ctx, propertyName, &rule.Children[i], path, found)
+	}
+}
@@ -0,0 +1,103 @@
+package papi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceCPCodes(t *testing.T) {
+	cpCode := func(value map[string]interface{}) papi.RuleBehavior {
+		return papi.RuleBehavior{Name: "cpCode", Options: papi.RuleOptionsMap{"value": value}}
+	}
+
+	tests := map[string]struct {
+		givenRule        papi.Rules
+		expectedRule     papi.Rules
+		expected         []CPCode
+		expectedObjects  []workspace.Object
+		expectedWarnings []warnings.Warning
+	}{
+		"CP codes in children": {
+			givenRule: papi.Rules{
+				Name:      "default",
+				Behaviors: []papi.RuleBehavior{cpCode(map[string]interface{}{"id": float64(2), "name": "second"})},
+				Children: []papi.Rules{
+					{
+						Name: "child",
+						Behaviors: []papi.RuleBehavior{
+							{Name: "caching"},
+							cpCode(map[string]interface{}{"id": float64(1), "name": "first"}),
+						},
+					},
+					{
+						Name:      "other",
+						Behaviors: []papi.RuleBehavior{cpCode(map[string]interface{}{"id": float64(2), "name": "second"})},
+					},
+				},
+			},
+			expectedRule: papi.Rules{
+				Name:      "default",
+				Behaviors: []papi.RuleBehavior{cpCode(map[string]interface{}{"id": "${env.cp_code_2}", "name": "second"})},
+				Children: []papi.Rules{
+					{
+						Name: "child",
+						Behaviors: []papi.RuleBehavior{
+							{Name: "caching"},
+							cpCode(map[string]interface{}{"id": "${env.cp_code_1}", "name": "first"}),
+						},
+					},
+					{
+						Name:      "other",
+						Behaviors: []papi.RuleBehavior{cpCode(map[string]interface{}{"id": "${env.cp_code_2}", "name": "second"})},
+					},
+				},
+			},
+			expected: []CPCode{
+				{ID: 1, Name: "first", ResourceName: "cp_code_1"},
+				{ID: 2, Name: "second", ResourceName: "cp_code_2"},
+			},
+			expectedObjects: []workspace.Object{
+				{Product: "cpcode", ID: "cpc_1", Name: "first", Referenced: true},
+				{Product: "cpcode", ID: "cpc_2", Name: "second", Referenced: true},
+			},
+		},
+		"CP code without name": {
+			givenRule: papi.Rules{
+				Name: "default",
+				Children: []papi.Rules{
+					{Name: "child", Behaviors: []papi.RuleBehavior{cpCode(map[string]interface{}{"id": float64(1)})}},
+				},
+			},
+			expectedRule: papi.Rules{
+				Name: "default",
+				Children: []papi.Rules{
+					{Name: "child", Behaviors: []papi.RuleBehavior{cpCode(map[string]interface{}{"id": float64(1)})}},
+				},
+			},
+			expected: []CPCode{},
+			expectedWarnings: []warnings.Warning{
+				{Product: "property", Object: "test.edgesuite.net", Reason: "CP code 1 in rule 'default/child' has no name, its ID is kept in the rules"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			collector := warnings.NewCollector()
+			recorder := workspace.NewRecorder()
+			ctx := warnings.WithCollector(context.Background(), collector)
+			ctx = workspace.WithRecorder(ctx, recorder)
+
+			cpCodes := referenceCPCodes(ctx, "test.edgesuite.net", &test.givenRule)
+			assert.Equal(t, test.expected, cpCodes)
+			assert.Equal(t, test.expectedRule, test.givenRule)
+			assert.ElementsMatch(t, test.expectedObjects, recorder.Objects())
+			assert.ElementsMatch(t, test.expectedWarnings, collector.Warnings())
+		})
+	}
+}
@@ -21,19 +21,24 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/cps"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/hapi"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
 	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/origins"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/secrets"
 	"github.com/akamai/cli-terraform/pkg/templates"
 	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
@@ -59,6 +64,7 @@ type Hostname struct {
 	Hostname                 string
 	EdgeHostnameResourceName string
 	CertProvisioningType     string
+	Certificate              *Certificate
 }
 
 // TFData holds template data
@@ -79,6 +85,17 @@ type TFData struct {
 	Emails               []string
 	ActivationNote       string
 	Version              string
+	PropertyVersion      int
+	MTLSLinks            []MTLSLink
+	// ReferenceExisting means that edge hostnames and CP codes are referenced as existing objects instead of being managed
+	ReferenceExisting bool
+	CPCodes           []CPCode
+	// CompareVersion is the version of the property the exported rules are compared to, RuleChanges are differences of
+	// the exported rules from its rules
+	CompareVersion int
+	RuleChanges    []RuleChange
+	// Environments are environments for which tfvars files with values live on their networks are generated
+	Environments []Environment
 }
 
 // RulesTemplate represent data used for rules
@@ -122,25 +139,27 @@ var normalizeRuleNameRegexp = regexp.MustCompile(`[^\w-.]`)
 
 var (
 	// ErrHostnamesNotFound is returned when hostnames cloudnt be found
-	ErrHostnamesNotFound = errors.New("hostnames not found")
+	ErrHostnamesNotFound = exitcode.New(exitcode.NotFound, "hostnames not found")
 	// ErrPropertyVersionNotFound is returned when property version couldn't be found
-	ErrPropertyVersionNotFound = errors.New("property version not found")
+	ErrPropertyVersionNotFound = exitcode.New(exitcode.NotFound, "property version not found")
 	// ErrPropertyVersionNotValid is returned when property version couldn't be found
-	ErrPropertyVersionNotValid = errors.New("property version not valid")
+	ErrPropertyVersionNotValid = exitcode.New(exitcode.General, "property version not valid")
 	// ErrProductNameNotFound is returned when product couldn't be found
-	ErrProductNameNotFound = errors.New("product name not found")
+	ErrProductNameNotFound = exitcode.New(exitcode.NotFound, "product name not found")
+	// ErrFetchingCertificates is returned when enrollments of certificates used by hostnames could not be listed
+	ErrFetchingCertificates = exitcode.New(exitcode.API, "fetching certificates")
 	// ErrFetchingHostnameDetails is returned when fetching hsotname details request failed
-	ErrFetchingHostnameDetails = errors.New("fetching hostnames")
+	ErrFetchingHostnameDetails = exitcode.New(exitcode.API, "fetching hostnames")
 	// ErrSavingSnippets is returned when error appeared while saving property snippet JSON files
-	ErrSavingSnippets = errors.New("saving snippets")
+	ErrSavingSnippets = exitcode.New(exitcode.IO, "saving snippets")
 	// ErrPropertyRulesNotFound is returned when property rules couldn't be found
-	ErrPropertyRulesNotFound = errors.New("property rules not found")
+	ErrPropertyRulesNotFound = exitcode.New(exitcode.NotFound, "property rules not found")
 	// ErrGroupNotFound is returned when group couldn't be found
-	ErrGroupNotFound = errors.New("group not found")
+	ErrGroupNotFound = exitcode.New(exitcode.NotFound, "group not found")
 	// ErrPropertyNotFound is returned when property couldn't be found
-	ErrPropertyNotFound = errors.New("property not found")
+	ErrPropertyNotFound = exitcode.New(exitcode.NotFound, "property not found")
 	// ErrSavingFiles is returned when an issue with processing templates occurs
-	ErrSavingFiles = errors.New("saving terraform project files")
+	ErrSavingFiles = exitcode.New(exitcode.IO, "saving terraform project files")
 )
 
 // CmdCreateProperty is an entrypoint to create-property command
@@ -166,28 +185,85 @@ func CmdCreateProperty(c *cli.Context) error {
 
 	err := tools.CheckFiles(propertyPath, variablesPath, importPath)
 	if err != nil {
-		return cli.Exit(color.RedString(err.Error()), 1)
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
 	}
 	templateToFile := map[string]string{
 		"property.tmpl":  propertyPath,
 		"variables.tmpl": variablesPath,
 		"imports.tmpl":   importPath,
 	}
+	if c.Bool("read-only") && c.Bool("bootstrap") {
+		return cli.Exit(color.RedString("Error exporting property: read-only and bootstrap flags cannot be used together"), exitcode.General)
+	}
+	if c.Bool("read-only") && c.Bool("reference-existing") {
+		return cli.Exit(color.RedString("Error exporting property: read-only and reference-existing flags cannot be used together"), exitcode.General)
+	}
+	if c.Bool("read-only") && c.IsSet("environments") {
+		return cli.Exit(color.RedString("Error exporting property: read-only and environments flags cannot be used together"), exitcode.General)
+	}
+	if c.Bool("bootstrap") && c.IsSet("compare-version") {
+		return cli.Exit(color.RedString("Error exporting property: bootstrap and compare-version flags cannot be used together"), exitcode.General)
+	}
+	if c.Bool("read-only") {
+		templateToFile = map[string]string{
+			"property-read-only.tmpl": propertyPath,
+			"variables.tmpl":          variablesPath,
+		}
+	}
+	if c.Bool("bootstrap") {
+		templateToFile = map[string]string{
+			"property-bootstrap.tmpl": propertyPath,
+			"variables.tmpl":          variablesPath,
+			"imports-bootstrap.tmpl":  importPath,
+		}
+	}
+
+	var compareVersion string
+	if c.IsSet("compare-version") {
+		compareVersion = c.String("compare-version")
+		rulesDiffPath := filepath.Join(tfWorkPath, "rules-diff.md")
+		if err := tools.CheckFiles(rulesDiffPath); err != nil {
+			return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+		}
+		templateToFile["rules-diff.tmpl"] = rulesDiffPath
+	}
+
+	var environments []Environment
+	if c.IsSet("environments") {
+		if environments, err = parseEnvironments(c.String("environments")); err != nil {
+			return cli.Exit(color.RedString(fmt.Sprintf("Error exporting property: %s", err)), exitcode.General)
+		}
+		for _, environment := range environments {
+			tfvarsPath := filepath.Join(tfWorkPath, environment.Name+".tfvars")
+			if err := tools.CheckFiles(tfvarsPath); err != nil {
+				return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+			}
+			templateToFile["tfvars-"+strings.ToLower(environment.Network)+".tmpl"] = tfvarsPath
+		}
+	}
 
 	processor := templates.FSTemplateProcessor{
 		TemplatesFS:     templateFiles,
 		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
+	}
+
+	var certOptions *certificateOptions
+	if c.Bool("cert-status") || c.Bool("export-certificates") {
+		certOptions = &certificateOptions{client: cps.Client(sess), export: c.Bool("export-certificates"), readOnly: c.Bool("read-only")}
 	}
 
 	propertyName := c.Args().First()
 	section := edgegrid.GetEdgercSection(c)
-	if err = createProperty(ctx, propertyName, version, section, "property-snippets", tfWorkPath, client, clientHapi, processor); err != nil {
-		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting property: %s", err)), 1)
+	if err = createProperty(ctx, propertyName, version, compareVersion, environments, section, "property-snippets", tfWorkPath, client, clientHapi, certOptions, c.Bool("reference-existing"), processor); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting property: %s", err)), exitcode.Of(err))
 	}
 	return nil
 }
 
-func createProperty(ctx context.Context, propertyName, readVersion, section, jsonDir, tfWorkPath string, client papi.PAPI, clientHapi hapi.HAPI, templateProcessor templates.TemplateProcessor) error {
+func createProperty(ctx context.Context, propertyName, readVersion, compareVersion string, environments []Environment, section, jsonDir, tfWorkPath string, client papi.PAPI, clientHapi hapi.HAPI,
+	certOptions *certificateOptions, referenceExisting bool, templateProcessor templates.TemplateProcessor) error {
 	term := terminal.Get(ctx)
 
 	var tfData TFData
@@ -195,12 +271,13 @@ func createProperty(ctx context.Context, propertyName, readVersion, section, jso
 	tfData.Hostnames = make(map[string]Hostname)
 	tfData.Emails = make([]string, 0)
 	tfData.Section = section
+	tfData.ReferenceExisting = referenceExisting
 
 	// Get Property
-	term.Spinner().Start("Fetching property " + propertyName)
+	progress.Get(ctx).Start("Fetching property " + propertyName)
 	property, err := findProperty(ctx, client, propertyName)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrPropertyNotFound, err)
 	}
 
@@ -209,46 +286,67 @@ func createProperty(ctx context.Context, propertyName, readVersion, section, jso
 	tfData.PropertyID = property.PropertyID
 	tfData.PropertyResourceName = strings.Replace(property.PropertyName, ".", "-", -1)
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	// Get Group
-	term.Spinner().Start("Fetching group ")
+	progress.Get(ctx).Start("Fetching group ")
 	group, err := getGroup(ctx, client, property.GroupID)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrGroupNotFound, err)
 	}
 
 	tfData.GroupName = group.GroupName
 	tfData.GroupID = group.GroupID
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	if readVersion == "" {
 		readVersion = "LATEST"
 	}
 
 	// Get Version
-	term.Spinner().Start("Fetching property version ")
+	progress.Get(ctx).Start("Fetching property version ")
 	version, err := getVersion(ctx, client, property, readVersion)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrPropertyVersionNotFound, err)
 	}
 
 	tfData.ProductID = version.Version.ProductID
 	tfData.Version = readVersion
+	tfData.PropertyVersion = version.Version.PropertyVersion
+	workspace.RecordObject(ctx, workspace.Object{
+		Product: "property",
+		ID:      property.PropertyID,
+		Name:    property.PropertyName,
+		Version: strconv.Itoa(version.Version.PropertyVersion),
+	})
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	// Get Property Rules
-	term.Spinner().Start("Fetching property rules ")
+	progress.Get(ctx).Start("Fetching property rules ")
 	rules, err := getPropertyRules(ctx, client, version)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrPropertyRulesNotFound, err)
 	}
 
+	if compareVersion != "" {
+		if tfData.CompareVersion, tfData.RuleChanges, err = compareRules(ctx, client, property, compareVersion, rules.Rules); err != nil {
+			progress.Get(ctx).Fail()
+			return err
+		}
+	}
+
+	reportAdvancedRules(ctx, property.PropertyName, rules.Rules, "")
+	tfData.MTLSLinks = findMTLSLinks(rules.Rules, "")
+	origins.Record(ctx, "property "+property.PropertyName, findOrigins(rules.Rules)...)
+	if referenceExisting {
+		tfData.CPCodes = referenceCPCodes(ctx, property.PropertyName, &rules.Rules)
+	}
+
 	tfData.IsSecure = "false"
 	if rules.Rules.Options.IsSecure {
 		tfData.IsSecure = "true"
@@ -257,63 +355,134 @@ func createProperty(ctx context.Context, propertyName, readVersion, section, jso
 	// Get Rule Format
 	tfData.RuleFormat = rules.RuleFormat
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	// Get Product
-	term.Spinner().Start("Fetching product name ")
+	progress.Get(ctx).Start("Fetching product name ")
 	product, err := getProduct(ctx, client, tfData.ProductID, property.ContractID)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrProductNameNotFound, err)
 	}
 
 	tfData.ProductName = product.ProductName
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
 	// Get Hostnames
-	term.Spinner().Start("Fetching hostnames ")
+	progress.Get(ctx).Start("Fetching hostnames ")
 	hostnames, err := getHostnames(ctx, client, property, version)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrHostnamesNotFound, err)
 	}
 
 	tfData.Hostnames, tfData.EdgeHostnames, err =
 		getEdgeHostnameDetail(ctx, client, clientHapi, hostnames, product.ProductName, property)
 	if err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrFetchingHostnameDetails, err)
 	}
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
+
+	if certOptions != nil {
+		progress.Get(ctx).Start("Fetching certificate status ")
+		if err = annotateCertificates(ctx, certOptions.client, property.PropertyName, property.ContractID, tfData.Hostnames); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingCertificates, err)
+		}
+		progress.Get(ctx).OK()
+	}
+
+	if len(environments) > 0 {
+		progress.Get(ctx).Start("Fetching versions active on environments ")
+		if tfData.Environments, err = fetchEnvironments(ctx, client, property, environments); err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrHostnamesNotFound, err)
+		}
+		progress.Get(ctx).OK()
+	}
 
-	term.Spinner().Start("Fetching activation details ")
+	progress.Get(ctx).Start("Fetching activation details ")
 	latestActivation, err := fetchLatestActivation(ctx, client, property)
 	if err == nil {
 		tfData.ActivationNote = latestActivation.Note
 		tfData.Emails = getContactEmails(latestActivation)
 	}
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 
-	term.Spinner().Start("Saving TF configurations ")
+	progress.Get(ctx).Start("Saving TF configurations ")
 	if err = templateProcessor.ProcessTemplates(tfData); err != nil {
-		term.Spinner().Fail()
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrSavingFiles, err)
 	}
 
 	// Save snippets
-	if err = saveSnippets(jsonDir, rules, tfWorkPath); err != nil {
-		term.Spinner().Fail()
+	if err = saveSnippets(templates.GetSink(ctx), jsonDir, rules, tfWorkPath); err != nil {
+		progress.Get(ctx).Fail()
 		return fmt.Errorf("%w: %s", ErrSavingSnippets, err)
 	}
 
-	term.Spinner().OK()
+	progress.Get(ctx).OK()
 	term.Printf("Terraform configuration for property '%s' was saved successfully\n", property.PropertyName)
 
+	if certOptions != nil && certOptions.export {
+		exportEnrollments(ctx, certOptions.client, property.PropertyName, property.ContractID, section, tfWorkPath, certOptions.readOnly, tfData.Hostnames)
+	}
+
 	return nil
 }
 
+// compareRules fetches rules of the compared version of the property and returns the version and changes of the
+// exported rules from its rules
+func compareRules(ctx context.Context, client papi.PAPI, property *papi.Property, compareVersion string, rules papi.Rules) (int, []RuleChange, error) {
+	version, err := getVersion(ctx, client, property, compareVersion)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: compared version: %s", ErrPropertyVersionNotFound, err)
+	}
+	compared, err := getPropertyRules(ctx, client, version)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: compared version: %s", ErrPropertyRulesNotFound, err)
+	}
+	return version.Version.PropertyVersion, diffRules(compared.Rules, rules), nil
+}
+
+// reportAdvancedRules reports rules using advanced features, which are exported as they are but can only be modified by Akamai
+func reportAdvancedRules(ctx context.Context, propertyName string, rule papi.Rules, parentPath string) {
+	path := rule.Name
+	if parentPath != "" {
+		path = parentPath + "/" + rule.Name
+	}
+	report := func(reason string) {
+		warnings.Report(ctx, warnings.Warning{
+			Product: "property",
+			Object:  propertyName,
+			Reason:  fmt.Sprintf("rule '%s' %s, it can only be modified by Akamai", path, reason),
+		})
+	}
+
+	if rule.AdvancedOverride != "" {
+		report("contains advanced override")
+	}
+	if rule.CustomOverride != nil {
+		report(fmt.Sprintf("uses custom override '%s'", rule.CustomOverride.Name))
+	}
+	for _, behavior := range rule.Behaviors {
+		if behavior.Name == "advanced" {
+			report("contains advanced behavior")
+		}
+	}
+	for _, criterion := range rule.Criteria {
+		if criterion.Name == "matchAdvanced" {
+			report("contains advanced match")
+		}
+	}
+	for _, child := range rule.Children {
+		reportAdvancedRules(ctx, propertyName, child, path)
+	}
+}
+
 func getHostnames(ctx context.Context, client papi.PAPI, property *papi.Property, version *papi.GetPropertyVersionsResponse) (*papi.HostnameResponseItems, error) {
 	if version == nil {
 		var err error
@@ -353,15 +522,15 @@ func getEdgeHostnameDetail(ctx context.Context, clientPAPI papi.PAPI, clientHAPI
 		}
 
 		// Get slot details
-		 edgeHostnameID, err := strconv.Atoi(strings.Replace(hostname.EdgeHostnameID, "ehn_", "", 1))
-		 if err != nil {
+		edgeHostnameID, err := strconv.Atoi(strings.Replace(hostname.EdgeHostnameID, "ehn_", "", 1))
+		if err != nil {
 			return nil, nil, fmt.Errorf("invalid Hostname id: %s", err)
-		 }
+		}
 
-		// edgeHostname, err := clientHAPI.GetEdgeHostname(ctx, edgeHostnameID)
-		// if err != nil {
-		//	return nil, nil, fmt.Errorf("edge hostname %d not found: %s", edgeHostnameID, err)
-		// }
+		edgeHostname, err := clientHAPI.GetEdgeHostname(ctx, edgeHostnameID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("edge hostname %d not found: %s", edgeHostnameID, err)
+		}
 		papiEdgeHostnames, err := clientPAPI.GetEdgeHostnames(ctx, papi.GetEdgeHostnamesRequest{
 			ContractID: property.ContractID,
 			GroupID:    property.GroupID,
@@ -388,10 +557,8 @@ func getEdgeHostnameDetail(ctx context.Context, clientPAPI papi.PAPI, clientHAPI
 			GroupID:                  property.GroupID,
 			IPv6:                     getIPv6(papiEdgeHostnames, hostname.EdgeHostnameID),
 			EdgeHostnameResourceName: cnameToResource,
-			SlotNumber:               99999
-			//SlotNumber:               edgeHostname.SlotNumber,
-			SecurityType:             "dummy",
-			//SecurityType:             edgeHostname.SecurityType,
+			SlotNumber:               edgeHostname.SlotNumber,
+			SecurityType:             edgeHostname.SecurityType,
 			UseCases:                 useCases,
 		}
 
@@ -436,7 +603,7 @@ func getContactEmails(activation *papi.Activation) []string {
 }
 
 // saveSnippets saves given property rules into files under jsonDir directory
-func saveSnippets(jsonDir string, rules *papi.GetRuleTreeResponse, tfWorkPath string) error {
+func saveSnippets(sink templates.OutputSink, jsonDir string, rules *papi.GetRuleTreeResponse, tfWorkPath string) error {
 
 	// Set up template structure
 	ruleTemplate := RuleTemplate{
@@ -465,10 +632,6 @@ func saveSnippets(jsonDir string, rules *papi.GetRuleTreeResponse, tfWorkPath st
 	}
 
 	snippetsPath := filepath.Join(tfWorkPath, jsonDir)
-	err := os.MkdirAll(snippetsPath, 0755)
-	if err != nil {
-		return fmt.Errorf("can't create directory for rule snippets: %s", err)
-	}
 
 	nameNormalizer := ruleNameNormalizer()
 	for _, rule := range rules.Rules.Children {
@@ -478,7 +641,11 @@ func saveSnippets(jsonDir string, rules *papi.GetRuleTreeResponse, tfWorkPath st
 		}
 		name := nameNormalizer(rule.Name)
 		rulesNamePath := filepath.Join(snippetsPath, fmt.Sprintf("%s.json", name))
-		err = os.WriteFile(rulesNamePath, jsonBody, 0644)
+		jsonBody, err = secrets.Filter(sink, rulesNamePath, jsonBody)
+		if err != nil {
+			return err
+		}
+		err = sink.WriteFile(rulesNamePath, tools.ApplyLineEndings(jsonBody))
 		if err != nil {
 			return fmt.Errorf("can't write property rule snippets: %s", err)
 		}
@@ -490,7 +657,11 @@ func saveSnippets(jsonDir string, rules *papi.GetRuleTreeResponse, tfWorkPath st
 		return fmt.Errorf("can't marshall rule template: %s", err)
 	}
 	templatePath := filepath.Join(snippetsPath, "main.json")
-	err = os.WriteFile(templatePath, jsonBody, 0644)
+	jsonBody, err = secrets.Filter(sink, templatePath, jsonBody)
+	if err != nil {
+		return err
+	}
+	err = sink.WriteFile(templatePath, tools.ApplyLineEndings(jsonBody))
 	if err != nil {
 		return fmt.Errorf("can't write property rule template: %s", err)
 	}
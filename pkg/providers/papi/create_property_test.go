@@ -13,6 +13,8 @@ import (
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/hapi"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
 	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
 	"github.com/akamai/cli/pkg/terminal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -409,6 +411,9 @@ func TestCreateProperty(t *testing.T) {
 		jsonDir             string
 		withError           error
 		readVersion         string
+		compareVersion      string
+		environments        []Environment
+		referenceExisting   bool
 	}{
 		"basic property": {
 			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor, dir string) {
@@ -526,9 +531,10 @@ func TestCreateProperty(t *testing.T) {
 							CertProvisioningType:     "CPS_MANAGED",
 						},
 					},
-					Section: "test_section",
-					Emails:  []string{"jsmith@akamai.com"},
-					Version: "LATEST",
+					Section:         "test_section",
+					Emails:          []string{"jsmith@akamai.com"},
+					Version:         "LATEST",
+					PropertyVersion: 5,
 				}).Return(nil).Once()
 			},
 			dir:     "basic",
@@ -540,6 +546,137 @@ func TestCreateProperty(t *testing.T) {
 				"Dynamic_Content.json",
 			},
 		},
+		"basic property referencing existing edge hostnames and CP codes": {
+			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor, dir string) {
+				c.On("SearchProperties", mock.Anything, papi.SearchRequest{Key: "propertyName", Value: "test.edgesuite.net"}).
+					Return(&searchPropertiesResponse, nil).Once()
+
+				c.On("GetProperty", mock.Anything, papi.GetPropertyRequest{ContractID: "ctr_1", GroupID: "grp_18420", PropertyID: "prp_445968"}).
+					Return(&getPropertyResponse, nil).Once()
+
+				var ruleResponse papi.GetRuleTreeResponse
+				rules, err := os.ReadFile(fmt.Sprintf("./testdata/%s/%s", dir, "mock_rules.json"))
+				assert.NoError(t, err)
+				err = json.Unmarshal(rules, &ruleResponse)
+				assert.NoError(t, err)
+				c.On("GetRuleTree", mock.Anything, papi.GetRuleTreeRequest{PropertyID: "prp_445968", PropertyVersion: 5, ContractID: "ctr_1", GroupID: "grp_18420", ValidateMode: "", ValidateRules: false, RuleFormat: "latest"}).
+					Return(&ruleResponse, nil).Once()
+
+				c.On("GetGroups", mock.Anything).
+					Return(&getGroupsResponse, nil).Once()
+
+				c.On("GetPropertyVersions", mock.Anything, papi.GetPropertyVersionsRequest{
+					PropertyID: "prp_445968",
+					ContractID: "ctr_1",
+					GroupID:    "grp_18420",
+				}).Return(&getPropertyVersionsResponse, nil).Once()
+
+				c.On("GetLatestVersion", mock.Anything, papi.GetLatestVersionRequest{
+					PropertyID:  "prp_445968",
+					ActivatedOn: "",
+					ContractID:  "ctr_1",
+					GroupID:     "grp_18420",
+				}).Return(&getLatestVersionResponse, nil).Once()
+
+				c.On("GetProducts", mock.Anything, papi.GetProductsRequest{
+					ContractID: "ctr_1",
+				}).Return(&getProductsResponse, nil).Once()
+
+				c.On("GetPropertyVersionHostnames", mock.Anything, papi.GetPropertyVersionHostnamesRequest{
+					PropertyID:      "prp_445968",
+					PropertyVersion: 5,
+					ContractID:      "ctr_1",
+					GroupID:         "grp_18420",
+				}).Return(&getPropertyVersionHostnamesResponse, nil).Once()
+
+				h.On("GetEdgeHostname", mock.Anything, 2867480).
+					Return(&hapi.GetEdgeHostnameResponse{
+						EdgeHostnameID:    2867480,
+						RecordName:        "test",
+						DNSZone:           "edgesuite.net",
+						SecurityType:      "STANDARD-TLS",
+						UseDefaultTTL:     false,
+						UseDefaultMap:     false,
+						IPVersionBehavior: "IPV6_IPV4_DUALSTACK",
+						ProductID:         "",
+						TTL:               21600,
+						Map:               "a;test.akamai.net",
+						SerialNumber:      1461,
+					}, nil).Once()
+
+				c.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{
+					ContractID: "ctr_1",
+					GroupID:    "grp_18420",
+				}).Return(&papi.GetEdgeHostnamesResponse{
+					EdgeHostnames: papi.EdgeHostnameItems{
+						Items: []papi.EdgeHostnameGetItem{
+							{
+								ID:                "ehn_2867480",
+								Domain:            "test.edgesuite.net",
+								ProductID:         "",
+								DomainPrefix:      "test",
+								DomainSuffix:      "edgesuite.net",
+								Status:            "CREATED",
+								Secure:            false,
+								IPVersionBehavior: "IPV6_COMPLIANCE",
+								UseCases:          []papi.UseCase(nil),
+							},
+						},
+					},
+				}, nil).Once()
+
+				c.On("GetActivations", mock.Anything, papi.GetActivationsRequest{
+					PropertyID: "prp_445968",
+					ContractID: "ctr_1",
+					GroupID:    "grp_18420",
+				}).Return(&getActivationsResponse, nil).Once()
+
+				p.On("ProcessTemplates", TFData{
+					GroupName:            "test_group",
+					GroupID:              "grp_18420",
+					ContractID:           "ctr_1",
+					PropertyResourceName: "test-edgesuite-net",
+					PropertyName:         "test.edgesuite.net",
+					PropertyID:           "prp_445968",
+					ProductID:            "prd_HTTP_Content_Del",
+					ProductName:          "HTTP_Content_Del",
+					RuleFormat:           "latest",
+					IsSecure:             "false",
+					EdgeHostnames: map[string]EdgeHostname{
+						"test-edgesuite-net": {
+							EdgeHostname:             "test.edgesuite.net",
+							EdgeHostnameID:           "ehn_2867480",
+							ProductName:              "HTTP_Content_Del",
+							ContractID:               "ctr_1",
+							GroupID:                  "grp_18420",
+							ID:                       "",
+							IPv6:                     "IPV6_COMPLIANCE",
+							SecurityType:             "STANDARD-TLS",
+							EdgeHostnameResourceName: "test-edgesuite-net",
+						},
+					},
+					Hostnames: map[string]Hostname{
+						"test.edgesuite.net": {
+							Hostname:                 "test.edgesuite.net",
+							EdgeHostnameResourceName: "test-edgesuite-net",
+							CertProvisioningType:     "CPS_MANAGED",
+						},
+					},
+					Section:           "test_section",
+					Emails:            []string{"jsmith@akamai.com"},
+					Version:           "LATEST",
+					PropertyVersion:   5,
+					ReferenceExisting: true,
+					CPCodes:           []CPCode{{ID: 626358, Name: "Test-NewHire", ResourceName: "cp_code_626358"}},
+				}).Return(nil).Once()
+			},
+			dir:     "basic_reference_existing",
+			jsonDir: "basic_reference_existing/property-snippets",
+			snippetFilesToCheck: []string{
+				"main.json",
+			},
+			referenceExisting: true,
+		},
 		"basic property with cert provisioning type": {
 			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor, dir string) {
 				c.On("SearchProperties", mock.Anything, papi.SearchRequest{Key: "propertyName", Value: "test.edgesuite.net"}).
@@ -656,9 +793,10 @@ func TestCreateProperty(t *testing.T) {
 							CertProvisioningType:     "DEFAULT",
 						},
 					},
-					Section: "test_section",
-					Emails:  []string{"jsmith@akamai.com"},
-					Version: "LATEST",
+					Section:         "test_section",
+					Emails:          []string{"jsmith@akamai.com"},
+					Version:         "LATEST",
+					PropertyVersion: 5,
 				}).Return(nil).Once()
 			},
 			dir:     "basic_with_cert_provisioning_type",
@@ -786,9 +924,10 @@ func TestCreateProperty(t *testing.T) {
 							CertProvisioningType:     "CPS_MANAGED",
 						},
 					},
-					Section: "test_section",
-					Emails:  []string{"jsmith@akamai.com"},
-					Version: "LATEST",
+					Section:         "test_section",
+					Emails:          []string{"jsmith@akamai.com"},
+					Version:         "LATEST",
+					PropertyVersion: 5,
 				}).Return(nil).Once()
 			},
 			dir:     "basic",
@@ -909,9 +1048,10 @@ func TestCreateProperty(t *testing.T) {
 							CertProvisioningType:     "CPS_MANAGED",
 						},
 					},
-					Section: "test_section",
-					Emails:  []string{"jsmith@akamai.com"},
-					Version: "1",
+					Section:         "test_section",
+					Emails:          []string{"jsmith@akamai.com"},
+					Version:         "1",
+					PropertyVersion: 1,
 				}).Return(nil).Once()
 			},
 			dir:     "basic-v1",
@@ -1040,10 +1180,11 @@ func TestCreateProperty(t *testing.T) {
 							CertProvisioningType:     "CPS_MANAGED",
 						},
 					},
-					Section:        "test_section",
-					Emails:         []string{"jsmith@akamai.com", "rjohnson@akamai.com"},
-					ActivationNote: "example note",
-					Version:        "LATEST",
+					Section:         "test_section",
+					Emails:          []string{"jsmith@akamai.com", "rjohnson@akamai.com"},
+					ActivationNote:  "example note",
+					Version:         "LATEST",
+					PropertyVersion: 5,
 				}).Return(nil).Once()
 			},
 			dir: "basic",
@@ -1145,6 +1286,7 @@ func TestCreateProperty(t *testing.T) {
 					RuleFormat:           "latest",
 					IsSecure:             "false",
 					Version:              "LATEST",
+					PropertyVersion:      5,
 					EdgeHostnames: map[string]EdgeHostname{
 						"test-edgesuite-net": {
 							EdgeHostname:             "test.edgesuite.net",
@@ -1248,6 +1390,37 @@ func TestCreateProperty(t *testing.T) {
 			},
 			withError: ErrPropertyVersionNotFound,
 		},
+		"error compared property version not found": {
+			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor, dir string) {
+				c.On("SearchProperties", mock.Anything, papi.SearchRequest{Key: "propertyName", Value: "test.edgesuite.net"}).
+					Return(&searchPropertiesResponse, nil).Once()
+
+				c.On("GetProperty", mock.Anything, papi.GetPropertyRequest{ContractID: "ctr_1", GroupID: "grp_18420", PropertyID: "prp_445968"}).
+					Return(&getPropertyResponse, nil).Once()
+
+				c.On("GetGroups", mock.Anything).
+					Return(&getGroupsResponse, nil).Once()
+
+				c.On("GetLatestVersion", mock.Anything, papi.GetLatestVersionRequest{
+					PropertyID:  "prp_445968",
+					ActivatedOn: "",
+					ContractID:  "ctr_1",
+					GroupID:     "grp_18420",
+				}).Return(&getLatestVersionResponse, nil).Once()
+
+				c.On("GetPropertyVersions", mock.Anything, papi.GetPropertyVersionsRequest{
+					PropertyID: "prp_445968",
+					ContractID: "ctr_1",
+					GroupID:    "grp_18420",
+				}).Return(&getPropertyVersionsResponse, nil).Twice()
+
+				c.On("GetRuleTree", mock.Anything, papi.GetRuleTreeRequest{PropertyID: "prp_445968", PropertyVersion: 5, ContractID: "ctr_1", GroupID: "grp_18420", ValidateMode: "", ValidateRules: false, RuleFormat: "latest"}).
+					Return(&papi.GetRuleTreeResponse{}, nil).Once()
+
+			},
+			compareVersion: "42",
+			withError:      ErrPropertyVersionNotFound,
+		},
 		"error product name not found": {
 			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor, dir string) {
 				c.On("SearchProperties", mock.Anything, papi.SearchRequest{Key: "propertyName", Value: "test.edgesuite.net"}).
@@ -1483,9 +1656,10 @@ func TestCreateProperty(t *testing.T) {
 							CertProvisioningType:     "CPS_MANAGED",
 						},
 					},
-					Section: "test_section",
-					Emails:  []string{"jsmith@akamai.com"},
-					Version: "LATEST",
+					Section:         "test_section",
+					Emails:          []string{"jsmith@akamai.com"},
+					Version:         "LATEST",
+					PropertyVersion: 5,
 				}).Return(fmt.Errorf("oops")).Once()
 			},
 			dir:       "basic",
@@ -1500,7 +1674,7 @@ func TestCreateProperty(t *testing.T) {
 			mp := new(mockProcessor)
 			test.init(mc, mh, mp, test.dir)
 			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
-			err := createProperty(ctx, "test.edgesuite.net", test.readVersion, section, fmt.Sprintf("./testdata/res/%s", test.jsonDir), "./", mc, mh, mp)
+			err := createProperty(ctx, "test.edgesuite.net", test.readVersion, test.compareVersion, test.environments, section, fmt.Sprintf("./testdata/res/%s", test.jsonDir), "./", mc, mh, nil, test.referenceExisting, mp)
 			if test.withError != nil {
 				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
 				return
@@ -1535,6 +1709,9 @@ func TestProcessPolicyTemplates(t *testing.T) {
 		givenData    TFData
 		dir          string
 		filesToCheck []string
+		readOnly     bool
+		bootstrap    bool
+		varNaming    string
 	}{
 		"property": {
 			givenData: TFData{
@@ -1575,6 +1752,241 @@ func TestProcessPolicyTemplates(t *testing.T) {
 			dir:          "basic",
 			filesToCheck: []string{"property.tf", "variables.tf", "import.sh"},
 		},
+		"property referencing existing edge hostnames and CP codes": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						ID:                       "",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "STANDARD-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+				},
+				Section:           "test_section",
+				Emails:            []string{"jsmith@akamai.com"},
+				ReferenceExisting: true,
+				CPCodes:           []CPCode{{ID: 626358, Name: "Test-NewHire", ResourceName: "cp_code_626358"}},
+			},
+			dir:          "basic_reference_existing",
+			filesToCheck: []string{"property.tf", "variables.tf", "import.sh"},
+		},
+		"property with rules compared to another version": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				PropertyVersion:      5,
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						ID:                       "",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "STANDARD-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+				},
+				Section:        "test_section",
+				Emails:         []string{"jsmith@akamai.com"},
+				CompareVersion: 3,
+				RuleChanges: []RuleChange{
+					{Rule: "default", Change: "behavior 'caching' changed"},
+					{Rule: "default/Performance", Change: "rule added"},
+				},
+			},
+			dir:          "compare_version",
+			filesToCheck: []string{"property.tf", "variables.tf", "import.sh", "rules-diff.md"},
+		},
+		"property with environments": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						ID:                       "",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "STANDARD-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+				},
+				Section: "test_section",
+				Emails:  []string{"jsmith@akamai.com"},
+				Environments: []Environment{
+					{Name: "staging", Network: "STAGING", Version: 4, Hostnames: []string{"test.edgesuite.net", "www.test.edgesuite.net"}},
+					{Name: "production", Network: "PRODUCTION"},
+				},
+			},
+			dir:          "basic_environments",
+			filesToCheck: []string{"property.tf", "variables.tf", "import.sh", "staging.tfvars", "production.tfvars"},
+		},
+		"property with environments and prefixed variables": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						ID:                       "",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "STANDARD-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+				},
+				Section: "test_section",
+				Emails:  []string{"jsmith@akamai.com"},
+				Environments: []Environment{
+					{Name: "staging", Network: "STAGING", Version: 4, Hostnames: []string{"test.edgesuite.net", "www.test.edgesuite.net"}},
+					{Name: "production", Network: "PRODUCTION"},
+				},
+			},
+			dir:          "basic_environments_var_naming_prefix",
+			filesToCheck: []string{"property.tf", "variables.tf", "staging.tfvars", "production.tfvars"},
+			varNaming:    "prefix=akamai_",
+		},
+		"property with rules same as another version": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				PropertyVersion:      5,
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				Section:              "test_section",
+				CompareVersion:       4,
+			},
+			dir:          "compare_version_same",
+			filesToCheck: []string{"rules-diff.md"},
+		},
+		"property with mtls links": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "true",
+				Version:              "LATEST",
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "ENHANCED-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+				},
+				MTLSLinks: []MTLSLink{
+					{Rule: "default/mTLS", CASets: []string{"12345", "67890"}},
+					{Rule: "default/Origin", ClientCertificate: "a1b2c3"},
+				},
+				Section: "test_section",
+				Emails:  []string{"jsmith@akamai.com"},
+			},
+			dir:          "basic_mtls",
+			filesToCheck: []string{"property.tf"},
+		},
 		"property with use cases": {
 			givenData: TFData{
 				GroupName:            "test_group",
@@ -1655,19 +2067,166 @@ func TestProcessPolicyTemplates(t *testing.T) {
 			dir:          "basic_with_activation_note",
 			filesToCheck: []string{"property.tf", "variables.tf", "import.sh"},
 		},
+		"property with certificate status": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						ID:                       "",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "STANDARD-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+						Certificate: &Certificate{
+							EnrollmentID: 12345,
+							CommonName:   "test.edgesuite.net",
+							Status:       CertificateDeployed,
+							Expiry:       "2023-01-01T00:00:00Z",
+						},
+					},
+				},
+				Section: "test_section",
+				Emails:  []string{"jsmith@akamai.com"},
+			},
+			dir:          "basic_with_certificate_status",
+			filesToCheck: []string{"property.tf", "variables.tf", "import.sh"},
+		},
+		"read-only property": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				PropertyVersion:      5,
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "STANDARD-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+					"www.test.edgesuite.net": {
+						Hostname:                 "www.test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+				},
+				Section: "test_section",
+			},
+			dir:          "basic_read_only",
+			filesToCheck: []string{"property.tf", "variables.tf"},
+			readOnly:     true,
+		},
+		"bootstrap property": {
+			givenData: TFData{
+				GroupName:            "test_group",
+				GroupID:              "grp_18420",
+				ContractID:           "ctr_1",
+				PropertyResourceName: "test-edgesuite-net",
+				PropertyName:         "test.edgesuite.net",
+				PropertyID:           "prp_445968",
+				ProductID:            "prd_HTTP_Content_Del",
+				ProductName:          "HTTP_Content_Del",
+				RuleFormat:           "latest",
+				IsSecure:             "false",
+				Version:              "LATEST",
+				PropertyVersion:      5,
+				EdgeHostnames: map[string]EdgeHostname{
+					"test-edgesuite-net": {
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2867480",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						IPv6:                     "IPV6_COMPLIANCE",
+						SecurityType:             "STANDARD-TLS",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+					},
+				},
+				Hostnames: map[string]Hostname{
+					"test.edgesuite.net": {
+						Hostname:                 "test.edgesuite.net",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						CertProvisioningType:     "CPS_MANAGED",
+					},
+				},
+				Section: "test_section",
+				Emails:  []string{"jsmith@akamai.com"},
+			},
+			dir:          "basic_bootstrap",
+			filesToCheck: []string{"property.tf", "variables.tf", "import.sh"},
+			bootstrap:    true,
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			tools.VarNaming = test.varNaming
+			defer func() { tools.VarNaming = "" }()
 			require.NoError(t, os.MkdirAll(fmt.Sprintf("./testdata/res/%s", test.dir), 0755))
 			processor := templates.FSTemplateProcessor{
 				TemplatesFS: templateFiles,
 				TemplateTargets: map[string]string{
-					"property.tmpl":  fmt.Sprintf("./testdata/res/%s/property.tf", test.dir),
-					"variables.tmpl": fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
-					"imports.tmpl":   fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
+					"property.tmpl":          fmt.Sprintf("./testdata/res/%s/property.tf", test.dir),
+					"variables.tmpl":         fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+					"imports.tmpl":           fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
+					"rules-diff.tmpl":        fmt.Sprintf("./testdata/res/%s/rules-diff.md", test.dir),
+					"tfvars-staging.tmpl":    fmt.Sprintf("./testdata/res/%s/staging.tfvars", test.dir),
+					"tfvars-production.tmpl": fmt.Sprintf("./testdata/res/%s/production.tfvars", test.dir),
 				},
 			}
+			if test.readOnly {
+				processor.TemplateTargets = map[string]string{
+					"property-read-only.tmpl": fmt.Sprintf("./testdata/res/%s/property.tf", test.dir),
+					"variables.tmpl":          fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+				}
+			}
+			if test.bootstrap {
+				processor.TemplateTargets = map[string]string{
+					"property-bootstrap.tmpl": fmt.Sprintf("./testdata/res/%s/property.tf", test.dir),
+					"variables.tmpl":          fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+					"imports-bootstrap.tmpl":  fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
+				}
+			}
 			require.NoError(t, processor.ProcessTemplates(test.givenData))
 
 			for _, f := range test.filesToCheck {
@@ -1749,3 +2308,42 @@ func TestRuleNameNormalizer(t *testing.T) {
 		})
 	}
 }
+
+func TestReportAdvancedRules(t *testing.T) {
+	rules := papi.Rules{
+		Name:             "default",
+		AdvancedOverride: "<xml/>",
+		Children: []papi.Rules{
+			{
+				Name:      "Offload",
+				Behaviors: []papi.RuleBehavior{{Name: "caching"}, {Name: "advanced"}},
+				Children: []papi.Rules{
+					{
+						Name:     "Images",
+						Criteria: []papi.RuleBehavior{{Name: "matchAdvanced"}},
+					},
+				},
+			},
+			{
+				Name:      "Performance",
+				Behaviors: []papi.RuleBehavior{{Name: "sureRoute"}},
+			},
+		},
+	}
+
+	collector := warnings.NewCollector()
+	ctx := warnings.WithCollector(context.Background(), collector)
+	reportAdvancedRules(ctx, "test.property.com", rules, "")
+
+	var reasons []string
+	for _, w := range collector.Warnings() {
+		assert.Equal(t, "property", w.Product)
+		assert.Equal(t, "test.property.com", w.Object)
+		reasons = append(reasons, w.Reason)
+	}
+	assert.Equal(t, []string{
+		"rule 'default' contains advanced override, it can only be modified by Akamai",
+		"rule 'default/Offload' contains advanced behavior, it can only be modified by Akamai",
+		"rule 'default/Offload/Images' contains advanced match, it can only be modified by Akamai",
+	}, reasons)
+}
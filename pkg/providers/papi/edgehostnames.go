@@ -0,0 +1,192 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/hapi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/identity"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// TFEdgeHostnamesData holds template data of edge hostnames exported independently of properties
+type TFEdgeHostnamesData struct {
+	ContractID    string
+	ContractName  string
+	GroupID       string
+	GroupName     string
+	EdgeHostnames []EdgeHostname
+	// MissingProduct is set when product of any edge hostname is not known, such edge hostnames use product_id variable
+	MissingProduct bool
+	Section        string
+}
+
+var (
+	// ErrFetchingEdgeHostnames is returned when edge hostnames of the contract and group could not be listed
+	ErrFetchingEdgeHostnames = exitcode.New(exitcode.API, "fetching edge hostnames")
+)
+
+// CmdCreateEdgeHostnames is an entrypoint to export-edgehostnames command
+func CmdCreateEdgeHostnames(c *cli.Context) error {
+	ctx := c.Context
+	sess := edgegrid.GetSession(c.Context)
+	client := papi.Client(sess)
+	clientHapi := hapi.Client(sess)
+
+	// tfWorkPath is a target directory for generated terraform resources
+	var tfWorkPath = "./"
+	if c.IsSet("tfworkpath") {
+		tfWorkPath = c.String("tfworkpath")
+	}
+
+	edgeHostnamesPath := filepath.Join(tfWorkPath, "edgehostnames.tf")
+	variablesPath := filepath.Join(tfWorkPath, "variables.tf")
+	importPath := filepath.Join(tfWorkPath, "import.sh")
+
+	err := tools.CheckFiles(edgeHostnamesPath, variablesPath, importPath)
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	templateToFile := map[string]string{
+		"edgehostnames.tmpl":           edgeHostnamesPath,
+		"edgehostnames-variables.tmpl": variablesPath,
+		"edgehostnames-imports.tmpl":   importPath,
+	}
+
+	processor := templates.FSTemplateProcessor{
+		TemplatesFS:     templateFiles,
+		TemplateTargets: templateToFile,
+		Output:          templates.GetOutputTarget(ctx),
+		Sink:            templates.GetSink(ctx),
+	}
+
+	resolver := identity.NewResolver(client)
+	contract, err := resolver.Contract(ctx, c.String("contract"))
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting edge hostnames: %s", err)), exitcode.Of(err))
+	}
+	group, err := resolver.Group(ctx, c.String("group"), contract.ID)
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting edge hostnames: %s", err)), exitcode.Of(err))
+	}
+
+	section := edgegrid.GetEdgercSection(c)
+	if err = createEdgeHostnames(ctx, contract, group, section, client, clientHapi, processor); err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error exporting edge hostnames: %s", err)), exitcode.Of(err))
+	}
+	return nil
+}
+
+func createEdgeHostnames(ctx context.Context, contract identity.Contract, group identity.Group, section string, client papi.PAPI, clientHapi hapi.HAPI, templateProcessor templates.TemplateProcessor) error {
+	term := terminal.Get(ctx)
+	term.Printf("Exporting edge hostnames\n")
+
+	contractID, groupID := withPrefix(contract.ID, "ctr_"), withPrefix(group.ID, "grp_")
+	progress.Get(ctx).Start(fmt.Sprintf("Fetching edge hostnames of contract %s and group %s ", contractID, groupID))
+	response, err := client.GetEdgeHostnames(ctx, papi.GetEdgeHostnamesRequest{
+		ContractID: contractID,
+		GroupID:    groupID,
+	})
+	if err != nil {
+		progress.Get(ctx).Fail()
+		return fmt.Errorf("%w: %s", ErrFetchingEdgeHostnames, err)
+	}
+	progress.Get(ctx).OK()
+
+	tfData := TFEdgeHostnamesData{
+		ContractID:    contractID,
+		ContractName:  contract.Name,
+		GroupID:       groupID,
+		GroupName:     group.Name,
+		EdgeHostnames: make([]EdgeHostname, 0, len(response.EdgeHostnames.Items)),
+		Section:       section,
+	}
+	progress.Get(ctx).Start("Fetching edge hostname details ")
+	progress.Get(ctx).Total(len(response.EdgeHostnames.Items))
+	for _, item := range response.EdgeHostnames.Items {
+		if err := ctx.Err(); err != nil {
+			progress.Get(ctx).Fail()
+			return err
+		}
+		edgeHostname, err := getEdgeHostname(ctx, clientHapi, contractID, groupID, response, item)
+		if err != nil {
+			progress.Get(ctx).Fail()
+			return fmt.Errorf("%w: %s", ErrFetchingHostnameDetails, err)
+		}
+		if edgeHostname.ProductName == "" {
+			tfData.MissingProduct = true
+			warnings.Report(ctx, warnings.Warning{
+				Product: "edge hostname",
+				Object:  edgeHostname.EdgeHostname,
+				Reason:  "product of the edge hostname is not known, set it with product_id variable",
+			})
+		}
+		tfData.EdgeHostnames = append(tfData.EdgeHostnames, *edgeHostname)
+		workspace.RecordObject(ctx, workspace.Object{Product: "edgehostname", ID: edgeHostname.EdgeHostnameID, Name: edgeHostname.EdgeHostname})
+		progress.Get(ctx).Step()
+	}
+	progress.Get(ctx).OK()
+	sort.Slice(tfData.EdgeHostnames, func(i, j int) bool {
+		return tfData.EdgeHostnames[i].EdgeHostnameResourceName < tfData.EdgeHostnames[j].EdgeHostnameResourceName
+	})
+
+	progress.Get(ctx).Start("Saving TF configurations ")
+	if err := templateProcessor.ProcessTemplates(tfData); err != nil {
+		progress.Get(ctx).Fail()
+		return err
+	}
+	progress.Get(ctx).OK()
+	term.Printf("Terraform configuration for %d edge hostname(s) was saved successfully\n", len(tfData.EdgeHostnames))
+
+	return nil
+}
+
+// getEdgeHostname returns the listed edge hostname with certificate details fetched from HAPI
+func getEdgeHostname(ctx context.Context, clientHapi hapi.HAPI, contractID, groupID string, response *papi.GetEdgeHostnamesResponse, item papi.EdgeHostnameGetItem) (*EdgeHostname, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(item.ID, "ehn_"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid edge hostname id: %s", err)
+	}
+	details, err := clientHapi.GetEdgeHostname(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("edge hostname %d not found: %s", id, err)
+	}
+	useCases, err := getUseCases(response, item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get use cases: %s", err)
+	}
+	return &EdgeHostname{
+		EdgeHostname:             item.Domain,
+		EdgeHostnameID:           item.ID,
+		ProductName:              strings.TrimPrefix(item.ProductID, "prd_"),
+		ContractID:               contractID,
+		GroupID:                  groupID,
+		IPv6:                     item.IPVersionBehavior,
+		EdgeHostnameResourceName: strings.Replace(item.Domain, ".", "-", -1),
+		SlotNumber:               details.SlotNumber,
+		SecurityType:             details.SecurityType,
+		UseCases:                 useCases,
+	}, nil
+}
+
+// withPrefix returns the ID with the prefix of its type, such as ctr_ for contracts, which is optional in flags
+func withPrefix(id, prefix string) string {
+	if strings.HasPrefix(id, prefix) {
+		return id
+	}
+	return prefix + id
+}
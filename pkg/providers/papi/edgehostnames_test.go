@@ -0,0 +1,261 @@
+package papi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/hapi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/identity"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/warnings"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEdgeHostnames(t *testing.T) {
+	getEdgeHostnamesResponse := papi.GetEdgeHostnamesResponse{
+		ContractID: "ctr_1",
+		GroupID:    "grp_18420",
+		EdgeHostnames: papi.EdgeHostnameItems{Items: []papi.EdgeHostnameGetItem{
+			{
+				ID:                "ehn_2",
+				Domain:            "test.edgesuite.net",
+				ProductID:         "prd_HTTP_Content_Del",
+				DomainPrefix:      "test",
+				DomainSuffix:      "edgesuite.net",
+				IPVersionBehavior: "IPV6_COMPLIANCE",
+				UseCases: []papi.UseCase{
+					{Option: "BACKGROUND", Type: "GLOBAL", UseCase: "Download_Mode"},
+				},
+			},
+			{
+				ID:                "ehn_1",
+				Domain:            "example.edgekey.net",
+				DomainPrefix:      "example",
+				DomainSuffix:      "edgekey.net",
+				IPVersionBehavior: "IPV4",
+			},
+		}},
+	}
+
+	tests := map[string]struct {
+		contract         identity.Contract
+		group            identity.Group
+		init             func(*papi.Mock, *hapi.Mock, *mockProcessor)
+		expectedWarnings []warnings.Warning
+		withError        error
+	}{
+		"edge hostnames": {
+			contract: identity.Contract{ID: "1", Name: "DIRECT_CUSTOMER"},
+			group:    identity.Group{ID: "grp_18420", Name: "test_group"},
+			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor) {
+				c.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{ContractID: "ctr_1", GroupID: "grp_18420"}).
+					Return(&getEdgeHostnamesResponse, nil).Once()
+				h.On("GetEdgeHostname", mock.Anything, 2).
+					Return(&hapi.GetEdgeHostnameResponse{EdgeHostnameID: 2, SecurityType: "STANDARD-TLS"}, nil).Once()
+				h.On("GetEdgeHostname", mock.Anything, 1).
+					Return(&hapi.GetEdgeHostnameResponse{EdgeHostnameID: 1, SecurityType: "ENHANCED-TLS", SlotNumber: 1234}, nil).Once()
+				p.On("ProcessTemplates", TFEdgeHostnamesData{
+					ContractID:   "ctr_1",
+					ContractName: "DIRECT_CUSTOMER",
+					GroupID:      "grp_18420",
+					GroupName:    "test_group",
+					EdgeHostnames: []EdgeHostname{
+						{
+							EdgeHostname:             "example.edgekey.net",
+							EdgeHostnameID:           "ehn_1",
+							ContractID:               "ctr_1",
+							GroupID:                  "grp_18420",
+							IPv6:                     "IPV4",
+							EdgeHostnameResourceName: "example-edgekey-net",
+							SlotNumber:               1234,
+							SecurityType:             "ENHANCED-TLS",
+						},
+						{
+							EdgeHostname:             "test.edgesuite.net",
+							EdgeHostnameID:           "ehn_2",
+							ProductName:              "HTTP_Content_Del",
+							ContractID:               "ctr_1",
+							GroupID:                  "grp_18420",
+							IPv6:                     "IPV6_COMPLIANCE",
+							EdgeHostnameResourceName: "test-edgesuite-net",
+							SecurityType:             "STANDARD-TLS",
+							UseCases:                 "[\n  {\n    \"option\": \"BACKGROUND\",\n    \"type\": \"GLOBAL\",\n    \"useCase\": \"Download_Mode\"\n  }\n]",
+						},
+					},
+					MissingProduct: true,
+					Section:        "test_section",
+				}).Return(nil).Once()
+			},
+			expectedWarnings: []warnings.Warning{
+				{Product: "edge hostname", Object: "example.edgekey.net", Reason: "product of the edge hostname is not known, set it with product_id variable"},
+			},
+		},
+		"no edge hostnames": {
+			contract: identity.Contract{ID: "ctr_1"},
+			group:    identity.Group{ID: "18420"},
+			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor) {
+				c.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{ContractID: "ctr_1", GroupID: "grp_18420"}).
+					Return(&papi.GetEdgeHostnamesResponse{}, nil).Once()
+				p.On("ProcessTemplates", TFEdgeHostnamesData{
+					ContractID:    "ctr_1",
+					GroupID:       "grp_18420",
+					EdgeHostnames: []EdgeHostname{},
+					Section:       "test_section",
+				}).Return(nil).Once()
+			},
+		},
+		"error fetching edge hostnames": {
+			contract: identity.Contract{ID: "ctr_1"},
+			group:    identity.Group{ID: "grp_18420"},
+			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor) {
+				c.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{ContractID: "ctr_1", GroupID: "grp_18420"}).
+					Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingEdgeHostnames,
+		},
+		"error fetching edge hostname details": {
+			contract: identity.Contract{ID: "ctr_1"},
+			group:    identity.Group{ID: "grp_18420"},
+			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor) {
+				c.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{ContractID: "ctr_1", GroupID: "grp_18420"}).
+					Return(&getEdgeHostnamesResponse, nil).Once()
+				h.On("GetEdgeHostname", mock.Anything, 2).
+					Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: ErrFetchingHostnameDetails,
+		},
+		"error saving files": {
+			contract: identity.Contract{ID: "ctr_1"},
+			group:    identity.Group{ID: "grp_18420"},
+			init: func(c *papi.Mock, h *hapi.Mock, p *mockProcessor) {
+				c.On("GetEdgeHostnames", mock.Anything, papi.GetEdgeHostnamesRequest{ContractID: "ctr_1", GroupID: "grp_18420"}).
+					Return(&papi.GetEdgeHostnamesResponse{}, nil).Once()
+				p.On("ProcessTemplates", mock.Anything).Return(templates.ErrSavingFiles).Once()
+			},
+			withError: templates.ErrSavingFiles,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mc := new(papi.Mock)
+			mh := new(hapi.Mock)
+			mp := new(mockProcessor)
+			test.init(mc, mh, mp)
+			collector := warnings.NewCollector()
+			ctx := terminal.Context(context.Background(), terminal.New(terminal.DiscardWriter(), nil, terminal.DiscardWriter()))
+			ctx = warnings.WithCollector(ctx, collector)
+			err := createEdgeHostnames(ctx, test.contract, test.group, "test_section", mc, mh, mp)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, test.expectedWarnings, collector.Warnings())
+			mc.AssertExpectations(t)
+			mh.AssertExpectations(t)
+			mp.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessEdgeHostnamesTemplates(t *testing.T) {
+	useCasesJSON, err := json.MarshalIndent([]papi.UseCase{
+		{Option: "BACKGROUND", Type: "GLOBAL", UseCase: "Download_Mode"},
+	}, "", "  ")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		givenData    TFEdgeHostnamesData
+		dir          string
+		filesToCheck []string
+	}{
+		"edge hostnames": {
+			givenData: TFEdgeHostnamesData{
+				ContractID:   "ctr_1",
+				ContractName: "DIRECT_CUSTOMER",
+				GroupID:      "grp_18420",
+				GroupName:    "test_group",
+				EdgeHostnames: []EdgeHostname{
+					{
+						EdgeHostname:             "example.edgekey.net",
+						EdgeHostnameID:           "ehn_1",
+						ProductName:              "Fresca",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						IPv6:                     "IPV4",
+						EdgeHostnameResourceName: "example-edgekey-net",
+						SlotNumber:               1234,
+						SecurityType:             "ENHANCED-TLS",
+					},
+					{
+						EdgeHostname:             "test.edgesuite.net",
+						EdgeHostnameID:           "ehn_2",
+						ProductName:              "HTTP_Content_Del",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						IPv6:                     "IPV6_COMPLIANCE",
+						EdgeHostnameResourceName: "test-edgesuite-net",
+						SecurityType:             "STANDARD-TLS",
+						UseCases:                 string(useCasesJSON),
+					},
+				},
+				Section: "test_section",
+			},
+			dir:          "edgehostnames",
+			filesToCheck: []string{"edgehostnames.tf", "variables.tf", "import.sh"},
+		},
+		"edge hostnames with missing product": {
+			givenData: TFEdgeHostnamesData{
+				ContractID: "ctr_1",
+				GroupID:    "grp_18420",
+				EdgeHostnames: []EdgeHostname{
+					{
+						EdgeHostname:             "example.edgekey.net",
+						EdgeHostnameID:           "ehn_1",
+						ContractID:               "ctr_1",
+						GroupID:                  "grp_18420",
+						IPv6:                     "IPV4",
+						EdgeHostnameResourceName: "example-edgekey-net",
+						SecurityType:             "ENHANCED-TLS",
+					},
+				},
+				MissingProduct: true,
+				Section:        "test_section",
+			},
+			dir:          "edgehostnames_missing_product",
+			filesToCheck: []string{"edgehostnames.tf", "variables.tf", "import.sh"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, os.MkdirAll(fmt.Sprintf("./testdata/res/%s", test.dir), 0755))
+			processor := templates.FSTemplateProcessor{
+				TemplatesFS: templateFiles,
+				TemplateTargets: map[string]string{
+					"edgehostnames.tmpl":           fmt.Sprintf("./testdata/res/%s/edgehostnames.tf", test.dir),
+					"edgehostnames-variables.tmpl": fmt.Sprintf("./testdata/res/%s/variables.tf", test.dir),
+					"edgehostnames-imports.tmpl":   fmt.Sprintf("./testdata/res/%s/import.sh", test.dir),
+				},
+			}
+			require.NoError(t, processor.ProcessTemplates(test.givenData))
+
+			for _, f := range test.filesToCheck {
+				expected, err := os.ReadFile(fmt.Sprintf("./testdata/%s/%s", test.dir, f))
+				require.NoError(t, err)
+				result, err := os.ReadFile(fmt.Sprintf("./testdata/res/%s/%s", test.dir, f))
+				require.NoError(t, err)
+				assert.Equal(t, string(expected), string(result))
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+)
+
+// Environment holds values live on the activation network of an environment, which are written into its tfvars file
+type Environment struct {
+	// Name is the name of the environment given on the command line, the tfvars file is named after it
+	Name string
+	// Network is the activation network of the environment, STAGING or PRODUCTION
+	Network string
+	// Version is the version of the property active on the network, 0 if no version is active
+	Version   int
+	Hostnames []string
+}
+
+// environmentNetworks maps accepted names of environments to their activation networks
+var environmentNetworks = map[string]string{
+	"staging":    "STAGING",
+	"prod":       "PRODUCTION",
+	"production": "PRODUCTION",
+}
+
+// parseEnvironments returns environments of the comma separated list of their names, each network can be given once
+func parseEnvironments(list string) ([]Environment, error) {
+	var environments []Environment
+	used := make(map[string]string)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		network, ok := environmentNetworks[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown environment '%s', supported environments are staging, prod and production", name)
+		}
+		if previous, ok := used[network]; ok {
+			return nil, fmt.Errorf("environments '%s' and '%s' both use %s network", previous, name, network)
+		}
+		used[network] = name
+		environments = append(environments, Environment{Name: name, Network: network})
+	}
+	return environments, nil
+}
+
+// fetchEnvironments fills versions of the property active on networks of the environments and their hostnames
+func fetchEnvironments(ctx context.Context, client papi.PAPI, property *papi.Property, environments []Environment) ([]Environment, error) {
+	result := make([]Environment, 0, len(environments))
+	for _, environment := range environments {
+		active := property.StagingVersion
+		if environment.Network == "PRODUCTION" {
+			active = property.ProductionVersion
+		}
+		if active != nil {
+			environment.Version = *active
+			hostnames, err := getHostnames(ctx, client, property, &papi.GetPropertyVersionsResponse{
+				Version: papi.PropertyVersionGetItem{PropertyVersion: *active},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("version %d active on %s: %s", *active, environment.Network, err)
+			}
+			environment.Hostnames = make([]string, 0, len(hostnames.Items))
+			for _, hostname := range hostnames.Items {
+				environment.Hostnames = append(environment.Hostnames, hostname.CnameFrom)
+			}
+			sort.Strings(environment.Hostnames)
+		}
+		result = append(result, environment)
+	}
+	return result, nil
+}
+
+// Environment returns the exported environment using the network, nil if there is none
+func (d TFData) Environment(network string) *Environment {
+	for i := range d.Environments {
+		if d.Environments[i].Network == network {
+			return &d.Environments[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package papi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironments(t *testing.T) {
+	tests := map[string]struct {
+		list      string
+		expected  []Environment
+		withError string
+	}{
+		"staging and prod": {
+			list: "staging, prod",
+			expected: []Environment{
+				{Name: "staging", Network: "STAGING"},
+				{Name: "prod", Network: "PRODUCTION"},
+			},
+		},
+		"production only": {
+			list:     "Production",
+			expected: []Environment{{Name: "Production", Network: "PRODUCTION"}},
+		},
+		"unknown environment": {
+			list:      "staging,qa",
+			withError: "unknown environment 'qa'",
+		},
+		"network used twice": {
+			list:      "prod,production",
+			withError: "environments 'prod' and 'production' both use PRODUCTION network",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			environments, err := parseEnvironments(test.list)
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, environments)
+		})
+	}
+}
+
+func TestFetchEnvironments(t *testing.T) {
+	property := &papi.Property{
+		PropertyID:     "prp_1",
+		ContractID:     "ctr_1",
+		GroupID:        "grp_1",
+		StagingVersion: tools.IntPtr(4),
+	}
+	hostnamesRequest := papi.GetPropertyVersionHostnamesRequest{PropertyID: "prp_1", PropertyVersion: 4, ContractID: "ctr_1", GroupID: "grp_1"}
+
+	tests := map[string]struct {
+		init      func(*papi.Mock)
+		expected  []Environment
+		withError string
+	}{
+		"version active on staging only": {
+			init: func(c *papi.Mock) {
+				c.On("GetPropertyVersionHostnames", mock.Anything, hostnamesRequest).Return(&papi.GetPropertyVersionHostnamesResponse{
+					Hostnames: papi.HostnameResponseItems{Items: []papi.Hostname{
+						{CnameFrom: "www.example.com"},
+						{CnameFrom: "example.com"},
+					}},
+				}, nil).Once()
+			},
+			expected: []Environment{
+				{Name: "staging", Network: "STAGING", Version: 4, Hostnames: []string{"example.com", "www.example.com"}},
+				{Name: "prod", Network: "PRODUCTION"},
+			},
+		},
+		"error fetching hostnames": {
+			init: func(c *papi.Mock) {
+				c.On("GetPropertyVersionHostnames", mock.Anything, hostnamesRequest).Return(nil, fmt.Errorf("oops")).Once()
+			},
+			withError: "version 4 active on STAGING: oops",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := new(papi.Mock)
+			test.init(client)
+			environments, err := fetchEnvironments(context.Background(), client, property, []Environment{
+				{Name: "staging", Network: "STAGING"},
+				{Name: "prod", Network: "PRODUCTION"},
+			})
+			if test.withError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, environments)
+			client.AssertExpectations(t)
+		})
+	}
+}
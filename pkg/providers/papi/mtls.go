@@ -0,0 +1,73 @@
+package papi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+)
+
+type (
+	// MTLSLink describes a rule whose behavior refers to mTLS objects, which are managed outside of the property
+	MTLSLink struct {
+		Rule string
+		// CASets are IDs of Edge TrustStore CA sets against which client certificates are validated
+		CASets []string
+		// ClientCertificate is the version GUID of the mTLS Keystore client certificate presented to the origin
+		ClientCertificate string
+	}
+)
+
+// Behaviors and their options which refer to mTLS objects
+const (
+	enforceMTLSBehavior     = "enforceMtlsSettings"
+	caSetsOption            = "certificateAuthoritySet"
+	originKeystoreBehavior  = "mtlsOriginKeystore"
+	clientCertificateOption = "clientCertificateVersionGuid"
+)
+
+// findMTLSLinks returns links of rules in the tree which enforce client certificates or present one to the origin, nil
+// is returned when there is none
+func findMTLSLinks(rule papi.Rules, parentPath string) []MTLSLink {
+	path := rule.Name
+	if parentPath != "" {
+		path = parentPath + "/" + rule.Name
+	}
+
+	var links []MTLSLink
+	for _, behavior := range rule.Behaviors {
+		switch behavior.Name {
+		case enforceMTLSBehavior:
+			if caSets := caSetIDs(behavior.Options[caSetsOption]); len(caSets) > 0 {
+				links = append(links, MTLSLink{Rule: path, CASets: caSets})
+			}
+		case originKeystoreBehavior:
+			if guid, ok := behavior.Options[clientCertificateOption].(string); ok && guid != "" {
+				links = append(links, MTLSLink{Rule: path, ClientCertificate: guid})
+			}
+		}
+	}
+	for _, child := range rule.Children {
+		links = append(links, findMTLSLinks(child, path)...)
+	}
+	return links
+}
+
+// caSetIDs returns sorted CA set IDs from the option value, which holds them either as strings or as numbers
+func caSetIDs(option interface{}) []string {
+	values, ok := option.([]interface{})
+	if !ok {
+		return nil
+	}
+	var ids []string
+	for _, value := range values {
+		switch id := value.(type) {
+		case string:
+			ids = append(ids, id)
+		case float64:
+			ids = append(ids, fmt.Sprintf("%.0f", id))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
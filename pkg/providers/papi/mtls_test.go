@@ -0,0 +1,58 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMTLSLinks(t *testing.T) {
+	tests := map[string]struct {
+		rules    papi.Rules
+		expected []MTLSLink
+	}{
+		"no mtls behaviors": {
+			rules: papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{{Name: "origin"}}},
+		},
+		"CA sets in nested rule": {
+			rules: papi.Rules{
+				Name: "default",
+				Children: []papi.Rules{
+					{
+						Name: "mTLS",
+						Behaviors: []papi.RuleBehavior{{
+							Name:    enforceMTLSBehavior,
+							Options: papi.RuleOptionsMap{caSetsOption: []interface{}{"67890", float64(12345)}},
+						}},
+					},
+				},
+			},
+			expected: []MTLSLink{{Rule: "default/mTLS", CASets: []string{"12345", "67890"}}},
+		},
+		"origin client certificate": {
+			rules: papi.Rules{
+				Name: "default",
+				Behaviors: []papi.RuleBehavior{{
+					Name:    originKeystoreBehavior,
+					Options: papi.RuleOptionsMap{clientCertificateOption: "a1b2c3"},
+				}},
+			},
+			expected: []MTLSLink{{Rule: "default", ClientCertificate: "a1b2c3"}},
+		},
+		"behaviors without referenced objects": {
+			rules: papi.Rules{
+				Name: "default",
+				Behaviors: []papi.RuleBehavior{
+					{Name: enforceMTLSBehavior, Options: papi.RuleOptionsMap{caSetsOption: []interface{}{}}},
+					{Name: originKeystoreBehavior, Options: papi.RuleOptionsMap{"enable": false}},
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, findMTLSLinks(test.rules, ""))
+		})
+	}
+}
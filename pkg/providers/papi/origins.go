@@ -0,0 +1,40 @@
+package papi
+
+import (
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/origins"
+)
+
+// Behaviors, criteria and their options which refer to origins
+const (
+	originBehavior          = "origin"
+	originHostnameOption    = "hostname"
+	cloudletsOriginCriteria = "cloudletsOrigin"
+	originIDOption          = "originId"
+)
+
+// findOrigins returns origins referenced by rules in the tree, in order of the tree. Hostnames of origin behaviors of
+// rules matching a conditional origin with a cloudlets origin criterion are returned together with the ID of the
+// conditional origin.
+func findOrigins(rule papi.Rules) []origins.Origin {
+	var conditional string
+	for _, criterion := range rule.Criteria {
+		if id, ok := criterion.Options[originIDOption].(string); ok && criterion.Name == cloudletsOriginCriteria && id != "" {
+			conditional = id
+			break
+		}
+	}
+	var result []origins.Origin
+	for _, behavior := range rule.Behaviors {
+		if hostname, ok := behavior.Options[originHostnameOption].(string); ok && behavior.Name == originBehavior && hostname != "" {
+			result = append(result, origins.Origin{ID: conditional, Hostname: hostname})
+		}
+	}
+	if conditional != "" && len(result) == 0 {
+		result = append(result, origins.Origin{ID: conditional})
+	}
+	for _, child := range rule.Children {
+		result = append(result, findOrigins(child)...)
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/akamai/cli-terraform/pkg/origins"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindOrigins(t *testing.T) {
+	tests := map[string]struct {
+		rules    papi.Rules
+		expected []origins.Origin
+	}{
+		"origin behaviors and conditional origins": {
+			rules: papi.Rules{
+				Name: "default",
+				Behaviors: []papi.RuleBehavior{
+					{Name: "origin", Options: papi.RuleOptionsMap{"originType": "CUSTOMER", "hostname": "origin.example.com"}},
+				},
+				Children: []papi.Rules{
+					{
+						Name:     "Conditional Origin",
+						Criteria: []papi.RuleBehavior{{Name: "cloudletsOrigin", Options: papi.RuleOptionsMap{"originId": "dc_east"}}},
+						Behaviors: []papi.RuleBehavior{
+							{Name: "origin", Options: papi.RuleOptionsMap{"originType": "CUSTOMER", "hostname": "east.example.com"}},
+						},
+					},
+					{
+						Name:     "Conditional Origin Without Origin Behavior",
+						Criteria: []papi.RuleBehavior{{Name: "cloudletsOrigin", Options: papi.RuleOptionsMap{"originId": "dc_west"}}},
+					},
+				},
+			},
+			expected: []origins.Origin{
+				{Hostname: "origin.example.com"},
+				{ID: "dc_east", Hostname: "east.example.com"},
+				{ID: "dc_west"},
+			},
+		},
+		"net storage origin without hostname": {
+			rules: papi.Rules{
+				Name: "default",
+				Behaviors: []papi.RuleBehavior{
+					{Name: "origin", Options: papi.RuleOptionsMap{"originType": "NET_STORAGE"}},
+					{Name: "caching", Options: papi.RuleOptionsMap{"hostname": "not.an.origin"}},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, findOrigins(test.rules))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package papi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+)
+
+// RuleChange is a difference of a rule of the exported version of the property from the compared version
+type RuleChange struct {
+	// Rule is the path of the rule, e.g. 'default/Performance/Compressible Objects'
+	Rule   string
+	Change string
+}
+
+// diffRules returns changes of rules of the exported version compared to rules of the compared version, in order of
+// the exported rule tree followed by removed rules. Rules are matched by their path; behaviors and criteria by their
+// name and position among behaviors or criteria of the same name.
+func diffRules(compared, exported papi.Rules) []RuleChange {
+	return diffRule(compared, exported, compared.Name)
+}
+
+func diffRule(compared, exported papi.Rules, path string) []RuleChange {
+	var changes []RuleChange
+	add := func(format string, args ...interface{}) {
+		changes = append(changes, RuleChange{Rule: path, Change: fmt.Sprintf(format, args...)})
+	}
+
+	if compared.CriteriaMustSatisfy != exported.CriteriaMustSatisfy {
+		add("criteria match changed from '%s' to '%s'", compared.CriteriaMustSatisfy, exported.CriteriaMustSatisfy)
+	}
+	if !reflect.DeepEqual(compared.Variables, exported.Variables) {
+		add("variables changed")
+	}
+	if compared.Comments != exported.Comments {
+		add("comments changed")
+	}
+	for _, change := range diffBehaviors(compared.Criteria, exported.Criteria) {
+		add("criterion %s", change)
+	}
+	for _, change := range diffBehaviors(compared.Behaviors, exported.Behaviors) {
+		add("behavior %s", change)
+	}
+
+	comparedChildren := make(map[string]papi.Rules)
+	for _, child := range compared.Children {
+		comparedChildren[child.Name] = child
+	}
+	exportedNames := make(map[string]bool)
+	for _, child := range exported.Children {
+		exportedNames[child.Name] = true
+		childPath := path + "/" + child.Name
+		old, ok := comparedChildren[child.Name]
+		if !ok {
+			changes = append(changes, RuleChange{Rule: childPath, Change: "rule added"})
+			continue
+		}
+		changes = append(changes, diffRule(old, child, childPath)...)
+	}
+	for _, child := range compared.Children {
+		if !exportedNames[child.Name] {
+			changes = append(changes, RuleChange{Rule: path + "/" + child.Name, Change: "rule removed"})
+		}
+	}
+	return changes
+}
+
+// diffBehaviors returns descriptions of added, removed and changed behaviors or criteria
+func diffBehaviors(compared, exported []papi.RuleBehavior) []string {
+	key := func(behaviors []papi.RuleBehavior, i int) string {
+		n := 0
+		for _, behavior := range behaviors[:i] {
+			if behavior.Name == behaviors[i].Name {
+				n++
+			}
+		}
+		return fmt.Sprintf("%s#%d", behaviors[i].Name, n)
+	}
+
+	old := make(map[string]papi.RuleBehavior)
+	for i := range compared {
+		old[key(compared, i)] = compared[i]
+	}
+	var changes []string
+	exportedKeys := make(map[string]bool)
+	for i, behavior := range exported {
+		k := key(exported, i)
+		exportedKeys[k] = true
+		previous, ok := old[k]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("'%s' added", behavior.Name))
+		case !reflect.DeepEqual(previous, behavior):
+			changes = append(changes, fmt.Sprintf("'%s' changed", behavior.Name))
+		}
+	}
+	for i, behavior := range compared {
+		if !exportedKeys[key(compared, i)] {
+			changes = append(changes, fmt.Sprintf("'%s' removed", behavior.Name))
+		}
+	}
+	return changes
+}
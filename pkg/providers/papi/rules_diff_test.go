@@ -0,0 +1,78 @@
+package papi
+
+import (
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/papi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRules(t *testing.T) {
+	caching := papi.RuleBehavior{Name: "caching", Options: papi.RuleOptionsMap{"behavior": "MAX_AGE", "ttl": "1d"}}
+	origin := papi.RuleBehavior{Name: "origin", Options: papi.RuleOptionsMap{"hostname": "origin.example.com"}}
+	path := papi.RuleBehavior{Name: "path", Options: papi.RuleOptionsMap{"values": []interface{}{"/images/*"}}}
+
+	tests := map[string]struct {
+		compared papi.Rules
+		exported papi.Rules
+		expected []RuleChange
+	}{
+		"same rules": {
+			compared: papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{origin, caching}},
+			exported: papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{origin, caching}},
+		},
+		"behaviors added, changed and removed": {
+			compared: papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{origin, caching}},
+			exported: papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{
+				{Name: "origin", Options: papi.RuleOptionsMap{"hostname": "new.example.com"}},
+				{Name: "sureRoute"},
+			}},
+			expected: []RuleChange{
+				{Rule: "default", Change: "behavior 'origin' changed"},
+				{Rule: "default", Change: "behavior 'sureRoute' added"},
+				{Rule: "default", Change: "behavior 'caching' removed"},
+			},
+		},
+		"repeated behavior added": {
+			compared: papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{caching}},
+			exported: papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{caching, caching}},
+			expected: []RuleChange{
+				{Rule: "default", Change: "behavior 'caching' added"},
+			},
+		},
+		"rule properties changed": {
+			compared: papi.Rules{Name: "default", CriteriaMustSatisfy: papi.RuleCriteriaMustSatisfyAll, Comments: "old"},
+			exported: papi.Rules{Name: "default", CriteriaMustSatisfy: papi.RuleCriteriaMustSatisfyAny, Comments: "new",
+				Variables: []papi.RuleVariable{{Name: "PMUSER_TEST", Value: "test"}}},
+			expected: []RuleChange{
+				{Rule: "default", Change: "criteria match changed from 'all' to 'any'"},
+				{Rule: "default", Change: "variables changed"},
+				{Rule: "default", Change: "comments changed"},
+			},
+		},
+		"child rules added, changed and removed": {
+			compared: papi.Rules{Name: "default", Children: []papi.Rules{
+				{Name: "Images", Criteria: []papi.RuleBehavior{path}, Behaviors: []papi.RuleBehavior{caching}},
+				{Name: "Offload"},
+			}},
+			exported: papi.Rules{Name: "default", Children: []papi.Rules{
+				{Name: "Performance", Children: []papi.Rules{{Name: "Compression"}}},
+				{Name: "Images", Behaviors: []papi.RuleBehavior{caching}, Children: []papi.Rules{
+					{Name: "Large", Behaviors: []papi.RuleBehavior{origin}},
+				}},
+			}},
+			expected: []RuleChange{
+				{Rule: "default/Performance", Change: "rule added"},
+				{Rule: "default/Images", Change: "criterion 'path' removed"},
+				{Rule: "default/Images/Large", Change: "rule added"},
+				{Rule: "default/Offload", Change: "rule removed"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, diffRules(test.compared, test.exported))
+		})
+	}
+}
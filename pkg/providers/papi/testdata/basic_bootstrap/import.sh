@@ -0,0 +1,4 @@
+terraform init
+terraform import akamai_edge_hostname.test-edgesuite-net ehn_2867480,ctr_1,grp_18420
+terraform import akamai_property_bootstrap.test-edgesuite-net prp_445968,ctr_1,grp_18420
+terraform import akamai_property.test-edgesuite-net prp_445968,ctr_1,grp_18420,LATEST
@@ -0,0 +1,19 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type    = string
+  default = "test_section"
+}
+
+variable "env" {
+  type    = string
+  default = "staging"
+}
+
+variable "activation_version" {
+  type    = number
+  default = null
+}
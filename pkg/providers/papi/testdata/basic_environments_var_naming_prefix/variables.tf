@@ -0,0 +1,19 @@
+variable "akamai_edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "akamai_config_section" {
+  type    = string
+  default = "test_section"
+}
+
+variable "akamai_env" {
+  type    = string
+  default = "staging"
+}
+
+variable "akamai_activation_version" {
+  type    = number
+  default = null
+}
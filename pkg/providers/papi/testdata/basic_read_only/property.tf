@@ -0,0 +1,40 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+data "akamai_property" "test-edgesuite-net" {
+  name    = "test.edgesuite.net"
+  version = 5
+}
+
+data "akamai_property_rules" "test-edgesuite-net" {
+  contract_id = "ctr_1"
+  group_id    = "grp_18420"
+  property_id = "prp_445968"
+  version     = 5
+}
+
+locals {
+  property_id      = "prp_445968"
+  property_version = 5
+  contract_id      = "ctr_1"
+  group_id         = "grp_18420"
+  product_id       = "prd_HTTP_Content_Del"
+  rule_format      = "latest"
+  rules            = jsondecode(data.akamai_property_rules.test-edgesuite-net.rules)
+  hostnames = {
+    "test.edgesuite.net"     = "test.edgesuite.net"
+    "www.test.edgesuite.net" = "test.edgesuite.net"
+  }
+}
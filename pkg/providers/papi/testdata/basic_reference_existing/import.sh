@@ -0,0 +1,2 @@
+terraform init
+terraform import akamai_property.test-edgesuite-net prp_445968,ctr_1,grp_18420,LATEST
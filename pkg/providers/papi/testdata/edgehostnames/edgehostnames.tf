@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_edge_hostname" "example-edgekey-net" {
+  product_id    = "prd_Fresca"
+  contract_id   = "ctr_1"     # DIRECT_CUSTOMER
+  group_id      = "grp_18420" # test_group
+  ip_behavior   = "IPV4"
+  edge_hostname = "example.edgekey.net"
+  certificate   = 1234
+}
+
+resource "akamai_edge_hostname" "test-edgesuite-net" {
+  product_id    = "prd_HTTP_Content_Del"
+  contract_id   = "ctr_1"     # DIRECT_CUSTOMER
+  group_id      = "grp_18420" # test_group
+  ip_behavior   = "IPV6_COMPLIANCE"
+  edge_hostname = "test.edgesuite.net"
+  use_cases = jsonencode([
+    {
+      "option" : "BACKGROUND",
+      "type" : "GLOBAL",
+      "useCase" : "Download_Mode"
+    }
+  ])
+}
@@ -0,0 +1,3 @@
+terraform init
+terraform import akamai_edge_hostname.example-edgekey-net ehn_1,ctr_1,grp_18420
+terraform import akamai_edge_hostname.test-edgesuite-net ehn_2,ctr_1,grp_18420
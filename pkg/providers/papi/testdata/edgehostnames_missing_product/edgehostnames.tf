@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_edge_hostname" "example-edgekey-net" {
+  product_id    = var.product_id
+  contract_id   = "ctr_1"
+  group_id      = "grp_18420"
+  ip_behavior   = "IPV4"
+  edge_hostname = "example.edgekey.net"
+}
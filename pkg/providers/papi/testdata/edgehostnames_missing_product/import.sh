@@ -0,0 +1,2 @@
+terraform init
+terraform import akamai_edge_hostname.example-edgekey-net ehn_1,ctr_1,grp_18420
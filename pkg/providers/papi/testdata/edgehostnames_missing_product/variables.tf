@@ -0,0 +1,14 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type    = string
+  default = "test_section"
+}
+
+variable "product_id" {
+  type        = string
+  description = "Product of edge hostnames for which it is not returned by the API, e.g. prd_Fresca"
+}
@@ -0,0 +1,364 @@
+package papi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// ruleSchema is a JSON schema of a rule format, only the catalog of behaviors and criteria is used
+	ruleSchema struct {
+		Definitions struct {
+			Catalog struct {
+				Behaviors map[string]*schemaNode `json:"behaviors"`
+				Criteria  map[string]*schemaNode `json:"criteria"`
+			} `json:"catalog"`
+		} `json:"definitions"`
+	}
+
+	// schemaNode is the subset of JSON schema keywords used by rule format schemas to describe options
+	schemaNode struct {
+		Type                 interface{}            `json:"type"`
+		Enum                 []interface{}          `json:"enum"`
+		Minimum              *float64               `json:"minimum"`
+		Maximum              *float64               `json:"maximum"`
+		Pattern              string                 `json:"pattern"`
+		Properties           map[string]*schemaNode `json:"properties"`
+		AdditionalProperties *bool                  `json:"additionalProperties"`
+		Items                *schemaNode            `json:"items"`
+	}
+)
+
+// latestRuleFormat is the rule format which is not pinned to any version
+const latestRuleFormat = "latest"
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// variableRegexp matches references to property variables, which are accepted in place of an option of any type
+var variableRegexp = regexp.MustCompile(`^\{\{(user|builtin)\.[A-Za-z0-9_]+\}\}$`)
+
+var (
+	// ErrReadingPropertyRules is returned when the rules file or any of its included snippets cannot be read
+	ErrReadingPropertyRules = exitcode.New(exitcode.IO, "unable to read property rules")
+	// ErrPropertyRulesFormat is returned when the rules file is not a rule tree or a rule
+	ErrPropertyRulesFormat = exitcode.New(exitcode.General, "property rules must be a rule tree with 'rules' object or a single rule")
+	// ErrRuleFormatNotBundled is returned when there is no bundled schema of the rule format
+	ErrRuleFormatNotBundled = exitcode.New(exitcode.Unsupported, "rule format is not bundled")
+	// ErrInvalidPropertyRules is returned when any of behaviors or criteria does not conform to the rule format schema
+	ErrInvalidPropertyRules = exitcode.New(exitcode.General, "invalid property rules")
+)
+
+// CmdValidatePropertyRules is an entrypoint to validate-property-rules command
+func CmdValidatePropertyRules(c *cli.Context) error {
+	term := terminal.Get(c.Context)
+	rulesPath := c.Args().First()
+	ruleFormat, problems, err := validatePropertyRules(rulesPath, c.String("rule-format"))
+	if err != nil {
+		return cli.Exit(color.RedString(err.Error()), exitcode.Of(err))
+	}
+	if len(problems) == 0 {
+		term.Printf("Property rules in '%s' are valid against rule format %s\n", rulesPath, ruleFormat)
+		return nil
+	}
+	for _, problem := range problems {
+		term.Printf("  %s\n", problem)
+	}
+	return cli.Exit(color.RedString(fmt.Sprintf("%s in '%s' against rule format %s: %d problem(s) found", ErrInvalidPropertyRules, rulesPath, ruleFormat, len(problems))), exitcode.Of(ErrInvalidPropertyRules))
+}
+
+// validatePropertyRules checks behaviors and criteria of the rule tree in the file, with snippets it includes, against
+// the bundled schema of the rule format and returns the rule format used and found problems; the rule format of the
+// file is used when none is given
+func validatePropertyRules(rulesPath, ruleFormat string) (string, []string, error) {
+	content, err := os.ReadFile(rulesPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrReadingPropertyRules, err)
+	}
+	var tree struct {
+		RuleFormat string                 `json:"ruleFormat"`
+		Rules      map[string]interface{} `json:"rules"`
+	}
+	if err := json.Unmarshal(content, &tree); err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrPropertyRulesFormat, err)
+	}
+	rule := tree.Rules
+	if rule == nil {
+		if err := json.Unmarshal(content, &rule); err != nil || rule["name"] == nil {
+			return "", nil, ErrPropertyRulesFormat
+		}
+	}
+	if ruleFormat == "" {
+		ruleFormat = tree.RuleFormat
+	}
+	if ruleFormat == "" {
+		return "", nil, fmt.Errorf("%w: the file has no 'ruleFormat', set it with --rule-format", ErrRuleFormatNotBundled)
+	}
+
+	schema, ruleFormat, err := loadRuleSchema(ruleFormat)
+	if err != nil {
+		return "", nil, err
+	}
+	v := rulesValidator{schema: schema, dir: filepath.Dir(rulesPath)}
+	if err := v.validateRule(rule, ""); err != nil {
+		return "", nil, err
+	}
+	return ruleFormat, v.problems, nil
+}
+
+// bundledRuleFormats returns sorted rule formats whose schemas are bundled, the newest one is the last
+func bundledRuleFormats() []string {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		return nil
+	}
+	var formats []string
+	for _, entry := range entries {
+		formats = append(formats, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// loadRuleSchema returns the bundled schema of the rule format and the rule format itself, the newest bundled rule
+// format is used for 'latest'
+func loadRuleSchema(ruleFormat string) (*ruleSchema, string, error) {
+	formats := bundledRuleFormats()
+	if ruleFormat == latestRuleFormat && len(formats) > 0 {
+		ruleFormat = formats[len(formats)-1]
+	}
+	content, err := schemaFiles.ReadFile(path.Join("schemas", ruleFormat+".json"))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: '%s', bundled rule formats are: %s", ErrRuleFormatNotBundled, ruleFormat, strings.Join(formats, ", "))
+	}
+	var schema ruleSchema
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, "", fmt.Errorf("%w: '%s': %s", ErrRuleFormatNotBundled, ruleFormat, err)
+	}
+	return &schema, ruleFormat, nil
+}
+
+// rulesValidator collects problems of rules validated against the schema, included snippets are resolved against dir
+type rulesValidator struct {
+	schema   *ruleSchema
+	dir      string
+	problems []string
+}
+
+func (v *rulesValidator) validateRule(rule map[string]interface{}, parentPath string) error {
+	name, _ := rule["name"].(string)
+	rulePath := name
+	if parentPath != "" {
+		rulePath = parentPath + "/" + name
+	}
+	v.validateItems(rule["behaviors"], "behavior", v.schema.Definitions.Catalog.Behaviors, rulePath)
+	v.validateItems(rule["criteria"], "criterion", v.schema.Definitions.Catalog.Criteria, rulePath)
+
+	children, _ := rule["children"].([]interface{})
+	for _, child := range children {
+		switch c := child.(type) {
+		case map[string]interface{}:
+			if err := v.validateRule(c, rulePath); err != nil {
+				return err
+			}
+		case string:
+			if !strings.HasPrefix(c, "#include:") {
+				v.problems = append(v.problems, fmt.Sprintf("rule '%s': child '%s' is neither a rule nor an include", rulePath, c))
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(v.dir, strings.TrimPrefix(c, "#include:")))
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrReadingPropertyRules, err)
+			}
+			var included map[string]interface{}
+			if err := json.Unmarshal(content, &included); err != nil {
+				return fmt.Errorf("%w: '%s': %s", ErrPropertyRulesFormat, c, err)
+			}
+			if err := v.validateRule(included, rulePath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateItems checks that behaviors or criteria are in the catalog and their options conform to it
+func (v *rulesValidator) validateItems(items interface{}, kind string, catalog map[string]*schemaNode, rulePath string) {
+	list, _ := items.([]interface{})
+	for _, item := range list {
+		fields, _ := item.(map[string]interface{})
+		name, _ := fields["name"].(string)
+		prefix := fmt.Sprintf("rule '%s': %s '%s'", rulePath, kind, name)
+		itemSchema, ok := catalog[name]
+		if !ok {
+			v.problems = append(v.problems, fmt.Sprintf("%s: unknown %s", prefix, kind))
+			continue
+		}
+		optionsSchema := itemSchema.Properties["options"]
+		if optionsSchema == nil {
+			continue
+		}
+		options, _ := fields["options"].(map[string]interface{})
+		for _, problem := range optionsSchema.validate(options, "") {
+			v.problems = append(v.problems, fmt.Sprintf("%s: %s", prefix, problem))
+		}
+	}
+}
+
+// validate returns problems of the value, problems are prefixed with the option path when not empty
+func (n *schemaNode) validate(value interface{}, optionPath string) []string {
+	prefix := ""
+	if optionPath != "" {
+		prefix = fmt.Sprintf("option '%s': ", optionPath)
+	}
+	if s, ok := value.(string); ok && variableRegexp.MatchString(s) {
+		return nil
+	}
+	if !n.hasType(value) {
+		return []string{fmt.Sprintf("%svalue %s is not of type %s", prefix, describeValue(value), strings.Join(n.types(), " or "))}
+	}
+	if len(n.Enum) > 0 && !enumContains(n.Enum, value) {
+		allowed := make([]string, 0, len(n.Enum))
+		for _, e := range n.Enum {
+			allowed = append(allowed, describeValue(e))
+		}
+		return []string{fmt.Sprintf("%svalue %s is not one of: %s", prefix, describeValue(value), strings.Join(allowed, ", "))}
+	}
+
+	var problems []string
+	switch val := value.(type) {
+	case float64:
+		if n.Minimum != nil && val < *n.Minimum || n.Maximum != nil && val > *n.Maximum {
+			problems = append(problems, fmt.Sprintf("%svalue %s is out of bounds %s", prefix, describeValue(value), n.bounds()))
+		}
+	case string:
+		if n.Pattern != "" {
+			if re, err := regexp.Compile(n.Pattern); err == nil && !re.MatchString(val) {
+				problems = append(problems, fmt.Sprintf("%svalue %s does not match pattern '%s'", prefix, describeValue(value), n.Pattern))
+			}
+		}
+	case []interface{}:
+		if n.Items != nil {
+			for i, item := range val {
+				problems = append(problems, n.Items.validate(item, fmt.Sprintf("%s[%d]", optionPath, i))...)
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := key
+			if optionPath != "" {
+				keyPath = optionPath + "." + key
+			}
+			property, ok := n.Properties[key]
+			if !ok {
+				if n.AdditionalProperties != nil && !*n.AdditionalProperties {
+					problems = append(problems, fmt.Sprintf("unknown option '%s'", keyPath))
+				}
+				continue
+			}
+			problems = append(problems, property.validate(val[key], keyPath)...)
+		}
+	}
+	return problems
+}
+
+// types returns types of the node, the keyword holds either a single type or an array of them
+func (n *schemaNode) types() []string {
+	var types []string
+	switch t := n.Type.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	return types
+}
+
+// hasType reports whether the value is of any of the types of the node, any value is accepted when there are none
+func (n *schemaNode) hasType(value interface{}) bool {
+	types := n.types()
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		switch val := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || t == "integer" && val == math.Trunc(val) {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (n *schemaNode) bounds() string {
+	switch {
+	case n.Minimum != nil && n.Maximum != nil:
+		return fmt.Sprintf("%v to %v", *n.Minimum, *n.Maximum)
+	case n.Minimum != nil:
+		return fmt.Sprintf("of at least %v", *n.Minimum)
+	}
+	return fmt.Sprintf("of at most %v", *n.Maximum)
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+func describeValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("'%s'", s)
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
@@ -0,0 +1,85 @@
+package papi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePropertyRules(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		ruleFormat     string
+		expectFormat   string
+		expectProblems []string
+		withError      error
+	}{
+		"exported rules of latest rule format": {
+			path:         "./testdata/basic/property-snippets/main.json",
+			expectFormat: "v2023-01-05",
+		},
+		"invalid rules with included snippets": {
+			path:         "./testdata/validate_rules/snippets/main.json",
+			expectFormat: "v2023-01-05",
+			expectProblems: []string{
+				"rule 'default': behavior 'origin': option 'compress': value 'yes' is not of type boolean",
+				"rule 'default': behavior 'origin': option 'httpsPort': value 70000 is out of bounds 1 to 65535",
+				"rule 'default': behavior 'cpCode': unknown option 'value.owner'",
+				"rule 'default': behavior 'fastPurge': unknown behavior",
+				"rule 'default/Static Content': behavior 'cacheError': option 'ttl': value '10x' does not match pattern '^[0-9]+[smhd]$'",
+				"rule 'default/Static Content': criterion 'fileExtension': option 'matchOperator': value 'IS_ANY_OF' is not one of: 'IS_ONE_OF', 'IS_NOT_ONE_OF'",
+				"rule 'default/Static Content': criterion 'fileExtension': option 'values[1]': value 7 is not of type string",
+				"rule 'default/Static Content/Images': behavior 'redirect': option 'responseCode': value 308 is not one of: 301, 302, 303, 307",
+			},
+		},
+		"single rule with given rule format": {
+			path:         "./testdata/validate_rules/no_rule_format.json",
+			ruleFormat:   "v2023-01-05",
+			expectFormat: "v2023-01-05",
+		},
+		"single rule without rule format": {
+			path:      "./testdata/validate_rules/no_rule_format.json",
+			withError: ErrRuleFormatNotBundled,
+		},
+		"rule format not bundled": {
+			path:      "./testdata/validate_rules/unbundled_format.json",
+			withError: ErrRuleFormatNotBundled,
+		},
+		"missing included snippet": {
+			path:      "./testdata/validate_rules/missing_include.json",
+			withError: ErrReadingPropertyRules,
+		},
+		"missing file": {
+			path:      "./testdata/validate_rules/missing.json",
+			withError: ErrReadingPropertyRules,
+		},
+		"not a rule": {
+			path:      "./testdata/validate_rules/snippets",
+			withError: ErrReadingPropertyRules,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ruleFormat, problems, err := validatePropertyRules(test.path, test.ruleFormat)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectFormat, ruleFormat)
+			assert.Equal(t, test.expectProblems, problems)
+		})
+	}
+}
+
+func TestBundledRuleFormats(t *testing.T) {
+	formats := bundledRuleFormats()
+	require.NotEmpty(t, formats)
+	for _, format := range formats {
+		_, loaded, err := loadRuleSchema(format)
+		require.NoError(t, err)
+		assert.Equal(t, format, loaded)
+	}
+}
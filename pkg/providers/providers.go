@@ -0,0 +1,101 @@
+// Package providers contains exporters of Akamai products and the registry of additional exporters compiled into the CLI
+package providers
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/urfave/cli/v2"
+)
+
+// Exporter is an exporter of a product maintained out of this repository, e.g. for a beta API, compiled into the CLI
+// by importing its package for side effects from the main package, which registers it in an init function:
+//
+//	func init() {
+//		providers.Register(betaExporter{})
+//	}
+type Exporter interface {
+	// Name returns the name of the exported product
+	Name() string
+	// Command returns the export command. Its Action is set when the command is wired and runs Export, tfworkpath flag is
+	// added if the command does not define it.
+	Command() *cli.Command
+	// Export fetches the object given on the command line and generates its configuration with the processor
+	Export(c *cli.Context, processor templates.TemplateProcessor) error
+	// Templates returns templates of the generated configuration. Template '<file>.tmpl' is rendered into '<file>' in the
+	// target directory, e.g. 'main.tf.tmpl' into 'main.tf' and 'import.sh.tmpl' into 'import.sh'.
+	Templates() fs.FS
+}
+
+var (
+	exportersMutex sync.Mutex
+	exporters      = make(map[string]Exporter)
+)
+
+// Register makes the exporter available as a command of the CLI. It panics if the exporter has no name, no command or
+// an exporter of the same name is already registered.
+func Register(exporter Exporter) {
+	exportersMutex.Lock()
+	defer exportersMutex.Unlock()
+
+	name := exporter.Name()
+	if name == "" {
+		panic("providers: exporter without name")
+	}
+	if exporter.Command() == nil {
+		panic(fmt.Sprintf("providers: exporter '%s' without command", name))
+	}
+	if _, ok := exporters[name]; ok {
+		panic(fmt.Sprintf("providers: exporter '%s' registered twice", name))
+	}
+	exporters[name] = exporter
+}
+
+// Exporters returns registered exporters sorted by name
+func Exporters() []Exporter {
+	exportersMutex.Lock()
+	defer exportersMutex.Unlock()
+
+	result := make([]Exporter, 0, len(exporters))
+	for _, exporter := range exporters {
+		result = append(result, exporter)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+// TemplateTargets returns files in the directory into which templates of the exporter are rendered, keyed by template
+// names
+func TemplateTargets(exporter Exporter, dir string) (map[string]string, error) {
+	targets := make(map[string]string)
+	err := fs.WalkDir(exporter.Templates(), ".", func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(filePath) != ".tmpl" {
+			return nil
+		}
+		name := path.Base(filePath)
+		if _, ok := targets[name]; ok {
+			return fmt.Errorf("template '%s' is defined twice", name)
+		}
+		file := strings.TrimSuffix(name, ".tmpl")
+		if path.Ext(file) == "" {
+			return fmt.Errorf("template '%s' does not name the extension of the file it is rendered into", name)
+		}
+		targets[name] = filepath.Join(dir, file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("templates of exporter '%s': %s", exporter.Name(), err)
+	}
+	return targets, nil
+}
@@ -0,0 +1,100 @@
+package providers
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+type testExporter struct {
+	name      string
+	command   *cli.Command
+	templates fs.FS
+}
+
+func (e testExporter) Name() string {
+	return e.name
+}
+
+func (e testExporter) Command() *cli.Command {
+	return e.command
+}
+
+func (e testExporter) Export(_ *cli.Context, _ templates.TemplateProcessor) error {
+	return nil
+}
+
+func (e testExporter) Templates() fs.FS {
+	return e.templates
+}
+
+func TestRegister(t *testing.T) {
+	defer func() { exporters = make(map[string]Exporter) }()
+
+	second := testExporter{name: "second", command: &cli.Command{Name: "export-second"}}
+	first := testExporter{name: "first", command: &cli.Command{Name: "export-first"}}
+	Register(second)
+	Register(first)
+	assert.Equal(t, []Exporter{first, second}, Exporters())
+
+	assert.PanicsWithValue(t, "providers: exporter 'first' registered twice", func() {
+		Register(testExporter{name: "first", command: &cli.Command{Name: "export-other"}})
+	})
+	assert.PanicsWithValue(t, "providers: exporter without name", func() {
+		Register(testExporter{command: &cli.Command{Name: "export-other"}})
+	})
+	assert.PanicsWithValue(t, "providers: exporter 'third' without command", func() {
+		Register(testExporter{name: "third"})
+	})
+}
+
+func TestTemplateTargets(t *testing.T) {
+	tests := map[string]struct {
+		givenTemplates fstest.MapFS
+		expected       map[string]string
+		withError      string
+	}{
+		"templates in subdirectory": {
+			givenTemplates: fstest.MapFS{
+				"templates/main.tf.tmpl":   {},
+				"templates/import.sh.tmpl": {},
+				"templates/README.md":      {},
+			},
+			expected: map[string]string{
+				"main.tf.tmpl":   filepath.Join("out", "main.tf"),
+				"import.sh.tmpl": filepath.Join("out", "import.sh"),
+			},
+		},
+		"template without file extension": {
+			givenTemplates: fstest.MapFS{
+				"templates/main.tmpl": {},
+			},
+			withError: "templates of exporter 'test': template 'main.tmpl' does not name the extension of the file it is rendered into",
+		},
+		"template defined twice": {
+			givenTemplates: fstest.MapFS{
+				"a/main.tf.tmpl": {},
+				"b/main.tf.tmpl": {},
+			},
+			withError: "templates of exporter 'test': template 'main.tf.tmpl' is defined twice",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			targets, err := TemplateTargets(testExporter{name: "test", templates: test.givenTemplates}, "out")
+			if test.withError != "" {
+				assert.EqualError(t, err, test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, targets)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package readme contains code for generating README.md describing exported Terraform configuration and next steps
+package readme
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type (
+	// Data represents the data used in README template
+	Data struct {
+		// Command is the export command, e.g. 'export-property'
+		Command string
+		// Object is the name of the exported object
+		Object string
+		// Resources are resources declared by the exported configuration, in order of their declaration
+		Resources []Resource
+		// Modules are modules called by the exported configuration
+		Modules []Module
+		// Variables are input variables declared by the exported configuration
+		Variables []Variable
+		// ImportScripts are names of scripts importing the exported objects into Terraform state
+		ImportScripts []string
+		// Warnings are warnings about skipped, unsupported or guessed parts reported during the export
+		Warnings []string
+	}
+
+	// Resource is a resource block of the exported configuration
+	Resource struct {
+		Type string
+		Name string
+		File string
+	}
+
+	// Module is a module block of the exported configuration
+	Module struct {
+		Name   string
+		Source string
+	}
+
+	// Variable is an input variable of the exported configuration, required variables have no default value
+	Variable struct {
+		Name        string
+		Description string
+		Required    bool
+	}
+)
+
+// FileName is the name of generated README file
+const FileName = "README.md"
+
+var (
+	//go:embed templates/*
+	templateFiles embed.FS
+
+	// ErrReadingConfiguration is returned when exported configuration cannot be read or parsed
+	ErrReadingConfiguration = exitcode.New(exitcode.Template, "unable to read exported configuration")
+	// ErrInvalidTemplate is returned when README template cannot be read, parsed or executed
+	ErrInvalidTemplate = exitcode.New(exitcode.Template, "invalid README template")
+)
+
+// LoadTemplate returns README template read from the file, the default template is returned when path is empty
+func LoadTemplate(path string) (*template.Template, error) {
+	name := "readme.tmpl"
+	var content []byte
+	var err error
+	if path == "" {
+		content, err = templateFiles.ReadFile("templates/" + name)
+	} else {
+		name = filepath.Base(path)
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+	tmpl, err := template.New(name).Funcs(templates.Funcs()).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// Generate completes the data with resources, modules, variables and import scripts of the configuration in dir and
+// writes README.md rendered with the template into dir using the sink
+func Generate(sink templates.OutputSink, tmpl *template.Template, dir string, data Data) error {
+	if err := readConfiguration(dir, &data); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+	if err := sink.WriteFile(filepath.Join(dir, FileName), tools.ApplyLineEndings(buf.Bytes())); err != nil {
+		return fmt.Errorf("%w: %s", templates.ErrSavingFiles, err)
+	}
+	return nil
+}
+
+// readConfiguration fills in blocks declared in .tf files and import scripts placed directly in dir
+func readConfiguration(dir string, data *Data) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrReadingConfiguration, err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%w: no configuration files found in '%s'", ErrReadingConfiguration, dir)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrReadingConfiguration, err)
+		}
+		f, diags := hclsyntax.ParseConfig(content, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("%w: %s", ErrReadingConfiguration, diags.Error())
+		}
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			switch {
+			case block.Type == "resource" && len(block.Labels) == 2:
+				data.Resources = append(data.Resources, Resource{Type: block.Labels[0], Name: block.Labels[1], File: filepath.Base(file)})
+			case block.Type == "module" && len(block.Labels) == 1:
+				data.Modules = append(data.Modules, Module{Name: block.Labels[0], Source: stringAttribute(block, "source")})
+			case block.Type == "variable" && len(block.Labels) == 1:
+				_, hasDefault := block.Body.Attributes["default"]
+				data.Variables = append(data.Variables, Variable{
+					Name:        block.Labels[0],
+					Description: stringAttribute(block, "description"),
+					Required:    !hasDefault,
+				})
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrReadingConfiguration, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if !entry.IsDir() && strings.Contains(name, "import") && (ext == ".sh" || ext == ".script") {
+			data.ImportScripts = append(data.ImportScripts, name)
+		}
+	}
+	return nil
+}
+
+// stringAttribute returns value of the string attribute of the block, empty string is returned when it is missing or
+// is not a literal string
+func stringAttribute(block *hclsyntax.Block, name string) string {
+	attr, ok := block.Body.Attributes[name]
+	if !ok {
+		return ""
+	}
+	var value string
+	if diags := gohcl.DecodeExpression(attr.Expr, nil, &value); diags.HasErrors() {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,77 @@
+package readme
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		dir          string
+		template     string
+		warnings     []string
+		expectedFile string
+		withError    error
+	}{
+		"resources, variables and import script": {
+			dir:          "./testdata/export",
+			expectedFile: "./testdata/res/export.md",
+		},
+		"known limitations": {
+			dir:          "./testdata/empty",
+			warnings:     []string{"property 'test': rule 'default' uses advanced behavior, which cannot be modified"},
+			expectedFile: "./testdata/res/empty.md",
+		},
+		"custom template": {
+			dir:          "./testdata/export",
+			template:     "./testdata/custom.tmpl",
+			expectedFile: "./testdata/res/custom.md",
+		},
+		"invalid configuration": {
+			dir:       "./testdata/invalid",
+			withError: ErrReadingConfiguration,
+		},
+		"no configuration": {
+			dir:       "./testdata/res",
+			withError: ErrReadingConfiguration,
+		},
+		"template failing to execute": {
+			dir:       "./testdata/export",
+			template:  "./testdata/invalid.tmpl",
+			withError: ErrInvalidTemplate,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := LoadTemplate(test.template)
+			require.NoError(t, err)
+			sink := templates.NewMemorySink()
+
+			err = Generate(sink, tmpl, test.dir, Data{Command: "export-property", Object: "test", Warnings: test.warnings})
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				assert.Empty(t, sink.Files())
+				return
+			}
+			require.NoError(t, err)
+			expected, err := os.ReadFile(test.expectedFile)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(sink.Files()[filepath.Join(test.dir, FileName)]))
+		})
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	_, err := LoadTemplate("./testdata/missing.tmpl")
+	assert.True(t, errors.Is(err, ErrInvalidTemplate), "expected: %s; got: %s", ErrInvalidTemplate, err)
+
+	_, err = LoadTemplate("./testdata/unparsable.tmpl")
+	assert.True(t, errors.Is(err, ErrInvalidTemplate), "expected: %s; got: %s", ErrInvalidTemplate, err)
+}
@@ -0,0 +1,4 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
@@ -0,0 +1,2 @@
+terraform init
+terraform import akamai_property.test prp_1
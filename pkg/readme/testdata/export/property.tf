@@ -0,0 +1,28 @@
+terraform {
+  required_providers {
+    akamai = {
+      source = "akamai/akamai"
+    }
+  }
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+resource "akamai_edge_hostname" "test-edgesuite-net" {
+  contract_id   = var.contract_id
+  group_id      = var.group_id
+  edge_hostname = "test.edgesuite.net"
+}
+
+resource "akamai_property" "test" {
+  name        = "test"
+  contract_id = var.contract_id
+  group_id    = var.group_id
+}
+
+module "rules" {
+  source = "./modules/rules"
+}
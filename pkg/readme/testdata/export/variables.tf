@@ -0,0 +1,18 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type    = string
+  default = "default"
+}
+
+variable "contract_id" {
+  type        = string
+  description = "ID of the contract of the property"
+}
+
+variable "group_id" {
+  type = string
+}
@@ -0,0 +1,321 @@
+// Package references contains code for replacing literal identifiers of exported objects with Terraform references
+package references
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/urfave/cli/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type (
+	// Resolved describes a literal identifier replaced with a reference
+	Resolved struct {
+		File      string
+		Address   string
+		Attribute string
+		Reference string
+	}
+
+	// rule describes an attribute holding literal identifiers of objects of another type
+	rule struct {
+		sourceType      string
+		sourceAttribute string
+		targetType      string
+		targetAttribute string
+		// normalize converts literal from the source attribute to the value of the target attribute
+		normalize func(string) string
+		// reference formats expression referring to the target, given its address
+		reference string
+		// dataSource is used to refer to targets exported into another directory, references are resolved only within a directory when empty
+		dataSource string
+	}
+
+	target struct {
+		dir  string
+		name string
+	}
+
+	dataSource struct {
+		typ       string
+		name      string
+		attribute string
+		value     string
+	}
+
+	tfFile struct {
+		path    string
+		dir     string
+		hcl     *hclwrite.File
+		changed bool
+	}
+)
+
+// FileName is the name of the file to which data sources referring to objects exported into other directories are written
+const FileName = "references.tf"
+
+var (
+	// rules list resolved relations; CP codes of properties and datacenters of GTM properties are not listed, as exports
+	// already refer to them with akamai_cp_code data sources and akamai_gtm_datacenter resources
+	rules = []rule{
+		{
+			sourceType:      "akamai_cloudlets_policy_activation",
+			sourceAttribute: "associated_properties",
+			targetType:      "akamai_property",
+			targetAttribute: "name",
+			normalize:       func(s string) string { return s },
+			reference:       "%s.name",
+			dataSource:      "akamai_property",
+		},
+		{
+			sourceType:      "akamai_dns_record",
+			sourceAttribute: "target",
+			targetType:      "akamai_edge_hostname",
+			targetAttribute: "edge_hostname",
+			normalize:       func(s string) string { return strings.TrimSuffix(s, ".") },
+			reference:       `"${%s.edge_hostname}."`,
+		},
+	}
+
+	// ErrResolvingReferences is returned when configuration cannot be read, parsed or saved
+	ErrResolvingReferences = exitcode.New(exitcode.Template, "unable to resolve references")
+)
+
+// CmdResolveReferences is an entrypoint to resolve-references command
+func CmdResolveReferences(c *cli.Context) error {
+	term := terminal.Get(c.Context)
+	resolved, err := Resolve(c.Args().First())
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error resolving references: %s", err)), exitcode.Of(err))
+	}
+	for _, r := range resolved {
+		term.Printf("%s: %s.%s -> %s\n", r.File, r.Address, r.Attribute, r.Reference)
+	}
+	term.Printf("Resolved %d reference(s)\n", len(resolved))
+	return nil
+}
+
+// Resolve replaces literal identifiers of objects exported into root or any of its subdirectories with references to them.
+// Objects exported into the same directory are referred to directly, objects exported into another directory are referred to
+// with data sources written to references.tf, if a data source is available for them.
+func Resolve(root string) ([]Resolved, error) {
+	files, err := load(root)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]map[string]target)
+	for _, f := range files {
+		for _, block := range f.hcl.Body().Blocks() {
+			labels := block.Labels()
+			if block.Type() != "resource" || len(labels) != 2 {
+				continue
+			}
+			for _, r := range rules {
+				if r.targetType != labels[0] {
+					continue
+				}
+				values, _ := literals(block.Body().GetAttribute(r.targetAttribute))
+				if len(values) != 1 {
+					continue
+				}
+				key := r.targetType + "." + r.targetAttribute
+				if targets[key] == nil {
+					targets[key] = make(map[string]target)
+				}
+				if _, ok := targets[key][values[0]]; !ok {
+					targets[key][values[0]] = target{dir: f.dir, name: labels[1]}
+				}
+			}
+		}
+	}
+
+	var resolved []Resolved
+	dataSources := make(map[string]map[string]dataSource)
+	for _, f := range files {
+		for _, block := range f.hcl.Body().Blocks() {
+			labels := block.Labels()
+			if block.Type() != "resource" || len(labels) != 2 {
+				continue
+			}
+			for _, r := range rules {
+				if r.sourceType != labels[0] {
+					continue
+				}
+				attr := block.Body().GetAttribute(r.sourceAttribute)
+				values, isList := literals(attr)
+				if len(values) == 0 {
+					continue
+				}
+				changed := false
+				elements := make([]string, 0, len(values))
+				for _, v := range values {
+					element := string(hclwrite.TokensForValue(cty.StringVal(v)).Bytes())
+					if t, ok := targets[r.targetType+"."+r.targetAttribute][r.normalize(v)]; ok && (t.dir == f.dir || r.dataSource != "") {
+						address := r.targetType + "." + t.name
+						if t.dir != f.dir {
+							if dataSources[f.dir] == nil {
+								dataSources[f.dir] = make(map[string]dataSource)
+							}
+							dataSources[f.dir][t.name] = dataSource{typ: r.dataSource, name: t.name, attribute: r.targetAttribute, value: element}
+							address = "data." + r.dataSource + "." + t.name
+						}
+						element = fmt.Sprintf(r.reference, address)
+						changed = true
+						resolved = append(resolved, Resolved{File: f.path, Address: labels[0] + "." + labels[1], Attribute: r.sourceAttribute, Reference: element})
+					}
+					elements = append(elements, element)
+				}
+				if !changed {
+					continue
+				}
+				expression := elements[0]
+				if isList {
+					expression = "[" + strings.Join(elements, ", ") + "]"
+				}
+				tokens, err := parseExpression(expression)
+				if err != nil {
+					return nil, err
+				}
+				block.Body().SetAttributeRaw(r.sourceAttribute, tokens)
+				f.changed = true
+			}
+		}
+	}
+
+	for _, f := range files {
+		if !f.changed {
+			continue
+		}
+		if err := os.WriteFile(f.path, hclwrite.Format(f.hcl.Bytes()), 0644); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrResolvingReferences, err)
+		}
+	}
+	if err := writeDataSources(dataSources); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
+
+// load parses all .tf files in root and its subdirectories
+func load(root string) ([]*tfFile, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != root && workspace.SkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".tf" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrResolvingReferences, err)
+	}
+	sort.Strings(paths)
+
+	files := make([]*tfFile, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrResolvingReferences, err)
+		}
+		f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%w: %s", ErrResolvingReferences, diags.Error())
+		}
+		files = append(files, &tfFile{path: path, dir: filepath.Dir(path), hcl: f})
+	}
+	return files, nil
+}
+
+// literals returns string values of the attribute, if it is a string literal or a list of string literals
+func literals(attr *hclwrite.Attribute) ([]string, bool) {
+	if attr == nil {
+		return nil, false
+	}
+	expr, diags := hclsyntax.ParseExpression(attr.Expr().BuildTokens(nil).Bytes(), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() {
+		return nil, false
+	}
+	if val.Type() == cty.String {
+		return []string{val.AsString()}, false
+	}
+	if !val.Type().IsTupleType() && !val.Type().IsListType() {
+		return nil, false
+	}
+	var values []string
+	for it := val.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.IsNull() || v.Type() != cty.String {
+			return nil, false
+		}
+		values = append(values, v.AsString())
+	}
+	return values, true
+}
+
+func parseExpression(expression string) (hclwrite.Tokens, error) {
+	f, diags := hclwrite.ParseConfig([]byte("expression = "+expression+"\n"), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%w: %s", ErrResolvingReferences, diags.Error())
+	}
+	return f.Body().GetAttribute("expression").Expr().BuildTokens(nil), nil
+}
+
+// writeDataSources adds data sources which are not yet declared to references.tf in each directory
+func writeDataSources(dataSources map[string]map[string]dataSource) error {
+	for dir, blocks := range dataSources {
+		path := filepath.Join(dir, FileName)
+		content, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrResolvingReferences, err)
+		}
+		f, diags := hclwrite.ParseConfig(content, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("%w: %s", ErrResolvingReferences, diags.Error())
+		}
+		names := make([]string, 0, len(blocks))
+		for name := range blocks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ds := blocks[name]
+			if f.Body().FirstMatchingBlock("data", []string{ds.typ, ds.name}) != nil {
+				continue
+			}
+			if len(f.Body().Blocks()) > 0 {
+				f.Body().AppendNewline()
+			}
+			tokens, err := parseExpression(ds.value)
+			if err != nil {
+				return err
+			}
+			f.Body().AppendNewBlock("data", []string{ds.typ, ds.name}).Body().SetAttributeRaw(ds.attribute, tokens)
+		}
+		if err := os.WriteFile(path, hclwrite.Format(f.Bytes()), 0644); err != nil {
+			return fmt.Errorf("%w: %s", ErrResolvingReferences, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package references
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	dir := copyDir(t, "./testdata/workspace")
+
+	resolved, err := Resolve(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Resolved{
+		{
+			File:      filepath.Join(dir, "cloudlets", "policy-activation.tf"),
+			Address:   "akamai_cloudlets_policy_activation.policy_activation",
+			Attribute: "associated_properties",
+			Reference: "data.akamai_property.example-com.name",
+		},
+		{
+			File:      filepath.Join(dir, "site", "dns.tf"),
+			Address:   "akamai_dns_record.example_com_www_CNAME",
+			Attribute: "target",
+			Reference: `"${akamai_edge_hostname.example-com-edgesuite-net.edge_hostname}."`,
+		},
+	}, resolved)
+	testutils.AssertFiles(t, "./testdata/expected", dir,
+		"cloudlets/policy-activation.tf", "cloudlets/references.tf", "dns/example_com.tf", "site/dns.tf", "site/property.tf")
+	assert.NoFileExists(t, filepath.Join(dir, "dns", FileName))
+
+	// references which are already resolved are left untouched
+	resolved, err = Resolve(dir)
+	require.NoError(t, err)
+	assert.Empty(t, resolved)
+	testutils.AssertFiles(t, "./testdata/expected", dir, "cloudlets/policy-activation.tf", "cloudlets/references.tf", "site/dns.tf")
+}
+
+func TestResolveErrors(t *testing.T) {
+	for name, dir := range map[string]string{
+		"invalid configuration": "./testdata/invalid",
+		"missing directory":     "./testdata/missing",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Resolve(dir)
+			assert.True(t, errors.Is(err, ErrResolvingReferences), "expected: %s; got: %s", ErrResolvingReferences, err)
+		})
+	}
+}
+
+func copyDir(t *testing.T, src string) string {
+	dst := t.TempDir()
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(dst, filepath.Dir(rel)), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), content, 0644)
+	})
+	require.NoError(t, err)
+	return dst
+}
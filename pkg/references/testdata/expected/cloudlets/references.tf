@@ -0,0 +1,3 @@
+data "akamai_property" "example-com" {
+  name = "example.com"
+}
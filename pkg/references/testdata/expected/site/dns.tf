@@ -0,0 +1,15 @@
+resource "akamai_dns_record" "example_com_www_CNAME" {
+  zone       = local.zone
+  name       = "www.example.com"
+  recordtype = "CNAME"
+  target     = ["${akamai_edge_hostname.example-com-edgesuite-net.edge_hostname}."]
+  ttl        = 300
+}
+
+resource "akamai_dns_record" "example_com_api_CNAME" {
+  zone       = local.zone
+  name       = "api.example.com"
+  recordtype = "CNAME"
+  target     = ["api.example.net."]
+  ttl        = 300
+}
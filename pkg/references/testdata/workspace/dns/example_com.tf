@@ -0,0 +1,7 @@
+resource "akamai_dns_record" "example_com_static_CNAME" {
+  zone       = local.zone
+  name       = "static.example.com"
+  recordtype = "CNAME"
+  target     = ["example.com.edgesuite.net."]
+  ttl        = 300
+}
@@ -0,0 +1,21 @@
+resource "akamai_edge_hostname" "example-com-edgesuite-net" {
+  product_id    = "prd_SPM"
+  contract_id   = data.akamai_contract.contract.id
+  group_id      = data.akamai_group.group.id
+  ip_behavior   = "IPV4"
+  edge_hostname = "example.com.edgesuite.net"
+}
+
+resource "akamai_property" "example-com" {
+  name        = "example.com"
+  contract_id = data.akamai_contract.contract.id
+  group_id    = data.akamai_group.group.id
+  product_id  = "prd_SPM"
+  rule_format = "latest"
+  hostnames {
+    cname_from             = "www.example.com"
+    cname_to               = akamai_edge_hostname.example-com-edgesuite-net.edge_hostname
+    cert_provisioning_type = "CPS_MANAGED"
+  }
+  rules = data.akamai_property_rules_template.rules.json
+}
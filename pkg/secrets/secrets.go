@@ -0,0 +1,183 @@
+// Package secrets contains code for detecting secrets in generated content before it is written to disk
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+type (
+	// Finding is a single secret detected in generated content
+	Finding struct {
+		File string
+		Line int
+		Name string
+	}
+
+	// Writer writes declarations of variables for redacted secrets, e.g. the output sink of the export
+	Writer interface {
+		WriteFile(path string, content []byte) error
+	}
+
+	// Error is returned when secrets are found and the scan mode is ModeFail, it lists all findings without their values
+	Error struct {
+		Findings []Finding
+	}
+)
+
+const (
+	// ModeOff disables the scan
+	ModeOff = ""
+	// ModeFail makes the export fail when a secret is found
+	ModeFail = "fail"
+	// ModeRedact replaces secrets in Terraform files with references to sensitive variables
+	ModeRedact = "redact"
+
+	// VariablesFile is the name of the file in which variables for redacted secrets are declared
+	VariablesFile = "secrets.tf"
+
+	// Redacted replaces secrets in JSON files, which cannot reference variables
+	Redacted = "<redacted>"
+)
+
+var (
+	// ErrSecretsFound is returned when secrets are found in generated content
+	ErrSecretsFound = exitcode.New(exitcode.Template, "secrets found in generated content")
+	// ErrInvalidMode is returned for unknown scan mode
+	ErrInvalidMode = exitcode.New(exitcode.General, "invalid secrets scan mode")
+	// ErrSavingVariables is returned when variables for redacted secrets cannot be written
+	ErrSavingVariables = exitcode.New(exitcode.IO, "saving variables for redacted secrets")
+
+	// hclSecret matches string literal assigned to an attribute with a name of a credential, e.g. TSIG key secret or GTM test object password
+	hclSecret = regexp.MustCompile(`(?m)^([ \t]*)([a-z0-9_]*(?:secret|password|token|private_key|access_key|api_key))([ \t]*=[ \t]*)"((?:[^"\\\n]|\\.)+)"`)
+	// jsonSecret matches string value of a key with a name of a credential
+	jsonSecret = regexp.MustCompile(`"([A-Za-z0-9]*(?:[Ss]ecret|[Pp]assword|[Tt]oken|[Pp]rivateKey|[Aa]ccessKey|[Aa]piKey))"([ \t]*:[ \t]*)"((?:[^"\\\n]|\\.)+)"`)
+
+	declaredVariable = regexp.MustCompile(`(?m)^variable "([^"]+)"`)
+	nonAlphanumeric  = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+func (e *Error) Error() string {
+	lines := make([]string, 0, len(e.Findings))
+	for _, f := range e.Findings {
+		lines = append(lines, fmt.Sprintf("  %s:%d: '%s'", f.File, f.Line, f.Name))
+	}
+	return fmt.Sprintf("%s, use --scan-secrets=redact to replace them with variables:\n%s", ErrSecretsFound, strings.Join(lines, "\n"))
+}
+
+// Unwrap returns ErrSecretsFound, so that the error carries its exit code
+func (e *Error) Unwrap() error {
+	return ErrSecretsFound
+}
+
+// ValidateMode returns an error if mode is not one of supported scan modes
+func ValidateMode(mode string) error {
+	switch mode {
+	case ModeOff, ModeFail, ModeRedact:
+		return nil
+	}
+	return fmt.Errorf("%w: '%s', expected '%s' or '%s'", ErrInvalidMode, mode, ModeFail, ModeRedact)
+}
+
+// Filter scans content which is going to be written to path, using the mode set with tools.ScanSecrets.
+// In ModeFail an *Error listing all findings is returned. In ModeRedact secrets in .tf files are replaced
+// with references to sensitive variables declared in secrets.tf next to path, which is written with w, while secrets in other files are replaced with a placeholder.
+func Filter(w Writer, path string, content []byte) ([]byte, error) {
+	switch tools.ScanSecrets {
+	case ModeOff:
+		return content, nil
+	case ModeFail:
+		if findings := Scan(path, content); len(findings) > 0 {
+			return nil, &Error{Findings: findings}
+		}
+		return content, nil
+	case ModeRedact:
+		if filepath.Ext(path) == ".tf" {
+			return redactHCL(w, path, content)
+		}
+		return jsonSecret.ReplaceAll(content, []byte(`"$1"$2"`+Redacted+`"`)), nil
+	}
+	return nil, ValidateMode(tools.ScanSecrets)
+}
+
+// Scan returns all secrets found in content which is going to be written to path
+func Scan(path string, content []byte) []Finding {
+	re, nameGroup := jsonSecret, 1
+	if filepath.Ext(path) == ".tf" {
+		re, nameGroup = hclSecret, 2
+	}
+	var findings []Finding
+	for _, match := range re.FindAllSubmatchIndex(content, -1) {
+		name := string(content[match[2*nameGroup]:match[2*nameGroup+1]])
+		if bytes.Contains(content[match[0]:match[1]], []byte(`"`+Redacted+`"`)) {
+			continue
+		}
+		findings = append(findings, Finding{
+			File: path,
+			Line: bytes.Count(content[:match[0]], []byte("\n")) + 1,
+			Name: name,
+		})
+	}
+	return findings
+}
+
+// redactHCL replaces secrets with variable references and declares the variables in VariablesFile
+func redactHCL(w Writer, path string, content []byte) ([]byte, error) {
+	if !hclSecret.Match(content) {
+		return content, nil
+	}
+	variablesPath := filepath.Join(filepath.Dir(path), VariablesFile)
+	existing, err := os.ReadFile(variablesPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrSavingVariables, err)
+	}
+	declared := make(map[string]bool)
+	for _, match := range declaredVariable.FindAllSubmatch(existing, -1) {
+		declared[string(match[1])] = true
+	}
+
+	prefix := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	var added []string
+	redacted := hclSecret.ReplaceAllFunc(content, func(match []byte) []byte {
+		groups := hclSecret.FindSubmatch(match)
+		name := variableName(prefix, string(groups[2]), declared)
+		declared[name] = true
+		added = append(added, name)
+		return []byte(fmt.Sprintf("%s%s%svar.%s", groups[1], groups[2], groups[3], name))
+	})
+
+	sort.Strings(added)
+	buf := bytes.NewBuffer(existing)
+	for _, name := range added {
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "variable \"%s\" {\n  type      = string\n  sensitive = true\n}\n", name)
+	}
+	if err := w.WriteFile(variablesPath, tools.ApplyLineEndings(buf.Bytes())); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSavingVariables, err)
+	}
+	return redacted, nil
+}
+
+// variableName returns name of a variable for the redacted attribute which is not yet declared
+func variableName(prefix, attribute string, declared map[string]bool) string {
+	base := strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(prefix+"_"+attribute), "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "secret_" + base
+	}
+	base = tools.VariableName(base)
+	name := base
+	for i := 2; declared[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	return name
+}
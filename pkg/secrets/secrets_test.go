@@ -0,0 +1,177 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const zoneConfig = `resource "akamai_dns_zone" "test_com" {
+  zone = local.zone
+  tsig_key {
+    name      = "tsig.test.com"
+    algorithm = "hmac-sha256"
+    secret    = "c2VjcmV0"
+  }
+}
+
+resource "akamai_gtm_property" "test" {
+  liveness_test {
+    test_object_password = "p@ss\"word"
+    test_object_username = "user"
+  }
+}
+`
+
+const ruleJSON = `{
+  "name": "Token auth",
+  "behaviors": [
+    {
+      "name": "verifyTokenAuthorization",
+      "options": {
+        "apiToken": "abc123",
+        "tokenName": "__token__"
+      }
+    }
+  ]
+}`
+
+func TestScan(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		content  string
+		expected []Finding
+	}{
+		"secrets in terraform file": {
+			path:    "test_com.tf",
+			content: zoneConfig,
+			expected: []Finding{
+				{File: "test_com.tf", Line: 6, Name: "secret"},
+				{File: "test_com.tf", Line: 12, Name: "test_object_password"},
+			},
+		},
+		"references and empty values are not secrets": {
+			path:    "variables.tf",
+			content: "secret = var.secret\npassword = \"\"\ntoken_name = \"__token__\"\n",
+		},
+		"secrets in json file": {
+			path:     "rules.json",
+			content:  ruleJSON,
+			expected: []Finding{{File: "rules.json", Line: 7, Name: "apiToken"}},
+		},
+		"redacted json": {
+			path:    "rules.json",
+			content: `{"apiToken": "<redacted>"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Scan(test.path, []byte(test.content)))
+		})
+	}
+}
+
+// dirWriter writes files directly into the filesystem
+type dirWriter struct{}
+
+func (dirWriter) WriteFile(path string, content []byte) error {
+	return os.WriteFile(path, content, 0644)
+}
+
+func TestFilter(t *testing.T) {
+	tests := map[string]struct {
+		mode              string
+		file              string
+		content           string
+		existingVariables string
+		expected          string
+		expectedVariables string
+		withError         error
+	}{
+		"scan disabled": {
+			file:     "test_com.tf",
+			content:  zoneConfig,
+			expected: zoneConfig,
+		},
+		"fail without secrets": {
+			mode:     ModeFail,
+			file:     "test_com.tf",
+			content:  "zone = local.zone\n",
+			expected: "zone = local.zone\n",
+		},
+		"fail with secrets": {
+			mode:      ModeFail,
+			file:      "test_com.tf",
+			content:   zoneConfig,
+			withError: ErrSecretsFound,
+		},
+		"redact terraform file": {
+			mode:              ModeRedact,
+			file:              "test_com.tf",
+			content:           "secret = \"c2VjcmV0\"\n  password = \"pass\"\nname = \"test\"\n",
+			existingVariables: "variable \"test_com_secret\" {\n  type      = string\n  sensitive = true\n}\n",
+			expected:          "secret = var.test_com_secret_2\n  password = var.test_com_password\nname = \"test\"\n",
+			expectedVariables: "variable \"test_com_secret\" {\n  type      = string\n  sensitive = true\n}\n" +
+				"\nvariable \"test_com_password\" {\n  type      = string\n  sensitive = true\n}\n" +
+				"\nvariable \"test_com_secret_2\" {\n  type      = string\n  sensitive = true\n}\n",
+		},
+		"redact json file": {
+			mode:     ModeRedact,
+			file:     "rules.json",
+			content:  `{"apiToken": "abc123", "tokenName": "__token__"}`,
+			expected: `{"apiToken": "<redacted>", "tokenName": "__token__"}`,
+		},
+		"invalid mode": {
+			mode:      "ignore",
+			file:      "test_com.tf",
+			content:   zoneConfig,
+			withError: ErrInvalidMode,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if test.existingVariables != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, VariablesFile), []byte(test.existingVariables), 0644))
+			}
+			tools.ScanSecrets = test.mode
+			defer func() {
+				tools.ScanSecrets = ModeOff
+			}()
+
+			out, err := Filter(dirWriter{}, filepath.Join(dir, test.file), []byte(test.content))
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(out))
+			if test.expectedVariables == "" {
+				assert.NoFileExists(t, filepath.Join(dir, VariablesFile))
+				return
+			}
+			variables, err := os.ReadFile(filepath.Join(dir, VariablesFile))
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedVariables, string(variables))
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	err := &Error{Findings: []Finding{
+		{File: "test_com.tf", Line: 6, Name: "secret"},
+		{File: "rules.json", Line: 7, Name: "apiToken"},
+	}}
+	assert.Equal(t, "secrets found in generated content, use --scan-secrets=redact to replace them with variables:\n"+
+		"  test_com.tf:6: 'secret'\n"+
+		"  rules.json:7: 'apiToken'", err.Error())
+	assert.Equal(t, exitcode.Template, exitcode.Of(err))
+}
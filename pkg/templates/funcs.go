@@ -0,0 +1,87 @@
+package templates
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// Funcs returns helper functions available in all templates, including templates given by users, e.g. README template
+//
+// The functions are:
+//   - comments - comment lines with metadata given with add-comment flag, placed before resource blocks
+//   - deepequal - reports whether two values are deeply equal
+//   - escape - escapes a string to be placed inside of a quoted terraform string literal
+//   - escapeName - makes a string suitable for a terraform resource name
+//   - formatIntList - formats a list of integers as a terraform list, e.g. [1, 2]
+//   - indent - indents all non-empty lines of a string by given number of spaces, e.g. {{ indent 2 .Rules }}
+//   - quoteEscape - escapes a string and wraps it in quotes, making it a terraform string literal
+//   - toJSON, tojson - pretty printed JSON representation of a value
+//   - toList, tolist - comma delimited list of quoted and escaped strings, meant to be placed inside of brackets
+//   - toLower, toUpper - changes case of a string or a value of a string type, e.g. activation network
+//   - trimNewlines - removes leading and trailing line breaks
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"comments":      tools.ResourceComments,
+		"deepequal":     reflect.DeepEqual,
+		"escape":        tools.EscapeQuotedStringLit,
+		"escapeName":    tools.EscapeName,
+		"formatIntList": formatIntList,
+		"indent":        indent,
+		"quoteEscape":   quoteEscape,
+		"toJSON":        tools.ToJSON,
+		"tojson":        tools.ToJSON,
+		"toList":        tools.ToList,
+		"tolist":        tools.ToList,
+		"toLower":       toLower,
+		"toUpper":       toUpper,
+		"trimNewlines":  trimNewlines,
+	}
+}
+
+func formatIntList(items []int) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+	var list []string
+	for _, v := range items {
+		list = append(list, strconv.Itoa(v))
+	}
+	output := strings.Join(list, ", ")
+	return "[" + output + "]"
+}
+
+func indent(spaces int, s string) string {
+	if spaces <= 0 {
+		return s
+	}
+	prefix := strings.Repeat(" ", spaces)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func quoteEscape(s string) string {
+	return `"` + tools.EscapeQuotedStringLit(s) + `"`
+}
+
+// toLower accepts values of any type, so that it can be used with values of string types defined by edgegrid packages
+func toLower(v interface{}) string {
+	return strings.ToLower(fmt.Sprint(v))
+}
+
+func toUpper(v interface{}) string {
+	return strings.ToUpper(fmt.Sprint(v))
+}
+
+func trimNewlines(s string) string {
+	return strings.Trim(s, "\r\n")
+}
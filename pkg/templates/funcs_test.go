@@ -0,0 +1,120 @@
+package templates
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type network string
+
+func TestFormatIntList(t *testing.T) {
+	tests := map[string]struct {
+		data   []int
+		expect string
+	}{
+		"list of ints": {
+			data:   []int{123, 345},
+			expect: "[123, 345]",
+		},
+		"empty list of ints": {
+			data:   []int{},
+			expect: "[]",
+		},
+		"nil list of ints": {
+			data:   nil,
+			expect: "[]",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := formatIntList(test.data)
+			assert.Equal(t, got, test.expect)
+		})
+	}
+}
+
+func TestIndent(t *testing.T) {
+	tests := map[string]struct {
+		spaces int
+		data   string
+		expect string
+	}{
+		"multiple lines": {
+			spaces: 2,
+			data:   "a = 1\nb = {\n  c = 2\n}",
+			expect: "  a = 1\n  b = {\n    c = 2\n  }",
+		},
+		"empty lines are not indented": {
+			spaces: 4,
+			data:   "a\n\nb\n",
+			expect: "    a\n\n    b\n",
+		},
+		"no indentation": {
+			spaces: 0,
+			data:   "a\nb",
+			expect: "a\nb",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expect, indent(test.spaces, test.data))
+		})
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	tests := map[string]struct {
+		template string
+		data     interface{}
+		expect   string
+	}{
+		"quoteEscape": {
+			template: `name = {{ quoteEscape . }}`,
+			data:     "say \"hi\" to ${var}",
+			expect:   `name = "say \"hi\" to $${var}"`,
+		},
+		"tolist": {
+			template: `ids = [{{ tolist . }}]`,
+			data:     []string{"a", "b"},
+			expect:   `ids = ["a", "b"]`,
+		},
+		"tojson": {
+			template: `{{ tojson . }}`,
+			data:     map[string]int{"a": 1},
+			expect:   "{\n    \"a\": 1\n}",
+		},
+		"indented json": {
+			template: "rules = <<EOT\n{{ tojson . | indent 2 }}\nEOT",
+			data:     map[string]int{"a": 1},
+			expect:   "rules = <<EOT\n  {\n      \"a\": 1\n  }\nEOT",
+		},
+		"toLower and toUpper of string type": {
+			template: `{{ toLower . }} {{ toUpper . }}`,
+			data:     network("Staging"),
+			expect:   "staging STAGING",
+		},
+		"trimNewlines": {
+			template: `<{{ trimNewlines . }}>`,
+			data:     "\r\ncert\r\n\n",
+			expect:   "<cert>",
+		},
+		"deepequal": {
+			template: `{{ deepequal . . }}`,
+			data:     []int{1},
+			expect:   "true",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := template.New(name).Funcs(Funcs()).Parse(test.template)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			require.NoError(t, tmpl.Execute(&buf, test.data))
+			assert.Equal(t, test.expect, buf.String())
+		})
+	}
+}
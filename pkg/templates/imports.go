@@ -0,0 +1,147 @@
+package templates
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/compat"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// importDependencies maps resource types to resource types they refer to; resources of the referenced types have to be
+// imported first, otherwise terraform fails to import the referring resource
+var importDependencies = map[string][]string{
+	"akamai_property":                                       {"akamai_property_bootstrap", "akamai_edge_hostname", "akamai_property_include", "akamai_cp_code"},
+	"akamai_property_activation":                            {"akamai_property"},
+	"akamai_property_include_activation":                    {"akamai_property_include"},
+	"akamai_gtm_datacenter":                                 {"akamai_gtm_domain"},
+	"akamai_gtm_resource":                                   {"akamai_gtm_domain", "akamai_gtm_datacenter"},
+	"akamai_gtm_cidrmap":                                    {"akamai_gtm_domain", "akamai_gtm_datacenter"},
+	"akamai_gtm_geomap":                                     {"akamai_gtm_domain", "akamai_gtm_datacenter"},
+	"akamai_gtm_asmap":                                      {"akamai_gtm_domain", "akamai_gtm_datacenter"},
+	"akamai_gtm_property":                                   {"akamai_gtm_domain", "akamai_gtm_datacenter", "akamai_gtm_resource"},
+	"akamai_dns_record":                                     {"akamai_dns_zone"},
+	"akamai_cloudlets_policy":                               {"akamai_cloudlets_application_load_balancer", "akamai_cloudlets_application_load_balancer_activation"},
+	"akamai_cloudlets_policy_activation":                    {"akamai_cloudlets_policy"},
+	"akamai_cloudlets_application_load_balancer_activation": {"akamai_cloudlets_application_load_balancer"},
+	"akamai_cps_upload_certificate":                         {"akamai_cps_third_party_enrollment"},
+	"akamai_imaging_policy_image":                           {"akamai_imaging_policy_set"},
+	"akamai_imaging_policy_video":                           {"akamai_imaging_policy_set"},
+	"akamai_iam_user":                                       {"akamai_iam_group", "akamai_iam_role"},
+	"akamai_edgeworkers_activation":                         {"akamai_edgeworker"},
+	"akamai_mtlstruststore_ca_set_activation":               {"akamai_mtlstruststore_ca_set"},
+	"akamai_appsec_security_policy":                         {"akamai_appsec_configuration"},
+	"akamai_appsec_custom_rule":                             {"akamai_appsec_configuration"},
+	"akamai_appsec_custom_deny":                             {"akamai_appsec_configuration"},
+	"akamai_appsec_rate_policy":                             {"akamai_appsec_configuration"},
+	"akamai_appsec_reputation_profile":                      {"akamai_appsec_configuration"},
+	"akamai_appsec_malware_policy":                          {"akamai_appsec_configuration"},
+	"akamai_appsec_siem_settings":                           {"akamai_appsec_security_policy"},
+	"akamai_appsec_selected_hostnames":                      {"akamai_appsec_configuration"},
+	"akamai_appsec_advanced_settings_logging":               {"akamai_appsec_security_policy"},
+	"akamai_appsec_advanced_settings_prefetch":              {"akamai_appsec_configuration"},
+	"akamai_appsec_advanced_settings_pragma_header":         {"akamai_appsec_security_policy"},
+	"akamai_appsec_advanced_settings_evasive_path_match":    {"akamai_appsec_security_policy"},
+	"akamai_appsec_match_target":                            {"akamai_appsec_security_policy"},
+	"akamai_appsec_waf_protection":                          {"akamai_appsec_security_policy"},
+	"akamai_appsec_api_constraints_protection":              {"akamai_appsec_security_policy"},
+	"akamai_appsec_ip_geo_protection":                       {"akamai_appsec_security_policy"},
+	"akamai_appsec_malware_protection":                      {"akamai_appsec_security_policy"},
+	"akamai_appsec_rate_protection":                         {"akamai_appsec_security_policy"},
+	"akamai_appsec_reputation_protection":                   {"akamai_appsec_security_policy"},
+	"akamai_appsec_slowpost_protection":                     {"akamai_appsec_security_policy"},
+	"akamai_appsec_waf_mode":                                {"akamai_appsec_security_policy"},
+	"akamai_appsec_rule":                                    {"akamai_appsec_waf_mode"},
+	"akamai_appsec_attack_group":                            {"akamai_appsec_waf_mode"},
+	"akamai_appsec_custom_rule_action":                      {"akamai_appsec_security_policy", "akamai_appsec_custom_rule"},
+	"akamai_appsec_api_request_constraints":                 {"akamai_appsec_security_policy"},
+	"akamai_appsec_reputation_profile_action":               {"akamai_appsec_security_policy", "akamai_appsec_reputation_profile"},
+	"akamai_appsec_rate_policy_action":                      {"akamai_appsec_security_policy", "akamai_appsec_rate_policy"},
+	"akamai_appsec_malware_policy_action":                   {"akamai_appsec_security_policy", "akamai_appsec_malware_policy"},
+	"akamai_appsec_ip_geo":                                  {"akamai_appsec_security_policy"},
+	"akamai_appsec_penalty_box":                             {"akamai_appsec_security_policy"},
+	"akamai_appsec_slow_post":                               {"akamai_appsec_security_policy"},
+	"akamai_appsec_activations":                             {"akamai_appsec_configuration"},
+}
+
+// isImportScript returns true if the file at targetPath is a script of terraform import commands
+func isImportScript(targetPath string) bool {
+	return strings.HasSuffix(targetPath, "import.sh")
+}
+
+// ValidateImports checks IDs of resources imported by the script against import ID formats documented for the provider
+// version given with check-provider-compat flag, if validate-import-ids flag is set
+func ValidateImports(script []byte) error {
+	if !tools.ValidateImportIDs {
+		return nil
+	}
+	return compat.ValidateImportScript(script, tools.ProviderVersion)
+}
+
+// OrderImports reorders 'terraform import' commands of the script so that resources are imported after resources they
+// depend on. Other lines, such as 'terraform init', keep their positions and the order of imports of resources which do
+// not depend on each other is preserved.
+func OrderImports(script []byte) []byte {
+	lines := bytes.Split(script, []byte("\n"))
+	var positions []int
+	var imports [][]byte
+	for i, line := range lines {
+		if importedType(string(line)) != "" {
+			positions = append(positions, i)
+			imports = append(imports, line)
+		}
+	}
+	depths := make(map[string]int)
+	sort.SliceStable(imports, func(i, j int) bool {
+		return importDepth(importedType(string(imports[i])), depths, nil) < importDepth(importedType(string(imports[j])), depths, nil)
+	})
+	for i, pos := range positions {
+		lines[pos] = imports[i]
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// importedType returns the type of the resource imported by the line, or an empty string if the line is not an import
+func importedType(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[0] != "terraform" || fields[1] != "import" {
+		return ""
+	}
+	for _, address := range fields[2:] {
+		if strings.HasPrefix(address, "-") {
+			continue
+		}
+		// addresses of instances with string keys are quoted in shell
+		parts := strings.Split(strings.TrimLeft(address, `'"`), ".")
+		for len(parts) > 2 && parts[0] == "module" {
+			parts = parts[2:]
+		}
+		return parts[0]
+	}
+	return ""
+}
+
+// importDepth returns the length of the longest chain of dependencies of the resource type,
+// resource types which are part of a dependency cycle are not followed again
+func importDepth(resourceType string, depths map[string]int, visiting map[string]bool) int {
+	if depth, ok := depths[resourceType]; ok {
+		return depth
+	}
+	if visiting == nil {
+		visiting = make(map[string]bool)
+	}
+	visiting[resourceType] = true
+	depth := 0
+	for _, dependency := range importDependencies[resourceType] {
+		if visiting[dependency] {
+			continue
+		}
+		if d := importDepth(dependency, depths, visiting) + 1; d > depth {
+			depth = d
+		}
+	}
+	delete(visiting, resourceType)
+	depths[resourceType] = depth
+	return depth
+}
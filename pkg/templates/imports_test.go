@@ -0,0 +1,125 @@
+package templates
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/compat"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderImports(t *testing.T) {
+	tests := map[string]struct {
+		script []string
+		expect []string
+	}{
+		"dependencies are imported first": {
+			script: []string{
+				"terraform init",
+				"terraform import akamai_property.p prp_1,ctr_1,grp_1,1",
+				"terraform import akamai_property_include.i inc_1,ctr_1,grp_1",
+				"terraform import akamai_edge_hostname.e ehn_1,ctr_1,grp_1",
+			},
+			expect: []string{
+				"terraform init",
+				"terraform import akamai_property_include.i inc_1,ctr_1,grp_1",
+				"terraform import akamai_edge_hostname.e ehn_1,ctr_1,grp_1",
+				"terraform import akamai_property.p prp_1,ctr_1,grp_1,1",
+			},
+		},
+		"quoted addresses of instances": {
+			script: []string{
+				"terraform init",
+				`terraform import 'akamai_dns_record.r["a.example.com"]' example.com#a.example.com#CNAME`,
+				"terraform import akamai_dns_zone.z example.com",
+			},
+			expect: []string{
+				"terraform init",
+				"terraform import akamai_dns_zone.z example.com",
+				`terraform import 'akamai_dns_record.r["a.example.com"]' example.com#a.example.com#CNAME`,
+			},
+		},
+		"chains of dependencies": {
+			script: []string{
+				"terraform init",
+				`terraform import akamai_gtm_property.p "d:p"`,
+				`terraform import akamai_gtm_resource.r "d:r"`,
+				`terraform import akamai_gtm_datacenter.dc "d:1"`,
+				`terraform import akamai_gtm_domain.d "d"`,
+			},
+			expect: []string{
+				"terraform init",
+				`terraform import akamai_gtm_domain.d "d"`,
+				`terraform import akamai_gtm_datacenter.dc "d:1"`,
+				`terraform import akamai_gtm_resource.r "d:r"`,
+				`terraform import akamai_gtm_property.p "d:p"`,
+			},
+		},
+		"cloudlets load balancers are activated before policy": {
+			script: []string{
+				"terraform init",
+				"terraform import akamai_cloudlets_policy_activation.policy_activation 1:staging",
+				"terraform import akamai_cloudlets_policy.policy policy",
+				"terraform import akamai_cloudlets_application_load_balancer_activation.load_balancer_activation_o1 o1,staging",
+				"terraform import akamai_cloudlets_application_load_balancer.load_balancer_o1 o1",
+			},
+			expect: []string{
+				"terraform init",
+				"terraform import akamai_cloudlets_application_load_balancer.load_balancer_o1 o1",
+				"terraform import akamai_cloudlets_application_load_balancer_activation.load_balancer_activation_o1 o1,staging",
+				"terraform import akamai_cloudlets_policy.policy policy",
+				"terraform import akamai_cloudlets_policy_activation.policy_activation 1:staging",
+			},
+		},
+		"resources in modules": {
+			script: []string{
+				"terraform init",
+				"terraform import module.security.akamai_appsec_waf_mode.policy 1:p",
+				"terraform import module.security.akamai_appsec_rule.policy_rule_1 1:p:1",
+				"terraform import module.security.akamai_appsec_configuration.config 1",
+			},
+			expect: []string{
+				"terraform init",
+				"terraform import module.security.akamai_appsec_configuration.config 1",
+				"terraform import module.security.akamai_appsec_waf_mode.policy 1:p",
+				"terraform import module.security.akamai_appsec_rule.policy_rule_1 1:p:1",
+			},
+		},
+		"independent resources and other lines keep their order": {
+			script: []string{
+				"terraform init",
+				"terraform import akamai_iam_role.b 2",
+				"",
+				"terraform import -var=x=1 akamai_iam_user.a 1",
+				"terraform import akamai_iam_group.c 3",
+				"terraform import akamai_unknown.d 4",
+			},
+			expect: []string{
+				"terraform init",
+				"terraform import akamai_iam_role.b 2",
+				"",
+				"terraform import akamai_iam_group.c 3",
+				"terraform import akamai_unknown.d 4",
+				"terraform import -var=x=1 akamai_iam_user.a 1",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := OrderImports([]byte(strings.Join(test.script, "\n")))
+			assert.Equal(t, strings.Join(test.expect, "\n"), string(got))
+		})
+	}
+}
+
+func TestValidateImports(t *testing.T) {
+	script := []byte("terraform init\nterraform import akamai_edgeworker.edgeworker abc")
+	assert.NoError(t, ValidateImports(script))
+
+	tools.ValidateImportIDs = true
+	defer func() { tools.ValidateImportIDs = false }()
+	assert.True(t, errors.Is(ValidateImports(script), compat.ErrInvalidImportID))
+	assert.NoError(t, ValidateImports([]byte("terraform import akamai_edgeworker.edgeworker 1234")))
+}
@@ -0,0 +1,123 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type (
+	// Journal records files and directories created by DirSink or passed to Track, so that partial output of an
+	// interrupted run can be removed
+	Journal struct {
+		mu    sync.Mutex
+		files []string
+		dirs  []string
+		seen  map[string]bool
+	}
+
+	journalCtxType string
+)
+
+var journalCtx journalCtxType = "journal"
+
+// NewJournal returns empty Journal
+func NewJournal() *Journal {
+	return &Journal{seen: make(map[string]bool)}
+}
+
+// WithJournal returns context carrying the journal into which DirSink returned by GetSink records created files
+func WithJournal(ctx context.Context, j *Journal) context.Context {
+	return context.WithValue(ctx, journalCtx, j)
+}
+
+// GetJournal returns journal stored in the context, nil is returned if created files are not recorded
+func GetJournal(ctx context.Context) *Journal {
+	j, _ := ctx.Value(journalCtx).(*Journal)
+	return j
+}
+
+// Track records the file, which the caller writes directly into the filesystem instead of through the output sink, in
+// the journal stored in the context and creates its parent directories; like files written by DirSink, it is removed by
+// Rollback only if it did not exist before
+func Track(ctx context.Context, path string) error {
+	j := GetJournal(ctx)
+	if j == nil {
+		return os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	isNew, err := j.prepare(path)
+	if err != nil {
+		return err
+	}
+	if isNew {
+		j.record(path)
+	}
+	return nil
+}
+
+// prepare creates parent directories of the file and records those which did not exist, it reports whether the file
+// itself is new, files which existed before the run are not recorded and thus never removed
+func (j *Journal) prepare(path string) (bool, error) {
+	dir := filepath.Dir(path)
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil || !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(path)
+	isNew := errors.Is(err, os.ErrNotExist)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	// parents are recorded before their children, so that rollback removes children first
+	for i := len(missing) - 1; i >= 0; i-- {
+		j.dirs = append(j.dirs, missing[i])
+	}
+	return isNew, nil
+}
+
+// record marks the file as created by the run
+func (j *Journal) record(path string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.seen[path] {
+		j.seen[path] = true
+		j.files = append(j.files, path)
+	}
+}
+
+// Rollback removes recorded files and the directories created for them, unless other files were put into them in the
+// meantime; paths of removed files are returned and the journal is emptied
+func (j *Journal) Rollback() ([]string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var removed []string
+	var errs []string
+	for _, path := range j.files {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err.Error())
+			continue
+		}
+		removed = append(removed, path)
+	}
+	for i := len(j.dirs) - 1; i >= 0; i-- {
+		// non-empty directories hold files which were not created by the run
+		_ = os.Remove(j.dirs[i])
+	}
+	j.files, j.dirs, j.seen = nil, nil, make(map[string]bool)
+	if len(errs) > 0 {
+		return removed, errors.New(strings.Join(errs, "; "))
+	}
+	return removed, nil
+}
@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	dir := "./testdata/res/journal"
+	require.NoError(t, os.RemoveAll(dir))
+	existing := filepath.Join(dir, "existing.tf")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+
+	journal := NewJournal()
+	sink := GetSink(WithJournal(context.Background(), journal))
+	created := filepath.Join(dir, "rules", "nested", "rule.json")
+	require.NoError(t, sink.WriteFile(existing, []byte("new")))
+	require.NoError(t, sink.WriteFile(created, []byte("{}")))
+	require.NoError(t, sink.WriteFile(created, []byte("{}")))
+	assert.True(t, IsDirSink(sink))
+
+	removed, err := journal.Rollback()
+	require.NoError(t, err)
+	assert.Equal(t, []string{created}, removed)
+	assert.NoDirExists(t, filepath.Join(dir, "rules"))
+	content, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+
+	removed, err = journal.Rollback()
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestJournalKeepsForeignFiles(t *testing.T) {
+	dir := "./testdata/res/journal_foreign"
+	require.NoError(t, os.RemoveAll(dir))
+
+	journal := NewJournal()
+	sink := DirSink{Journal: journal}
+	created := filepath.Join(dir, "property.tf")
+	require.NoError(t, sink.WriteFile(created, []byte("resource")))
+	foreign := filepath.Join(dir, "notes.md")
+	require.NoError(t, os.WriteFile(foreign, []byte("notes"), 0644))
+
+	removed, err := journal.Rollback()
+	require.NoError(t, err)
+	assert.Equal(t, []string{created}, removed)
+	assert.FileExists(t, foreign)
+}
+
+func TestTrack(t *testing.T) {
+	dir := "./testdata/res/journal_track"
+	require.NoError(t, os.RemoveAll(dir))
+	existing := filepath.Join(dir, "existing.tf")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+
+	journal := NewJournal()
+	ctx := WithJournal(context.Background(), journal)
+	created := filepath.Join(dir, "modules", "zone.tf")
+	for _, path := range []string{existing, created} {
+		require.NoError(t, Track(ctx, path))
+		require.NoError(t, os.WriteFile(path, []byte("new"), 0644))
+	}
+
+	removed, err := journal.Rollback()
+	require.NoError(t, err)
+	assert.Equal(t, []string{created}, removed)
+	assert.NoDirExists(t, filepath.Join(dir, "modules"))
+	assert.FileExists(t, existing)
+
+	untracked := filepath.Join(dir, "untracked", "zone.tf")
+	require.NoError(t, Track(context.Background(), untracked))
+	assert.DirExists(t, filepath.Dir(untracked))
+}
+
+func TestGetJournal(t *testing.T) {
+	assert.Nil(t, GetJournal(context.Background()))
+	assert.Equal(t, DirSink{}, GetSink(context.Background()))
+
+	journal := NewJournal()
+	assert.Equal(t, journal, GetJournal(WithJournal(context.Background(), journal)))
+}
@@ -0,0 +1,114 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+)
+
+type (
+	// OutputTarget describes an exported object and a generated file, it is the data of the output template
+	OutputTarget struct {
+		// Product is the name of the export command without the 'export-' prefix, e.g. 'property'
+		Product string
+		// Name is the name of the exported object, e.g. the property name
+		Name string
+		// File is the name of the generated file, e.g. 'property.tf'
+		File string
+	}
+
+	outputTargetCtxType string
+)
+
+var (
+	outputTargetCtx outputTargetCtxType = "outputTarget"
+
+	// ErrInvalidOutputTemplate is returned when the output template cannot be parsed or it does not produce valid file paths
+	ErrInvalidOutputTemplate = exitcode.New(exitcode.General, "invalid output template")
+
+	unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// NewOutputTarget returns target of the object exported by the command, characters which are not safe in file names are
+// replaced in both the product and the object name
+func NewOutputTarget(command, name string) OutputTarget {
+	product := strings.TrimPrefix(strings.TrimPrefix(command, "export-"), "create-")
+	return OutputTarget{
+		Product: unsafeName.ReplaceAllString(product, "_"),
+		Name:    unsafeName.ReplaceAllString(name, "_"),
+	}
+}
+
+// WithOutputTarget returns context carrying the target of the exported object
+func WithOutputTarget(ctx context.Context, target OutputTarget) context.Context {
+	return context.WithValue(ctx, outputTargetCtx, &target)
+}
+
+// GetOutputTarget returns target of the exported object stored in the context, nil is returned if there is none
+func GetOutputTarget(ctx context.Context) *OutputTarget {
+	target, _ := ctx.Value(outputTargetCtx).(*OutputTarget)
+	return target
+}
+
+// ValidateOutputTemplate checks that the output template renders relative paths which do not leave the target directory,
+// that its directory part does not depend on the file and that its file part does
+func ValidateOutputTemplate(pattern string) error {
+	first, err := renderOutput(pattern, OutputTarget{Product: "product", Name: "name", File: "a.tf"})
+	if err != nil {
+		return err
+	}
+	second, err := renderOutput(pattern, OutputTarget{Product: "product", Name: "name", File: "b.tf"})
+	if err != nil {
+		return err
+	}
+	if path.Dir(first) != path.Dir(second) {
+		return fmt.Errorf("%w: '%s': directory must not depend on {{.File}}", ErrInvalidOutputTemplate, pattern)
+	}
+	if path.Base(first) == path.Base(second) {
+		return fmt.Errorf("%w: '%s': file name must contain {{.File}}", ErrInvalidOutputTemplate, pattern)
+	}
+	return nil
+}
+
+// OutputDir returns the directory, relative to the target directory, into which the object is exported
+func OutputDir(pattern string, target OutputTarget) (string, error) {
+	rendered, err := renderOutput(pattern, target)
+	if err != nil {
+		return "", err
+	}
+	return path.Dir(rendered), nil
+}
+
+// OutputFile returns the name under which the file of the target is written
+func OutputFile(pattern string, target OutputTarget) (string, error) {
+	rendered, err := renderOutput(pattern, target)
+	if err != nil {
+		return "", err
+	}
+	return path.Base(rendered), nil
+}
+
+func renderOutput(pattern string, target OutputTarget) (string, error) {
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidOutputTemplate, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, target); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidOutputTemplate, err)
+	}
+	rendered := path.Clean(strings.ReplaceAll(buf.String(), "\\", "/"))
+	if path.IsAbs(rendered) || rendered == ".." || strings.HasPrefix(rendered, "../") {
+		return "", fmt.Errorf("%w: '%s' renders path '%s' outside of the target directory", ErrInvalidOutputTemplate, pattern, rendered)
+	}
+	if rendered == "." {
+		return "", fmt.Errorf("%w: '%s' renders empty path", ErrInvalidOutputTemplate, pattern)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,135 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOutputTemplate(t *testing.T) {
+	tests := map[string]struct {
+		pattern   string
+		withError error
+	}{
+		"file only": {
+			pattern: "{{.File}}",
+		},
+		"directories per product and object": {
+			pattern: "{{.Product}}/{{.Name}}/{{.File}}",
+		},
+		"prefixed file name": {
+			pattern: "{{.Name}}_{{.File}}",
+		},
+		"directory depends on file": {
+			pattern:   "{{.File}}/main.tf",
+			withError: ErrInvalidOutputTemplate,
+		},
+		"file name without file": {
+			pattern:   "{{.Product}}/{{.Name}}.tf",
+			withError: ErrInvalidOutputTemplate,
+		},
+		"absolute path": {
+			pattern:   "/tmp/{{.File}}",
+			withError: ErrInvalidOutputTemplate,
+		},
+		"path outside of work path": {
+			pattern:   "../{{.File}}",
+			withError: ErrInvalidOutputTemplate,
+		},
+		"unknown field": {
+			pattern:   "{{.Section}}/{{.File}}",
+			withError: ErrInvalidOutputTemplate,
+		},
+		"invalid syntax": {
+			pattern:   "{{.File",
+			withError: ErrInvalidOutputTemplate,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateOutputTemplate(test.pattern)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOutputDirAndFile(t *testing.T) {
+	tests := map[string]struct {
+		pattern    string
+		target     OutputTarget
+		expectDir  string
+		expectFile string
+	}{
+		"file only": {
+			pattern:    "{{.File}}",
+			target:     OutputTarget{Product: "property", Name: "test", File: "property.tf"},
+			expectDir:  ".",
+			expectFile: "property.tf",
+		},
+		"directories per product and object": {
+			pattern:    "{{.Product}}/{{.Name}}/{{.File}}",
+			target:     OutputTarget{Product: "property", Name: "test", File: "property.tf"},
+			expectDir:  "property/test",
+			expectFile: "property.tf",
+		},
+		"prefixed file name": {
+			pattern:    "out/{{.Product}}-{{.File}}",
+			target:     OutputTarget{Product: "gtm", Name: "test.akadns.net", File: "domain.tf"},
+			expectDir:  "out",
+			expectFile: "gtm-domain.tf",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, err := OutputDir(test.pattern, test.target)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectDir, dir)
+			file, err := OutputFile(test.pattern, test.target)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectFile, file)
+		})
+	}
+}
+
+func TestNewOutputTarget(t *testing.T) {
+	tests := map[string]struct {
+		command string
+		name    string
+		expect  OutputTarget
+	}{
+		"export command": {
+			command: "export-property",
+			name:    "test.property",
+			expect:  OutputTarget{Product: "property", Name: "test.property"},
+		},
+		"create command": {
+			command: "create-domain",
+			name:    "test.akadns.net",
+			expect:  OutputTarget{Product: "domain", Name: "test.akadns.net"},
+		},
+		"unsafe characters": {
+			command: "export-cloudlets-policy",
+			name:    "my policy/v2",
+			expect:  OutputTarget{Product: "cloudlets-policy", Name: "my_policy_v2"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expect, NewOutputTarget(test.command, test.name))
+		})
+	}
+}
+
+func TestOutputTargetContext(t *testing.T) {
+	assert.Nil(t, GetOutputTarget(context.Background()))
+
+	ctx := WithOutputTarget(context.Background(), OutputTarget{Product: "property", Name: "test"})
+	assert.Equal(t, &OutputTarget{Product: "property", Name: "test"}, GetOutputTarget(ctx))
+}
@@ -3,16 +3,17 @@ package templates
 
 import (
 	"bytes"
-	"errors"
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
-	"strconv"
-	"strings"
+	"sort"
 	"text/template"
 
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/secrets"
 	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
@@ -30,68 +31,173 @@ type (
 	// as well as a map which stores template names with target files to which the result should be written
 	// All templates within TemplatesFS should have .tmpl extension
 	// AdditionalFuncs can be used to add custom template functions
+	// Output is the exported object, when set, names of target files are mapped with the output template
+	// Sink receives generated files, they are written into the filesystem when it is nil
 	FSTemplateProcessor struct {
 		TemplatesFS     fs.FS
 		TemplateTargets map[string]string
 		AdditionalFuncs template.FuncMap
+		Output          *OutputTarget
+		Sink            OutputSink
 	}
 )
 
 var (
 	// ErrTemplateExecution is returned when template.Execute method fails
-	ErrTemplateExecution = errors.New("executing template")
+	ErrTemplateExecution = exitcode.New(exitcode.Template, "executing template")
 	// ErrSavingFiles is returned when an issue with processing templates occurs
-	ErrSavingFiles = errors.New("saving processed terraform file")
+	ErrSavingFiles = exitcode.New(exitcode.IO, "saving processed terraform file")
 )
 
 // ProcessTemplates parses templates located in fs.FS and executes them using the provided data
 // result of each template execution is persisted in location provided in FSTemplateProcessor.TemplateTargets
 func (t FSTemplateProcessor) ProcessTemplates(data interface{}) error {
-	funcs := template.FuncMap{
-		"escape":        tools.EscapeQuotedStringLit,
-		"formatIntList": formatIntList,
-		"toJSON":        tools.ToJSON,
-		"escapeName":    tools.EscapeName,
-		"toList":        tools.ToList,
-	}
 	files, err := findTemplateFiles(t.TemplatesFS)
 	if err != nil {
 		return fmt.Errorf("%s: %s", "error filtering template files", err)
 	}
 
-	tmpl := template.Must(template.New("templates").Funcs(funcs).Funcs(t.AdditionalFuncs).
+	tmpl := template.Must(template.New("templates").Funcs(Funcs()).Funcs(t.AdditionalFuncs).
 		ParseFS(t.TemplatesFS, files...))
 
-	for templateName, targetPath := range t.TemplateTargets {
-		buf := bytes.Buffer{}
+	names := make([]string, 0, len(t.TemplateTargets))
+	for name := range t.TemplateTargets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-		if err := tmpl.Lookup(templateName).Execute(&buf, data); err != nil {
-			return fmt.Errorf("%w: %s: %s", ErrTemplateExecution, templateName, err)
+	// templates are executed concurrently; errors are reported and files are formatted and written in order of template
+	// names once all templates are executed, so that the output does not depend on scheduling; hclwrite keeps state of
+	// formatting in package variables, so it cannot run concurrently
+	outputs := make([]rendered, len(names))
+	errs := make([]error, len(names))
+	_ = tools.RunConcurrently(context.Background(), len(names), func(_ context.Context, i int) error {
+		threshold := 0
+		if streamable(t.TemplateTargets[names[i]]) {
+			threshold = streamThreshold
+		}
+		outputs[i], errs[i] = render(tmpl, names[i], data, threshold)
+		return errs[i]
+	})
+	defer func() {
+		for _, out := range outputs {
+			if out.file != "" {
+				_ = os.Remove(out.file)
+			}
+		}
+	}()
+	for _, err := range errs {
+		if err != nil {
+			return err
 		}
-		out := buf.Bytes()
-		if len(bytes.TrimSpace(out)) == 0 {
+	}
+	// import IDs are validated before any file is written
+	for i, name := range names {
+		if outputs[i].content != nil && isImportScript(t.TemplateTargets[name]) {
+			if err := ValidateImports(outputs[i].content); err != nil {
+				return err
+			}
+		}
+	}
+	// configuration is validated against the provider schema before any file is written as well
+	if tools.ValidateSchema {
+		for i, name := range names {
+			if filepath.Ext(t.TemplateTargets[name]) != ".tf" {
+				continue
+			}
+			if err := ValidateSchema(outputs[i].content, t.TemplateTargets[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, name := range names {
+		targetPath := t.TemplateTargets[name]
+		if outputs[i].file != "" {
+			// output too large to be kept in memory is streamed into the sink as it is
+			outputPath, err := t.outputPath(targetPath)
+			if err != nil {
+				return err
+			}
+			if err := streamFile(t.sink(), outputPath, outputs[i].file); err != nil {
+				return fmt.Errorf("%w: '%s': %s", ErrSavingFiles, outputPath, err)
+			}
 			continue
 		}
-		if filepath.Ext(targetPath) == ".tf" {
+		out := outputs[i].content
+		if out == nil {
+			continue
+		}
+		if ext := filepath.Ext(targetPath); ext == ".tf" || ext == ".tfvars" || ext == ".hcl" {
 			out = hclwrite.Format(out)
 		}
-		if err := os.WriteFile(targetPath, out, 0644); err != nil {
-			return fmt.Errorf("%w: '%s': %s", ErrSavingFiles, targetPath, err)
+		if filepath.Ext(targetPath) == ".tf" {
+			out = RenameVariables(out)
+		}
+		if isVariableDefinitions(targetPath) {
+			out = RenameVariableValues(out)
+		}
+		if isImportScript(targetPath) {
+			out = OrderImports(out)
+		}
+		outputPath, err := t.outputPath(targetPath)
+		if err != nil {
+			return err
+		}
+		out, err = secrets.Filter(t.sink(), outputPath, out)
+		if err != nil {
+			return err
+		}
+		if err := t.sink().WriteFile(outputPath, tools.ApplyLineEndings(out)); err != nil {
+			return fmt.Errorf("%w: '%s': %s", ErrSavingFiles, outputPath, err)
 		}
 	}
 	return nil
 }
 
-func formatIntList(items []int) string {
-	if len(items) == 0 {
-		return "[]"
+// render executes the template, output larger than threshold is written into a temporary file, unless threshold is 0;
+// nil content is returned when the output is empty
+func render(tmpl *template.Template, templateName string, data interface{}, threshold int) (rendered, error) {
+	w := spillWriter{limit: threshold}
+	if err := tmpl.Lookup(templateName).Execute(&w, data); err != nil {
+		w.discard()
+		return rendered{}, fmt.Errorf("%w: %s: %s", ErrTemplateExecution, templateName, err)
+	}
+	out, err := w.finish()
+	if err != nil {
+		return rendered{}, fmt.Errorf("%w: %s: %s", ErrTemplateExecution, templateName, err)
+	}
+	if out.file != "" {
+		return out, nil
+	}
+	// templates checked out with CRLF line endings produce content with mixed line endings
+	out.content = bytes.ReplaceAll(out.content, []byte("\r\n"), []byte("\n"))
+	if len(bytes.TrimSpace(out.content)) == 0 {
+		out.content = nil
+	}
+	return out, nil
+}
+
+// sink returns sink into which generated files are written
+func (t FSTemplateProcessor) sink() OutputSink {
+	if t.Sink == nil {
+		return DirSink{}
 	}
-	var list []string
-	for _, v := range items {
-		list = append(list, strconv.Itoa(v))
+	return t.Sink
+}
+
+// outputPath returns the path to which the target is written, the file name is mapped with the output template if it is set
+func (t FSTemplateProcessor) outputPath(targetPath string) (string, error) {
+	if t.Output == nil || tools.OutputTemplate == "" {
+		return targetPath, nil
+	}
+	target := *t.Output
+	target.File = filepath.Base(targetPath)
+	file, err := OutputFile(tools.OutputTemplate, target)
+	if err != nil {
+		return "", err
 	}
-	output := strings.Join(list, ", ")
-	return "[" + output + "]"
+	return filepath.Join(filepath.Dir(targetPath), file), nil
 }
 
 func findTemplateFiles(dirFS fs.FS) ([]string, error) {
@@ -2,11 +2,15 @@ package templates
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
+	"testing/fstest"
 
+	"github.com/akamai/cli-terraform/pkg/tools"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +35,9 @@ func TestProcessTemplates(t *testing.T) {
 		templateDir     string
 		templateTargets map[string]string
 		data            TestData
+		output          *OutputTarget
+		outputTemplate  string
+		comments        []string
 		withError       error
 		expected        map[string]string
 	}{
@@ -75,6 +82,47 @@ func TestProcessTemplates(t *testing.T) {
 				"./testdata/res/res.txt": "This nests template 1: Hello",
 			},
 		},
+		"file names mapped with output template": {
+			templateDir: "./testdata",
+			templateTargets: map[string]string{
+				"1.tmpl": "./testdata/res/1.txt",
+			},
+			data: TestData{
+				A: "Hello",
+			},
+			output:         &OutputTarget{Product: "property", Name: "test"},
+			outputTemplate: "{{.Product}}/{{.Name}}-{{.File}}",
+			expected: map[string]string{
+				"./testdata/res/test-1.txt": "Hello",
+			},
+		},
+		"output template ignored without output": {
+			templateDir: "./testdata",
+			templateTargets: map[string]string{
+				"2.tmpl": "./testdata/res/2.txt",
+			},
+			data: TestData{
+				B: "World",
+			},
+			outputTemplate: "{{.Name}}-{{.File}}",
+			expected: map[string]string{
+				"./testdata/res/2.txt": "World",
+			},
+		},
+		"resource comments": {
+			templateDir: "./testdata",
+			templateTargets: map[string]string{
+				"comments.tmpl": "./testdata/res/comments.tf",
+			},
+			data: TestData{
+				A: "Hello",
+				B: "World",
+			},
+			comments: []string{"owner=team-a", "ticket=ABC-123"},
+			expected: map[string]string{
+				"./testdata/res/comments.tf": "# owner: team-a\n# ticket: ABC-123\nresource \"test\" \"Hello\" {\n  b = \"World\"\n}\n",
+			},
+		},
 		"error executing template": {
 			templateDir: "./testdata",
 			templateTargets: map[string]string{
@@ -89,10 +137,17 @@ func TestProcessTemplates(t *testing.T) {
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			tools.OutputTemplate = test.outputTemplate
+			tools.Comments = test.comments
+			defer func() {
+				tools.OutputTemplate = ""
+				tools.Comments = nil
+			}()
 			templateFS := os.DirFS(test.templateDir)
 			processor := FSTemplateProcessor{
 				TemplatesFS:     templateFS,
 				TemplateTargets: test.templateTargets,
+				Output:          test.output,
 			}
 			err := processor.ProcessTemplates(test.data)
 			if test.withError != nil {
@@ -114,32 +169,110 @@ func TestProcessTemplates(t *testing.T) {
 	}
 }
 
-func TestFormatIntList(t *testing.T) {
+func TestProcessTemplatesLineEndings(t *testing.T) {
+	// templates checked out on Windows with core.autocrlf have CRLF line endings, while data and formatting add LF
+	templateFS := fstest.MapFS{
+		"resource.tmpl": {Data: []byte("resource \"test\" \"{{.A}}\" {\r\n  b = \"{{.B}}\"\n}\r\n")},
+		"import.tmpl":   {Data: []byte("terraform init\r\nterraform import test.{{.A}} {{.B}}\r\n")},
+	}
 	tests := map[string]struct {
-		data   []int
-		expect string
+		lineEndings string
+		expected    map[string]string
 	}{
-		"list of ints": {
-			data:   []int{123, 345},
-			expect: "[123, 345]",
-		},
-		"empty list of ints": {
-			data:   []int{},
-			expect: "[]",
+		"lf": {
+			lineEndings: tools.LineEndingsLF,
+			expected: map[string]string{
+				"resource.tf": "resource \"test\" \"Hello\" {\n  b = \"World\"\n}\n",
+				"import.sh":   "terraform init\nterraform import test.Hello World\n",
+			},
 		},
-		"nil list of ints": {
-			data:   nil,
-			expect: "[]",
+		"crlf": {
+			lineEndings: tools.LineEndingsCRLF,
+			expected: map[string]string{
+				"resource.tf": "resource \"test\" \"Hello\" {\r\n  b = \"World\"\r\n}\r\n",
+				"import.sh":   "terraform init\r\nterraform import test.Hello World\r\n",
+			},
 		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := formatIntList(test.data)
-			assert.Equal(t, got, test.expect)
+			tools.LineEndings = test.lineEndings
+			defer func() { tools.LineEndings = "" }()
+			sink := NewMemorySink()
+			processor := FSTemplateProcessor{
+				TemplatesFS:     templateFS,
+				TemplateTargets: map[string]string{"resource.tmpl": "resource.tf", "import.tmpl": "import.sh"},
+				Sink:            sink,
+			}
+			require.NoError(t, processor.ProcessTemplates(TestData{A: "Hello", B: "World"}))
+
+			files := sink.Files()
+			for path, expected := range test.expected {
+				assert.Equal(t, expected, string(files[path]))
+			}
 		})
 	}
 }
 
+func TestProcessTemplatesSink(t *testing.T) {
+	sink := NewMemorySink()
+	processor := FSTemplateProcessor{
+		TemplatesFS: os.DirFS("./testdata"),
+		TemplateTargets: map[string]string{
+			"1.tmpl":     "./testdata/res/sink/1.txt",
+			"empty.tmpl": "./testdata/res/sink/empty.txt",
+		},
+		Sink: sink,
+	}
+	require.NoError(t, processor.ProcessTemplates(TestData{A: "Hello"}))
+
+	assert.Equal(t, map[string][]byte{filepath.Clean("./testdata/res/sink/1.txt"): []byte("Hello")}, sink.Files())
+	_, err := os.Stat("./testdata/res/sink")
+	assert.True(t, errors.Is(err, os.ErrNotExist), "expected no files written into the filesystem")
+}
+
+// orderedSink records paths of written files in order of writes
+type orderedSink struct {
+	*MemorySink
+	paths []string
+}
+
+func (s *orderedSink) WriteFile(path string, content []byte) error {
+	s.paths = append(s.paths, path)
+	return s.MemorySink.WriteFile(path, content)
+}
+
+func TestProcessTemplatesConcurrently(t *testing.T) {
+	tools.Concurrency = 8
+	defer func() { tools.Concurrency = 4 }()
+
+	templateFS := fstest.MapFS{}
+	targets := make(map[string]string)
+	var expectedPaths []string
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("%02d.tmpl", i)
+		templateFS[name] = &fstest.MapFile{Data: []byte(fmt.Sprintf("resource \"test\" \"r%d\" {\n  a = \"{{.A}}\"\n}\n", i))}
+		targets[name] = fmt.Sprintf("%02d.tf", i)
+		expectedPaths = append(expectedPaths, fmt.Sprintf("%02d.tf", i))
+	}
+	sink := &orderedSink{MemorySink: NewMemorySink()}
+	processor := FSTemplateProcessor{TemplatesFS: templateFS, TemplateTargets: targets, Sink: sink}
+	require.NoError(t, processor.ProcessTemplates(TestData{A: "Hello"}))
+
+	assert.Equal(t, expectedPaths, sink.paths)
+	assert.Equal(t, "resource \"test\" \"r7\" {\n  a = \"Hello\"\n}\n", string(sink.Files()["07.tf"]))
+
+	// the error of the first failing template is reported, regardless of which one failed first
+	templateFS["20.tmpl"] = &fstest.MapFile{Data: []byte("{{.Missing}}")}
+	templateFS["40.tmpl"] = &fstest.MapFile{Data: []byte("{{.Missing}}")}
+	sink = &orderedSink{MemorySink: NewMemorySink()}
+	processor = FSTemplateProcessor{TemplatesFS: templateFS, TemplateTargets: targets, Sink: sink}
+	err := processor.ProcessTemplates(TestData{A: "Hello"})
+	assert.True(t, errors.Is(err, ErrTemplateExecution), "expected: %s; got: %s", ErrTemplateExecution, err)
+	assert.Contains(t, err.Error(), "20.tmpl")
+	assert.Empty(t, sink.paths)
+}
+
 func TestFindTemplateFiles(t *testing.T) {
 	templateDir := os.DirFS("./testdata/findtemplatefiles")
 	got, err := findTemplateFiles(templateDir)
@@ -0,0 +1,15 @@
+package templates
+
+import (
+	"github.com/akamai/cli-terraform/pkg/compat"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+// ValidateSchema checks resources and data sources of the configuration written into targetPath against arguments of
+// their types in the provider schema snapshot, if validate-schema flag is set
+func ValidateSchema(content []byte, targetPath string) error {
+	if !tools.ValidateSchema {
+		return nil
+	}
+	return compat.ValidateConfiguration(content, targetPath)
+}
@@ -0,0 +1,26 @@
+package templates
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/compat"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchema(t *testing.T) {
+	content := []byte(`resource "akamai_cloudlets_policy" "policy" {
+  name = "test_policy"
+}`)
+	assert.NoError(t, ValidateSchema(content, "policy.tf"))
+
+	tools.ValidateSchema = true
+	defer func() { tools.ValidateSchema = false }()
+	assert.True(t, errors.Is(ValidateSchema(content, "policy.tf"), compat.ErrSchemaMismatch))
+	assert.NoError(t, ValidateSchema([]byte(`resource "akamai_cloudlets_policy" "policy" {
+  name = "test_policy"
+  cloudlet_code = "ER"
+  group_id = "12345"
+}`), "policy.tf"))
+}
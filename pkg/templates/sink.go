@@ -0,0 +1,288 @@
+package templates
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+)
+
+type (
+	// OutputSink receives generated files and decides where they are written
+	OutputSink interface {
+		// WriteFile writes content of the file at the given path, which includes the work path
+		WriteFile(path string, content []byte) error
+		// Close finishes writing, e.g. completes the zip archive, no files can be written afterwards
+		Close() error
+	}
+
+	// StreamingSink is an OutputSink which can write a file as its content is produced, without holding it in memory
+	StreamingSink interface {
+		OutputSink
+		// Create returns writer of the file at the given path, the file is complete once the writer is closed
+		Create(path string) (io.WriteCloser, error)
+	}
+
+	// DirSink writes files into the filesystem, creating their parent directories; files and directories it creates are
+	// recorded in the journal, if there is one
+	DirSink struct {
+		Journal *Journal
+	}
+
+	// MemorySink keeps written files in memory, it is meant for embedding exports in other programs and for tests
+	MemorySink struct {
+		mu    sync.Mutex
+		files map[string][]byte
+	}
+
+	// StdoutSink streams files into the writer, each one preceded by a comment line with its path relative to the work path
+	StdoutSink struct {
+		mu   sync.Mutex
+		w    io.Writer
+		base string
+	}
+
+	// ZipSink packs files into a zip archive under paths relative to the work path
+	ZipSink struct {
+		mu   sync.Mutex
+		file *os.File
+		zw   *zip.Writer
+		base string
+	}
+
+	// bufferedFile collects content of a file written into a sink which cannot stream it, the file is written on close
+	bufferedFile struct {
+		bytes.Buffer
+		sink OutputSink
+		path string
+	}
+
+	sinkCtxType string
+)
+
+// Names of output sinks accepted by NewSink
+const (
+	SinkDir    = "dir"
+	SinkStdout = "stdout"
+	SinkZip    = "zip"
+)
+
+var (
+	sinkCtx sinkCtxType = "outputSink"
+
+	// ErrInvalidOutputSink is returned when the output sink is not one of the supported ones
+	ErrInvalidOutputSink = exitcode.New(exitcode.General, "invalid output sink")
+)
+
+// NewSink returns sink given by spec, which is 'dir', 'stdout' or 'zip:<file>', paths of files written to stdout or into
+// the zip archive are relative to base
+func NewSink(spec, base string, stdout io.Writer) (OutputSink, error) {
+	name, arg := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		name, arg = spec[:i], spec[i+1:]
+	}
+	switch {
+	case (name == "" || name == SinkDir) && arg == "":
+		return DirSink{}, nil
+	case name == SinkStdout && arg == "":
+		return NewStdoutSink(stdout, base), nil
+	case name == SinkZip && arg != "":
+		return NewZipSink(arg, base)
+	}
+	return nil, fmt.Errorf("%w: '%s', expected '%s', '%s' or '%s:<file>'", ErrInvalidOutputSink, spec, SinkDir, SinkStdout, SinkZip)
+}
+
+// WithSink returns context carrying the sink into which exports write generated files
+func WithSink(ctx context.Context, sink OutputSink) context.Context {
+	return context.WithValue(ctx, sinkCtx, sink)
+}
+
+// GetSink returns sink stored in the context, DirSink recording into the journal from the context is returned if there is none;
+// files written into the sink are counted if there are write stats in the context
+func GetSink(ctx context.Context) OutputSink {
+	sink, ok := ctx.Value(sinkCtx).(OutputSink)
+	if !ok {
+		sink = DirSink{Journal: GetJournal(ctx)}
+	}
+	if stats := GetWriteStats(ctx); stats != nil {
+		return countingSink{OutputSink: sink, stats: stats}
+	}
+	return sink
+}
+
+// IsDirSink returns true if the sink writes files into the filesystem, so that they can be read back from the work path
+func IsDirSink(sink OutputSink) bool {
+	if counting, ok := sink.(countingSink); ok {
+		sink = counting.OutputSink
+	}
+	_, ok := sink.(DirSink)
+	return ok
+}
+
+// WriteFile writes the file, creating its parent directories
+func (s DirSink) WriteFile(path string, content []byte) error {
+	if s.Journal == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, content, 0644)
+	}
+	isNew, err := s.Journal.prepare(path)
+	if err != nil {
+		return err
+	}
+	if isNew {
+		// the file is recorded before it is written, so that a partially written file is removed as well
+		s.Journal.record(path)
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// Create creates the file and its parent directories, and returns its writer
+func (s DirSink) Create(path string) (io.WriteCloser, error) {
+	if s.Journal == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		return os.Create(path)
+	}
+	isNew, err := s.Journal.prepare(path)
+	if err != nil {
+		return nil, err
+	}
+	if isNew {
+		s.Journal.record(path)
+	}
+	return os.Create(path)
+}
+
+// Close does nothing, files are written immediately
+func (DirSink) Close() error {
+	return nil
+}
+
+// NewMemorySink returns empty MemorySink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{files: make(map[string][]byte)}
+}
+
+// WriteFile stores copy of the content under the cleaned path, replacing the previous one
+func (s *MemorySink) WriteFile(path string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[filepath.Clean(path)] = append([]byte(nil), content...)
+	return nil
+}
+
+// Close does nothing, files stay available
+func (s *MemorySink) Close() error {
+	return nil
+}
+
+// Files returns copy of all written files mapped by their cleaned paths
+func (s *MemorySink) Files() map[string][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := make(map[string][]byte, len(s.files))
+	for path, content := range s.files {
+		files[path] = append([]byte(nil), content...)
+	}
+	return files
+}
+
+// NewStdoutSink returns sink streaming files into w
+func NewStdoutSink(w io.Writer, base string) *StdoutSink {
+	return &StdoutSink{w: w, base: base}
+}
+
+// WriteFile writes a comment line with the path of the file followed by its content
+func (s *StdoutSink) WriteFile(path string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := fmt.Fprintf(s.w, "# File: %s\n", relativePath(s.base, path)); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(content); err != nil {
+		return err
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		_, err := io.WriteString(s.w, "\n")
+		return err
+	}
+	return nil
+}
+
+// Close does nothing, files are streamed immediately
+func (s *StdoutSink) Close() error {
+	return nil
+}
+
+// NewZipSink returns sink creating zip archive at path, any existing file at the path is replaced
+func NewZipSink(path, base string) (*ZipSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSavingFiles, err)
+	}
+	return &ZipSink{file: file, zw: zip.NewWriter(file), base: base}, nil
+}
+
+// WriteFile adds the file to the archive
+func (s *ZipSink) WriteFile(path string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, err := s.zw.CreateHeader(&zip.FileHeader{
+		Name:     relativePath(s.base, path),
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// Close writes the central directory of the archive and closes its file
+func (s *ZipSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.zw.Close(); err != nil {
+		_ = s.file.Close()
+		return fmt.Errorf("%w: %s", ErrSavingFiles, err)
+	}
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingFiles, err)
+	}
+	return nil
+}
+
+// CreateFile returns writer of the file at the path in the sink. Content is streamed into sinks implementing
+// StreamingSink, other sinks receive the whole file when the writer is closed.
+func CreateFile(sink OutputSink, path string) (io.WriteCloser, error) {
+	if s, ok := sink.(StreamingSink); ok {
+		return s.Create(path)
+	}
+	return &bufferedFile{sink: sink, path: path}, nil
+}
+
+// Close writes the collected content into the sink
+func (f *bufferedFile) Close() error {
+	return f.sink.WriteFile(f.path, f.Bytes())
+}
+
+// relativePath returns slash separated path of the file relative to base, or the cleaned path if it is outside of base
+func relativePath(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		rel = filepath.Clean(path)
+	}
+	return filepath.ToSlash(rel)
+}
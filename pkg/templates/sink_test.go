@@ -0,0 +1,130 @@
+package templates
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSink(t *testing.T) {
+	tests := map[string]struct {
+		spec      string
+		expect    interface{}
+		withError error
+	}{
+		"default": {
+			spec:   "",
+			expect: DirSink{},
+		},
+		"dir": {
+			spec:   "dir",
+			expect: DirSink{},
+		},
+		"stdout": {
+			spec:   "stdout",
+			expect: &StdoutSink{},
+		},
+		"zip": {
+			spec:   "zip:./testdata/res/new_sink.zip",
+			expect: &ZipSink{},
+		},
+		"zip without file": {
+			spec:      "zip",
+			withError: ErrInvalidOutputSink,
+		},
+		"stdout with argument": {
+			spec:      "stdout:file",
+			withError: ErrInvalidOutputSink,
+		},
+		"unknown sink": {
+			spec:      "s3",
+			withError: ErrInvalidOutputSink,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sink, err := NewSink(test.spec, "./testdata/res", io.Discard)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, test.expect, sink)
+			assert.NoError(t, sink.Close())
+		})
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	sink := DirSink{}
+	path := "./testdata/res/dir_sink/nested/file.json"
+	require.NoError(t, sink.WriteFile(path, []byte("{}")))
+	require.NoError(t, sink.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(content))
+	assert.True(t, IsDirSink(sink))
+}
+
+func TestMemorySink(t *testing.T) {
+	sink := NewMemorySink()
+	content := []byte("first")
+	require.NoError(t, sink.WriteFile("./out/property.tf", content))
+	require.NoError(t, sink.WriteFile("out/import.sh", []byte("terraform init")))
+	content[0] = 'F'
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, map[string][]byte{
+		filepath.Clean("out/property.tf"): []byte("first"),
+		filepath.Clean("out/import.sh"):   []byte("terraform init"),
+	}, sink.Files())
+	assert.False(t, IsDirSink(sink))
+}
+
+func TestStdoutSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewStdoutSink(&buf, "./out")
+	require.NoError(t, sink.WriteFile("out/property.tf", []byte("resource \"a\" \"b\" {\n}\n")))
+	require.NoError(t, sink.WriteFile("out/property-snippets/main.json", []byte("{}")))
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, "# File: property.tf\nresource \"a\" \"b\" {\n}\n# File: property-snippets/main.json\n{}\n", buf.String())
+}
+
+func TestZipSink(t *testing.T) {
+	archive := "./testdata/res/zip_sink.zip"
+	sink, err := NewZipSink(archive, "./out")
+	require.NoError(t, err)
+	require.NoError(t, sink.WriteFile("out/property.tf", []byte("property")))
+	require.NoError(t, sink.WriteFile("out/property-snippets/main.json", []byte("{}")))
+	require.NoError(t, sink.WriteFile("other/variables.tf", []byte("variables")))
+	require.NoError(t, sink.Close())
+
+	r, err := zip.OpenReader(archive)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Close())
+	}()
+	files := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(content)
+	}
+	assert.Equal(t, map[string]string{
+		"property.tf":                 "property",
+		"property-snippets/main.json": "{}",
+		"other/variables.tf":          "variables",
+	}, files)
+}
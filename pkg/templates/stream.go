@@ -0,0 +1,138 @@
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/akamai/cli-terraform/pkg/secrets"
+	"github.com/akamai/cli-terraform/pkg/tools"
+)
+
+type (
+	// rendered is output of a template, kept in memory, or in a temporary file once it grew over streamThreshold
+	rendered struct {
+		content []byte
+		file    string
+	}
+
+	// spillWriter keeps written content in memory until it grows over the limit, then the content is moved into
+	// a temporary file and further content is written into it in chunks of streamChunkSize
+	spillWriter struct {
+		limit int
+		buf   bytes.Buffer
+		file  *os.File
+		w     *bufio.Writer
+	}
+)
+
+const streamChunkSize = 64 << 10
+
+// streamThreshold is the size of rendered output above which it is not kept in memory, but written into a temporary
+// file and streamed from it into the sink, unless the output is transformed before it is written; 0 disables streaming
+var streamThreshold = 64 << 20
+
+func (s *spillWriter) Write(p []byte) (int, error) {
+	if s.file == nil {
+		if s.limit <= 0 || s.buf.Len()+len(p) <= s.limit {
+			return s.buf.Write(p)
+		}
+		file, err := os.CreateTemp("", "akamai-terraform-*")
+		if err != nil {
+			return 0, err
+		}
+		s.file, s.w = file, bufio.NewWriterSize(file, streamChunkSize)
+		if _, err := s.w.Write(s.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		s.buf = bytes.Buffer{}
+	}
+	return s.w.Write(p)
+}
+
+// finish returns the written content, the temporary file is flushed and closed if the content was moved into it
+func (s *spillWriter) finish() (rendered, error) {
+	if s.file == nil {
+		return rendered{content: s.buf.Bytes()}, nil
+	}
+	err := s.w.Flush()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(s.file.Name())
+		return rendered{}, err
+	}
+	return rendered{file: s.file.Name()}, nil
+}
+
+// discard removes the temporary file, if there is one
+func (s *spillWriter) discard() {
+	if s.file != nil {
+		_ = s.file.Close()
+		_ = os.Remove(s.file.Name())
+	}
+}
+
+// streamable returns true if output written to targetPath may be streamed into the sink as it is rendered; HCL files
+// are formatted and their variables renamed, import scripts are validated and ordered and with scan-secrets flag all
+// files are filtered, which needs the whole content in memory
+func streamable(targetPath string) bool {
+	if isImportScript(targetPath) || isVariableDefinitions(targetPath) || tools.ScanSecrets != secrets.ModeOff {
+		return false
+	}
+	switch filepath.Ext(targetPath) {
+	case ".tf", ".tfvars", ".hcl":
+		return false
+	}
+	return true
+}
+
+// streamFile copies the temporary file with rendered output into the file at path in the sink
+func streamFile(sink OutputSink, path, file string) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := CreateFile(sink, path)
+	if err != nil {
+		return err
+	}
+	if err := copyLines(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyLines copies r into w line by line with line endings converted by tools.ApplyLineEndings, lines longer than
+// streamChunkSize are copied in chunks
+func copyLines(w io.Writer, r io.Reader) error {
+	br := bufio.NewReaderSize(r, streamChunkSize)
+	for {
+		line, err := br.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) && len(line) > 1 && line[len(line)-1] == '\r' {
+			// CR may be followed by LF in the next chunk
+			_ = br.UnreadByte()
+			line = line[:len(line)-1]
+		}
+		if len(line) > 0 {
+			if _, werr := w.Write(tools.ApplyLineEndings(line)); werr != nil {
+				return werr
+			}
+		}
+		switch {
+		case err == nil, errors.Is(err, bufio.ErrBufferFull):
+		case errors.Is(err, io.EOF):
+			return nil
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/akamai/cli-terraform/pkg/secrets"
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTemplatesStreaming(t *testing.T) {
+	templateFS := fstest.MapFS{
+		"rules.tmpl":   {Data: []byte("[\r\n{{range $i, $r := .}}{{if $i}},\n{{end}}  {\"name\":\"{{$r}}\"}{{end}}\n]\n")},
+		"records.tmpl": {Data: []byte("{{range .}}variable \"{{.}}_value\" {\n}\nresource \"test\" \"{{.}}\" {\r\n  a=var.{{.}}_value\n}\n{{end}}")},
+		"import.tmpl":  {Data: []byte("terraform init\n{{range .}}terraform import test.{{.}} {{.}}\n{{end}}")},
+	}
+	records := []string{"r1", "r2", "r3", "r4"}
+	rules := func(lineEnding string) string {
+		var b strings.Builder
+		b.WriteString("[" + lineEnding)
+		for i, r := range records {
+			if i > 0 {
+				b.WriteString("," + lineEnding)
+			}
+			b.WriteString("  {\"name\":\"" + r + "\"}")
+		}
+		b.WriteString(lineEnding + "]" + lineEnding)
+		return b.String()
+	}
+	formattedRecords := func(variable func(string) string) string {
+		var b strings.Builder
+		for _, r := range records {
+			b.WriteString("variable \"" + variable(r) + "\" {\n}\nresource \"test\" \"" + r + "\" {\n  a = var." + variable(r) + "\n}\n")
+		}
+		return b.String()
+	}
+	snakeCase := func(r string) string { return r + "_value" }
+
+	tests := map[string]struct {
+		sink        func(dir string) OutputSink
+		lineEndings string
+		varNaming   string
+		expected    map[string]string
+	}{
+		"large output is streamed into dir sink, configuration is formatted": {
+			sink: func(string) OutputSink { return DirSink{} },
+			expected: map[string]string{
+				"rules.json": rules("\n"),
+				"records.tf": formattedRecords(snakeCase),
+				"import.sh":  "terraform init\nterraform import test.r1 r1\nterraform import test.r2 r2\nterraform import test.r3 r3\nterraform import test.r4 r4\n",
+			},
+		},
+		"large output is streamed with crlf line endings": {
+			sink:        func(string) OutputSink { return DirSink{} },
+			lineEndings: tools.LineEndingsCRLF,
+			expected: map[string]string{
+				"rules.json": rules("\r\n"),
+				"records.tf": strings.ReplaceAll(formattedRecords(snakeCase), "\n", "\r\n"),
+			},
+		},
+		"sink which cannot stream receives whole file": {
+			sink: func(string) OutputSink { return NewMemorySink() },
+			expected: map[string]string{
+				"rules.json": rules("\n"),
+				"records.tf": formattedRecords(snakeCase),
+			},
+		},
+		"variables of large configuration are renamed": {
+			sink:      func(string) OutputSink { return DirSink{} },
+			varNaming: tools.VarNamingCamel,
+			expected: map[string]string{
+				"records.tf": formattedRecords(func(r string) string { return r + "Value" }),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			streamThreshold = 40
+			tools.LineEndings = test.lineEndings
+			tools.VarNaming = test.varNaming
+			defer func() {
+				streamThreshold = 64 << 20
+				tools.LineEndings = ""
+				tools.VarNaming = ""
+			}()
+			dir := t.TempDir()
+			sink := test.sink(dir)
+			processor := FSTemplateProcessor{
+				TemplatesFS: templateFS,
+				TemplateTargets: map[string]string{
+					"rules.tmpl":   filepath.Join(dir, "rules.json"),
+					"records.tmpl": filepath.Join(dir, "records.tf"),
+					"import.tmpl":  filepath.Join(dir, "import.sh"),
+				},
+				Sink: sink,
+			}
+			require.NoError(t, processor.ProcessTemplates(records))
+
+			for file, expected := range test.expected {
+				var content []byte
+				if memory, ok := sink.(*MemorySink); ok {
+					content = memory.Files()[filepath.Join(dir, file)]
+				} else {
+					var err error
+					content, err = os.ReadFile(filepath.Join(dir, file))
+					require.NoError(t, err)
+				}
+				assert.Equal(t, expected, string(content), file)
+			}
+		})
+	}
+}
+
+func TestStreamable(t *testing.T) {
+	tests := map[string]struct {
+		path        string
+		scanSecrets string
+		expected    bool
+	}{
+		"rules":                        {path: "property-snippets/main.json", expected: true},
+		"rules with secrets scanned":   {path: "property-snippets/main.json", scanSecrets: secrets.ModeRedact},
+		"configuration":                {path: "property.tf"},
+		"variable definitions":         {path: "terraform.tfvars"},
+		"variable definitions example": {path: "terraform.tfvars.example"},
+		"terragrunt configuration":     {path: "terragrunt.hcl"},
+		"import script":                {path: "import.sh"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools.ScanSecrets = test.scanSecrets
+			defer func() { tools.ScanSecrets = secrets.ModeOff }()
+			assert.Equal(t, test.expected, streamable(test.path))
+		})
+	}
+}
+
+func TestCopyLines(t *testing.T) {
+	long := strings.Repeat("a", streamChunkSize-1)
+	tests := map[string]struct {
+		input       string
+		lineEndings string
+		expected    string
+	}{
+		"mixed line endings": {
+			input:    "a\r\nb\nc",
+			expected: "a\nb\nc",
+		},
+		"crlf line endings": {
+			input:       "a\r\nb\nc\n",
+			lineEndings: tools.LineEndingsCRLF,
+			expected:    "a\r\nb\r\nc\r\n",
+		},
+		"line longer than chunk": {
+			input:    long + long + "\n" + long,
+			expected: long + long + "\n" + long,
+		},
+		"crlf split between chunks": {
+			input:       long + "\r\nb\r\n",
+			lineEndings: tools.LineEndingsCRLF,
+			expected:    long + "\r\nb\r\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools.LineEndings = test.lineEndings
+			defer func() { tools.LineEndings = "" }()
+			var out bytes.Buffer
+			require.NoError(t, copyLines(&out, strings.NewReader(test.input)))
+			assert.Equal(t, test.expected, out.String())
+		})
+	}
+}
+
+func TestCreateFile(t *testing.T) {
+	t.Run("dir sink", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "file.json")
+		w, err := CreateFile(DirSink{}, path)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("{}"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(content))
+	})
+
+	t.Run("sink without streaming receives file on close", func(t *testing.T) {
+		sink := NewMemorySink()
+		w, err := CreateFile(sink, "file.json")
+		require.NoError(t, err)
+		_, err = w.Write([]byte("{}"))
+		require.NoError(t, err)
+		assert.Empty(t, sink.Files())
+		require.NoError(t, w.Close())
+		assert.Equal(t, map[string][]byte{"file.json": []byte("{}")}, sink.Files())
+	})
+
+	t.Run("streamed files are counted", func(t *testing.T) {
+		stats := NewWriteStats()
+		sink := GetSink(WithWriteStats(context.Background(), stats))
+		w, err := CreateFile(sink, filepath.Join(t.TempDir(), "file.json"))
+		require.NoError(t, err)
+		_, err = w.Write([]byte("{}"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		assert.Equal(t, 1, stats.Files())
+		assert.Equal(t, int64(2), stats.Bytes())
+	})
+}
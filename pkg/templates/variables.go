@@ -0,0 +1,103 @@
+package templates
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// moduleMetaArguments are arguments of module blocks which do not set variables of the module
+var moduleMetaArguments = map[string]bool{
+	"source": true, "version": true, "count": true, "for_each": true, "providers": true, "depends_on": true,
+}
+
+// RenameVariables renames declarations of variables, references to them and arguments of local modules setting them
+// according to the naming convention given with var-naming flag. Content is returned as it is when no convention is
+// set or it is not valid HCL, e.g. a fragment of a block.
+func RenameVariables(content []byte) []byte {
+	if tools.VarNaming == "" {
+		return content
+	}
+	f, diags := hclwrite.ParseConfig(content, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return content
+	}
+	for _, block := range f.Body().Blocks() {
+		switch block.Type() {
+		case "variable":
+			if labels := block.Labels(); len(labels) == 1 {
+				block.SetLabels([]string{tools.VariableName(labels[0])})
+			}
+		case "module":
+			if !isLocalModule(block) {
+				continue
+			}
+			for name, attr := range block.Body().Attributes() {
+				if !moduleMetaArguments[name] {
+					renameAttribute(attr, tools.VariableName(name))
+				}
+			}
+		}
+	}
+
+	// tokens are shared with the file, so references are renamed in place
+	tokens := f.BuildTokens(nil)
+	for i := 2; i < len(tokens); i++ {
+		root, dot, name := tokens[i-2], tokens[i-1], tokens[i]
+		if root.Type != hclsyntax.TokenIdent || string(root.Bytes) != "var" || dot.Type != hclsyntax.TokenDot || name.Type != hclsyntax.TokenIdent {
+			continue
+		}
+		// var is not the root of traversal, e.g. in local.var.name
+		if i > 2 && tokens[i-3].Type == hclsyntax.TokenDot {
+			continue
+		}
+		name.Bytes = []byte(tools.VariableName(string(name.Bytes)))
+	}
+	return hclwrite.Format(f.Bytes())
+}
+
+// RenameVariableValues renames variables set in variable definitions file, such as terraform.tfvars, according to the
+// naming convention given with var-naming flag. Content is returned as it is when no convention is set or it is not
+// valid HCL.
+func RenameVariableValues(content []byte) []byte {
+	if tools.VarNaming == "" {
+		return content
+	}
+	f, diags := hclwrite.ParseConfig(content, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return content
+	}
+	for name, attr := range f.Body().Attributes() {
+		renameAttribute(attr, tools.VariableName(name))
+	}
+	return hclwrite.Format(f.Bytes())
+}
+
+// isVariableDefinitions returns true for variable definitions files and their examples, e.g. terraform.tfvars.example
+func isVariableDefinitions(path string) bool {
+	return filepath.Ext(strings.TrimSuffix(path, ".example")) == ".tfvars"
+}
+
+// isLocalModule returns true for modules with source in a local directory, such as modules generated with the configuration
+func isLocalModule(block *hclwrite.Block) bool {
+	source := block.Body().GetAttribute("source")
+	if source == nil {
+		return false
+	}
+	path := strings.Trim(strings.TrimSpace(string(source.Expr().BuildTokens(nil).Bytes())), `"`)
+	return strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// renameAttribute renames the attribute in place, as hclwrite has no means to rename attributes
+func renameAttribute(attr *hclwrite.Attribute, name string) {
+	for _, token := range attr.BuildTokens(nil) {
+		if token.Type == hclsyntax.TokenIdent {
+			token.Bytes = []byte(name)
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameVariables(t *testing.T) {
+	config := `variable "edgerc_path" {
+  type = string
+}
+
+variable "config_section" {
+  type = string
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+module "zone" {
+  source = "./modules/zone"
+
+  contract_id = var.contractid
+  depends_on  = [akamai_dns_zone.zone]
+}
+
+module "registry" {
+  source  = "akamai/property/akamai"
+  group_id = var.groupid
+}
+
+resource "akamai_dns_zone" "zone" {
+  comment = "var.not_a_reference ${var.env}"
+  group   = local.var.groupid
+}
+`
+	tests := map[string]struct {
+		naming   string
+		content  string
+		expected string
+	}{
+		"no naming": {
+			content:  config,
+			expected: config,
+		},
+		"camel": {
+			naming:  tools.VarNamingCamel,
+			content: config,
+			expected: `variable "edgercPath" {
+  type = string
+}
+
+variable "configSection" {
+  type = string
+}
+
+provider "akamai" {
+  edgerc         = var.edgercPath
+  config_section = var.configSection
+}
+
+module "zone" {
+  source = "./modules/zone"
+
+  contractId = var.contractid
+  depends_on = [akamai_dns_zone.zone]
+}
+
+module "registry" {
+  source   = "akamai/property/akamai"
+  group_id = var.groupid
+}
+
+resource "akamai_dns_zone" "zone" {
+  comment = "var.not_a_reference ${var.env}"
+  group   = local.var.groupid
+}
+`,
+		},
+		"prefix": {
+			naming:   "prefix=akamai_",
+			content:  "variable \"env\" {\n}\n\nlocals {\n  env = var.env\n}\n",
+			expected: "variable \"akamai_env\" {\n}\n\nlocals {\n  env = var.akamai_env\n}\n",
+		},
+		"invalid content kept": {
+			naming:   tools.VarNamingCamel,
+			content:  "edgerc = var.edgerc_path\n}\n",
+			expected: "edgerc = var.edgerc_path\n}\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools.VarNaming = test.naming
+			defer func() { tools.VarNaming = "" }()
+			assert.Equal(t, test.expected, string(RenameVariables([]byte(test.content))))
+		})
+	}
+}
+
+func TestRenameVariableValues(t *testing.T) {
+	tfvars := `# Path of the credentials file
+edgerc_path = "~/.edgerc"
+
+config_section        = "default"
+associated_properties = ["prp_1"]
+`
+	tests := map[string]struct {
+		naming   string
+		content  string
+		expected string
+	}{
+		"no naming": {
+			content:  tfvars,
+			expected: tfvars,
+		},
+		"camel": {
+			naming:  tools.VarNamingCamel,
+			content: tfvars,
+			expected: `# Path of the credentials file
+edgercPath = "~/.edgerc"
+
+configSection        = "default"
+associatedProperties = ["prp_1"]
+`,
+		},
+		"prefix": {
+			naming:   "prefix=akamai_",
+			content:  "env = \"staging\"\n",
+			expected: "akamai_env = \"staging\"\n",
+		},
+		"invalid content kept": {
+			naming:   tools.VarNamingCamel,
+			content:  "edgerc_path = \n}\n",
+			expected: "edgerc_path = \n}\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools.VarNaming = test.naming
+			defer func() { tools.VarNaming = "" }()
+			assert.Equal(t, test.expected, string(RenameVariableValues([]byte(test.content))))
+		})
+	}
+}
+
+func TestIsVariableDefinitions(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"terraform.tfvars":         true,
+		"dir/staging.tfvars":       true,
+		"terraform.tfvars.example": true,
+		"variables.tf":             false,
+		"import.sh":                false,
+		"terraform.tf.example":     false,
+	} {
+		assert.Equal(t, expected, isVariableDefinitions(path), path)
+	}
+}
@@ -0,0 +1,120 @@
+package templates
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+type (
+	// WriteStats counts files written into sinks and their size, a file written more than once is counted with its last size
+	WriteStats struct {
+		mu    sync.Mutex
+		sizes map[string]int
+	}
+
+	// countingSink records files written into the wrapped sink
+	countingSink struct {
+		OutputSink
+		stats *WriteStats
+	}
+
+	// countingWriter counts bytes of a file streamed into a countingSink, the file is recorded when it is closed
+	countingWriter struct {
+		io.WriteCloser
+		stats *WriteStats
+		path  string
+		size  int
+	}
+
+	writeStatsCtxType string
+)
+
+var writeStatsCtx writeStatsCtxType = "writeStats"
+
+// NewWriteStats returns empty WriteStats
+func NewWriteStats() *WriteStats {
+	return &WriteStats{sizes: make(map[string]int)}
+}
+
+// WithWriteStats returns context in which sinks returned by GetSink record written files into stats
+func WithWriteStats(ctx context.Context, stats *WriteStats) context.Context {
+	return context.WithValue(ctx, writeStatsCtx, stats)
+}
+
+// GetWriteStats returns stats stored in the context, nil is returned if written files are not counted
+func GetWriteStats(ctx context.Context) *WriteStats {
+	stats, _ := ctx.Value(writeStatsCtx).(*WriteStats)
+	return stats
+}
+
+// Files returns the number of written files
+func (s *WriteStats) Files() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sizes)
+}
+
+// Bytes returns the total size of written files
+func (s *WriteStats) Bytes() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total int64
+	for _, size := range s.sizes {
+		total += int64(size)
+	}
+	return total
+}
+
+// Paths returns sorted paths of written files
+func (s *WriteStats) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := make([]string, 0, len(s.sizes))
+	for path := range s.sizes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (s *WriteStats) record(path string, size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sizes[filepath.Clean(path)] = size
+}
+
+// WriteFile writes the file into the wrapped sink and records it, if it was written
+func (s countingSink) WriteFile(path string, content []byte) error {
+	if err := s.OutputSink.WriteFile(path, content); err != nil {
+		return err
+	}
+	s.stats.record(path, len(content))
+	return nil
+}
+
+// Create returns writer of the file in the wrapped sink, which records the file once it is closed
+func (s countingSink) Create(path string) (io.WriteCloser, error) {
+	w, err := CreateFile(s.OutputSink, path)
+	if err != nil {
+		return nil, err
+	}
+	return &countingWriter{WriteCloser: w, stats: s.stats, path: path}, nil
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	w.size += n
+	return n, err
+}
+
+// Close closes the file and records it, if it was written
+func (w *countingWriter) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		return err
+	}
+	w.stats.record(w.path, w.size)
+	return nil
+}
@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingSink struct {
+	MemorySink
+}
+
+func (*failingSink) WriteFile(string, []byte) error {
+	return errors.New("oops")
+}
+
+func TestWriteStats(t *testing.T) {
+	t.Run("files written into sink are counted", func(t *testing.T) {
+		memory := NewMemorySink()
+		stats := NewWriteStats()
+		ctx := WithWriteStats(WithSink(context.Background(), memory), stats)
+
+		sink := GetSink(ctx)
+		require.NoError(t, sink.WriteFile("dir/property.tf", []byte("resource")))
+		require.NoError(t, sink.WriteFile("dir/variables.tf", []byte("variable")))
+		require.NoError(t, sink.WriteFile("dir/./property.tf", []byte("resource {}")))
+
+		assert.Equal(t, 2, stats.Files())
+		assert.Equal(t, int64(19), stats.Bytes())
+		assert.Equal(t, []string{filepath.Join("dir", "property.tf"), filepath.Join("dir", "variables.tf")}, stats.Paths())
+		assert.Len(t, memory.Files(), 2)
+	})
+
+	t.Run("failed writes are not counted", func(t *testing.T) {
+		stats := NewWriteStats()
+		ctx := WithWriteStats(WithSink(context.Background(), &failingSink{}), stats)
+
+		assert.Error(t, GetSink(ctx).WriteFile("property.tf", []byte("resource")))
+		assert.Equal(t, 0, stats.Files())
+	})
+
+	t.Run("counting dir sink is still dir sink", func(t *testing.T) {
+		dir := t.TempDir()
+		stats := NewWriteStats()
+		sink := GetSink(WithWriteStats(context.Background(), stats))
+
+		assert.True(t, IsDirSink(sink))
+		require.NoError(t, sink.WriteFile(filepath.Join(dir, "property.tf"), []byte("resource")))
+		assert.FileExists(t, filepath.Join(dir, "property.tf"))
+		assert.Equal(t, 1, stats.Files())
+	})
+
+	t.Run("no stats in context", func(t *testing.T) {
+		assert.Nil(t, GetWriteStats(context.Background()))
+		assert.Equal(t, DirSink{}, GetSink(context.Background()))
+	})
+}
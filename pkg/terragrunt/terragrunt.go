@@ -0,0 +1,94 @@
+// Package terragrunt contains code for scaffolding terragrunt configuration for exported Terraform configuration
+package terragrunt
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/templates"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type (
+	// TFData represents the data used in terragrunt template
+	TFData struct {
+		Variables []Variable
+	}
+
+	// Variable is an input variable declared in the exported configuration,
+	// Default holds the source of its default value and is empty for required variables
+	Variable struct {
+		Name    string
+		Default string
+	}
+)
+
+// FileName is the name of generated terragrunt configuration file
+const FileName = "terragrunt.hcl"
+
+var (
+	//go:embed templates/*
+	templateFiles embed.FS
+
+	// ErrReadingConfiguration is returned when exported configuration cannot be read or parsed
+	ErrReadingConfiguration = exitcode.New(exitcode.Template, "unable to read exported configuration")
+)
+
+// Scaffold writes terragrunt.hcl into dir, wiring remote state and all variables declared
+// by the Terraform configuration in dir as inputs
+func Scaffold(dir string) error {
+	variables, err := readVariables(dir)
+	if err != nil {
+		return err
+	}
+	processor := templates.FSTemplateProcessor{
+		TemplatesFS:     templateFiles,
+		TemplateTargets: map[string]string{"terragrunt.tmpl": filepath.Join(dir, FileName)},
+	}
+	return processor.ProcessTemplates(TFData{Variables: variables})
+}
+
+// readVariables returns variables declared in .tf files placed directly in dir, in order of their declaration
+func readVariables(dir string) ([]Variable, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingConfiguration, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no configuration files found in '%s'", ErrReadingConfiguration, dir)
+	}
+	sort.Strings(files)
+
+	var variables []Variable
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrReadingConfiguration, err)
+		}
+		f, diags := hclsyntax.ParseConfig(content, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%w: %s", ErrReadingConfiguration, diags.Error())
+		}
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "variable" || len(block.Labels) != 1 {
+				continue
+			}
+			variable := Variable{Name: block.Labels[0]}
+			if def, ok := block.Body.Attributes["default"]; ok {
+				variable.Default = strings.TrimSpace(string(def.Expr.Range().SliceBytes(content)))
+			}
+			variables = append(variables, variable)
+		}
+	}
+	return variables, nil
+}
@@ -0,0 +1,58 @@
+package terragrunt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffold(t *testing.T) {
+	tests := map[string]struct {
+		dir          string
+		expectedFile string
+		withError    error
+	}{
+		"variables wired as inputs": {
+			dir:          "./testdata/export",
+			expectedFile: "./testdata/res/terragrunt.hcl",
+		},
+		"invalid configuration": {
+			dir:       "./testdata/invalid",
+			withError: ErrReadingConfiguration,
+		},
+		"no configuration": {
+			dir:       "./testdata/res",
+			withError: ErrReadingConfiguration,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			files, err := filepath.Glob(filepath.Join(test.dir, "*.tf"))
+			require.NoError(t, err)
+			for _, file := range files {
+				content, err := os.ReadFile(file)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.Base(file)), content, 0644))
+			}
+
+			err = Scaffold(dir)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+				assert.NoFileExists(t, filepath.Join(dir, FileName))
+				return
+			}
+			require.NoError(t, err)
+			expected, err := os.ReadFile(test.expectedFile)
+			require.NoError(t, err)
+			result, err := os.ReadFile(filepath.Join(dir, FileName))
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(result))
+		})
+	}
+}
@@ -0,0 +1,12 @@
+variable "hostnames" {
+  type = list(string)
+  default = [
+    "test.com",
+    "www.test.com",
+  ]
+}
+
+resource "akamai_property" "test" {
+  name        = "test"
+  contract_id = var.contract_id
+}
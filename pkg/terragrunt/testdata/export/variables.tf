@@ -0,0 +1,13 @@
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "config_section" {
+  type    = string
+  default = "default"
+}
+
+variable "contract_id" {
+  type = string
+}
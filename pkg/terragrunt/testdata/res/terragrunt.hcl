@@ -0,0 +1,23 @@
+# Terragrunt configuration generated by Akamai CLI for Terraform.
+# State is kept next to this file, replace the backend with the one used by your organization
+# or remove this block when remote state is inherited from a parent terragrunt.hcl.
+remote_state {
+  backend = "local"
+  generate = {
+    path      = "backend.tf"
+    if_exists = "overwrite_terragrunt"
+  }
+  config = {
+    path = "${get_terragrunt_dir()}/terraform.tfstate"
+  }
+}
+
+inputs = {
+  hostnames = [
+    "test.com",
+    "www.test.com",
+  ]
+  edgerc_path    = "~/.edgerc"
+  config_section = "default"
+  # contract_id = <required, no default value in exported configuration>
+}
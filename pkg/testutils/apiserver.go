@@ -0,0 +1,91 @@
+package testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/archive"
+)
+
+// APIServer is a fake Akamai API server which responds with recorded responses
+type APIServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string][]archive.Response
+	unmatched []string
+}
+
+// NewAPIServer starts APIServer seeded with responses from given fixture files, which are closed when the test finishes.
+// Fixture files use the format of responses recorded with --archive-api-responses flag. Requests are matched by method,
+// path and query; when the same request was recorded more than once, responses are returned in the recorded order
+// and the last one is repeated.
+func NewAPIServer(t testing.TB, fixtures ...string) *APIServer {
+	t.Helper()
+	s := &APIServer{responses: make(map[string][]archive.Response)}
+	for _, fixture := range fixtures {
+		content, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("reading API fixture: %s", err)
+		}
+		var responses []archive.Response
+		if err := json.Unmarshal(content, &responses); err != nil {
+			t.Fatalf("parsing API fixture '%s': %s", fixture, err)
+		}
+		for _, resp := range responses {
+			u, err := url.Parse(resp.URL)
+			if err != nil {
+				t.Fatalf("parsing URL of API fixture '%s': %s", fixture, err)
+			}
+			key := requestKey(resp.Method, u)
+			s.responses[key] = append(s.responses[key], resp)
+		}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Unmatched returns requests, in form of 'METHOD path?query', for which no response was recorded
+func (s *APIServer) Unmatched() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.unmatched...)
+}
+
+func (s *APIServer) handle(w http.ResponseWriter, r *http.Request) {
+	key := requestKey(r.Method, r.URL)
+	s.mu.Lock()
+	responses := s.responses[key]
+	if len(responses) == 0 {
+		s.unmatched = append(s.unmatched, key)
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotImplemented)
+		_, _ = fmt.Fprintf(w, `{"title":"No recorded response","detail":"%s"}`, key)
+		return
+	}
+	resp := responses[0]
+	if len(responses) > 1 {
+		s.responses[key] = responses[1:]
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write([]byte(resp.Body))
+}
+
+func requestKey(method string, u *url.URL) string {
+	key := method + " " + u.Path
+	if query := u.Query().Encode(); query != "" {
+		key += "?" + query
+	}
+	return key
+}
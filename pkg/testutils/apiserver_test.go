@@ -0,0 +1,43 @@
+package testutils
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIServer(t *testing.T) {
+	srv := NewAPIServer(t, "./testdata/api/responses.json")
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, resp.Body.Close())
+		}()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	tests := []struct {
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{path: "/cloudlets/api/v2/policies?pageSize=1000&offset=0", expectedCode: http.StatusOK, expectedBody: `[{"policyId":1}]`},
+		{path: "/cloudlets/api/v2/policies/1/versions/1", expectedCode: http.StatusInternalServerError, expectedBody: `{"title":"Internal Server Error"}`},
+		{path: "/cloudlets/api/v2/policies/1/versions/1", expectedCode: http.StatusOK, expectedBody: `{"version":1}`},
+		{path: "/cloudlets/api/v2/policies/1/versions/1", expectedCode: http.StatusOK, expectedBody: `{"version":1}`},
+		{path: "/cloudlets/api/v2/policies/2", expectedCode: http.StatusNotImplemented, expectedBody: `{"title":"No recorded response","detail":"GET /cloudlets/api/v2/policies/2"}`},
+	}
+	for _, test := range tests {
+		code, body := get(test.path)
+		assert.Equal(t, test.expectedCode, code, test.path)
+		assert.Equal(t, test.expectedBody, body, test.path)
+	}
+	assert.Equal(t, []string{"GET /cloudlets/api/v2/policies/2"}, srv.Unmatched())
+}
@@ -0,0 +1,84 @@
+// Package testutils contains helpers for snapshot testing of generated files against golden files
+package testutils
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var update = flag.Bool("update", false, "overwrite golden files with generated results instead of comparing them")
+
+// Update reports whether the test was run with -update flag, in which case golden files are regenerated
+func Update() bool {
+	return *update
+}
+
+// AssertFile compares generated file at resultPath with the golden file at goldenPath and reports a normalized diff if they differ,
+// when run with -update flag, the golden file is overwritten with the generated one instead
+func AssertFile(t testing.TB, goldenPath, resultPath string) {
+	t.Helper()
+	result, err := os.ReadFile(resultPath)
+	if err != nil {
+		t.Fatalf("reading generated file: %s", err)
+	}
+	AssertContent(t, goldenPath, result)
+}
+
+// AssertFiles compares files with given names generated in resultDir with golden files placed in goldenDir
+func AssertFiles(t testing.TB, goldenDir, resultDir string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		AssertFile(t, filepath.Join(goldenDir, file), filepath.Join(resultDir, file))
+	}
+}
+
+// AssertContent compares content with the golden file at goldenPath and reports a normalized diff if they differ,
+// when run with -update flag, the golden file is overwritten with content instead
+func AssertContent(t testing.TB, goldenPath string, content []byte) {
+	t.Helper()
+	if Update() {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+			t.Fatalf("creating golden file directory: %s", err)
+		}
+		if err := os.WriteFile(goldenPath, content, 0644); err != nil {
+			t.Fatalf("updating golden file: %s", err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("reading golden file, run the test with -update flag to create it: %s", err)
+	}
+	if diff := Diff(expected, content); diff != "" {
+		t.Errorf("generated content does not match golden file %s, run the test with -update flag to regenerate it:\n%s", goldenPath, diff)
+	}
+}
+
+// Diff returns unified diff between normalized expected and actual content, or an empty string if they are equal
+func Diff(expected, actual []byte) string {
+	expected, actual = Normalize(expected), Normalize(actual)
+	if bytes.Equal(expected, actual) {
+		return ""
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(string(actual)),
+		FromFile: "expected",
+		ToFile:   "actual",
+		Context:  3,
+	})
+	if err != nil {
+		return err.Error()
+	}
+	return diff
+}
+
+// Normalize converts line endings to '\n', so that golden files checked out on any platform can be compared
+func Normalize(content []byte) []byte {
+	return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+}
@@ -0,0 +1,91 @@
+package testutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT records failures instead of failing the test which runs the assertion
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func TestAssertContent(t *testing.T) {
+	tests := map[string]struct {
+		golden          string
+		content         string
+		expectedFailure string
+	}{
+		"equal": {
+			golden:  "./testdata/policy.tf",
+			content: "resource \"akamai_cloudlets_policy\" \"policy\" {\n  name = \"test\"\n}\n",
+		},
+		"equal with different line endings": {
+			golden:  "./testdata/policy.tf",
+			content: "resource \"akamai_cloudlets_policy\" \"policy\" {\r\n  name = \"test\"\r\n}\r\n",
+		},
+		"different": {
+			golden:  "./testdata/policy.tf",
+			content: "resource \"akamai_cloudlets_policy\" \"policy\" {\n  name = \"other\"\n}\n",
+			expectedFailure: "generated content does not match golden file ./testdata/policy.tf, run the test with -update flag to regenerate it:\n" +
+				"--- expected\n+++ actual\n@@ -1,4 +1,4 @@\n resource \"akamai_cloudlets_policy\" \"policy\" {\n-  name = \"test\"\n+  name = \"other\"\n }\n \n",
+		},
+		"missing golden file": {
+			golden:          "./testdata/missing.tf",
+			content:         "",
+			expectedFailure: "reading golden file, run the test with -update flag to create it",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rt := &recordingT{TB: t}
+			AssertContent(rt, test.golden, []byte(test.content))
+			if test.expectedFailure == "" {
+				assert.Empty(t, rt.failures)
+				return
+			}
+			require.NotEmpty(t, rt.failures)
+			assert.Contains(t, rt.failures[0], test.expectedFailure)
+		})
+	}
+}
+
+func TestAssertFilesUpdate(t *testing.T) {
+	*update = true
+	defer func() {
+		*update = false
+	}()
+
+	goldenDir, resultDir := filepath.Join(t.TempDir(), "golden"), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(resultDir, "policy.tf"), []byte("policy"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(resultDir, "import.sh"), []byte("import"), 0644))
+
+	AssertFiles(t, goldenDir, resultDir, "policy.tf", "import.sh")
+	for file, expected := range map[string]string{"policy.tf": "policy", "import.sh": "import"} {
+		content, err := os.ReadFile(filepath.Join(goldenDir, file))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	assert.Empty(t, Diff([]byte("a\nb\n"), []byte("a\r\nb\r\n")))
+	assert.Equal(t, "--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n a\n-b\n+c\n \n", Diff([]byte("a\nb\n"), []byte("a\nc\n")))
+}
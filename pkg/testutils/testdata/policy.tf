@@ -0,0 +1,3 @@
+resource "akamai_cloudlets_policy" "policy" {
+  name = "test"
+}
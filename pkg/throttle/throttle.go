@@ -0,0 +1,224 @@
+// Package throttle contains code for keeping API requests of a command within concurrency and rate budgets of each API
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Budget limits requests of a single API
+	Budget struct {
+		// Concurrency is the maximum number of requests to the API in flight at once
+		Concurrency int
+		// Rate is the maximum number of requests to the API started per second, 0 means no limit
+		Rate float64
+	}
+
+	// Scheduler keeps requests sent by its round tripper within budgets of APIs they are sent to, requests to APIs
+	// without a budget are not limited
+	Scheduler struct {
+		limiters map[string]*limiter
+	}
+
+	// limiter holds free request slots of an API and the earliest time the next request to it can start
+	limiter struct {
+		slots    chan struct{}
+		interval time.Duration
+		mu       sync.Mutex
+		next     time.Time
+		now      func() time.Time
+	}
+
+	roundTripperFunc func(*http.Request) (*http.Response, error)
+
+	ctxType string
+)
+
+// APIs whose requests are limited, their names are used to override budgets with api-budget flag
+const (
+	APIAppSec      = "appsec"
+	APICloudlets   = "cloudlets"
+	APIDNS         = "dns"
+	APIEdgeWorkers = "edgeworkers"
+	APIGTM         = "gtm"
+	APIPAPI        = "papi"
+)
+
+var (
+	schedulerCtx ctxType = "scheduler"
+
+	// apiPaths are path prefixes of endpoints of each API
+	apiPaths = map[string][]string{
+		APIAppSec:      {"/appsec/"},
+		APICloudlets:   {"/cloudlets/"},
+		APIDNS:         {"/config-dns/"},
+		APIEdgeWorkers: {"/edgeworkers/", "/edgekv/"},
+		APIGTM:         {"/config-gtm/"},
+		APIPAPI:        {"/papi/"},
+	}
+
+	// defaultBudgets are budgets of APIs used unless they are overridden with api-budget flag, APIs with lower rate
+	// limits get fewer concurrent requests, so that a multi-product export does not spend all of them on a single API
+	defaultBudgets = map[string]Budget{
+		APIAppSec:      {Concurrency: 2, Rate: 5},
+		APICloudlets:   {Concurrency: 2, Rate: 5},
+		APIDNS:         {Concurrency: 8, Rate: 20},
+		APIEdgeWorkers: {Concurrency: 4, Rate: 10},
+		APIGTM:         {Concurrency: 2, Rate: 5},
+		APIPAPI:        {Concurrency: 4, Rate: 10},
+	}
+)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// ParseBudgets returns default budgets of APIs overridden by budgets given with api-budget flag in
+// 'api=concurrency[:rate]' format, e.g. 'papi=4:10' allows 4 concurrent requests and 10 requests per second
+func ParseBudgets(values []string) (map[string]Budget, error) {
+	budgets := make(map[string]Budget, len(defaultBudgets))
+	for api, budget := range defaultBudgets {
+		budgets[api] = budget
+	}
+	for _, value := range values {
+		i := strings.Index(value, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("budget '%s' is not in 'api=concurrency[:rate]' format", value)
+		}
+		api, limits := strings.TrimSpace(value[:i]), value[i+1:]
+		if _, ok := defaultBudgets[api]; !ok {
+			return nil, fmt.Errorf("budget '%s' is given for unknown API '%s', expected one of: %s", value, api, strings.Join(APIs(), ", "))
+		}
+		var budget Budget
+		concurrency, rate := limits, ""
+		if j := strings.Index(limits, ":"); j >= 0 {
+			concurrency, rate = limits[:j], limits[j+1:]
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(concurrency))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("concurrency of budget '%s' must be a positive number", value)
+		}
+		budget.Concurrency = n
+		if concurrency != limits {
+			r, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+			if err != nil || r < 0 {
+				return nil, fmt.Errorf("rate of budget '%s' must be a non-negative number", value)
+			}
+			budget.Rate = r
+		}
+		budgets[api] = budget
+	}
+	return budgets, nil
+}
+
+// APIs returns sorted names of APIs which have a budget
+func APIs() []string {
+	apis := make([]string, 0, len(defaultBudgets))
+	for api := range defaultBudgets {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+	return apis
+}
+
+// API returns the name of the API the endpoint at the path belongs to, an empty string is returned for endpoints of
+// APIs without a budget
+func API(path string) string {
+	for api, prefixes := range apiPaths {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return api
+			}
+		}
+	}
+	return ""
+}
+
+// New returns a Scheduler keeping requests within the budgets
+func New(budgets map[string]Budget) *Scheduler {
+	s := &Scheduler{limiters: make(map[string]*limiter, len(budgets))}
+	for api, budget := range budgets {
+		l := &limiter{slots: make(chan struct{}, budget.Concurrency), now: time.Now}
+		if budget.Rate > 0 {
+			l.interval = time.Duration(float64(time.Second) / budget.Rate)
+		}
+		s.limiters[api] = l
+	}
+	return s
+}
+
+// RoundTripper returns http.RoundTripper which sends requests using the next round tripper once the budget of their API
+// allows it, a request waiting for its turn fails when its context is done
+func (s *Scheduler) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		l, ok := s.limiters[API(req.URL.Path)]
+		if !ok {
+			return next.RoundTrip(req)
+		}
+		if err := l.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+		defer l.release()
+		return next.RoundTrip(req)
+	})
+}
+
+// acquire takes a request slot and waits until the request can start without exceeding the rate
+func (l *limiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	wait := l.reserve()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// reserve returns how long the request has to wait for its start, requests start at least interval apart
+func (l *limiter) reserve() time.Duration {
+	if l.interval == 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	return start.Sub(now)
+}
+
+func (l *limiter) release() {
+	<-l.slots
+}
+
+// WithScheduler puts the scheduler in context
+func WithScheduler(ctx context.Context, s *Scheduler) context.Context {
+	return context.WithValue(ctx, schedulerCtx, s)
+}
+
+// GetScheduler returns the scheduler from context, nil is returned if there is none
+func GetScheduler(ctx context.Context) *Scheduler {
+	s, _ := ctx.Value(schedulerCtx).(*Scheduler)
+	return s
+}
@@ -0,0 +1,185 @@
+package throttle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBudgets(t *testing.T) {
+	tests := map[string]struct {
+		values    []string
+		expect    map[string]Budget
+		withError string
+	}{
+		"defaults": {
+			expect: defaultBudgets,
+		},
+		"concurrency and rate": {
+			values: []string{"papi=8:25"},
+			expect: withBudget(APIPAPI, Budget{Concurrency: 8, Rate: 25}),
+		},
+		"concurrency only removes rate limit": {
+			values: []string{" dns = 16"},
+			expect: withBudget(APIDNS, Budget{Concurrency: 16}),
+		},
+		"fractional rate": {
+			values: []string{"cloudlets=1:0.5"},
+			expect: withBudget(APICloudlets, Budget{Concurrency: 1, Rate: 0.5}),
+		},
+		"unknown API": {
+			values:    []string{"hapi=2"},
+			withError: "budget 'hapi=2' is given for unknown API 'hapi', expected one of: appsec, cloudlets, dns, edgeworkers, gtm, papi",
+		},
+		"missing API": {
+			values:    []string{"2:10"},
+			withError: "budget '2:10' is not in 'api=concurrency[:rate]' format",
+		},
+		"zero concurrency": {
+			values:    []string{"papi=0"},
+			withError: "concurrency of budget 'papi=0' must be a positive number",
+		},
+		"invalid rate": {
+			values:    []string{"papi=2:fast"},
+			withError: "rate of budget 'papi=2:fast' must be a non-negative number",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			budgets, err := ParseBudgets(test.values)
+			if test.withError != "" {
+				assert.EqualError(t, err, test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, budgets)
+		})
+	}
+}
+
+func TestAPI(t *testing.T) {
+	tests := map[string]string{
+		"/papi/v1/properties":                  APIPAPI,
+		"/cloudlets/api/v2/policies":           APICloudlets,
+		"/cloudlets/v3/policies/1":             APICloudlets,
+		"/config-dns/v2/zones/example.com":     APIDNS,
+		"/config-gtm/v1/domains":               APIGTM,
+		"/appsec/v1/configs":                   APIAppSec,
+		"/edgekv/v1/networks/staging":          APIEdgeWorkers,
+		"/hapi/v1/edge-hostnames":              "",
+		"/papi-like/v1/not-a-papi-endpoint":    "",
+		"/edgeworkers/v1/ids/1/versions/1.0.0": APIEdgeWorkers,
+	}
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			assert.Equal(t, expected, API(path))
+		})
+	}
+}
+
+func TestRoundTripperConcurrency(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight = map[string]int{}
+		peak     = map[string]int{}
+	)
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		api := API(req.URL.Path)
+		mu.Lock()
+		inFlight[api]++
+		if inFlight[api] > peak[api] {
+			peak[api] = inFlight[api]
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight[api]--
+		mu.Unlock()
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	rt := New(map[string]Budget{APIPAPI: {Concurrency: 2}}).RoundTripper(next)
+
+	var wg sync.WaitGroup
+	for _, path := range []string{"/papi/v1/a", "/papi/v1/b", "/papi/v1/c", "/papi/v1/d", "/papi/v1/e", "/hapi/v1/a", "/hapi/v1/b", "/hapi/v1/c"} {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://host"+path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}(path)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, peak[APIPAPI])
+	// requests to APIs without a budget are not limited
+	assert.Equal(t, 3, peak[""])
+}
+
+func TestRoundTripperCanceled(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	rt := New(map[string]Budget{APIDNS: {Concurrency: 1}}).RoundTripper(next)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://host/config-dns/v2/zones", nil))
+		assert.NoError(t, err)
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "https://host/config-dns/v2/zones", nil).WithContext(ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	<-done
+}
+
+func TestReserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := New(map[string]Budget{APIPAPI: {Concurrency: 1, Rate: 10}}).limiters[APIPAPI]
+	l.now = func() time.Time { return now }
+
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, 100*time.Millisecond, l.reserve())
+	assert.Equal(t, 200*time.Millisecond, l.reserve())
+
+	// reservations are not accumulated while the API is idle
+	now = now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), l.reserve())
+
+	unlimited := New(map[string]Budget{APIPAPI: {Concurrency: 1}}).limiters[APIPAPI]
+	assert.Equal(t, time.Duration(0), unlimited.reserve())
+	assert.Equal(t, time.Duration(0), unlimited.reserve())
+}
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, GetScheduler(ctx))
+
+	s := New(defaultBudgets)
+	assert.Same(t, s, GetScheduler(WithScheduler(ctx, s)))
+}
+
+func withBudget(api string, budget Budget) map[string]Budget {
+	budgets := make(map[string]Budget, len(defaultBudgets))
+	for a, b := range defaultBudgets {
+		budgets[a] = b
+	}
+	budgets[api] = budget
+	return budgets
+}
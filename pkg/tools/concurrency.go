@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	semaphoreMutex sync.Mutex
+	semaphore      chan struct{}
+)
+
+// RunConcurrently calls fn for every index in range [0, n).
+//
+// All calls share a single semaphore sized according to Concurrency, so the number of parallel requests stays bounded
+// even if RunConcurrently is nested or used by several providers at once. When no slot is available, fn is executed
+// in the calling goroutine, which guarantees progress without the risk of deadlock.
+// The first error returned by fn cancels the context passed to remaining calls and is returned once all started calls finish.
+func RunConcurrently(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	setErr := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	slots := getSemaphore()
+	for i := 0; i < n && runCtx.Err() == nil; i++ {
+		select {
+		case slots <- struct{}{}:
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				if err := fn(runCtx, i); err != nil {
+					setErr(err)
+				}
+			}(i)
+		default:
+			if err := fn(runCtx, i); err != nil {
+				setErr(err)
+			}
+		}
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// getSemaphore returns semaphore shared by all concurrent calls, the calling goroutine counts as one of the workers
+func getSemaphore() chan struct{} {
+	semaphoreMutex.Lock()
+	defer semaphoreMutex.Unlock()
+
+	size := Concurrency - 1
+	if size < 0 {
+		size = 0
+	}
+	if semaphore == nil || cap(semaphore) != size {
+		semaphore = make(chan struct{}, size)
+	}
+	return semaphore
+}
@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	tests := map[string]struct {
+		concurrency int
+		n           int
+		failAt      int
+		withError   bool
+	}{
+		"sequential": {
+			concurrency: 1,
+			n:           10,
+			failAt:      -1,
+		},
+		"parallel": {
+			concurrency: 4,
+			n:           50,
+			failAt:      -1,
+		},
+		"concurrency lower than 1 runs sequentially": {
+			concurrency: 0,
+			n:           5,
+			failAt:      -1,
+		},
+		"error stops processing": {
+			concurrency: 4,
+			n:           50,
+			failAt:      3,
+			withError:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func(c int) { Concurrency = c }(Concurrency)
+			Concurrency = test.concurrency
+
+			var running, maxRunning int32
+			results := make([]int, test.n)
+			err := RunConcurrently(context.Background(), test.n, func(_ context.Context, i int) error {
+				current := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					max := atomic.LoadInt32(&maxRunning)
+					if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				if i == test.failAt {
+					return errors.New("oops")
+				}
+				results[i] = i * 2
+				return nil
+			})
+			limit := int32(test.concurrency)
+			if limit < 1 {
+				limit = 1
+			}
+			assert.LessOrEqual(t, maxRunning, limit)
+			if test.withError {
+				assert.EqualError(t, err, "oops")
+				return
+			}
+			require.NoError(t, err)
+			for i, r := range results {
+				assert.Equal(t, i*2, r)
+			}
+		})
+	}
+}
+
+func TestRunConcurrentlyCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int32
+	err := RunConcurrently(ctx, 10, func(_ context.Context, _ int) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, int32(0), calls)
+}
@@ -4,14 +4,24 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
 )
 
+// ErrFileExists is returned when a file which is about to be generated already exists
+var ErrFileExists = exitcode.New(exitcode.IO, "file already exists")
+
 // CheckFiles verifies if all given files doesn't exist in filesystem
+// Existing files are allowed when Merge is set, as they are merged with re-exported configuration, and when Only is
+// set, as only the selected blocks and files are written over them
 func CheckFiles(files ...string) error {
+	if Merge || len(Only) > 0 {
+		return nil
+	}
 	for _, file := range files {
 		_, err := os.Stat(file)
 		if err == nil {
-			return fmt.Errorf("file %s already exists", file)
+			return fmt.Errorf("%w: %s", ErrFileExists, file)
 		}
 	}
 	return nil
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,8 @@ import (
 func TestCheckFiles(t *testing.T) {
 	tests := map[string]struct {
 		given     []string
+		merge     bool
+		only      []string
 		withError bool
 	}{
 		"files do not exist": {
@@ -19,13 +22,27 @@ func TestCheckFiles(t *testing.T) {
 			given:     []string{"testdata/f1.txt", "testdata/f3.txt"},
 			withError: true,
 		},
+		"existing files are merged": {
+			given:     []string{"testdata/f1.txt", "testdata/f3.txt"},
+			merge:     true,
+			withError: false,
+		},
+		"existing files are re-exported partially": {
+			given:     []string{"testdata/f1.txt", "testdata/f3.txt"},
+			only:      []string{"akamai_dns_record.www"},
+			withError: false,
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			Merge, Only = test.merge, test.only
+			defer func() {
+				Merge, Only = false, nil
+			}()
 			err := CheckFiles(test.given...)
 			if test.withError {
-				assert.Error(t, err)
+				assert.True(t, errors.Is(err, ErrFileExists), "expected: %s; got: %s", ErrFileExists, err)
 				return
 			}
 			assert.NoError(t, err)
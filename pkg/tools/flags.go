@@ -1,4 +1,100 @@
 package tools
 
+import "time"
+
 // Schema means that content of the policy will be generated using HCL instead of JSON file
 var Schema bool
+
+// Concurrency is the maximum number of API requests which can be run in parallel by all providers
+var Concurrency = 4
+
+// JSON means that summary of the command run is printed in JSON format
+var JSON bool
+
+// APIStats means that counts and durations of API calls are recorded and reported per endpoint when the command finishes
+var APIStats bool
+
+// Stats means that a summary of the run, such as numbers of exported objects, API calls and written files, is reported
+// when the command finishes
+var Stats bool
+
+// ProviderVersion is a version of Akamai Terraform provider against which generated configuration is checked, check is skipped when empty
+var ProviderVersion string
+
+// ValidateImportIDs means that IDs of imported resources are checked against import ID formats documented for the
+// provider version before import scripts are written
+var ValidateImportIDs bool
+
+// ValidateSchema means that resources and data sources of generated configuration are checked against arguments of
+// their types in the provider schema snapshot before files are written
+var ValidateSchema bool
+
+// Terragrunt means that terragrunt.hcl is generated next to exported configuration
+var Terragrunt bool
+
+// Readme means that README.md describing exported resources, variables, import procedure and warnings is generated next to exported configuration
+var Readme bool
+
+// ReadmeTemplate is a path of template used to generate README.md instead of the default one, setting it implies Readme
+var ReadmeTemplate string
+
+// Archive is a path of tarball into which exported configuration is packed, nothing is packed when empty
+var Archive string
+
+// ArchiveManifest is a path of manifest file added to the archive
+var ArchiveManifest string
+
+// ArchiveAPIResponses means that API responses received during the export are recorded and added to the archive
+var ArchiveAPIResponses bool
+
+// OriginsInventory means that origins.tf listing origins referenced by objects exported in the run is written into the work path
+var OriginsInventory bool
+
+// Checksums means that SHA256SUMS covering all files in the work path is written after the export
+var Checksums bool
+
+// SignKey is a path of minisign secret key with which SHA256SUMS is signed, setting it implies Checksums
+var SignKey string
+
+// ScanSecrets is a mode of scanning generated content for secrets, either 'fail' or 'redact', scan is skipped when empty
+var ScanSecrets string
+
+// Merge means that re-exported configuration is three-way merged with local edits instead of overwriting them
+var Merge bool
+
+// Status means that instead of exporting, the configuration recorded in the workspace state is compared with the current state of the API
+var Status bool
+
+// Strict means that the export fails if any warning about skipped, unsupported or guessed parts of exported objects is reported
+var Strict bool
+
+// Comments are 'key=value' pairs of metadata, e.g. owner or ticket, added as comments to every generated resource block
+var Comments []string
+
+// OutputSink is where generated files are written, either 'dir', 'stdout' or 'zip:<file>', they are written into the work path when empty
+var OutputSink string
+
+// OutputTemplate is a pattern of paths of generated files, e.g. '{{.Product}}/{{.Name}}/{{.File}}', files keep their names when empty
+var OutputTemplate string
+
+// LineEndings are line endings of generated files, either 'lf' or 'crlf', files use 'lf' when empty
+var LineEndings string
+
+// PageSizes are sizes of pages of list calls given with page-size flag keyed by API, the size used by all APIs is stored
+// under an empty key, APIs use their default page sizes when empty
+var PageSizes map[string]int
+
+// Only are addresses of blocks, e.g. akamai_dns_record.www, or paths of files relative to the work path, to which
+// changes of the export are limited, the rest of the work path stays untouched; all generated files are written when empty
+var Only []string
+
+// VarNaming is a naming convention of generated variables, either 'snake', 'camel' or 'prefix=<prefix>', variables keep
+// names given by templates when empty
+var VarNaming string
+
+// Retries is the number of times a paginated list call failed with a transient error, such as 502, is retried before
+// the export fails
+var Retries = 3
+
+// RetryBackoff is the delay before the first retry of a failed list call, doubled with every next retry
+var RetryBackoff = time.Second
@@ -106,3 +106,41 @@ func EscapeQuotedStringLit(s string) string {
 	}
 	return buf.String()
 }
+
+// SplitList returns values of a string slice flag with comma separated values split into separate items,
+// so that both '--flag a --flag b' and '--flag a,b' can be used
+func SplitList(values []string) []string {
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// ParseComment returns key and value of the 'key=value' pair given with add-comment flag
+func ParseComment(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.ContainsAny(s, "\r\n") {
+		return "", "", fmt.Errorf("comment '%s' is not in 'key=value' format", s)
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+// ResourceComments returns comment lines with metadata given with add-comment flag, they are meant to be placed before
+// every resource block
+// USAGE EXAMPLE: {{comments}}resource "akamai_property" "{{.PropertyResourceName}}" {
+func ResourceComments() string {
+	var buf strings.Builder
+	for _, c := range Comments {
+		key, value, err := ParseComment(c)
+		if err != nil {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf("# %s: %s\n", key, value))
+	}
+	return buf.String()
+}
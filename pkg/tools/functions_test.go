@@ -30,6 +30,11 @@ func TestToList(t *testing.T) {
 	assert.Equal(t, "\"this\", \"is\", \"a\", \"list\", \"of\", \"strings\"", ToList(tests))
 }
 
+func TestSplitList(t *testing.T) {
+	assert.Equal(t, []string{"dns", "gtm", "property"}, SplitList([]string{"dns,gtm", " property ", ""}))
+	assert.Empty(t, SplitList(nil))
+}
+
 func TestEscapeTFName(t *testing.T) {
 	tests := []string{"This is a test", "This Is A Test", "123 This is a test", "!This is a test!", "This is a test!", "This_is-a$test!"}
 	expected := []string{"this_is_a_test", "this_is_a_test", "ak_123_this_is_a_test", "this_is_a_test", "this_is_a_test", "this_isatest"}
@@ -81,3 +86,65 @@ func TestEscapeQuotedStringLit(t *testing.T) {
 		})
 	}
 }
+
+func TestParseComment(t *testing.T) {
+	tests := map[string]struct {
+		comment     string
+		expectKey   string
+		expectValue string
+		withError   bool
+	}{
+		"key and value": {
+			comment:     "owner=team-a",
+			expectKey:   "owner",
+			expectValue: "team-a",
+		},
+		"spaces are trimmed": {
+			comment:     " ticket = ABC-123 ",
+			expectKey:   "ticket",
+			expectValue: "ABC-123",
+		},
+		"value with equal sign": {
+			comment:     "note=a=b",
+			expectKey:   "note",
+			expectValue: "a=b",
+		},
+		"empty value": {
+			comment:   "environment=",
+			expectKey: "environment",
+		},
+		"missing equal sign": {
+			comment:   "owner",
+			withError: true,
+		},
+		"empty key": {
+			comment:   "=team-a",
+			withError: true,
+		},
+		"new line": {
+			comment:   "owner=team-a\nresource",
+			withError: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, value, err := ParseComment(test.comment)
+			if test.withError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectKey, key)
+			assert.Equal(t, test.expectValue, value)
+		})
+	}
+}
+
+func TestResourceComments(t *testing.T) {
+	defer func() { Comments = nil }()
+
+	assert.Equal(t, "", ResourceComments())
+
+	Comments = []string{"owner=team-a", "ticket=ABC-123"}
+	assert.Equal(t, "# owner: team-a\n# ticket: ABC-123\n", ResourceComments())
+}
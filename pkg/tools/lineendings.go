@@ -0,0 +1,31 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Line endings of generated files accepted by line-endings flag
+const (
+	LineEndingsLF   = "lf"
+	LineEndingsCRLF = "crlf"
+)
+
+// ValidateLineEndings checks that line endings are either 'lf' or 'crlf', empty value means 'lf'
+func ValidateLineEndings(endings string) error {
+	switch endings {
+	case "", LineEndingsLF, LineEndingsCRLF:
+		return nil
+	}
+	return fmt.Errorf("line endings '%s' are not supported, expected one of: %s, %s", endings, LineEndingsLF, LineEndingsCRLF)
+}
+
+// ApplyLineEndings normalizes line endings of generated text content, which may mix them when templates are checked out
+// with CRLF line endings, into the ones given with line-endings flag
+func ApplyLineEndings(content []byte) []byte {
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if LineEndings == LineEndingsCRLF {
+		content = bytes.ReplaceAll(content, []byte("\n"), []byte("\r\n"))
+	}
+	return content
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLineEndings(t *testing.T) {
+	assert.NoError(t, ValidateLineEndings(""))
+	assert.NoError(t, ValidateLineEndings(LineEndingsLF))
+	assert.NoError(t, ValidateLineEndings(LineEndingsCRLF))
+	assert.EqualError(t, ValidateLineEndings("cr"), "line endings 'cr' are not supported, expected one of: lf, crlf")
+}
+
+func TestApplyLineEndings(t *testing.T) {
+	tests := map[string]struct {
+		endings  string
+		content  string
+		expected string
+	}{
+		"mixed endings normalized to lf": {
+			content:  "resource \"a\" \"b\" {\r\n  c = 1\n}\r\n",
+			expected: "resource \"a\" \"b\" {\n  c = 1\n}\n",
+		},
+		"lf endings": {
+			endings:  LineEndingsLF,
+			content:  "a\nb\n",
+			expected: "a\nb\n",
+		},
+		"mixed endings normalized to crlf": {
+			endings:  LineEndingsCRLF,
+			content:  "a\r\nb\nc",
+			expected: "a\r\nb\r\nc",
+		},
+		"escaped line endings kept": {
+			endings:  LineEndingsCRLF,
+			content:  "a = \"b\\r\\nc\"\n",
+			expected: "a = \"b\\r\\nc\"\r\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			LineEndings = test.endings
+			defer func() { LineEndings = "" }()
+			assert.Equal(t, test.expected, string(ApplyLineEndings([]byte(test.content))))
+		})
+	}
+}
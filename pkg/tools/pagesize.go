@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// APIs whose list calls are paginated, their names are used to override the page size of a single API
+const (
+	PageSizeCloudlets = "cloudlets"
+	PageSizeDNS       = "dns"
+)
+
+// pageSizeLimits holds bounds of page sizes accepted by each API, max 0 means that the API has no upper bound
+var pageSizeLimits = map[string]struct{ min, max int }{
+	PageSizeCloudlets: {min: 1, max: 1000},
+	PageSizeDNS:       {min: 1},
+}
+
+// ParsePageSizes returns page sizes given with page-size flag, which is either a size used by all APIs or 'api=size'
+// overriding it for a single API; the default size is stored under an empty key and every size is checked against
+// bounds of APIs it applies to
+func ParsePageSizes(values []string) (map[string]int, error) {
+	sizes := make(map[string]int, len(values))
+	for _, value := range values {
+		api, size := "", value
+		if i := strings.Index(value, "="); i >= 0 {
+			api, size = strings.TrimSpace(value[:i]), value[i+1:]
+			if _, ok := pageSizeLimits[api]; !ok {
+				return nil, fmt.Errorf("page size '%s' is given for unknown API '%s', expected one of: %s", value, api, strings.Join(pageSizeAPIs(), ", "))
+			}
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil {
+			return nil, fmt.Errorf("page size '%s' is not a number", value)
+		}
+		sizes[api] = n
+	}
+	for _, api := range pageSizeAPIs() {
+		size, ok := sizes[api]
+		if !ok {
+			if size, ok = sizes[""]; !ok {
+				continue
+			}
+		}
+		limits := pageSizeLimits[api]
+		if size < limits.min || (limits.max > 0 && size > limits.max) {
+			return nil, fmt.Errorf("page size %d is out of bounds of %s API, expected %s", size, api, formatPageSizeLimits(limits.min, limits.max))
+		}
+	}
+	return sizes, nil
+}
+
+// PageSize returns size of pages of list calls of the API, def is returned when the size was not set with page-size flag
+func PageSize(api string, def int) int {
+	if size, ok := PageSizes[api]; ok {
+		return size
+	}
+	if size, ok := PageSizes[""]; ok {
+		return size
+	}
+	return def
+}
+
+func pageSizeAPIs() []string {
+	apis := make([]string, 0, len(pageSizeLimits))
+	for api := range pageSizeLimits {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+	return apis
+}
+
+func formatPageSizeLimits(min, max int) string {
+	if max == 0 {
+		return fmt.Sprintf("at least %d", min)
+	}
+	return fmt.Sprintf("%d to %d", min, max)
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageSizes(t *testing.T) {
+	tests := map[string]struct {
+		values    []string
+		expect    map[string]int
+		withError string
+	}{
+		"no sizes": {
+			expect: map[string]int{},
+		},
+		"size of all APIs": {
+			values: []string{"500"},
+			expect: map[string]int{"": 500},
+		},
+		"override of single API": {
+			values: []string{"200", "dns = 5000"},
+			expect: map[string]int{"": 200, PageSizeDNS: 5000},
+		},
+		"override allows default size out of bounds of other API": {
+			values: []string{"5000", "cloudlets=1000"},
+			expect: map[string]int{"": 5000, PageSizeCloudlets: 1000},
+		},
+		"default size out of bounds": {
+			values:    []string{"5000"},
+			withError: "page size 5000 is out of bounds of cloudlets API, expected 1 to 1000",
+		},
+		"override out of bounds": {
+			values:    []string{"dns=0"},
+			withError: "page size 0 is out of bounds of dns API, expected at least 1",
+		},
+		"unknown API": {
+			values:    []string{"papi=100"},
+			withError: "page size 'papi=100' is given for unknown API 'papi', expected one of: cloudlets, dns",
+		},
+		"not a number": {
+			values:    []string{"cloudlets=many"},
+			withError: "page size 'cloudlets=many' is not a number",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sizes, err := ParsePageSizes(test.values)
+			if test.withError != "" {
+				assert.EqualError(t, err, test.withError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, sizes)
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	defer func() { PageSizes = nil }()
+	assert.Equal(t, 1000, PageSize(PageSizeCloudlets, 1000))
+
+	PageSizes = map[string]int{"": 200, PageSizeDNS: 5000}
+	assert.Equal(t, 200, PageSize(PageSizeCloudlets, 1000))
+	assert.Equal(t, 5000, PageSize(PageSizeDNS, 0))
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Naming conventions of generated variables accepted by var-naming flag
+const (
+	VarNamingSnake  = "snake"
+	VarNamingCamel  = "camel"
+	VarNamingPrefix = "prefix="
+)
+
+var variablePrefix = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// ValidateVarNaming checks that naming is either 'snake', 'camel' or 'prefix=<prefix>' with prefix which can start
+// a terraform identifier, empty value keeps names given by templates
+func ValidateVarNaming(naming string) error {
+	switch {
+	case naming == "", naming == VarNamingSnake, naming == VarNamingCamel:
+		return nil
+	case strings.HasPrefix(naming, VarNamingPrefix):
+		if prefix := strings.TrimPrefix(naming, VarNamingPrefix); !variablePrefix.MatchString(prefix) {
+			return fmt.Errorf("prefix '%s' is not a valid start of variable name", prefix)
+		}
+		return nil
+	}
+	return fmt.Errorf("naming '%s' is not supported, expected one of: %s, %s, %s<prefix>", naming, VarNamingSnake, VarNamingCamel, VarNamingPrefix)
+}
+
+// VariableName returns name of generated variable following the convention given with var-naming flag, e.g. edgerc_path
+// becomes edgercPath with 'camel' and akamai_edgerc_path with 'prefix=akamai_'
+func VariableName(name string) string {
+	switch {
+	case VarNaming == VarNamingSnake:
+		return snakeCase(name)
+	case VarNaming == VarNamingCamel:
+		return camelCase(name)
+	case strings.HasPrefix(VarNaming, VarNamingPrefix):
+		if prefix := strings.TrimPrefix(VarNaming, VarNamingPrefix); !strings.HasPrefix(name, prefix) {
+			return prefix + name
+		}
+	}
+	return name
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '-' {
+			r = '_'
+		}
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func camelCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	if len(words) == 0 {
+		return name
+	}
+	var b strings.Builder
+	for i, w := range words {
+		runes := []rune(w)
+		if i == 0 {
+			runes[0] = unicode.ToLower(runes[0])
+		} else {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateVarNaming(t *testing.T) {
+	assert.NoError(t, ValidateVarNaming(""))
+	assert.NoError(t, ValidateVarNaming(VarNamingSnake))
+	assert.NoError(t, ValidateVarNaming(VarNamingCamel))
+	assert.NoError(t, ValidateVarNaming("prefix=akamai_"))
+	assert.EqualError(t, ValidateVarNaming("prefix=1st"), "prefix '1st' is not a valid start of variable name")
+	assert.EqualError(t, ValidateVarNaming("prefix="), "prefix '' is not a valid start of variable name")
+	assert.EqualError(t, ValidateVarNaming("kebab"), "naming 'kebab' is not supported, expected one of: snake, camel, prefix=<prefix>")
+}
+
+func TestVariableName(t *testing.T) {
+	tests := map[string]struct {
+		naming   string
+		names    []string
+		expected []string
+	}{
+		"names kept": {
+			names:    []string{"edgerc_path", "contractid", "groupId"},
+			expected: []string{"edgerc_path", "contractid", "groupId"},
+		},
+		"snake": {
+			naming:   VarNamingSnake,
+			names:    []string{"edgerc_path", "contractid", "groupId", "matchRules-v2", "ipv6Address"},
+			expected: []string{"edgerc_path", "contractid", "group_id", "match_rules_v2", "ipv6_address"},
+		},
+		"camel": {
+			naming:   VarNamingCamel,
+			names:    []string{"edgerc_path", "contractid", "groupId", "config-section", "_env"},
+			expected: []string{"edgercPath", "contractid", "groupId", "configSection", "env"},
+		},
+		"prefix": {
+			naming:   "prefix=akamai_",
+			names:    []string{"edgerc_path", "akamai_env"},
+			expected: []string{"akamai_edgerc_path", "akamai_env"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			VarNaming = test.naming
+			defer func() { VarNaming = "" }()
+			for i, n := range test.names {
+				assert.Equal(t, test.expected[i], VariableName(n))
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+resource "akamai_property" "example-com" {
+  name        = "example.com"
+  contract_id = "ctr_1-1TJZH5"
+  group_id    = "grp_1"
+  product_id  = "prd_SPM"
+  rules       = data.akamai_property_rules_template.rules.json
+}
@@ -0,0 +1,11 @@
+resource "akamai_cloudlets_application_load_balancer" "load_balancer_alb_1" {
+  origin_id      = "alb_1"
+  description    = "edited by hand"
+  balancing_type = "WEIGHTED"
+}
+
+resource "akamai_cloudlets_application_load_balancer_activation" "load_balancer_activation_alb_1" {
+  origin_id = akamai_cloudlets_application_load_balancer.load_balancer_alb_1.origin_id
+  network   = var.env
+  version   = akamai_cloudlets_application_load_balancer.load_balancer_alb_1.version
+}
@@ -0,0 +1,8 @@
+data "akamai_cloudlets_application_load_balancer_match_rule" "match_rules_alb" {
+  match_rules {
+    name = "r1"
+    forward_settings {
+      origin_id = "alb_1"
+    }
+  }
+}
@@ -0,0 +1,14 @@
+variable "config_section" {
+  type    = string
+  default = "default"
+}
+
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "env" {
+  type    = string
+  default = "staging"
+}
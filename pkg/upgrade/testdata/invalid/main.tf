@@ -0,0 +1 @@
+resource "akamai_property" "broken" {
@@ -0,0 +1,7 @@
+resource "akamai_property" "example-com" {
+  name     = "example.com"
+  contract = "ctr_1-1TJZH5"
+  group    = "grp_1"
+  product  = "prd_SPM"
+  rules    = data.akamai_property_rules_template.rules.json
+}
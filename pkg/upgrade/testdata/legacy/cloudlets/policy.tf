@@ -0,0 +1,54 @@
+terraform {
+  required_providers {
+    akamai = {
+      source  = "akamai/akamai"
+      version = ">= 2.0.0"
+    }
+  }
+  required_version = ">= 0.13"
+}
+
+provider "akamai" {
+  edgerc         = var.edgerc_path
+  config_section = var.config_section
+}
+
+variable "edgerc_path" {
+  type    = string
+  default = "~/.edgerc"
+}
+
+variable "env" {
+  type    = string
+  default = "staging"
+}
+
+resource "akamai_cloudlets_application_load_balancer" "load_balancer_alb_1" {
+  origin_id      = "alb_1"
+  description    = "edited by hand"
+  balancing_type = "WEIGHTED"
+}
+
+resource "akamai_cloudlets_application_load_balancer_activation" "load_balancer_activation_alb_1" {
+  origin_id = akamai_cloudlets_application_load_balancer.load_balancer_alb_1.origin_id
+  network   = var.env
+  version   = akamai_cloudlets_application_load_balancer.load_balancer_alb_1.version
+}
+
+resource "akamai_cloudlets_policy" "policy" {
+  name              = "my_policy"
+  cloudlet_code     = "ALB"
+  description       = ""
+  group_id          = "12345"
+  match_rule_format = "1.0"
+  match_rules       = data.akamai_cloudlets_application_load_balancer_match_rule.match_rules_alb.json
+}
+
+data "akamai_cloudlets_application_load_balancer_match_rule" "match_rules_alb" {
+  match_rules {
+    name = "r1"
+    forward_settings {
+      origin_id = "alb_1"
+    }
+  }
+}
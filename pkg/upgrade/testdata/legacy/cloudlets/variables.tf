@@ -0,0 +1,4 @@
+variable "config_section" {
+  type    = string
+  default = "default"
+}
@@ -0,0 +1,26 @@
+data "akamai_group" "group" {
+  name     = "my group"
+  contract = "ctr_1-1TJZH5"
+}
+
+resource "akamai_cp_code" "cp_code" {
+  name     = "example.com"
+  contract = "ctr_1-1TJZH5"
+  group    = data.akamai_group.group.id
+  product  = "prd_SPM"
+}
+
+# edited: hostname kept on a separate edge hostname
+resource "akamai_property" "example-com" {
+  name     = "example.com"
+  contract = "ctr_1-1TJZH5"
+  group    = data.akamai_group.group.id
+  product  = "prd_SPM" # changed by hand
+  rules    = data.akamai_property_rules_template.rules.json
+}
+
+resource "akamai_property_activation" "example-com" {
+  property = akamai_property.example-com.id
+  network  = "STAGING"
+  version  = akamai_property.example-com.latest_version
+}
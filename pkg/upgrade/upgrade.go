@@ -0,0 +1,370 @@
+// Package upgrade contains code for rewriting configuration generated by older versions of the CLI to the current file
+// structure and resource schemas
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/workspace"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/urfave/cli/v2"
+)
+
+type (
+	// Change describes a change of a file made by the upgrade
+	Change struct {
+		File   string
+		Change string
+	}
+
+	// rename is an attribute of a resource or data source type renamed by a newer provider version
+	rename struct {
+		blockType string
+		typeName  string
+		from      string
+		to        string
+	}
+
+	// move places blocks of the types into the file of the current layout
+	move struct {
+		file  string
+		match func(block *hclwrite.Block) bool
+	}
+
+	// directory holds content of .tf files of a directory keyed by file names
+	directory struct {
+		path  string
+		files map[string][]byte
+	}
+)
+
+var (
+	// renames are attributes renamed in provider 1.0.0, older versions of the CLI generated their deprecated names
+	renames = []rename{
+		{blockType: "resource", typeName: "akamai_property", from: "contract", to: "contract_id"},
+		{blockType: "resource", typeName: "akamai_property", from: "group", to: "group_id"},
+		{blockType: "resource", typeName: "akamai_property", from: "product", to: "product_id"},
+		{blockType: "resource", typeName: "akamai_cp_code", from: "contract", to: "contract_id"},
+		{blockType: "resource", typeName: "akamai_cp_code", from: "group", to: "group_id"},
+		{blockType: "resource", typeName: "akamai_cp_code", from: "product", to: "product_id"},
+		{blockType: "resource", typeName: "akamai_edge_hostname", from: "contract", to: "contract_id"},
+		{blockType: "resource", typeName: "akamai_edge_hostname", from: "group", to: "group_id"},
+		{blockType: "resource", typeName: "akamai_edge_hostname", from: "product", to: "product_id"},
+		{blockType: "resource", typeName: "akamai_property_activation", from: "property", to: "property_id"},
+		{blockType: "data", typeName: "akamai_group", from: "name", to: "group_name"},
+		{blockType: "data", typeName: "akamai_group", from: "contract", to: "contract_id"},
+	}
+
+	// cloudletsPolicyFile is the file holding the policy, older versions of the CLI exported the whole policy into it
+	cloudletsPolicyFile = "policy.tf"
+
+	// cloudletsMoves place blocks of a cloudlets policy exported into a single file into files of the current layout
+	cloudletsMoves = []move{
+		{file: "match-rules.tf", match: func(block *hclwrite.Block) bool {
+			labels := block.Labels()
+			return block.Type() == "data" && len(labels) == 2 &&
+				strings.HasPrefix(labels[0], "akamai_cloudlets_") && strings.HasSuffix(labels[0], "_match_rule")
+		}},
+		{file: "load-balancer.tf", match: func(block *hclwrite.Block) bool {
+			labels := block.Labels()
+			return block.Type() == "resource" && len(labels) == 2 &&
+				strings.HasPrefix(labels[0], "akamai_cloudlets_application_load_balancer")
+		}},
+		{file: "variables.tf", match: func(block *hclwrite.Block) bool {
+			return block.Type() == "variable"
+		}},
+	}
+
+	// ErrUpgradingWorkspace is returned when configuration cannot be read, parsed or saved
+	ErrUpgradingWorkspace = exitcode.New(exitcode.Template, "unable to upgrade workspace")
+)
+
+// CmdUpgradeWorkspace is an entrypoint to upgrade-workspace command
+func CmdUpgradeWorkspace(c *cli.Context) error {
+	term := terminal.Get(c.Context)
+	dryRun := c.Bool("dry-run")
+	changes, err := Upgrade(c.Args().First(), dryRun)
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error upgrading workspace: %s", err)), exitcode.Of(err))
+	}
+	for _, change := range changes {
+		term.Printf("%s: %s\n", change.File, change.Change)
+	}
+	if dryRun {
+		term.Printf("%d change(s) would be made\n", len(changes))
+		return nil
+	}
+	term.Printf("Made %d change(s)\n", len(changes))
+	return nil
+}
+
+// Upgrade rewrites configuration generated by older versions of the CLI into root or any of its subdirectories to the
+// current file structure and resource schemas. Attributes are renamed and blocks moved with their content, so that
+// edits made to the configuration are kept. Base files of the workspace metadata are upgraded the same way, so that
+// the upgrade is not taken for local edits when the workspace is re-exported with --merge. With dryRun set, changes
+// are only returned.
+func Upgrade(root string, dryRun bool) ([]Change, error) {
+	changes, err := upgradeTree(root, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Join(root, workspace.MetadataDir, "base")
+	if _, err := os.Stat(base); err == nil && !dryRun {
+		if _, err := upgradeTree(base, false); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func upgradeTree(root string, dryRun bool) ([]Change, error) {
+	dirs, err := load(root)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for _, dir := range dirs {
+		before := make(map[string][]byte, len(dir.files))
+		for name, content := range dir.files {
+			before[name] = content
+		}
+
+		for _, name := range dir.names() {
+			content, renamed, err := renameAttributes(dir.files[name])
+			if err != nil {
+				return nil, err
+			}
+			dir.files[name] = content
+			for _, r := range renamed {
+				changes = append(changes, Change{File: filepath.Join(dir.path, name), Change: r})
+			}
+		}
+		moved, err := dir.splitCloudletsPolicy()
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, moved...)
+
+		if dryRun {
+			continue
+		}
+		for _, name := range dir.names() {
+			if bytes.Equal(before[name], dir.files[name]) {
+				continue
+			}
+			if err := os.WriteFile(filepath.Join(dir.path, name), hclwrite.Format(dir.files[name]), 0644); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrUpgradingWorkspace, err)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// renameAttributes renames deprecated attributes of resources and data sources in place, so that their values,
+// comments and order are kept; attributes are not renamed when the block already sets the new name
+func renameAttributes(content []byte) ([]byte, []string, error) {
+	f, diags := hclsyntax.ParseConfig(content, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUpgradingWorkspace, diags.Error())
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return content, nil, nil
+	}
+
+	type replacement struct {
+		start, end int
+		name       string
+	}
+	var replacements []replacement
+	var renamed []string
+	for _, block := range body.Blocks {
+		if len(block.Labels) != 2 {
+			continue
+		}
+		for _, r := range renames {
+			if block.Type != r.blockType || block.Labels[0] != r.typeName {
+				continue
+			}
+			attr, ok := block.Body.Attributes[r.from]
+			if !ok {
+				continue
+			}
+			if _, ok := block.Body.Attributes[r.to]; ok {
+				continue
+			}
+			replacements = append(replacements, replacement{start: attr.NameRange.Start.Byte, end: attr.NameRange.End.Byte, name: r.to})
+			address := strings.Join(block.Labels, ".")
+			if block.Type == "data" {
+				address = "data." + address
+			}
+			renamed = append(renamed, fmt.Sprintf("renamed %s.%s to %s", address, r.from, r.to))
+		}
+	}
+	if len(replacements) == 0 {
+		return content, nil, nil
+	}
+
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+	result := append([]byte(nil), content...)
+	for _, r := range replacements {
+		result = append(result[:r.start], append([]byte(r.name), result[r.end:]...)...)
+	}
+	return result, renamed, nil
+}
+
+// splitCloudletsPolicy moves match rules, load balancers and variables of a cloudlets policy exported into a single
+// policy.tf into files of the current layout, blocks already present in the target files are left in place
+func (d *directory) splitCloudletsPolicy() ([]Change, error) {
+	content, ok := d.files[cloudletsPolicyFile]
+	if !ok {
+		return nil, nil
+	}
+	policy, err := parse(d.path, cloudletsPolicyFile, content)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Body().FirstMatchingBlock("resource", []string{"akamai_cloudlets_policy", "policy"}) == nil {
+		return nil, nil
+	}
+
+	var changes []Change
+	targets := make(map[string]*hclwrite.File)
+	for _, block := range policy.Body().Blocks() {
+		for _, m := range cloudletsMoves {
+			if !m.match(block) {
+				continue
+			}
+			target, ok := targets[m.file]
+			if !ok {
+				if target, err = parse(d.path, m.file, d.files[m.file]); err != nil {
+					return nil, err
+				}
+				targets[m.file] = target
+			}
+			address := strings.Join(block.Labels(), ".")
+			if block.Type() != "resource" {
+				address = block.Type() + "." + address
+			}
+			if target.Body().FirstMatchingBlock(block.Type(), block.Labels()) != nil {
+				break
+			}
+			if len(target.Body().Blocks()) > 0 || len(target.Bytes()) > 0 {
+				target.Body().AppendNewline()
+			}
+			target.Body().AppendBlock(block)
+			policy.Body().RemoveBlock(block)
+			changes = append(changes, Change{
+				File:   filepath.Join(d.path, cloudletsPolicyFile),
+				Change: fmt.Sprintf("moved %s to %s", address, m.file),
+			})
+			break
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	d.files[cloudletsPolicyFile] = squeezeBlankLines(policy.Bytes())
+	for name, target := range targets {
+		d.files[name] = target.Bytes()
+	}
+	return changes, nil
+}
+
+// squeezeBlankLines collapses blank lines left by removed blocks into a single one and removes them from the end of
+// the content, newlines within strings and heredocs are kept
+func squeezeBlankLines(content []byte) []byte {
+	tokens, diags := hclsyntax.LexConfig(content, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return content
+	}
+	type cut struct{ start, end int }
+	var cuts []cut
+	for i := 0; i < len(tokens); {
+		if tokens[i].Type != hclsyntax.TokenNewline {
+			i++
+			continue
+		}
+		j := i
+		for j < len(tokens) && tokens[j].Type == hclsyntax.TokenNewline {
+			j++
+		}
+		switch {
+		case j < len(tokens) && tokens[j].Type == hclsyntax.TokenEOF:
+			cuts = append(cuts, cut{start: tokens[i].Range.End.Byte, end: len(content)})
+		case j-i > 2:
+			cuts = append(cuts, cut{start: tokens[i+1].Range.End.Byte, end: tokens[j-1].Range.End.Byte})
+		}
+		i = j
+	}
+	result := make([]byte, 0, len(content))
+	last := 0
+	for _, c := range cuts {
+		result = append(result, content[last:c.start]...)
+		last = c.end
+	}
+	return append(result, content[last:]...)
+}
+
+func (d *directory) names() []string {
+	names := make([]string, 0, len(d.files))
+	for name := range d.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parse(dir, name string, content []byte) (*hclwrite.File, error) {
+	f, diags := hclwrite.ParseConfig(content, filepath.Join(dir, name), hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%w: %s", ErrUpgradingWorkspace, diags.Error())
+	}
+	return f, nil
+}
+
+// load reads all .tf files in root and its subdirectories, grouped by directory
+func load(root string) ([]*directory, error) {
+	var dirs []*directory
+	byPath := make(map[string]*directory)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && workspace.SkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			dir := &directory{path: path, files: make(map[string][]byte)}
+			dirs = append(dirs, dir)
+			byPath[filepath.Clean(path)] = dir
+			return nil
+		}
+		if filepath.Ext(path) != ".tf" {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		byPath[filepath.Dir(path)].files[d.Name()] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUpgradingWorkspace, err)
+	}
+	return dirs, nil
+}
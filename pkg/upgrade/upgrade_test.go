@@ -0,0 +1,113 @@
+package upgrade
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/cli-terraform/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgrade(t *testing.T) {
+	dir := copyDir(t, "./testdata/legacy")
+	policy := filepath.Join(dir, "cloudlets", "policy.tf")
+	property := filepath.Join(dir, "property", "property.tf")
+	expected := []Change{
+		{File: policy, Change: "moved variable.edgerc_path to variables.tf"},
+		{File: policy, Change: "moved variable.env to variables.tf"},
+		{File: policy, Change: "moved akamai_cloudlets_application_load_balancer.load_balancer_alb_1 to load-balancer.tf"},
+		{File: policy, Change: "moved akamai_cloudlets_application_load_balancer_activation.load_balancer_activation_alb_1 to load-balancer.tf"},
+		{File: policy, Change: "moved data.akamai_cloudlets_application_load_balancer_match_rule.match_rules_alb to match-rules.tf"},
+		{File: property, Change: "renamed data.akamai_group.group.name to group_name"},
+		{File: property, Change: "renamed data.akamai_group.group.contract to contract_id"},
+		{File: property, Change: "renamed akamai_cp_code.cp_code.contract to contract_id"},
+		{File: property, Change: "renamed akamai_cp_code.cp_code.group to group_id"},
+		{File: property, Change: "renamed akamai_cp_code.cp_code.product to product_id"},
+		{File: property, Change: "renamed akamai_property.example-com.contract to contract_id"},
+		{File: property, Change: "renamed akamai_property.example-com.group to group_id"},
+		{File: property, Change: "renamed akamai_property.example-com.product to product_id"},
+		{File: property, Change: "renamed akamai_property_activation.example-com.property to property_id"},
+	}
+
+	changes, err := Upgrade(dir, true)
+	require.NoError(t, err)
+	assert.Equal(t, expected, changes)
+	testutils.AssertFiles(t, "./testdata/legacy", dir, "cloudlets/policy.tf", "cloudlets/variables.tf", "property/property.tf")
+	assert.NoFileExists(t, filepath.Join(dir, "cloudlets", "match-rules.tf"))
+
+	changes, err = Upgrade(dir, false)
+	require.NoError(t, err)
+	assert.Equal(t, expected, changes)
+	testutils.AssertFiles(t, "./testdata/expected", dir,
+		"cloudlets/policy.tf", "cloudlets/variables.tf", "cloudlets/match-rules.tf", "cloudlets/load-balancer.tf",
+		"property/property.tf", ".cli-terraform/base/property/property.tf")
+
+	// upgraded configuration is left untouched
+	changes, err = Upgrade(dir, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestUpgradeErrors(t *testing.T) {
+	for name, dir := range map[string]string{
+		"invalid configuration": "./testdata/invalid",
+		"missing directory":     "./testdata/missing",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Upgrade(dir, true)
+			assert.True(t, errors.Is(err, ErrUpgradingWorkspace), "expected: %s; got: %s", ErrUpgradingWorkspace, err)
+		})
+	}
+}
+
+func TestSqueezeBlankLines(t *testing.T) {
+	tests := map[string]struct {
+		content  string
+		expected string
+	}{
+		"blank lines between blocks": {
+			content:  "a = 1\n\n\n  \n\nb = 2\n",
+			expected: "a = 1\n\nb = 2\n",
+		},
+		"blank lines at the end": {
+			content:  "a = 1\n\n\n",
+			expected: "a = 1\n",
+		},
+		"blank lines in heredoc are kept": {
+			content:  "a = <<EOT\nx\n\n\n\ny\nEOT\n",
+			expected: "a = <<EOT\nx\n\n\n\ny\nEOT\n",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, string(squeezeBlankLines([]byte(test.content))))
+		})
+	}
+}
+
+func copyDir(t *testing.T, src string) string {
+	dst := t.TempDir()
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(dst, filepath.Dir(rel)), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), content, 0644)
+	})
+	require.NoError(t, err)
+	return dst
+}
@@ -0,0 +1,116 @@
+// Package warnings contains code for collecting warnings, e.g. about objects skipped during export because they are not supported
+package warnings
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+)
+
+type (
+	// Warning describes a part of exported configuration which could not be fully exported
+	Warning struct {
+		Product string
+		Object  string
+		Reason  string
+	}
+
+	// Collector gathers warnings reported by providers during a single command run
+	Collector struct {
+		mu       sync.Mutex
+		warnings []Warning
+	}
+
+	ctxType string
+)
+
+var (
+	collectorCtx ctxType = "warnings"
+
+	// ErrStrict is returned in strict mode when any warning was reported during the export
+	ErrStrict = exitcode.New(exitcode.Unsupported, "strict mode: export is not lossless")
+)
+
+// NewCollector returns empty Collector
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add registers a new warning
+func (c *Collector) Add(w Warning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, w)
+}
+
+// Warnings returns all registered warnings in order of reporting
+func (c *Collector) Warnings() []Warning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]Warning, len(c.warnings))
+	copy(result, c.warnings)
+	return result
+}
+
+// String returns human-readable representation of the warning
+func (w Warning) String() string {
+	if w.Object == "" {
+		return fmt.Sprintf("%s: %s", w.Product, w.Reason)
+	}
+	return fmt.Sprintf("%s '%s': %s", w.Product, w.Object, w.Reason)
+}
+
+// WithCollector puts a Collector in context
+func WithCollector(ctx context.Context, c *Collector) context.Context {
+	return context.WithValue(ctx, collectorCtx, c)
+}
+
+// GetCollector retrieves a Collector from context, nil is returned if there is none
+func GetCollector(ctx context.Context) *Collector {
+	c, _ := ctx.Value(collectorCtx).(*Collector)
+	return c
+}
+
+// Report registers a warning in the collector stored in context
+// If there is no collector in context, the warning is printed immediately, so it is never lost
+func Report(ctx context.Context, w Warning) {
+	if c := GetCollector(ctx); c != nil {
+		c.Add(w)
+		return
+	}
+	terminal.Get(ctx).Writeln(color.YellowString("Warning: %s", w))
+}
+
+// PrintSummary prints all warnings registered in the collector stored in context
+func PrintSummary(ctx context.Context) {
+	c := GetCollector(ctx)
+	if c == nil {
+		return
+	}
+	reported := c.Warnings()
+	if len(reported) == 0 {
+		return
+	}
+	term := terminal.Get(ctx)
+	term.Writeln(color.YellowString("Export finished with %d warning(s):", len(reported)))
+	for _, w := range reported {
+		term.Writeln(color.YellowString("  * %s", w))
+	}
+}
+
+// Strict returns ErrStrict if any warning was registered in the collector stored in context,
+// the warnings themselves are listed by PrintSummary
+func Strict(ctx context.Context) error {
+	c := GetCollector(ctx)
+	if c == nil {
+		return nil
+	}
+	if reported := c.Warnings(); len(reported) > 0 {
+		return fmt.Errorf("%w: %d warning(s) reported", ErrStrict, len(reported))
+	}
+	return nil
+}
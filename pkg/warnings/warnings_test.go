@@ -0,0 +1,94 @@
+package warnings
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	color.NoColor = true
+	tests := map[string]struct {
+		withCollector bool
+		expectedLines []string
+	}{
+		"warnings are collected and summarized": {
+			withCollector: true,
+			expectedLines: []string{
+				"Export finished with 2 warning(s):",
+				"  * cloudlets 'test_policy': cloudlet type 'XX' is not supported",
+				"  * dns: objects could not be listed",
+			},
+		},
+		"warnings are printed immediately without collector": {
+			withCollector: false,
+			expectedLines: []string{
+				"Warning: cloudlets 'test_policy': cloudlet type 'XX' is not supported",
+				"Warning: dns: objects could not be listed",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			term := &terminal.Mock{}
+			for _, line := range test.expectedLines {
+				term.On("Writeln", []interface{}{line}).Return(0, nil).Once()
+			}
+			ctx := terminal.Context(context.Background(), term)
+			if test.withCollector {
+				ctx = WithCollector(ctx, NewCollector())
+			}
+
+			Report(ctx, Warning{Product: "cloudlets", Object: "test_policy", Reason: "cloudlet type 'XX' is not supported"})
+			Report(ctx, Warning{Product: "dns", Reason: "objects could not be listed"})
+			PrintSummary(ctx)
+
+			term.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	tests := map[string]struct {
+		withCollector bool
+		warnings      []Warning
+		withError     bool
+	}{
+		"no collector": {},
+		"no warnings": {
+			withCollector: true,
+		},
+		"warnings reported": {
+			withCollector: true,
+			warnings: []Warning{
+				{Product: "cloudlets", Object: "test_policy", Reason: "cloudlet type 'XX' is not supported"},
+				{Product: "dns", Reason: "objects could not be listed"},
+			},
+			withError: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.withCollector {
+				collector := NewCollector()
+				for _, w := range test.warnings {
+					collector.Add(w)
+				}
+				ctx = WithCollector(ctx, collector)
+			}
+			err := Strict(ctx)
+			if !test.withError {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ErrStrict), "expected: %s; got: %s", ErrStrict, err)
+			assert.Equal(t, "strict mode: export is not lossless: 2 warning(s) reported", err.Error())
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Package wizard contains code for exporting objects in interactive mode
+package wizard
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/akamai/cli-terraform/pkg/edgegrid"
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/akamai/cli-terraform/pkg/manifest"
+	"github.com/akamai/cli-terraform/pkg/progress"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// export describes the command selected by the user in the wizard
+type export struct {
+	command string
+	args    []string
+}
+
+var (
+	// ErrNotTTY is returned when interactive mode is requested without a terminal attached
+	ErrNotTTY = exitcode.New(exitcode.General, "interactive mode requires a terminal")
+	// ErrNoObjects is returned when there are no exportable objects of the selected product
+	ErrNoObjects = exitcode.New(exitcode.NotFound, "no exportable objects found")
+	// ErrAborted is returned when the user does not confirm running the export
+	ErrAborted = exitcode.New(exitcode.General, "export aborted")
+	// ErrUnknownCommand is returned when selected object cannot be exported with any of the available commands
+	ErrUnknownCommand = exitcode.New(exitcode.General, "unknown export command")
+)
+
+// CmdInteractive is an entrypoint to interactive mode, it guides the user through the export and runs it
+func CmdInteractive(c *cli.Context) error {
+	ctx := c.Context
+	clients := discovery.NewClients(edgegrid.GetSession(ctx))
+
+	e, err := runWizard(ctx, clients)
+	if err != nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("Error running interactive mode: %s", err)), exitcode.Of(err))
+	}
+	return runExport(c, e)
+}
+
+func runWizard(ctx context.Context, clients discovery.Clients) (*export, error) {
+	term := terminal.Get(ctx)
+	if !term.IsTTY() {
+		return nil, ErrNotTTY
+	}
+
+	product, err := term.Prompt("Select product to export", discovery.Products()...)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.Get(ctx).Start("Listing " + product + " objects")
+	objects, err := discovery.List(ctx, product, clients)
+	if err != nil {
+		progress.Get(ctx).Fail()
+		return nil, err
+	}
+	progress.Get(ctx).OK()
+
+	object, err := selectObject(term, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	tfWorkPath, err := selectWorkPath(term)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := selectOptions(term, product)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]string{"--tfworkpath", tfWorkPath}, options...)
+	args = append(args, object.Args...)
+	e := &export{command: object.Command, args: args}
+
+	confirmed, err := term.Confirm(fmt.Sprintf("Run 'akamai terraform %s %s'?", e.command, strings.Join(e.args, " ")), true)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, ErrAborted
+	}
+	return e, nil
+}
+
+// selectObject prompts for one of the objects which can be exported
+func selectObject(term terminal.Terminal, objects []manifest.Object) (*manifest.Object, error) {
+	var labels []string
+	selectable := make(map[string]manifest.Object)
+	for _, object := range objects {
+		if !object.Selected {
+			continue
+		}
+		label := object.Name
+		if object.ID != "" {
+			label = fmt.Sprintf("%s (%s)", object.Name, object.ID)
+		}
+		labels = append(labels, label)
+		selectable[label] = object
+	}
+	if len(labels) == 0 {
+		return nil, ErrNoObjects
+	}
+
+	label, err := term.Prompt("Select object to export", labels...)
+	if err != nil {
+		return nil, err
+	}
+	object := selectable[label]
+	return &object, nil
+}
+
+// selectWorkPath prompts for the target directory, creating it when it does not exist yet
+func selectWorkPath(term terminal.Terminal) (string, error) {
+	tfWorkPath, err := term.Prompt("Target directory for generated files (use . for current directory)")
+	if err != nil {
+		return "", err
+	}
+	tfWorkPath = filepath.FromSlash(tfWorkPath)
+
+	_, err = os.Stat(tfWorkPath)
+	if err == nil {
+		return tfWorkPath, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", exitcode.New(exitcode.IO, err.Error())
+	}
+	create, err := term.Confirm(fmt.Sprintf("Directory '%s' does not exist, create it?", tfWorkPath), true)
+	if err != nil {
+		return "", err
+	}
+	if !create {
+		return "", ErrAborted
+	}
+	if err := os.MkdirAll(tfWorkPath, 0755); err != nil {
+		return "", exitcode.New(exitcode.IO, err.Error())
+	}
+	return tfWorkPath, nil
+}
+
+// selectOptions prompts for additional flags of the export command of given product
+func selectOptions(term terminal.Terminal, product string) ([]string, error) {
+	switch product {
+	case discovery.ProductProperty:
+		latest, err := term.Confirm("Export latest version of the property?", true)
+		if err != nil || latest {
+			return nil, err
+		}
+		version, err := term.Prompt("Property version to export")
+		if err != nil {
+			return nil, err
+		}
+		return []string{"--version", version}, nil
+	case discovery.ProductDNS:
+		segment, err := term.Confirm("Group and segment records by name into separate config files?", false)
+		if err != nil || !segment {
+			return nil, err
+		}
+		return []string{"--segmentconfig"}, nil
+	}
+	return nil, nil
+}
+
+// runExport executes the selected export command within the current cli context
+func runExport(c *cli.Context, e *export) error {
+	cmd := c.App.Command(e.command)
+	if cmd == nil {
+		return cli.Exit(color.RedString(fmt.Sprintf("%s: %s", ErrUnknownCommand, e.command)), exitcode.Of(ErrUnknownCommand))
+	}
+
+	set := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	for _, f := range cmd.Flags {
+		if err := f.Apply(set); err != nil {
+			return err
+		}
+	}
+	if err := set.Parse(e.args); err != nil {
+		return err
+	}
+
+	cmdCtx := cli.NewContext(c.App, set, c)
+	cmdCtx.Command = cmd
+	return cmd.Action(cmdCtx)
+}
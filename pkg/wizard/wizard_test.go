@@ -0,0 +1,154 @@
+package wizard
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/dns"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v3/pkg/gtm"
+	"github.com/akamai/cli-terraform/pkg/discovery"
+	"github.com/akamai/cli/pkg/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	expectSelectProduct = func(t *terminal.Mock, product string) {
+		t.On("IsTTY").Return(true).Once()
+		t.On("Prompt", "Select product to export", discovery.Products()).Return(product, nil).Once()
+		t.On("Spinner").Return(t)
+		t.On("Start", "%s", []interface{}{"Listing " + product + " objects"}).Return().Once()
+	}
+
+	expectSelectWorkPath = func(t *terminal.Mock, path string) {
+		t.On("Prompt", "Target directory for generated files (use . for current directory)", []string(nil)).Return(path, nil).Once()
+	}
+)
+
+func TestRunWizard(t *testing.T) {
+	tmpDir := t.TempDir()
+	newDir := filepath.Join(tmpDir, "new")
+
+	tests := map[string]struct {
+		init      func(*terminal.Mock, *dns.Mock, *gtm.Mock)
+		expected  *export
+		withError error
+	}{
+		"export gtm domain": {
+			init: func(tm *terminal.Mock, _ *dns.Mock, g *gtm.Mock) {
+				expectSelectProduct(tm, discovery.ProductGTM)
+				g.On("ListDomains", mock.Anything).Return([]*gtm.DomainItem{{Name: "first.akadns.net"}, {Name: "second.akadns.net"}}, nil).Once()
+				tm.On("OK").Return().Once()
+				tm.On("Prompt", "Select object to export", []string{"first.akadns.net", "second.akadns.net"}).Return("second.akadns.net", nil).Once()
+				expectSelectWorkPath(tm, tmpDir)
+				tm.On("Confirm", "Run 'akamai terraform export-domain --tfworkpath "+tmpDir+" second.akadns.net'?", true).Return(true, nil).Once()
+			},
+			expected: &export{
+				command: "export-domain",
+				args:    []string{"--tfworkpath", tmpDir, "second.akadns.net"},
+			},
+		},
+		"export dns zone with options into new directory": {
+			init: func(tm *terminal.Mock, d *dns.Mock, _ *gtm.Mock) {
+				expectSelectProduct(tm, discovery.ProductDNS)
+				d.On("ListZones", mock.Anything, dns.ZoneListQueryArgs{ShowAll: true}).
+					Return(&dns.ZoneListResponse{Zones: []*dns.ZoneResponse{{Zone: "test.zone"}}}, nil).Once()
+				tm.On("OK").Return().Once()
+				tm.On("Prompt", "Select object to export", []string{"test.zone"}).Return("test.zone", nil).Once()
+				expectSelectWorkPath(tm, newDir)
+				tm.On("Confirm", "Directory '"+newDir+"' does not exist, create it?", true).Return(true, nil).Once()
+				tm.On("Confirm", "Group and segment records by name into separate config files?", false).Return(true, nil).Once()
+				tm.On("Confirm", mock.Anything, true).Return(true, nil).Once()
+			},
+			expected: &export{
+				command: "export-zone",
+				args:    []string{"--tfworkpath", newDir, "--segmentconfig", "--createconfig", "--configonly", "--importscript", "test.zone"},
+			},
+		},
+		"export not confirmed": {
+			init: func(tm *terminal.Mock, _ *dns.Mock, g *gtm.Mock) {
+				expectSelectProduct(tm, discovery.ProductGTM)
+				g.On("ListDomains", mock.Anything).Return([]*gtm.DomainItem{{Name: "test.akadns.net"}}, nil).Once()
+				tm.On("OK").Return().Once()
+				tm.On("Prompt", "Select object to export", []string{"test.akadns.net"}).Return("test.akadns.net", nil).Once()
+				expectSelectWorkPath(tm, tmpDir)
+				tm.On("Confirm", mock.Anything, true).Return(false, nil).Once()
+			},
+			withError: ErrAborted,
+		},
+		"no objects to export": {
+			init: func(tm *terminal.Mock, _ *dns.Mock, g *gtm.Mock) {
+				expectSelectProduct(tm, discovery.ProductGTM)
+				g.On("ListDomains", mock.Anything).Return([]*gtm.DomainItem{}, nil).Once()
+				tm.On("OK").Return().Once()
+			},
+			withError: ErrNoObjects,
+		},
+		"listing objects failed": {
+			init: func(tm *terminal.Mock, _ *dns.Mock, g *gtm.Mock) {
+				expectSelectProduct(tm, discovery.ProductGTM)
+				g.On("ListDomains", mock.Anything).Return(nil, errors.New("oops")).Once()
+				tm.On("Fail").Return().Once()
+			},
+			withError: errors.New("oops"),
+		},
+		"not a terminal": {
+			init: func(tm *terminal.Mock, _ *dns.Mock, _ *gtm.Mock) {
+				tm.On("IsTTY").Return(false).Once()
+			},
+			withError: ErrNotTTY,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mt, md, mg := new(terminal.Mock), new(dns.Mock), new(gtm.Mock)
+			test.init(mt, md, mg)
+			ctx := terminal.Context(context.Background(), mt)
+			e, err := runWizard(ctx, discovery.Clients{DNS: md, GTM: mg})
+			mt.AssertExpectations(t)
+			md.AssertExpectations(t)
+			mg.AssertExpectations(t)
+			if test.withError != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.withError.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, e)
+			assert.DirExists(t, e.args[1])
+		})
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	var tfWorkPath string
+	var args []string
+	app := cli.NewApp()
+	app.Commands = []*cli.Command{{
+		Name: "export-domain",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "tfworkpath"},
+		},
+		Action: func(c *cli.Context) error {
+			tfWorkPath = c.String("tfworkpath")
+			args = c.Args().Slice()
+			return nil
+		},
+	}}
+	c := cli.NewContext(app, flag.NewFlagSet("test", 0), nil)
+	c.Context = context.Background()
+
+	err := runExport(c, &export{command: "export-domain", args: []string{"--tfworkpath", "./test", "test.akadns.net"}})
+	require.NoError(t, err)
+	assert.Equal(t, "./test", tfWorkPath)
+	assert.Equal(t, []string{"test.akadns.net"}, args)
+
+	err = runExport(c, &export{command: "unknown"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package workspace
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Duplicate describes an object exported into a workspace which is already managed by another workspace
+type Duplicate struct {
+	Object Object
+	// Workspace is the path of the other workspace
+	Workspace string
+	// Command is the export command which generated configuration of the object in the other workspace
+	Command string
+}
+
+// maxSiblingDepth limits how deep below the parent of the workspace other workspaces are looked for
+const maxSiblingDepth = 2
+
+// FindDuplicates looks for workspaces in the directory tree containing the workspace in dir, i.e. below its parent
+// directory, whose state records any of the given objects. Objects which are only referenced by the configuration are
+// not compared. Workspaces with state which cannot be read are skipped, as the search only serves to warn about
+// objects managed twice.
+func FindDuplicates(dir string, objects []Object) ([]Duplicate, error) {
+	managed := make(map[string]Object)
+	for _, o := range objects {
+		if !o.Referenced {
+			managed[objectKey(o)] = o
+		}
+	}
+	if len(managed) == 0 {
+		return nil, nil
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Dir(dir)
+
+	var duplicates []Duplicate
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			// unreadable directories of other workspaces do not prevent the export
+			return nil
+		}
+		if path != root && SkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		if path == dir {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(path, MetadataDir, StateFile)); err == nil {
+			duplicates = append(duplicates, findInState(path, managed)...)
+		}
+		if path != root && depth(root, path) >= maxSiblingDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
+
+// findInState returns managed objects recorded in state of the workspace in dir
+func findInState(dir string, managed map[string]Object) []Duplicate {
+	state, err := LoadState(dir)
+	if err != nil {
+		return nil
+	}
+	var duplicates []Duplicate
+	seen := make(map[string]bool)
+	for _, e := range state.Exports {
+		for _, o := range e.Objects {
+			key := objectKey(o)
+			if _, ok := managed[key]; !ok || o.Referenced || seen[key] {
+				continue
+			}
+			seen[key] = true
+			duplicates = append(duplicates, Duplicate{Object: managed[key], Workspace: dir, Command: e.Command})
+		}
+	}
+	return duplicates
+}
+
+// depth returns the number of directories between root and path, including path
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// objectKey identifies the object by its product and ID, or name for objects without ID such as zones
+func objectKey(o Object) string {
+	if o.ID != "" {
+		return o.Product + "/" + o.ID
+	}
+	return o.Product + "/" + strings.ToLower(o.Name)
+}
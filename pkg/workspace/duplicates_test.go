@@ -0,0 +1,59 @@
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	root := t.TempDir()
+	saveState := func(dir string, exports ...Export) {
+		require.NoError(t, (&State{Exports: exports}).Save(filepath.Join(root, filepath.FromSlash(dir))))
+	}
+	saveState("property-a", Export{Command: "export-property", Objects: []Object{
+		{Product: "property", ID: "prp_1", Name: "a", Version: "3"},
+		{Product: "cps", ID: "10", Name: "a.example.com", Referenced: true},
+	}})
+	saveState("zones/example", Export{Command: "export-zone", Objects: []Object{{Product: "dns", Name: "Example.com"}}})
+	saveState("nested/too/deep", Export{Command: "export-zone", Objects: []Object{{Product: "dns", Name: "deep.com"}}})
+	saveState("current", Export{Command: "export-property", Objects: []Object{{Product: "property", ID: "prp_1", Name: "a"}}})
+	writeFiles(t, root, map[string]string{".hidden/.cli-terraform/state.json": `{"exports": [{"command": "export-zone", "objects": [{"product": "dns", "name": "example.com"}]}]}`})
+	writeFiles(t, root, map[string]string{"broken/.cli-terraform/state.json": "{"})
+
+	tests := map[string]struct {
+		objects  []Object
+		expected []Duplicate
+	}{
+		"no objects": {},
+		"object not managed elsewhere": {
+			objects: []Object{{Product: "property", ID: "prp_2", Name: "b"}},
+		},
+		"object managed by sibling workspaces": {
+			objects: []Object{
+				{Product: "property", ID: "prp_1", Name: "a-renamed", Version: "4"},
+				{Product: "dns", Name: "example.com"},
+			},
+			expected: []Duplicate{
+				{Object: Object{Product: "property", ID: "prp_1", Name: "a-renamed", Version: "4"}, Workspace: filepath.Join(root, "property-a"), Command: "export-property"},
+				{Object: Object{Product: "dns", Name: "example.com"}, Workspace: filepath.Join(root, "zones", "example"), Command: "export-zone"},
+			},
+		},
+		"referenced objects are not compared": {
+			objects: []Object{{Product: "cps", ID: "10", Name: "a.example.com", Referenced: true}},
+		},
+		"workspaces below maximum depth are skipped": {
+			objects: []Object{{Product: "dns", Name: "deep.com"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			duplicates, err := FindDuplicates(filepath.Join(root, "current"), test.objects)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, duplicates)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HistoryEntry describes an export appended to the history of the workspace
+type HistoryEntry struct {
+	Export
+	// GlobalArgs are global flags set for the export, in form of '--name=value'
+	GlobalArgs []string
+	// ToolVersion is the version of cli-terraform which made the export
+	ToolVersion string
+}
+
+// HistoryFile is the name of the file in MetadataDir to which every export into the workspace is appended
+const HistoryFile = "history.log"
+
+// String returns the entry as a single line with tab separated time of the export, version of cli-terraform, command
+// line and exported objects with their versions
+func (e HistoryEntry) String() string {
+	words := []string{"akamai", "terraform"}
+	words = append(words, e.GlobalArgs...)
+	words = append(words, e.Command)
+	words = append(words, e.Args...)
+	for i, w := range words {
+		if strings.ContainsAny(w, " \t\"'") {
+			words[i] = strconv.Quote(w)
+		}
+	}
+	objects := make([]string, 0, len(e.Objects))
+	for _, o := range e.Objects {
+		object := fmt.Sprintf("%s '%s'", o.Product, o.Name)
+		if o.ID != "" {
+			object += fmt.Sprintf(" (%s)", o.ID)
+		}
+		if o.Version != "" {
+			object += " version " + o.Version
+		}
+		objects = append(objects, object)
+	}
+	if len(objects) == 0 {
+		objects = append(objects, "-")
+	}
+	return strings.Join([]string{
+		e.ExportedAt.UTC().Format(time.RFC3339),
+		"cli-terraform " + e.ToolVersion,
+		strings.Join(words, " "),
+		strings.Join(objects, ", "),
+	}, "\t")
+}
+
+// AppendHistory appends the entry to MetadataDir/history.log of the workspace in dir
+func AppendHistory(dir string, e HistoryEntry) error {
+	if err := os.MkdirAll(filepath.Join(dir, MetadataDir), 0755); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, MetadataDir, HistoryFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	if _, err := f.WriteString(e.String() + "\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, AppendHistory(dir, HistoryEntry{
+		Export: Export{
+			Command:    "export-property",
+			Args:       []string{"--rules-as-hcl=true", "test"},
+			ExportedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+			Objects:    []Object{{Product: "property", ID: "prp_1", Name: "test", Version: "3"}},
+		},
+		GlobalArgs:  []string{"--section=test"},
+		ToolVersion: "1.2.0",
+	}))
+	require.NoError(t, AppendHistory(dir, HistoryEntry{
+		Export: Export{
+			Command:    "export-domain",
+			Args:       []string{"my domain.akadns.net"},
+			ExportedAt: time.Date(2022, 1, 3, 4, 5, 6, 0, time.FixedZone("CET", 3600)),
+		},
+		ToolVersion: "1.3.0",
+	}))
+
+	content, err := os.ReadFile(filepath.Join(dir, MetadataDir, HistoryFile))
+	require.NoError(t, err)
+	assert.Equal(t, "2022-01-02T03:04:05Z\tcli-terraform 1.2.0\takamai terraform --section=test export-property --rules-as-hcl=true test\tproperty 'test' (prp_1) version 3\n"+
+		"2022-01-03T03:05:06Z\tcli-terraform 1.3.0\takamai terraform export-domain \"my domain.akadns.net\"\t-\n", string(content))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), nil, 0644))
+	err = AppendHistory(filepath.Join(dir, "file"), HistoryEntry{})
+	assert.True(t, errors.Is(err, ErrSavingWorkspace), "expected: %s; got: %s", ErrSavingWorkspace, err)
+}
@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+type (
+	// hunk replaces lines [start, end) of the base with lines, on one side of the merge
+	hunk struct {
+		side       int
+		start, end int
+		lines      []string
+	}
+)
+
+const (
+	sideOurs = iota
+	sideTheirs
+)
+
+// Conflict markers surrounding local edits and re-exported content which could not be merged
+const (
+	MarkerOurs      = "<<<<<<< local"
+	MarkerSeparator = "======="
+	MarkerTheirs    = ">>>>>>> export"
+)
+
+// Merge performs line based three-way merge of ours and theirs, which both derive from base.
+// Changes made on only one side are applied, while overlapping changes which differ are written
+// between conflict markers. Merged content and the number of conflicts are returned.
+func Merge(base, ours, theirs []byte) ([]byte, int) {
+	baseLines := splitLines(string(base))
+	hunks := append(diff(baseLines, splitLines(string(ours)), sideOurs), diff(baseLines, splitLines(string(theirs)), sideTheirs)...)
+	sort.SliceStable(hunks, func(i, j int) bool {
+		if hunks[i].start != hunks[j].start {
+			return hunks[i].start < hunks[j].start
+		}
+		return hunks[i].end < hunks[j].end
+	})
+
+	var out strings.Builder
+	var conflicts int
+	pos := 0
+	for i := 0; i < len(hunks); {
+		// hunks which overlap or touch each other are merged as a single group
+		start, end := hunks[i].start, hunks[i].end
+		j := i + 1
+		for ; j < len(hunks) && hunks[j].start <= end; j++ {
+			if hunks[j].end > end {
+				end = hunks[j].end
+			}
+		}
+		group := hunks[i:j]
+		i = j
+
+		writeLines(&out, baseLines[pos:start])
+		pos = end
+		oursLines, oursChanged := apply(baseLines, start, end, group, sideOurs)
+		theirsLines, theirsChanged := apply(baseLines, start, end, group, sideTheirs)
+		switch {
+		case !theirsChanged:
+			writeLines(&out, oursLines)
+		case !oursChanged || strings.Join(oursLines, "") == strings.Join(theirsLines, ""):
+			writeLines(&out, theirsLines)
+		default:
+			conflicts++
+			out.WriteString(MarkerOurs + "\n")
+			writeBlock(&out, oursLines)
+			out.WriteString(MarkerSeparator + "\n")
+			writeBlock(&out, theirsLines)
+			out.WriteString(MarkerTheirs + "\n")
+		}
+	}
+	writeLines(&out, baseLines[pos:])
+	return []byte(out.String()), conflicts
+}
+
+// diff returns hunks transforming base into changed
+func diff(base, changed []string, side int) []hunk {
+	var hunks []hunk
+	for _, op := range difflib.NewMatcher(base, changed).GetOpCodes() {
+		if op.Tag == 'e' {
+			continue
+		}
+		hunks = append(hunks, hunk{side: side, start: op.I1, end: op.I2, lines: changed[op.J1:op.J2]})
+	}
+	return hunks
+}
+
+// apply returns lines [start, end) of the base with hunks of the given side applied
+func apply(base []string, start, end int, group []hunk, side int) ([]string, bool) {
+	var lines []string
+	changed := false
+	pos := start
+	for _, h := range group {
+		if h.side != side {
+			continue
+		}
+		changed = true
+		lines = append(lines, base[pos:h.start]...)
+		lines = append(lines, h.lines...)
+		pos = h.end
+	}
+	return append(lines, base[pos:end]...), changed
+}
+
+// splitLines splits content into lines keeping line endings, so that content is restored by joining them
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, line := range lines {
+		out.WriteString(line)
+	}
+}
+
+// writeBlock writes lines between conflict markers, which must always start in a new line
+func writeBlock(out *strings.Builder, lines []string) {
+	writeLines(out, lines)
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteString("\n")
+	}
+}
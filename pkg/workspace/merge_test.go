@@ -0,0 +1,80 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	base := "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 60\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n"
+
+	tests := map[string]struct {
+		base              string
+		ours              string
+		theirs            string
+		expected          string
+		expectedConflicts int
+	}{
+		"only local edits": {
+			base:     base,
+			ours:     "resource \"a\" \"b\" {\n  name = var.name\n  ttl  = 60\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			theirs:   base,
+			expected: "resource \"a\" \"b\" {\n  name = var.name\n  ttl  = 60\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+		},
+		"only exported changes": {
+			base:     base,
+			ours:     base,
+			theirs:   "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 300\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			expected: "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 300\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+		},
+		"local edits and exported changes in different places": {
+			base:     base,
+			ours:     "resource \"a\" \"b\" {\n  name = var.name\n  ttl  = 60\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			theirs:   "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 60\n}\n\nresource \"c\" \"d\" {\n  value = 2\n}\n\nresource \"e\" \"f\" {\n}\n",
+			expected: "resource \"a\" \"b\" {\n  name = var.name\n  ttl  = 60\n}\n\nresource \"c\" \"d\" {\n  value = 2\n}\n\nresource \"e\" \"f\" {\n}\n",
+		},
+		"same change on both sides": {
+			base:     base,
+			ours:     "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 300\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			theirs:   "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 300\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			expected: "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 300\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+		},
+		"conflicting changes": {
+			base:   base,
+			ours:   "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = var.ttl\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			theirs: "resource \"a\" \"b\" {\n  name = \"test\"\n  ttl  = 300\n}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			expected: "resource \"a\" \"b\" {\n  name = \"test\"\n" +
+				"<<<<<<< local\n  ttl  = var.ttl\n=======\n  ttl  = 300\n>>>>>>> export\n" +
+				"}\n\nresource \"c\" \"d\" {\n  value = 1\n}\n",
+			expectedConflicts: 1,
+		},
+		"adjacent changes conflict": {
+			base:              "a\nb\n",
+			ours:              "x\nb\n",
+			theirs:            "a\ny\n",
+			expected:          "<<<<<<< local\nx\nb\n=======\na\ny\n>>>>>>> export\n",
+			expectedConflicts: 1,
+		},
+		"no base": {
+			ours:              "a\nb\n",
+			theirs:            "a\nc\n",
+			expected:          "<<<<<<< local\na\nb\n=======\na\nc\n>>>>>>> export\n",
+			expectedConflicts: 1,
+		},
+		"missing trailing newline": {
+			base:     "a\nb\nc",
+			ours:     "x\nb\nc",
+			theirs:   "a\nb\nc\nd",
+			expected: "x\nb\nc\nd",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			merged, conflicts := Merge([]byte(test.base), []byte(test.ours), []byte(test.theirs))
+			assert.Equal(t, test.expected, string(merged))
+			assert.Equal(t, test.expectedConflicts, conflicts)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// ErrSelectionNotFound is returned when a selected block or file is not generated by the export
+var ErrSelectionNotFound = exitcode.New(exitcode.NotFound, "selection not generated by the export")
+
+// Select reverts changes made by the export since the snapshot of all files of the workspace in dir was taken, except
+// of the selected ones. Selection contains addresses of blocks, e.g. akamai_dns_record.www or
+// data.akamai_cloudlets_edge_redirector_match_rule.rules, and slash separated paths of files relative to the workspace,
+// e.g. property-snippets/main.json. Selected blocks replace blocks with the same address in existing files, blocks
+// which were not exported before are appended to the file they were generated to.
+func Select(dir string, before Snapshot, selection []string) error {
+	after, err := TakeAll(dir)
+	if err != nil {
+		return err
+	}
+	selected := make(map[string]bool, len(selection))
+	for _, s := range selection {
+		selected[strings.TrimPrefix(filepath.ToSlash(s), "./")] = true
+	}
+	files := make([]string, 0, len(after))
+	for file := range after {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	found := make(map[string]bool)
+	for _, file := range files {
+		theirs := after[file]
+		ours, existed := before[file]
+		content := ours
+		switch {
+		case selected[file]:
+			found[file] = true
+			content = theirs
+		case filepath.Ext(file) == ".tf":
+			content, err = replaceBlocks(file, ours, theirs, selected, found)
+			if err != nil {
+				return err
+			}
+		}
+		if bytes.Equal(content, theirs) && (existed || len(content) > 0) {
+			continue
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if !existed && len(content) == 0 {
+			err = os.Remove(path)
+		} else {
+			err = os.WriteFile(path, content, 0644)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+		}
+	}
+
+	var missing []string
+	for _, s := range selection {
+		if !found[strings.TrimPrefix(filepath.ToSlash(s), "./")] {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrSelectionNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// replaceBlocks returns ours with selected blocks of theirs in place of blocks with the same address, selected blocks
+// missing in ours are appended to it; addresses of selected blocks are added to found
+func replaceBlocks(file string, ours, theirs []byte, selected, found map[string]bool) ([]byte, error) {
+	generated, err := parseBlocks(file, theirs)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := parseBlocks(file, ours)
+	if err != nil {
+		return nil, err
+	}
+	ranges := make(map[string]hcl.Range, len(existing))
+	for _, block := range existing {
+		ranges[blockAddress(block)] = block.Range()
+	}
+
+	type replacement struct {
+		rng     hcl.Range
+		content []byte
+	}
+	var replacements []replacement
+	var appended [][]byte
+	for _, block := range generated {
+		address := blockAddress(block)
+		if address == "" || !selected[address] {
+			continue
+		}
+		found[address] = true
+		content := theirs[block.Range().Start.Byte:block.Range().End.Byte]
+		if rng, ok := ranges[address]; ok {
+			replacements = append(replacements, replacement{rng: rng, content: content})
+			continue
+		}
+		appended = append(appended, content)
+	}
+	if len(replacements) == 0 && len(appended) == 0 {
+		return ours, nil
+	}
+
+	// blocks are replaced from the end of the file, so that ranges of preceding blocks stay valid
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].rng.Start.Byte > replacements[j].rng.Start.Byte
+	})
+	result := append([]byte{}, ours...)
+	for _, r := range replacements {
+		tail := append(append([]byte{}, r.content...), result[r.rng.End.Byte:]...)
+		result = append(result[:r.rng.Start.Byte], tail...)
+	}
+	for _, content := range appended {
+		if len(result) > 0 {
+			result = append(bytes.TrimRight(result, "\n"), "\n\n"...)
+		}
+		result = append(append(result, content...), '\n')
+	}
+	return result, nil
+}
+
+func parseBlocks(file string, content []byte) (hclsyntax.Blocks, error) {
+	f, diags := hclsyntax.ParseConfig(content, file, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%w: %s", ErrReadingWorkspace, diags.Error())
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, nil
+	}
+	return body.Blocks, nil
+}
+
+// blockAddress returns address of the block as used by terraform, e.g. data.akamai_contract.contract, or an empty string
+// for blocks which are not addressable, such as terraform or locals
+func blockAddress(block *hclsyntax.Block) string {
+	switch {
+	case block.Type == "resource" && len(block.Labels) == 2:
+		return block.Labels[0] + "." + block.Labels[1]
+	case block.Type == "data" && len(block.Labels) == 2:
+		return "data." + block.Labels[0] + "." + block.Labels[1]
+	case block.Type == "module" && len(block.Labels) == 1:
+		return "module." + block.Labels[0]
+	case block.Type == "variable" && len(block.Labels) == 1:
+		return "var." + block.Labels[0]
+	case block.Type == "output" && len(block.Labels) == 1:
+		return "output." + block.Labels[0]
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	localZone = `resource "akamai_dns_zone" "zone" {
+  comment = "edited locally"
+}
+
+# local comment
+resource "akamai_dns_record" "www" {
+  target = ["1.2.3.4"]
+}
+`
+	exportedZone = `resource "akamai_dns_zone" "zone" {
+  comment = "exported"
+}
+
+resource "akamai_dns_record" "www" {
+  target = ["5.6.7.8"]
+}
+
+resource "akamai_dns_record" "api" {
+  target = ["9.9.9.9"]
+}
+`
+)
+
+func TestSelect(t *testing.T) {
+	tests := map[string]struct {
+		local     map[string]string
+		exported  map[string]string
+		selection []string
+		expected  map[string]string
+		withError error
+	}{
+		"selected block replaces existing one": {
+			local:     map[string]string{"zone.tf": localZone, "import.sh": "local script"},
+			exported:  map[string]string{"zone.tf": exportedZone, "import.sh": "exported script"},
+			selection: []string{"akamai_dns_record.www"},
+			expected: map[string]string{
+				"zone.tf": `resource "akamai_dns_zone" "zone" {
+  comment = "edited locally"
+}
+
+# local comment
+resource "akamai_dns_record" "www" {
+  target = ["5.6.7.8"]
+}
+`,
+				"import.sh": "local script",
+			},
+		},
+		"selected block missing locally is appended": {
+			local:     map[string]string{"zone.tf": localZone},
+			exported:  map[string]string{"zone.tf": exportedZone},
+			selection: []string{"akamai_dns_record.api"},
+			expected: map[string]string{
+				"zone.tf": localZone + `
+resource "akamai_dns_record" "api" {
+  target = ["9.9.9.9"]
+}
+`,
+			},
+		},
+		"new files keep only selected blocks and files": {
+			exported: map[string]string{
+				"policy.tf":        "data \"akamai_cloudlets_edge_redirector_match_rule\" \"rules\" {\n}\n\nresource \"akamai_cloudlets_policy\" \"policy\" {\n}\n",
+				"modules/main.tf":  "variable \"name\" {\n}\n\noutput \"id\" {\n  value = 1\n}\n",
+				"match-rules.json": "{}",
+				"variables.tf":     "terraform {\n}\n\nvariable \"contract\" {\n}\n",
+				"README.md":        "readme",
+			},
+			selection: []string{"data.akamai_cloudlets_edge_redirector_match_rule.rules", "./match-rules.json", "output.id"},
+			expected: map[string]string{
+				"policy.tf":        "data \"akamai_cloudlets_edge_redirector_match_rule\" \"rules\" {\n}\n",
+				"modules/main.tf":  "output \"id\" {\n  value = 1\n}\n",
+				"match-rules.json": "{}",
+				"variables.tf":     "",
+				"README.md":        "",
+			},
+		},
+		"selection not generated": {
+			local:     map[string]string{"zone.tf": localZone},
+			exported:  map[string]string{"zone.tf": exportedZone},
+			selection: []string{"akamai_dns_record.www", "akamai_dns_record.ftp"},
+			expected: map[string]string{
+				"zone.tf": `resource "akamai_dns_zone" "zone" {
+  comment = "edited locally"
+}
+
+# local comment
+resource "akamai_dns_record" "www" {
+  target = ["5.6.7.8"]
+}
+`,
+			},
+			withError: ErrSelectionNotFound,
+		},
+		"invalid local configuration": {
+			local:     map[string]string{"zone.tf": "resource {"},
+			exported:  map[string]string{"zone.tf": exportedZone},
+			selection: []string{"akamai_dns_record.www"},
+			withError: ErrReadingWorkspace,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, test.local)
+			before, err := TakeAll(dir)
+			require.NoError(t, err)
+			writeFiles(t, dir, test.exported)
+
+			err = Select(dir, before, test.selection)
+			if test.withError != nil {
+				assert.True(t, errors.Is(err, test.withError), "expected: %s; got: %s", test.withError, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assertFiles(t, dir, test.expected)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package workspace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// State records exports made into a workspace, it is stored in MetadataDir/state.json
+	State struct {
+		Exports []Export `json:"exports"`
+	}
+
+	// Export describes a single export command run into the workspace
+	Export struct {
+		Command    string    `json:"command"`
+		Args       []string  `json:"args"`
+		ExportedAt time.Time `json:"exportedAt"`
+		Objects    []Object  `json:"objects,omitempty"`
+		// Files maps slash separated paths of generated files, relative to the workspace, to checksums of their generated content
+		Files map[string]string `json:"files"`
+	}
+
+	// Object identifies an API object from which configuration was exported
+	Object struct {
+		Product string `json:"product"`
+		ID      string `json:"id,omitempty"`
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+		// Referenced is set for objects which are only referenced by the exported configuration, not managed by it
+		Referenced bool `json:"referenced,omitempty"`
+	}
+
+	// Recorder collects objects reported by the export command
+	Recorder struct {
+		mu      sync.Mutex
+		objects []Object
+		// parent is the recorder of the enclosing context, which receives objects recorded into this one as well
+		parent *Recorder
+	}
+
+	ctxType string
+)
+
+// StateFile is the name of the file in MetadataDir in which State is stored
+const StateFile = "state.json"
+
+var recorderCtx ctxType = "recorder"
+
+// LoadState reads state of the workspace in dir, empty State is returned if nothing was exported into it yet
+func LoadState(dir string) (*State, error) {
+	content, err := os.ReadFile(filepath.Join(dir, MetadataDir, StateFile))
+	if os.IsNotExist(err) {
+		return &State{Exports: []Export{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingWorkspace, err)
+	}
+	var s State
+	if err := json.Unmarshal(content, &s); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingWorkspace, err)
+	}
+	return &s, nil
+}
+
+// Save writes state of the workspace in dir
+func (s *State) Save(dir string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, MetadataDir), 0755); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, MetadataDir, StateFile), append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	return nil
+}
+
+// Find returns the export made with the given command and arguments, nil is returned if there is none
+func (s *State) Find(command string, args []string) *Export {
+	for i, e := range s.Exports {
+		if e.Command == command && strings.Join(e.Args, "\x00") == strings.Join(args, "\x00") {
+			return &s.Exports[i]
+		}
+	}
+	return nil
+}
+
+// Record adds the export to the state, replacing the previous export made with the same command and arguments.
+// Files which were not changed by the export are kept from the previous export, as long as they still exist in dir.
+func (s *State) Record(dir string, e Export) {
+	previous := s.Find(e.Command, e.Args)
+	if previous == nil {
+		s.Exports = append(s.Exports, e)
+		return
+	}
+	for file, checksum := range previous.Files {
+		if _, ok := e.Files[file]; !ok {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file))); err == nil {
+				e.Files[file] = checksum
+			}
+		}
+	}
+	*previous = e
+}
+
+// Checksum returns checksum of the content in form of 'sha256:<hex>'
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// NewRecorder returns an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Objects returns objects recorded so far, in order of reporting
+func (r *Recorder) Objects() []Object {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Object(nil), r.objects...)
+}
+
+// WithRecorder puts a Recorder in context, a recorder already in the context keeps receiving recorded objects
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	if parent, ok := ctx.Value(recorderCtx).(*Recorder); ok && parent != r {
+		r.parent = parent
+	}
+	return context.WithValue(ctx, recorderCtx, r)
+}
+
+// RecordObject registers an object exported by the current command, so that its identifier and version are kept
+// in the workspace state; it does nothing if the command does not run within a workspace
+func RecordObject(ctx context.Context, o Object) {
+	r, ok := ctx.Value(recorderCtx).(*Recorder)
+	if !ok {
+		return
+	}
+	for ; r != nil; r = r.parent {
+		r.mu.Lock()
+		r.objects = append(r.objects, o)
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := LoadState(dir)
+	require.NoError(t, err)
+	assert.Empty(t, s.Exports)
+
+	exportedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.Record(dir, Export{
+		Command:    "export-property",
+		Args:       []string{"test"},
+		ExportedAt: exportedAt,
+		Objects:    []Object{{Product: "property", ID: "prp_1", Name: "test", Version: "3"}},
+		Files:      map[string]string{"property.tf": Checksum([]byte("a"))},
+	})
+	require.NoError(t, s.Save(dir))
+
+	loaded, err := LoadState(dir)
+	require.NoError(t, err)
+	assert.Equal(t, s, loaded)
+	assert.Nil(t, loaded.Find("export-property", []string{"other"}))
+	e := loaded.Find("export-property", []string{"test"})
+	require.NotNil(t, e)
+	assert.Equal(t, exportedAt, e.ExportedAt)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, MetadataDir, StateFile), []byte("{"), 0644))
+	_, err = LoadState(dir)
+	assert.ErrorIs(t, err, ErrReadingWorkspace)
+}
+
+func TestRecord(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"property.tf": "a", "variables.tf": "b"})
+	s := &State{Exports: []Export{{
+		Command: "export-property",
+		Args:    []string{"test"},
+		Files: map[string]string{
+			"property.tf":  Checksum([]byte("a")),
+			"variables.tf": Checksum([]byte("b")),
+			"rules.tf":     Checksum([]byte("c")),
+		},
+	}, {
+		Command: "export-zone",
+		Args:    []string{"test"},
+		Files:   map[string]string{"test.tf": Checksum([]byte("d"))},
+	}}}
+
+	s.Record(dir, Export{
+		Command: "export-property",
+		Args:    []string{"test"},
+		Files:   map[string]string{"property.tf": Checksum([]byte("x"))},
+	})
+	require.Len(t, s.Exports, 2)
+	assert.Equal(t, map[string]string{
+		"property.tf":  Checksum([]byte("x")),
+		"variables.tf": Checksum([]byte("b")),
+	}, s.Exports[0].Files)
+
+	s.Record(dir, Export{Command: "export-property", Args: []string{"other"}, Files: map[string]string{}})
+	assert.Len(t, s.Exports, 3)
+}
+
+func TestRecordObject(t *testing.T) {
+	RecordObject(context.Background(), Object{Product: "property", Name: "ignored"})
+
+	r := NewRecorder()
+	ctx := WithRecorder(context.Background(), r)
+	RecordObject(ctx, Object{Product: "property", ID: "prp_1", Name: "test", Version: "1"})
+	RecordObject(ctx, Object{Product: "cloudlets", ID: "2", Name: "policy", Version: "5"})
+	assert.Equal(t, []Object{
+		{Product: "property", ID: "prp_1", Name: "test", Version: "1"},
+		{Product: "cloudlets", ID: "2", Name: "policy", Version: "5"},
+	}, r.Objects())
+}
+
+func TestRecordObjectNested(t *testing.T) {
+	outer := NewRecorder()
+	ctx := WithRecorder(context.Background(), outer)
+	RecordObject(ctx, Object{Product: "property", ID: "prp_1", Name: "test"})
+
+	inner := NewRecorder()
+	RecordObject(WithRecorder(ctx, inner), Object{Product: "cloudlets", ID: "2", Name: "policy"})
+
+	assert.Equal(t, []Object{{Product: "cloudlets", ID: "2", Name: "policy"}}, inner.Objects())
+	assert.Equal(t, []Object{
+		{Product: "property", ID: "prp_1", Name: "test"},
+		{Product: "cloudlets", ID: "2", Name: "policy"},
+	}, outer.Objects())
+}
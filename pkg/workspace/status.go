@@ -0,0 +1,84 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type (
+	// FileStatus describes how a file generated by the recorded export differs from the local file and from the file
+	// generated from the current state of the API
+	FileStatus struct {
+		File   string
+		Status string
+	}
+)
+
+// Statuses of generated files
+const (
+	// StatusUpToDate means that neither the local file nor the API changed since the export
+	StatusUpToDate = "up to date"
+	// StatusChanged means that the API changed since the export, so the file is stale
+	StatusChanged = "changed in API"
+	// StatusModified means that the local file was edited or deleted since the export
+	StatusModified = "modified locally"
+	// StatusConflict means that both the local file and the API changed since the export
+	StatusConflict = "changed in API, modified locally"
+	// StatusAdded means that the file is generated from the current state of the API, but it was not exported
+	StatusAdded = "added in API"
+	// StatusRemoved means that the file was exported, but it is no longer generated from the current state of the API
+	StatusRemoved = "removed in API"
+)
+
+// Compare returns status of each file of the export recorded in the workspace in dir, given the files generated from
+// the current state of the API. Files are sorted by name.
+func Compare(dir string, e *Export, generated Snapshot) []FileStatus {
+	files := make([]string, 0, len(e.Files))
+	for file := range e.Files {
+		files = append(files, file)
+	}
+	for file := range generated {
+		if _, ok := e.Files[file]; !ok {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	statuses := make([]FileStatus, 0, len(files))
+	for _, file := range files {
+		recorded, exported := e.Files[file]
+		content, isGenerated := generated[file]
+		status := StatusUpToDate
+		switch {
+		case !exported:
+			status = StatusAdded
+		case !isGenerated:
+			status = StatusRemoved
+		default:
+			changed := Checksum(content) != recorded
+			local, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+			modified := err != nil || Checksum(local) != recorded
+			switch {
+			case changed && modified:
+				status = StatusConflict
+			case changed:
+				status = StatusChanged
+			case modified:
+				status = StatusModified
+			}
+		}
+		statuses = append(statuses, FileStatus{File: file, Status: status})
+	}
+	return statuses
+}
+
+// IsStale returns true if any of the files changed in the API since the export
+func IsStale(statuses []FileStatus) bool {
+	for _, s := range statuses {
+		if s.Status != StatusUpToDate && s.Status != StatusModified {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"same.tf":     "a",
+		"changed.tf":  "b",
+		"modified.tf": "x",
+		"conflict.tf": "x",
+	})
+	e := &Export{Files: map[string]string{
+		"same.tf":     Checksum([]byte("a")),
+		"changed.tf":  Checksum([]byte("b")),
+		"modified.tf": Checksum([]byte("c")),
+		"conflict.tf": Checksum([]byte("d")),
+		"deleted.tf":  Checksum([]byte("e")),
+		"removed.tf":  Checksum([]byte("f")),
+	}}
+	generated := Snapshot{
+		"same.tf":     []byte("a"),
+		"changed.tf":  []byte("y"),
+		"modified.tf": []byte("c"),
+		"conflict.tf": []byte("y"),
+		"deleted.tf":  []byte("e"),
+		"added.tf":    []byte("g"),
+	}
+
+	statuses := Compare(dir, e, generated)
+	assert.Equal(t, []FileStatus{
+		{File: "added.tf", Status: StatusAdded},
+		{File: "changed.tf", Status: StatusChanged},
+		{File: "conflict.tf", Status: StatusConflict},
+		{File: "deleted.tf", Status: StatusModified},
+		{File: "modified.tf", Status: StatusModified},
+		{File: "removed.tf", Status: StatusRemoved},
+		{File: "same.tf", Status: StatusUpToDate},
+	}, statuses)
+	assert.True(t, IsStale(statuses))
+	assert.False(t, IsStale([]FileStatus{{File: "same.tf", Status: StatusUpToDate}, {File: "modified.tf", Status: StatusModified}}))
+}
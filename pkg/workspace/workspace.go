@@ -0,0 +1,193 @@
+// Package workspace contains code for keeping metadata of exports in the directory they are exported to
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akamai/cli-terraform/pkg/exitcode"
+)
+
+type (
+	// Snapshot holds content of generated files of a workspace, keyed by slash separated paths relative to the workspace
+	Snapshot map[string][]byte
+
+	// Conflict describes a file in which local edits overlap with changes of the re-exported configuration
+	Conflict struct {
+		File  string
+		Count int
+	}
+
+	// Result describes files changed by the export
+	Result struct {
+		// Generated maps files changed by the export to checksums of their generated content
+		Generated map[string]string
+		Conflicts []Conflict
+	}
+)
+
+const (
+	// MetadataDir is the directory within a workspace in which export metadata is stored, it is skipped when reading configuration
+	MetadataDir = ".cli-terraform"
+
+	// baseDir holds content of files as generated by the previous export, used as the base of three-way merge
+	baseDir = "base"
+)
+
+var (
+	// mergeable lists extensions of generated files which can be edited by the user and are merged on re-export
+	mergeable = map[string]bool{".tf": true, ".tfvars": true, ".hcl": true, ".json": true}
+
+	// ErrReadingWorkspace is returned when files of the workspace cannot be read
+	ErrReadingWorkspace = exitcode.New(exitcode.IO, "unable to read workspace")
+	// ErrSavingWorkspace is returned when merged files or export metadata cannot be written
+	ErrSavingWorkspace = exitcode.New(exitcode.IO, "unable to save workspace")
+)
+
+// SkipDir returns true for directories which do not contain configuration of the workspace,
+// i.e. hidden directories such as MetadataDir, .terraform or .git
+func SkipDir(name string) bool {
+	return strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
+// Take reads mergeable files of the workspace in dir
+func Take(dir string) (Snapshot, error) {
+	return take(dir, func(path string) bool { return mergeable[filepath.Ext(path)] })
+}
+
+// TakeAll reads all files of the workspace in dir, including files which are not merged, e.g. import scripts
+func TakeAll(dir string) (Snapshot, error) {
+	return take(dir, func(string) bool { return true })
+}
+
+func take(dir string, include func(path string) bool) (Snapshot, error) {
+	s := make(Snapshot)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && SkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !include(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		s[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrReadingWorkspace, err)
+	}
+	return s, nil
+}
+
+// Reconcile processes files of the workspace in dir changed by the export since the snapshot was taken.
+// New content of each changed file is stored as the base of the next export. With merge set, changed files are
+// three-way merged with their content from the snapshot, using the previously stored base, so that local edits
+// are preserved; files deleted locally stay deleted unless the export changed them.
+func Reconcile(dir string, before Snapshot, merge bool) (*Result, error) {
+	after, err := Take(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(after))
+	for file := range after {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	result := Result{Generated: make(map[string]string)}
+	for _, file := range files {
+		theirs := after[file]
+		ours, existed := before[file]
+		if existed && bytes.Equal(ours, theirs) {
+			// file was not written by the export or its content did not change
+			continue
+		}
+		base, hasBase, err := readBase(dir, file)
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		switch {
+		case !merge:
+		case !existed && hasBase && bytes.Equal(base, theirs):
+			if err := os.Remove(path); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+			}
+		case existed:
+			merged, n := Merge(base, ours, theirs)
+			if err := os.WriteFile(path, merged, 0644); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+			}
+			if n > 0 {
+				result.Conflicts = append(result.Conflicts, Conflict{File: path, Count: n})
+			}
+		}
+		if err := writeBase(dir, file, theirs); err != nil {
+			return nil, err
+		}
+		result.Generated[file] = Checksum(theirs)
+	}
+	return &result, nil
+}
+
+// Restore writes back files changed since the snapshot was taken and removes files created since then, e.g. when the export failed
+func Restore(dir string, before Snapshot) error {
+	after, err := Take(dir)
+	if err != nil {
+		return err
+	}
+	for file, content := range after {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		ours, existed := before[file]
+		if !existed {
+			err = os.Remove(path)
+		} else if !bytes.Equal(ours, content) {
+			err = os.WriteFile(path, ours, 0644)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+		}
+	}
+	return nil
+}
+
+func readBase(dir, file string) ([]byte, bool, error) {
+	content, err := os.ReadFile(filepath.Join(dir, MetadataDir, baseDir, filepath.FromSlash(file)))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrReadingWorkspace, err)
+	}
+	return content, true, nil
+}
+
+func writeBase(dir, file string, content []byte) error {
+	path := filepath.Join(dir, MetadataDir, baseDir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("%w: %s", ErrSavingWorkspace, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func assertFiles(t *testing.T, dir string, expected map[string]string) {
+	for name, content := range expected {
+		actual, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if content == "" {
+			assert.True(t, os.IsNotExist(err), "file '%s' should not exist", name)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, content, string(actual), name)
+	}
+}
+
+func TestTake(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.tf":                     "main",
+		"modules/zone.tf":             "zone",
+		"rules.json":                  "{}",
+		"bundle.tgz":                  "binary",
+		".terraform/providers.tf":     "skipped",
+		MetadataDir + "/base/main.tf": "skipped",
+	})
+
+	s, err := Take(dir)
+	require.NoError(t, err)
+	assert.Equal(t, Snapshot{
+		"main.tf":         []byte("main"),
+		"modules/zone.tf": []byte("zone"),
+		"rules.json":      []byte("{}"),
+	}, s)
+
+	s, err = TakeAll(dir)
+	require.NoError(t, err)
+	assert.Equal(t, Snapshot{
+		"main.tf":         []byte("main"),
+		"modules/zone.tf": []byte("zone"),
+		"rules.json":      []byte("{}"),
+		"bundle.tgz":      []byte("binary"),
+	}, s)
+
+	s, err = Take(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, s)
+}
+
+func TestReconcile(t *testing.T) {
+	tests := map[string]struct {
+		base              map[string]string
+		local             map[string]string
+		exported          map[string]string
+		merge             bool
+		expected          map[string]string
+		expectedGenerated []string
+		expectedConflicts []string
+	}{
+		"first export stores base": {
+			exported:          map[string]string{"main.tf": "a\nb\n"},
+			expected:          map[string]string{"main.tf": "a\nb\n", MetadataDir + "/base/main.tf": "a\nb\n"},
+			expectedGenerated: []string{"main.tf"},
+		},
+		"export without merge overwrites local edits": {
+			base:              map[string]string{"main.tf": "a\nb\nc\n"},
+			local:             map[string]string{"main.tf": "x\nb\nc\n"},
+			exported:          map[string]string{"main.tf": "a\nb\ny\n"},
+			expected:          map[string]string{"main.tf": "a\nb\ny\n", MetadataDir + "/base/main.tf": "a\nb\ny\n"},
+			expectedGenerated: []string{"main.tf"},
+		},
+		"merge preserves local edits": {
+			base:     map[string]string{"main.tf": "a\nb\nc\n"},
+			local:    map[string]string{"main.tf": "x\nb\nc\n", "custom.tf": "custom\n"},
+			exported: map[string]string{"main.tf": "a\nb\ny\n"},
+			merge:    true,
+			expected: map[string]string{
+				"main.tf":                       "x\nb\ny\n",
+				"custom.tf":                     "custom\n",
+				MetadataDir + "/base/main.tf":   "a\nb\ny\n",
+				MetadataDir + "/base/custom.tf": "",
+			},
+			expectedGenerated: []string{"main.tf"},
+		},
+		"merge reports conflicts": {
+			base:              map[string]string{"main.tf": "a\nb\n"},
+			local:             map[string]string{"main.tf": "a\nx\n"},
+			exported:          map[string]string{"main.tf": "a\ny\n"},
+			merge:             true,
+			expected:          map[string]string{"main.tf": "a\n<<<<<<< local\nx\n=======\ny\n>>>>>>> export\n"},
+			expectedGenerated: []string{"main.tf"},
+			expectedConflicts: []string{"main.tf"},
+		},
+		"locally deleted file stays deleted": {
+			base:              map[string]string{"import.tf": "a\n", "vars.tf": "b\n"},
+			exported:          map[string]string{"import.tf": "a\n", "vars.tf": "c\n"},
+			merge:             true,
+			expected:          map[string]string{"import.tf": "", "vars.tf": "c\n"},
+			expectedGenerated: []string{"import.tf", "vars.tf"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for file, content := range test.base {
+				require.NoError(t, writeBase(dir, file, []byte(content)))
+			}
+			writeFiles(t, dir, test.local)
+			before, err := Take(dir)
+			require.NoError(t, err)
+			writeFiles(t, dir, test.exported)
+
+			result, err := Reconcile(dir, before, test.merge)
+			require.NoError(t, err)
+			var generated []string
+			for file, checksum := range result.Generated {
+				generated = append(generated, file)
+				assert.Equal(t, Checksum([]byte(test.exported[file])), checksum)
+			}
+			sort.Strings(generated)
+			assert.Equal(t, test.expectedGenerated, generated)
+			var conflictFiles []string
+			for _, c := range result.Conflicts {
+				rel, err := filepath.Rel(dir, c.File)
+				require.NoError(t, err)
+				conflictFiles = append(conflictFiles, filepath.ToSlash(rel))
+			}
+			assert.Equal(t, test.expectedConflicts, conflictFiles)
+			assertFiles(t, dir, test.expected)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"main.tf": "local\n"})
+	before, err := Take(dir)
+	require.NoError(t, err)
+	writeFiles(t, dir, map[string]string{"main.tf": "exported\n", "new.tf": "new\n"})
+
+	require.NoError(t, Restore(dir, before))
+	assertFiles(t, dir, map[string]string{"main.tf": "local\n", "new.tf": ""})
+}
+
+func TestSkipDir(t *testing.T) {
+	assert.True(t, SkipDir(MetadataDir))
+	assert.True(t, SkipDir(".terraform"))
+	assert.False(t, SkipDir("."))
+	assert.False(t, SkipDir("modules"))
+}
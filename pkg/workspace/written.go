@@ -0,0 +1,56 @@
+package workspace
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// Written returns paths, relative to dir, of files in dir written by the run started at since: files modified since
+// then and given paths of files written into the sink, which are listed even if the file system keeps coarse
+// modification times; files left in dir by earlier runs and hidden directories such as MetadataDir are not returned
+func Written(dir string, since time.Time, paths []string) ([]string, error) {
+	recorded := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrReadingWorkspace, err)
+		}
+		recorded[absPath] = true
+	}
+
+	var names []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != dir && SkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if !recorded[absPath] && info.ModTime().Before(since) {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReadingWorkspace, err)
+	}
+	return names, nil
+}
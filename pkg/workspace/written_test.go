@@ -0,0 +1,41 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritten(t *testing.T) {
+	dir := t.TempDir()
+	since := time.Now().Add(-time.Hour)
+	old := since.Add(-time.Hour)
+	files := map[string]time.Time{
+		".edgerc":                   old,
+		".git/config":               old,
+		"previous/policy.tf":        old,
+		"recorded.tf":               old,
+		"policy.tf":                 since,
+		"modules/match_rules/a.tf":  since.Add(time.Minute),
+		".cli-terraform/state.json": since,
+		".terraform/plugin.json":    since,
+	}
+	for file, modified := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("content"), 0644))
+		require.NoError(t, os.Chtimes(path, modified, modified))
+	}
+
+	names, err := Written(dir, since, []string{filepath.Join(dir, "recorded.tf")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("modules", "match_rules", "a.tf"), "policy.tf", "recorded.tf"}, names)
+
+	_, err = Written(filepath.Join(dir, "missing"), since, nil)
+	assert.True(t, errors.Is(err, ErrReadingWorkspace), "expected: %s; got: %s", ErrReadingWorkspace, err)
+}